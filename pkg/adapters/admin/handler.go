@@ -0,0 +1,426 @@
+// Package admin 提供可选的 HTTP 管理接口: 清洗规则 CRUD、隔离区分诊 (查询 / 统计 / 修正 / 忽略) 与设备元数据维护
+// 只依赖标准库 net/http，基于已有端口实现；Handler 可直接挂到任意路由 (chi 等) 的子路径下:
+//
+//	mux.Handle("/admin/", http.StripPrefix("/admin", admin.NewHandler(admin.WithRules(ruleRepo, nil))))
+//
+// 本包不做认证，部署时须置于内网或由外层中间件完成认证。授权可交给 ports.Authorizer:
+// 规则仓储用 services.NewAuthorizedRuleRepository 包装，隔离区处理由 services.WithAuthorizer 检查，
+// 被拒绝的请求响应 403。规则写入与隔离区处理的操作人都取自 ctx 中的 IngestContext.Operator (由认证中间件设置)，
+// 请求体中的 operator 字段只能与之相同，否则响应 403。
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+// QuarantineReviewer 隔离记录处理 (*services.CoreStandardizer 实现了该接口)
+type QuarantineReviewer interface {
+	Resolve(ctx context.Context, id string, correctedValue float64, operator string) (*domain.StandardizationResult, error)
+	Ignore(ctx context.Context, id string, reason string) error
+}
+
+// 编译期检查接口实现
+var _ QuarantineReviewer = (*services.CoreStandardizer)(nil)
+
+// Option 配置 Handler
+type Option func(*Handler)
+
+// WithRules 开放清洗规则接口 (/rules)
+// validate 可选: 保存前校验规则 (如用 RuleFactory.CreateRule 检查规则类型与参数)，返回错误时响应 400
+func WithRules(repo ports.CleaningRuleRepository, validate func(domain.CleaningRule) error) Option {
+	return func(h *Handler) {
+		h.rules, h.validateRule = repo, validate
+	}
+}
+
+// WithQuarantine 开放隔离区接口 (/quarantine)；reviewer 为 nil 时只开放查询与统计
+func WithQuarantine(repo ports.QuarantineRepository, reviewer QuarantineReviewer) Option {
+	return func(h *Handler) {
+		h.quarantine, h.reviewer = repo, reviewer
+	}
+}
+
+// WithDevices 开放设备元数据接口 (/devices)
+func WithDevices(catalog ports.DeviceCatalog) Option {
+	return func(h *Handler) {
+		h.devices = catalog
+	}
+}
+
+// Handler 管理接口的 http.Handler，只注册已配置部分的路由 (未配置的路径返回 404)
+// 请求与响应均为 JSON；错误响应为 {"error": "..."}，ports.ErrNotFound 映射为 404，已处理的隔离记录映射为 409。
+type Handler struct {
+	rules        ports.CleaningRuleRepository
+	validateRule func(domain.CleaningRule) error
+	quarantine   ports.QuarantineRepository
+	reviewer     QuarantineReviewer
+	devices      ports.DeviceCatalog
+	mux          *http.ServeMux
+}
+
+// NewHandler 创建管理接口
+func NewHandler(opts ...Option) *Handler {
+	h := &Handler{mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.rules != nil {
+		h.mux.HandleFunc("GET /rules", h.listRules)
+		h.mux.HandleFunc("POST /rules", h.createRule)
+		h.mux.HandleFunc("GET /rules/{id}", h.getRule)
+		h.mux.HandleFunc("PUT /rules/{id}", h.putRule)
+		h.mux.HandleFunc("DELETE /rules/{id}", h.deleteRule)
+	}
+	if h.quarantine != nil {
+		h.mux.HandleFunc("GET /quarantine", h.listQuarantine)
+		h.mux.HandleFunc("GET /quarantine/counts", h.countQuarantine)
+		if h.reviewer != nil {
+			h.mux.HandleFunc("POST /quarantine/{id}/resolve", h.resolveQuarantine)
+			h.mux.HandleFunc("POST /quarantine/{id}/ignore", h.ignoreQuarantine)
+		}
+	}
+	if h.devices != nil {
+		h.mux.HandleFunc("GET /devices", h.listDevices)
+		h.mux.HandleFunc("GET /devices/{id}", h.getDevice)
+		h.mux.HandleFunc("PUT /devices/{id}", h.putDevice)
+		h.mux.HandleFunc("DELETE /devices/{id}", h.deleteDevice)
+	}
+	return h
+}
+
+// ServeHTTP 实现 http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// --- 清洗规则 ---
+
+// listRules GET /rules?device_type=ELEC[&enabled=true]
+// CleaningRuleRepository 按设备类型查询，device_type 必填
+func (h *Handler) listRules(w http.ResponseWriter, r *http.Request) {
+	deviceType := domain.DeviceType(r.URL.Query().Get("device_type"))
+	if deviceType == "" {
+		writeError(w, badRequest("device_type is required"))
+		return
+	}
+	list := h.rules.ListByDeviceType
+	if enabled, _ := strconv.ParseBool(r.URL.Query().Get("enabled")); enabled {
+		list = h.rules.ListEnabledByDeviceType
+	}
+	rules, err := list(r.Context(), deviceType)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(rules))
+}
+
+// createRule POST /rules (ID 已存在时返回 409)
+func (h *Handler) createRule(w http.ResponseWriter, r *http.Request) {
+	var rule domain.CleaningRule
+	if err := decodeJSON(r, &rule); err != nil {
+		writeError(w, err)
+		return
+	}
+	if rule.ID == "" {
+		writeError(w, badRequest("rule id is required"))
+		return
+	}
+	if _, err := h.rules.GetByID(r.Context(), rule.ID); err == nil {
+		writeError(w, &httpError{status: http.StatusConflict, msg: fmt.Sprintf("rule %s already exists", rule.ID)})
+		return
+	} else if !errors.Is(err, ports.ErrNotFound) {
+		writeError(w, err)
+		return
+	}
+	h.saveRule(w, r, rule, http.StatusCreated)
+}
+
+// getRule GET /rules/{id}
+func (h *Handler) getRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := h.rules.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// putRule PUT /rules/{id} 新增或整体替换规则 (请求体中的 ID 可省略，不得与路径不同)
+func (h *Handler) putRule(w http.ResponseWriter, r *http.Request) {
+	var rule domain.CleaningRule
+	if err := decodeJSON(r, &rule); err != nil {
+		writeError(w, err)
+		return
+	}
+	id := r.PathValue("id")
+	if rule.ID != "" && rule.ID != id {
+		writeError(w, badRequest(fmt.Sprintf("rule id %q does not match path %q", rule.ID, id)))
+		return
+	}
+	rule.ID = id
+	h.saveRule(w, r, rule, http.StatusOK)
+}
+
+func (h *Handler) saveRule(w http.ResponseWriter, r *http.Request, rule domain.CleaningRule, status int) {
+	if rule.DeviceType == "" {
+		writeError(w, badRequest("rule device_type is required"))
+		return
+	}
+	if h.validateRule != nil {
+		if err := h.validateRule(rule); err != nil {
+			writeError(w, badRequest(fmt.Sprintf("invalid rule %s: %v", rule.ID, err)))
+			return
+		}
+	}
+	if err := h.rules.Save(r.Context(), rule); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, status, rule)
+}
+
+// deleteRule DELETE /rules/{id}
+func (h *Handler) deleteRule(w http.ResponseWriter, r *http.Request) {
+	if err := h.rules.Delete(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// --- 隔离区 ---
+
+// listQuarantine GET /quarantine?status=PENDING&device_id=&device_type=&rule_id=&code=&reason=&start=&end=&limit=&offset=
+// start / end 为 RFC3339 时间 (原始读数时间)，limit 默认 100
+func (h *Handler) listQuarantine(w http.ResponseWriter, r *http.Request) {
+	filter, err := quarantineFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	records, err := h.quarantine.Find(r.Context(), filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(records))
+}
+
+// countQuarantine GET /quarantine/counts?group_by=rule_id&<与列表相同的过滤参数>
+func (h *Handler) countQuarantine(w http.ResponseWriter, r *http.Request) {
+	filter, err := quarantineFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	groupBy := ports.QuarantineGroupBy(r.URL.Query().Get("group_by"))
+	if err := groupBy.Validate(); err != nil {
+		writeError(w, badRequest(err.Error()))
+		return
+	}
+	counts, err := h.quarantine.Count(r.Context(), filter, groupBy)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	out := make([]quarantineCount, len(counts))
+	for i, c := range counts {
+		out[i] = quarantineCount{Key: c.Key, Count: c.Count}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+type quarantineCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// resolveRequest 修正请求体
+// Operator 仅用于兼容旧客户端: 操作人取自 ctx (认证中间件设置)，请求体中的值必须与之一致
+type resolveRequest struct {
+	Value    *float64 `json:"value"`
+	Operator string   `json:"operator"`
+}
+
+// resolveQuarantine POST /quarantine/{id}/resolve {"value": 12.5}
+func (h *Handler) resolveQuarantine(w http.ResponseWriter, r *http.Request) {
+	var req resolveRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if req.Value == nil {
+		writeError(w, badRequest("value is required"))
+		return
+	}
+	operator, err := requiredOperator(r.Context(), req.Operator)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	result, err := h.reviewer.Resolve(r.Context(), r.PathValue("id"), *req.Value, operator)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"readings": nonNil(result.Readings)})
+}
+
+// ignoreRequest 忽略请求体 (Operator 同 resolveRequest)
+type ignoreRequest struct {
+	Reason   string `json:"reason"`
+	Operator string `json:"operator"`
+}
+
+// ignoreQuarantine POST /quarantine/{id}/ignore {"reason": "meter replaced"}
+func (h *Handler) ignoreQuarantine(w http.ResponseWriter, r *http.Request) {
+	var req ignoreRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if req.Reason == "" {
+		writeError(w, badRequest("reason is required"))
+		return
+	}
+	if _, err := requiredOperator(r.Context(), req.Operator); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := h.reviewer.Ignore(r.Context(), r.PathValue("id"), req.Reason); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// errUnauthenticated ctx 中没有认证中间件设置的操作人
+var errUnauthenticated = &httpError{status: http.StatusUnauthorized, msg: "operator is required: the request carries no authenticated operator"}
+
+// requiredOperator 同 requestOperator，但 ctx 中没有已认证的操作人时返回 errUnauthenticated (隔离记录的处理必须留下处理人)
+func requiredOperator(ctx context.Context, claimed string) (string, error) {
+	operator, err := requestOperator(ctx, claimed)
+	if err == nil && operator == "" {
+		err = errUnauthenticated
+	}
+	return operator, err
+}
+
+// requestOperator 返回 ctx 中已认证的操作人 (IngestContext.Operator)
+// 请求体声明的 claimed 与之不同时拒绝 (未认证 401，身份不符 403)，防止调用方冒用其他身份绕过授权
+func requestOperator(ctx context.Context, claimed string) (string, error) {
+	info, _ := domain.FromContext(ctx)
+	if claimed == "" {
+		return info.Operator, nil
+	}
+	if info.Operator == "" {
+		return "", errUnauthenticated
+	}
+	if claimed != info.Operator {
+		return "", fmt.Errorf("operator %q does not match the authenticated operator: %w", claimed, ports.ErrForbidden)
+	}
+	return info.Operator, nil
+}
+
+// DefaultPageSize 隔离记录列表未指定 limit 时的条数
+const DefaultPageSize = 100
+
+func quarantineFilter(r *http.Request) (ports.QuarantineFilter, error) {
+	q := r.URL.Query()
+	f := ports.QuarantineFilter{
+		DeviceID:       q.Get("device_id"),
+		DeviceType:     domain.DeviceType(q.Get("device_type")),
+		RuleID:         q.Get("rule_id"),
+		Code:           domain.QuarantineCode(q.Get("code")),
+		Status:         domain.QuarantineStatus(q.Get("status")),
+		ReasonContains: q.Get("reason"),
+	}
+	var err error
+	if f.Start, err = timeParam(q.Get("start")); err != nil {
+		return f, err
+	}
+	if f.End, err = timeParam(q.Get("end")); err != nil {
+		return f, err
+	}
+	if f.Limit, err = intParam("limit", q.Get("limit")); err != nil {
+		return f, err
+	}
+	if f.Offset, err = intParam("offset", q.Get("offset")); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// --- 设备 ---
+
+// listDevices GET /devices[?type=ELEC]
+func (h *Handler) listDevices(w http.ResponseWriter, r *http.Request) {
+	devices, err := h.devices.List(r.Context(), domain.DeviceType(r.URL.Query().Get("type")))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(devices))
+}
+
+// getDevice GET /devices/{id}
+func (h *Handler) getDevice(w http.ResponseWriter, r *http.Request) {
+	device, err := h.devices.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, device)
+}
+
+// putDevice PUT /devices/{id} 新增或整体替换设备元数据 (请求体中的 device_id 可省略，不得与路径不同)
+func (h *Handler) putDevice(w http.ResponseWriter, r *http.Request) {
+	var device domain.DeviceInfo
+	if err := decodeJSON(r, &device); err != nil {
+		writeError(w, err)
+		return
+	}
+	id := r.PathValue("id")
+	if device.ID != "" && device.ID != id {
+		writeError(w, badRequest(fmt.Sprintf("device id %q does not match path %q", device.ID, id)))
+		return
+	}
+	device.ID = id
+	if device.Timezone != "" {
+		if _, err := time.LoadLocation(device.Timezone); err != nil {
+			writeError(w, badRequest(fmt.Sprintf("invalid timezone %q", device.Timezone)))
+			return
+		}
+	}
+	if device.Unit != "" && !device.Unit.IsKnown() {
+		writeError(w, badRequest(fmt.Sprintf("unknown unit %q", device.Unit)))
+		return
+	}
+	if err := h.devices.Save(r.Context(), device); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, device)
+}
+
+// deleteDevice DELETE /devices/{id}
+func (h *Handler) deleteDevice(w http.ResponseWriter, r *http.Request) {
+	if err := h.devices.Delete(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
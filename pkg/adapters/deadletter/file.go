@@ -0,0 +1,65 @@
+// Package deadletter 提供 ports.DeadLetterSink 的实现: 追加写入的 JSON Lines 文件与消息主题 (经 ports.MessagePublisher)
+// 内存仓储实现见 memory.DeadLetterRepository。
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// FileSink 把死信逐行追加到 JSON Lines 文件 (每行一个 ports.DeadLetter)
+// 每次写入后 fsync，Write 返回 nil 时死信已落盘
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// 编译期检查接口实现
+var _ ports.DeadLetterSink = (*FileSink)(nil)
+
+// NewFileSink 打开 (不存在时创建) 死信文件，写入追加到文件末尾
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open dead letter file: %w", err)
+	}
+	return &FileSink{file: f}, nil
+}
+
+// Write 追加一条死信
+func (s *FileSink) Write(ctx context.Context, letter ports.DeadLetter) error {
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("encode dead letter: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return errors.New("dead letter file closed")
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write dead letter: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("sync dead letter file: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭文件，之后的 Write 返回错误
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
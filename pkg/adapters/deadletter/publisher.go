@@ -0,0 +1,40 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// PublisherSink 把死信作为消息发布到主题 (如 Kafka 的 DLQ topic)
+// 消息 Key 为来源 (摄入格式)，Payload 为 ports.DeadLetter 的 JSON
+type PublisherSink struct {
+	publisher ports.MessagePublisher
+	topic     string
+}
+
+// 编译期检查接口实现
+var _ ports.DeadLetterSink = (*PublisherSink)(nil)
+
+// NewPublisherSink 创建发布死信的输出 (topic 为空使用 ports.DefaultDeadLetterTopic)
+func NewPublisherSink(publisher ports.MessagePublisher, topic string) *PublisherSink {
+	if topic == "" {
+		topic = ports.DefaultDeadLetterTopic
+	}
+	return &PublisherSink{publisher: publisher, topic: topic}
+}
+
+// Write 发布一条死信，代理确认接收后返回 nil
+func (s *PublisherSink) Write(ctx context.Context, letter ports.DeadLetter) error {
+	payload, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("encode dead letter: %w", err)
+	}
+	msg := ports.OutboxMessage{Topic: s.topic, Key: letter.Source, Payload: payload, CreatedAt: letter.FailedAt}
+	if err := s.publisher.Publish(ctx, msg); err != nil {
+		return fmt.Errorf("publish dead letter to %s: %w", s.topic, err)
+	}
+	return nil
+}
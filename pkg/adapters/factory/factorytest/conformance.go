@@ -0,0 +1,151 @@
+// Package factorytest provides a conformance suite for custom rule builders.
+//
+// Rule pack authors run it from their own tests to verify that a builder
+// honours the contracts the Sanitizer relies on:
+//
+//	func TestHumidityDriftConformance(t *testing.T) {
+//		factorytest.Run(t, factorytest.Suite{
+//			Builder:       buildHumidityDrift,
+//			ValidParams:   map[string]any{"max_drift": 5.0},
+//			InvalidParams: []map[string]any{nil, {"max_drift": "x"}},
+//		})
+//	}
+package factorytest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/factory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// Suite describes the builder under test
+type Suite struct {
+	Builder       factory.RuleBuilder
+	ValidParams   map[string]any   // parameters the builder must accept
+	InvalidParams []map[string]any // parameters the builder must reject with an error
+	Actions       []domain.RuleAction
+	Samples       []domain.Reading // optional readings to check (defaults to a small synthetic series)
+}
+
+// Run executes the conformance suite as subtests of t
+func Run(t *testing.T, s Suite) {
+	t.Helper()
+
+	actions := s.Actions
+	if len(actions) == 0 {
+		actions = []domain.RuleAction{"", domain.ActionReject}
+	}
+	samples := s.Samples
+	if len(samples) == 0 {
+		samples = defaultSamples()
+	}
+
+	t.Run("RejectsInvalidParams", func(t *testing.T) {
+		for i, params := range s.InvalidParams {
+			rule, err := build(s.Builder, params, domain.ActionReject)
+			if err == nil {
+				t.Errorf("invalid params #%d %v: expected error, got rule %T", i, params, rule)
+			}
+		}
+	})
+
+	for _, action := range actions {
+		action := action
+		t.Run(fmt.Sprintf("Action=%q", action), func(t *testing.T) {
+			rule, err := build(s.Builder, s.ValidParams, action)
+			if err != nil {
+				t.Fatalf("valid params %v: unexpected error: %v", s.ValidParams, err)
+			}
+			if rule == nil {
+				t.Fatalf("valid params %v: builder returned nil rule", s.ValidParams)
+			}
+			checkContracts(t, rule, samples)
+		})
+	}
+}
+
+// build invokes the builder and converts panics into errors
+func build(b factory.RuleBuilder, params map[string]any, action domain.RuleAction) (rule ports.CleaningRule, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("builder panicked: %v", r)
+		}
+	}()
+	return b(params, action)
+}
+
+// checkContracts verifies the CheckResult contracts for every sample
+func checkContracts(t *testing.T, rule ports.CleaningRule, samples []domain.Reading) {
+	t.Helper()
+
+	var prev *domain.Reading
+	for i, curr := range samples {
+		var next *domain.Reading
+		if i+1 < len(samples) {
+			next = &samples[i+1]
+		}
+		ctx := ports.CleaningContext{Previous: prev, Next: next}
+
+		res, err := check(rule, ctx, curr)
+		if err != nil {
+			t.Fatalf("sample #%d: %v", i, err)
+		}
+
+		if res.Reading.DeviceInfo.ID != curr.DeviceInfo.ID || !res.Reading.Timestamp.Equal(curr.Timestamp) {
+			t.Errorf("sample #%d: result must keep device and timestamp of the input reading", i)
+		}
+		if !res.Passed && res.Corrected {
+			t.Errorf("sample #%d: a rejected result must not be marked corrected", i)
+		}
+		if (!res.Passed || res.Corrected) && res.Reason == "" {
+			t.Errorf("sample #%d: rejected or corrected results must carry a reason", i)
+		}
+
+		again, _ := check(rule, ctx, curr)
+		if again.Passed != res.Passed || again.Corrected != res.Corrected || again.Reading.Value != res.Reading.Value {
+			t.Errorf("sample #%d: Check must be deterministic for identical input", i)
+		}
+
+		if res.Passed {
+			kept := res.Reading
+			prev = &kept
+		}
+	}
+
+	// The first reading of a series has no context at all
+	if _, err := check(rule, ports.CleaningContext{}, samples[0]); err != nil {
+		t.Errorf("empty context: %v", err)
+	}
+}
+
+// check invokes the rule and converts panics into errors
+func check(rule ports.CleaningRule, ctx ports.CleaningContext, curr domain.Reading) (res ports.CheckResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Check panicked: %v", r)
+		}
+	}()
+	return rule.Check(ctx, curr), nil
+}
+
+// defaultSamples returns a small series covering normal growth, a regression, a spike and a missing value
+func defaultSamples() []domain.Reading {
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "CONFORMANCE", Type: domain.DeviceTypeElec}
+	values := []float64{100, 101, 102, 50, 5000, 104, 105}
+
+	samples := make([]domain.Reading, 0, len(values)+1)
+	for i, v := range values {
+		samples = append(samples, domain.Reading{DeviceInfo: dev, Timestamp: base.Add(time.Duration(i) * 15 * time.Minute), Value: v})
+	}
+	samples = append(samples, domain.Reading{
+		DeviceInfo: dev,
+		Timestamp:  base.Add(time.Duration(len(values)) * 15 * time.Minute),
+		Quality:    domain.QualityMissing,
+	})
+	return samples
+}
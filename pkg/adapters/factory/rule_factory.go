@@ -3,10 +3,12 @@ package factory
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/renjie/prism-core/pkg/core/domain"
 	"github.com/renjie/prism-core/pkg/core/ports"
 	"github.com/renjie/prism-core/pkg/core/services/rules"
+	"github.com/renjie/prism-core/pkg/core/services/rules/expr"
 )
 
 // RuleBuilder defines the contract for creating a specific rule logic
@@ -39,6 +41,13 @@ func NewRuleFactory() *RuleFactory {
 	}
 	// Register built-in rules
 	f.Register(domain.RuleTypeRange, buildRangeRule)
+	f.Register(domain.RuleTypeBidirectional, buildBidirectionalRule)
+	f.Register(domain.RuleTypeImpute, buildImputeRule)
+	f.Register(domain.RuleTypeSpike, buildSpikeRule)
+	f.Register(domain.RuleTypeTimeWindow, buildTimeWindowRule)
+	f.Register(domain.RuleTypeComposite, f.buildCompositeRule)
+	f.Register(domain.RuleTypeScript, buildScriptRule)
+	f.Register(domain.RuleTypeTimestamp, buildTimestampRule)
 	return f
 }
 
@@ -50,6 +59,10 @@ func (f *RuleFactory) Register(ruleType domain.RuleType, builder RuleBuilder) {
 }
 
 // CreateRule instantiates a rule strategy based on configuration
+// The returned rule carries rule.ID so that check results and quarantine records can be traced back to it,
+// and only applies to devices within rule.DeviceIDs (if set) that are not listed in rule.Exemptions.
+// If rule.Parameters carries a "unit" (e.g. {"max": 500, "unit": "kWh"}), thresholds are compared
+// in that unit regardless of the device's native unit.
 func (f *RuleFactory) CreateRule(rule domain.CleaningRule) (ports.CleaningRule, error) {
 	f.mu.RLock()
 	builder, ok := f.builders[rule.Type]
@@ -58,7 +71,24 @@ func (f *RuleFactory) CreateRule(rule domain.CleaningRule) (ports.CleaningRule,
 	if !ok {
 		return nil, fmt.Errorf("no builder registered for rule type: %s", rule.Type)
 	}
-	return builder(rule.Parameters, rule.Action)
+	built, err := builder(rule.Parameters, rule.Action)
+	if err != nil {
+		return nil, err
+	}
+	if v, exists := rule.Parameters["unit"]; exists {
+		unit, ok := v.(string)
+		if !ok || !domain.Unit(unit).IsKnown() {
+			return nil, fmt.Errorf("invalid unit %v for rule %s", v, rule.ID)
+		}
+		built = rules.WithUnit(domain.Unit(unit), built)
+	}
+	if len(rule.DeviceIDs) > 0 || !rule.Exemptions.IsEmpty() {
+		built = rules.WithScope(rule, built)
+	}
+	if rule.ID == "" {
+		return built, nil
+	}
+	return rules.WithID(rule.ID, built), nil
 }
 
 // buildRangeRule (Built-in implementation)
@@ -74,3 +104,234 @@ func buildRangeRule(params map[string]interface{}, action domain.RuleAction) (po
 	}
 	return &rules.RangeRule{Min: min, Max: max, Action: action}, nil
 }
+
+// buildBidirectionalRule (Built-in implementation)
+// Parameters: max_rate(float, units per hour, required), rollover(float, optional)
+func buildBidirectionalRule(params map[string]interface{}, action domain.RuleAction) (ports.CleaningRule, error) {
+	maxRate, ok := params["max_rate"].(float64)
+	if !ok || maxRate <= 0 {
+		return nil, fmt.Errorf("invalid parameters for BIDIRECTIONAL_MONOTONIC rule: need positive max_rate(float)")
+	}
+
+	var rollover float64
+	if v, exists := params["rollover"]; exists {
+		if rollover, ok = v.(float64); !ok || rollover < 0 {
+			return nil, fmt.Errorf("invalid parameters for BIDIRECTIONAL_MONOTONIC rule: rollover must be a non-negative float")
+		}
+	}
+
+	if action == "" {
+		action = domain.ActionReject
+	}
+	return &rules.BidirectionalMonotonicRule{MaxRate: maxRate, Rollover: rollover, Action: action}, nil
+}
+
+// buildImputeRule (Built-in implementation)
+// Parameters: strategy(string: LOCF / LINEAR / REJECT, default REJECT)
+func buildImputeRule(params map[string]interface{}, action domain.RuleAction) (ports.CleaningRule, error) {
+	strategy := domain.ImputeReject
+	if v, exists := params["strategy"]; exists {
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid parameters for IMPUTE rule: strategy must be a string")
+		}
+		strategy = domain.ImputeStrategy(str)
+	}
+
+	switch strategy {
+	case domain.ImputeLOCF, domain.ImputeLinear, domain.ImputeReject:
+	default:
+		return nil, fmt.Errorf("invalid parameters for IMPUTE rule: unknown strategy %q", strategy)
+	}
+	return &rules.ImputeRule{Strategy: strategy}, nil
+}
+
+// buildSpikeRule (Built-in implementation)
+// Parameters: threshold(float, required)
+func buildSpikeRule(params map[string]interface{}, action domain.RuleAction) (ports.CleaningRule, error) {
+	threshold, ok := params["threshold"].(float64)
+	if !ok || threshold <= 0 {
+		return nil, fmt.Errorf("invalid parameters for SPIKE rule: need positive threshold(float)")
+	}
+
+	if action == "" {
+		action = domain.ActionReject
+	}
+	return &rules.SpikeRule{Threshold: threshold, Action: action}, nil
+}
+
+// buildTimeWindowRule (Built-in implementation)
+// Parameters: start(string "HH:MM"), end(string "HH:MM"), weekdays([]int, 0=Sunday, optional), timezone(string, optional)
+func buildTimeWindowRule(params map[string]interface{}, action domain.RuleAction) (ports.CleaningRule, error) {
+	start, err := parseClock(params["start"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for TIME_WINDOW rule: start: %w", err)
+	}
+	end, err := parseClock(params["end"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for TIME_WINDOW rule: end: %w", err)
+	}
+
+	rule := &rules.TimeWindowRule{Start: start, End: end}
+
+	if v, exists := params["weekdays"]; exists {
+		days, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid parameters for TIME_WINDOW rule: weekdays must be a list of numbers")
+		}
+		rule.Weekdays = make(map[time.Weekday]bool, len(days))
+		for _, d := range days {
+			n, ok := d.(float64)
+			if !ok || n < 0 || n > 6 {
+				return nil, fmt.Errorf("invalid parameters for TIME_WINDOW rule: weekday %v out of range [0, 6]", d)
+			}
+			rule.Weekdays[time.Weekday(n)] = true
+		}
+	}
+
+	if v, exists := params["timezone"]; exists {
+		name, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid parameters for TIME_WINDOW rule: timezone must be a string")
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameters for TIME_WINDOW rule: %w", err)
+		}
+		rule.Location = loc
+	}
+	return rule, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight
+func parseClock(v interface{}) (time.Duration, error) {
+	str, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("need time of day as string \"HH:MM\"")
+	}
+	t, err := time.Parse("15:04", str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", str)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// buildCompositeRule (Built-in implementation)
+// Parameters: operator(string: AND / OR / NOT), rules([]{type, parameters})
+// Child rules are built through the same factory, so any registered type (including COMPOSITE) can be nested
+func (f *RuleFactory) buildCompositeRule(params map[string]interface{}, action domain.RuleAction) (ports.CleaningRule, error) {
+	if action != "" && action != domain.ActionReject {
+		return nil, fmt.Errorf("invalid action for COMPOSITE rule: only %s is supported", domain.ActionReject)
+	}
+
+	opStr, _ := params["operator"].(string)
+	op := domain.CompositeOperator(opStr)
+
+	rawChildren, ok := params["rules"].([]interface{})
+	if !ok || len(rawChildren) == 0 {
+		return nil, fmt.Errorf("invalid parameters for COMPOSITE rule: need non-empty rules(list)")
+	}
+
+	switch op {
+	case domain.CompositeAnd, domain.CompositeOr:
+	case domain.CompositeNot:
+		if len(rawChildren) != 1 {
+			return nil, fmt.Errorf("invalid parameters for COMPOSITE rule: NOT requires exactly one child rule")
+		}
+	default:
+		return nil, fmt.Errorf("invalid parameters for COMPOSITE rule: unknown operator %q", opStr)
+	}
+
+	children := make([]ports.CleaningRule, 0, len(rawChildren))
+	for i, raw := range rawChildren {
+		spec, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid parameters for COMPOSITE rule: child %d must be an object", i)
+		}
+		childType, _ := spec["type"].(string)
+		childParams, _ := spec["parameters"].(map[string]interface{})
+		child, err := f.CreateRule(domain.CleaningRule{
+			Type:       domain.RuleType(childType),
+			Action:     domain.ActionReject,
+			Parameters: childParams,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid child %d for COMPOSITE rule: %w", i, err)
+		}
+		children = append(children, child)
+	}
+
+	return &rules.CompositeRule{Operator: op, Children: children}, nil
+}
+
+// buildScriptRule (Built-in implementation)
+// Parameters: condition(string expression, required), correction(string expression, required for CORRECT)
+func buildScriptRule(params map[string]interface{}, action domain.RuleAction) (ports.CleaningRule, error) {
+	src, ok := params["condition"].(string)
+	if !ok || src == "" {
+		return nil, fmt.Errorf("invalid parameters for SCRIPT rule: need condition(string)")
+	}
+	condition, err := expr.Compile(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for SCRIPT rule: condition: %w", err)
+	}
+
+	rule := &rules.ScriptRule{Condition: condition, Action: action}
+	if rule.Action == "" {
+		rule.Action = domain.ActionReject
+	}
+
+	if v, exists := params["correction"]; exists {
+		src, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid parameters for SCRIPT rule: correction must be a string")
+		}
+		if rule.Correction, err = expr.Compile(src); err != nil {
+			return nil, fmt.Errorf("invalid parameters for SCRIPT rule: correction: %w", err)
+		}
+	}
+	if rule.Action == domain.ActionCorrect && rule.Correction == nil {
+		return nil, fmt.Errorf("invalid parameters for SCRIPT rule: CORRECT action needs correction(string)")
+	}
+	return rule, nil
+}
+
+// buildTimestampRule (Built-in implementation)
+// Parameters: max_future_skew(duration, default "5m"), max_age(duration, optional, e.g. "8760h")
+func buildTimestampRule(params map[string]interface{}, action domain.RuleAction) (ports.CleaningRule, error) {
+	skew, err := durationParam(params, "max_future_skew", 5*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for TIMESTAMP_SANITY rule: %w", err)
+	}
+	maxAge, err := durationParam(params, "max_age", 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for TIMESTAMP_SANITY rule: %w", err)
+	}
+	return &rules.TimestampSanityRule{MaxFutureSkew: skew, MaxAge: maxAge}, nil
+}
+
+// durationParam reads a duration parameter given either as a Go duration string ("90m") or as seconds (float)
+func durationParam(params map[string]interface{}, key string, def time.Duration) (time.Duration, error) {
+	v, exists := params[key]
+	if !exists {
+		return def, nil
+	}
+
+	var d time.Duration
+	switch val := v.(type) {
+	case string:
+		parsed, err := time.ParseDuration(val)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", key, err)
+		}
+		d = parsed
+	case float64:
+		d = time.Duration(val * float64(time.Second))
+	default:
+		return 0, fmt.Errorf("%s must be a duration string or seconds", key)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s must not be negative", key)
+	}
+	return d, nil
+}
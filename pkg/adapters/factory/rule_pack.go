@@ -0,0 +1,93 @@
+package factory
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// NamespaceSeparator separates the namespace from the rule name in external rule types
+// e.g. "acme/HUMIDITY_DRIFT"
+const NamespaceSeparator = "/"
+
+// namespacePattern restricts namespaces to lower-case identifiers (e.g. module or vendor names)
+var namespacePattern = regexp.MustCompile(`^[a-z][a-z0-9_.-]*$`)
+
+// RulePack is a set of custom rule builders shipped by a downstream module
+// All rule types in a pack are registered under the pack's namespace, so that
+// third-party rules can never shadow built-in types or each other.
+type RulePack struct {
+	Namespace string                 // e.g. "acme"
+	Rules     map[string]RuleBuilder // rule name -> builder, e.g. "HUMIDITY_DRIFT"
+}
+
+// NamespacedType builds the fully qualified rule type for a rule in a namespace
+func NamespacedType(namespace, name string) domain.RuleType {
+	return domain.RuleType(namespace + NamespaceSeparator + name)
+}
+
+// RegisterPack registers all rules of a pack atomically
+// It returns an error (and registers nothing) if the namespace is invalid or
+// any of the resulting rule types is already registered.
+func (f *RuleFactory) RegisterPack(pack RulePack) error {
+	if !namespacePattern.MatchString(pack.Namespace) {
+		return fmt.Errorf("invalid rule pack namespace %q: must match %s", pack.Namespace, namespacePattern)
+	}
+	if len(pack.Rules) == 0 {
+		return fmt.Errorf("rule pack %q has no rules", pack.Namespace)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	types := make(map[domain.RuleType]RuleBuilder, len(pack.Rules))
+	for name, builder := range pack.Rules {
+		if name == "" || strings.Contains(name, NamespaceSeparator) {
+			return fmt.Errorf("invalid rule name %q in pack %q", name, pack.Namespace)
+		}
+		if builder == nil {
+			return fmt.Errorf("nil builder for rule %q in pack %q", name, pack.Namespace)
+		}
+		ruleType := NamespacedType(pack.Namespace, name)
+		if _, exists := f.builders[ruleType]; exists {
+			return fmt.Errorf("rule type %s already registered", ruleType)
+		}
+		types[ruleType] = builder
+	}
+
+	for ruleType, builder := range types {
+		f.builders[ruleType] = builder
+	}
+	return nil
+}
+
+// MustRegisterPack registers a pack into the singleton factory and panics on conflict
+// Intended to be called from a rule pack's init() function:
+//
+//	func init() {
+//		factory.MustRegisterPack(factory.RulePack{
+//			Namespace: "acme",
+//			Rules:     map[string]factory.RuleBuilder{"HUMIDITY_DRIFT": buildHumidityDrift},
+//		})
+//	}
+func MustRegisterPack(pack RulePack) {
+	if err := GetRuleFactory().RegisterPack(pack); err != nil {
+		panic(fmt.Sprintf("factory: %v", err))
+	}
+}
+
+// RegisteredTypes returns all registered rule types in sorted order
+func (f *RuleFactory) RegisteredTypes() []domain.RuleType {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	types := make([]domain.RuleType, 0, len(f.builders))
+	for t := range f.builders {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -16,18 +17,30 @@ import (
 // 专门处理 CSV 格式的数据流
 type CsvUniversalIngestor struct {
 	downstream func(context.Context, []domain.Reading) error
+	config     ingestorConfig
 }
 
 // NewCsvUniversalIngestor 创建 CSV 摄入器实例
-func NewCsvUniversalIngestor(downstream func(context.Context, []domain.Reading) error) *CsvUniversalIngestor {
+func NewCsvUniversalIngestor(downstream func(context.Context, []domain.Reading) error, opts ...IngestorOption) *CsvUniversalIngestor {
 	return &CsvUniversalIngestor{
 		downstream: downstream,
+		config:     newIngestorConfig(opts),
 	}
 }
 
 // IngestStream 实现 UniversalIngestor.IngestStream
 // 逐行读取 CSV 流
 func (c *CsvUniversalIngestor) IngestStream(ctx context.Context, stream io.Reader) (*domain.IngestionResult, error) {
+	result, err := measure(stream, func(r io.Reader) (*domain.IngestionResult, error) {
+		return c.config.once(ctx, c.downstream, func(downstream downstreamFunc) (*domain.IngestionResult, error) {
+			return c.ingestStream(ctx, r, downstream)
+		})
+	})
+	c.config.ingested(ctx, "csv", result)
+	return result, err
+}
+
+func (c *CsvUniversalIngestor) ingestStream(ctx context.Context, stream io.Reader, downstream downstreamFunc) (*domain.IngestionResult, error) {
 	reader := csv.NewReader(stream)
 	// 允许变长字段，避免因某些行缺少非必填字段报错
 	reader.FieldsPerRecord = -1
@@ -44,9 +57,23 @@ func (c *CsvUniversalIngestor) IngestStream(ctx context.Context, stream io.Reade
 		return nil, fmt.Errorf("failed to read csv header: %w", err)
 	}
 
+	contract, err := c.config.contract(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if contract != nil {
+		if err := contract.Check(headers); err != nil {
+			return nil, err
+		}
+	}
+
 	headerMap := make(map[string]int)
 	for i, h := range headers {
-		headerMap[strings.ToLower(strings.TrimSpace(h))] = i
+		name := strings.ToLower(strings.TrimSpace(h))
+		if contract != nil {
+			name = contract.Canonical(h)
+		}
+		headerMap[name] = i
 	}
 
 	// Validate required columns
@@ -82,7 +109,7 @@ func (c *CsvUniversalIngestor) IngestStream(ctx context.Context, stream io.Reade
 		result.Success++
 
 		if len(buffer) >= batchSize {
-			if err := c.downstream(ctx, buffer); err != nil {
+			if err := c.config.deliver(ctx, "csv", downstream, buffer, result); err != nil {
 				return result, err
 			}
 			buffer = buffer[:0]
@@ -90,7 +117,7 @@ func (c *CsvUniversalIngestor) IngestStream(ctx context.Context, stream io.Reade
 	}
 
 	if len(buffer) > 0 {
-		if err := c.downstream(ctx, buffer); err != nil {
+		if err := c.config.deliver(ctx, "csv", downstream, buffer, result); err != nil {
 			return result, err
 		}
 	}
@@ -144,20 +171,46 @@ func (c *CsvUniversalIngestor) parseRecord(record []string, headerMap map[string
 		}
 	}
 
+	reading := domain.Reading{
+		DeviceInfo: domain.DeviceInfo{
+			TenantID: get("tenant_id"),
+			ID:       deviceID,
+			Model:    get("model"),
+			Type:     domain.DeviceType(get("type")),
+			Unit:     domain.Unit(get("unit")),
+			Timezone: get("timezone"),
+		},
+		Metric:     domain.Metric(get("metric")),
+		Timestamp:  ts,
+		ReceivedAt: time.Now(),
+	}
+
 	// 3. Value
-	valStr := get("value")
+	// 空值/NaN 作为缺失读数透传，由清洗阶段负责插补
+	valStr := strings.TrimSpace(get("value"))
+	if isMissingValue(valStr) {
+		reading.Quality = domain.QualityMissing
+		return reading, nil
+	}
 	val, err := strconv.ParseFloat(valStr, 64)
 	if err != nil {
 		return domain.Reading{}, fmt.Errorf("invalid value format: %s", valStr)
 	}
+	if math.IsNaN(val) {
+		reading.Quality = domain.QualityMissing
+		return reading, nil
+	}
+	reading.Value = val
 
-	return domain.Reading{
-		DeviceInfo: domain.DeviceInfo{
-			ID:    deviceID,
-			Model: get("model"),
-			Type:  domain.DeviceType(get("type")),
-		},
-		Timestamp: ts,
-		Value:     val,
-	}, nil
+	return reading, nil
+}
+
+// isMissingValue 判断原始值文本是否表示缺失值
+func isMissingValue(s string) bool {
+	switch strings.ToLower(s) {
+	case "", "null", "nan", "n/a":
+		return true
+	default:
+		return false
+	}
 }
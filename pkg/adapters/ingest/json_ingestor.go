@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/renjie/prism-core/pkg/core/domain"
@@ -18,17 +20,29 @@ type JsonUniversalIngestor struct {
 	// 在实际系统中，这里可能是调用 Standardizer.ProcessAndStandardize
 	// 或者推送到消息队列
 	downstream func(context.Context, []domain.Reading) error
+	config     ingestorConfig
 }
 
-func NewJsonUniversalIngestor(downstream func(context.Context, []domain.Reading) error) *JsonUniversalIngestor {
+func NewJsonUniversalIngestor(downstream func(context.Context, []domain.Reading) error, opts ...IngestorOption) *JsonUniversalIngestor {
 	return &JsonUniversalIngestor{
 		downstream: downstream,
+		config:     newIngestorConfig(opts),
 	}
 }
 
 // IngestStream 实现 UniversalIngestor.IngestStream
 // 简化版：我们假设输入总是 JSON 数组 [...]，以规避 decoder.Token 的复杂性
 func (j *JsonUniversalIngestor) IngestStream(ctx context.Context, stream io.Reader) (*domain.IngestionResult, error) {
+	result, err := measure(stream, func(r io.Reader) (*domain.IngestionResult, error) {
+		return j.config.once(ctx, j.downstream, func(downstream downstreamFunc) (*domain.IngestionResult, error) {
+			return j.ingestStream(ctx, r, downstream)
+		})
+	})
+	j.config.ingested(ctx, "json", result)
+	return result, err
+}
+
+func (j *JsonUniversalIngestor) ingestStream(ctx context.Context, stream io.Reader, downstream downstreamFunc) (*domain.IngestionResult, error) {
 	// 使用 bufio.Reader 预读首字节，避免消耗 Token
 	bufStream := bufio.NewReader(stream)
 	head, err := bufStream.Peek(1)
@@ -39,6 +53,10 @@ func (j *JsonUniversalIngestor) IngestStream(ctx context.Context, stream io.Read
 		return nil, fmt.Errorf("failed to peek start token: %w", err)
 	}
 
+	contract, err := j.config.contract(ctx)
+	if err != nil {
+		return nil, err
+	}
 	decoder := json.NewDecoder(bufStream)
 	result := &domain.IngestionResult{}
 
@@ -48,13 +66,13 @@ func (j *JsonUniversalIngestor) IngestStream(ctx context.Context, stream io.Read
 		if _, err := decoder.Token(); err != nil {
 			return nil, err
 		}
-		return j.decodeArray(ctx, decoder, result)
+		return j.decodeArray(ctx, decoder, contract, result, downstream)
 	}
 
 	// Case 2: Single JSON Object {...}
 	if head[0] == '{' {
-		var p rawPayload
-		if err := decoder.Decode(&p); err != nil {
+		p, err := decodePayload(decoder, contract)
+		if err != nil {
 			return nil, fmt.Errorf("failed to decode single object: %w", err)
 		}
 
@@ -66,10 +84,10 @@ func (j *JsonUniversalIngestor) IngestStream(ctx context.Context, stream io.Read
 			return result, nil // Partial success? Or we consider single object fail as total fail.
 		}
 
-		if err := j.downstream(ctx, []domain.Reading{reading}); err != nil {
+		result.Success++
+		if err := j.config.deliver(ctx, "json", downstream, []domain.Reading{reading}, result); err != nil {
 			return nil, err
 		}
-		result.Success++
 		return result, nil
 	}
 
@@ -89,21 +107,53 @@ func (j *JsonUniversalIngestor) IngestBatch(ctx context.Context, file io.Reader,
 // rawPayload 定义接收的扁平化 JSON 结构
 // 适配多种字段命名风格 (Snake Case / Camel Case)
 type rawPayload struct {
+	TenantID  string      `json:"tenant_id"` // 可选: 所属租户
 	DeviceID  string      `json:"device_id"`
 	Model     string      `json:"model"`
 	Type      string      `json:"type"`
+	Unit      string      `json:"unit"`      // 可选: 原生计量单位
+	Metric    string      `json:"metric"`    // 可选: 计量通道 (如 ENERGY / POWER)
+	Timezone  string      `json:"timezone"`  // 可选: 设备所在时区
 	Timestamp string      `json:"timestamp"` // 支持 RFC3339 或 简单时间格式
-	Value     json.Number `json:"value"`     // 使用 json.Number 避免精度丢失
+	Value     json.Number `json:"value"`     // 使用 json.Number 避免精度丢失 (null 解码为空串)
+
+	Tags map[string]string `json:"tags"` // 可选: 设备标签
+}
+
+// decodePayload 解码下一个对象；配置了数据契约时先校验字段并按 Mapping 改名
+func decodePayload(decoder *json.Decoder, contract *domain.DataContract) (rawPayload, error) {
+	var p rawPayload
+	if contract == nil {
+		err := decoder.Decode(&p)
+		return p, err
+	}
+	var fields map[string]json.RawMessage
+	if err := decoder.Decode(&fields); err != nil {
+		return p, err
+	}
+	if err := contract.Check(slices.Collect(maps.Keys(fields))); err != nil {
+		return p, err
+	}
+	canonical := make(map[string]json.RawMessage, len(fields))
+	for name, value := range fields {
+		canonical[contract.Canonical(name)] = value
+	}
+	body, err := json.Marshal(canonical)
+	if err != nil {
+		return p, err
+	}
+	err = json.Unmarshal(body, &p)
+	return p, err
 }
 
-func (j *JsonUniversalIngestor) decodeArray(ctx context.Context, decoder *json.Decoder, result *domain.IngestionResult) (*domain.IngestionResult, error) {
+func (j *JsonUniversalIngestor) decodeArray(ctx context.Context, decoder *json.Decoder, contract *domain.DataContract, result *domain.IngestionResult, downstream downstreamFunc) (*domain.IngestionResult, error) {
 	var buffer []domain.Reading
 	const batchSize = 100 // 简单的批处理缓冲
 
 	// while decoder.More()
 	for decoder.More() {
-		var p rawPayload
-		if err := decoder.Decode(&p); err != nil {
+		p, err := decodePayload(decoder, contract)
+		if err != nil {
 			return nil, fmt.Errorf("decode error inside array: %w", err)
 		}
 
@@ -121,7 +171,7 @@ func (j *JsonUniversalIngestor) decodeArray(ctx context.Context, decoder *json.D
 
 		// Flush buffer if full
 		if len(buffer) >= batchSize {
-			if err := j.downstream(ctx, buffer); err != nil {
+			if err := j.config.deliver(ctx, "json", downstream, buffer, result); err != nil {
 				return result, err
 			}
 			buffer = buffer[:0] // clear
@@ -130,7 +180,7 @@ func (j *JsonUniversalIngestor) decodeArray(ctx context.Context, decoder *json.D
 
 	// Flush remaining
 	if len(buffer) > 0 {
-		if err := j.downstream(ctx, buffer); err != nil {
+		if err := j.config.deliver(ctx, "json", downstream, buffer, result); err != nil {
 			return result, err
 		}
 	}
@@ -156,19 +206,32 @@ func (j *JsonUniversalIngestor) mapToDomain(p rawPayload) (domain.Reading, error
 		}
 	}
 
+	reading := domain.Reading{
+		DeviceInfo: domain.DeviceInfo{
+			TenantID: p.TenantID,
+			ID:       p.DeviceID,
+			Model:    p.Model,
+			Type:     domain.DeviceType(p.Type),
+			Unit:     domain.Unit(p.Unit),
+			Timezone: p.Timezone,
+			Tags:     p.Tags,
+		},
+		Metric:     domain.Metric(p.Metric),
+		Timestamp:  ts,
+		ReceivedAt: time.Now(),
+	}
+
 	// 2. Value Parsing
+	// null/缺省值作为缺失读数透传，由清洗阶段负责插补
+	if p.Value == "" {
+		reading.Quality = domain.QualityMissing
+		return reading, nil
+	}
 	val, err := p.Value.Float64()
 	if err != nil {
 		return domain.Reading{}, fmt.Errorf("invalid value format: %v", p.Value)
 	}
+	reading.Value = val
 
-	return domain.Reading{
-		DeviceInfo: domain.DeviceInfo{
-			ID:    p.DeviceID,
-			Model: p.Model,
-			Type:  domain.DeviceType(p.Type),
-		},
-		Timestamp: ts,
-		Value:     val,
-	}, nil
+	return reading, nil
 }
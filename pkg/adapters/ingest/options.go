@@ -0,0 +1,218 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// downstreamFunc 接收解析后读数的下游 (通常是标准化服务)
+type downstreamFunc func(context.Context, []domain.Reading) error
+
+// IngestorOption 摄入器配置选项 (Functional Option Pattern)
+type IngestorOption func(*ingestorConfig)
+
+// ingestorConfig CSV 与 JSON 摄入器共用的可选配置
+type ingestorConfig struct {
+	events     ports.EventPublisher
+	attempts   int
+	backoff    time.Duration
+	deadLetter ports.DeadLetterSink
+	batches    ports.BatchRegistry
+	skipDone   bool
+	pseudonyms ports.Pseudonymizer
+	contracts  ports.ContractRegistry
+	source     string
+}
+
+// WithDeliveryRetry 下游交付失败时重试: attempts 为总尝试次数 (含首次，<= 1 表示不重试)，
+// 第 n 次重试前等待 backoff * 2^(n-1)；ctx 取消时立即放弃
+func WithDeliveryRetry(attempts int, backoff time.Duration) IngestorOption {
+	return func(c *ingestorConfig) {
+		c.attempts, c.backoff = attempts, backoff
+	}
+}
+
+// WithDeadLetterSink 设置死信输出: 重试后仍交付失败的批次连同错误信息写入 sink，摄入继续处理后续记录，
+// 这些记录计入 IngestionResult.DeadLettered。死信写入也失败时摄入中止并返回两个错误。
+// 未设置时交付失败直接中止摄入 (与之前的行为一致)
+func WithDeadLetterSink(sink ports.DeadLetterSink) IngestorOption {
+	return func(c *ingestorConfig) {
+		c.deadLetter = sink
+	}
+}
+
+// WithBatchRegistry 按 IngestContext.BatchID 保证批次只摄入一次: 开始前原子地认领批次，摄入正常结束 (未返回错误) 后登记完成，
+// 失败时释放认领。同一 BatchID 正在处理时并发提交返回 ports.ErrBatchInProgress；
+// 已完成后再次提交默认返回 ports.ErrBatchAlreadyProcessed 且不读取输入；
+// skip 为 true 时仍解析输入但不交付下游，有效记录计入 IngestionResult.Skipped 而非 Success。
+// 修正内容后重新上传的文件应使用新的 BatchID；ctx 未携带 BatchID 时不做检查
+func WithBatchRegistry(registry ports.BatchRegistry, skip bool) IngestorOption {
+	return func(c *ingestorConfig) {
+		c.batches, c.skipDone = registry, skip
+	}
+}
+
+// WithPseudonymizer 在交付下游之前把读数的设备ID替换为假名 (如 services.HMACPseudonymizer)，
+// 标准化、持久化与死信都只看到假名。假名化失败时摄入中止，不会把原始设备ID交给下游
+func WithPseudonymizer(p ports.Pseudonymizer) IngestorOption {
+	return func(c *ingestorConfig) {
+		c.pseudonyms = p
+	}
+}
+
+// WithDataContract 按来源系统 source 在 registry 中登记的契约校验输入:
+// CSV 校验表头，JSON 校验每个对象的字段；字段缺失或出现未登记字段时摄入以 domain.ErrContractViolation 中止，
+// 契约未登记时同样中止。契约的 Mapping 把源字段改名为标准字段后再解析
+func WithDataContract(registry ports.ContractRegistry, source string) IngestorOption {
+	return func(c *ingestorConfig) {
+		c.contracts, c.source = registry, source
+	}
+}
+
+// WithEventPublisher 设置事件输出 (可传入 EventBus): 每次 IngestStream / IngestBatch 返回结果时发出 BatchIngested
+// 发布失败不影响摄入结果
+func WithEventPublisher(publisher ports.EventPublisher) IngestorOption {
+	return func(c *ingestorConfig) {
+		c.events = publisher
+	}
+}
+
+func newIngestorConfig(opts []IngestorOption) ingestorConfig {
+	var c ingestorConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// once 按 WithBatchRegistry 执行一次摄入: run 使用传入的 downstream 交付读数
+// 先原子地认领批次: 已完成时拒绝，或以丢弃读数的 downstream 运行并把成功数转为跳过数；
+// 正由另一次提交处理时返回 ports.ErrBatchInProgress。摄入正常结束后登记完成，失败时释放认领以便重试
+func (c ingestorConfig) once(ctx context.Context, downstream downstreamFunc, run func(downstreamFunc) (*domain.IngestionResult, error)) (*domain.IngestionResult, error) {
+	ic, _ := domain.FromContext(ctx)
+	if c.batches == nil || ic.BatchID == "" {
+		return run(downstream)
+	}
+	done, err := c.batches.Begin(ctx, ic.BatchID)
+	if err != nil {
+		return nil, fmt.Errorf("claim batch %s: %w", ic.BatchID, err)
+	}
+	if done {
+		if !c.skipDone {
+			return nil, fmt.Errorf("batch %s: %w", ic.BatchID, ports.ErrBatchAlreadyProcessed)
+		}
+		result, err := run(func(context.Context, []domain.Reading) error { return nil })
+		if result != nil {
+			result.Skipped += result.Success
+			result.Success = 0
+		}
+		return result, err
+	}
+
+	result, err := run(downstream)
+	if err != nil {
+		// ctx 可能已取消，释放认领不应因此失败
+		if releaseErr := c.batches.Release(context.WithoutCancel(ctx), ic.BatchID); releaseErr != nil {
+			err = errors.Join(err, fmt.Errorf("release batch %s: %w", ic.BatchID, releaseErr))
+		}
+		return result, err
+	}
+	// 读数已交付，登记失败不应让调用方重试整个批次
+	if markErr := c.batches.MarkCompleted(context.WithoutCancel(ctx), ic.BatchID, time.Now()); markErr != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("register batch %s: %v", ic.BatchID, markErr))
+	}
+	return result, nil
+}
+
+// pseudonymize 原地替换批次的设备ID (未配置 WithPseudonymizer 时为空操作)
+// 读数自带租户时按读数的租户生成假名
+func (c ingestorConfig) pseudonymize(ctx context.Context, batch []domain.Reading) error {
+	if c.pseudonyms == nil {
+		return nil
+	}
+	for i := range batch {
+		dctx := ctx
+		if tenantID := batch[i].DeviceInfo.TenantID; tenantID != "" {
+			dctx = domain.WithTenant(ctx, tenantID)
+		}
+		pseudonym, err := c.pseudonyms.Pseudonymize(dctx, batch[i].DeviceInfo.ID)
+		if err != nil {
+			return fmt.Errorf("pseudonymize device id: %w", err)
+		}
+		batch[i].DeviceInfo.ID = pseudonym
+	}
+	return nil
+}
+
+// contract 加载来源的数据契约 (未配置 WithDataContract 时返回 nil)
+func (c ingestorConfig) contract(ctx context.Context) (*domain.DataContract, error) {
+	if c.contracts == nil {
+		return nil, nil
+	}
+	contract, err := c.contracts.Get(ctx, c.source)
+	if err != nil {
+		return nil, fmt.Errorf("load data contract for source %s: %w", c.source, err)
+	}
+	return contract, nil
+}
+
+// ingested 发出 BatchIngested 事件 (result 为 nil 表示输入整体无法解析，不发出)
+func (c ingestorConfig) ingested(ctx context.Context, format string, result *domain.IngestionResult) {
+	if c.events == nil || result == nil {
+		return
+	}
+	_ = c.events.Publish(ctx, ports.BatchIngested{Format: format, Result: *result, OccurredAt: time.Now()})
+}
+
+// deliver 把批次交给下游，按 WithDeliveryRetry 重试；最终失败时写入死信输出并更新 result
+// 返回 nil 表示批次已交付或已写入死信，摄入可以继续
+func (c ingestorConfig) deliver(ctx context.Context, format string, downstream downstreamFunc, batch []domain.Reading, result *domain.IngestionResult) error {
+	if err := c.pseudonymize(ctx, batch); err != nil {
+		return err
+	}
+	attempts, err := 0, error(nil)
+retry:
+	for delay := c.backoff; ; delay *= 2 {
+		attempts++
+		if err = downstream(ctx, batch); err == nil {
+			return nil
+		}
+		if attempts >= c.attempts || ctx.Err() != nil {
+			break
+		}
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				break retry
+			}
+		}
+	}
+	if c.deadLetter == nil {
+		return err
+	}
+
+	letter := ports.DeadLetter{
+		Source:   format,
+		Readings: slices.Clone(batch), // 调用方会复用缓冲区
+		Error:    err.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now(),
+	}
+	// 即使摄入的 ctx 已取消，也要尽力保存死信
+	if dlqErr := c.deadLetter.Write(context.WithoutCancel(ctx), letter); dlqErr != nil {
+		return errors.Join(err, fmt.Errorf("write dead letter: %w", dlqErr))
+	}
+	result.Success -= len(batch)
+	result.DeadLettered += len(batch)
+	result.Errors = append(result.Errors, fmt.Sprintf("%d readings dead-lettered after %d attempts: %v", len(batch), attempts, err))
+	return nil
+}
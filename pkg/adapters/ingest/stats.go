@@ -0,0 +1,35 @@
+package ingest
+
+import (
+	"io"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// countingReader 统计从输入流读取的字节数
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// measure 包装一次摄入调用，在结果中填写耗时、读取字节数与吞吐量 (结果为 nil 时不填写)
+func measure(stream io.Reader, ingest func(io.Reader) (*domain.IngestionResult, error)) (*domain.IngestionResult, error) {
+	start := time.Now()
+	counter := &countingReader{r: stream}
+	result, err := ingest(counter)
+	if result != nil {
+		result.Duration = time.Since(start)
+		result.BytesRead = counter.n
+		if secs := result.Duration.Seconds(); secs > 0 {
+			result.RecordsPerSecond = float64(result.Total) / secs
+		}
+	}
+	return result, err
+}
@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"mime"
+	"net/smtp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// SendMailFunc 发送邮件的函数签名 (与 smtp.SendMail 相同)
+type SendMailFunc func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+// EmailOption 配置 EmailNotifier
+type EmailOption func(*EmailNotifier)
+
+// WithSMTPAuth 设置 SMTP 认证 (如 smtp.PlainAuth)
+func WithSMTPAuth(auth smtp.Auth) EmailOption {
+	return func(e *EmailNotifier) {
+		e.auth = auth
+	}
+}
+
+// WithSendMail 替换发送函数 (默认 smtp.SendMail)，用于接入其他邮件通道或测试
+func WithSendMail(send SendMailFunc) EmailOption {
+	return func(e *EmailNotifier) {
+		if send != nil {
+			e.send = send
+		}
+	}
+}
+
+// EmailNotifier 通过 SMTP 发送纯文本告警邮件
+// smtp.SendMail 不感知 ctx: 发送开始后无法取消，ctx 只在发送前检查
+type EmailNotifier struct {
+	addr string
+	from string
+	to   []string
+	auth smtp.Auth
+	send SendMailFunc
+}
+
+// 编译期检查接口实现
+var _ ports.Notifier = (*EmailNotifier)(nil)
+
+// NewEmailNotifier 创建邮件通知，addr 为 SMTP 服务地址 (host:port)
+func NewEmailNotifier(addr, from string, to []string, opts ...EmailOption) (*EmailNotifier, error) {
+	if addr == "" || from == "" || len(to) == 0 {
+		return nil, errors.New("email notifier: smtp address, sender and recipients are required")
+	}
+	e := &EmailNotifier{addr: addr, from: from, to: slices.Clone(to), send: smtp.SendMail}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
+}
+
+// Notify 实现 ports.Notifier
+func (e *EmailNotifier) Notify(ctx context.Context, n ports.Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := e.send(e.addr, e.auth, e.from, e.to, e.message(n)); err != nil {
+		return fmt.Errorf("send notification email: %w", err)
+	}
+	return nil
+}
+
+// message 构造邮件 (标题按 RFC 2047 编码，正文为消息加按名称排序的标签)
+func (e *EmailNotifier) message(n ports.Notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "[prism] "+n.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", n.OccurredAt.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(n.Message, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	if len(n.Labels) > 0 {
+		b.WriteString("\r\n")
+		for _, k := range slices.Sorted(maps.Keys(n.Labels)) {
+			fmt.Fprintf(&b, "%s: %s\r\n", k, n.Labels[k])
+		}
+	}
+	return []byte(b.String())
+}
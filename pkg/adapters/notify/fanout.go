@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// Fanout 把通知依次发送到多个目标 (如同时发 Webhook 与邮件)
+// 某个目标失败不影响其他目标，全部错误聚合返回
+type Fanout []ports.Notifier
+
+// 编译期检查接口实现
+var _ ports.Notifier = Fanout(nil)
+
+// Notify 实现 ports.Notifier
+func (f Fanout) Notify(ctx context.Context, n ports.Notification) error {
+	var errs []error
+	for _, notifier := range f {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,87 @@
+// Package notify 提供 ports.Notifier 的实现: Webhook (HTTP POST JSON) 与邮件 (SMTP)
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DefaultWebhookTimeout 未指定 HTTP 客户端时的请求超时
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookOption 配置 WebhookNotifier
+type WebhookOption func(*WebhookNotifier)
+
+// WithHTTPClient 设置 HTTP 客户端 (默认超时 DefaultWebhookTimeout)
+func WithHTTPClient(client *http.Client) WebhookOption {
+	return func(w *WebhookNotifier) {
+		if client != nil {
+			w.client = client
+		}
+	}
+}
+
+// WithHeader 为每个请求添加请求头 (如鉴权令牌)
+func WithHeader(key, value string) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.header.Add(key, value)
+	}
+}
+
+// WebhookNotifier 把通知以 JSON (ports.Notification 的字段) POST 到指定地址
+// 非 2xx 响应视为发送失败
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+	header http.Header
+}
+
+// 编译期检查接口实现
+var _ ports.Notifier = (*WebhookNotifier)(nil)
+
+// NewWebhookNotifier 创建 Webhook 通知
+func NewWebhookNotifier(url string, opts ...WebhookOption) (*WebhookNotifier, error) {
+	if url == "" {
+		return nil, errors.New("webhook url is required")
+	}
+	w := &WebhookNotifier{url: url, client: &http.Client{Timeout: DefaultWebhookTimeout}, header: make(http.Header)}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w, nil
+}
+
+// Notify 实现 ports.Notifier
+func (w *WebhookNotifier) Notify(ctx context.Context, n ports.Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	for key, values := range w.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16)) // 读完响应以复用连接
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("send webhook: unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package sqlmigrate 执行内嵌的 SQL 迁移，文件命名与版本表与 golang-migrate 兼容:
+// 迁移文件为 {version}_{title}.up.sql / .down.sql，当前版本记录在 schema_migrations (version, dirty) 的唯一一行中，
+// 因此同一数据库可以随时改用 golang-migrate CLI (如执行 down 迁移或 force 修复 dirty 版本)。
+package sqlmigrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Table 版本表名 (与 golang-migrate 默认值一致)
+const Table = "schema_migrations"
+
+// Migration 一个向上迁移
+type Migration struct {
+	Version uint64
+	Name    string // 文件名
+	SQL     string
+}
+
+// Load 读取 fsys 根目录下的全部 *.up.sql，按版本升序返回
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Migration, 0, len(names))
+	for _, name := range names {
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: missing {version}_ prefix", name)
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version: %w", name, err)
+		}
+		body, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Migration{Version: version, Name: name, SQL: string(body)})
+	}
+	slices.SortFunc(out, func(a, b Migration) int {
+		switch {
+		case a.Version < b.Version:
+			return -1
+		case a.Version > b.Version:
+			return 1
+		}
+		return 0
+	})
+	for i := 1; i < len(out); i++ {
+		if out[i].Version == out[i-1].Version {
+			return nil, fmt.Errorf("migration %s: duplicate version %d", out[i].Name, out[i].Version)
+		}
+	}
+	return out, nil
+}
+
+// Up 依次执行高于当前版本的迁移
+// versionTableDDL 为创建版本表的方言 DDL (须包含 IF NOT EXISTS)。
+// 与 golang-migrate 相同，执行前将版本标记为 dirty、成功后清除；版本为 dirty 时拒绝继续，需人工修复后 force。
+// 不加锁: 多个实例同时启动时应由部署流程保证只有一个实例执行迁移。
+func Up(ctx context.Context, db *sql.DB, fsys fs.FS, versionTableDDL string) error {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, versionTableDDL); err != nil {
+		return fmt.Errorf("create %s: %w", Table, err)
+	}
+	current, dirty, err := version(ctx, db)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%s: version %d is dirty, fix the schema and force the version before migrating", Table, current)
+	}
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		if err := setVersion(ctx, db, m.Version, true); err != nil {
+			return err
+		}
+		if _, err := db.ExecContext(ctx, m.SQL); err != nil {
+			return fmt.Errorf("apply migration %s: %w", m.Name, err)
+		}
+		if err := setVersion(ctx, db, m.Version, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// version 读取当前版本 (未执行过迁移时为 0)
+func version(ctx context.Context, db *sql.DB) (uint64, bool, error) {
+	var (
+		v     int64
+		dirty bool
+	)
+	err := db.QueryRowContext(ctx, "SELECT version, dirty FROM "+Table+" LIMIT 1").Scan(&v, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read %s: %w", Table, err)
+	}
+	return uint64(v), dirty, nil
+}
+
+// setVersion 以单行替换的方式记录版本 (版本号与布尔值直接写入语句，避免方言占位符差异)
+func setVersion(ctx context.Context, db *sql.DB, v uint64, dirty bool) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+Table); err != nil {
+		return fmt.Errorf("set %s version: %w", Table, err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (version, dirty) VALUES (%d, %t)", Table, v, dirty)); err != nil {
+		return fmt.Errorf("set %s version: %w", Table, err)
+	}
+	return tx.Commit()
+}
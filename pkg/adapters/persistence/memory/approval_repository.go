@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// ApprovalRepository 实现 ports.ApprovalRepository
+// 租户隔离: 新建时未设置租户的记录使用 ctx 的租户，查询只返回 ctx 租户的记录
+type ApprovalRepository struct {
+	mu       sync.Mutex
+	seq      int64
+	requests *store[string, domain.ApprovalRequest]
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.ApprovalRepository = (*ApprovalRepository)(nil)
+	_ ports.HealthChecker      = (*ApprovalRepository)(nil)
+)
+
+// NewApprovalRepository 创建内存审批仓储
+func NewApprovalRepository(opts ...Option) *ApprovalRepository {
+	return &ApprovalRepository{requests: newStore[string, domain.ApprovalRequest](newConfig(opts))}
+}
+
+// Create 分配 ID (a-1, a-2, ...) 并保存
+func (r *ApprovalRepository) Create(ctx context.Context, req domain.ApprovalRequest) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if req.TenantID == "" {
+		req.TenantID = domain.TenantFromContext(ctx)
+	}
+	if req.Status == "" {
+		req.Status = domain.ApprovalStatusPending
+	}
+	if req.SubmittedAt.IsZero() {
+		req.SubmittedAt = r.requests.cfg.now()
+	}
+	r.seq++
+	req.ID = fmt.Sprintf("a-%d", r.seq)
+	req.Readings = slices.Clone(req.Readings)
+	r.requests.put(req.ID, req)
+	return req.ID, nil
+}
+
+// Get 按 ID 获取 (其他租户的记录视为不存在)
+func (r *ApprovalRepository) Get(ctx context.Context, id string) (*domain.ApprovalRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.requests.get(id)
+	if !ok || req.TenantID != domain.TenantFromContext(ctx) {
+		return nil, ports.ErrNotFound
+	}
+	req.Readings = slices.Clone(req.Readings)
+	return &req, nil
+}
+
+// Update 在同一把锁内比较当前状态并更新已有记录
+func (r *ApprovalRepository) Update(ctx context.Context, req domain.ApprovalRequest, expected domain.ApprovalStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.requests.get(req.ID)
+	if !ok || existing.TenantID != domain.TenantFromContext(ctx) {
+		return ports.ErrNotFound
+	}
+	if existing.Status != expected {
+		return fmt.Errorf("approval request %s is %s, expected %s: %w", req.ID, existing.Status, expected, ports.ErrApprovalStatusChanged)
+	}
+	req.TenantID = existing.TenantID
+	req.Readings = slices.Clone(req.Readings)
+	r.requests.put(req.ID, req)
+	return nil
+}
+
+// ListPending 按提交时间升序返回待审批记录
+func (r *ApprovalRepository) ListPending(ctx context.Context, limit int) ([]domain.ApprovalRequest, error) {
+	tenantID := domain.TenantFromContext(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.ApprovalRequest
+	r.requests.each(func(_ string, req domain.ApprovalRequest) bool {
+		if req.TenantID == tenantID && req.Status == domain.ApprovalStatusPending {
+			req.Readings = slices.Clone(req.Readings)
+			out = append(out, req)
+		}
+		return true
+	})
+	slices.SortFunc(out, func(a, b domain.ApprovalRequest) int {
+		return cmp.Or(a.SubmittedAt.Compare(b.SubmittedAt), cmp.Compare(a.ID, b.ID))
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *ApprovalRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// AuditRepository 实现 ports.AuditRepository
+// 审计记录按追加顺序编号保存；WithMaxSize / WithTTL 可限制保留的记录
+type AuditRepository struct {
+	mu      sync.Mutex
+	seq     int64
+	entries *store[int64, ports.StandardReadingAudit]
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.AuditRepository = (*AuditRepository)(nil)
+	_ ports.HealthChecker   = (*AuditRepository)(nil)
+)
+
+// NewAuditRepository 创建内存审计日志
+func NewAuditRepository(opts ...Option) *AuditRepository {
+	return &AuditRepository{entries: newStore[int64, ports.StandardReadingAudit](newConfig(opts))}
+}
+
+// Record 追加审计记录
+func (r *AuditRepository) Record(ctx context.Context, entries []ports.StandardReadingAudit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range entries {
+		r.seq++
+		r.entries.put(r.seq, e)
+	}
+	return nil
+}
+
+// FindByDevice 获取设备在 [start, end] 内的审计记录 (按覆盖时间、追加顺序排序)
+func (r *AuditRepository) FindByDevice(ctx context.Context, deviceID string, start, end time.Time) ([]ports.StandardReadingAudit, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []ports.StandardReadingAudit
+	r.entries.each(func(_ int64, e ports.StandardReadingAudit) bool {
+		ts := e.New.Timestamp
+		if e.New.DeviceID == deviceID && !ts.Before(start) && !ts.After(end) {
+			out = append(out, e)
+		}
+		return true
+	})
+	slices.SortStableFunc(out, func(a, b ports.StandardReadingAudit) int {
+		return a.ChangedAt.Compare(b.ChangedAt)
+	})
+	return out, nil
+}
+
+// Len 返回保存的审计记录数
+func (r *AuditRepository) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.entries.len()
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *AuditRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
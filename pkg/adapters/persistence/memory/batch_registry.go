@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// BatchRegistry 实现 ports.BatchRegistry
+// 批次按 (租户, BatchID) 登记；WithTTL 可让过久的批次过期，之后同一 BatchID 可再次摄入
+type BatchRegistry struct {
+	mu      sync.Mutex
+	batches *store[string, time.Time] // 键为 domain.TenantScopedID(租户, BatchID)，值为完成时间 (零值表示处理中)
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.BatchRegistry = (*BatchRegistry)(nil)
+	_ ports.HealthChecker = (*BatchRegistry)(nil)
+)
+
+// NewBatchRegistry 创建内存批次登记表
+func NewBatchRegistry(opts ...Option) *BatchRegistry {
+	return &BatchRegistry{batches: newStore[string, time.Time](newConfig(opts))}
+}
+
+// Begin 在同一把锁内检查并认领 ctx 租户下的批次
+func (r *BatchRegistry) Begin(ctx context.Context, batchID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := scoped(ctx, batchID)
+	if completedAt, ok := r.batches.get(key); ok {
+		if completedAt.IsZero() {
+			return false, fmt.Errorf("batch %s: %w", batchID, ports.ErrBatchInProgress)
+		}
+		return true, nil
+	}
+	r.batches.put(key, time.Time{})
+	return false, nil
+}
+
+// MarkCompleted 登记批次完成 (已完成时保留首次完成时间，at 为零值时取当前时间)
+func (r *BatchRegistry) MarkCompleted(ctx context.Context, batchID string, at time.Time) error {
+	if at.IsZero() {
+		at = r.batches.cfg.now()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := scoped(ctx, batchID)
+	if completedAt, ok := r.batches.get(key); !ok || completedAt.IsZero() {
+		r.batches.put(key, at)
+	}
+	return nil
+}
+
+// Release 删除处理中的认领 (已完成的批次保持不变)
+func (r *BatchRegistry) Release(ctx context.Context, batchID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := scoped(ctx, batchID)
+	if completedAt, ok := r.batches.get(key); ok && completedAt.IsZero() {
+		r.batches.delete(key)
+	}
+	return nil
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *BatchRegistry) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
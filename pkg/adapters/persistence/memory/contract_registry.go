@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// ContractRegistry 实现 ports.ContractRegistry (每个来源只保留最新登记的契约)
+type ContractRegistry struct {
+	mu        sync.Mutex
+	contracts *store[string, domain.DataContract]
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.ContractRegistry = (*ContractRegistry)(nil)
+	_ ports.HealthChecker    = (*ContractRegistry)(nil)
+)
+
+// NewContractRegistry 创建内存契约登记表
+func NewContractRegistry(opts ...Option) *ContractRegistry {
+	return &ContractRegistry{contracts: newStore[string, domain.DataContract](newConfig(opts))}
+}
+
+// Register 登记或替换契约
+func (r *ContractRegistry) Register(ctx context.Context, contract domain.DataContract) error {
+	if contract.Source == "" {
+		return errors.New("register contract: source is required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.contracts.put(contract.Source, cloneContract(contract))
+	return nil
+}
+
+// Get 获取来源的契约
+func (r *ContractRegistry) Get(ctx context.Context, source string) (*domain.DataContract, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	contract, ok := r.contracts.get(source)
+	if !ok {
+		return nil, ports.ErrNotFound
+	}
+	contract = cloneContract(contract)
+	return &contract, nil
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *ContractRegistry) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func cloneContract(c domain.DataContract) domain.DataContract {
+	c.Fields = slices.Clone(c.Fields)
+	c.Optional = slices.Clone(c.Optional)
+	c.Mapping = maps.Clone(c.Mapping)
+	return c
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DeadLetterRepository 实现 ports.DeadLetterRepository
+// 死信按写入顺序编号保存；WithMaxSize / WithTTL 可限制保留的死信
+type DeadLetterRepository struct {
+	mu      sync.Mutex
+	seq     int64
+	letters *store[int64, ports.DeadLetter]
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.DeadLetterRepository = (*DeadLetterRepository)(nil)
+	_ ports.HealthChecker        = (*DeadLetterRepository)(nil)
+)
+
+// NewDeadLetterRepository 创建内存死信仓储
+func NewDeadLetterRepository(opts ...Option) *DeadLetterRepository {
+	return &DeadLetterRepository{letters: newStore[int64, ports.DeadLetter](newConfig(opts))}
+}
+
+// Write 分配 ID 并保存死信
+func (r *DeadLetterRepository) Write(ctx context.Context, letter ports.DeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	letter.ID = r.seq
+	letter.Readings = slices.Clone(letter.Readings)
+	r.letters.put(r.seq, letter)
+	return nil
+}
+
+// List 按 ID 升序返回至多 limit 条死信
+func (r *DeadLetterRepository) List(ctx context.Context, limit int) ([]ports.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []ports.DeadLetter
+	r.letters.each(func(_ int64, l ports.DeadLetter) bool {
+		l.Readings = slices.Clone(l.Readings)
+		out = append(out, l)
+		return true
+	})
+	slices.SortFunc(out, func(a, b ports.DeadLetter) int { return cmp.Compare(a.ID, b.ID) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// Delete 删除指定死信 (不存在的 ID 忽略)
+func (r *DeadLetterRepository) Delete(ctx context.Context, ids []int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		r.letters.delete(id)
+	}
+	return nil
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *DeadLetterRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
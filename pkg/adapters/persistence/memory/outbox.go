@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// Outbox 实现 ports.Outbox
+// 通过 WithOutbox 交给 StandardReadingRepository，写入生效的标准读数时在仓储锁内追加消息；已发布的消息直接删除。
+type Outbox struct {
+	mu       sync.Mutex
+	seq      int64
+	messages []ports.OutboxMessage
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.Outbox        = (*Outbox)(nil)
+	_ ports.HealthChecker = (*Outbox)(nil)
+)
+
+// NewOutbox 创建内存发件箱
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+// Pending 按 ID 升序返回至多 limit 条未发布的消息
+func (o *Outbox) Pending(ctx context.Context, limit int) ([]ports.OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := len(o.messages)
+	if limit > 0 {
+		n = min(n, limit)
+	}
+	return slices.Clone(o.messages[:n]), nil
+}
+
+// MarkPublished 删除已发布的消息
+func (o *Outbox) MarkPublished(ctx context.Context, ids []int64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = slices.DeleteFunc(o.messages, func(m ports.OutboxMessage) bool {
+		return slices.Contains(ids, m.ID)
+	})
+	return nil
+}
+
+// Len 返回未发布的消息数
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.messages)
+}
+
+// append 分配序号并追加消息
+func (o *Outbox) append(msgs []ports.OutboxMessage, now time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, m := range msgs {
+		o.seq++
+		m.ID, m.CreatedAt = o.seq, now
+		o.messages = append(o.messages, m)
+	}
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (o *Outbox) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
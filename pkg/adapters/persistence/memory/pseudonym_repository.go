@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// PseudonymRepository 实现 ports.PseudonymRepository
+// 映射按 (租户, 假名) 保存；进程重启后丢失，生产环境的映射应使用持久化存储
+type PseudonymRepository struct {
+	mu      sync.Mutex
+	mapping *store[string, string] // 键为 domain.TenantScopedID(租户, 假名)，值为设备ID
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.PseudonymRepository = (*PseudonymRepository)(nil)
+	_ ports.HealthChecker       = (*PseudonymRepository)(nil)
+)
+
+// NewPseudonymRepository 创建内存假名映射仓储
+func NewPseudonymRepository(opts ...Option) *PseudonymRepository {
+	return &PseudonymRepository{mapping: newStore[string, string](newConfig(opts))}
+}
+
+// Save 保存 ctx 租户下的映射
+func (r *PseudonymRepository) Save(ctx context.Context, pseudonym, deviceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mapping.put(scoped(ctx, pseudonym), deviceID)
+	return nil
+}
+
+// Lookup 按假名查找 ctx 租户下的设备ID
+func (r *PseudonymRepository) Lookup(ctx context.Context, pseudonym string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deviceID, ok := r.mapping.get(scoped(ctx, pseudonym))
+	if !ok {
+		return "", ports.ErrNotFound
+	}
+	return deviceID, nil
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *PseudonymRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// QuarantineRepository 实现 ports.QuarantineRepository
+// 租户隔离: 保存时未设置租户的记录使用 ctx 的租户 (domain.WithTenant)，查询只返回 ctx 租户的记录
+type QuarantineRepository struct {
+	mu      sync.Mutex
+	records *store[string, domain.QuarantineReading]
+	seq     int
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.QuarantineRepository = (*QuarantineRepository)(nil)
+	_ ports.HealthChecker        = (*QuarantineRepository)(nil)
+)
+
+// NewQuarantineRepository 创建内存隔离区仓储
+func NewQuarantineRepository(opts ...Option) *QuarantineRepository {
+	return &QuarantineRepository{records: newStore[string, domain.QuarantineReading](newConfig(opts))}
+}
+
+// Save 保存一条隔离记录 (按 ID 新增或更新)
+// 清洗阶段产生的记录没有 ID，保存时按顺序生成 (q-1, q-2, ...)
+func (r *QuarantineRepository) Save(ctx context.Context, record domain.QuarantineReading) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if record.TenantID == "" {
+		record.TenantID = domain.TenantFromContext(ctx)
+	}
+	if record.ID == "" {
+		r.seq++
+		record.ID = fmt.Sprintf("q-%d", r.seq)
+	}
+	if record.Status == "" {
+		record.Status = domain.QuarantineStatusPending
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = r.records.cfg.now()
+	}
+	r.records.put(record.ID, record)
+	return nil
+}
+
+// UpdateStatus 仅当记录的当前状态为 expected 时以 record 替换 (在锁内比较并写入)
+func (r *QuarantineRepository) UpdateStatus(ctx context.Context, record domain.QuarantineReading, expected domain.QuarantineStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tenantID := domain.TenantFromContext(ctx)
+	current, ok := r.records.get(record.ID)
+	if !ok || current.TenantID != tenantID {
+		return fmt.Errorf("quarantine record %s: %w", record.ID, ports.ErrNotFound)
+	}
+	if current.Status != expected {
+		return fmt.Errorf("quarantine record %s is %s, expected %s: %w", record.ID, current.Status, expected, ports.ErrQuarantineStatusChanged)
+	}
+	record.TenantID = tenantID
+	r.records.put(record.ID, record)
+	return nil
+}
+
+// FindPending 获取待处理的隔离记录 (按隔离时间排序，limit <= 0 表示不限制)
+func (r *QuarantineRepository) FindPending(ctx context.Context, limit int) ([]domain.QuarantineReading, error) {
+	return r.find(ctx, limit, func(q domain.QuarantineReading) bool {
+		return q.Status == domain.QuarantineStatusPending
+	}, byCreatedAt), nil
+}
+
+// FindPendingByDeviceType 获取某设备类型下待处理的隔离记录
+func (r *QuarantineRepository) FindPendingByDeviceType(ctx context.Context, deviceType domain.DeviceType, limit int) ([]domain.QuarantineReading, error) {
+	return r.find(ctx, limit, func(q domain.QuarantineReading) bool {
+		return q.Status == domain.QuarantineStatusPending && q.Reading.DeviceInfo.Type == deviceType
+	}, byCreatedAt), nil
+}
+
+// FindByDevice 获取设备在 [start, end] 时间范围内的隔离记录 (任意状态，按读数时间排序)
+func (r *QuarantineRepository) FindByDevice(ctx context.Context, deviceID string, start, end time.Time) ([]domain.QuarantineReading, error) {
+	return r.find(ctx, 0, func(q domain.QuarantineReading) bool {
+		ts := q.Reading.Timestamp
+		return q.Reading.DeviceInfo.ID == deviceID && !ts.Before(start) && !ts.After(end)
+	}, func(a, b domain.QuarantineReading) int {
+		return a.Reading.Timestamp.Compare(b.Reading.Timestamp)
+	}), nil
+}
+
+// Find 按条件查询隔离记录 (按隔离时间排序)
+func (r *QuarantineRepository) Find(ctx context.Context, filter ports.QuarantineFilter) ([]domain.QuarantineReading, error) {
+	out := r.find(ctx, 0, filter.Match, byCreatedAt)
+	out = out[min(max(filter.Offset, 0), len(out)):]
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
+
+// Count 统计满足条件的隔离记录数
+func (r *QuarantineRepository) Count(ctx context.Context, filter ports.QuarantineFilter, groupBy ports.QuarantineGroupBy) ([]ports.QuarantineCount, error) {
+	if err := groupBy.Validate(); err != nil {
+		return nil, err
+	}
+	return ports.CountQuarantine(r.find(ctx, 0, filter.Match, byCreatedAt), groupBy), nil
+}
+
+// Len 返回当前保存的隔离记录数 (不含已过期条目)
+func (r *QuarantineRepository) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.records.len()
+}
+
+func (r *QuarantineRepository) find(ctx context.Context, limit int, match func(domain.QuarantineReading) bool, order func(a, b domain.QuarantineReading) int) []domain.QuarantineReading {
+	tenantID := domain.TenantFromContext(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.QuarantineReading
+	r.records.each(func(_ string, q domain.QuarantineReading) bool {
+		if q.TenantID == tenantID && match(q) {
+			out = append(out, q)
+		}
+		return true
+	})
+	slices.SortStableFunc(out, order)
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func byCreatedAt(a, b domain.QuarantineReading) int {
+	return a.CreatedAt.Compare(b.CreatedAt)
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *QuarantineRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
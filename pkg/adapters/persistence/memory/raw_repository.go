@@ -0,0 +1,225 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// rawKey 原始读数唯一键 (同一计量通道同一时间戳只保留最后写入的一条)
+type rawKey struct {
+	channel  string
+	unixNano int64
+}
+
+// RawReadingRepository 实现 ports.RawReadingRepository
+// 租户隔离: 保存时未设置租户的读数使用 ctx 的租户，查询只返回 ctx 租户的读数
+type RawReadingRepository struct {
+	mu       sync.Mutex
+	readings *store[rawKey, domain.Reading]
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.RawReadingRepository = (*RawReadingRepository)(nil)
+	_ ports.HealthChecker        = (*RawReadingRepository)(nil)
+)
+
+// NewRawReadingRepository 创建内存原始读数仓储
+func NewRawReadingRepository(opts ...Option) *RawReadingRepository {
+	return &RawReadingRepository{readings: newStore[rawKey, domain.Reading](newConfig(opts))}
+}
+
+// Save 保存原始读数 (同一计量通道同一时间戳以最后写入的为准)
+func (r *RawReadingRepository) Save(ctx context.Context, readings []domain.Reading) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tenantID := domain.TenantFromContext(ctx)
+	for _, rd := range readings {
+		if rd.DeviceInfo.TenantID == "" {
+			rd.DeviceInfo.TenantID = tenantID
+		}
+		r.readings.put(rawKey{rd.ChannelID(), rd.Timestamp.UnixNano()}, rd)
+	}
+	return nil
+}
+
+// FindRange 获取设备在 [start, end] 时间范围内的原始读数 (全部通道)，按时间排序
+func (r *RawReadingRepository) FindRange(ctx context.Context, deviceID string, start, end time.Time) ([]domain.Reading, error) {
+	tenantID := domain.TenantFromContext(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.Reading
+	r.readings.each(func(_ rawKey, rd domain.Reading) bool {
+		if rd.DeviceInfo.TenantID == tenantID && rd.DeviceInfo.ID == deviceID && !rd.Timestamp.Before(start) && !rd.Timestamp.After(end) {
+			out = append(out, rd)
+		}
+		return true
+	})
+	slices.SortStableFunc(out, func(a, b domain.Reading) int { return a.Timestamp.Compare(b.Timestamp) })
+	return out, nil
+}
+
+// DeviceRepository 实现 ports.DeviceRepository 与 ports.DeviceCatalog
+// 租户隔离: 设备按 (租户, ID) 保存，查询只返回 ctx 租户的设备
+type DeviceRepository struct {
+	mu      sync.Mutex
+	devices *store[string, domain.DeviceInfo] // 键为 domain.TenantScopedID(租户, ID)
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.DeviceRepository = (*DeviceRepository)(nil)
+	_ ports.DeviceCatalog    = (*DeviceRepository)(nil)
+	_ ports.HealthChecker    = (*DeviceRepository)(nil)
+)
+
+// NewDeviceRepository 创建内存设备仓储，可选用 ids 预置已知设备 (只有 ID，无其他元数据)
+func NewDeviceRepository(ids []string, opts ...Option) *DeviceRepository {
+	r := &DeviceRepository{devices: newStore[string, domain.DeviceInfo](newConfig(opts))}
+	r.Add(ids...)
+	return r
+}
+
+// Add 在默认租户下登记设备 (只有 ID；已登记的设备保留原有元数据)
+func (r *DeviceRepository) Add(ids ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := r.devices.get(id); !ok {
+			r.devices.put(id, domain.DeviceInfo{ID: id})
+		}
+	}
+}
+
+// Save 新增或更新设备元数据
+func (r *DeviceRepository) Save(ctx context.Context, device domain.DeviceInfo) error {
+	if device.ID == "" {
+		return errors.New("save device: id is required")
+	}
+	if device.TenantID == "" {
+		device.TenantID = domain.TenantFromContext(ctx)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.devices.put(domain.TenantScopedID(device.TenantID, device.ID), cloneDevice(device))
+	return nil
+}
+
+// GetByID 获取设备元数据
+func (r *DeviceRepository) GetByID(ctx context.Context, deviceID string) (*domain.DeviceInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.devices.get(scoped(ctx, deviceID))
+	if !ok {
+		return nil, fmt.Errorf("device %s: %w", deviceID, ports.ErrNotFound)
+	}
+	d = cloneDevice(d)
+	return &d, nil
+}
+
+// List 列出设备，按 ID 升序
+func (r *DeviceRepository) List(ctx context.Context, deviceType domain.DeviceType) ([]domain.DeviceInfo, error) {
+	tenantID := domain.TenantFromContext(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.DeviceInfo
+	r.devices.each(func(_ string, d domain.DeviceInfo) bool {
+		if d.TenantID == tenantID && (deviceType == "" || d.Type == deviceType) {
+			out = append(out, cloneDevice(d))
+		}
+		return true
+	})
+	slices.SortFunc(out, func(a, b domain.DeviceInfo) int { return strings.Compare(a.ID, b.ID) })
+	return out, nil
+}
+
+// Delete 删除设备
+func (r *DeviceRepository) Delete(ctx context.Context, deviceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.devices.delete(scoped(ctx, deviceID))
+	return nil
+}
+
+// cloneDevice 复制标签与校准系数，避免调用方修改仓储内的数据
+func cloneDevice(d domain.DeviceInfo) domain.DeviceInfo {
+	d.Tags = maps.Clone(d.Tags)
+	if d.Calibration != nil {
+		c := *d.Calibration
+		d.Calibration = &c
+	}
+	return d
+}
+
+// Exists 检查设备是否存在
+func (r *DeviceRepository) Exists(ctx context.Context, deviceID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.devices.get(scoped(ctx, deviceID))
+	return ok, nil
+}
+
+// GapSink 实现 ports.DataGapSink，保存上报的数据缺口
+type GapSink struct {
+	mu   sync.Mutex
+	gaps *store[int, domain.DataGap]
+	seq  int
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.DataGapSink   = (*GapSink)(nil)
+	_ ports.HealthChecker = (*GapSink)(nil)
+)
+
+// NewGapSink 创建内存缺口输出端
+func NewGapSink(opts ...Option) *GapSink {
+	return &GapSink{gaps: newStore[int, domain.DataGap](newConfig(opts))}
+}
+
+// ReportGaps 保存一批数据缺口
+func (s *GapSink) ReportGaps(ctx context.Context, gaps []domain.DataGap) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, g := range gaps {
+		s.seq++
+		s.gaps.put(s.seq, g)
+	}
+	return nil
+}
+
+// Gaps 按上报顺序返回当前保存的缺口
+func (s *GapSink) Gaps() []domain.DataGap {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []domain.DataGap
+	s.gaps.each(func(_ int, g domain.DataGap) bool {
+		out = append(out, g)
+		return true
+	})
+	return out
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *RawReadingRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *DeviceRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (s *GapSink) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// EnergyReportRepository 实现 ports.EnergyReportRepository
+type EnergyReportRepository struct {
+	mu      sync.Mutex
+	reports *store[string, domain.EnergyReport]
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.EnergyReportRepository = (*EnergyReportRepository)(nil)
+	_ ports.HealthChecker          = (*EnergyReportRepository)(nil)
+)
+
+// NewEnergyReportRepository 创建内存能耗报表仓储
+func NewEnergyReportRepository(opts ...Option) *EnergyReportRepository {
+	return &EnergyReportRepository{reports: newStore[string, domain.EnergyReport](newConfig(opts))}
+}
+
+// Save 新增或覆盖报表 (按 ID)
+func (r *EnergyReportRepository) Save(ctx context.Context, reports ...domain.EnergyReport) error {
+	for _, rp := range reports {
+		if rp.ID == "" {
+			return errors.New("save energy report: id is required")
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rp := range reports {
+		r.reports.put(rp.ID, rp)
+	}
+	return nil
+}
+
+// Find 返回设备计量通道在 [filter.Start, filter.End) 内开始的报表，按周期开始时间升序
+func (r *EnergyReportRepository) Find(ctx context.Context, filter ports.EnergyReportFilter) ([]domain.EnergyReport, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.EnergyReport
+	r.reports.each(func(_ string, rp domain.EnergyReport) bool {
+		if rp.DeviceID == filter.DeviceID && rp.Metric == filter.Metric && rp.Period == filter.Period &&
+			!rp.StartTime.Before(filter.Start) && rp.StartTime.Before(filter.End) {
+			out = append(out, rp)
+		}
+		return true
+	})
+	slices.SortStableFunc(out, func(a, b domain.EnergyReport) int { return a.StartTime.Compare(b.StartTime) })
+	return out, nil
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *EnergyReportRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// CleaningRuleRepository 实现 ports.CleaningRuleRepository
+type CleaningRuleRepository struct {
+	mu       sync.Mutex
+	rules    *store[string, domain.CleaningRule]
+	watchers map[*ruleWatcher]struct{}
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.CleaningRuleRepository = (*CleaningRuleRepository)(nil)
+	_ ports.HealthChecker          = (*CleaningRuleRepository)(nil)
+)
+
+// NewCleaningRuleRepository 创建内存清洗规则仓储，可选用 rules 预置规则
+func NewCleaningRuleRepository(rules []domain.CleaningRule, opts ...Option) *CleaningRuleRepository {
+	r := &CleaningRuleRepository{
+		rules:    newStore[string, domain.CleaningRule](newConfig(opts)),
+		watchers: make(map[*ruleWatcher]struct{}),
+	}
+	for _, rule := range rules {
+		r.rules.put(rule.ID, rule)
+	}
+	return r
+}
+
+// Save 保存或更新规则
+func (r *CleaningRuleRepository) Save(ctx context.Context, rule domain.CleaningRule) error {
+	if rule.ID == "" {
+		return errors.New("save cleaning rule: empty id")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event := ports.RuleChangeEvent{Type: ports.RuleChangeSaved, RuleID: rule.ID, DeviceType: rule.DeviceType, At: r.rules.cfg.now()}
+	if previous, ok := r.rules.get(rule.ID); ok && previous.DeviceType != rule.DeviceType {
+		event.PreviousDeviceType = previous.DeviceType
+	}
+	r.rules.put(rule.ID, rule)
+	r.notify(event)
+	return nil
+}
+
+// GetByID 获取指定规则
+func (r *CleaningRuleRepository) GetByID(ctx context.Context, id string) (*domain.CleaningRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.rules.get(id)
+	if !ok {
+		return nil, ports.ErrNotFound
+	}
+	return &rule, nil
+}
+
+// ListByDeviceType 获取适用于特定设备类型的所有规则 (按优先级排序)
+func (r *CleaningRuleRepository) ListByDeviceType(ctx context.Context, deviceType domain.DeviceType) ([]domain.CleaningRule, error) {
+	return r.list(func(rule domain.CleaningRule) bool { return rule.DeviceType == deviceType }), nil
+}
+
+// ListEnabledByDeviceType 获取特定设备类型下所有启用的规则 (按优先级排序)
+func (r *CleaningRuleRepository) ListEnabledByDeviceType(ctx context.Context, deviceType domain.DeviceType) ([]domain.CleaningRule, error) {
+	return r.list(func(rule domain.CleaningRule) bool { return rule.DeviceType == deviceType && rule.Enabled }), nil
+}
+
+// Delete 删除规则 (不存在时为空操作)
+func (r *CleaningRuleRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous, ok := r.rules.get(id)
+	if !ok {
+		return nil
+	}
+	r.rules.delete(id)
+	r.notify(ports.RuleChangeEvent{Type: ports.RuleChangeDeleted, RuleID: id, DeviceType: previous.DeviceType, At: r.rules.cfg.now()})
+	return nil
+}
+
+// Watch 订阅经由本仓储的规则变更 (Save / Delete)，ctx 取消后关闭通道
+// 每个订阅者有独立的无界队列，消费缓慢不会阻塞写入，也不会丢失事件
+func (r *CleaningRuleRepository) Watch(ctx context.Context) (<-chan ports.RuleChangeEvent, error) {
+	w := &ruleWatcher{wake: make(chan struct{}, 1)}
+	r.mu.Lock()
+	r.watchers[w] = struct{}{}
+	r.mu.Unlock()
+
+	out := make(chan ports.RuleChangeEvent)
+	go func() {
+		defer close(out)
+		defer func() {
+			r.mu.Lock()
+			delete(r.watchers, w)
+			r.mu.Unlock()
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.wake:
+			}
+			for _, event := range w.drain() {
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// notify 向全部订阅者投递事件 (调用方持有 r.mu)
+func (r *CleaningRuleRepository) notify(event ports.RuleChangeEvent) {
+	for w := range r.watchers {
+		w.push(event)
+	}
+}
+
+func (r *CleaningRuleRepository) list(match func(domain.CleaningRule) bool) []domain.CleaningRule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.CleaningRule
+	r.rules.each(func(_ string, rule domain.CleaningRule) bool {
+		if match(rule) {
+			out = append(out, rule)
+		}
+		return true
+	})
+	slices.SortFunc(out, func(a, b domain.CleaningRule) int {
+		if c := cmp.Compare(a.Priority, b.Priority); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return out
+}
+
+// ruleWatcher 一个规则变更订阅者的待投递队列
+type ruleWatcher struct {
+	mu    sync.Mutex
+	queue []ports.RuleChangeEvent
+	wake  chan struct{} // 容量 1: 有新事件时唤醒投递 goroutine
+}
+
+func (w *ruleWatcher) push(event ports.RuleChangeEvent) {
+	w.mu.Lock()
+	w.queue = append(w.queue, event)
+	w.mu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *ruleWatcher) drain() []ports.RuleChangeEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := w.queue
+	w.queue = nil
+	return out
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *CleaningRuleRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,417 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// standardKey 标准读数唯一键 (TenantID, DeviceID, Metric, Resolution, Timestamp)
+type standardKey struct {
+	tenantID   string
+	deviceID   string
+	metric     domain.Metric
+	resolution string
+	unixNano   int64
+}
+
+// defaultMaxIdempotencyKeys 未设置 WithMaxSize 时保留的已应用幂等键数上限
+const defaultMaxIdempotencyKeys = 100000
+
+// StandardReadingRepository 实现 ports.StandardReadingRepository
+// 按设备维护二级索引，查询只扫描该设备的条目；已应用的幂等键同样受 TTL 约束，
+// 并按 WithMaxSize (未设置时为 defaultMaxIdempotencyKeys) 淘汰最早记录的键。
+// 租户隔离: 写入时未设置租户的读数使用 ctx 的租户 (domain.WithTenant)，查询只返回 ctx 租户的读数。
+type StandardReadingRepository struct {
+	mu       sync.Mutex
+	readings *store[standardKey, domain.StandardReading]
+	byDevice map[string]map[standardKey]struct{} // 键为 domain.TenantScopedID(租户, 设备ID)
+	batches  *store[string, struct{}]
+	history  map[standardKey][]domain.StandardReading // 被替换的历史版本 (WithVersioning)，按版本升序
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.StandardReadingRepository  = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingRetention   = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingPager       = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingMultiFinder = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingAggregator  = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingWithdrawer  = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingVersioner   = (*StandardReadingRepository)(nil)
+	_ ports.HealthChecker              = (*StandardReadingRepository)(nil)
+)
+
+// NewStandardReadingRepository 创建内存标准读数仓储 (容量上限按标准读数条数计)
+func NewStandardReadingRepository(opts ...Option) *StandardReadingRepository {
+	cfg := newConfig(opts)
+	maxKeys := cfg.maxSize
+	if maxKeys <= 0 {
+		maxKeys = defaultMaxIdempotencyKeys
+	}
+	r := &StandardReadingRepository{
+		readings: newStore[standardKey, domain.StandardReading](cfg),
+		byDevice: make(map[string]map[standardKey]struct{}),
+		batches:  newStore[string, struct{}](config{ttl: cfg.ttl, maxSize: maxKeys, now: cfg.now}),
+		history:  make(map[standardKey][]domain.StandardReading),
+	}
+	r.readings.onEvict = func(k standardKey, _ domain.StandardReading) {
+		delete(r.history, k)
+		device := domain.TenantScopedID(k.tenantID, k.deviceID)
+		keys := r.byDevice[device]
+		delete(keys, k)
+		if len(keys) == 0 {
+			delete(r.byDevice, device)
+		}
+	}
+	return r
+}
+
+// Save 保存单个标准读数
+func (r *StandardReadingRepository) Save(ctx context.Context, reading domain.StandardReading, strategy ports.UpsertStrategy) error {
+	if err := validateStrategy(strategy); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.upsert(withTenant(ctx, reading), strategy); ok {
+		return r.publish([]domain.StandardReading{stored})
+	}
+	return nil
+}
+
+// SaveBatch 批量保存，已应用的幂等键直接返回
+// 幂等键在整批写入并发布到发件箱后才记录，中途失败的批次可用同一幂等键重试
+func (r *StandardReadingRepository) SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, idempotencyKey string) error {
+	if err := validateStrategy(strategy); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if idempotencyKey != "" {
+		if _, ok := r.batches.get(idempotencyKey); ok {
+			return nil
+		}
+	}
+	var applied []domain.StandardReading
+	for _, sr := range readings {
+		if stored, ok := r.upsert(withTenant(ctx, sr), strategy); ok {
+			applied = append(applied, stored)
+		}
+	}
+	if err := r.publish(applied); err != nil {
+		return err
+	}
+	if idempotencyKey != "" {
+		r.batches.put(idempotencyKey, struct{}{})
+	}
+	return nil
+}
+
+// withTenant 为未设置租户的读数补全 ctx 的租户
+func withTenant(ctx context.Context, sr domain.StandardReading) domain.StandardReading {
+	if sr.TenantID == "" {
+		sr.TenantID = domain.TenantFromContext(ctx)
+	}
+	return sr
+}
+
+// scoped 返回 ctx 租户下设备的索引键
+func scoped(ctx context.Context, deviceID string) string {
+	return domain.TenantScopedID(domain.TenantFromContext(ctx), deviceID)
+}
+
+// publish 将生效的读数写入发件箱 (未配置 WithOutbox 时为空操作)
+func (r *StandardReadingRepository) publish(applied []domain.StandardReading) error {
+	cfg := r.readings.cfg
+	if cfg.outbox == nil || len(applied) == 0 {
+		return nil
+	}
+	msgs, err := ports.StandardOutboxMessages(cfg.outboxTopic, applied)
+	if err != nil {
+		return err
+	}
+	cfg.outbox.append(msgs, cfg.now())
+	return nil
+}
+
+// upsert 按策略写入: HIGH_PRIORITY_WINS 只在新数据优先级 >= 已有数据时覆盖，返回写入的读数与是否写入
+func (r *StandardReadingRepository) upsert(sr domain.StandardReading, strategy ports.UpsertStrategy) (domain.StandardReading, bool) {
+	key := standardKey{sr.TenantID, sr.DeviceID, sr.Metric, sr.Resolution, sr.Timestamp.UnixNano()}
+	old, exists := r.readings.get(key)
+	if exists && strategy == ports.UpsertStrategyHighPriorityWins && sr.Priority < old.Priority {
+		return sr, false
+	}
+	if sr.IngestedAt.IsZero() {
+		sr.IngestedAt = r.readings.cfg.now()
+	}
+	if exists && r.readings.cfg.versioning && contentChanged(old, sr) {
+		r.history[key] = append(r.history[key], old)
+	}
+	r.readings.put(key, sr)
+	device := domain.TenantScopedID(sr.TenantID, sr.DeviceID)
+	keys, ok := r.byDevice[device]
+	if !ok {
+		keys = make(map[standardKey]struct{})
+		r.byDevice[device] = keys
+	}
+	keys[key] = struct{}{}
+	return sr, true
+}
+
+// contentChanged 判断覆盖是否改变了读数内容 (数值、精度、质量或优先级)，重放相同数据不产生新版本
+func contentChanged(old, sr domain.StandardReading) bool {
+	return old.ValueScaled != sr.ValueScaled || old.ScaleFactor != sr.ScaleFactor ||
+		old.Quality != sr.Quality || old.Priority != sr.Priority
+}
+
+// FindVersions 返回设备在 timestamp 时间点的全部版本，按 (Metric, Resolution, Version) 升序
+func (r *StandardReadingRepository) FindVersions(ctx context.Context, deviceID string, timestamp time.Time) ([]domain.StandardReading, error) {
+	current := r.find(scoped(ctx, deviceID), func(k standardKey) bool {
+		return k.unixNano == timestamp.UnixNano()
+	})
+	slices.SortStableFunc(current, func(a, b domain.StandardReading) int {
+		return cmp.Or(cmp.Compare(a.Metric, b.Metric), cmp.Compare(a.Resolution, b.Resolution))
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := make([]domain.StandardReading, 0, len(current))
+	for _, sr := range current {
+		past := r.history[standardKey{sr.TenantID, sr.DeviceID, sr.Metric, sr.Resolution, sr.Timestamp.UnixNano()}]
+		for i, old := range past {
+			old.Version = i + 1
+			versions = append(versions, old)
+		}
+		sr.Version = len(past) + 1
+		versions = append(versions, sr)
+	}
+	return versions, nil
+}
+
+// FindExact 获取设备默认通道在 timestamp 时间点的标准读数 (多个分辨率时按分辨率标签取第一条)
+func (r *StandardReadingRepository) FindExact(ctx context.Context, deviceID string, timestamp time.Time) (*domain.StandardReading, error) {
+	found := r.find(scoped(ctx, deviceID), func(k standardKey) bool {
+		return k.metric == "" && k.unixNano == timestamp.UnixNano()
+	})
+	if len(found) == 0 {
+		return nil, ports.ErrNotFound
+	}
+	return &found[0], nil
+}
+
+// FindRange 获取设备在 [start, end] 内的标准读数 (全部通道与分辨率)，按时间排序
+func (r *StandardReadingRepository) FindRange(ctx context.Context, deviceID string, start, end time.Time) ([]domain.StandardReading, error) {
+	from, to := start.UnixNano(), end.UnixNano()
+	return r.find(scoped(ctx, deviceID), func(k standardKey) bool {
+		return k.unixNano >= from && k.unixNano <= to
+	}), nil
+}
+
+// FindRangePaged 按 (Timestamp, Metric, Resolution) 升序分页返回设备在 [start, end] 内的标准读数
+func (r *StandardReadingRepository) FindRangePaged(ctx context.Context, deviceID string, start, end time.Time, page ports.PageRequest) (ports.StandardPage, error) {
+	var after *ports.RangeCursor
+	if page.Cursor != "" {
+		c, err := ports.DecodeCursor(page.Cursor)
+		if err != nil {
+			return ports.StandardPage{}, err
+		}
+		after = &c
+	}
+	all, _ := r.FindRange(ctx, deviceID, start, end)
+	if after != nil {
+		i := 0
+		for i < len(all) && !after.Before(all[i]) {
+			i++
+		}
+		all = all[i:]
+	}
+	limit := page.PageSize()
+	return ports.PageOf(all[:min(len(all), limit+1)], limit), nil
+}
+
+// FindRangeMulti 获取多个设备在 [start, end] 内的标准读数，按设备分组
+func (r *StandardReadingRepository) FindRangeMulti(ctx context.Context, deviceIDs []string, start, end time.Time) (map[string][]domain.StandardReading, error) {
+	out := make(map[string][]domain.StandardReading, len(deviceIDs))
+	for _, id := range deviceIDs {
+		if _, done := out[id]; done {
+			continue
+		}
+		if found, _ := r.FindRange(ctx, id, start, end); len(found) > 0 {
+			out[id] = found
+		}
+	}
+	return out, nil
+}
+
+// SumByPeriod 按周期求和
+func (r *StandardReadingRepository) SumByPeriod(ctx context.Context, q ports.AggregateQuery) ([]ports.AggregateBucket, error) {
+	return r.aggregate(ctx, q, false)
+}
+
+// AvgByPeriod 按周期求平均值
+func (r *StandardReadingRepository) AvgByPeriod(ctx context.Context, q ports.AggregateQuery) ([]ports.AggregateBucket, error) {
+	return r.aggregate(ctx, q, true)
+}
+
+func (r *StandardReadingRepository) aggregate(ctx context.Context, q ports.AggregateQuery, avg bool) ([]ports.AggregateBucket, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	from, to := q.Start.UnixNano(), q.End.UnixNano()
+	found := r.find(scoped(ctx, q.DeviceID), func(k standardKey) bool {
+		return k.metric == q.Metric && k.resolution == q.Resolution && k.unixNano >= from && k.unixNano < to
+	})
+	partials := make([]ports.AggregateBucket, 0, len(found))
+	for _, sr := range found {
+		if sr.Quality != domain.QualityMissing && sr.Quality != domain.QualityWithdrawn {
+			partials = append(partials, ports.AggregateBucket{Start: sr.Timestamp, Value: sr.ValueDisplay, Count: 1})
+		}
+	}
+	return ports.MergeBuckets(q, partials, avg), nil
+}
+
+// Withdraw 将满足条件的标准读数标记为已撤回，返回本次新撤回的读数
+func (r *StandardReadingRepository) Withdraw(ctx context.Context, req ports.WithdrawRequest) ([]domain.StandardReading, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readings.expire()
+
+	at := req.At
+	if at.IsZero() {
+		at = r.readings.cfg.now()
+	}
+	from, to := req.Start.UnixNano(), req.End.UnixNano()
+	var out []domain.StandardReading
+	for k := range r.byDevice[scoped(ctx, req.DeviceID)] {
+		if k.metric != req.Metric || (req.Resolution != "" && k.resolution != req.Resolution) || k.unixNano < from || k.unixNano > to {
+			continue
+		}
+		sr, ok := r.readings.get(k)
+		if !ok || sr.Withdrawal != nil {
+			continue
+		}
+		sr.Withdraw(req.Reason, req.Operator, at)
+		r.readings.put(k, sr)
+		out = append(out, sr)
+	}
+	sortStandards(out)
+	return out, nil
+}
+
+// FindLatest 获取设备最新的一条标准读数
+func (r *StandardReadingRepository) FindLatest(ctx context.Context, deviceID string) (*domain.StandardReading, error) {
+	found := r.find(scoped(ctx, deviceID), func(standardKey) bool { return true })
+	if len(found) == 0 {
+		return nil, ports.ErrNotFound
+	}
+	// find 按 (Timestamp, Metric, Resolution) 升序: 取最新时间点的第一条
+	i := len(found) - 1
+	for i > 0 && found[i-1].Timestamp.Equal(found[i].Timestamp) {
+		i--
+	}
+	return &found[i], nil
+}
+
+// FindLastBefore 获取指定通道、分辨率下时间早于 before 的最近一条标准读数
+func (r *StandardReadingRepository) FindLastBefore(ctx context.Context, deviceID string, metric domain.Metric, resolution string, before time.Time) (*domain.StandardReading, error) {
+	found := r.find(scoped(ctx, deviceID), func(k standardKey) bool {
+		return k.metric == metric && k.resolution == resolution && k.unixNano < before.UnixNano()
+	})
+	if len(found) == 0 {
+		return nil, ports.ErrNotFound
+	}
+	return &found[len(found)-1], nil
+}
+
+// DeleteOlderThan 删除时间早于 cutoff 的标准读数 (deviceID / resolution 为空表示全部)
+// deviceID 为空时: ctx 设置了租户则只删除该租户的读数，否则删除全部租户的读数
+func (r *StandardReadingRepository) DeleteOlderThan(ctx context.Context, deviceID string, resolution string, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readings.expire()
+
+	tenantID := domain.TenantFromContext(ctx)
+	var victims []standardKey
+	for id, keys := range r.byDevice {
+		if deviceID != "" && id != domain.TenantScopedID(tenantID, deviceID) {
+			continue
+		}
+		for k := range keys {
+			if deviceID == "" && tenantID != "" && k.tenantID != tenantID {
+				continue
+			}
+			if (resolution == "" || k.resolution == resolution) && k.unixNano < cutoff.UnixNano() {
+				victims = append(victims, k)
+			}
+		}
+	}
+	for _, k := range victims {
+		r.readings.delete(k)
+	}
+	return int64(len(victims)), nil
+}
+
+// Len 返回当前保存的标准读数条数 (不含已过期条目)
+func (r *StandardReadingRepository) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.readings.len()
+}
+
+// find 返回设备 (租户内索引键) 下满足条件的标准读数，按 (Timestamp, Metric, Resolution) 排序
+func (r *StandardReadingRepository) find(device string, match func(standardKey) bool) []domain.StandardReading {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readings.expire()
+
+	var out []domain.StandardReading
+	for k := range r.byDevice[device] {
+		if !match(k) {
+			continue
+		}
+		if sr, ok := r.readings.get(k); ok {
+			out = append(out, sr)
+		}
+	}
+	sortStandards(out)
+	return out
+}
+
+// sortStandards 按 (Timestamp, Metric, Resolution) 升序排序
+func sortStandards(readings []domain.StandardReading) {
+	slices.SortFunc(readings, func(a, b domain.StandardReading) int {
+		if c := a.Timestamp.Compare(b.Timestamp); c != 0 {
+			return c
+		}
+		if c := strings.Compare(string(a.Metric), string(b.Metric)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Resolution, b.Resolution)
+	})
+}
+
+// validateStrategy 拒绝未知策略，与数据库适配器的行为保持一致
+func validateStrategy(strategy ports.UpsertStrategy) error {
+	switch strategy {
+	case ports.UpsertStrategyHighPriorityWins, ports.UpsertStrategyLastWriteWins:
+		return nil
+	default:
+		return fmt.Errorf("unsupported upsert strategy %q", strategy)
+	}
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *StandardReadingRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,158 @@
+// Package memory 提供全部仓储端口的内存实现
+// 用于单元测试 (替代手写的 fake) 与离线边缘部署的本地缓存；可选 TTL 过期与容量上限。
+// 所有实现均为并发安全。
+package memory
+
+import (
+	"container/list"
+	"time"
+)
+
+// Option 配置内存仓储
+type Option func(*config)
+
+type config struct {
+	ttl         time.Duration
+	maxSize     int
+	now         func() time.Time
+	outbox      *Outbox
+	outboxTopic string
+	versioning  bool
+}
+
+// WithTTL 设置条目存活时间 (自最近一次写入起算)，过期条目在读写时惰性清除；<= 0 表示永不过期
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// WithMaxSize 设置最大条目数，超出时淘汰最早写入的条目；<= 0 表示不限制
+func WithMaxSize(n int) Option {
+	return func(c *config) {
+		c.maxSize = n
+	}
+}
+
+// WithOutbox 让 StandardReadingRepository 在写入生效的标准读数时向 outbox 追加消息 (topic 为空使用 ports.DefaultOutboxTopic)
+// 仅对标准读数仓储生效
+func WithOutbox(outbox *Outbox, topic string) Option {
+	return func(c *config) {
+		c.outbox, c.outboxTopic = outbox, topic
+	}
+}
+
+// WithVersioning 让 StandardReadingRepository 在覆盖内容有变化的读数前保留被替换的版本 (见 ports.StandardReadingVersioner)
+// 仅对标准读数仓储生效；历史版本随当前读数一同过期、淘汰或删除
+func WithVersioning() Option {
+	return func(c *config) {
+		c.versioning = true
+	}
+}
+
+// WithClock 设置时钟 (测试中验证 TTL 时使用)
+func WithClock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{now: time.Now}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// store 带 TTL 与容量上限的键值存储 (非并发安全，由各仓储加锁)
+// 条目按写入顺序保存在链表中: 表头最早写入，既是最先过期也是最先被容量淘汰的条目
+type store[K comparable, V any] struct {
+	cfg     config
+	items   map[K]*list.Element
+	order   *list.List
+	onEvict func(K, V) // 可选: 条目被删除 (过期、淘汰或显式删除) 时回调，用于维护二级索引
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	written time.Time
+}
+
+func newStore[K comparable, V any](cfg config) *store[K, V] {
+	return &store[K, V]{cfg: cfg, items: make(map[K]*list.Element), order: list.New()}
+}
+
+// get 返回未过期的条目
+func (s *store[K, V]) get(key K) (V, bool) {
+	s.expire()
+	el, ok := s.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return el.Value.(*entry[K, V]).value, true
+}
+
+// put 写入条目并刷新其写入时间，必要时淘汰最早写入的条目
+func (s *store[K, V]) put(key K, value V) {
+	s.expire()
+	now := s.cfg.now()
+	if el, ok := s.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value, e.written = value, now
+		s.order.MoveToBack(el)
+		return
+	}
+	s.items[key] = s.order.PushBack(&entry[K, V]{key: key, value: value, written: now})
+	for s.cfg.maxSize > 0 && s.order.Len() > s.cfg.maxSize {
+		s.remove(s.order.Front())
+	}
+}
+
+// delete 删除条目 (不存在时为空操作)
+func (s *store[K, V]) delete(key K) {
+	if el, ok := s.items[key]; ok {
+		s.remove(el)
+	}
+}
+
+// each 按写入顺序遍历未过期的条目，fn 返回 false 时停止
+func (s *store[K, V]) each(fn func(K, V) bool) {
+	s.expire()
+	for el := s.order.Front(); el != nil; el = el.Next() {
+		e := el.Value.(*entry[K, V])
+		if !fn(e.key, e.value) {
+			return
+		}
+	}
+}
+
+// len 返回未过期的条目数
+func (s *store[K, V]) len() int {
+	s.expire()
+	return s.order.Len()
+}
+
+// expire 清除过期条目 (从表头开始，遇到第一个未过期条目即停止)
+func (s *store[K, V]) expire() {
+	if s.cfg.ttl <= 0 {
+		return
+	}
+	cutoff := s.cfg.now().Add(-s.cfg.ttl)
+	for el := s.order.Front(); el != nil; el = s.order.Front() {
+		if el.Value.(*entry[K, V]).written.After(cutoff) {
+			return
+		}
+		s.remove(el)
+	}
+}
+
+func (s *store[K, V]) remove(el *list.Element) {
+	e := s.order.Remove(el).(*entry[K, V])
+	delete(s.items, e.key)
+	if s.onEvict != nil {
+		s.onEvict(e.key, e.value)
+	}
+}
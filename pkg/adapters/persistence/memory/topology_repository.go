@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// TopologyRepository 实现 ports.TopologyRepository
+type TopologyRepository struct {
+	mu    sync.Mutex
+	nodes *store[string, domain.TopologyNode]
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.TopologyRepository = (*TopologyRepository)(nil)
+	_ ports.HealthChecker      = (*TopologyRepository)(nil)
+)
+
+// NewTopologyRepository 创建内存设备层级树仓储，可选用 nodes 预置节点
+func NewTopologyRepository(nodes []domain.TopologyNode, opts ...Option) *TopologyRepository {
+	r := &TopologyRepository{nodes: newStore[string, domain.TopologyNode](newConfig(opts))}
+	for _, n := range nodes {
+		r.nodes.put(n.ID, n)
+	}
+	return r
+}
+
+// Save 新增或更新节点
+func (r *TopologyRepository) Save(ctx context.Context, nodes ...domain.TopologyNode) error {
+	for _, n := range nodes {
+		if n.ID == "" {
+			return errors.New("topology node id is required")
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, n := range nodes {
+		r.nodes.put(n.ID, n)
+	}
+	return nil
+}
+
+// FindSubtree 按层级顺序返回以 rootID 为根的子树 (同一父节点下按 ID 排序)
+func (r *TopologyRepository) FindSubtree(ctx context.Context, rootID string) ([]domain.TopologyNode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	root, ok := r.nodes.get(rootID)
+	if !ok {
+		return nil, fmt.Errorf("topology node %s: %w", rootID, ports.ErrNotFound)
+	}
+	children := make(map[string][]domain.TopologyNode)
+	r.nodes.each(func(_ string, n domain.TopologyNode) bool {
+		if n.ParentID != "" {
+			children[n.ParentID] = append(children[n.ParentID], n)
+		}
+		return true
+	})
+
+	out := []domain.TopologyNode{root}
+	seen := map[string]bool{root.ID: true}
+	for i := 0; i < len(out); i++ {
+		kids := children[out[i].ID]
+		slices.SortFunc(kids, func(a, b domain.TopologyNode) int { return strings.Compare(a.ID, b.ID) })
+		for _, kid := range kids {
+			if !seen[kid.ID] { // 父子关系成环时只访问一次
+				seen[kid.ID] = true
+				out = append(out, kid)
+			}
+		}
+	}
+	return out, nil
+}
+
+// HealthCheck 内存存储始终可用，仅反映 ctx 是否已取消
+func (r *TopologyRepository) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
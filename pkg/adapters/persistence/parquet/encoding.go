@@ -0,0 +1,356 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// 本文件实现写出 Parquet 文件所需的最小子集 (只依赖标准库):
+// 单个 row group、每列一个 v1 数据页、PLAIN 编码、不压缩；可选列的定义级别使用 RLE 编码。
+// 元数据按 Thrift Compact Protocol 编码，字段编号见 parquet-format 的 parquet.thrift。
+
+// magic Parquet 文件首尾的魔数
+const magic = "PAR1"
+
+// kind 列的逻辑类型 (决定物理类型与 converted type)
+type kind int
+
+const (
+	kindInt32     kind = iota // INT32
+	kindInt64                 // INT64
+	kindDouble                // DOUBLE
+	kindString                // BYTE_ARRAY (UTF8)
+	kindJSON                  // BYTE_ARRAY (JSON)
+	kindTimestamp             // INT64 (TIMESTAMP_MICROS，UTC)
+)
+
+// Parquet 物理类型、converted type 与编码 (parquet.thrift 中的枚举值)
+const (
+	typeInt32     = 1
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+	convertedJSON            = 19
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+	codecNone    = 0
+)
+
+func (k kind) physical() int32 {
+	switch k {
+	case kindInt32:
+		return typeInt32
+	case kindInt64, kindTimestamp:
+		return typeInt64
+	case kindDouble:
+		return typeDouble
+	default:
+		return typeByteArray
+	}
+}
+
+// converted 返回 converted type (-1 表示无)
+func (k kind) converted() int32 {
+	switch k {
+	case kindString:
+		return convertedUTF8
+	case kindJSON:
+		return convertedJSON
+	case kindTimestamp:
+		return convertedTimestampMicros
+	default:
+		return -1
+	}
+}
+
+// column 一列的定义与数据: values 按行排列，可选列以 nil 表示 NULL
+// 值的 Go 类型与 kind 对应: int32 / int64 / float64 / string (JSON 同为 string) / int64 微秒
+type column struct {
+	name     string
+	kind     kind
+	optional bool
+	values   []any
+}
+
+// writeFile 把各列写成一个 Parquet 文件 (各列行数必须相同)
+func writeFile(w io.Writer, columns []column) error {
+	rows := 0
+	if len(columns) > 0 {
+		rows = len(columns[0].values)
+	}
+	var buf bytes.Buffer
+	buf.WriteString(magic)
+
+	chunks := make([]chunkMeta, len(columns))
+	for i, c := range columns {
+		if len(c.values) != rows {
+			return fmt.Errorf("parquet column %s has %d values, want %d", c.name, len(c.values), rows)
+		}
+		page, err := c.page()
+		if err != nil {
+			return err
+		}
+		header := pageHeader(len(c.values), c.optional, len(page))
+		offset := int64(buf.Len())
+		buf.Write(header)
+		buf.Write(page)
+		chunks[i] = chunkMeta{offset: offset, size: int64(len(header) + len(page))}
+	}
+
+	footer := fileMetaData(columns, rows, chunks)
+	buf.Write(footer)
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(footer)))
+	buf.WriteString(magic)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// chunkMeta 列块在文件中的位置
+type chunkMeta struct {
+	offset int64 // 数据页 (含页头) 的起始偏移
+	size   int64 // 页头与页数据的总字节数
+}
+
+// page 编码数据页内容: 可选列先写定义级别 (4 字节长度 + RLE)，再写非空值的 PLAIN 编码
+func (c column) page() ([]byte, error) {
+	var buf bytes.Buffer
+	if c.optional {
+		levels := definitionLevels(c.values)
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(levels)))
+		buf.Write(levels)
+	}
+	for _, v := range c.values {
+		if v == nil {
+			if !c.optional {
+				return nil, fmt.Errorf("parquet column %s is required but has a null value", c.name)
+			}
+			continue
+		}
+		if err := c.plain(&buf, v); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// plain 按 PLAIN 编码写入单个值
+func (c column) plain(buf *bytes.Buffer, v any) error {
+	switch c.kind {
+	case kindInt32:
+		if x, ok := v.(int32); ok {
+			return binary.Write(buf, binary.LittleEndian, x)
+		}
+	case kindInt64, kindTimestamp:
+		if x, ok := v.(int64); ok {
+			return binary.Write(buf, binary.LittleEndian, x)
+		}
+	case kindDouble:
+		if x, ok := v.(float64); ok {
+			return binary.Write(buf, binary.LittleEndian, math.Float64bits(x))
+		}
+	case kindString, kindJSON:
+		if x, ok := v.(string); ok {
+			_ = binary.Write(buf, binary.LittleEndian, uint32(len(x)))
+			buf.WriteString(x)
+			return nil
+		}
+	}
+	return fmt.Errorf("parquet column %s: unexpected value type %T", c.name, v)
+}
+
+// definitionLevels 以 RLE/bit-packing 混合编码中的 RLE 游程编码定义级别 (位宽 1: 非空为 1，NULL 为 0)
+func definitionLevels(values []any) []byte {
+	var out []byte
+	for i := 0; i < len(values); {
+		defined := values[i] != nil
+		j := i
+		for j < len(values) && (values[j] != nil) == defined {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1) // 最低位 0 表示 RLE 游程
+		if defined {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		i = j
+	}
+	return out
+}
+
+// pageHeader 编码 PageHeader (数据页，不压缩时压缩前后大小相同)
+func pageHeader(numValues int, optional bool, size int) []byte {
+	var e compactEncoder
+	e.i32(1, pageTypeData)
+	e.i32(2, int32(size))
+	e.i32(3, int32(size))
+	e.beginStruct(5) // DataPageHeader
+	e.i32(1, int32(numValues))
+	e.i32(2, encodingPlain)
+	e.i32(3, encodingRLE)
+	e.i32(4, encodingRLE)
+	e.endStruct()
+	e.stop()
+	return e.buf
+}
+
+// fileMetaData 编码文件尾的 FileMetaData
+func fileMetaData(columns []column, rows int, chunks []chunkMeta) []byte {
+	var e compactEncoder
+	e.i32(1, 1) // version
+
+	e.listHeader(2, compactStruct, len(columns)+1) // schema: 根节点 + 各列
+	e.beginElem()
+	e.binary(4, "schema")
+	e.i32(5, int32(len(columns)))
+	e.endStruct()
+	for _, c := range columns {
+		e.beginElem()
+		e.i32(1, c.kind.physical())
+		repetition := int32(repetitionRequired)
+		if c.optional {
+			repetition = repetitionOptional
+		}
+		e.i32(3, repetition)
+		e.binary(4, c.name)
+		if conv := c.kind.converted(); conv >= 0 {
+			e.i32(6, conv)
+		}
+		e.endStruct()
+	}
+
+	e.i64(3, int64(rows))
+
+	var total int64
+	for _, ch := range chunks {
+		total += ch.size
+	}
+	e.listHeader(4, compactStruct, 1) // row_groups
+	e.beginElem()
+	e.listHeader(1, compactStruct, len(columns)) // columns
+	for i, c := range columns {
+		e.beginElem() // ColumnChunk
+		e.i64(2, chunks[i].offset)
+		e.beginStruct(3) // ColumnMetaData
+		e.i32(1, c.kind.physical())
+		e.listHeader(2, compactI32, 2)
+		e.rawVarint(zigzag(encodingPlain))
+		e.rawVarint(zigzag(encodingRLE))
+		e.listHeader(3, compactBinary, 1)
+		e.rawBinary(c.name)
+		e.i32(4, codecNone)
+		e.i64(5, int64(len(c.values)))
+		e.i64(6, chunks[i].size)
+		e.i64(7, chunks[i].size)
+		e.i64(9, chunks[i].offset)
+		e.endStruct()
+		e.endStruct()
+	}
+	e.i64(2, total)
+	e.i64(3, int64(rows))
+	e.endStruct()
+
+	e.binary(6, "prism-core")
+	e.stop()
+	return e.buf
+}
+
+// Thrift Compact Protocol 的类型编号
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactEncoder Thrift Compact Protocol 编码器 (只支持本文件用到的类型)
+// 字段头使用与上一个字段编号的差值，进入嵌套结构时保存外层的编号
+type compactEncoder struct {
+	buf   []byte
+	last  int16
+	stack []int16
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (e *compactEncoder) rawVarint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *compactEncoder) rawBinary(s string) {
+	e.rawVarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *compactEncoder) field(id int16, typ byte) {
+	if delta := id - e.last; delta > 0 && delta <= 15 {
+		e.buf = append(e.buf, byte(delta)<<4|typ)
+	} else {
+		e.buf = append(e.buf, typ)
+		e.rawVarint(zigzag(int64(id)))
+	}
+	e.last = id
+}
+
+func (e *compactEncoder) i32(id int16, v int32) {
+	e.field(id, compactI32)
+	e.rawVarint(zigzag(int64(v)))
+}
+
+func (e *compactEncoder) i64(id int16, v int64) {
+	e.field(id, compactI64)
+	e.rawVarint(zigzag(v))
+}
+
+func (e *compactEncoder) binary(id int16, s string) {
+	e.field(id, compactBinary)
+	e.rawBinary(s)
+}
+
+func (e *compactEncoder) listHeader(id int16, elem byte, size int) {
+	e.field(id, compactList)
+	if size < 15 {
+		e.buf = append(e.buf, byte(size)<<4|elem)
+		return
+	}
+	e.buf = append(e.buf, 0xF0|elem)
+	e.rawVarint(uint64(size))
+}
+
+// beginStruct 开始一个结构体字段
+func (e *compactEncoder) beginStruct(id int16) {
+	e.field(id, compactStruct)
+	e.beginElem()
+}
+
+// beginElem 开始一个结构体 (列表元素或字段值)
+func (e *compactEncoder) beginElem() {
+	e.stack = append(e.stack, e.last)
+	e.last = 0
+}
+
+// endStruct 结束结构体并恢复外层的字段编号
+func (e *compactEncoder) endStruct() {
+	e.stop()
+	e.last = e.stack[len(e.stack)-1]
+	e.stack = e.stack[:len(e.stack)-1]
+}
+
+func (e *compactEncoder) stop() {
+	e.buf = append(e.buf, 0)
+}
@@ -0,0 +1,213 @@
+// Package parquet 把标准读数导出为按设备、日期分区的 Parquet 文件，供数据湖与下游分析团队直接读取
+// 文件布局采用 Hive 风格分区 (device_id=<设备>/date=<YYYY-MM-DD>/data.parquet)，Spark / DuckDB / Athena 可直接按分区裁剪。
+// 本包只依赖标准库: 写出未压缩、PLAIN 编码的单 row group 文件，写入目标由 Sink 抽象 (本地目录或对象存储)。
+package parquet
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DefaultFileName 每个分区的文件名
+const DefaultFileName = "data.parquet"
+
+// Option 配置 Exporter
+type Option func(*Exporter)
+
+// WithPrefix 设置所有分区路径的前缀 (如 "energy/standard_readings")
+func WithPrefix(prefix string) Option {
+	return func(e *Exporter) {
+		e.prefix = prefix
+	}
+}
+
+// WithLocation 设置按日分区所用的时区 (默认 UTC)，日期按当地日历切分
+func WithLocation(loc *time.Location) Option {
+	return func(e *Exporter) {
+		if loc != nil {
+			e.loc = loc
+		}
+	}
+}
+
+// WithResolutions 只导出指定分辨率的标准读数 (默认导出全部分辨率)
+func WithResolutions(resolutions ...string) Option {
+	return func(e *Exporter) {
+		e.resolutions = resolutions
+	}
+}
+
+// Exporter 标准读数的 Parquet 导出器
+// 每个 (设备, 日期) 分区写成一个完整的文件: 重复导出同一天会以仓储中的最新数据覆盖整个分区，
+// 因此迟到或重新校准的数据只需重新导出受影响的日期，下游不会读到重复行。
+type Exporter struct {
+	sink        Sink
+	prefix      string
+	loc         *time.Location
+	resolutions []string
+}
+
+// NewExporter 创建导出器
+func NewExporter(sink Sink, opts ...Option) *Exporter {
+	e := &Exporter{sink: sink, loc: time.UTC}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// ExportResult 一次导出的结果
+type ExportResult struct {
+	Files []string // 写入的分区文件 (Sink 中的 key)，按写入顺序
+	Rows  int      // 写入的总行数
+}
+
+// Export 导出各设备在 [start, end) 内涉及的全部日期 (按分区时区向外取整到整天)
+// 没有数据的日期不写文件；单台设备失败不影响其他设备，所有错误聚合返回。
+func (e *Exporter) Export(ctx context.Context, repo ports.StandardReadingRepository, deviceIDs []string, start, end time.Time) (ExportResult, error) {
+	var result ExportResult
+	if !end.After(start) {
+		return result, nil
+	}
+	days := domain.NewTimeGrid(24*time.Hour, e.loc)
+	from, to := days.Floor(start), days.Ceil(end)
+
+	var errs []error
+	for _, id := range deviceIDs {
+		if err := ctx.Err(); err != nil {
+			return result, errors.Join(append(errs, err)...)
+		}
+		readings, err := repo.FindRange(ctx, id, from, to.Add(-time.Nanosecond)) // FindRange 为闭区间
+		if err != nil {
+			errs = append(errs, fmt.Errorf("export %s: %w", id, err))
+			continue
+		}
+		if err := e.write(ctx, id, readings, &result); err != nil {
+			errs = append(errs, fmt.Errorf("export %s: %w", id, err))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// Write 把给定的读数按 (设备, 日期) 分区写出 (每个分区覆盖为给定的读数)
+// 调用方需保证每个分区传入的是该日期的完整数据，否则会覆盖掉分区中已有的其他读数；通常使用 Export
+func (e *Exporter) Write(ctx context.Context, readings []domain.StandardReading) (ExportResult, error) {
+	var result ExportResult
+	byDevice := make(map[string][]domain.StandardReading)
+	for _, sr := range readings {
+		byDevice[sr.DeviceID] = append(byDevice[sr.DeviceID], sr)
+	}
+	for _, id := range slices.Sorted(maps.Keys(byDevice)) {
+		if err := e.write(ctx, id, byDevice[id], &result); err != nil {
+			return result, fmt.Errorf("export %s: %w", id, err)
+		}
+	}
+	return result, nil
+}
+
+// write 按日期分区写出单台设备的读数
+func (e *Exporter) write(ctx context.Context, deviceID string, readings []domain.StandardReading, result *ExportResult) error {
+	byDay := make(map[string][]domain.StandardReading)
+	for _, sr := range readings {
+		if len(e.resolutions) > 0 && !slices.Contains(e.resolutions, sr.Resolution) {
+			continue
+		}
+		day := sr.Timestamp.In(e.loc).Format(time.DateOnly)
+		byDay[day] = append(byDay[day], sr)
+	}
+	for _, day := range slices.Sorted(maps.Keys(byDay)) {
+		rows := byDay[day]
+		slices.SortStableFunc(rows, func(a, b domain.StandardReading) int {
+			return cmp.Or(a.Timestamp.Compare(b.Timestamp), cmp.Compare(a.Metric, b.Metric), cmp.Compare(a.Resolution, b.Resolution))
+		})
+		var buf bytes.Buffer
+		if err := EncodeStandardReadings(&buf, rows); err != nil {
+			return err
+		}
+		key := e.PartitionKey(deviceID, day)
+		if err := e.sink.Put(ctx, key, &buf); err != nil {
+			return err
+		}
+		result.Files = append(result.Files, key)
+		result.Rows += len(rows)
+	}
+	return nil
+}
+
+// PartitionKey 返回 (设备, 日期) 分区文件的 key；day 格式为 YYYY-MM-DD，设备ID按 URL 路径规则转义
+func (e *Exporter) PartitionKey(deviceID, day string) string {
+	key := "device_id=" + url.PathEscape(deviceID) + "/date=" + day + "/" + DefaultFileName
+	if e.prefix != "" {
+		key = e.prefix + "/" + key
+	}
+	return key
+}
+
+// EncodeStandardReadings 把标准读数编码为一个 Parquet 文件写入 w
+// 列与数据库表一致: 时间列为 UTC 微秒时间戳，calibration / withdrawal 为可空的 JSON 字符串
+func EncodeStandardReadings(w io.Writer, readings []domain.StandardReading) error {
+	cols := []column{
+		{name: "device_id", kind: kindString},
+		{name: "metric", kind: kindString},
+		{name: "resolution", kind: kindString},
+		{name: "ts", kind: kindTimestamp},
+		{name: "value_scaled", kind: kindInt64},
+		{name: "scale_factor", kind: kindInt32},
+		{name: "value_display", kind: kindDouble},
+		{name: "quality", kind: kindString},
+		{name: "quality_reason", kind: kindString},
+		{name: "confidence", kind: kindDouble},
+		{name: "source_type", kind: kindString},
+		{name: "calibration", kind: kindJSON, optional: true},
+		{name: "ingested_at", kind: kindTimestamp},
+		{name: "priority", kind: kindInt32},
+		{name: "withdrawal", kind: kindJSON, optional: true},
+	}
+	for i := range cols {
+		cols[i].values = make([]any, 0, len(readings))
+	}
+	for _, sr := range readings {
+		calibration, err := jsonValue(sr.Calibration)
+		if err != nil {
+			return fmt.Errorf("encode calibration of %s: %w", sr.DeviceID, err)
+		}
+		withdrawal, err := jsonValue(sr.Withdrawal)
+		if err != nil {
+			return fmt.Errorf("encode withdrawal of %s: %w", sr.DeviceID, err)
+		}
+		row := []any{
+			sr.DeviceID, string(sr.Metric), sr.Resolution, sr.Timestamp.UnixMicro(),
+			sr.ValueScaled, int32(sr.ScaleFactor), sr.ValueDisplay,
+			string(sr.Quality), sr.QualityReason, sr.Confidence, string(sr.SourceType), calibration,
+			sr.IngestedAt.UnixMicro(), int32(sr.Priority), withdrawal,
+		}
+		for i, v := range row {
+			cols[i].values = append(cols[i].values, v)
+		}
+	}
+	return writeFile(w, cols)
+}
+
+// jsonValue 把可空的结构编码为 JSON 字符串，nil 指针返回 nil (写为 NULL)
+func jsonValue[T any](v *T) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
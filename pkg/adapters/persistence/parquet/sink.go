@@ -0,0 +1,58 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Sink 导出文件的写入目标 (本地目录、挂载的共享存储或对象存储)
+// 对象存储 (S3 / OSS / GCS) 由调用方基于各自的 SDK 实现: key 即对象名，Put 对应一次 PutObject
+type Sink interface {
+	// Put 写入 key (以 "/" 分隔的相对路径) 的完整内容，已存在时覆盖
+	Put(ctx context.Context, key string, body io.Reader) error
+}
+
+// DirSink 把文件写入本地目录 (key 映射为 root 下的相对路径)
+// 先写临时文件再重命名，读取方不会看到写了一半的文件
+type DirSink struct {
+	root string
+}
+
+// 编译期检查接口实现
+var _ Sink = (*DirSink)(nil)
+
+// NewDirSink 创建写入 root 目录的 Sink (目录不存在时在首次写入时创建)
+func NewDirSink(root string) *DirSink {
+	return &DirSink{root: root}
+}
+
+// Put 写入 root/key
+func (s *DirSink) Put(ctx context.Context, key string, body io.Reader) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory for %s: %w", key, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name()) // 重命名成功后为空操作
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// CopyFromFunc 将 rows 批量写入 table 的 columns 列 (在 tx 内执行)
+// COPY 协议没有统一的 database/sql 接口，不同驱动的写法不同，因此以函数注入:
+// 默认的 PQCopyFrom 适用于 lib/pq；pgx 等驱动可基于各自的 CopyFrom 实现该函数
+type CopyFromFunc func(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any) error
+
+// PQCopyFrom 使用 lib/pq 的 COPY FROM STDIN 约定: 预编译 COPY 语句，逐行 Exec，最后以无参数 Exec 结束
+func PQCopyFrom(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any) error {
+	stmt, err := tx.PrepareContext(ctx, copySQL(table, columns))
+	if err != nil {
+		return fmt.Errorf("prepare copy into %s: %w", table, err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return fmt.Errorf("copy row into %s: %w", table, err)
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("finish copy into %s: %w", table, err)
+	}
+	return nil
+}
+
+// InsertValues 以多行 INSERT 写入 (不支持 COPY 的驱动或测试环境使用)
+// 每条语句最多 InsertChunkRows(columns) 行，避免超出 PostgreSQL 单条语句 MaxBindParams 个参数的限制
+func InsertValues(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any) error {
+	chunkRows := InsertChunkRows(columns)
+	for start := 0; start < len(rows); start += chunkRows {
+		chunk := rows[start:min(start+chunkRows, len(rows))]
+		args := make([]any, 0, len(chunk)*len(columns))
+		var b strings.Builder
+		fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES ", table, strings.Join(columns, ", "))
+		for i, row := range chunk {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("(")
+			for j := range row {
+				if j > 0 {
+					b.WriteString(", ")
+				}
+				args = append(args, row[j])
+				fmt.Fprintf(&b, "$%d", len(args))
+			}
+			b.WriteString(")")
+		}
+		if _, err := tx.ExecContext(ctx, b.String(), args...); err != nil {
+			return fmt.Errorf("insert into %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// MaxBindParams PostgreSQL 单条语句的参数上限 (协议以 uint16 表示参数个数)
+const MaxBindParams = 65535
+
+// InsertChunkRows InsertValues 单条语句的最大行数: 按列数计算，保证 行数 x 列数 <= MaxBindParams
+func InsertChunkRows(columns []string) int {
+	return max(MaxBindParams/max(len(columns), 1), 1)
+}
+
+// copySQL 生成 COPY FROM STDIN 语句
+func copySQL(table string, columns []string) string {
+	return fmt.Sprintf("COPY %s (%s) FROM STDIN", table, strings.Join(columns, ", "))
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/internal/sqlmigrate"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrations 内嵌的迁移文件 (golang-migrate 格式，位于根目录)
+// 可直接交给 golang-migrate 的 iofs 源: iofs.New(postgres.Migrations, ".")
+var Migrations = mustSub(migrationFiles, "migrations")
+
+// versionTableSQL 与 golang-migrate postgres 驱动一致的版本表
+const versionTableSQL = `CREATE TABLE IF NOT EXISTS ` + sqlmigrate.Table + ` (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+
+// Migrate 执行全部未应用的迁移，创建默认表名 (DefaultTable、DefaultBatchTable、DefaultOutboxTable、DefaultVersionTable、DefaultRawTable、DefaultReportTable) 下的表与索引
+// 使用自定义表名或 TimescaleDB hypertable 时请改用 EnsureSchema；迁移不加锁，多实例部署时应只由一个实例执行。
+func Migrate(ctx context.Context, db *sql.DB) error {
+	return sqlmigrate.Up(ctx, db, Migrations, versionTableSQL)
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
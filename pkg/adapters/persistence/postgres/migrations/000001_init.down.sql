@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS standard_reading_outbox;
+DROP TABLE IF EXISTS standard_reading_batches;
+DROP TABLE IF EXISTS standard_readings;
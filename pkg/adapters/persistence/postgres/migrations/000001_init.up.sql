@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS standard_readings (
+	device_id      TEXT             NOT NULL,
+	metric         TEXT             NOT NULL DEFAULT '',
+	resolution     TEXT             NOT NULL,
+	ts             TIMESTAMPTZ      NOT NULL,
+	value_scaled   BIGINT           NOT NULL,
+	scale_factor   INTEGER          NOT NULL,
+	value_display  DOUBLE PRECISION NOT NULL,
+	quality        TEXT             NOT NULL,
+	quality_reason TEXT             NOT NULL DEFAULT '',
+	confidence     DOUBLE PRECISION NOT NULL DEFAULT 1,
+	source_type    TEXT             NOT NULL,
+	calibration    JSONB,
+	ingested_at    TIMESTAMPTZ      NOT NULL,
+	priority       INTEGER          NOT NULL DEFAULT 0,
+	withdrawal     JSONB,
+	PRIMARY KEY (device_id, metric, resolution, ts)
+);
+
+CREATE INDEX IF NOT EXISTS standard_readings_device_ts ON standard_readings (device_id, ts);
+
+CREATE TABLE IF NOT EXISTS standard_reading_batches (
+	idempotency_key TEXT        PRIMARY KEY,
+	applied_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS standard_reading_outbox (
+	id         BIGSERIAL   PRIMARY KEY,
+	topic      TEXT        NOT NULL,
+	msg_key    TEXT        NOT NULL,
+	payload    JSONB       NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS standard_reading_versions (
+	device_id      TEXT             NOT NULL,
+	metric         TEXT             NOT NULL DEFAULT '',
+	resolution     TEXT             NOT NULL,
+	ts             TIMESTAMPTZ      NOT NULL,
+	value_scaled   BIGINT           NOT NULL,
+	scale_factor   INTEGER          NOT NULL,
+	value_display  DOUBLE PRECISION NOT NULL,
+	quality        TEXT             NOT NULL,
+	quality_reason TEXT             NOT NULL DEFAULT '',
+	confidence     DOUBLE PRECISION NOT NULL DEFAULT 1,
+	source_type    TEXT             NOT NULL,
+	calibration    JSONB,
+	ingested_at    TIMESTAMPTZ      NOT NULL,
+	priority       INTEGER          NOT NULL DEFAULT 0,
+	withdrawal     JSONB,
+	version        INTEGER          NOT NULL,
+	superseded_at  TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (device_id, metric, resolution, ts, version)
+);
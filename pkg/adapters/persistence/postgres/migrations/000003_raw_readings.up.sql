@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS raw_readings (
+	device_id   TEXT             NOT NULL,
+	metric      TEXT             NOT NULL DEFAULT '',
+	ts          TIMESTAMPTZ      NOT NULL,
+	value       DOUBLE PRECISION,
+	body        JSONB            NOT NULL,
+	archived_at TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (device_id, metric, ts)
+);
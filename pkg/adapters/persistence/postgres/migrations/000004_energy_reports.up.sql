@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS energy_reports (
+	id           TEXT             PRIMARY KEY,
+	device_id    TEXT             NOT NULL,
+	metric       TEXT             NOT NULL DEFAULT '',
+	period       TEXT             NOT NULL,
+	start_time   TIMESTAMPTZ      NOT NULL,
+	end_time     TIMESTAMPTZ      NOT NULL,
+	device_model TEXT             NOT NULL DEFAULT '',
+	device_type  TEXT             NOT NULL DEFAULT '',
+	total_usage  DOUBLE PRECISION NOT NULL,
+	usage_scaled BIGINT           NOT NULL,
+	scale_factor INTEGER          NOT NULL,
+	co2e_kg      DOUBLE PRECISION,
+	co2e_scaled  BIGINT,
+	generated_at TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS energy_reports_device_period ON energy_reports (device_id, metric, period, start_time);
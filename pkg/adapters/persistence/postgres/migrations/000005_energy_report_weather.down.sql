@@ -0,0 +1 @@
+ALTER TABLE energy_reports DROP COLUMN IF EXISTS weather;
@@ -0,0 +1 @@
+ALTER TABLE energy_reports ADD COLUMN IF NOT EXISTS weather JSONB;
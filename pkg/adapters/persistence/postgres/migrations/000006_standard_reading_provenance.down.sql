@@ -0,0 +1,2 @@
+ALTER TABLE standard_reading_versions DROP COLUMN IF EXISTS provenance;
+ALTER TABLE standard_readings DROP COLUMN IF EXISTS provenance;
@@ -0,0 +1,2 @@
+ALTER TABLE standard_readings ADD COLUMN IF NOT EXISTS provenance JSONB;
+ALTER TABLE standard_reading_versions ADD COLUMN IF NOT EXISTS provenance JSONB;
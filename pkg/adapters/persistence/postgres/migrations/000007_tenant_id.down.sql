@@ -0,0 +1,14 @@
+-- 不同租户存在同名设备的同一时间点时，恢复旧主键会失败，需先人工清理
+ALTER TABLE raw_readings DROP CONSTRAINT IF EXISTS raw_readings_pkey,
+	ADD PRIMARY KEY (device_id, metric, ts);
+ALTER TABLE raw_readings DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE standard_reading_versions DROP CONSTRAINT IF EXISTS standard_reading_versions_pkey,
+	ADD PRIMARY KEY (device_id, metric, resolution, ts, version);
+ALTER TABLE standard_reading_versions DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS standard_readings_tenant_device_ts;
+ALTER TABLE standard_readings DROP CONSTRAINT IF EXISTS standard_readings_pkey,
+	ADD PRIMARY KEY (device_id, metric, resolution, ts);
+ALTER TABLE standard_readings DROP COLUMN IF EXISTS tenant_id;
+CREATE INDEX IF NOT EXISTS standard_readings_device_ts ON standard_readings (device_id, ts);
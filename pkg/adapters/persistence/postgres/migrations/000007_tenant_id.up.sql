@@ -0,0 +1,13 @@
+ALTER TABLE standard_readings ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE standard_readings DROP CONSTRAINT IF EXISTS standard_readings_pkey,
+	ADD PRIMARY KEY (tenant_id, device_id, metric, resolution, ts);
+DROP INDEX IF EXISTS standard_readings_device_ts;
+CREATE INDEX IF NOT EXISTS standard_readings_tenant_device_ts ON standard_readings (tenant_id, device_id, ts);
+
+ALTER TABLE standard_reading_versions ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE standard_reading_versions DROP CONSTRAINT IF EXISTS standard_reading_versions_pkey,
+	ADD PRIMARY KEY (tenant_id, device_id, metric, resolution, ts, version);
+
+ALTER TABLE raw_readings ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE raw_readings DROP CONSTRAINT IF EXISTS raw_readings_pkey,
+	ADD PRIMARY KEY (tenant_id, device_id, metric, ts);
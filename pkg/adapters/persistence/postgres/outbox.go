@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// Outbox 实现 ports.Outbox，读取由 WithOutbox 写入的发件箱表
+type Outbox struct {
+	db    *sql.DB
+	table string
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.Outbox        = (*Outbox)(nil)
+	_ ports.HealthChecker = (*Outbox)(nil)
+)
+
+// NewOutbox 创建发件箱 (table 为空使用 DefaultOutboxTable，需与 WithOutbox 一致)
+func NewOutbox(db *sql.DB, table string) *Outbox {
+	if table == "" {
+		table = DefaultOutboxTable
+	}
+	return &Outbox{db: db, table: quoteIdent(table)}
+}
+
+// EnsureSchema 创建发件箱表 (已存在时跳过)
+func (o *Outbox) EnsureSchema(ctx context.Context) error {
+	if _, err := o.db.ExecContext(ctx, outboxTableSQL(o.table)); err != nil {
+		return fmt.Errorf("create outbox table failed: %w", err)
+	}
+	return nil
+}
+
+// HealthCheck 读取发件箱表验证存储可用
+func (o *Outbox) HealthCheck(ctx context.Context) error {
+	return checkTables(ctx, o.db, o.table)
+}
+
+// Pending 按 ID 升序返回至多 limit 条未发布的消息
+func (o *Outbox) Pending(ctx context.Context, limit int) ([]ports.OutboxMessage, error) {
+	query := fmt.Sprintf("SELECT id, topic, msg_key, payload, created_at FROM %s ORDER BY id", o.table)
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	rows, err := o.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ports.OutboxMessage
+	for rows.Next() {
+		var m ports.OutboxMessage
+		if err := rows.Scan(&m.ID, &m.Topic, &m.Key, &m.Payload, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan outbox message: %w", err)
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query outbox: %w", err)
+	}
+	return out, nil
+}
+
+// MarkPublished 删除已发布的消息
+func (o *Outbox) MarkPublished(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	if _, err := o.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", o.table, strings.Join(placeholders, ", ")), args...); err != nil {
+		return fmt.Errorf("delete published outbox messages: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DefaultRawTable 默认的原始读数归档表名
+const DefaultRawTable = "raw_readings"
+
+// RawReadingRepository 实现 ports.RawReadingRepository，归档清洗前的原始读数供重处理使用
+// 读数整体以 JSONB 保存在 body 列；数值另存于可空的 value 列 (缺失值 NaN 无法以 JSON 表示，存为 NULL)
+type RawReadingRepository struct {
+	db    *sql.DB
+	table string
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.RawReadingRepository = (*RawReadingRepository)(nil)
+	_ ports.HealthChecker        = (*RawReadingRepository)(nil)
+)
+
+// NewRawReadingRepository 创建原始读数仓储 (table 为空使用 DefaultRawTable)
+func NewRawReadingRepository(db *sql.DB, table string) *RawReadingRepository {
+	if table == "" {
+		table = DefaultRawTable
+	}
+	return &RawReadingRepository{db: db, table: quoteIdent(table)}
+}
+
+// EnsureSchema 创建原始读数表 (已存在时跳过)
+func (r *RawReadingRepository) EnsureSchema(ctx context.Context) error {
+	for _, stmt := range rawTableSQL(r.table) {
+		if _, err := r.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create raw reading table failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// HealthCheck 读取原始读数表验证存储可用
+func (r *RawReadingRepository) HealthCheck(ctx context.Context) error {
+	return checkTables(ctx, r.db, r.table)
+}
+
+// Save 在一个事务内逐条 upsert (同一租户同一计量通道同一时间戳以最后写入的为准)
+// 未设置租户的读数使用 ctx 的租户 (domain.WithTenant)
+func (r *RawReadingRepository) Save(ctx context.Context, readings []domain.Reading) error {
+	if len(readings) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // 提交后 Rollback 为空操作
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(`INSERT INTO %s (tenant_id, device_id, metric, ts, value, body, archived_at)
+VALUES ($1, $2, $3, $4, $5, $6, NOW())
+ON CONFLICT (tenant_id, device_id, metric, ts) DO UPDATE SET value = EXCLUDED.value, body = EXCLUDED.body, archived_at = EXCLUDED.archived_at`, r.table))
+	if err != nil {
+		return fmt.Errorf("prepare raw reading upsert: %w", err)
+	}
+	defer stmt.Close()
+	tenantID := domain.TenantFromContext(ctx)
+	for _, rd := range readings {
+		if rd.DeviceInfo.TenantID == "" {
+			rd.DeviceInfo.TenantID = tenantID
+		}
+		value, body, err := encodeRaw(rd)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, rd.DeviceInfo.TenantID, rd.DeviceInfo.ID, string(rd.Metric), rd.Timestamp.UTC(), value, body); err != nil {
+			return fmt.Errorf("save raw reading %s@%s: %w", rd.DeviceInfo.ID, rd.Timestamp.Format(time.RFC3339), err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit raw readings: %w", err)
+	}
+	return nil
+}
+
+// FindRange 获取 ctx 租户下设备在 [start, end] 时间范围内的原始读数 (全部通道)，按时间排序
+func (r *RawReadingRepository) FindRange(ctx context.Context, deviceID string, start, end time.Time) ([]domain.Reading, error) {
+	rows, err := r.db.QueryContext(ctx,
+		fmt.Sprintf("SELECT value, body FROM %s WHERE tenant_id = $1 AND device_id = $2 AND ts >= $3 AND ts <= $4 ORDER BY ts, metric", r.table),
+		domain.TenantFromContext(ctx), deviceID, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query raw readings: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Reading
+	for rows.Next() {
+		var (
+			value sql.NullFloat64
+			body  []byte
+		)
+		if err := rows.Scan(&value, &body); err != nil {
+			return nil, fmt.Errorf("scan raw reading: %w", err)
+		}
+		var rd domain.Reading
+		if err := json.Unmarshal(body, &rd); err != nil {
+			return nil, fmt.Errorf("decode raw reading: %w", err)
+		}
+		rd.Value = math.NaN()
+		if value.Valid {
+			rd.Value = value.Float64
+		}
+		out = append(out, rd)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query raw readings: %w", err)
+	}
+	return out, nil
+}
+
+// encodeRaw 拆分原始读数: 数值 (NaN / ±Inf 为 NULL) 与其余字段的 JSON
+func encodeRaw(rd domain.Reading) (any, string, error) {
+	var value any // NULL 表示缺失值
+	if !math.IsNaN(rd.Value) && !math.IsInf(rd.Value, 0) {
+		value = rd.Value
+	}
+	rd.Value = 0
+	body, err := json.Marshal(rd)
+	if err != nil {
+		return nil, "", fmt.Errorf("encode raw reading of %s: %w", rd.DeviceInfo.ID, err)
+	}
+	return value, string(body), nil
+}
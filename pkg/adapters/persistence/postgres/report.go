@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DefaultReportTable 默认的能耗报表表名
+const DefaultReportTable = "energy_reports"
+
+// EnergyReportRepository 实现 ports.EnergyReportRepository，保存已生成的能耗报表供看板复用
+// 报表按 ID 覆盖；碳排放为空时 co2e 列为 NULL，气候修正以 JSONB 保存在 weather 列
+type EnergyReportRepository struct {
+	db    *sql.DB
+	table string
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.EnergyReportRepository = (*EnergyReportRepository)(nil)
+	_ ports.HealthChecker          = (*EnergyReportRepository)(nil)
+)
+
+// NewEnergyReportRepository 创建能耗报表仓储 (table 为空使用 DefaultReportTable)
+func NewEnergyReportRepository(db *sql.DB, table string) *EnergyReportRepository {
+	if table == "" {
+		table = DefaultReportTable
+	}
+	return &EnergyReportRepository{db: db, table: quoteIdent(table)}
+}
+
+// EnsureSchema 创建能耗报表表与查询索引 (已存在时跳过)
+func (r *EnergyReportRepository) EnsureSchema(ctx context.Context) error {
+	for _, stmt := range reportTableSQL(r.table, quoteIdent(tableName(r.table)+"_device_period")) {
+		if _, err := r.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create energy report table failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// HealthCheck 读取能耗报表表验证存储可用
+func (r *EnergyReportRepository) HealthCheck(ctx context.Context) error {
+	return checkTables(ctx, r.db, r.table)
+}
+
+// Save 在一个事务内逐条 upsert (按 ID 覆盖)
+func (r *EnergyReportRepository) Save(ctx context.Context, reports ...domain.EnergyReport) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	for _, rp := range reports {
+		if rp.ID == "" {
+			return errors.New("save energy report: id is required")
+		}
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // 提交后 Rollback 为空操作
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(`INSERT INTO %s (id, device_id, metric, period, start_time, end_time, device_model, device_type,
+	total_usage, usage_scaled, scale_factor, co2e_kg, co2e_scaled, weather, generated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW())
+ON CONFLICT (id) DO UPDATE SET device_id = EXCLUDED.device_id, metric = EXCLUDED.metric, period = EXCLUDED.period,
+	start_time = EXCLUDED.start_time, end_time = EXCLUDED.end_time, device_model = EXCLUDED.device_model, device_type = EXCLUDED.device_type,
+	total_usage = EXCLUDED.total_usage, usage_scaled = EXCLUDED.usage_scaled, scale_factor = EXCLUDED.scale_factor,
+	co2e_kg = EXCLUDED.co2e_kg, co2e_scaled = EXCLUDED.co2e_scaled, weather = EXCLUDED.weather, generated_at = EXCLUDED.generated_at`, r.table))
+	if err != nil {
+		return fmt.Errorf("prepare energy report upsert: %w", err)
+	}
+	defer stmt.Close()
+	for _, rp := range reports {
+		var co2eKg, co2eScaled any // NULL 表示没有碳排放
+		if rp.Carbon != nil {
+			co2eKg, co2eScaled = rp.Carbon.CO2eKg, rp.Carbon.CO2eScaled
+		}
+		var weather any
+		if rp.Weather != nil {
+			body, err := json.Marshal(rp.Weather)
+			if err != nil {
+				return fmt.Errorf("encode weather normalization of %s: %w", rp.ID, err)
+			}
+			weather = string(body)
+		}
+		if _, err := stmt.ExecContext(ctx, rp.ID, rp.DeviceID, string(rp.Metric), string(rp.Period), rp.StartTime.UTC(), rp.EndTime.UTC(),
+			rp.DeviceModel, string(rp.DeviceType), rp.TotalUsage, rp.UsageScaled, rp.ScaleFactor, co2eKg, co2eScaled, weather); err != nil {
+			return fmt.Errorf("save energy report %s: %w", rp.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit energy reports: %w", err)
+	}
+	return nil
+}
+
+// Find 返回设备计量通道在 [filter.Start, filter.End) 内开始的报表，按周期开始时间升序
+func (r *EnergyReportRepository) Find(ctx context.Context, filter ports.EnergyReportFilter) ([]domain.EnergyReport, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`SELECT id, device_id, metric, period, start_time, end_time, device_model, device_type,
+	total_usage, usage_scaled, scale_factor, co2e_kg, co2e_scaled, weather FROM %s
+WHERE device_id = $1 AND metric = $2 AND period = $3 AND start_time >= $4 AND start_time < $5 ORDER BY start_time`, r.table),
+		filter.DeviceID, string(filter.Metric), string(filter.Period), filter.Start.UTC(), filter.End.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query energy reports: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.EnergyReport
+	for rows.Next() {
+		var (
+			rp                         domain.EnergyReport
+			metric, period, deviceType string
+			co2eKg                     sql.NullFloat64
+			co2eScaled                 sql.NullInt64
+			weather                    []byte
+		)
+		if err := rows.Scan(&rp.ID, &rp.DeviceID, &metric, &period, &rp.StartTime, &rp.EndTime, &rp.DeviceModel, &deviceType,
+			&rp.TotalUsage, &rp.UsageScaled, &rp.ScaleFactor, &co2eKg, &co2eScaled, &weather); err != nil {
+			return nil, fmt.Errorf("scan energy report: %w", err)
+		}
+		rp.Metric, rp.Period, rp.DeviceType = domain.Metric(metric), domain.ReportPeriod(period), domain.DeviceType(deviceType)
+		if co2eKg.Valid && co2eScaled.Valid {
+			rp.Carbon = &domain.CarbonEmission{CO2eKg: co2eKg.Float64, CO2eScaled: co2eScaled.Int64}
+		}
+		if weather != nil {
+			rp.Weather = new(domain.WeatherNormalization)
+			if err := json.Unmarshal(weather, rp.Weather); err != nil {
+				return nil, fmt.Errorf("decode weather normalization of %s: %w", rp.ID, err)
+			}
+		}
+		out = append(out, rp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query energy reports: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,686 @@
+// Package postgres 提供基于 PostgreSQL / TimescaleDB 的 StandardReadingRepository 实现
+// 通过 database/sql 访问数据库，驱动由调用方注册 (如 lib/pq 或 pgx/stdlib)。
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+const (
+	// DefaultTable 默认的标准读数表名
+	DefaultTable = "standard_readings"
+	// DefaultBatchTable 默认的批次幂等表名
+	DefaultBatchTable = "standard_reading_batches"
+	// DefaultOutboxTable 默认的发件箱表名
+	DefaultOutboxTable = "standard_reading_outbox"
+	// DefaultVersionTable 默认的历史版本表名
+	DefaultVersionTable = "standard_reading_versions"
+)
+
+// Option 配置 StandardReadingRepository
+type Option func(*StandardReadingRepository)
+
+// WithTable 设置标准读数表名 (可带 schema 前缀，如 "energy.standard_readings")
+func WithTable(name string) Option {
+	return func(r *StandardReadingRepository) {
+		r.table = quoteIdent(name)
+	}
+}
+
+// WithBatchTable 设置记录已应用幂等键的表名
+func WithBatchTable(name string) Option {
+	return func(r *StandardReadingRepository) {
+		r.batchTable = quoteIdent(name)
+	}
+}
+
+// WithHypertable 启用 TimescaleDB hypertable，EnsureSchema 时按 chunkInterval 分块
+func WithHypertable(chunkInterval time.Duration) Option {
+	return func(r *StandardReadingRepository) {
+		r.chunkInterval = chunkInterval
+	}
+}
+
+// WithCopyFrom 设置 SaveBatch 写入暂存表的方式 (默认 PQCopyFrom)
+func WithCopyFrom(fn CopyFromFunc) Option {
+	return func(r *StandardReadingRepository) {
+		r.copyFrom = fn
+	}
+}
+
+// WithOutbox 启用事务性发件箱: Save / SaveBatch 在同一事务内把实际生效的读数写入发件箱表
+// table 为空使用 DefaultOutboxTable，topic 为空使用 ports.DefaultOutboxTopic；消息由 NewOutbox + services.OutboxRelay 发布
+func WithOutbox(table, topic string) Option {
+	return func(r *StandardReadingRepository) {
+		if table == "" {
+			table = DefaultOutboxTable
+		}
+		r.outboxTable, r.outboxTopic = quoteIdent(table), topic
+	}
+}
+
+// WithVersioning 启用版本保留: Save / SaveBatch 在同一事务内先把将被替换的读数复制到历史版本表
+// table 为空使用 DefaultVersionTable；历史版本通过 FindVersions 查询 (见 ports.StandardReadingVersioner)
+func WithVersioning(table string) Option {
+	return func(r *StandardReadingRepository) {
+		if table == "" {
+			table = DefaultVersionTable
+		}
+		r.versionTable = quoteIdent(table)
+	}
+}
+
+// StandardReadingRepository 实现 ports.StandardReadingRepository
+// 唯一键为 (tenant_id, device_id, metric, resolution, ts)；冲突按 UpsertStrategy 在数据库内原子裁决:
+// HIGH_PRIORITY_WINS 只在新数据 priority >= 库中数据时更新，LAST_WRITE_WINS 总是覆盖。
+// 租户隔离与内存适配器一致: 写入时未设置租户的读数使用 ctx 的租户 (domain.WithTenant)，查询只返回 ctx 租户的读数。
+type StandardReadingRepository struct {
+	db            *sql.DB
+	table         string
+	batchTable    string
+	chunkInterval time.Duration
+	copyFrom      CopyFromFunc
+	outboxTable   string // 为空表示未启用发件箱
+	outboxTopic   string
+	versionTable  string // 为空表示未启用版本保留
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.StandardReadingRepository  = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingRetention   = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingPager       = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingMultiFinder = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingAggregator  = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingWithdrawer  = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingVersioner   = (*StandardReadingRepository)(nil)
+	_ ports.HealthChecker              = (*StandardReadingRepository)(nil)
+)
+
+// NewStandardReadingRepository 创建仓储实例
+func NewStandardReadingRepository(db *sql.DB, opts ...Option) *StandardReadingRepository {
+	r := &StandardReadingRepository{
+		db:         db,
+		table:      quoteIdent(DefaultTable),
+		batchTable: quoteIdent(DefaultBatchTable),
+		copyFrom:   PQCopyFrom,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// HealthCheck 读取标准读数表、批次表 (启用时还有发件箱表与历史版本表) 验证存储可用
+func (r *StandardReadingRepository) HealthCheck(ctx context.Context) error {
+	tables := []string{r.table, r.batchTable}
+	if r.outboxTable != "" {
+		tables = append(tables, r.outboxTable)
+	}
+	if r.versionTable != "" {
+		tables = append(tables, r.versionTable)
+	}
+	return checkTables(ctx, r.db, tables...)
+}
+
+// Save 保存单个标准读数
+func (r *StandardReadingRepository) Save(ctx context.Context, reading domain.StandardReading, strategy ports.UpsertStrategy) error {
+	if err := validateStrategy(strategy); err != nil {
+		return err
+	}
+	reading = withTenant(ctx, reading)
+	row, err := standardRow(reading)
+	if err != nil {
+		return err
+	}
+	placeholders := make([]string, len(row))
+	for i := range row {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := fmt.Sprintf("INSERT INTO %s AS t (%s) VALUES (%s) %s",
+		r.table, strings.Join(standardColumns, ", "), strings.Join(placeholders, ", "), onConflict(strategy))
+	if r.outboxTable == "" && r.versionTable == "" {
+		if _, err := r.db.ExecContext(ctx, query, row...); err != nil {
+			return fmt.Errorf("save standard reading %s@%s: %w", reading.DeviceID, reading.Timestamp.Format(time.RFC3339), err)
+		}
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	archiveArgs := []any{row[0], row[1], row[2], row[3], row[4], row[5], row[6], row[8], row[14]}
+	if err := r.apply(ctx, tx, r.archiveSQL(singleRowSource, strategy), archiveArgs, query, row...); err != nil {
+		return fmt.Errorf("save standard reading %s@%s: %w", reading.DeviceID, reading.Timestamp.Format(time.RFC3339), err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit standard reading: %w", err)
+	}
+	return nil
+}
+
+// apply 在 tx 内先保留将被替换的版本 (启用时)，再执行 upsert 并发布 (启用发件箱时)
+// 批量写入时数据来自暂存表，两条语句都不带参数
+func (r *StandardReadingRepository) apply(ctx context.Context, tx *sql.Tx, archive string, archiveArgs []any, upsert string, args ...any) error {
+	if r.versionTable != "" {
+		if _, err := tx.ExecContext(ctx, archive, archiveArgs...); err != nil {
+			return fmt.Errorf("archive superseded versions: %w", err)
+		}
+	}
+	if r.outboxTable != "" {
+		return r.applyAndPublish(ctx, tx, upsert, args...)
+	}
+	_, err := tx.ExecContext(ctx, upsert, args...)
+	return err
+}
+
+// applyAndPublish 在 tx 内执行 upsert，并把实际生效的行 (RETURNING) 写入发件箱
+// ON CONFLICT ... DO UPDATE ... WHERE 不满足时不返回该行，因此被优先级拦下的读数不会发布
+func (r *StandardReadingRepository) applyAndPublish(ctx context.Context, tx *sql.Tx, upsert string, args ...any) error {
+	rows, err := tx.QueryContext(ctx, upsert+" RETURNING "+strings.Join(standardColumns, ", "), args...)
+	if err != nil {
+		return err
+	}
+	applied, err := scanStandards(rows)
+	if err != nil {
+		return err
+	}
+	msgs, err := ports.StandardOutboxMessages(r.outboxTopic, applied)
+	if err != nil {
+		return err
+	}
+	for _, m := range msgs {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (topic, msg_key, payload) VALUES ($1, $2, $3)", r.outboxTable),
+			m.Topic, m.Key, string(m.Payload)); err != nil {
+			return fmt.Errorf("write outbox message: %w", err)
+		}
+	}
+	return nil
+}
+
+// SaveBatch 批量保存: COPY 到事务内的临时暂存表，再以一条 INSERT ... SELECT ... ON CONFLICT 合并到主表
+// 批次内同一唯一键的多条读数先按策略去重 (ON CONFLICT 不允许同一语句两次更新同一行)；
+// 幂等键与数据在同一事务中写入，失败回滚后重试不会被误判为已应用。
+func (r *StandardReadingRepository) SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, idempotencyKey string) error {
+	if err := validateStrategy(strategy); err != nil {
+		return err
+	}
+	if len(readings) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, len(readings))
+	for i, sr := range readings {
+		row, err := standardRow(withTenant(ctx, sr))
+		if err != nil {
+			return err
+		}
+		rows[i] = append(row, int64(i)) // seq: 批次内的先后顺序
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // 提交后 Rollback 为空操作
+
+	if idempotencyKey != "" {
+		res, err := tx.ExecContext(ctx,
+			fmt.Sprintf("INSERT INTO %s (idempotency_key) VALUES ($1) ON CONFLICT DO NOTHING", r.batchTable), idempotencyKey)
+		if err != nil {
+			return fmt.Errorf("record idempotency key: %w", err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return nil // 已应用的批次
+		}
+	}
+
+	stage := quoteIdent(stageTable)
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS, seq BIGINT NOT NULL) ON COMMIT DROP", stage, r.table)); err != nil {
+		return fmt.Errorf("create staging table: %w", err)
+	}
+	if err := r.copyFrom(ctx, tx, stage, append(standardColumns[:len(standardColumns):len(standardColumns)], "seq"), rows); err != nil {
+		return err
+	}
+	source := "(" + dedupSQL(stage, strategy) + ") AS s"
+	if err := r.apply(ctx, tx, r.archiveSQL(source, strategy), nil, r.mergeSQL(stage, strategy)); err != nil {
+		return fmt.Errorf("merge staged standard readings: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit standard readings: %w", err)
+	}
+	return nil
+}
+
+// withTenant 为未设置租户的读数补全 ctx 的租户
+func withTenant(ctx context.Context, sr domain.StandardReading) domain.StandardReading {
+	if sr.TenantID == "" {
+		sr.TenantID = domain.TenantFromContext(ctx)
+	}
+	return sr
+}
+
+// stageTable SaveBatch 使用的临时暂存表 (ON COMMIT DROP，事务间互不影响)
+const stageTable = "prism_standard_stage"
+
+// mergeSQL 从暂存表合并到主表的语句
+// DISTINCT ON 按唯一键去重: HIGH_PRIORITY_WINS 取优先级最高者 (同优先级取批次中靠后的一条，与逐条 >= 覆盖的结果一致)，
+// LAST_WRITE_WINS 取批次中最后一条
+func (r *StandardReadingRepository) mergeSQL(stage string, strategy ports.UpsertStrategy) string {
+	return fmt.Sprintf("INSERT INTO %s AS t (%s) %s %s",
+		r.table, strings.Join(standardColumns, ", "), dedupSQL(stage, strategy), onConflict(strategy))
+}
+
+// dedupSQL 从暂存表按唯一键选出每个键最终生效的一条 (mergeSQL 与 archiveSQL 共用)
+func dedupSQL(stage string, strategy ports.UpsertStrategy) string {
+	order := "seq DESC"
+	if strategy == ports.UpsertStrategyHighPriorityWins {
+		order = "priority DESC, seq DESC"
+	}
+	cols := strings.Join(standardColumns, ", ")
+	key := strings.Join(conflictColumns, ", ")
+	return fmt.Sprintf("SELECT DISTINCT ON (%s) %s FROM %s ORDER BY %s, %s", key, cols, stage, key, order)
+}
+
+// singleRowSource Save 的 archiveSQL 数据源: 唯一键与比较所需的列 (参数取自 standardRow)
+const singleRowSource = "(VALUES ($1::text, $2::text, $3::text, $4::text, $5::timestamptz, $6::bigint, $7::integer, $8::text, $9::integer))" +
+	" AS s (tenant_id, device_id, metric, resolution, ts, value_scaled, scale_factor, quality, priority)"
+
+// archiveSQL 把即将被 source (别名 s) 覆盖的当前行复制到历史版本表，版本号在该键已有的最大版本上顺延
+// 只有覆盖会生效 (HIGH_PRIORITY_WINS 要求 s.priority >= t.priority) 且内容有变化时才保留，重放相同数据不产生新版本；
+// 批量写入时批次内同一唯一键只比较最终生效的一条，中间值从未落库，也就没有可保留的版本
+func (r *StandardReadingRepository) archiveSQL(source string, strategy ports.UpsertStrategy) string {
+	cols := make([]string, len(standardColumns))
+	for i, c := range standardColumns {
+		cols[i] = "t." + c
+	}
+	cond := "(s.value_scaled, s.scale_factor, s.quality, s.priority) IS DISTINCT FROM (t.value_scaled, t.scale_factor, t.quality, t.priority)"
+	if strategy == ports.UpsertStrategyHighPriorityWins {
+		cond += " AND s.priority >= t.priority"
+	}
+	return fmt.Sprintf(`INSERT INTO %s (%s, version)
+SELECT %s, (SELECT COALESCE(MAX(v.version), 0) + 1 FROM %s v
+	WHERE v.tenant_id = t.tenant_id AND v.device_id = t.device_id AND v.metric = t.metric AND v.resolution = t.resolution AND v.ts = t.ts)
+FROM %s t JOIN %s USING (%s)
+WHERE %s`,
+		r.versionTable, strings.Join(standardColumns, ", "), strings.Join(cols, ", "), r.versionTable,
+		r.table, source, strings.Join(conflictColumns, ", "), cond)
+}
+
+// onConflict 按策略生成 ON CONFLICT 子句
+// 关键在于 WHERE 比较的方向: EXCLUDED 是新数据，别名 t 是库中已有数据
+func onConflict(strategy ports.UpsertStrategy) string {
+	sets := make([]string, 0, len(standardColumns))
+	for _, c := range standardColumns {
+		if slices.Contains(conflictColumns, c) {
+			continue
+		}
+		sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", c, c))
+	}
+	clause := fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(conflictColumns, ", "), strings.Join(sets, ", "))
+	if strategy == ports.UpsertStrategyHighPriorityWins {
+		clause += " WHERE EXCLUDED.priority >= t.priority"
+	}
+	return clause
+}
+
+// FindExact 获取设备默认通道在 timestamp 时间点的标准读数
+// 同一时间点存在多个分辨率时按分辨率标签排序取第一条
+func (r *StandardReadingRepository) FindExact(ctx context.Context, deviceID string, timestamp time.Time) (*domain.StandardReading, error) {
+	rows, err := r.query(ctx, "AND device_id = $2 AND metric = '' AND ts = $3 ORDER BY resolution LIMIT 1", deviceID, timestamp.UTC())
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ports.ErrNotFound
+	}
+	return &rows[0], nil
+}
+
+// FindRange 获取设备在 [start, end] 内的标准读数 (全部通道与分辨率)，按时间排序
+func (r *StandardReadingRepository) FindRange(ctx context.Context, deviceID string, start, end time.Time) ([]domain.StandardReading, error) {
+	return r.query(ctx, "AND device_id = $2 AND ts >= $3 AND ts <= $4 ORDER BY ts, metric, resolution",
+		deviceID, start.UTC(), end.UTC())
+}
+
+// FindRangePaged 按 (ts, metric, resolution) 升序 keyset 分页
+// 文本列使用 "C" 排序规则，保证数据库内的顺序与游标比较 (字节序) 一致，不受数据库默认 collation 影响
+func (r *StandardReadingRepository) FindRangePaged(ctx context.Context, deviceID string, start, end time.Time, page ports.PageRequest) (ports.StandardPage, error) {
+	limit := page.PageSize()
+	clause := "AND device_id = $2 AND ts >= $3 AND ts <= $4"
+	args := []any{deviceID, start.UTC(), end.UTC()}
+	if page.Cursor != "" {
+		c, err := ports.DecodeCursor(page.Cursor)
+		if err != nil {
+			return ports.StandardPage{}, err
+		}
+		clause += ` AND (ts, metric COLLATE "C", resolution COLLATE "C") > ($5, $6, $7)`
+		args = append(args, c.Timestamp, string(c.Metric), c.Resolution)
+	}
+	clause += fmt.Sprintf(` ORDER BY ts, metric COLLATE "C", resolution COLLATE "C" LIMIT %d`, limit+1)
+
+	rows, err := r.query(ctx, clause, args...)
+	if err != nil {
+		return ports.StandardPage{}, err
+	}
+	return ports.PageOf(rows, limit), nil
+}
+
+// multiQueryDevices FindRangeMulti 单条查询最多包含的设备数 (远低于 PostgreSQL 65535 个绑定参数的上限)
+const multiQueryDevices = 1000
+
+// FindRangeMulti 获取多个设备在 [start, end] 内的标准读数，按设备分组
+// 设备列表按 multiQueryDevices 分批，每批一次 device_id IN (...) 查询
+func (r *StandardReadingRepository) FindRangeMulti(ctx context.Context, deviceIDs []string, start, end time.Time) (map[string][]domain.StandardReading, error) {
+	ids := slices.Compact(slices.Sorted(slices.Values(deviceIDs)))
+	out := make(map[string][]domain.StandardReading, len(ids))
+	for chunk := range slices.Chunk(ids, multiQueryDevices) {
+		args := []any{start.UTC(), end.UTC()}
+		placeholders := make([]string, len(chunk))
+		for i, id := range chunk {
+			args = append(args, id)
+			placeholders[i] = fmt.Sprintf("$%d", i+4)
+		}
+		rows, err := r.query(ctx, fmt.Sprintf("AND ts >= $2 AND ts <= $3 AND device_id IN (%s) ORDER BY device_id, ts, metric, resolution",
+			strings.Join(placeholders, ", ")), args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, sr := range rows {
+			out[sr.DeviceID] = append(out[sr.DeviceID], sr)
+		}
+	}
+	return out, nil
+}
+
+// SumByPeriod 按周期求和 (数据库内 date_trunc + SUM)
+func (r *StandardReadingRepository) SumByPeriod(ctx context.Context, q ports.AggregateQuery) ([]ports.AggregateBucket, error) {
+	return r.aggregate(ctx, q, "SUM")
+}
+
+// AvgByPeriod 按周期求平均值 (数据库内 date_trunc + AVG)
+func (r *StandardReadingRepository) AvgByPeriod(ctx context.Context, q ports.AggregateQuery) ([]ports.AggregateBucket, error) {
+	return r.aggregate(ctx, q, "AVG")
+}
+
+// aggregate 在查询时区下按周期截断时间并聚合
+// ts AT TIME ZONE tz 得到当地时间 (timestamp without time zone)，截断后按当地时间还原为周期开始时刻
+func (r *StandardReadingRepository) aggregate(ctx context.Context, q ports.AggregateQuery, fn string) ([]ports.AggregateBucket, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if loc == time.Local {
+		return nil, errors.New("aggregate query: location must be an IANA time zone, not time.Local")
+	}
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`SELECT date_trunc($1, ts AT TIME ZONE $2) AS bucket, %s(value_display), COUNT(*) FROM %s
+WHERE tenant_id = $3 AND device_id = $4 AND metric = $5 AND resolution = $6 AND ts >= $7 AND ts < $8 AND quality NOT IN ($9, $10)
+GROUP BY bucket ORDER BY bucket`, fn, r.table),
+		strings.ToLower(string(q.Period)), loc.String(),
+		domain.TenantFromContext(ctx), q.DeviceID, string(q.Metric), q.Resolution, q.Start.UTC(), q.End.UTC(),
+		string(domain.QualityMissing), string(domain.QualityWithdrawn))
+	if err != nil {
+		return nil, fmt.Errorf("aggregate standard readings: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ports.AggregateBucket
+	for rows.Next() {
+		var (
+			wall time.Time
+			b    ports.AggregateBucket
+		)
+		if err := rows.Scan(&wall, &b.Value, &b.Count); err != nil {
+			return nil, fmt.Errorf("scan aggregate: %w", err)
+		}
+		b.Start = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), 0, 0, 0, loc)
+		out = append(out, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("aggregate standard readings: %w", err)
+	}
+	return out, nil
+}
+
+// Withdraw 将满足条件的标准读数标记为已撤回，返回本次新撤回的读数
+// 单条 UPDATE ... RETURNING 完成: 撤回记录由数据库内的 jsonb_build_object 生成，原质量标记取自更新前的行
+func (r *StandardReadingRepository) Withdraw(ctx context.Context, req ports.WithdrawRequest) ([]domain.StandardReading, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	at := req.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`UPDATE %s SET quality = $1,
+	withdrawal = jsonb_build_object('reason', $2::text, 'operator', $3::text, 'at', $4::timestamptz, 'previous_quality', quality)
+WHERE tenant_id = $5 AND device_id = $6 AND metric = $7 AND ($8::text = '' OR resolution = $8) AND ts >= $9 AND ts <= $10 AND withdrawal IS NULL
+RETURNING %s`, r.table, strings.Join(standardColumns, ", ")),
+		string(domain.QualityWithdrawn), req.Reason, req.Operator, at.UTC(),
+		domain.TenantFromContext(ctx), req.DeviceID, string(req.Metric), req.Resolution, req.Start.UTC(), req.End.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("withdraw standard readings of %s: %w", req.DeviceID, err)
+	}
+	out, err := scanStandards(rows)
+	if err != nil {
+		return nil, fmt.Errorf("withdraw standard readings of %s: %w", req.DeviceID, err)
+	}
+	slices.SortFunc(out, func(a, b domain.StandardReading) int { return a.Timestamp.Compare(b.Timestamp) })
+	return out, nil
+}
+
+// FindLatest 获取设备最新的一条标准读数 (沿 (device_id, ts) 索引倒序扫描)
+func (r *StandardReadingRepository) FindLatest(ctx context.Context, deviceID string) (*domain.StandardReading, error) {
+	rows, err := r.query(ctx, `AND device_id = $2 ORDER BY ts DESC, metric COLLATE "C", resolution COLLATE "C" LIMIT 1`, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ports.ErrNotFound
+	}
+	return &rows[0], nil
+}
+
+// FindLastBefore 获取指定通道、分辨率下时间早于 before 的最近一条标准读数
+func (r *StandardReadingRepository) FindLastBefore(ctx context.Context, deviceID string, metric domain.Metric, resolution string, before time.Time) (*domain.StandardReading, error) {
+	rows, err := r.query(ctx, "AND device_id = $2 AND metric = $3 AND resolution = $4 AND ts < $5 ORDER BY ts DESC LIMIT 1",
+		deviceID, string(metric), resolution, before.UTC())
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ports.ErrNotFound
+	}
+	return &rows[0], nil
+}
+
+// FindVersions 返回设备在 timestamp 时间点的全部版本: 历史版本来自历史版本表，当前版本的版本号在其后顺延
+// 未启用 WithVersioning 时只返回当前版本 (版本号为 1)
+func (r *StandardReadingRepository) FindVersions(ctx context.Context, deviceID string, timestamp time.Time) ([]domain.StandardReading, error) {
+	cols := strings.Join(standardColumns, ", ")
+	query := fmt.Sprintf("SELECT %s, 1 AS version FROM %s WHERE tenant_id = $1 AND device_id = $2 AND ts = $3 ORDER BY metric, resolution", cols, r.table)
+	if r.versionTable != "" {
+		query = fmt.Sprintf(`SELECT %s, version FROM %s WHERE tenant_id = $1 AND device_id = $2 AND ts = $3
+UNION ALL
+SELECT %s, (SELECT COALESCE(MAX(v.version), 0) + 1 FROM %s v
+	WHERE v.tenant_id = t.tenant_id AND v.device_id = t.device_id AND v.metric = t.metric AND v.resolution = t.resolution AND v.ts = t.ts)
+FROM %s t WHERE tenant_id = $1 AND device_id = $2 AND ts = $3
+ORDER BY metric, resolution, version`, cols, r.versionTable, cols, r.versionTable, r.table)
+	}
+	rows, err := r.db.QueryContext(ctx, query, domain.TenantFromContext(ctx), deviceID, timestamp.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query standard reading versions: %w", err)
+	}
+	defer rows.Close()
+	var out []domain.StandardReading
+	for rows.Next() {
+		var version int64
+		sr, err := scanStandard(rows, &version)
+		if err != nil {
+			return nil, err
+		}
+		sr.Version = int(version)
+		out = append(out, sr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query standard reading versions: %w", err)
+	}
+	return out, nil
+}
+
+// DeleteOlderThan 删除时间早于 cutoff 的标准读数 (deviceID / resolution 为空表示全部)
+// deviceID 为空时: ctx 设置了租户则只删除该租户的读数，否则删除全部租户的读数 (与内存适配器一致)
+// hypertable 上按整块过期时，drop_chunks 比逐行删除更高效，可由运维另行配置 TimescaleDB 的保留策略
+func (r *StandardReadingRepository) DeleteOlderThan(ctx context.Context, deviceID string, resolution string, cutoff time.Time) (int64, error) {
+	const where = " WHERE ts < $1 AND ($2::text = '' OR (tenant_id = $4 AND device_id = $2)) AND ($3::text = '' OR resolution = $3)" +
+		" AND ($4::text = '' OR tenant_id = $4)"
+	tenantID := domain.TenantFromContext(ctx)
+	if r.versionTable != "" {
+		// 历史版本随当前读数一同清理 (不计入返回的条数)
+		if _, err := r.db.ExecContext(ctx, "DELETE FROM "+r.versionTable+where, cutoff.UTC(), deviceID, resolution, tenantID); err != nil {
+			return 0, fmt.Errorf("delete standard reading versions before %s: %w", cutoff.Format(time.RFC3339), err)
+		}
+	}
+	res, err := r.db.ExecContext(ctx, "DELETE FROM "+r.table+where, cutoff.UTC(), deviceID, resolution, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("delete standard readings before %s: %w", cutoff.Format(time.RFC3339), err)
+	}
+	return res.RowsAffected()
+}
+
+// query 查询 ctx 租户下的标准读数: 租户条件固定为 $1，clause 以 AND 开头，接续其余过滤条件 ($2 起) 与排序子句
+func (r *StandardReadingRepository) query(ctx context.Context, clause string, args ...any) ([]domain.StandardReading, error) {
+	args = append([]any{domain.TenantFromContext(ctx)}, args...)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE tenant_id = $1 %s", strings.Join(standardColumns, ", "), r.table, clause), args...)
+	if err != nil {
+		return nil, fmt.Errorf("query standard readings: %w", err)
+	}
+	out, err := scanStandards(rows)
+	if err != nil {
+		return nil, fmt.Errorf("query standard readings: %w", err)
+	}
+	return out, nil
+}
+
+// scanStandards 读取全部结果行并关闭 rows
+func scanStandards(rows *sql.Rows) ([]domain.StandardReading, error) {
+	defer rows.Close()
+	var out []domain.StandardReading
+	for rows.Next() {
+		sr, err := scanStandard(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sr)
+	}
+	return out, rows.Err()
+}
+
+// standardRow 按 standardColumns 的顺序展开标准读数
+func standardRow(sr domain.StandardReading) ([]any, error) {
+	var calibration any // NULL 表示未校准
+	if sr.Calibration != nil {
+		b, err := json.Marshal(sr.Calibration)
+		if err != nil {
+			return nil, fmt.Errorf("encode calibration of %s: %w", sr.DeviceID, err)
+		}
+		calibration = string(b)
+	}
+	var withdrawal any // NULL 表示未撤回
+	if sr.Withdrawal != nil {
+		b, err := json.Marshal(sr.Withdrawal)
+		if err != nil {
+			return nil, fmt.Errorf("encode withdrawal of %s: %w", sr.DeviceID, err)
+		}
+		withdrawal = string(b)
+	}
+	var provenance any // NULL 表示未记录来源
+	if sr.Provenance != nil {
+		b, err := json.Marshal(sr.Provenance)
+		if err != nil {
+			return nil, fmt.Errorf("encode provenance of %s: %w", sr.DeviceID, err)
+		}
+		provenance = string(b)
+	}
+	ingestedAt := sr.IngestedAt
+	if ingestedAt.IsZero() {
+		ingestedAt = time.Now()
+	}
+	return []any{
+		sr.TenantID, sr.DeviceID, string(sr.Metric), sr.Resolution, sr.Timestamp.UTC(),
+		sr.ValueScaled, int64(sr.ScaleFactor), sr.ValueDisplay,
+		string(sr.Quality), sr.QualityReason, sr.Confidence, string(sr.SourceType), calibration,
+		ingestedAt.UTC(), int64(sr.Priority), withdrawal, provenance,
+	}, nil
+}
+
+// scanStandard 按 standardColumns 的顺序读取一行，extra 接收其后的附加列
+func scanStandard(rows *sql.Rows, extra ...any) (domain.StandardReading, error) {
+	var (
+		sr                          domain.StandardReading
+		metric, quality, sourceType string
+		scaleFactor, priority       int64
+		calibration, withdrawal     []byte
+		provenance                  []byte
+	)
+	dest := append([]any{
+		&sr.TenantID, &sr.DeviceID, &metric, &sr.Resolution, &sr.Timestamp,
+		&sr.ValueScaled, &scaleFactor, &sr.ValueDisplay,
+		&quality, &sr.QualityReason, &sr.Confidence, &sourceType, &calibration,
+		&sr.IngestedAt, &priority, &withdrawal, &provenance,
+	}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return sr, fmt.Errorf("scan standard reading: %w", err)
+	}
+	sr.Metric = domain.Metric(metric)
+	sr.Quality = domain.QualityState(quality)
+	sr.SourceType = domain.ReadingType(sourceType)
+	sr.ScaleFactor = int(scaleFactor)
+	sr.Priority = int(priority)
+	if len(calibration) > 0 {
+		sr.Calibration = &domain.Calibration{}
+		if err := json.Unmarshal(calibration, sr.Calibration); err != nil {
+			return sr, fmt.Errorf("decode calibration of %s: %w", sr.DeviceID, err)
+		}
+	}
+	if len(withdrawal) > 0 {
+		sr.Withdrawal = &domain.Withdrawal{}
+		if err := json.Unmarshal(withdrawal, sr.Withdrawal); err != nil {
+			return sr, fmt.Errorf("decode withdrawal of %s: %w", sr.DeviceID, err)
+		}
+	}
+	if len(provenance) > 0 {
+		sr.Provenance = &domain.Provenance{}
+		if err := json.Unmarshal(provenance, sr.Provenance); err != nil {
+			return sr, fmt.Errorf("decode provenance of %s: %w", sr.DeviceID, err)
+		}
+	}
+	return sr, nil
+}
+
+// validateStrategy 拒绝未知策略，避免空值被静默当作覆盖写入
+func validateStrategy(strategy ports.UpsertStrategy) error {
+	switch strategy {
+	case ports.UpsertStrategyHighPriorityWins, ports.UpsertStrategyLastWriteWins:
+		return nil
+	default:
+		return fmt.Errorf("unsupported upsert strategy %q", strategy)
+	}
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// standardColumns 标准读数表的数据列 (COPY 与 upsert 共用的列顺序)
+var standardColumns = []string{
+	"tenant_id", "device_id", "metric", "resolution", "ts",
+	"value_scaled", "scale_factor", "value_display",
+	"quality", "quality_reason", "confidence", "source_type", "calibration",
+	"ingested_at", "priority", "withdrawal", "provenance",
+}
+
+// conflictColumns 唯一键: 设备ID只在租户内唯一，同一设备同一通道同一时间点可存在多个分辨率
+var conflictColumns = []string{"tenant_id", "device_id", "metric", "resolution", "ts"}
+
+// rawKeyColumns 原始读数表的唯一键
+var rawKeyColumns = []string{"tenant_id", "device_id", "metric", "ts"}
+
+// createTableSQL 标准读数表、批次幂等表与 (启用时) 发件箱表、历史版本表的 DDL
+// ts 是唯一键的一部分，满足 TimescaleDB 对 hypertable 唯一索引必须包含分区列的要求
+func (r *StandardReadingRepository) createTableSQL() []string {
+	stmts := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	tenant_id      TEXT             NOT NULL DEFAULT '',
+	device_id      TEXT             NOT NULL,
+	metric         TEXT             NOT NULL DEFAULT '',
+	resolution     TEXT             NOT NULL,
+	ts             TIMESTAMPTZ      NOT NULL,
+	value_scaled   BIGINT           NOT NULL,
+	scale_factor   INTEGER          NOT NULL,
+	value_display  DOUBLE PRECISION NOT NULL,
+	quality        TEXT             NOT NULL,
+	quality_reason TEXT             NOT NULL DEFAULT '',
+	confidence     DOUBLE PRECISION NOT NULL DEFAULT 1,
+	source_type    TEXT             NOT NULL,
+	calibration    JSONB,
+	ingested_at    TIMESTAMPTZ      NOT NULL,
+	priority       INTEGER          NOT NULL DEFAULT 0,
+	withdrawal     JSONB,
+	provenance     JSONB,
+	PRIMARY KEY (%s)
+)`, r.table, strings.Join(conflictColumns, ", ")),
+		// provenance 列在 000006 迁移中加入，为此前按旧结构创建的表补齐
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS provenance JSONB`, r.table),
+	}
+	// tenant_id 列与含租户的主键在 000007 迁移中加入
+	stmts = append(stmts, tenantKeySQL(r.table, conflictColumns)...)
+	stmts = append(stmts,
+		// 时间区间查询与 keyset 分页按 (tenant_id, device_id, ts) 扫描
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (tenant_id, device_id, ts)`, quoteIdent(r.indexName("tenant_device_ts")), r.table),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	idempotency_key TEXT        PRIMARY KEY,
+	applied_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`, r.batchTable),
+	)
+	if r.outboxTable != "" {
+		stmts = append(stmts, outboxTableSQL(r.outboxTable))
+	}
+	if r.versionTable != "" {
+		stmts = append(stmts, versionsTableSQL(r.versionTable),
+			fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS provenance JSONB`, r.versionTable))
+		stmts = append(stmts, tenantKeySQL(r.versionTable, append(conflictColumns[:len(conflictColumns):len(conflictColumns)], "version"))...)
+	}
+	return stmts
+}
+
+// versionsTableSQL 历史版本表的 DDL: 标准读数的全部列加版本号与被替换时间
+func versionsTableSQL(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	tenant_id      TEXT             NOT NULL DEFAULT '',
+	device_id      TEXT             NOT NULL,
+	metric         TEXT             NOT NULL DEFAULT '',
+	resolution     TEXT             NOT NULL,
+	ts             TIMESTAMPTZ      NOT NULL,
+	value_scaled   BIGINT           NOT NULL,
+	scale_factor   INTEGER          NOT NULL,
+	value_display  DOUBLE PRECISION NOT NULL,
+	quality        TEXT             NOT NULL,
+	quality_reason TEXT             NOT NULL DEFAULT '',
+	confidence     DOUBLE PRECISION NOT NULL DEFAULT 1,
+	source_type    TEXT             NOT NULL,
+	calibration    JSONB,
+	ingested_at    TIMESTAMPTZ      NOT NULL,
+	priority       INTEGER          NOT NULL DEFAULT 0,
+	withdrawal     JSONB,
+	provenance     JSONB,
+	version        INTEGER          NOT NULL,
+	superseded_at  TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (%s, version)
+)`, table, strings.Join(conflictColumns, ", "))
+}
+
+// outboxTableSQL 发件箱表的 DDL (已发布的消息由 Outbox.MarkPublished 删除)
+func outboxTableSQL(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id         BIGSERIAL   PRIMARY KEY,
+	topic      TEXT        NOT NULL,
+	msg_key    TEXT        NOT NULL,
+	payload    JSONB       NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`, table)
+}
+
+// rawTableSQL 原始读数归档表 (数值为 NULL 表示缺失值)，并为此前按旧结构创建的表补齐租户列与主键
+func rawTableSQL(table string) []string {
+	return append([]string{fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	tenant_id   TEXT             NOT NULL DEFAULT '',
+	device_id   TEXT             NOT NULL,
+	metric      TEXT             NOT NULL DEFAULT '',
+	ts          TIMESTAMPTZ      NOT NULL,
+	value       DOUBLE PRECISION,
+	body        JSONB            NOT NULL,
+	archived_at TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (%s)
+)`, table, strings.Join(rawKeyColumns, ", "))}, tenantKeySQL(table, rawKeyColumns)...)
+}
+
+// tenantKeySQL 为按旧结构 (无租户) 创建的表补齐 tenant_id 列 (原有数据归入默认租户)，并把主键替换为 key
+// 主键已包含 tenant_id 时不做任何修改，可重复执行
+func tenantKeySQL(table string, key []string) []string {
+	return []string{
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT ''`, table),
+		fmt.Sprintf(`DO $$
+DECLARE pk name;
+BEGIN
+	SELECT c.conname INTO pk FROM pg_constraint c WHERE c.conrelid = '%[1]s'::regclass AND c.contype = 'p';
+	IF pk IS NOT NULL AND NOT EXISTS (SELECT 1 FROM pg_constraint c JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = ANY (c.conkey)
+		WHERE c.conrelid = '%[1]s'::regclass AND c.contype = 'p' AND a.attname = 'tenant_id') THEN
+		EXECUTE format('ALTER TABLE %[1]s DROP CONSTRAINT %%I, ADD PRIMARY KEY (%[2]s)', pk);
+	END IF;
+END $$`, table, strings.Join(key, ", ")),
+	}
+}
+
+// reportTableSQL 能耗报表表与按设备、通道、周期查询的索引 (碳排放、气候修正列为 NULL 表示没有相应结果)
+// weather 列在 000005 迁移中加入，ALTER 语句为此前按旧结构创建的表补齐该列
+func reportTableSQL(table, index string) []string {
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id           TEXT             PRIMARY KEY,
+	device_id    TEXT             NOT NULL,
+	metric       TEXT             NOT NULL DEFAULT '',
+	period       TEXT             NOT NULL,
+	start_time   TIMESTAMPTZ      NOT NULL,
+	end_time     TIMESTAMPTZ      NOT NULL,
+	device_model TEXT             NOT NULL DEFAULT '',
+	device_type  TEXT             NOT NULL DEFAULT '',
+	total_usage  DOUBLE PRECISION NOT NULL,
+	usage_scaled BIGINT           NOT NULL,
+	scale_factor INTEGER          NOT NULL,
+	co2e_kg      DOUBLE PRECISION,
+	co2e_scaled  BIGINT,
+	weather      JSONB,
+	generated_at TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+)`, table),
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS weather JSONB`, table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (device_id, metric, period, start_time)`, index, table),
+	}
+}
+
+// EnsureSchema 创建标准读数表、批次幂等表与 (启用时) 发件箱表、历史版本表 (已存在时跳过)
+// 启用 hypertable 时同时调用 create_hypertable (需已安装 TimescaleDB 扩展)
+func (r *StandardReadingRepository) EnsureSchema(ctx context.Context) error {
+	for _, stmt := range r.createTableSQL() {
+		if _, err := r.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create schema failed: %w", err)
+		}
+	}
+	if r.chunkInterval <= 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx,
+		`SELECT create_hypertable($1::regclass, 'ts', chunk_time_interval => $2::interval, if_not_exists => TRUE)`,
+		r.table, intervalLiteral(r.chunkInterval)); err != nil {
+		return fmt.Errorf("create hypertable %s failed: %w", r.table, err)
+	}
+	return nil
+}
+
+// indexName 以表名 (不含 schema) 为前缀生成索引名
+func (r *StandardReadingRepository) indexName(suffix string) string {
+	return tableName(r.table) + "_" + suffix
+}
+
+// tableName 返回已转义标识符中的表名 (不含 schema 与引号)
+func tableName(quoted string) string {
+	parts := strings.Split(quoted, ".")
+	return strings.ReplaceAll(strings.Trim(parts[len(parts)-1], `"`), `""`, `"`)
+}
+
+// checkTables 依次读取各表 (已转义的标识符) 的一行，验证数据库可达且表已创建、有读权限
+func checkTables(ctx context.Context, db *sql.DB, tables ...string) error {
+	for _, table := range tables {
+		rows, err := db.QueryContext(ctx, "SELECT 1 FROM "+table+" LIMIT 1")
+		if err != nil {
+			return fmt.Errorf("health check %s: %w", table, err)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("health check %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// intervalLiteral 将时长格式化为 PostgreSQL interval 字面量 (精确到微秒)
+func intervalLiteral(d time.Duration) string {
+	return fmt.Sprintf("%d microseconds", d.Microseconds())
+}
+
+// quoteIdent 引用 (可带 schema 前缀的) 标识符，如 energy.standard_readings -> "energy"."standard_readings"
+func quoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
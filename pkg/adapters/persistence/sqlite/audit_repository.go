@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// AuditRepository 实现 ports.AuditRepository
+// 审计记录整体以 JSON 保存在 body 列，设备、读数时间与覆盖时间另存一列用于过滤和排序
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.AuditRepository = (*AuditRepository)(nil)
+	_ ports.HealthChecker   = (*AuditRepository)(nil)
+)
+
+// NewAuditRepository 创建审计日志仓储 (需先调用 EnsureSchema 或 Migrate)
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// HealthCheck 读取 standard_reading_audit 表验证存储可用
+func (r *AuditRepository) HealthCheck(ctx context.Context) error {
+	return checkTables(ctx, r.db, "standard_reading_audit")
+}
+
+// Record 在一个事务内追加审计记录
+func (r *AuditRepository) Record(ctx context.Context, entries []ports.StandardReadingAudit) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin audit transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO standard_reading_audit (device_id, ts, changed_at, body) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("prepare audit insert: %w", err)
+	}
+	defer stmt.Close()
+	for _, e := range entries {
+		body, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("encode audit entry for %s: %w", e.New.DeviceID, err)
+		}
+		if _, err := stmt.ExecContext(ctx, e.New.DeviceID, toNanos(e.New.Timestamp), toNanos(e.ChangedAt), string(body)); err != nil {
+			return fmt.Errorf("insert audit entry for %s: %w", e.New.DeviceID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// FindByDevice 获取设备在 [start, end] 内的审计记录 (按覆盖时间、追加顺序排序)
+func (r *AuditRepository) FindByDevice(ctx context.Context, deviceID string, start, end time.Time) ([]ports.StandardReadingAudit, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT body FROM standard_reading_audit WHERE device_id = ? AND ts >= ? AND ts <= ? ORDER BY changed_at, id",
+		deviceID, toNanos(start), toNanos(end))
+	if err != nil {
+		return nil, fmt.Errorf("query audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ports.StandardReadingAudit
+	for rows.Next() {
+		var body string
+		if err := rows.Scan(&body); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		var e ports.StandardReadingAudit
+		if err := json.Unmarshal([]byte(body), &e); err != nil {
+			return nil, fmt.Errorf("decode audit entry: %w", err)
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query audit entries: %w", err)
+	}
+	return out, nil
+}
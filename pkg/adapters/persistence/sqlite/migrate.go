@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/internal/sqlmigrate"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrations 内嵌的迁移文件 (golang-migrate 格式，位于根目录)
+// 可直接交给 golang-migrate 的 iofs 源: iofs.New(sqlite.Migrations, ".")
+var Migrations = mustSub(migrationFiles, "migrations")
+
+// versionTableSQL 与 golang-migrate sqlite 驱动一致的版本表
+const versionTableSQL = `CREATE TABLE IF NOT EXISTS ` + sqlmigrate.Table + ` (version UINT64, dirty BOOL)`
+
+// Migrate 执行全部未应用的迁移 (与 EnsureSchema 创建相同的表，但记录版本，便于后续升级)
+// 驱动需支持单次 Exec 执行多条语句 (modernc.org/sqlite 与 mattn/go-sqlite3 均支持)。
+func Migrate(ctx context.Context, db *sql.DB) error {
+	return sqlmigrate.Up(ctx, db, Migrations, versionTableSQL)
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS quarantine_readings;
+DROP TABLE IF EXISTS cleaning_rules;
+DROP TABLE IF EXISTS outbox_messages;
+DROP TABLE IF EXISTS standard_reading_batches;
+DROP TABLE IF EXISTS standard_readings;
@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS standard_readings (
+	device_id      TEXT    NOT NULL,
+	metric         TEXT    NOT NULL DEFAULT '',
+	resolution     TEXT    NOT NULL,
+	ts             INTEGER NOT NULL,
+	value_scaled   INTEGER NOT NULL,
+	scale_factor   INTEGER NOT NULL,
+	value_display  REAL    NOT NULL,
+	quality        TEXT    NOT NULL,
+	quality_reason TEXT    NOT NULL DEFAULT '',
+	confidence     REAL    NOT NULL DEFAULT 1,
+	source_type    TEXT    NOT NULL,
+	calibration    TEXT,
+	ingested_at    INTEGER NOT NULL,
+	priority       INTEGER NOT NULL DEFAULT 0,
+	withdrawal     TEXT,
+	PRIMARY KEY (device_id, metric, resolution, ts)
+) WITHOUT ROWID;
+
+CREATE INDEX IF NOT EXISTS standard_readings_device_ts ON standard_readings (device_id, ts);
+
+CREATE TABLE IF NOT EXISTS standard_reading_batches (
+	idempotency_key TEXT    PRIMARY KEY,
+	applied_at      INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS outbox_messages (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	topic      TEXT    NOT NULL,
+	msg_key    TEXT    NOT NULL,
+	payload    TEXT    NOT NULL,
+	created_at INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS cleaning_rules (
+	id          TEXT    PRIMARY KEY,
+	device_type TEXT    NOT NULL,
+	enabled     INTEGER NOT NULL,
+	priority    INTEGER NOT NULL,
+	body        TEXT    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS cleaning_rules_device_type ON cleaning_rules (device_type, priority);
+
+CREATE TABLE IF NOT EXISTS quarantine_readings (
+	id          TEXT    PRIMARY KEY,
+	device_id   TEXT    NOT NULL,
+	device_type TEXT    NOT NULL,
+	ts          INTEGER NOT NULL,
+	status      TEXT    NOT NULL,
+	created_at  INTEGER NOT NULL,
+	body        TEXT    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS quarantine_readings_status ON quarantine_readings (status, device_type, created_at);
+
+CREATE INDEX IF NOT EXISTS quarantine_readings_device ON quarantine_readings (device_id, ts);
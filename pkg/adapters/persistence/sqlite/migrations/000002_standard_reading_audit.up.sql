@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS standard_reading_audit (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	device_id  TEXT    NOT NULL,
+	ts         INTEGER NOT NULL,
+	changed_at INTEGER NOT NULL,
+	body       TEXT    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS standard_reading_audit_device ON standard_reading_audit (device_id, ts);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS standard_reading_versions (
+	device_id      TEXT    NOT NULL,
+	metric         TEXT    NOT NULL DEFAULT '',
+	resolution     TEXT    NOT NULL,
+	ts             INTEGER NOT NULL,
+	value_scaled   INTEGER NOT NULL,
+	scale_factor   INTEGER NOT NULL,
+	value_display  REAL    NOT NULL,
+	quality        TEXT    NOT NULL,
+	quality_reason TEXT    NOT NULL DEFAULT '',
+	confidence     REAL    NOT NULL DEFAULT 1,
+	source_type    TEXT    NOT NULL,
+	calibration    TEXT,
+	ingested_at    INTEGER NOT NULL,
+	priority       INTEGER NOT NULL DEFAULT 0,
+	withdrawal     TEXT,
+	version        INTEGER NOT NULL,
+	superseded_at  INTEGER NOT NULL,
+	PRIMARY KEY (device_id, metric, resolution, ts, version)
+) WITHOUT ROWID;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS raw_readings (
+	device_id   TEXT    NOT NULL,
+	metric      TEXT    NOT NULL DEFAULT '',
+	ts          INTEGER NOT NULL,
+	value       REAL,
+	body        TEXT    NOT NULL,
+	archived_at INTEGER NOT NULL,
+	PRIMARY KEY (device_id, metric, ts)
+) WITHOUT ROWID;
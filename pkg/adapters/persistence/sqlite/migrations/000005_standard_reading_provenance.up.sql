@@ -0,0 +1,2 @@
+ALTER TABLE standard_readings ADD COLUMN provenance TEXT;
+ALTER TABLE standard_reading_versions ADD COLUMN provenance TEXT;
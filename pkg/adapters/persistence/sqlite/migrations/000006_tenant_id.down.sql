@@ -0,0 +1,77 @@
+-- 不同租户存在同名设备的同一时间点时，恢复旧主键会失败，需先人工清理
+DROP INDEX IF EXISTS quarantine_readings_tenant_status;
+DROP INDEX IF EXISTS quarantine_readings_tenant_device;
+ALTER TABLE quarantine_readings DROP COLUMN tenant_id;
+CREATE INDEX IF NOT EXISTS quarantine_readings_status ON quarantine_readings (status, device_type, created_at);
+CREATE INDEX IF NOT EXISTS quarantine_readings_device ON quarantine_readings (device_id, ts);
+
+CREATE TABLE raw_readings_legacy (
+	device_id   TEXT    NOT NULL,
+	metric      TEXT    NOT NULL DEFAULT '',
+	ts          INTEGER NOT NULL,
+	value       REAL,
+	body        TEXT    NOT NULL,
+	archived_at INTEGER NOT NULL,
+	PRIMARY KEY (device_id, metric, ts)
+) WITHOUT ROWID;
+INSERT INTO raw_readings_legacy (device_id, metric, ts, value, body, archived_at)
+SELECT device_id, metric, ts, value, body, archived_at FROM raw_readings;
+DROP TABLE raw_readings;
+ALTER TABLE raw_readings_legacy RENAME TO raw_readings;
+
+CREATE TABLE standard_reading_versions_legacy (
+	device_id      TEXT    NOT NULL,
+	metric         TEXT    NOT NULL DEFAULT '',
+	resolution     TEXT    NOT NULL,
+	ts             INTEGER NOT NULL,
+	value_scaled   INTEGER NOT NULL,
+	scale_factor   INTEGER NOT NULL,
+	value_display  REAL    NOT NULL,
+	quality        TEXT    NOT NULL,
+	quality_reason TEXT    NOT NULL DEFAULT '',
+	confidence     REAL    NOT NULL DEFAULT 1,
+	source_type    TEXT    NOT NULL,
+	calibration    TEXT,
+	ingested_at    INTEGER NOT NULL,
+	priority       INTEGER NOT NULL DEFAULT 0,
+	withdrawal     TEXT,
+	provenance     TEXT,
+	version        INTEGER NOT NULL,
+	superseded_at  INTEGER NOT NULL,
+	PRIMARY KEY (device_id, metric, resolution, ts, version)
+) WITHOUT ROWID;
+INSERT INTO standard_reading_versions_legacy (device_id, metric, resolution, ts, value_scaled, scale_factor, value_display,
+	quality, quality_reason, confidence, source_type, calibration, ingested_at, priority, withdrawal, provenance, version, superseded_at)
+SELECT device_id, metric, resolution, ts, value_scaled, scale_factor, value_display,
+	quality, quality_reason, confidence, source_type, calibration, ingested_at, priority, withdrawal, provenance, version, superseded_at
+FROM standard_reading_versions;
+DROP TABLE standard_reading_versions;
+ALTER TABLE standard_reading_versions_legacy RENAME TO standard_reading_versions;
+
+CREATE TABLE standard_readings_legacy (
+	device_id      TEXT    NOT NULL,
+	metric         TEXT    NOT NULL DEFAULT '',
+	resolution     TEXT    NOT NULL,
+	ts             INTEGER NOT NULL,
+	value_scaled   INTEGER NOT NULL,
+	scale_factor   INTEGER NOT NULL,
+	value_display  REAL    NOT NULL,
+	quality        TEXT    NOT NULL,
+	quality_reason TEXT    NOT NULL DEFAULT '',
+	confidence     REAL    NOT NULL DEFAULT 1,
+	source_type    TEXT    NOT NULL,
+	calibration    TEXT,
+	ingested_at    INTEGER NOT NULL,
+	priority       INTEGER NOT NULL DEFAULT 0,
+	withdrawal     TEXT,
+	provenance     TEXT,
+	PRIMARY KEY (device_id, metric, resolution, ts)
+) WITHOUT ROWID;
+INSERT INTO standard_readings_legacy (device_id, metric, resolution, ts, value_scaled, scale_factor, value_display,
+	quality, quality_reason, confidence, source_type, calibration, ingested_at, priority, withdrawal, provenance)
+SELECT device_id, metric, resolution, ts, value_scaled, scale_factor, value_display,
+	quality, quality_reason, confidence, source_type, calibration, ingested_at, priority, withdrawal, provenance
+FROM standard_readings;
+DROP TABLE standard_readings;
+ALTER TABLE standard_readings_legacy RENAME TO standard_readings;
+CREATE INDEX IF NOT EXISTS standard_readings_device_ts ON standard_readings (device_id, ts);
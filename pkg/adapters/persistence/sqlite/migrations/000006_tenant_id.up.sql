@@ -0,0 +1,79 @@
+CREATE TABLE standard_readings_tenant (
+	tenant_id      TEXT    NOT NULL DEFAULT '',
+	device_id      TEXT    NOT NULL,
+	metric         TEXT    NOT NULL DEFAULT '',
+	resolution     TEXT    NOT NULL,
+	ts             INTEGER NOT NULL,
+	value_scaled   INTEGER NOT NULL,
+	scale_factor   INTEGER NOT NULL,
+	value_display  REAL    NOT NULL,
+	quality        TEXT    NOT NULL,
+	quality_reason TEXT    NOT NULL DEFAULT '',
+	confidence     REAL    NOT NULL DEFAULT 1,
+	source_type    TEXT    NOT NULL,
+	calibration    TEXT,
+	ingested_at    INTEGER NOT NULL,
+	priority       INTEGER NOT NULL DEFAULT 0,
+	withdrawal     TEXT,
+	provenance     TEXT,
+	PRIMARY KEY (tenant_id, device_id, metric, resolution, ts)
+) WITHOUT ROWID;
+INSERT INTO standard_readings_tenant (device_id, metric, resolution, ts, value_scaled, scale_factor, value_display,
+	quality, quality_reason, confidence, source_type, calibration, ingested_at, priority, withdrawal, provenance)
+SELECT device_id, metric, resolution, ts, value_scaled, scale_factor, value_display,
+	quality, quality_reason, confidence, source_type, calibration, ingested_at, priority, withdrawal, provenance
+FROM standard_readings;
+DROP TABLE standard_readings;
+ALTER TABLE standard_readings_tenant RENAME TO standard_readings;
+CREATE INDEX IF NOT EXISTS standard_readings_tenant_device_ts ON standard_readings (tenant_id, device_id, ts);
+
+CREATE TABLE standard_reading_versions_tenant (
+	tenant_id      TEXT    NOT NULL DEFAULT '',
+	device_id      TEXT    NOT NULL,
+	metric         TEXT    NOT NULL DEFAULT '',
+	resolution     TEXT    NOT NULL,
+	ts             INTEGER NOT NULL,
+	value_scaled   INTEGER NOT NULL,
+	scale_factor   INTEGER NOT NULL,
+	value_display  REAL    NOT NULL,
+	quality        TEXT    NOT NULL,
+	quality_reason TEXT    NOT NULL DEFAULT '',
+	confidence     REAL    NOT NULL DEFAULT 1,
+	source_type    TEXT    NOT NULL,
+	calibration    TEXT,
+	ingested_at    INTEGER NOT NULL,
+	priority       INTEGER NOT NULL DEFAULT 0,
+	withdrawal     TEXT,
+	provenance     TEXT,
+	version        INTEGER NOT NULL,
+	superseded_at  INTEGER NOT NULL,
+	PRIMARY KEY (tenant_id, device_id, metric, resolution, ts, version)
+) WITHOUT ROWID;
+INSERT INTO standard_reading_versions_tenant (device_id, metric, resolution, ts, value_scaled, scale_factor, value_display,
+	quality, quality_reason, confidence, source_type, calibration, ingested_at, priority, withdrawal, provenance, version, superseded_at)
+SELECT device_id, metric, resolution, ts, value_scaled, scale_factor, value_display,
+	quality, quality_reason, confidence, source_type, calibration, ingested_at, priority, withdrawal, provenance, version, superseded_at
+FROM standard_reading_versions;
+DROP TABLE standard_reading_versions;
+ALTER TABLE standard_reading_versions_tenant RENAME TO standard_reading_versions;
+
+CREATE TABLE raw_readings_tenant (
+	tenant_id   TEXT    NOT NULL DEFAULT '',
+	device_id   TEXT    NOT NULL,
+	metric      TEXT    NOT NULL DEFAULT '',
+	ts          INTEGER NOT NULL,
+	value       REAL,
+	body        TEXT    NOT NULL,
+	archived_at INTEGER NOT NULL,
+	PRIMARY KEY (tenant_id, device_id, metric, ts)
+) WITHOUT ROWID;
+INSERT INTO raw_readings_tenant (device_id, metric, ts, value, body, archived_at)
+SELECT device_id, metric, ts, value, body, archived_at FROM raw_readings;
+DROP TABLE raw_readings;
+ALTER TABLE raw_readings_tenant RENAME TO raw_readings;
+
+ALTER TABLE quarantine_readings ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '';
+DROP INDEX IF EXISTS quarantine_readings_status;
+DROP INDEX IF EXISTS quarantine_readings_device;
+CREATE INDEX IF NOT EXISTS quarantine_readings_tenant_status ON quarantine_readings (tenant_id, status, device_type, created_at);
+CREATE INDEX IF NOT EXISTS quarantine_readings_tenant_device ON quarantine_readings (tenant_id, device_id, ts);
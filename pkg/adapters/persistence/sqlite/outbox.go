@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// Outbox 实现 ports.Outbox，读取由 WithOutbox 写入的 outbox_messages 表 (需先调用 EnsureSchema)
+type Outbox struct {
+	db *sql.DB
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.Outbox        = (*Outbox)(nil)
+	_ ports.HealthChecker = (*Outbox)(nil)
+)
+
+// NewOutbox 创建发件箱
+func NewOutbox(db *sql.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+// HealthCheck 读取 outbox_messages 表验证存储可用
+func (o *Outbox) HealthCheck(ctx context.Context) error {
+	return checkTables(ctx, o.db, "outbox_messages")
+}
+
+// Pending 按 ID 升序返回至多 limit 条未发布的消息
+func (o *Outbox) Pending(ctx context.Context, limit int) ([]ports.OutboxMessage, error) {
+	rows, err := o.db.QueryContext(ctx,
+		"SELECT id, topic, msg_key, payload, created_at FROM outbox_messages ORDER BY id LIMIT ?", limitArg(limit))
+	if err != nil {
+		return nil, fmt.Errorf("query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ports.OutboxMessage
+	for rows.Next() {
+		var (
+			m         ports.OutboxMessage
+			payload   string
+			createdAt int64
+		)
+		if err := rows.Scan(&m.ID, &m.Topic, &m.Key, &payload, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan outbox message: %w", err)
+		}
+		m.Payload, m.CreatedAt = []byte(payload), fromNanos(createdAt)
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query outbox: %w", err)
+	}
+	return out, nil
+}
+
+// MarkPublished 删除已发布的消息
+func (o *Outbox) MarkPublished(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	if _, err := o.db.ExecContext(ctx, "DELETE FROM outbox_messages WHERE id IN ("+placeholders+")", args...); err != nil {
+		return fmt.Errorf("delete published outbox messages: %w", err)
+	}
+	return nil
+}
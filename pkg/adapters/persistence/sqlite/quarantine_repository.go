@@ -0,0 +1,250 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// QuarantineRepository 实现 ports.QuarantineRepository
+// 记录整体以 JSON 保存在 body 列，租户、设备、时间与状态另存一列用于过滤
+// 租户隔离: 保存时未设置租户的记录使用 ctx 的租户 (domain.WithTenant)，查询只返回 ctx 租户的记录
+type QuarantineRepository struct {
+	db *sql.DB
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.QuarantineRepository = (*QuarantineRepository)(nil)
+	_ ports.HealthChecker        = (*QuarantineRepository)(nil)
+)
+
+// NewQuarantineRepository 创建隔离区仓储 (需先调用 EnsureSchema)
+func NewQuarantineRepository(db *sql.DB) *QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+// HealthCheck 读取 quarantine_readings 表验证存储可用
+func (r *QuarantineRepository) HealthCheck(ctx context.Context) error {
+	return checkTables(ctx, r.db, "quarantine_readings")
+}
+
+// Save 保存一条隔离记录 (按 ID 新增或更新状态)
+// 清洗阶段产生的记录没有 ID，保存时生成随机 ID；ID 已属于其他租户时不做修改
+func (r *QuarantineRepository) Save(ctx context.Context, record domain.QuarantineReading) error {
+	if record.TenantID == "" {
+		record.TenantID = domain.TenantFromContext(ctx)
+	}
+	if record.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return err
+		}
+		record.ID = id
+	}
+	if record.Status == "" {
+		record.Status = domain.QuarantineStatusPending
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode quarantine record %s: %w", record.ID, err)
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO quarantine_readings (id, tenant_id, device_id, device_type, ts, status, created_at, body)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET status = excluded.status, body = excluded.body
+WHERE quarantine_readings.tenant_id = excluded.tenant_id`,
+		record.ID, record.TenantID, record.Reading.DeviceInfo.ID, string(record.Reading.DeviceInfo.Type), toNanos(record.Reading.Timestamp),
+		string(record.Status), toNanos(record.CreatedAt), string(body))
+	if err != nil {
+		return fmt.Errorf("save quarantine record %s: %w", record.ID, err)
+	}
+	return nil
+}
+
+// UpdateStatus 仅当记录的当前状态为 expected 时以 record 替换 (单条 UPDATE ... WHERE status = ? 完成比较与写入)
+func (r *QuarantineRepository) UpdateStatus(ctx context.Context, record domain.QuarantineReading, expected domain.QuarantineStatus) error {
+	tenantID := domain.TenantFromContext(ctx)
+	record.TenantID = tenantID
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode quarantine record %s: %w", record.ID, err)
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE quarantine_readings SET status = ?, body = ? WHERE id = ? AND tenant_id = ? AND status = ?`,
+		string(record.Status), string(body), record.ID, tenantID, string(expected))
+	if err != nil {
+		return fmt.Errorf("update quarantine record %s: %w", record.ID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("update quarantine record %s: %w", record.ID, err)
+	} else if n > 0 {
+		return nil
+	}
+	// 未更新: 区分记录不存在与状态已改变
+	var status string
+	err = r.db.QueryRowContext(ctx, `SELECT status FROM quarantine_readings WHERE id = ? AND tenant_id = ?`, record.ID, tenantID).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("quarantine record %s: %w", record.ID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("load quarantine record %s: %w", record.ID, err)
+	}
+	return fmt.Errorf("quarantine record %s is %s, expected %s: %w", record.ID, status, expected, ports.ErrQuarantineStatusChanged)
+}
+
+// FindPending 获取待处理的隔离记录 (按隔离时间排序，limit <= 0 表示不限制)
+func (r *QuarantineRepository) FindPending(ctx context.Context, limit int) ([]domain.QuarantineReading, error) {
+	return r.list(ctx, "AND status = ? ORDER BY created_at, id LIMIT ?",
+		string(domain.QuarantineStatusPending), limitArg(limit))
+}
+
+// FindPendingByDeviceType 获取某设备类型下待处理的隔离记录
+func (r *QuarantineRepository) FindPendingByDeviceType(ctx context.Context, deviceType domain.DeviceType, limit int) ([]domain.QuarantineReading, error) {
+	return r.list(ctx, "AND status = ? AND device_type = ? ORDER BY created_at, id LIMIT ?",
+		string(domain.QuarantineStatusPending), string(deviceType), limitArg(limit))
+}
+
+// FindByDevice 获取设备在 [start, end] 时间范围内的隔离记录 (任意状态，按读数时间排序)
+func (r *QuarantineRepository) FindByDevice(ctx context.Context, deviceID string, start, end time.Time) ([]domain.QuarantineReading, error) {
+	return r.list(ctx, "AND device_id = ? AND ts >= ? AND ts <= ? ORDER BY ts, id",
+		deviceID, toNanos(start), toNanos(end))
+}
+
+// Find 按条件查询隔离记录 (按隔离时间排序)
+// 规则ID、原因代码与原因只保存在 body 中，通过 json_extract 过滤；原因子串匹配仅对 ASCII 字母不区分大小写
+func (r *QuarantineRepository) Find(ctx context.Context, filter ports.QuarantineFilter) ([]domain.QuarantineReading, error) {
+	where, args := quarantineWhere(filter)
+	return r.list(ctx, where+" ORDER BY created_at, id LIMIT ? OFFSET ?", append(args, limitArg(filter.Limit), max(filter.Offset, 0))...)
+}
+
+// quarantineGroupColumns 分组维度对应的列表达式
+var quarantineGroupColumns = map[ports.QuarantineGroupBy]string{
+	ports.QuarantineGroupStatus:     "status",
+	ports.QuarantineGroupDevice:     "device_id",
+	ports.QuarantineGroupDeviceType: "device_type",
+	ports.QuarantineGroupRule:       "json_extract(body, '$.rule_id')",
+	ports.QuarantineGroupCode:       "json_extract(body, '$.code')",
+}
+
+// Count 统计满足条件的隔离记录数 (数据库内 GROUP BY)
+func (r *QuarantineRepository) Count(ctx context.Context, filter ports.QuarantineFilter, groupBy ports.QuarantineGroupBy) ([]ports.QuarantineCount, error) {
+	if err := groupBy.Validate(); err != nil {
+		return nil, err
+	}
+	where, args := quarantineWhere(filter)
+	args = append([]any{domain.TenantFromContext(ctx)}, args...)
+	where = "WHERE tenant_id = ? " + where
+	query := "SELECT '', COUNT(*) FROM quarantine_readings " + where
+	if col, ok := quarantineGroupColumns[groupBy]; ok {
+		query = fmt.Sprintf("SELECT COALESCE(%s, ''), COUNT(*) FROM quarantine_readings %s GROUP BY 1", col, where)
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("count quarantine records: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ports.QuarantineCount
+	for rows.Next() {
+		var c ports.QuarantineCount
+		if err := rows.Scan(&c.Key, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan quarantine count: %w", err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("count quarantine records: %w", err)
+	}
+	ports.SortQuarantineCounts(out)
+	return out, nil
+}
+
+// quarantineWhere 将过滤条件转换为接续租户条件的 AND 子句 (无条件时为空)
+func quarantineWhere(f ports.QuarantineFilter) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+	if f.ID != "" {
+		add("id = ?", f.ID)
+	}
+	if f.DeviceID != "" {
+		add("device_id = ?", f.DeviceID)
+	}
+	if f.DeviceType != "" {
+		add("device_type = ?", string(f.DeviceType))
+	}
+	if f.RuleID != "" {
+		add("json_extract(body, '$.rule_id') = ?", f.RuleID)
+	}
+	if f.Code != "" {
+		add("json_extract(body, '$.code') = ?", string(f.Code))
+	}
+	if f.Status != "" {
+		add("status = ?", string(f.Status))
+	}
+	if f.ReasonContains != "" {
+		add("instr(lower(json_extract(body, '$.reason')), lower(?)) > 0", f.ReasonContains)
+	}
+	if !f.Start.IsZero() {
+		add("ts >= ?", toNanos(f.Start))
+	}
+	if !f.End.IsZero() {
+		add("ts <= ?", toNanos(f.End))
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "AND " + strings.Join(conds, " AND "), args
+}
+
+// list 查询 ctx 租户下的隔离记录: clause 接续租户条件 (以 AND 开头的过滤条件) 与排序子句
+func (r *QuarantineRepository) list(ctx context.Context, clause string, args ...any) ([]domain.QuarantineReading, error) {
+	args = append([]any{domain.TenantFromContext(ctx)}, args...)
+	rows, err := r.db.QueryContext(ctx, "SELECT body FROM quarantine_readings WHERE tenant_id = ? "+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query quarantine records: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.QuarantineReading
+	for rows.Next() {
+		var body string
+		if err := rows.Scan(&body); err != nil {
+			return nil, fmt.Errorf("scan quarantine record: %w", err)
+		}
+		var q domain.QuarantineReading
+		if err := json.Unmarshal([]byte(body), &q); err != nil {
+			return nil, fmt.Errorf("decode quarantine record: %w", err)
+		}
+		out = append(out, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query quarantine records: %w", err)
+	}
+	return out, nil
+}
+
+// newID 生成 16 字节随机 ID (十六进制)
+func newID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate quarantine id: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// RawReadingRepository 实现 ports.RawReadingRepository
+// 读数整体以 JSON 保存在 body 列；数值另存于可空的 value 列 (缺失值 NaN 无法以 JSON 表示，存为 NULL)
+type RawReadingRepository struct {
+	db *sql.DB
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.RawReadingRepository = (*RawReadingRepository)(nil)
+	_ ports.HealthChecker        = (*RawReadingRepository)(nil)
+)
+
+// NewRawReadingRepository 创建原始读数仓储 (需先调用 EnsureSchema)
+func NewRawReadingRepository(db *sql.DB) *RawReadingRepository {
+	return &RawReadingRepository{db: db}
+}
+
+// HealthCheck 读取 raw_readings 表验证存储可用
+func (r *RawReadingRepository) HealthCheck(ctx context.Context) error {
+	return checkTables(ctx, r.db, "raw_readings")
+}
+
+// Save 在一个事务内逐条 upsert (同一计量通道同一时间戳以最后写入的为准)
+// 未设置租户的读数使用 ctx 的租户 (domain.WithTenant)
+func (r *RawReadingRepository) Save(ctx context.Context, readings []domain.Reading) error {
+	if len(readings) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // 提交后 Rollback 为空操作
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO raw_readings (tenant_id, device_id, metric, ts, value, body, archived_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (tenant_id, device_id, metric, ts) DO UPDATE SET value = excluded.value, body = excluded.body, archived_at = excluded.archived_at`)
+	if err != nil {
+		return fmt.Errorf("prepare raw reading upsert: %w", err)
+	}
+	defer stmt.Close()
+	now := time.Now().UnixNano()
+	tenantID := domain.TenantFromContext(ctx)
+	for _, rd := range readings {
+		if rd.DeviceInfo.TenantID == "" {
+			rd.DeviceInfo.TenantID = tenantID
+		}
+		value, body, err := encodeRaw(rd)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, rd.DeviceInfo.TenantID, rd.DeviceInfo.ID, string(rd.Metric), toNanos(rd.Timestamp), value, body, now); err != nil {
+			return fmt.Errorf("save raw reading %s@%s: %w", rd.DeviceInfo.ID, rd.Timestamp.Format(time.RFC3339), err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit raw readings: %w", err)
+	}
+	return nil
+}
+
+// FindRange 获取 ctx 租户下设备在 [start, end] 时间范围内的原始读数 (全部通道)，按时间排序
+func (r *RawReadingRepository) FindRange(ctx context.Context, deviceID string, start, end time.Time) ([]domain.Reading, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT value, body FROM raw_readings WHERE tenant_id = ? AND device_id = ? AND ts >= ? AND ts <= ? ORDER BY ts, metric",
+		domain.TenantFromContext(ctx), deviceID, toNanos(start), toNanos(end))
+	if err != nil {
+		return nil, fmt.Errorf("query raw readings: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Reading
+	for rows.Next() {
+		var (
+			value sql.NullFloat64
+			body  string
+		)
+		if err := rows.Scan(&value, &body); err != nil {
+			return nil, fmt.Errorf("scan raw reading: %w", err)
+		}
+		rd, err := decodeRaw(value, body)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rd)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query raw readings: %w", err)
+	}
+	return out, nil
+}
+
+// encodeRaw 拆分原始读数: 数值 (NaN / ±Inf 为 NULL) 与其余字段的 JSON
+func encodeRaw(rd domain.Reading) (any, string, error) {
+	var value any // NULL 表示缺失值
+	if !math.IsNaN(rd.Value) && !math.IsInf(rd.Value, 0) {
+		value = rd.Value
+	}
+	rd.Value = 0
+	body, err := json.Marshal(rd)
+	if err != nil {
+		return nil, "", fmt.Errorf("encode raw reading of %s: %w", rd.DeviceInfo.ID, err)
+	}
+	return value, string(body), nil
+}
+
+// decodeRaw 还原 encodeRaw 拆分的原始读数
+func decodeRaw(value sql.NullFloat64, body string) (domain.Reading, error) {
+	var rd domain.Reading
+	if err := json.Unmarshal([]byte(body), &rd); err != nil {
+		return rd, fmt.Errorf("decode raw reading: %w", err)
+	}
+	rd.Value = math.NaN()
+	if value.Valid {
+		rd.Value = value.Float64
+	}
+	return rd, nil
+}
@@ -0,0 +1,238 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// CleaningRuleRepository 实现 ports.CleaningRuleRepository
+// 规则整体以 JSON 保存在 body 列，设备类型、启用状态与优先级另存一列用于过滤和排序
+type CleaningRuleRepository struct {
+	db            *sql.DB
+	watchInterval time.Duration
+	logger        *slog.Logger // Watch 轮询失败的日志输出 (nil 使用 slog.Default())
+}
+
+// DefaultRuleWatchInterval Watch 轮询规则表的默认间隔
+const DefaultRuleWatchInterval = 2 * time.Second
+
+// RuleOption 配置 CleaningRuleRepository
+type RuleOption func(*CleaningRuleRepository)
+
+// WithRuleWatchInterval 设置 Watch 轮询规则表的间隔 (<= 0 使用 DefaultRuleWatchInterval)
+func WithRuleWatchInterval(d time.Duration) RuleOption {
+	return func(r *CleaningRuleRepository) {
+		if d > 0 {
+			r.watchInterval = d
+		}
+	}
+}
+
+// WithRuleLogger 设置 Watch 轮询失败时的日志输出 (默认使用 slog.Default())
+func WithRuleLogger(logger *slog.Logger) RuleOption {
+	return func(r *CleaningRuleRepository) {
+		r.logger = logger
+	}
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.CleaningRuleRepository = (*CleaningRuleRepository)(nil)
+	_ ports.HealthChecker          = (*CleaningRuleRepository)(nil)
+)
+
+// NewCleaningRuleRepository 创建清洗规则仓储 (需先调用 EnsureSchema)
+func NewCleaningRuleRepository(db *sql.DB, opts ...RuleOption) *CleaningRuleRepository {
+	r := &CleaningRuleRepository{db: db, watchInterval: DefaultRuleWatchInterval}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Save 保存或更新规则
+func (r *CleaningRuleRepository) Save(ctx context.Context, rule domain.CleaningRule) error {
+	if rule.ID == "" {
+		return errors.New("save cleaning rule: empty id")
+	}
+	body, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("encode cleaning rule %s: %w", rule.ID, err)
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO cleaning_rules (id, device_type, enabled, priority, body) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	device_type = excluded.device_type, enabled = excluded.enabled, priority = excluded.priority, body = excluded.body`,
+		rule.ID, string(rule.DeviceType), rule.Enabled, int64(rule.Priority), string(body))
+	if err != nil {
+		return fmt.Errorf("save cleaning rule %s: %w", rule.ID, err)
+	}
+	return nil
+}
+
+// HealthCheck 读取 cleaning_rules 表验证存储可用
+func (r *CleaningRuleRepository) HealthCheck(ctx context.Context) error {
+	return checkTables(ctx, r.db, "cleaning_rules")
+}
+
+// GetByID 获取指定规则
+func (r *CleaningRuleRepository) GetByID(ctx context.Context, id string) (*domain.CleaningRule, error) {
+	rules, err := r.list(ctx, "WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, ports.ErrNotFound
+	}
+	return &rules[0], nil
+}
+
+// ListByDeviceType 获取适用于特定设备类型的所有规则 (按优先级排序)
+func (r *CleaningRuleRepository) ListByDeviceType(ctx context.Context, deviceType domain.DeviceType) ([]domain.CleaningRule, error) {
+	return r.list(ctx, "WHERE device_type = ? ORDER BY priority, id", string(deviceType))
+}
+
+// ListEnabledByDeviceType 获取特定设备类型下所有启用的规则 (按优先级排序)
+func (r *CleaningRuleRepository) ListEnabledByDeviceType(ctx context.Context, deviceType domain.DeviceType) ([]domain.CleaningRule, error) {
+	return r.list(ctx, "WHERE device_type = ? AND enabled = 1 ORDER BY priority, id", string(deviceType))
+}
+
+// Delete 删除规则 (不存在时为空操作)
+func (r *CleaningRuleRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM cleaning_rules WHERE id = ?", id); err != nil {
+		return fmt.Errorf("delete cleaning rule %s: %w", id, err)
+	}
+	return nil
+}
+
+// Watch 定期轮询规则表并与上一次快照比较，发出新增、更新与删除事件；ctx 取消后关闭通道
+// SQLite 没有变更通知，轮询也能发现其他进程 (如管理后台) 直接写入的规则；轮询失败时记录日志并在下一周期重试
+func (r *CleaningRuleRepository) Watch(ctx context.Context) (<-chan ports.RuleChangeEvent, error) {
+	snapshot, err := r.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan ports.RuleChangeEvent)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(r.watchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			next, err := r.snapshot(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger := r.logger
+					if logger == nil {
+						logger = slog.Default()
+					}
+					logger.Warn("poll cleaning rules failed", "error", err)
+				}
+				continue
+			}
+			for _, event := range diffRules(snapshot, next, time.Now()) {
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			snapshot = next
+		}
+	}()
+	return out, nil
+}
+
+// ruleVersion 规则快照中的一条记录
+type ruleVersion struct {
+	deviceType domain.DeviceType
+	body       string
+}
+
+// snapshot 读取全部规则的当前版本
+func (r *CleaningRuleRepository) snapshot(ctx context.Context) (map[string]ruleVersion, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, device_type, body FROM cleaning_rules")
+	if err != nil {
+		return nil, fmt.Errorf("query cleaning rules: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]ruleVersion)
+	for rows.Next() {
+		var (
+			id         string
+			deviceType string
+			v          ruleVersion
+		)
+		if err := rows.Scan(&id, &deviceType, &v.body); err != nil {
+			return nil, fmt.Errorf("scan cleaning rule: %w", err)
+		}
+		v.deviceType = domain.DeviceType(deviceType)
+		out[id] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query cleaning rules: %w", err)
+	}
+	return out, nil
+}
+
+// diffRules 比较两次快照，返回按规则ID排序的变更事件
+func diffRules(prev, next map[string]ruleVersion, at time.Time) []ports.RuleChangeEvent {
+	var events []ports.RuleChangeEvent
+	for id, v := range next {
+		old, ok := prev[id]
+		if ok && old == v {
+			continue
+		}
+		event := ports.RuleChangeEvent{Type: ports.RuleChangeSaved, RuleID: id, DeviceType: v.deviceType, At: at}
+		if ok && old.deviceType != v.deviceType {
+			event.PreviousDeviceType = old.deviceType
+		}
+		events = append(events, event)
+	}
+	for id, old := range prev {
+		if _, ok := next[id]; !ok {
+			events = append(events, ports.RuleChangeEvent{Type: ports.RuleChangeDeleted, RuleID: id, DeviceType: old.deviceType, At: at})
+		}
+	}
+	slices.SortFunc(events, func(a, b ports.RuleChangeEvent) int { return strings.Compare(a.RuleID, b.RuleID) })
+	return events
+}
+
+func (r *CleaningRuleRepository) list(ctx context.Context, clause string, args ...any) ([]domain.CleaningRule, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT body FROM cleaning_rules "+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query cleaning rules: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.CleaningRule
+	for rows.Next() {
+		var body string
+		if err := rows.Scan(&body); err != nil {
+			return nil, fmt.Errorf("scan cleaning rule: %w", err)
+		}
+		var rule domain.CleaningRule
+		if err := json.Unmarshal([]byte(body), &rule); err != nil {
+			return nil, fmt.Errorf("decode cleaning rule: %w", err)
+		}
+		out = append(out, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query cleaning rules: %w", err)
+	}
+	return out, nil
+}
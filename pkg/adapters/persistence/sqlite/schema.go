@@ -0,0 +1,157 @@
+// Package sqlite 提供基于 SQLite 的嵌入式仓储实现 (标准读数及其历史版本、原始读数、清洗规则、隔离区、审计日志)
+// 适用于边缘网关与演示环境: 无需数据库服务，单个文件即可保存全部数据。
+// 本包只依赖标准库 database/sql，驱动由调用方注册 (如纯 Go 实现的 modernc.org/sqlite，无需 CGO)。
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// schema 全部表的 DDL
+// 时间统一以 UTC Unix 纳秒 (INTEGER) 存储，保证排序与区间查询正确
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS standard_readings (
+	tenant_id      TEXT    NOT NULL DEFAULT '',
+	device_id      TEXT    NOT NULL,
+	metric         TEXT    NOT NULL DEFAULT '',
+	resolution     TEXT    NOT NULL,
+	ts             INTEGER NOT NULL,
+	value_scaled   INTEGER NOT NULL,
+	scale_factor   INTEGER NOT NULL,
+	value_display  REAL    NOT NULL,
+	quality        TEXT    NOT NULL,
+	quality_reason TEXT    NOT NULL DEFAULT '',
+	confidence     REAL    NOT NULL DEFAULT 1,
+	source_type    TEXT    NOT NULL,
+	calibration    TEXT,
+	ingested_at    INTEGER NOT NULL,
+	priority       INTEGER NOT NULL DEFAULT 0,
+	withdrawal     TEXT,
+	provenance     TEXT,
+	PRIMARY KEY (tenant_id, device_id, metric, resolution, ts)
+) WITHOUT ROWID`,
+	`CREATE INDEX IF NOT EXISTS standard_readings_tenant_device_ts ON standard_readings (tenant_id, device_id, ts)`,
+	`CREATE TABLE IF NOT EXISTS standard_reading_batches (
+	idempotency_key TEXT    PRIMARY KEY,
+	applied_at      INTEGER NOT NULL
+)`,
+	`CREATE TABLE IF NOT EXISTS outbox_messages (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	topic      TEXT    NOT NULL,
+	msg_key    TEXT    NOT NULL,
+	payload    TEXT    NOT NULL,
+	created_at INTEGER NOT NULL
+)`,
+	`CREATE TABLE IF NOT EXISTS cleaning_rules (
+	id          TEXT    PRIMARY KEY,
+	device_type TEXT    NOT NULL,
+	enabled     INTEGER NOT NULL,
+	priority    INTEGER NOT NULL,
+	body        TEXT    NOT NULL
+)`,
+	`CREATE INDEX IF NOT EXISTS cleaning_rules_device_type ON cleaning_rules (device_type, priority)`,
+	`CREATE TABLE IF NOT EXISTS quarantine_readings (
+	id          TEXT    PRIMARY KEY,
+	tenant_id   TEXT    NOT NULL DEFAULT '',
+	device_id   TEXT    NOT NULL,
+	device_type TEXT    NOT NULL,
+	ts          INTEGER NOT NULL,
+	status      TEXT    NOT NULL,
+	created_at  INTEGER NOT NULL,
+	body        TEXT    NOT NULL
+)`,
+	`CREATE INDEX IF NOT EXISTS quarantine_readings_tenant_status ON quarantine_readings (tenant_id, status, device_type, created_at)`,
+	`CREATE INDEX IF NOT EXISTS quarantine_readings_tenant_device ON quarantine_readings (tenant_id, device_id, ts)`,
+	`CREATE TABLE IF NOT EXISTS standard_reading_audit (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	device_id  TEXT    NOT NULL,
+	ts         INTEGER NOT NULL,
+	changed_at INTEGER NOT NULL,
+	body       TEXT    NOT NULL
+)`,
+	`CREATE INDEX IF NOT EXISTS standard_reading_audit_device ON standard_reading_audit (device_id, ts)`,
+	`CREATE TABLE IF NOT EXISTS standard_reading_versions (
+	tenant_id      TEXT    NOT NULL DEFAULT '',
+	device_id      TEXT    NOT NULL,
+	metric         TEXT    NOT NULL DEFAULT '',
+	resolution     TEXT    NOT NULL,
+	ts             INTEGER NOT NULL,
+	value_scaled   INTEGER NOT NULL,
+	scale_factor   INTEGER NOT NULL,
+	value_display  REAL    NOT NULL,
+	quality        TEXT    NOT NULL,
+	quality_reason TEXT    NOT NULL DEFAULT '',
+	confidence     REAL    NOT NULL DEFAULT 1,
+	source_type    TEXT    NOT NULL,
+	calibration    TEXT,
+	ingested_at    INTEGER NOT NULL,
+	priority       INTEGER NOT NULL DEFAULT 0,
+	withdrawal     TEXT,
+	provenance     TEXT,
+	version        INTEGER NOT NULL,
+	superseded_at  INTEGER NOT NULL,
+	PRIMARY KEY (tenant_id, device_id, metric, resolution, ts, version)
+) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS raw_readings (
+	tenant_id   TEXT    NOT NULL DEFAULT '',
+	device_id   TEXT    NOT NULL,
+	metric      TEXT    NOT NULL DEFAULT '',
+	ts          INTEGER NOT NULL,
+	value       REAL,
+	body        TEXT    NOT NULL,
+	archived_at INTEGER NOT NULL,
+	PRIMARY KEY (tenant_id, device_id, metric, ts)
+) WITHOUT ROWID`,
+}
+
+// EnsureSchema 创建全部表与索引 (已存在时跳过)
+func EnsureSchema(ctx context.Context, db *sql.DB) error {
+	for _, stmt := range schema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create sqlite schema failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkTables 依次读取各表的一行，验证数据库可达且表已创建、可读
+func checkTables(ctx context.Context, db *sql.DB, tables ...string) error {
+	for _, table := range tables {
+		rows, err := db.QueryContext(ctx, "SELECT 1 FROM "+table+" LIMIT 1")
+		if err != nil {
+			return fmt.Errorf("health check %s: %w", table, err)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("health check %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// toNanos 将时间编码为 UTC Unix 纳秒 (零值编码为 0)
+func toNanos(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromNanos 解码 toNanos 的结果
+func fromNanos(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
+
+// limitArg 将 limit <= 0 转换为 SQLite 的 "不限制" (LIMIT -1)
+func limitArg(limit int) int64 {
+	if limit <= 0 {
+		return -1
+	}
+	return int64(limit)
+}
@@ -0,0 +1,591 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+const standardColumns = `tenant_id, device_id, metric, resolution, ts, value_scaled, scale_factor, value_display,
+	quality, quality_reason, confidence, source_type, calibration, ingested_at, priority, withdrawal, provenance`
+
+// upsertStandardSQL 按唯一键 upsert；HIGH_PRIORITY_WINS 追加 priorityGuard
+const upsertStandardSQL = `INSERT INTO standard_readings (` + standardColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (tenant_id, device_id, metric, resolution, ts) DO UPDATE SET
+	value_scaled = excluded.value_scaled, scale_factor = excluded.scale_factor, value_display = excluded.value_display,
+	quality = excluded.quality, quality_reason = excluded.quality_reason, confidence = excluded.confidence,
+	source_type = excluded.source_type, calibration = excluded.calibration,
+	ingested_at = excluded.ingested_at, priority = excluded.priority, withdrawal = excluded.withdrawal,
+	provenance = excluded.provenance`
+
+// priorityGuard 只有新数据 (excluded) 的优先级 >= 库中数据时才更新
+const priorityGuard = `
+WHERE excluded.priority >= standard_readings.priority`
+
+// archiveStandardSQL 把即将被覆盖的当前行复制到 standard_reading_versions (版本号顺延)
+// 只有覆盖会生效 (HIGH_PRIORITY_WINS 追加 archivePriorityGuard) 且内容有变化时才保留；重放相同数据不产生新版本
+const archiveStandardSQL = `INSERT INTO standard_reading_versions (` + standardColumns + `, version, superseded_at)
+SELECT ` + standardColumns + `, (
+	SELECT COALESCE(MAX(v.version), 0) + 1 FROM standard_reading_versions v
+	WHERE v.tenant_id = standard_readings.tenant_id AND v.device_id = standard_readings.device_id
+		AND v.metric = standard_readings.metric AND v.resolution = standard_readings.resolution AND v.ts = standard_readings.ts), ?
+FROM standard_readings
+WHERE tenant_id = ? AND device_id = ? AND metric = ? AND resolution = ? AND ts = ?
+	AND (value_scaled <> ? OR scale_factor <> ? OR quality <> ? OR priority <> ?)`
+
+// archivePriorityGuard 与 priorityGuard 对应: 新数据优先级低于库中数据时不会覆盖，也就无需保留
+const archivePriorityGuard = `
+	AND ? >= priority`
+
+// Option 配置 StandardReadingRepository
+type Option func(*StandardReadingRepository)
+
+// WithOutbox 启用事务性发件箱: Save / SaveBatch 在同一事务内把实际生效的读数写入 outbox_messages 表
+// topic 为空使用 ports.DefaultOutboxTopic；消息由 NewOutbox + services.OutboxRelay 发布
+func WithOutbox(topic string) Option {
+	return func(r *StandardReadingRepository) {
+		r.outbox, r.outboxTopic = true, topic
+	}
+}
+
+// WithVersioning 启用版本保留: Save / SaveBatch 在同一事务内先把将被替换的读数复制到 standard_reading_versions
+// 历史版本通过 FindVersions 查询 (见 ports.StandardReadingVersioner)
+func WithVersioning() Option {
+	return func(r *StandardReadingRepository) {
+		r.versioning = true
+	}
+}
+
+// StandardReadingRepository 实现 ports.StandardReadingRepository
+type StandardReadingRepository struct {
+	db          *sql.DB
+	outbox      bool
+	outboxTopic string
+	versioning  bool
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.StandardReadingRepository  = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingRetention   = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingPager       = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingMultiFinder = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingAggregator  = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingWithdrawer  = (*StandardReadingRepository)(nil)
+	_ ports.StandardReadingVersioner   = (*StandardReadingRepository)(nil)
+	_ ports.HealthChecker              = (*StandardReadingRepository)(nil)
+)
+
+// NewStandardReadingRepository 创建标准读数仓储 (需先调用 EnsureSchema)
+func NewStandardReadingRepository(db *sql.DB, opts ...Option) *StandardReadingRepository {
+	r := &StandardReadingRepository{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// HealthCheck 读取标准读数表 (启用发件箱时还有 outbox_messages) 验证存储可用
+func (r *StandardReadingRepository) HealthCheck(ctx context.Context) error {
+	tables := []string{"standard_readings"}
+	if r.outbox {
+		tables = append(tables, "outbox_messages")
+	}
+	return checkTables(ctx, r.db, tables...)
+}
+
+// Save 保存单个标准读数
+func (r *StandardReadingRepository) Save(ctx context.Context, reading domain.StandardReading, strategy ports.UpsertStrategy) error {
+	query, err := upsertSQL(strategy)
+	if err != nil {
+		return err
+	}
+	args, err := standardArgs(withTenant(ctx, reading))
+	if err != nil {
+		return err
+	}
+	if !r.outbox && !r.versioning {
+		if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("save standard reading %s@%s: %w", reading.DeviceID, reading.Timestamp.Format(time.RFC3339), err)
+		}
+		return nil
+	}
+	return r.SaveBatch(ctx, []domain.StandardReading{reading}, strategy, "")
+}
+
+// SaveBatch 在一个事务内逐条 upsert (预编译语句)
+// 批次内的重复键按顺序应用，结果与逐条调用 Save 一致；幂等键与数据同事务写入。
+func (r *StandardReadingRepository) SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, idempotencyKey string) error {
+	query, err := upsertSQL(strategy)
+	if err != nil {
+		return err
+	}
+	if len(readings) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // 提交后 Rollback 为空操作
+
+	if idempotencyKey != "" {
+		res, err := tx.ExecContext(ctx,
+			"INSERT OR IGNORE INTO standard_reading_batches (idempotency_key, applied_at) VALUES (?, ?)",
+			idempotencyKey, time.Now().UnixNano())
+		if err != nil {
+			return fmt.Errorf("record idempotency key: %w", err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return nil // 已应用的批次
+		}
+	}
+
+	if r.outbox {
+		// RETURNING 只返回实际写入的行: priorityGuard 不满足时不返回
+		query += "\nRETURNING " + standardColumns
+	}
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare upsert: %w", err)
+	}
+	defer stmt.Close()
+	archive, err := r.prepareArchive(ctx, tx, strategy)
+	if err != nil {
+		return err
+	}
+	if archive != nil {
+		defer archive.Close()
+	}
+	supersededAt := time.Now().UnixNano()
+	var applied []domain.StandardReading
+	for _, sr := range readings {
+		args, err := standardArgs(withTenant(ctx, sr))
+		if err != nil {
+			return err
+		}
+		if archive != nil {
+			// 与 upsert 同序执行: 批次内的重复键依次生成版本
+			if _, err := archive.ExecContext(ctx, archiveArgs(args, supersededAt, strategy)...); err != nil {
+				return fmt.Errorf("archive standard reading %s@%s: %w", sr.DeviceID, sr.Timestamp.Format(time.RFC3339), err)
+			}
+		}
+		if !r.outbox {
+			if _, err := stmt.ExecContext(ctx, args...); err != nil {
+				return fmt.Errorf("save standard reading %s@%s: %w", sr.DeviceID, sr.Timestamp.Format(time.RFC3339), err)
+			}
+			continue
+		}
+		rows, err := stmt.QueryContext(ctx, args...)
+		if err != nil {
+			return fmt.Errorf("save standard reading %s@%s: %w", sr.DeviceID, sr.Timestamp.Format(time.RFC3339), err)
+		}
+		written, err := scanStandards(rows)
+		if err != nil {
+			return fmt.Errorf("save standard reading %s@%s: %w", sr.DeviceID, sr.Timestamp.Format(time.RFC3339), err)
+		}
+		applied = append(applied, written...)
+	}
+	if err := r.writeOutbox(ctx, tx, applied); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit standard readings: %w", err)
+	}
+	return nil
+}
+
+// prepareArchive 预编译版本保留语句 (未启用 WithVersioning 时返回 nil)
+func (r *StandardReadingRepository) prepareArchive(ctx context.Context, tx *sql.Tx, strategy ports.UpsertStrategy) (*sql.Stmt, error) {
+	if !r.versioning {
+		return nil, nil
+	}
+	query := archiveStandardSQL
+	if strategy == ports.UpsertStrategyHighPriorityWins {
+		query += archivePriorityGuard
+	}
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare archive: %w", err)
+	}
+	return stmt, nil
+}
+
+// archiveArgs 从 standardArgs 的结果中取出 archiveStandardSQL 所需的参数
+func archiveArgs(args []any, supersededAt int64, strategy ports.UpsertStrategy) []any {
+	out := []any{supersededAt, args[0], args[1], args[2], args[3], args[4], args[5], args[6], args[8], args[14]}
+	if strategy == ports.UpsertStrategyHighPriorityWins {
+		out = append(out, args[14])
+	}
+	return out
+}
+
+// withTenant 为未设置租户的读数补全 ctx 的租户
+func withTenant(ctx context.Context, sr domain.StandardReading) domain.StandardReading {
+	if sr.TenantID == "" {
+		sr.TenantID = domain.TenantFromContext(ctx)
+	}
+	return sr
+}
+
+// writeOutbox 在 tx 内把生效的读数写入发件箱 (未启用时为空操作)
+func (r *StandardReadingRepository) writeOutbox(ctx context.Context, tx *sql.Tx, applied []domain.StandardReading) error {
+	msgs, err := ports.StandardOutboxMessages(r.outboxTopic, applied)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixNano()
+	for _, m := range msgs {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO outbox_messages (topic, msg_key, payload, created_at) VALUES (?, ?, ?, ?)",
+			m.Topic, m.Key, string(m.Payload), now); err != nil {
+			return fmt.Errorf("write outbox message: %w", err)
+		}
+	}
+	return nil
+}
+
+// FindExact 获取设备默认通道在 timestamp 时间点的标准读数 (多个分辨率时按分辨率标签取第一条)
+func (r *StandardReadingRepository) FindExact(ctx context.Context, deviceID string, timestamp time.Time) (*domain.StandardReading, error) {
+	return r.findOne(ctx, "AND device_id = ? AND metric = '' AND ts = ? ORDER BY resolution LIMIT 1",
+		deviceID, toNanos(timestamp))
+}
+
+// FindRange 获取设备在 [start, end] 内的标准读数 (全部通道与分辨率)，按时间排序
+func (r *StandardReadingRepository) FindRange(ctx context.Context, deviceID string, start, end time.Time) ([]domain.StandardReading, error) {
+	return r.query(ctx, "AND device_id = ? AND ts >= ? AND ts <= ? ORDER BY ts, metric, resolution",
+		deviceID, toNanos(start), toNanos(end))
+}
+
+// FindRangePaged 按 (ts, metric, resolution) 升序 keyset 分页 (不随页码增大而变慢)
+func (r *StandardReadingRepository) FindRangePaged(ctx context.Context, deviceID string, start, end time.Time, page ports.PageRequest) (ports.StandardPage, error) {
+	limit := page.PageSize()
+	clause := "AND device_id = ? AND ts >= ? AND ts <= ?"
+	args := []any{deviceID, toNanos(start), toNanos(end)}
+	if page.Cursor != "" {
+		c, err := ports.DecodeCursor(page.Cursor)
+		if err != nil {
+			return ports.StandardPage{}, err
+		}
+		clause += " AND (ts, metric, resolution) > (?, ?, ?)"
+		args = append(args, toNanos(c.Timestamp), string(c.Metric), c.Resolution)
+	}
+	clause += " ORDER BY ts, metric, resolution LIMIT ?"
+	args = append(args, int64(limit+1))
+
+	rows, err := r.query(ctx, clause, args...)
+	if err != nil {
+		return ports.StandardPage{}, err
+	}
+	return ports.PageOf(rows, limit), nil
+}
+
+// multiQueryDevices FindRangeMulti 单条查询最多包含的设备数 (低于旧版 SQLite 999 个绑定参数的上限)
+const multiQueryDevices = 500
+
+// FindRangeMulti 获取多个设备在 [start, end] 内的标准读数，按设备分组
+func (r *StandardReadingRepository) FindRangeMulti(ctx context.Context, deviceIDs []string, start, end time.Time) (map[string][]domain.StandardReading, error) {
+	ids := slices.Compact(slices.Sorted(slices.Values(deviceIDs)))
+	out := make(map[string][]domain.StandardReading, len(ids))
+	for chunk := range slices.Chunk(ids, multiQueryDevices) {
+		args := []any{toNanos(start), toNanos(end)}
+		for _, id := range chunk {
+			args = append(args, id)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(chunk)), ", ")
+		rows, err := r.query(ctx, "AND ts >= ? AND ts <= ? AND device_id IN ("+placeholders+") ORDER BY device_id, ts, metric, resolution", args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, sr := range rows {
+			out[sr.DeviceID] = append(out[sr.DeviceID], sr)
+		}
+	}
+	return out, nil
+}
+
+// aggregateSlot 数据库内预聚合的时间片 (15 分钟)
+// SQLite 没有时区数据库，无法按当地日历切分周期: 先在库内按 15 分钟片求和 (现行时区偏移均为 15 分钟的整数倍)，
+// 再由 ports.MergeBuckets 归并到查询周期
+const aggregateSlot = 15 * time.Minute
+
+// SumByPeriod 按周期求和
+func (r *StandardReadingRepository) SumByPeriod(ctx context.Context, q ports.AggregateQuery) ([]ports.AggregateBucket, error) {
+	return r.aggregate(ctx, q, false)
+}
+
+// AvgByPeriod 按周期求平均值
+func (r *StandardReadingRepository) AvgByPeriod(ctx context.Context, q ports.AggregateQuery) ([]ports.AggregateBucket, error) {
+	return r.aggregate(ctx, q, true)
+}
+
+func (r *StandardReadingRepository) aggregate(ctx context.Context, q ports.AggregateQuery, avg bool) ([]ports.AggregateBucket, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	slot := int64(aggregateSlot)
+	rows, err := r.db.QueryContext(ctx, `SELECT ts - (ts % ?) AS slot, SUM(value_display), COUNT(*) FROM standard_readings
+WHERE tenant_id = ? AND device_id = ? AND metric = ? AND resolution = ? AND ts >= ? AND ts < ? AND quality NOT IN (?, ?)
+GROUP BY slot ORDER BY slot`,
+		slot, domain.TenantFromContext(ctx), q.DeviceID, string(q.Metric), q.Resolution, toNanos(q.Start), toNanos(q.End),
+		string(domain.QualityMissing), string(domain.QualityWithdrawn))
+	if err != nil {
+		return nil, fmt.Errorf("aggregate standard readings: %w", err)
+	}
+	defer rows.Close()
+
+	var partials []ports.AggregateBucket
+	for rows.Next() {
+		var (
+			start int64
+			p     ports.AggregateBucket
+		)
+		if err := rows.Scan(&start, &p.Value, &p.Count); err != nil {
+			return nil, fmt.Errorf("scan aggregate: %w", err)
+		}
+		p.Start = fromNanos(start)
+		partials = append(partials, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("aggregate standard readings: %w", err)
+	}
+	return ports.MergeBuckets(q, partials, avg), nil
+}
+
+// Withdraw 将满足条件的标准读数标记为已撤回，返回本次新撤回的读数
+// 单条 UPDATE ... RETURNING 完成 (需 SQLite 3.35+): 撤回记录由 json_object 生成，原质量标记取自更新前的行
+func (r *StandardReadingRepository) Withdraw(ctx context.Context, req ports.WithdrawRequest) ([]domain.StandardReading, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	at := req.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	rows, err := r.db.QueryContext(ctx, `UPDATE standard_readings SET quality = ?,
+	withdrawal = json_object('reason', ?, 'operator', ?, 'at', ?, 'previous_quality', quality)
+WHERE tenant_id = ? AND device_id = ? AND metric = ? AND (? = '' OR resolution = ?) AND ts >= ? AND ts <= ? AND withdrawal IS NULL
+RETURNING `+standardColumns,
+		string(domain.QualityWithdrawn), req.Reason, req.Operator, at.UTC().Format(time.RFC3339Nano),
+		domain.TenantFromContext(ctx), req.DeviceID, string(req.Metric), req.Resolution, req.Resolution, toNanos(req.Start), toNanos(req.End))
+	if err != nil {
+		return nil, fmt.Errorf("withdraw standard readings of %s: %w", req.DeviceID, err)
+	}
+	out, err := scanStandards(rows)
+	if err != nil {
+		return nil, fmt.Errorf("withdraw standard readings of %s: %w", req.DeviceID, err)
+	}
+	slices.SortFunc(out, func(a, b domain.StandardReading) int { return a.Timestamp.Compare(b.Timestamp) })
+	return out, nil
+}
+
+// FindVersions 返回设备在 timestamp 时间点的全部版本: 历史版本来自 standard_reading_versions，当前版本的版本号顺延
+func (r *StandardReadingRepository) FindVersions(ctx context.Context, deviceID string, timestamp time.Time) ([]domain.StandardReading, error) {
+	tenantID := domain.TenantFromContext(ctx)
+	rows, err := r.db.QueryContext(ctx, `SELECT `+standardColumns+`, version FROM standard_reading_versions
+WHERE tenant_id = ? AND device_id = ? AND ts = ?
+UNION ALL
+SELECT `+standardColumns+`, (
+	SELECT COALESCE(MAX(v.version), 0) + 1 FROM standard_reading_versions v
+	WHERE v.tenant_id = standard_readings.tenant_id AND v.device_id = standard_readings.device_id
+		AND v.metric = standard_readings.metric AND v.resolution = standard_readings.resolution AND v.ts = standard_readings.ts)
+FROM standard_readings
+WHERE tenant_id = ? AND device_id = ? AND ts = ?
+ORDER BY metric, resolution, version`, tenantID, deviceID, toNanos(timestamp), tenantID, deviceID, toNanos(timestamp))
+	if err != nil {
+		return nil, fmt.Errorf("query standard reading versions: %w", err)
+	}
+	out, err := scanStandardVersions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("query standard reading versions: %w", err)
+	}
+	return out, nil
+}
+
+// FindLatest 获取设备最新的一条标准读数
+func (r *StandardReadingRepository) FindLatest(ctx context.Context, deviceID string) (*domain.StandardReading, error) {
+	return r.findOne(ctx, "AND device_id = ? ORDER BY ts DESC, metric, resolution LIMIT 1", deviceID)
+}
+
+// FindLastBefore 获取指定通道、分辨率下时间早于 before 的最近一条标准读数
+func (r *StandardReadingRepository) FindLastBefore(ctx context.Context, deviceID string, metric domain.Metric, resolution string, before time.Time) (*domain.StandardReading, error) {
+	return r.findOne(ctx, "AND device_id = ? AND metric = ? AND resolution = ? AND ts < ? ORDER BY ts DESC LIMIT 1",
+		deviceID, string(metric), resolution, toNanos(before))
+}
+
+// DeleteOlderThan 删除时间早于 cutoff 的标准读数 (deviceID / resolution 为空表示全部)
+// deviceID 为空时: ctx 设置了租户则只删除该租户的读数，否则删除全部租户的读数 (与内存适配器一致)
+func (r *StandardReadingRepository) DeleteOlderThan(ctx context.Context, deviceID string, resolution string, cutoff time.Time) (int64, error) {
+	const where = " WHERE ts < ? AND (? = '' OR (tenant_id = ? AND device_id = ?)) AND (? = '' OR resolution = ?) AND (? = '' OR tenant_id = ?)"
+	tenantID := domain.TenantFromContext(ctx)
+	args := []any{toNanos(cutoff), deviceID, tenantID, deviceID, resolution, resolution, tenantID, tenantID}
+	if r.versioning {
+		// 历史版本随当前读数一同清理 (不计入返回的条数)
+		if _, err := r.db.ExecContext(ctx, "DELETE FROM standard_reading_versions"+where, args...); err != nil {
+			return 0, fmt.Errorf("delete standard reading versions before %s: %w", cutoff.Format(time.RFC3339), err)
+		}
+	}
+	res, err := r.db.ExecContext(ctx, "DELETE FROM standard_readings"+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("delete standard readings before %s: %w", cutoff.Format(time.RFC3339), err)
+	}
+	return res.RowsAffected()
+}
+
+func (r *StandardReadingRepository) findOne(ctx context.Context, clause string, args ...any) (*domain.StandardReading, error) {
+	found, err := r.query(ctx, clause, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, ports.ErrNotFound
+	}
+	return &found[0], nil
+}
+
+// query 查询 ctx 租户下的标准读数: clause 以 AND 开头，接续租户条件之后的过滤条件与排序子句
+func (r *StandardReadingRepository) query(ctx context.Context, clause string, args ...any) ([]domain.StandardReading, error) {
+	args = append([]any{domain.TenantFromContext(ctx)}, args...)
+	rows, err := r.db.QueryContext(ctx, "SELECT "+standardColumns+" FROM standard_readings WHERE tenant_id = ? "+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query standard readings: %w", err)
+	}
+	out, err := scanStandards(rows)
+	if err != nil {
+		return nil, fmt.Errorf("query standard readings: %w", err)
+	}
+	return out, nil
+}
+
+// scanStandards 按 standardColumns 的顺序读取全部结果行并关闭 rows
+func scanStandards(rows *sql.Rows) ([]domain.StandardReading, error) {
+	defer rows.Close()
+	var out []domain.StandardReading
+	for rows.Next() {
+		sr, err := scanStandard(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sr)
+	}
+	return out, rows.Err()
+}
+
+// scanStandardVersions 扫描 standardColumns 之后附带版本号列的结果
+func scanStandardVersions(rows *sql.Rows) ([]domain.StandardReading, error) {
+	defer rows.Close()
+	var out []domain.StandardReading
+	for rows.Next() {
+		var version int64
+		sr, err := scanStandard(rows, &version)
+		if err != nil {
+			return nil, err
+		}
+		sr.Version = int(version)
+		out = append(out, sr)
+	}
+	return out, rows.Err()
+}
+
+// scanStandard 按 standardColumns 的顺序解码当前行，extra 接收其后的附加列
+func scanStandard(rows *sql.Rows, extra ...any) (domain.StandardReading, error) {
+	var (
+		sr                          domain.StandardReading
+		metric, quality, sourceType string
+		ts, ingestedAt              int64
+		scaleFactor, priority       int64
+		calibration, withdrawal     sql.NullString
+		provenance                  sql.NullString
+	)
+	dest := append([]any{&sr.TenantID, &sr.DeviceID, &metric, &sr.Resolution, &ts, &sr.ValueScaled, &scaleFactor, &sr.ValueDisplay,
+		&quality, &sr.QualityReason, &sr.Confidence, &sourceType, &calibration, &ingestedAt, &priority, &withdrawal,
+		&provenance}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return sr, fmt.Errorf("scan standard reading: %w", err)
+	}
+	sr.Metric = domain.Metric(metric)
+	sr.Timestamp = fromNanos(ts)
+	sr.ScaleFactor = int(scaleFactor)
+	sr.Quality = domain.QualityState(quality)
+	sr.SourceType = domain.ReadingType(sourceType)
+	sr.IngestedAt = fromNanos(ingestedAt)
+	sr.Priority = int(priority)
+	if calibration.Valid {
+		sr.Calibration = &domain.Calibration{}
+		if err := json.Unmarshal([]byte(calibration.String), sr.Calibration); err != nil {
+			return sr, fmt.Errorf("decode calibration of %s: %w", sr.DeviceID, err)
+		}
+	}
+	if withdrawal.Valid {
+		sr.Withdrawal = &domain.Withdrawal{}
+		if err := json.Unmarshal([]byte(withdrawal.String), sr.Withdrawal); err != nil {
+			return sr, fmt.Errorf("decode withdrawal of %s: %w", sr.DeviceID, err)
+		}
+	}
+	if provenance.Valid {
+		sr.Provenance = &domain.Provenance{}
+		if err := json.Unmarshal([]byte(provenance.String), sr.Provenance); err != nil {
+			return sr, fmt.Errorf("decode provenance of %s: %w", sr.DeviceID, err)
+		}
+	}
+	return sr, nil
+}
+
+// upsertSQL 按策略选择 upsert 语句，拒绝未知策略
+func upsertSQL(strategy ports.UpsertStrategy) (string, error) {
+	switch strategy {
+	case ports.UpsertStrategyHighPriorityWins:
+		return upsertStandardSQL + priorityGuard, nil
+	case ports.UpsertStrategyLastWriteWins:
+		return upsertStandardSQL, nil
+	default:
+		return "", fmt.Errorf("unsupported upsert strategy %q", strategy)
+	}
+}
+
+// standardArgs 按 standardColumns 的顺序展开标准读数
+func standardArgs(sr domain.StandardReading) ([]any, error) {
+	var calibration any // NULL 表示未校准
+	if sr.Calibration != nil {
+		b, err := json.Marshal(sr.Calibration)
+		if err != nil {
+			return nil, fmt.Errorf("encode calibration of %s: %w", sr.DeviceID, err)
+		}
+		calibration = string(b)
+	}
+	var withdrawal any // NULL 表示未撤回
+	if sr.Withdrawal != nil {
+		b, err := json.Marshal(sr.Withdrawal)
+		if err != nil {
+			return nil, fmt.Errorf("encode withdrawal of %s: %w", sr.DeviceID, err)
+		}
+		withdrawal = string(b)
+	}
+	var provenance any // NULL 表示未记录来源
+	if sr.Provenance != nil {
+		b, err := json.Marshal(sr.Provenance)
+		if err != nil {
+			return nil, fmt.Errorf("encode provenance of %s: %w", sr.DeviceID, err)
+		}
+		provenance = string(b)
+	}
+	ingestedAt := sr.IngestedAt
+	if ingestedAt.IsZero() {
+		ingestedAt = time.Now()
+	}
+	return []any{
+		sr.TenantID, sr.DeviceID, string(sr.Metric), sr.Resolution, toNanos(sr.Timestamp),
+		sr.ValueScaled, int64(sr.ScaleFactor), sr.ValueDisplay,
+		string(sr.Quality), sr.QualityReason, sr.Confidence, string(sr.SourceType), calibration,
+		toNanos(ingestedAt), int64(sr.Priority), withdrawal, provenance,
+	}, nil
+}
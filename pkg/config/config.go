@@ -0,0 +1,212 @@
+// Package config 从配置文件构建完整的标准化管道 (摄入器、清洗规则、标准化参数与仓储适配器)，
+// 取代每个部署手写的几十行 NewCoreStandardizer(WithX...) 装配代码:
+//
+//	cfg, err := config.Load("prism.json")
+//	if err != nil {
+//	    log.Fatal(err) // 错误带字段路径，如 standardizer.rounding_mode: unknown value "HALF" (want HALF_UP, HALF_EVEN, TRUNCATE)
+//	}
+//	pipeline, err := config.Build(ctx, cfg)
+//	defer pipeline.Close()
+//
+// 原生支持 JSON；本模块只依赖标准库，YAML 通过 WithDecoder 接入解析库 (如 gopkg.in/yaml.v3):
+//
+//	cfg, err := config.Load("prism.yaml", config.WithDecoder(".yaml", yaml.Unmarshal))
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+// Config 管道配置 (JSON / YAML 字段名为 snake_case)
+type Config struct {
+	Standardizer StandardizerConfig    `json:"standardizer"`
+	Storage      StorageConfig         `json:"storage"`
+	Ingest       IngestConfig          `json:"ingest"`
+	Rules        []domain.CleaningRule `json:"rules"` // 内联清洗规则，启动时写入规则仓储 (同 ID 覆盖)
+}
+
+// StandardizerConfig 标准化参数，零值字段使用 NewCoreStandardizer 的默认值
+type StandardizerConfig struct {
+	Interval            Duration                                     `json:"interval"`  // 标准间隔 (默认 15m)
+	Tolerance           Duration                                     `json:"tolerance"` // 对齐容差 (默认 5m)
+	Resolutions         []Duration                                   `json:"resolutions"`
+	GridAnchor          Duration                                     `json:"grid_anchor"`
+	SnapshotMode        domain.SnapshotMode                          `json:"snapshot_mode"`
+	Timezone            string                                       `json:"timezone"` // IANA 时区 (默认 UTC)
+	Precision           int                                          `json:"precision"`
+	DeviceTypePrecision map[domain.DeviceType]int                    `json:"device_type_precision"`
+	RoundingMode        domain.RoundingMode                          `json:"rounding_mode"`
+	Aggregation         map[domain.DeviceType]domain.AggregationMode `json:"aggregation"`
+	MetricAggregation   map[domain.Metric]domain.AggregationMode     `json:"metric_aggregation"`
+	DuplicatePolicy     domain.DuplicatePolicy                       `json:"duplicate_policy"`
+	GapFill             domain.GapFillStrategy                       `json:"gap_fill"`
+	RuleCacheTTL        *Duration                                    `json:"rule_cache_ttl"` // 为空使用默认值，"0s" 表示禁用缓存
+	Concurrency         int                                          `json:"concurrency"`
+	ClockSkewCorrection bool                                         `json:"clock_skew_correction"` // 以默认参数启用时钟偏移修正
+	Audit               bool                                         `json:"audit"`                 // 记录标准读数的覆盖审计 (需要仓储提供审计表)
+	Quarantine          QuarantineConfig                             `json:"quarantine"`
+}
+
+// QuarantineConfig 隔离区持久化策略 (CALLBACK 模式只能在代码中配置)
+type QuarantineConfig struct {
+	Mode      services.QuarantineMode `json:"mode"` // ASYNC (默认) / SYNC
+	QueueSize int                     `json:"queue_size"`
+}
+
+// 存储类型
+const (
+	StorageMemory   = "memory"
+	StorageSQLite   = "sqlite"
+	StoragePostgres = "postgres"
+)
+
+// StorageConfig 仓储适配器
+// sqlite 保存全部仓储；postgres 保存标准读数与原始读数，规则、隔离区与审计暂使用内存实现。
+// 数据库驱动由调用方注册 (如 import _ "modernc.org/sqlite")，也可通过 WithDB 传入已打开的连接。
+type StorageConfig struct {
+	Type       string `json:"type"`       // memory (默认) / sqlite / postgres
+	Driver     string `json:"driver"`     // database/sql 驱动名 (默认 sqlite 为 "sqlite"，postgres 为 "pgx")
+	DSN        string `json:"dsn"`        // 连接串
+	Migrate    bool   `json:"migrate"`    // 启动时执行内嵌迁移
+	Versioning bool   `json:"versioning"` // 保留标准读数的历史版本
+	Table      string `json:"table"`      // postgres: 标准读数表名 (默认 standard_readings)
+	Raw        bool   `json:"raw"`        // 归档原始读数
+	RawTable   string `json:"raw_table"`  // postgres: 原始读数表名 (默认 raw_readings)
+}
+
+// 摄入格式
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// IngestConfig 摄入器
+type IngestConfig struct {
+	Formats []string `json:"formats"` // 为空表示 csv 与 json
+}
+
+// Duration 配置中的时长，写作 time.ParseDuration 格式的字符串 (如 "15m"、"1h30m")
+type Duration time.Duration
+
+// UnmarshalJSON 实现 json.Unmarshaler
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"15m\", got %s", data)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q (want a value such as \"15m\" or \"1h30m\")", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Std 返回 time.Duration
+func (d Duration) Std() time.Duration { return time.Duration(d) }
+
+// DecodeFunc 把配置文件解码为通用值 (map[string]any 等)，签名与 yaml.Unmarshal 相同
+type DecodeFunc func(data []byte, v any) error
+
+// LoadOption 配置文件加载选项
+type LoadOption func(*loader)
+
+type loader struct {
+	decoders map[string]DecodeFunc
+}
+
+// WithDecoder 为扩展名 (如 ".yaml"、".yml"、".toml") 注册解码器
+// 解码结果转换为 JSON 后按与 JSON 文件相同的规则校验，解码器须产生字符串键的映射 (如 yaml.v3)
+func WithDecoder(ext string, decode DecodeFunc) LoadOption {
+	return func(l *loader) {
+		l.decoders[strings.ToLower(ext)] = decode
+	}
+}
+
+// Load 读取并校验配置文件，按扩展名选择解码方式 (.json 内置，其他扩展名需 WithDecoder)
+func Load(path string, opts ...LoadOption) (*Config, error) {
+	l := &loader{decoders: make(map[string]DecodeFunc)}
+	for _, opt := range opts {
+		opt(l)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if decode, ok := l.decoders[ext]; ok {
+		var generic any
+		if err := decode(data, &generic); err != nil {
+			return nil, fmt.Errorf("load config %s: %w", path, err)
+		}
+		if data, err = json.Marshal(generic); err != nil {
+			return nil, fmt.Errorf("load config %s: decoded value is not JSON-compatible (string keys required): %w", path, err)
+		}
+	} else if ext != ".json" {
+		return nil, fmt.Errorf("load config %s: unsupported extension %q (register a decoder with config.WithDecoder)", path, ext)
+	}
+	cfg, err := parse(data, ext == ".json")
+	if err != nil {
+		return nil, fmt.Errorf("load config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse 解析并校验 JSON 配置 (未知字段视为错误，避免拼写错误的参数被静默忽略)
+func Parse(data []byte) (*Config, error) {
+	return parse(data, true)
+}
+
+// parse 解析并校验配置；positions 为 false 时 (data 由其他格式转换而来) 行列号没有意义，不附加
+func parse(data []byte, positions bool) (*Config, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		if !positions {
+			return nil, err
+		}
+		return nil, describeJSONError(data, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// describeJSONError 为语法与类型错误补充行列号
+func describeJSONError(data []byte, err error) error {
+	var syntax *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntax):
+		line, col := position(data, syntax.Offset)
+		return fmt.Errorf("line %d, column %d: %w", line, col, err)
+	case errors.As(err, &typ):
+		line, col := position(data, typ.Offset)
+		return fmt.Errorf("line %d, column %d: %s: cannot use %s as %s", line, col, typ.Field, typ.Value, typ.Type)
+	default:
+		return err
+	}
+}
+
+func position(data []byte, offset int64) (line, col int) {
+	data = data[:min(int(offset), len(data))]
+	line = bytes.Count(data, []byte("\n")) + 1
+	return line, len(data) - bytes.LastIndexByte(data, '\n')
+}
@@ -0,0 +1,336 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/ingest"
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/adapters/persistence/postgres"
+	"github.com/renjie/prism-core/pkg/adapters/persistence/sqlite"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+// 默认的 database/sql 驱动名
+const (
+	DefaultSQLiteDriver   = "sqlite"
+	DefaultPostgresDriver = "pgx"
+)
+
+// Pipeline 由配置构建的管道
+// 仓储字段供管理接口、报表等其他组件复用；未启用的可选仓储为 nil。
+type Pipeline struct {
+	Standardizer *services.CoreStandardizer
+	Ingestors    map[string]ports.UniversalIngestor // 按格式 (csv / json)
+
+	Repository           ports.StandardReadingRepository
+	RawRepository        ports.RawReadingRepository // storage.raw 为 false 时为 nil
+	QuarantineRepository ports.QuarantineRepository
+	RuleRepository       ports.CleaningRuleRepository
+	AuditRepository      ports.AuditRepository // standardizer.audit 为 false 时为 nil
+
+	db     *sql.DB // 由 Build 打开的连接 (WithDB 传入的连接不由 Pipeline 关闭)
+	closed bool
+}
+
+// BuildOption 构建选项，用于配置文件无法表达的部分 (钩子、指标、日志、事件总线等)
+type BuildOption func(*builder)
+
+type builder struct {
+	db           *sql.DB
+	standardizer []services.StandardizerOption
+	ingestor     []ingest.IngestorOption
+	downstream   func(context.Context, []domain.Reading) error
+	quarantine   ports.QuarantineRepository
+	rules        ports.CleaningRuleRepository
+}
+
+// WithDB 使用已打开的数据库连接，忽略 storage.driver 与 storage.dsn (Pipeline.Close 不关闭该连接)
+func WithDB(db *sql.DB) BuildOption {
+	return func(b *builder) {
+		b.db = db
+	}
+}
+
+// WithQuarantineRepository 使用传入的隔离区仓储，代替 storage.type 对应的实现
+// postgres 存储没有内置的隔离区仓储，必须以此传入 (否则 Build 返回错误)
+func WithQuarantineRepository(repo ports.QuarantineRepository) BuildOption {
+	return func(b *builder) {
+		b.quarantine = repo
+	}
+}
+
+// WithRuleRepository 使用传入的规则仓储，代替 storage.type 对应的实现 (内联规则写入该仓储)
+// postgres 存储没有内置的规则仓储，必须以此传入 (否则 Build 返回错误)
+func WithRuleRepository(repo ports.CleaningRuleRepository) BuildOption {
+	return func(b *builder) {
+		b.rules = repo
+	}
+}
+
+// WithStandardizerOptions 追加标准化选项，在配置生成的选项之后应用 (同一设置以此为准)
+func WithStandardizerOptions(opts ...services.StandardizerOption) BuildOption {
+	return func(b *builder) {
+		b.standardizer = append(b.standardizer, opts...)
+	}
+}
+
+// WithIngestorOptions 追加摄入器选项 (如 ingest.WithEventPublisher)
+func WithIngestorOptions(opts ...ingest.IngestorOption) BuildOption {
+	return func(b *builder) {
+		b.ingestor = append(b.ingestor, opts...)
+	}
+}
+
+// WithIngestDownstream 设置摄入器的下游 (默认直接调用 Standardizer.ProcessAndStandardize)
+// 常驻服务可改为 StandardizerService.Enqueue
+func WithIngestDownstream(downstream func(context.Context, []domain.Reading) error) BuildOption {
+	return func(b *builder) {
+		b.downstream = downstream
+	}
+}
+
+// Build 按配置创建仓储、标准化服务与摄入器
+// cfg 须已通过 Validate (Load / Parse 已校验)；sqlite / postgres 存储在 storage.migrate 为 true 时先执行内嵌迁移。
+// postgres 存储须以 WithQuarantineRepository 与 WithRuleRepository 传入隔离区与规则仓储。
+func Build(ctx context.Context, cfg *Config, opts ...BuildOption) (*Pipeline, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	b := &builder{}
+	for _, opt := range opts {
+		opt(b)
+	}
+	// 进程内的隔离区与规则在重启后丢失，不能与持久化的标准读数混用
+	if cfg.Storage.Type == StoragePostgres && (b.quarantine == nil || b.rules == nil) {
+		return nil, errors.New("postgres storage has no built-in quarantine or rule repository: inject them with config.WithQuarantineRepository and config.WithRuleRepository")
+	}
+
+	p := &Pipeline{}
+	if err := p.buildStorage(ctx, cfg, b); err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+
+	std := append(standardizerOptions(cfg.Standardizer), services.WithRepository(p.Repository),
+		services.WithQuarantineRepository(p.QuarantineRepository), services.WithRuleRepository(p.RuleRepository))
+	if p.RawRepository != nil {
+		std = append(std, services.WithRawRepository(p.RawRepository))
+	}
+	if p.AuditRepository != nil {
+		std = append(std, services.WithAuditRepository(p.AuditRepository))
+	}
+	p.Standardizer = services.NewCoreStandardizer(append(std, b.standardizer...)...).(*services.CoreStandardizer)
+
+	downstream := b.downstream
+	if downstream == nil {
+		downstream = func(ctx context.Context, readings []domain.Reading) error {
+			_, err := p.Standardizer.ProcessAndStandardize(ctx, readings)
+			return err
+		}
+	}
+	formats := cfg.Ingest.Formats
+	if len(formats) == 0 {
+		formats = ingestFormats
+	}
+	p.Ingestors = make(map[string]ports.UniversalIngestor, len(formats))
+	for _, f := range formats {
+		switch f {
+		case FormatCSV:
+			p.Ingestors[f] = ingest.NewCsvUniversalIngestor(downstream, b.ingestor...)
+		case FormatJSON:
+			p.Ingestors[f] = ingest.NewJsonUniversalIngestor(downstream, b.ingestor...)
+		}
+	}
+	return p, nil
+}
+
+// buildStorage 创建仓储并写入内联规则
+func (p *Pipeline) buildStorage(ctx context.Context, cfg *Config, b *builder) error {
+	st, db := cfg.Storage, b.db
+	switch st.Type {
+	case StorageSQLite, StoragePostgres:
+		if db == nil {
+			driver := st.Driver
+			if driver == "" {
+				driver = map[string]string{StorageSQLite: DefaultSQLiteDriver, StoragePostgres: DefaultPostgresDriver}[st.Type]
+			}
+			var err error
+			if db, err = sql.Open(driver, st.DSN); err != nil {
+				return fmt.Errorf("open %s storage (is the %q driver imported?): %w", st.Type, driver, err)
+			}
+			p.db = db
+		}
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("connect %s storage: %w", st.Type, err)
+		}
+	}
+
+	switch st.Type {
+	case StorageSQLite:
+		if st.Migrate {
+			if err := sqlite.Migrate(ctx, db); err != nil {
+				return fmt.Errorf("migrate sqlite storage: %w", err)
+			}
+		}
+		var repoOpts []sqlite.Option
+		if st.Versioning {
+			repoOpts = append(repoOpts, sqlite.WithVersioning())
+		}
+		p.Repository = sqlite.NewStandardReadingRepository(db, repoOpts...)
+		p.QuarantineRepository = sqlite.NewQuarantineRepository(db)
+		p.RuleRepository = sqlite.NewCleaningRuleRepository(db)
+		if st.Raw {
+			p.RawRepository = sqlite.NewRawReadingRepository(db)
+		}
+		if cfg.Standardizer.Audit {
+			p.AuditRepository = sqlite.NewAuditRepository(db)
+		}
+	case StoragePostgres:
+		if st.Migrate {
+			if err := postgres.Migrate(ctx, db); err != nil {
+				return fmt.Errorf("migrate postgres storage: %w", err)
+			}
+		}
+		var repoOpts []postgres.Option
+		if st.Table != "" {
+			repoOpts = append(repoOpts, postgres.WithTable(st.Table))
+		}
+		if st.Versioning {
+			repoOpts = append(repoOpts, postgres.WithVersioning(""))
+		}
+		p.Repository = postgres.NewStandardReadingRepository(db, repoOpts...)
+		if st.Raw {
+			p.RawRepository = postgres.NewRawReadingRepository(db, st.RawTable)
+		}
+	default:
+		var repoOpts []memory.Option
+		if st.Versioning {
+			repoOpts = append(repoOpts, memory.WithVersioning())
+		}
+		p.Repository = memory.NewStandardReadingRepository(repoOpts...)
+		p.QuarantineRepository = memory.NewQuarantineRepository()
+		p.RuleRepository = memory.NewCleaningRuleRepository(nil)
+		if st.Raw {
+			p.RawRepository = memory.NewRawReadingRepository()
+		}
+		if cfg.Standardizer.Audit {
+			p.AuditRepository = memory.NewAuditRepository()
+		}
+	}
+
+	if b.quarantine != nil {
+		p.QuarantineRepository = b.quarantine
+	}
+	if b.rules != nil {
+		p.RuleRepository = b.rules
+	}
+
+	for _, rule := range cfg.Rules {
+		if err := p.RuleRepository.Save(ctx, rule); err != nil {
+			return fmt.Errorf("save rule %s: %w", rule.ID, err)
+		}
+	}
+	return nil
+}
+
+// standardizerOptions 把标准化参数转换为选项 (零值字段不生成选项，沿用默认值)
+func standardizerOptions(s StandardizerConfig) []services.StandardizerOption {
+	var opts []services.StandardizerOption
+	if s.Interval > 0 || s.Tolerance > 0 {
+		interval, tolerance := 15*time.Minute, 5*time.Minute
+		if s.Interval > 0 {
+			interval = s.Interval.Std()
+		}
+		if s.Tolerance > 0 {
+			tolerance = s.Tolerance.Std()
+		}
+		opts = append(opts, services.WithAlignment(interval, tolerance))
+	}
+	if len(s.Resolutions) > 0 {
+		intervals := make([]time.Duration, len(s.Resolutions))
+		for i, r := range s.Resolutions {
+			intervals[i] = r.Std()
+		}
+		opts = append(opts, services.WithResolutions(intervals...))
+	}
+	if s.GridAnchor > 0 {
+		opts = append(opts, services.WithGridAnchor(s.GridAnchor.Std()))
+	}
+	if s.SnapshotMode != "" {
+		opts = append(opts, services.WithSnapshotMode(s.SnapshotMode))
+	}
+	if s.Timezone != "" {
+		loc, _ := time.LoadLocation(s.Timezone) // Validate 已校验
+		opts = append(opts, services.WithLocation(loc))
+	}
+	if s.Precision > 0 {
+		opts = append(opts, services.WithPrecision(s.Precision))
+	}
+	for dt, factor := range s.DeviceTypePrecision {
+		opts = append(opts, services.WithDeviceTypePrecision(dt, factor))
+	}
+	if s.RoundingMode != "" {
+		opts = append(opts, services.WithRoundingMode(s.RoundingMode))
+	}
+	for dt, mode := range s.Aggregation {
+		opts = append(opts, services.WithAggregation(dt, mode))
+	}
+	for m, mode := range s.MetricAggregation {
+		opts = append(opts, services.WithMetricAggregation(m, mode))
+	}
+	if s.DuplicatePolicy != "" {
+		opts = append(opts, services.WithDuplicatePolicy(s.DuplicatePolicy))
+	}
+	if s.GapFill != "" {
+		opts = append(opts, services.WithGapFill(s.GapFill))
+	}
+	if s.RuleCacheTTL != nil {
+		opts = append(opts, services.WithRuleCacheTTL(s.RuleCacheTTL.Std()))
+	}
+	if s.Concurrency > 0 {
+		opts = append(opts, services.WithConcurrencyLimit(s.Concurrency))
+	}
+	if s.ClockSkewCorrection {
+		opts = append(opts, services.WithClockSkewCorrection(services.DefaultClockSkewConfig()))
+	}
+	if s.Quarantine.Mode != "" || s.Quarantine.QueueSize > 0 {
+		policy := services.DefaultQuarantinePolicy()
+		if s.Quarantine.Mode != "" {
+			policy.Mode = s.Quarantine.Mode
+		}
+		if s.Quarantine.QueueSize > 0 {
+			policy.QueueSize = s.Quarantine.QueueSize
+		}
+		opts = append(opts, services.WithQuarantinePolicy(policy))
+	}
+	return opts
+}
+
+// Shutdown 优雅停止标准化服务 (等待在途批次、排空隔离区队列) 并关闭由 Build 打开的数据库连接
+// ctx 到期时返回错误且保留数据库连接，在途工作继续在后台进行，可以用新的 ctx 再次调用
+func (p *Pipeline) Shutdown(ctx context.Context) error {
+	if p.closed {
+		return nil
+	}
+	if p.Standardizer != nil {
+		if err := p.Standardizer.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	p.closed = true
+	if p.db != nil {
+		return p.db.Close()
+	}
+	return nil
+}
+
+// Close 等同于 Shutdown(context.Background())
+func (p *Pipeline) Close() error {
+	return p.Shutdown(context.Background())
+}
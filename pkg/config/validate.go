@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/factory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+// 各枚举字段的合法取值 (空值表示使用默认值)
+var (
+	snapshotModes    = []domain.SnapshotMode{domain.SnapshotNearest, domain.SnapshotAtOrBefore, domain.SnapshotInterpolate}
+	roundingModes    = []domain.RoundingMode{domain.RoundHalfUp, domain.RoundHalfEven, domain.RoundTruncate}
+	aggregationModes = []domain.AggregationMode{domain.AggregationSnapshot, domain.AggregationFirst, domain.AggregationLast, domain.AggregationMean, domain.AggregationMax, domain.AggregationMin, domain.AggregationSum}
+	duplicatePolicy  = []domain.DuplicatePolicy{domain.DuplicateKeepFirst, domain.DuplicateKeepLast, domain.DuplicateKeepHighestPrio, domain.DuplicateAverage}
+	gapFills         = []domain.GapFillStrategy{domain.GapFillNone, domain.GapFillLinear, domain.GapFillLOCF}
+	quarantineModes  = []services.QuarantineMode{services.QuarantineAsync, services.QuarantineSync}
+	storageTypes     = []string{StorageMemory, StorageSQLite, StoragePostgres}
+	ingestFormats    = []string{FormatCSV, FormatJSON}
+)
+
+// Validate 校验全部字段，返回的错误汇总所有问题，每条带字段路径
+// 内联规则用 factory.GetRuleFactory() 实例化校验，与标准化服务加载规则的方式一致 (自定义规则类型需先注册)。
+func (c *Config) Validate() error {
+	var v validator
+	s := c.Standardizer
+	v.check(s.Interval >= 0, "standardizer.interval", "must not be negative")
+	v.check(s.Tolerance >= 0, "standardizer.tolerance", "must not be negative")
+	for i, r := range s.Resolutions {
+		v.check(r > 0, fmt.Sprintf("standardizer.resolutions[%d]", i), "must be positive")
+	}
+	v.check(s.GridAnchor >= 0, "standardizer.grid_anchor", "must not be negative")
+	oneOf(&v, "standardizer.snapshot_mode", s.SnapshotMode, snapshotModes)
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			v.addf("standardizer.timezone", "unknown time zone %q", s.Timezone)
+		}
+	}
+	v.check(s.Precision >= 0, "standardizer.precision", "must not be negative")
+	for _, dt := range slices.Sorted(maps.Keys(s.DeviceTypePrecision)) {
+		v.check(s.DeviceTypePrecision[dt] > 0, fmt.Sprintf("standardizer.device_type_precision.%s", dt), "must be positive")
+	}
+	oneOf(&v, "standardizer.rounding_mode", s.RoundingMode, roundingModes)
+	for _, dt := range slices.Sorted(maps.Keys(s.Aggregation)) {
+		oneOf(&v, fmt.Sprintf("standardizer.aggregation.%s", dt), s.Aggregation[dt], aggregationModes)
+	}
+	for _, m := range slices.Sorted(maps.Keys(s.MetricAggregation)) {
+		oneOf(&v, fmt.Sprintf("standardizer.metric_aggregation.%s", m), s.MetricAggregation[m], aggregationModes)
+	}
+	oneOf(&v, "standardizer.duplicate_policy", s.DuplicatePolicy, duplicatePolicy)
+	oneOf(&v, "standardizer.gap_fill", s.GapFill, gapFills)
+	v.check(s.RuleCacheTTL == nil || *s.RuleCacheTTL >= 0, "standardizer.rule_cache_ttl", "must not be negative")
+	v.check(s.Concurrency >= 0, "standardizer.concurrency", "must not be negative")
+	oneOf(&v, "standardizer.quarantine.mode", s.Quarantine.Mode, quarantineModes)
+	v.check(s.Quarantine.QueueSize >= 0, "standardizer.quarantine.queue_size", "must not be negative")
+
+	st := c.Storage
+	oneOf(&v, "storage.type", st.Type, storageTypes)
+	switch st.Type {
+	case StorageSQLite, StoragePostgres:
+		v.check(st.DSN != "", "storage.dsn", fmt.Sprintf("is required for %s storage", st.Type))
+	default:
+		v.check(st.DSN == "" && st.Driver == "", "storage", "dsn and driver are only used by sqlite and postgres storage")
+	}
+	v.check(st.Type == StoragePostgres || (st.Table == "" && st.RawTable == ""), "storage", "table and raw_table are only used by postgres storage")
+	v.check(!s.Audit || st.Type != StoragePostgres, "standardizer.audit", "is not supported by postgres storage yet")
+
+	for i, f := range c.Ingest.Formats {
+		oneOf(&v, fmt.Sprintf("ingest.formats[%d]", i), f, ingestFormats)
+	}
+
+	ids := make(map[string]int)
+	rules := factory.GetRuleFactory()
+	for i, r := range c.Rules {
+		path := fmt.Sprintf("rules[%d]", i)
+		if r.ID != "" {
+			path = fmt.Sprintf("rules[%d] (%s)", i, r.ID)
+		}
+		v.check(r.ID != "", path+".id", "is required")
+		v.check(r.DeviceType != "", path+".device_type", "is required")
+		if first, dup := ids[r.ID]; dup && r.ID != "" {
+			v.addf(path+".id", "duplicates rules[%d]", first)
+		} else {
+			ids[r.ID] = i
+		}
+		if _, err := rules.CreateRule(r); err != nil {
+			v.addf(path, "%v", err)
+		}
+	}
+	return v.err()
+}
+
+// validator 收集校验错误
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(path, format string, args ...any) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) check(ok bool, path, msg string) {
+	if !ok {
+		v.addf(path, "%s", msg)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return errors.New("invalid config:\n  " + strings.Join(v.problems, "\n  "))
+}
+
+// oneOf 校验枚举字段 (空值合法)，错误列出全部可选值
+func oneOf[T ~string](v *validator, path string, value T, allowed []T) {
+	if value == "" || slices.Contains(allowed, value) {
+		return
+	}
+	names := make([]string, len(allowed))
+	for i, a := range allowed {
+		names[i] = string(a)
+	}
+	v.addf(path, "unknown value %q (want %s)", value, strings.Join(names, ", "))
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// AggregationMode 定义标准时间槽位的取值方式
+type AggregationMode string
+
+const (
+	AggregationSnapshot AggregationMode = "SNAPSHOT" // 最近邻快照 (默认，适用于累积型表计)
+	AggregationFirst    AggregationMode = "FIRST"    // 区间内第一条
+	AggregationLast     AggregationMode = "LAST"     // 区间内最后一条
+	AggregationMean     AggregationMode = "MEAN"     // 区间均值 (适用于瞬时量/仪表)
+	AggregationMax      AggregationMode = "MAX"      // 区间最大值
+	AggregationMin      AggregationMode = "MIN"      // 区间最小值
+	AggregationSum      AggregationMode = "SUM"      // 区间求和 (适用于区间电量表)
+)
+
+// Window 返回落在 [from, to) 区间内的读数子切片
+// 前提是 readings 已按时间排序
+func Window(readings []Reading, from, to time.Time) []Reading {
+	lo := sort.Search(len(readings), func(i int) bool {
+		return !readings[i].Timestamp.Before(from)
+	})
+	hi := sort.Search(len(readings), func(i int) bool {
+		return !readings[i].Timestamp.Before(to)
+	})
+	return readings[lo:hi]
+}
+
+// Aggregate 按聚合方式把区间内的读数合并为一条
+// 结果沿用第一条读数的设备信息，取最低置信度、最高优先级，
+// 并继承区间内的非有效质量标记 (如 ESTIMATED)。区间为空或模式为 SNAPSHOT 时返回 ok=false。
+func Aggregate(readings []Reading, mode AggregationMode) (Reading, bool) {
+	if len(readings) == 0 || mode == AggregationSnapshot || mode == "" {
+		return Reading{}, false
+	}
+
+	out := readings[0]
+	out.Quality = ""
+	out.QualityReason = ""
+	out.Confidence = 0
+	var sum float64
+	for i, r := range readings {
+		sum += r.Value
+		if r.Quality != "" && r.Quality != QualityValid && out.Quality == "" {
+			out.Quality = r.Quality
+			out.QualityReason = r.QualityReason
+		}
+		if r.Confidence > 0 && (out.Confidence == 0 || r.Confidence < out.Confidence) {
+			out.Confidence = r.Confidence
+		}
+		if r.Priority > out.Priority {
+			out.Priority = r.Priority
+		}
+		if i == 0 {
+			continue
+		}
+		switch mode {
+		case AggregationMax:
+			out.Value = max(out.Value, r.Value)
+		case AggregationMin:
+			out.Value = min(out.Value, r.Value)
+		}
+	}
+
+	switch mode {
+	case AggregationFirst:
+		out.Value = readings[0].Value
+	case AggregationLast:
+		out.Value = readings[len(readings)-1].Value
+	case AggregationMean:
+		out.Value = sum / float64(len(readings))
+	case AggregationSum:
+		out.Value = sum
+	case AggregationMax, AggregationMin:
+	default:
+		return Reading{}, false
+	}
+	return out, true
+}
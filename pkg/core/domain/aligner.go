@@ -5,9 +5,19 @@ import (
 	"time"
 )
 
+// SnapshotMode 定义快照查找方式 (方向偏好或插值)
+type SnapshotMode string
+
+const (
+	SnapshotNearest     SnapshotMode = "NEAREST"      // 容差内时间差最小的读数，前后均可 (默认)
+	SnapshotAtOrBefore  SnapshotMode = "AT_OR_BEFORE" // 容差内不晚于目标时间的最后一条读数 (计费语义，不会把未来用量归入过去的槽位)
+	SnapshotInterpolate SnapshotMode = "INTERPOLATE"  // 前后读数均在容差内时按时间比例线性插值，否则退回 NEAREST
+)
+
 // TimeAligner 默认实现：基于时间容差的二分查找
 type TimeAligner struct {
 	Tolerance time.Duration
+	Mode      SnapshotMode // FindSnapshot 使用的查找方式 (空值按 NEAREST)
 }
 
 // NewAligner 创建时间对齐器实例
@@ -16,13 +26,35 @@ func NewAligner(tolerance time.Duration) *TimeAligner {
 	return &TimeAligner{Tolerance: tolerance}
 }
 
-// FindSnapshot 使用二分查找寻找最接近 target 时间点的读数
+// FindSnapshot 使用二分查找寻找最接近 target 时间点的读数 (查找方式取 Mode)
 // 时间复杂度: O(log n)，前提是 readings 已按时间排序
 func (t *TimeAligner) FindSnapshot(readings []Reading, target time.Time) *Reading {
+	return t.FindSnapshotMode(readings, target, t.Mode)
+}
+
+// FindSnapshotMode 与 FindSnapshot 相同，但由调用方指定本次的查找方式
+// INTERPOLATE 模式下返回的是新构造的插值读数，而非 readings 中的元素
+func (t *TimeAligner) FindSnapshotMode(readings []Reading, target time.Time, mode SnapshotMode) *Reading {
 	if len(readings) == 0 {
 		return nil
 	}
 
+	switch mode {
+	case SnapshotAtOrBefore:
+		prev, _ := Bracket(readings, target)
+		if prev == nil || target.Sub(prev.Timestamp) > t.Tolerance {
+			return nil
+		}
+		return prev
+	case SnapshotInterpolate:
+		prev, next := Bracket(readings, target)
+		if prev != nil && next != nil && prev != next &&
+			target.Sub(prev.Timestamp) <= t.Tolerance && next.Timestamp.Sub(target) <= t.Tolerance {
+			interpolated := Interpolate(*prev, *next, target)
+			return &interpolated
+		}
+	}
+
 	// 二分查找: 找到第一个 Timestamp >= target 的位置
 	idx := sort.Search(len(readings), func(i int) bool {
 		return !readings[i].Timestamp.Before(target)
@@ -46,6 +78,63 @@ func (t *TimeAligner) FindSnapshot(readings []Reading, target time.Time) *Readin
 	return best
 }
 
+// GapFillStrategy 定义时间网格空槽位 (FindSnapshot 未命中) 的填充策略
+type GapFillStrategy string
+
+const (
+	GapFillNone   GapFillStrategy = "NONE"   // 不填充，空槽位不产生标准读数 (默认)
+	GapFillLinear GapFillStrategy = "LINEAR" // 使用前后读数线性插值
+	GapFillLOCF   GapFillStrategy = "LOCF"   // 沿用前一条读数
+)
+
+// Bracket 返回 target 两侧最近的读数: prev 为最后一条 Timestamp <= target 的读数，next 为第一条 Timestamp >= target 的读数
+// 前提是 readings 已按时间排序；不存在时对应返回 nil
+func Bracket(readings []Reading, target time.Time) (prev, next *Reading) {
+	idx := sort.Search(len(readings), func(i int) bool {
+		return !readings[i].Timestamp.Before(target)
+	})
+	if idx < len(readings) {
+		next = &readings[idx]
+		if readings[idx].Timestamp.Equal(target) {
+			prev = next
+			return prev, next
+		}
+	}
+	if idx > 0 {
+		prev = &readings[idx-1]
+	}
+	return prev, next
+}
+
+// Interpolate 按时间比例在 prev 与 next 之间线性插值出 target 时刻的读数
+// 结果沿用 prev 的设备信息，标记为 INTERPOLATED；优先级取两者中较高者，置信度取较低者
+func Interpolate(prev, next Reading, target time.Time) Reading {
+	out := prev
+	out.Timestamp = target
+	if span := next.Timestamp.Sub(prev.Timestamp); span > 0 {
+		ratio := float64(target.Sub(prev.Timestamp)) / float64(span)
+		out.Value = prev.Value + (next.Value-prev.Value)*ratio
+	}
+	out.Quality = QualityInterpolated
+	out.QualityReason = "interpolated between bracketing readings"
+	out.Confidence = minConfidence(prev.Confidence, next.Confidence)
+	if next.Priority > out.Priority {
+		out.Priority = next.Priority
+	}
+	return out
+}
+
+// minConfidence 返回两个置信度中较低者 (未评估的 0 视为 1)
+func minConfidence(a, b float64) float64 {
+	if a <= 0 {
+		a = 1
+	}
+	if b <= 0 {
+		b = 1
+	}
+	return min(a, b)
+}
+
 // absDuration 返回 Duration 的绝对值
 func absDuration(d time.Duration) time.Duration {
 	if d < 0 {
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// AnomalyKind 语义异常类型
+type AnomalyKind string
+
+const (
+	AnomalyConsumptionSpike AnomalyKind = "CONSUMPTION_SPIKE"  // 用量突增: 某区间用量明显高于同类时段的历史水平
+	AnomalyBaseLoadIncrease AnomalyKind = "BASE_LOAD_INCREASE" // 夜间基础负荷上升: 夜间平均用量明显高于历史水平 (如设备未关、泄漏)
+)
+
+// Anomaly 标准读数序列上检测到的语义异常
+// 清洗规则只处理单点的脏数据；异常是数值合法、但偏离设备自身历史规律的用能行为。
+type Anomaly struct {
+	DeviceID   string      `json:"device_id"`
+	Metric     Metric      `json:"metric,omitempty"`
+	Kind       AnomalyKind `json:"kind"`
+	Start      time.Time   `json:"start"`     // 异常区间开始
+	End        time.Time   `json:"end"`       // 异常区间结束 (不含)
+	Observed   float64     `json:"observed"`  // 实际值 (区间用量或夜间平均用量)
+	Expected   float64     `json:"expected"`  // 历史基线
+	Threshold  float64     `json:"threshold"` // 判定阈值 (Observed 超过该值即为异常)
+	Reason     string      `json:"reason"`
+	DetectedAt time.Time   `json:"detected_at"`
+}
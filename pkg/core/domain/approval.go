@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// ApprovalStatus 定义待审批写入的状态
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending  ApprovalStatus = "PENDING"  // 待审批
+	ApprovalStatusApproved ApprovalStatus = "APPROVED" // 已批准 (读数已写入)
+	ApprovalStatusRejected ApprovalStatus = "REJECTED" // 已驳回 (读数未写入)
+)
+
+// ApprovalRequest 一批等待第二人审批的人工校准 (CALIBRATION) 读数
+// 校准优先级最高，会无条件覆盖已有标准读数；影响范围超过阈值时先暂存，批准后才写入
+type ApprovalRequest struct {
+	ID       string    `json:"id"`
+	TenantID string    `json:"tenant_id,omitempty"`
+	Readings []Reading `json:"readings"` // 提交时的原始读数
+
+	// 提交时的 IngestContext (Strategy 恒为 CALIBRATION)，批准后以此重新处理
+	TraceID     string    `json:"trace_id,omitempty"`
+	BatchID     string    `json:"batch_id,omitempty"`
+	SubmittedBy string    `json:"submitted_by,omitempty"` // 提交人 (IngestContext.Operator)
+	SubmittedAt time.Time `json:"submitted_at"`
+
+	// QuarantineID 非空表示人工修正隔离记录 (Resolve) 的请求: 批准后与 Resolve 一样不经清洗规则直接对齐写入，
+	// 驳回后该隔离记录恢复为 PENDING
+	QuarantineID string `json:"quarantine_id,omitempty"`
+
+	Status     ApprovalStatus `json:"status"`
+	ReviewedBy string         `json:"reviewed_by,omitempty"` // 审批人 (APPROVED / REJECTED 时填写)
+	ReviewedAt time.Time      `json:"reviewed_at"`
+	Note       string         `json:"note,omitempty"` // 审批说明 (如驳回原因)
+}
+
+// IngestContext 还原提交时的摄入上下文
+func (r ApprovalRequest) IngestContext() IngestContext {
+	return IngestContext{TraceID: r.TraceID, Strategy: IngestStrategyCalibration, Operator: r.SubmittedBy, BatchID: r.BatchID}
+}
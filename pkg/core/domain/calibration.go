@@ -0,0 +1,39 @@
+package domain
+
+// Calibration 设备计量校准系数
+// 互感器计量的回路上，表计寄存器的原始值必须乘以变比才是一次侧的真实值:
+// 一次值 = 原始值 × CTRatio × PTRatio × Multiplier + Offset
+// 各倍率为 0 时视为 1 (未配置)。
+type Calibration struct {
+	CTRatio    float64 `json:"ct_ratio,omitempty"`   // 电流互感器变比 (如 200/5 → 40)
+	PTRatio    float64 `json:"pt_ratio,omitempty"`   // 电压互感器变比 (如 10kV/100V → 100)
+	Multiplier float64 `json:"multiplier,omitempty"` // 额外校准倍率
+	Offset     float64 `json:"offset,omitempty"`     // 校准偏移 (在倍率之后叠加)
+}
+
+// Factor 返回综合倍率 CTRatio × PTRatio × Multiplier
+func (c Calibration) Factor() float64 {
+	return orOne(c.CTRatio) * orOne(c.PTRatio) * orOne(c.Multiplier)
+}
+
+// IsIdentity 判断校准是否不改变数值
+func (c Calibration) IsIdentity() bool {
+	return c.Factor() == 1 && c.Offset == 0
+}
+
+// Apply 将原始值换算为校准后的一次值
+func (c Calibration) Apply(raw float64) float64 {
+	return raw*c.Factor() + c.Offset
+}
+
+// Revert 将校准后的值还原为原始值 (Apply 的逆运算)
+func (c Calibration) Revert(calibrated float64) float64 {
+	return (calibrated - c.Offset) / c.Factor()
+}
+
+func orOne(v float64) float64 {
+	if v == 0 {
+		return 1
+	}
+	return v
+}
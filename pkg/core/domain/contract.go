@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrContractViolation 输入与来源系统登记的数据契约不一致 (字段缺失或出现未登记字段)
+var ErrContractViolation = errors.New("data contract violation")
+
+// DataContract 来源系统登记的输入结构与字段映射
+// 上游改名、增删字段时摄入直接失败，而不是静默产生错位的读数。
+type DataContract struct {
+	Source  string `json:"source"`  // 来源系统标识 (如 "scada-north")
+	Version string `json:"version"` // 契约版本 (由来源系统维护，出现在错误信息中便于定位)
+
+	// Fields 必须出现的源字段；Optional 可以出现的源字段。不在两者中的字段视为漂移
+	Fields   []string `json:"fields"`
+	Optional []string `json:"optional,omitempty"`
+
+	// Mapping 源字段 -> 标准字段 (device_id, timestamp, value, type, unit, metric, model, timezone, tenant_id)
+	// 未列出的源字段按原名使用
+	Mapping map[string]string `json:"mapping,omitempty"`
+}
+
+// Check 校验一条记录的源字段集合 (字段名不区分大小写)，不一致时返回包装 ErrContractViolation 的错误
+func (c DataContract) Check(fields []string) error {
+	present := make(map[string]bool, len(fields))
+	var unexpected []string
+	for _, f := range fields {
+		f = normalizeField(f)
+		present[f] = true
+		if !c.declares(f) {
+			unexpected = append(unexpected, f)
+		}
+	}
+	var missing []string
+	for _, f := range c.Fields {
+		if !present[normalizeField(f)] {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return nil
+	}
+	var details []string
+	if len(missing) > 0 {
+		details = append(details, "missing "+strings.Join(missing, ", "))
+	}
+	if len(unexpected) > 0 {
+		slices.Sort(unexpected)
+		details = append(details, "unexpected "+strings.Join(unexpected, ", "))
+	}
+	return fmt.Errorf("%w: source %s contract %s: %s", ErrContractViolation, c.Source, c.Version, strings.Join(details, "; "))
+}
+
+// Canonical 返回源字段对应的标准字段名 (小写)
+func (c DataContract) Canonical(field string) string {
+	field = normalizeField(field)
+	for src, dst := range c.Mapping {
+		if normalizeField(src) == field {
+			return normalizeField(dst)
+		}
+	}
+	return field
+}
+
+// declares 判断契约是否登记了该 (已规范化的) 源字段
+func (c DataContract) declares(field string) bool {
+	match := func(f string) bool { return normalizeField(f) == field }
+	return slices.ContainsFunc(c.Fields, match) || slices.ContainsFunc(c.Optional, match)
+}
+
+func normalizeField(f string) string {
+	return strings.ToLower(strings.TrimSpace(f))
+}
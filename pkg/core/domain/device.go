@@ -13,7 +13,21 @@ const (
 // DeviceInfo 包含设备的静态属性
 // 对应需求 3.2: 设备信息（型号、类型）
 type DeviceInfo struct {
+	// TenantID 设备所属租户 (多租户部署)，为空表示默认租户；设备ID只需在租户内唯一
+	TenantID string `json:"tenant_id,omitempty"`
+
 	ID    string     `json:"device_id"`
 	Model string     `json:"model"`
 	Type  DeviceType `json:"type"`
+	Unit  Unit       `json:"unit,omitempty"` // 读数的原生计量单位 (如 kWh / Wh)，为空表示未知
+
+	// Timezone 设备所在地的 IANA 时区 (如 "Asia/Shanghai")，用于按本地时间对齐标准时间网格
+	Timezone string `json:"timezone,omitempty"`
+
+	// Calibration 计量校准系数 (CT/PT 变比、倍率、偏移)，为空表示读数已是一次值
+	// 清洗规则作用于原始值；校准在标准化转换时应用，并记录在 StandardReading 上
+	Calibration *Calibration `json:"calibration,omitempty"`
+
+	// Tags 设备标签 (如 site=A, vendor=X)，用于规则豁免等按标签选择设备的场景
+	Tags map[string]string `json:"tags,omitempty"`
 }
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// ForecastPoint 预测序列上的一个时间点
+type ForecastPoint struct {
+	Timestamp   time.Time `json:"timestamp"`    // 区间开始时间 (标准时间网格)
+	Value       float64   `json:"value"`        // 预测值 (展示用)
+	ValueScaled int64     `json:"value_scaled"` // 预测值的定点整数
+	ScaleFactor int       `json:"scale_factor"` // 精度因子
+}
+
+// Forecast 设备用量预测
+type Forecast struct {
+	DeviceID    string          `json:"device_id"`
+	Metric      Metric          `json:"metric,omitempty"`
+	Method      string          `json:"method"`   // 预测方法 (如 "seasonal-naive")
+	Interval    time.Duration   `json:"interval"` // 预测粒度
+	Start       time.Time       `json:"start"`    // 预测区间 [Start, End)
+	End         time.Time       `json:"end"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Points      []ForecastPoint `json:"points"` // 按时间升序；历史上没有对应读数的时间点不给出
+}
+
+// Total 返回预测区间的用量合计 (展示用浮点值，预算报表与实际用量对比时使用)
+func (f Forecast) Total() float64 {
+	var total float64
+	for _, p := range f.Points {
+		total += p.Value
+	}
+	return total
+}
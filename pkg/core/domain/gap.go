@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// DataGap 数据缺口事件
+// 标准化过程中，某设备在连续若干个标准时间点上找不到可用读数时产生，
+// 供运维侧及时触发补抄，而不是等到月报出现空洞才发现。
+type DataGap struct {
+	DeviceID      string        `json:"device_id"`
+	From          time.Time     `json:"from"`           // 第一个缺失的标准时间点
+	To            time.Time     `json:"to"`             // 最后一个缺失的标准时间点 (含)
+	Interval      time.Duration `json:"interval"`       // 标准时间间隔
+	ExpectedCount int           `json:"expected_count"` // 缺失的标准读数个数
+	DetectedAt    time.Time     `json:"detected_at"`
+
+	// Metric 缺口所在的计量通道 (为空表示默认通道)
+	Metric Metric `json:"metric,omitempty"`
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeGrid 标准时间网格
+// 网格按 Location 的本地时间对齐: 日粒度的边界是本地零点，夏令时切换日为 23 / 25 小时；
+// 小于一天的间隔从本地零点起按绝对时长推进。
+// Anchor 使全部网格点整体平移，例如 15m 网格 Anchor=5m 时网格点为 :05/:20/:35/:50。
+type TimeGrid struct {
+	Interval time.Duration
+	Location *time.Location // 为空表示 UTC
+	Anchor   time.Duration  // 网格点相对默认边界的偏移 (按 Interval 取模)
+}
+
+// NewTimeGrid 创建时间网格
+func NewTimeGrid(interval time.Duration, loc *time.Location) TimeGrid {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return TimeGrid{Interval: interval, Location: loc}
+}
+
+// WithAnchor 返回网格点平移 offset 后的网格
+func (g TimeGrid) WithAnchor(offset time.Duration) TimeGrid {
+	g.Anchor = offset
+	return g
+}
+
+// anchor 返回归一化到 [0, Interval) 的偏移
+func (g TimeGrid) anchor() time.Duration {
+	if g.Interval <= 0 {
+		return 0
+	}
+	a := g.Anchor % g.Interval
+	if a < 0 {
+		a += g.Interval
+	}
+	return a
+}
+
+// daily 间隔是否为整天 (按日历日推进)
+func (g TimeGrid) daily() bool {
+	return g.Interval >= 24*time.Hour && g.Interval%(24*time.Hour) == 0
+}
+
+// Floor 返回不晚于 t 的最近网格点
+func (g TimeGrid) Floor(t time.Time) time.Time {
+	a := g.anchor()
+	if a == 0 {
+		return g.floor(t)
+	}
+	return g.floor(t.Add(-a)).Add(a)
+}
+
+// floor 未平移网格上不晚于 t 的最近网格点
+func (g TimeGrid) floor(t time.Time) time.Time {
+	loc := g.loc()
+	local := t.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	if g.daily() {
+		days := int(g.Interval / (24 * time.Hour))
+		if days == 1 {
+			return midnight
+		}
+		// 多日间隔: 以 Unix 纪元日为锚点对齐
+		epochDay := time.Date(1970, 1, 1, 0, 0, 0, 0, loc)
+		elapsed := int(midnight.Sub(epochDay).Round(time.Hour).Hours() / 24)
+		return midnight.AddDate(0, 0, -(elapsed % days))
+	}
+
+	if g.Interval >= 24*time.Hour || (24*time.Hour)%g.Interval != 0 {
+		// 无法整除一天的间隔无法按本地日对齐，退化为绝对时间截断
+		return t.Truncate(g.Interval)
+	}
+	offset := t.Sub(midnight)
+	return midnight.Add(offset - offset%g.Interval)
+}
+
+// Ceil 返回不早于 t 的最近网格点
+func (g TimeGrid) Ceil(t time.Time) time.Time {
+	f := g.Floor(t)
+	if f.Equal(t) {
+		return f
+	}
+	return g.Next(f)
+}
+
+// Next 返回网格点 t 的下一个网格点
+func (g TimeGrid) Next(t time.Time) time.Time {
+	a := g.anchor()
+	if a == 0 {
+		return g.next(t)
+	}
+	return g.next(t.Add(-a)).Add(a)
+}
+
+// next 未平移网格上网格点 t 的下一个网格点
+func (g TimeGrid) next(t time.Time) time.Time {
+	if g.daily() {
+		return t.In(g.loc()).AddDate(0, 0, int(g.Interval/(24*time.Hour)))
+	}
+	next := t.Add(g.Interval)
+	// 跨越本地零点时重新对齐 (夏令时切换日的零点偏移不同)
+	if f := g.floor(next); !f.Equal(next) && f.After(t) {
+		return f
+	}
+	return next
+}
+
+func (g TimeGrid) loc() *time.Location {
+	if g.Location == nil {
+		return time.UTC
+	}
+	return g.Location
+}
+
+// ResolutionTag 返回时间分辨率的标签，用于区分同一设备不同粒度的标准读数
+// 例如: 15m -> "15m", 1h -> "1h", 24h -> "1d"
+func ResolutionTag(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return ""
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return d.String()
+	}
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // IngestStrategy 定义数据摄入策略和优先级
 // 用于解决“后到数据”与“已有数据”的冲突 (Backfilling & Conflict Resolution)
@@ -29,6 +32,25 @@ type IngestContext struct {
 	Strategy IngestStrategy
 	Operator string // 操作人 (SYSTEM 或 具体User)
 	BatchID  string // 批次号
+
+	// Location 可选: 本次处理的时间网格时区 (为空时使用设备或服务的默认时区)
+	Location *time.Location
+}
+
+// Provenance 数据来源: 由哪次调用 (TraceID)、哪个操作人、哪个批次导入
+// 标准化服务从 IngestContext 复制到标准读数与隔离记录上并随之持久化，使每个标准值都可追溯到导入者
+type Provenance struct {
+	TraceID  string `json:"trace_id,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	BatchID  string `json:"batch_id,omitempty"`
+}
+
+// Provenance 返回上下文中的来源信息，三项均为空时返回 nil
+func (c IngestContext) Provenance() *Provenance {
+	if c.TraceID == "" && c.Operator == "" && c.BatchID == "" {
+		return nil
+	}
+	return &Provenance{TraceID: c.TraceID, Operator: c.Operator, BatchID: c.BatchID}
 }
 
 // GetPriority 根据策略获取具体的优先级数值
@@ -1,10 +1,19 @@
 package domain
 
+import "time"
+
 // IngestionResult 导入结果统计
 type IngestionResult struct {
-	Total   int      `json:"total"`
-	Success int      `json:"success"`
-	Failed  int      `json:"failed"`
-	Skipped int      `json:"skipped"` // 重复或其他原因跳过
-	Errors  []string `json:"errors"`  // 具体的错误信息
+	Total   int `json:"total"`
+	Success int `json:"success"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"` // 重复或其他原因跳过
+	// DeadLettered 解析成功但重试后仍无法交付下游、已写入死信输出的记录数 (不计入 Success)
+	DeadLettered int      `json:"dead_lettered"`
+	Errors       []string `json:"errors"` // 具体的错误信息
+
+	// 性能统计 (由摄入器填写): 导入任务可直接上报，无需在外层另行计时
+	Duration         time.Duration `json:"duration_ns"`        // 摄入耗时，包含下游 (标准化、持久化) 的处理时间
+	BytesRead        int64         `json:"bytes_read"`         // 从输入流读取的字节数
+	RecordsPerSecond float64       `json:"records_per_second"` // 每秒处理的记录数 (Total / Duration)
 }
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Metric 计量通道标识
+// 同一台设备可同时上报多个通道 (如电量与功率)，清洗与对齐按 设备 + 通道 独立进行。
+// 为空表示设备的默认通道 (兼容只有单一通道的数据源)。
+type Metric string
+
+const (
+	MetricEnergy  Metric = "ENERGY"  // 累计电量/热量
+	MetricPower   Metric = "POWER"   // 有功功率
+	MetricVoltage Metric = "VOLTAGE" // 电压
+	MetricFlow    Metric = "FLOW"    // 瞬时流量
+)
+
+// ChannelID 返回计量通道的唯一标识: 默认通道为设备ID，其余为 `"设备ID"#通道`
+// 设备ID以 Go 字符串字面量的形式引用 (默认通道的设备ID以引号开头时同样引用)，
+// 设备ID中的 "#" 不会使不同的 (设备, 通道) 得到相同的结果。
+func ChannelID(deviceID string, metric Metric) string {
+	switch {
+	case metric != "":
+		return strconv.Quote(deviceID) + "#" + string(metric)
+	case strings.HasPrefix(deviceID, `"`):
+		return strconv.Quote(deviceID)
+	}
+	return deviceID
+}
+
+// ChannelID 返回读数所属计量通道的唯一标识 (非默认租户带租户前缀，不同租户的同名设备互不干扰)
+func (r Reading) ChannelID() string {
+	return TenantScopedID(r.DeviceInfo.TenantID, ChannelID(r.DeviceInfo.ID, r.Metric))
+}
+
+// ChannelID 返回标准读数所属计量通道的唯一标识 (非默认租户带租户前缀)
+func (r StandardReading) ChannelID() string {
+	return TenantScopedID(r.TenantID, ChannelID(r.DeviceID, r.Metric))
+}
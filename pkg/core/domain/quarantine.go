@@ -11,17 +11,39 @@ const (
 	QuarantineStatusIgnored  QuarantineStatus = "IGNORED"  // 已忽略 (确认无效)
 )
 
+// QuarantineCode 隔离原因的类型化代码，便于按类别统计与处理 (Reason 为人类可读的详情)
+type QuarantineCode string
+
+const (
+	QuarantineCodeRuleRejected  QuarantineCode = "RULE_REJECTED"  // 清洗规则拒绝 (见 RuleID)
+	QuarantineCodeMissingValue  QuarantineCode = "MISSING_VALUE"  // 缺失值未被插补
+	QuarantineCodeDuplicate     QuarantineCode = "DUPLICATE"      // 同设备同时间戳的重复读数
+	QuarantineCodeOutOfOrder    QuarantineCode = "OUT_OF_ORDER"   // 流式模式下乱序到达
+	QuarantineCodeScaleOverflow QuarantineCode = "SCALE_OVERFLOW" // 定点换算结果超出 int64 范围 (常见于单位错配，如 Wh 当作 kWh)
+	QuarantineCodeInvalidFactor QuarantineCode = "INVALID_FACTOR" // 配置的精度因子不合法
+)
+
 // QuarantineReading 代表一条被“隔离”审查的异常数据
 // 当数据未通过 Sanitizer 清洗规则时，会被封装为此对象存入隔离区
 type QuarantineReading struct {
 	ID        string           `json:"id"`
-	Reading   Reading          `json:"reading"`    // 原始读数快照
-	Reason    string           `json:"reason"`     // 隔离原因 (e.g. "Value -50 below range min 0")
-	RuleID    string           `json:"rule_id"`    // 触发的规则ID
-	CreatedAt time.Time        `json:"created_at"` // 隔离时间
-	UpdatedAt time.Time        `json:"updated_at"` // 更新时间
-	Status    QuarantineStatus `json:"status"`     // 当前状态
+	TenantID  string           `json:"tenant_id,omitempty"` // 所属租户 (与 Reading.DeviceInfo.TenantID 一致)
+	Reading   Reading          `json:"reading"`             // 原始读数快照
+	Reason    string           `json:"reason"`              // 隔离原因 (e.g. "Value -50 below range min 0")
+	Code      QuarantineCode   `json:"code"`                // 隔离原因代码
+	RuleID    string           `json:"rule_id"`             // 触发的规则ID
+	CreatedAt time.Time        `json:"created_at"`          // 隔离时间
+	UpdatedAt time.Time        `json:"updated_at"`          // 更新时间
+	Status    QuarantineStatus `json:"status"`              // 当前状态
 
 	// 可选: 记录批次信息，方便批量重试
 	BatchID string `json:"batch_id,omitempty"`
+
+	// Provenance 数据来源 (取自隔离时的 IngestContext)，为空表示未记录
+	Provenance *Provenance `json:"provenance,omitempty"`
+
+	// 处理记录 (RESOLVED / IGNORED 时填写)
+	Operator       string   `json:"operator,omitempty"`        // 处理人
+	Note           string   `json:"note,omitempty"`            // 处理说明 (如忽略原因)
+	CorrectedValue *float64 `json:"corrected_value,omitempty"` // 人工修正后重新入库的值
 }
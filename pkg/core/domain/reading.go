@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 // ReadingType 定义读数类型
 type ReadingType string
@@ -18,6 +21,8 @@ const (
 	QualityCorrected    QualityState = "CORRECTED"    // 已修正 (如去噪、插值)
 	QualityEstimated    QualityState = "ESTIMATED"    // 估算值
 	QualityInterpolated QualityState = "INTERPOLATED" // 插值生成 (频率对齐产物)
+	QualityMissing      QualityState = "MISSING"      // 缺失值 (源数据为空/NaN，待插补)
+	QualityWithdrawn    QualityState = "WITHDRAWN"    // 已撤回 (保留用于审计，下游不应再使用，见 StandardReading.Withdrawal)
 )
 
 // Reading 代表一次原始读数
@@ -25,20 +30,106 @@ type Reading struct {
 	DeviceInfo DeviceInfo `json:"device_info"`
 	Timestamp  time.Time  `json:"timestamp"`
 	Value      float64    `json:"value"` // 累积读数 (Cumulative Value)
+
+	// Metric 计量通道 (为空表示设备的默认通道)
+	Metric Metric `json:"metric,omitempty"`
+
+	// Quality 读数质量标记 (为空表示 VALID)
+	// 摄入层遇到空值/NaN 时标记为 MISSING，由清洗阶段负责插补
+	Quality QualityState `json:"quality,omitempty"`
+
+	// QualityReason 质量标记的原因 (如规则修正说明，多条以 "; " 连接)
+	QualityReason string `json:"quality_reason,omitempty"`
+
+	// Priority 读数级冲突优先级 (可选，0 表示沿用 IngestContext 的策略优先级)
+	// 用于同一时间戳存在多份读数时按优先级取舍
+	Priority int `json:"priority,omitempty"`
+
+	// ReceivedAt 摄入层接收到该读数的时间 (可选)
+	// 与设备上报的 Timestamp 对比可发现设备时钟偏移
+	ReceivedAt time.Time `json:"received_at,omitempty"`
+
+	// Confidence 清洗后的置信度 (0-1]，由规则链各规则的置信度相乘得到
+	// 为 0 表示尚未评估 (视为 1)
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// MarkCorrected 将读数标记为 CORRECTED 并追加原因
+// 已带有更具体质量标记 (如 ESTIMATED、INTERPOLATED) 的读数只追加原因
+func (r *Reading) MarkCorrected(reason string) {
+	if r.Quality == "" || r.Quality == QualityValid {
+		r.Quality = QualityCorrected
+	}
+	if reason == "" {
+		return
+	}
+	if r.QualityReason != "" {
+		r.QualityReason += "; "
+	}
+	r.QualityReason += reason
+}
+
+// IsMissing 判断读数是否为缺失值
+func (r Reading) IsMissing() bool {
+	return r.Quality == QualityMissing || math.IsNaN(r.Value)
 }
 
 // StandardReading 代表“数据标准”输出
 // 对应核心竞争力: 帮下游平台“避坑” & “数据标准”
 type StandardReading struct {
-	DeviceID     string       `json:"device_id"`
-	Timestamp    time.Time    `json:"timestamp"`     // 标准时间点 (e.g. 10:00:00)
-	ValueScaled  int64        `json:"value_scaled"`  // 统一度量衡: 高精度整型值
-	ScaleFactor  int          `json:"scale_factor"`  // 精度因子 (e.g. 10000)
-	ValueDisplay float64      `json:"value_display"` // 展示用浮点值
-	Quality      QualityState `json:"quality"`       // 数据质量标记
-	SourceType   ReadingType  `json:"source_type"`   // 数据来源类型
+	TenantID      string       `json:"tenant_id,omitempty"` // 所属租户 (为空表示默认租户)
+	DeviceID      string       `json:"device_id"`
+	Metric        Metric       `json:"metric,omitempty"`         // 计量通道 (为空表示默认通道)
+	Timestamp     time.Time    `json:"timestamp"`                // 标准时间点 (e.g. 10:00:00)
+	Resolution    string       `json:"resolution"`               // 时间分辨率标签 (e.g. "15m", "1h", "1d")，同一设备同一时间点可存在多个分辨率
+	ValueScaled   int64        `json:"value_scaled"`             // 统一度量衡: 高精度整型值
+	ScaleFactor   int          `json:"scale_factor"`             // 精度因子 (e.g. 10000)
+	ValueDisplay  float64      `json:"value_display"`            // 展示用浮点值
+	Quality       QualityState `json:"quality"`                  // 数据质量标记
+	QualityReason string       `json:"quality_reason,omitempty"` // 非 VALID 时的原因 (如修正说明)
+	Confidence    float64      `json:"confidence"`               // 置信度 (0-1)，供下游分析加权使用
+	SourceType    ReadingType  `json:"source_type"`              // 数据来源类型
+	Calibration   *Calibration `json:"calibration,omitempty"`    // 已应用的校准系数 (为空表示未校准)
 
 	// 新增: 数据治理与冲突解决字段 (Phase 1 Backfilling Support)
 	IngestedAt time.Time `json:"ingested_at"` // 物理入库时间 (Physical Time)
 	Priority   int       `json:"priority"`    // 冲突优先级 (1000=Manual Fix, 100=Realtime, 50=Late Batch)
+
+	// Withdrawal 撤回记录 (为空表示未撤回)
+	// 撤回不删除数据: Quality 置为 WITHDRAWN，原质量标记保存在撤回记录中；同一键重新写入的新读数会清除撤回状态
+	Withdrawal *Withdrawal `json:"withdrawal,omitempty"`
+
+	// Provenance 数据来源 (取自写入时的 IngestContext)，为空表示未记录
+	Provenance *Provenance `json:"provenance,omitempty"`
+
+	// Version 版本号 (从 1 开始)，仅由 StandardReadingVersioner.FindVersions 填写；普通查询恒为 0
+	Version int `json:"version,omitempty"`
 }
+
+// Withdrawal 标准读数的撤回记录 (审计用)
+// 场景: 校准发现历史数据有误时，撤回受影响的标准读数并通知下游更正，而不是直接删除
+type Withdrawal struct {
+	Reason          string       `json:"reason"`                     // 撤回原因
+	Operator        string       `json:"operator"`                   // 操作人
+	At              time.Time    `json:"at"`                         // 撤回时间
+	PreviousQuality QualityState `json:"previous_quality,omitempty"` // 撤回前的质量标记
+}
+
+// Withdraw 将标准读数标记为已撤回 (已撤回的读数保留最初的撤回记录)
+func (sr *StandardReading) Withdraw(reason, operator string, at time.Time) {
+	if sr.Withdrawal != nil {
+		return
+	}
+	sr.Withdrawal = &Withdrawal{Reason: reason, Operator: operator, At: at, PreviousQuality: sr.Quality}
+	sr.Quality = QualityWithdrawn
+}
+
+// DuplicatePolicy 定义同设备同时间戳的重复读数处理策略
+type DuplicatePolicy string
+
+const (
+	DuplicateKeepFirst       DuplicatePolicy = "KEEP_FIRST"            // 保留最先到达的一条 (默认)
+	DuplicateKeepLast        DuplicatePolicy = "KEEP_LAST"             // 保留最后到达的一条 (适用于重传网关)
+	DuplicateKeepHighestPrio DuplicatePolicy = "KEEP_HIGHEST_PRIORITY" // 保留 Priority 最高的一条 (同优先级取先到达者)
+	DuplicateAverage         DuplicatePolicy = "AVERAGE"               // 合并为平均值
+)
@@ -3,15 +3,64 @@ package domain
 import "time"
 
 // ReportPeriod 报表统计维度
-// 对应需求 2: 多维聚合 (小时、日、月)
+// 对应需求 2: 多维聚合 (小时、日、周、月)
 type ReportPeriod string
 
 const (
 	ReportPeriodHour  ReportPeriod = "HOUR"
 	ReportPeriodDay   ReportPeriod = "DAY"
+	ReportPeriodWeek  ReportPeriod = "WEEK" // 自然周 (周一开始，同 ISO 8601)
 	ReportPeriodMonth ReportPeriod = "MONTH"
 )
 
+// Valid 判断是否为已定义的统计维度
+func (p ReportPeriod) Valid() bool {
+	switch p {
+	case ReportPeriodHour, ReportPeriodDay, ReportPeriodWeek, ReportPeriodMonth:
+		return true
+	}
+	return false
+}
+
+// Start 返回 t 所在统计周期在 loc 时区下的开始时间 (loc 为空表示 UTC)
+// 日、月周期按当地日历切分，跨夏令时的日长度可能不是 24 小时
+func (p ReportPeriod) Start(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	switch p {
+	case ReportPeriodHour:
+		// 按当地时间截去分秒，而非 time.Date 重建: 夏令时结束时重复的一小时 (如 01:00 EDT / 01:00 EST) 是两个不同的周期
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case ReportPeriodWeek:
+		return time.Date(t.Year(), t.Month(), t.Day()-(int(t.Weekday())+6)%7, 0, 0, 0, 0, loc)
+	case ReportPeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+// Next 返回 start (Start 的结果) 所在统计周期的结束时间，即下一个周期的开始时间
+// 小时周期按绝对时长推进；日、月周期按当地日历推进: 夏令时切换日为 23 / 25 小时，月长度为 28 - 31 天
+func (p ReportPeriod) Next(start time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t := start.In(loc)
+	switch p {
+	case ReportPeriodHour:
+		return t.Add(time.Hour)
+	case ReportPeriodWeek:
+		return time.Date(t.Year(), t.Month(), t.Day()+7, 0, 0, 0, 0, loc)
+	case ReportPeriodMonth:
+		return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+	}
+}
+
 // EnergyReport 能耗统计报表
 // 对应需求 3.3: 步长聚合 & 分类统计
 type EnergyReport struct {
@@ -19,6 +68,7 @@ type EnergyReport struct {
 	DeviceID    string       `json:"device_id"`
 	DeviceModel string       `json:"device_model"`
 	DeviceType  DeviceType   `json:"device_type"`
+	Metric      Metric       `json:"metric,omitempty"` // 计量通道 (为空表示默认通道)
 	Period      ReportPeriod `json:"period"`
 	StartTime   time.Time    `json:"start_time"`  // 统计周期开始时间
 	EndTime     time.Time    `json:"end_time"`    // 统计周期结束时间
@@ -28,4 +78,64 @@ type EnergyReport struct {
 	// 使用整型存储避免浮点数计算误差
 	UsageScaled int64 `json:"usage_scaled"` // 缩放后的整数值 (e.g. 10.1234 -> 101234)
 	ScaleFactor int   `json:"scale_factor"` // 缩放因子 (e.g. 10000)
+
+	// Carbon 该周期的碳排放 (未配置排放因子或因子不完整时为空)
+	Carbon *CarbonEmission `json:"carbon,omitempty"`
+
+	// Weather 该周期的度日数与气候修正后的用量 (未配置气象数据时为空)
+	Weather *WeatherNormalization `json:"weather,omitempty"`
+}
+
+// CarbonEmission 统计周期的碳排放 (二氧化碳当量)
+// 供可持续发展报告使用: 用量 x 排放因子，时变因子按各区间的开始时刻取值
+type CarbonEmission struct {
+	CO2eKg     float64 `json:"co2e_kg"`     // 排放量 (kg CO₂e)，展示用
+	CO2eScaled int64   `json:"co2e_scaled"` // 缩放后的整数值，缩放因子与报表的 ScaleFactor 相同
+}
+
+// EmissionFactor 排放因子: 每单位用量 (设备的计量单位，如 kWh、m³) 排放的 kg CO₂e
+// 按设备类型区分能源载体 (电、燃气、热)；同一载体配置多个生效时间即可表示时变的电网排放强度 (年度因子或逐小时强度)
+type EmissionFactor struct {
+	DeviceType    DeviceType `json:"device_type,omitempty"` // 适用的设备类型 (为空表示全部)
+	Metric        Metric     `json:"metric,omitempty"`      // 适用的计量通道 (为空表示全部)
+	KgCO2ePerUnit float64    `json:"kg_co2e_per_unit"`      // 排放因子
+	ValidFrom     time.Time  `json:"valid_from,omitempty"`  // 生效时间 (零值表示一直有效)，直到同一范围内下一个因子生效
+}
+
+// DegreeDays 度日数 (以基准温度计，如 18°C)
+type DegreeDays struct {
+	Heating float64 `json:"heating"` // 采暖度日数 (HDD)
+	Cooling float64 `json:"cooling"` // 制冷度日数 (CDD)
+}
+
+// DegreeDayBasis 气候修正所依据的度日数
+type DegreeDayBasis string
+
+const (
+	DegreeDayHeating DegreeDayBasis = "HEATING" // 仅采暖度日数 (如燃气、供热)
+	DegreeDayCooling DegreeDayBasis = "COOLING" // 仅制冷度日数 (如冷水机组)
+	DegreeDayTotal   DegreeDayBasis = "TOTAL"   // 采暖与制冷度日数之和 (如全年运行空调的建筑用电)
+)
+
+// Of 返回该依据下的度日数
+func (b DegreeDayBasis) Of(dd DegreeDays) float64 {
+	switch b {
+	case DegreeDayHeating:
+		return dd.Heating
+	case DegreeDayCooling:
+		return dd.Cooling
+	default:
+		return dd.Heating + dd.Cooling
+	}
+}
+
+// WeatherNormalization 统计周期的气候修正
+// 度日法: 修正用量 = 实际用量 x 常年度日数 / 实际度日数，使天气不同的月份可以公平比较
+type WeatherNormalization struct {
+	Basis             DegreeDayBasis `json:"basis"`
+	Actual            DegreeDays     `json:"actual"`               // 周期内的实际度日数
+	Normal            DegreeDays     `json:"normal"`               // 同一时段的常年度日数
+	UsagePerDegreeDay float64        `json:"usage_per_degree_day"` // 单位度日数的用量 (实际度日数为 0 时为 0)
+	NormalizedUsage   float64        `json:"normalized_usage"`     // 修正用量 (展示用)
+	NormalizedScaled  int64          `json:"normalized_scaled"`    // 修正用量的定点值，缩放因子与报表的 ScaleFactor 相同
 }
@@ -7,6 +7,28 @@ const (
 	RuleTypeRange RuleType = "RANGE" // 范围检查 (Min/Max)
 	RuleTypeRate  RuleType = "RATE"  // 变化率检查
 	RuleTypeTrend RuleType = "TREND" // 趋势检查
+
+	// RuleTypeBidirectional 双向计量检查 (允许读数下降)
+	// 适用于光伏等具备反向输出能力的净计量电表，以翻转和速率限制代替回退检查
+	RuleTypeBidirectional RuleType = "BIDIRECTIONAL_MONOTONIC"
+
+	// RuleTypeImpute 缺失值插补 (LOCF / 线性插值 / 拒绝)
+	RuleTypeImpute RuleType = "IMPUTE"
+
+	// RuleTypeSpike 孤立尖峰检查 (尖峰后立即回归趋势)
+	RuleTypeSpike RuleType = "SPIKE"
+
+	// RuleTypeTimeWindow 时间窗口检查 (读数落在窗口外即触发，常作为组合规则的条件)
+	RuleTypeTimeWindow RuleType = "TIME_WINDOW"
+
+	// RuleTypeComposite 组合规则 (AND / OR / NOT 组合子规则的触发条件)
+	RuleTypeComposite RuleType = "COMPOSITE"
+
+	// RuleTypeScript 脚本规则 (沙箱表达式，运维可在不发版的情况下配置一次性清洗逻辑)
+	RuleTypeScript RuleType = "SCRIPT"
+
+	// RuleTypeTimestamp 时间戳合理性检查 (未来时间 / 过期时间)
+	RuleTypeTimestamp RuleType = "TIMESTAMP_SANITY"
 )
 
 // RuleAction 定义规则触发后的处理策略
@@ -28,4 +50,82 @@ type CleaningRule struct {
 	Enabled    bool           `json:"enabled"`
 	Parameters map[string]any `json:"parameters"` // 规则参数 (例如: {"min": 0, "max": 100})
 	Priority   int            `json:"priority"`   // 执行优先级
+
+	// DeviceIDs 规则作用范围: 仅对列出的设备生效 (为空表示该设备类型下的全部设备)
+	DeviceIDs []string `json:"device_ids,omitempty"`
+
+	// Exemptions 豁免名单: 命中的设备跳过该规则 (用于个别已知异常表计，无需全局停用规则)
+	Exemptions RuleExemptions `json:"exemptions"`
+}
+
+// AppliesTo 判断规则是否作用于该设备 (考虑作用范围与豁免名单)
+func (r CleaningRule) AppliesTo(device DeviceInfo) bool {
+	if len(r.DeviceIDs) > 0 {
+		found := false
+		for _, id := range r.DeviceIDs {
+			if id == device.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return !r.Exemptions.Matches(device)
+}
+
+// RuleExemptions 规则豁免名单
+type RuleExemptions struct {
+	DeviceIDs    []string            `json:"device_ids,omitempty"`    // 按设备ID豁免
+	TagSelectors []map[string]string `json:"tag_selectors,omitempty"` // 按标签豁免: 设备满足任一选择器的全部标签即豁免
 }
+
+// IsEmpty 判断豁免名单是否为空
+func (e RuleExemptions) IsEmpty() bool {
+	return len(e.DeviceIDs) == 0 && len(e.TagSelectors) == 0
+}
+
+// Matches 判断设备是否在豁免名单中
+func (e RuleExemptions) Matches(device DeviceInfo) bool {
+	for _, id := range e.DeviceIDs {
+		if id == device.ID {
+			return true
+		}
+	}
+
+	for _, selector := range e.TagSelectors {
+		if len(selector) == 0 {
+			continue
+		}
+		matched := true
+		for k, v := range selector {
+			if device.Tags[k] != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ImputeStrategy 定义缺失值的插补策略
+type ImputeStrategy string
+
+const (
+	ImputeLOCF   ImputeStrategy = "LOCF"   // 沿用上一条有效读数 (Last Observation Carried Forward)
+	ImputeLinear ImputeStrategy = "LINEAR" // 使用前后有效读数进行线性插值
+	ImputeReject ImputeStrategy = "REJECT" // 不插补，直接拒绝
+)
+
+// CompositeOperator 定义组合规则的布尔运算符
+type CompositeOperator string
+
+const (
+	CompositeAnd CompositeOperator = "AND" // 所有子规则均触发
+	CompositeOr  CompositeOperator = "OR"  // 任一子规则触发
+	CompositeNot CompositeOperator = "NOT" // 唯一的子规则未触发
+)
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// StandardizationResult 一次标准化处理的结果
+// 单台设备失败不会作废整个批次: 成功设备的标准读数照常输出，失败设备记录在 DeviceErrors 中
+// (多通道设备按计量通道处理，键为 ChannelID)
+type StandardizationResult struct {
+	Readings     []StandardReading `json:"readings"`
+	DeviceErrors map[string]error  `json:"-"` // 设备ID (或 ChannelID) -> 错误 (无失败时为 nil)
+
+	// Conflicts 本批次内按优先级裁决的槽位冲突 (同一槽位上存在不同来源的读数)
+	Conflicts []PriorityConflict `json:"conflicts,omitempty"`
+
+	// PendingApproval 批次未写入、等待审批时的审批记录 ID (见 CoreStandardizer.Approve)
+	PendingApproval string `json:"pending_approval,omitempty"`
+}
+
+// PriorityConflict 同一次处理中，同一 (设备, 槽位) 上出现不同优先级读数时的裁决记录
+// 冲突在持久化之前按优先级解决，不依赖仓储的 upsert 语义；胜出读数的优先级即标准读数的 Priority
+type PriorityConflict struct {
+	DeviceID       string    `json:"device_id"`
+	Metric         Metric    `json:"metric,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`            // 槽位时间
+	Resolution     string    `json:"resolution,omitempty"` // 槽位所在网格的分辨率 (标准间隔)
+	WinnerPriority int       `json:"winner_priority"`      // 胜出读数的优先级
+	LoserPriority  int       `json:"loser_priority"`       // 被覆盖读数中的最高优先级
+	Overridden     int       `json:"overridden"`           // 被覆盖的读数数
+}
+
+// Failed 判断是否存在处理失败的设备
+func (r *StandardizationResult) Failed() bool {
+	return len(r.DeviceErrors) > 0
+}
+
+// Err 将各设备错误合并为一个 error (按设备ID排序)，无失败时返回 nil
+func (r *StandardizationResult) Err() error {
+	if !r.Failed() {
+		return nil
+	}
+	ids := make([]string, 0, len(r.DeviceErrors))
+	for id := range r.DeviceErrors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	errs := make([]error, 0, len(ids))
+	for _, id := range ids {
+		errs = append(errs, fmt.Errorf("device %s: %w", id, r.DeviceErrors[id]))
+	}
+	return errors.Join(errs...)
+}
+
+// BatchFingerprint 计算一批标准读数的幂等键
+// 由批次号 (可为空) 与内容哈希组成，与读数顺序无关；IngestedAt 不参与计算，
+// 因此同一批数据重试时得到相同的键，而同一批次号下内容不同 (如规则修正后重算) 则得到不同的键。
+func BatchFingerprint(batchID string, readings []StandardReading) string {
+	digests := make([][sha256.Size]byte, len(readings))
+	for i, r := range readings {
+		digests[i] = readingDigest(r)
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return string(digests[i][:]) < string(digests[j][:])
+	})
+
+	h := sha256.New()
+	for _, d := range digests {
+		h.Write(d[:])
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if batchID == "" {
+		return "sha256:" + sum
+	}
+	return "batch:" + batchID + ":sha256:" + sum
+}
+
+// readingDigest 单条标准读数的内容摘要 (不含 IngestedAt)
+func readingDigest(r StandardReading) [sha256.Size]byte {
+	h := sha256.New()
+	var buf [8]byte
+	writeString := func(s string) {
+		binary.BigEndian.PutUint64(buf[:], uint64(len(s)))
+		h.Write(buf[:])
+		h.Write([]byte(s))
+	}
+	writeInt := func(v int64) {
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		h.Write(buf[:])
+	}
+
+	if r.TenantID != "" {
+		writeString("tenant:" + r.TenantID) // 默认租户不参与，同上
+	}
+	writeString(r.DeviceID)
+	if r.Metric != "" {
+		writeString(string(r.Metric)) // 默认通道不参与，保持已有批次的幂等键不变
+	}
+	writeInt(r.Timestamp.UnixNano())
+	writeString(r.Resolution)
+	writeInt(r.ValueScaled)
+	writeInt(int64(r.ScaleFactor))
+	writeString(string(r.Quality))
+	writeInt(int64(r.Priority))
+
+	var d [sha256.Size]byte
+	copy(d[:], h.Sum(nil))
+	return d
+}
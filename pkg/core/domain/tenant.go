@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrTenantMismatch 读数的租户与处理上下文的租户不一致，或一个批次混合了多个租户
+var ErrTenantMismatch = errors.New("tenant mismatch")
+
+type tenantContextKey struct{}
+
+// WithTenant 返回携带租户ID的 Context
+// 多租户部署中，标准化服务与仓储按此租户隔离读数: 写入时为缺省租户的读数补全，查询时只返回该租户的数据。
+// 空字符串表示默认租户 (单租户部署无需设置)。
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext 返回 ctx 携带的租户ID，未设置时返回空字符串 (默认租户)
+func TenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantContextKey{}).(string)
+	return tenantID
+}
+
+// ResolveTenant 统一批次的租户: 未设置租户的读数使用 ctx 的租户，
+// 与 ctx 租户不一致或 (ctx 未设置租户时) 批次混合多个租户返回 ErrTenantMismatch。
+// 返回携带批次租户的 Context，供后续查询与持久化使用；readings 原地修改。
+func ResolveTenant(ctx context.Context, readings []Reading) (context.Context, error) {
+	tenantID := TenantFromContext(ctx)
+	fromCtx := tenantID != ""
+	for i := range readings {
+		r := &readings[i]
+		switch {
+		case r.DeviceInfo.TenantID == "":
+			continue
+		case tenantID == "" && !fromCtx:
+			tenantID = r.DeviceInfo.TenantID
+		case r.DeviceInfo.TenantID != tenantID:
+			return ctx, fmt.Errorf("reading of device %s belongs to tenant %q, batch tenant is %q: %w",
+				r.DeviceInfo.ID, r.DeviceInfo.TenantID, tenantID, ErrTenantMismatch)
+		}
+	}
+	if tenantID == "" {
+		return ctx, nil
+	}
+	for i := range readings {
+		readings[i].DeviceInfo.TenantID = tenantID
+	}
+	if fromCtx {
+		return ctx, nil
+	}
+	return WithTenant(ctx, tenantID), nil
+}
+
+// TenantScopedID 返回带租户前缀的标识 (`"租户"/标识`)，默认租户不加前缀
+// 租户以 Go 字符串字面量的形式引用，租户或标识中的 "/" 都不会使不同的 (租户, 标识) 得到相同的结果。
+func TenantScopedID(tenantID, id string) string {
+	if tenantID == "" {
+		return id
+	}
+	return strconv.Quote(tenantID) + "/" + id
+}
@@ -0,0 +1,21 @@
+package domain
+
+// NodeLevel 设备层级树的节点层级 (常见为 园区 → 楼栋 → 楼层 → 设备，可按客户自定义)
+type NodeLevel string
+
+const (
+	NodeLevelSite     NodeLevel = "SITE"     // 园区 / 站点
+	NodeLevelBuilding NodeLevel = "BUILDING" // 楼栋
+	NodeLevelFloor    NodeLevel = "FLOOR"    // 楼层
+	NodeLevelDevice   NodeLevel = "DEVICE"   // 计量设备 (叶子节点)
+)
+
+// TopologyNode 设备层级树的一个节点
+// 分组节点只有 ID / 名称 / 层级；设备节点通过 Device 关联计量设备，并作为叶子节点参与用量汇总。
+type TopologyNode struct {
+	ID       string      `json:"id"`
+	Name     string      `json:"name,omitempty"`
+	Level    NodeLevel   `json:"level,omitempty"`
+	ParentID string      `json:"parent_id,omitempty"` // 为空表示根节点
+	Device   *DeviceInfo `json:"device,omitempty"`    // 设备节点关联的设备 (分组节点为空)
+}
@@ -1,6 +1,35 @@
 package domain
 
-import "math"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+var (
+	// ErrScaleOverflow 定点换算结果超出 int64 范围 (或数值不是有限数)
+	ErrScaleOverflow = errors.New("scaled value overflows int64")
+	// ErrInvalidScaleFactor 精度因子不合法 (须为 1 到 MaxScaleFactor 之间的 10 的幂)
+	ErrInvalidScaleFactor = errors.New("invalid scale factor")
+)
+
+// MaxScaleFactor 允许的最大精度因子 (12 位小数)
+const MaxScaleFactor = 1_000_000_000_000
+
+// ValidateScaleFactor 校验精度因子: 须为 1 到 MaxScaleFactor 之间的 10 的幂
+func ValidateScaleFactor(factor int) error {
+	if factor <= 0 || factor > MaxScaleFactor {
+		return fmt.Errorf("%w: %d", ErrInvalidScaleFactor, factor)
+	}
+	for f := factor; f > 1; f /= 10 {
+		if f%10 != 0 {
+			return fmt.Errorf("%w: %d is not a power of 10", ErrInvalidScaleFactor, factor)
+		}
+	}
+	return nil
+}
 
 // Unifier 定义度量衡统一能力的接口
 // 核心职责：处理数值精度和单位转换
@@ -12,7 +41,8 @@ type Unifier interface {
 
 // MetricUnifier 默认实现：基于乘数因子的定点数转换
 type MetricUnifier struct {
-	Factor int // e.g., 10000 for 4 decimal places
+	Factor   int          // e.g., 10000 for 4 decimal places
+	Rounding RoundingMode // 舍入方式 (空值按 HALF_UP)
 }
 
 func NewUnifier(factor int) Unifier {
@@ -20,7 +50,7 @@ func NewUnifier(factor int) Unifier {
 }
 
 func (u *MetricUnifier) ToScaled(val float64) int64 {
-	return int64(math.Round(val * float64(u.Factor)))
+	return ScaleDecimal(val, u.Factor, u.Rounding)
 }
 
 func (u *MetricUnifier) FromScaled(val int64) float64 {
@@ -30,3 +60,89 @@ func (u *MetricUnifier) FromScaled(val int64) float64 {
 func (u *MetricUnifier) GetScaleFactor() int {
 	return u.Factor
 }
+
+// RoundingMode 定义定点换算时的舍入方式
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "HALF_UP"   // 四舍五入，半数远离零 (默认)
+	RoundHalfEven RoundingMode = "HALF_EVEN" // 银行家舍入，半数取偶 (部分地区计量法规要求)
+	RoundTruncate RoundingMode = "TRUNCATE"  // 向零截断
+)
+
+// ScaleExact 将浮点数按十进制精确放大为定点整数，并四舍五入 (半数远离零)
+// 等价于 ScaleDecimal(val, factor, RoundHalfUp)
+func ScaleExact(val float64, factor int) int64 {
+	return ScaleDecimal(val, factor, RoundHalfUp)
+}
+
+// ScaleDecimal 将浮点数按十进制精确放大为定点整数，按 mode 舍入 (未知模式按 HALF_UP 处理)
+// 以浮点数的最短十进制表示参与运算，避免 val*factor 的二进制误差:
+// 例如 100.00019 * 10000 在浮点下为 1000001.8999...，HALF_UP 下得到 1000002。
+// NaN/Inf 返回 0；超出 int64 范围时饱和到边界值 (需要识别溢出时使用 ScaleDecimalChecked)。
+func ScaleDecimal(val float64, factor int, mode RoundingMode) int64 {
+	v, _ := ScaleDecimalChecked(val, factor, mode)
+	return v
+}
+
+// ScaleDecimalChecked 与 ScaleDecimal 相同，但在结果超出 int64 范围或数值非有限数时返回 ErrScaleOverflow
+// (超出范围时同时返回饱和后的边界值)
+func ScaleDecimalChecked(val float64, factor int, mode RoundingMode) (int64, error) {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, fmt.Errorf("%w: non-finite value %v", ErrScaleOverflow, val)
+	}
+
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(val, 'f', -1, 64))
+	if !ok {
+		return 0, fmt.Errorf("%w: unparsable value %v", ErrScaleOverflow, val)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(int64(factor)))
+	v, err := RoundRat(r, mode)
+	if err != nil {
+		return v, fmt.Errorf("%w: %v x %d", ErrScaleOverflow, val, factor)
+	}
+	return v, nil
+}
+
+// RoundRat 将精确有理数按 mode 舍入为 int64 (未知模式按 HALF_UP 处理)
+// 用于定点值与系数 (如排放因子) 相乘后的精确舍入；超出 int64 范围时返回 ErrScaleOverflow 与饱和后的边界值
+func RoundRat(r *big.Rat, mode RoundingMode) (int64, error) {
+	// 商与余数 (Quo 向零截断)
+	num, den := r.Num(), r.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	if roundAway(quo, rem, den, mode) {
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	if !quo.IsInt64() {
+		if quo.Sign() < 0 {
+			return math.MinInt64, ErrScaleOverflow
+		}
+		return math.MaxInt64, ErrScaleOverflow
+	}
+	return quo.Int64(), nil
+}
+
+// roundAway 判断截断后的商是否需要向远离零的方向进一位
+func roundAway(quo, rem, den *big.Int, mode RoundingMode) bool {
+	if mode == RoundTruncate || rem.Sign() == 0 {
+		return false
+	}
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	switch cmp := twice.Cmp(den); {
+	case cmp > 0:
+		return true
+	case cmp < 0:
+		return false
+	case mode == RoundHalfEven:
+		return quo.Bit(0) == 1 // 恰好一半: 商为奇数时进位
+	default:
+		return true
+	}
+}
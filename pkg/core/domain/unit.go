@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unit 计量单位 (如 kWh, Wh, m3)
+type Unit string
+
+const (
+	UnitWh  Unit = "Wh"
+	UnitKWh Unit = "kWh"
+	UnitMWh Unit = "MWh"
+	UnitGJ  Unit = "GJ"
+	UnitMJ  Unit = "MJ"
+	UnitL   Unit = "L"
+	UnitM3  Unit = "m3"
+)
+
+// unitDef 单位定义: 所属量纲及换算到该量纲基准单位的系数
+type unitDef struct {
+	dimension string
+	factor    float64
+}
+
+// unitTable 已知单位表 (键为小写)
+// 能量以 Wh 为基准，体积以 L 为基准
+var unitTable = map[string]unitDef{
+	"wh":  {"energy", 1},
+	"kwh": {"energy", 1e3},
+	"mwh": {"energy", 1e6},
+	"mj":  {"energy", 1e6 / 3600},
+	"gj":  {"energy", 1e9 / 3600},
+	"l":   {"volume", 1},
+	"m3":  {"volume", 1e3},
+}
+
+// IsKnown 判断是否为已知单位
+func (u Unit) IsKnown() bool {
+	_, ok := unitTable[strings.ToLower(string(u))]
+	return ok
+}
+
+// ConvertValue 将数值从 from 单位换算到 to 单位
+// 任一单位为空时视为无单位信息，原样返回；量纲不同或单位未知时返回错误
+func ConvertValue(v float64, from, to Unit) (float64, error) {
+	if from == "" || to == "" || strings.EqualFold(string(from), string(to)) {
+		return v, nil
+	}
+	f, ok := unitTable[strings.ToLower(string(from))]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	t, ok := unitTable[strings.ToLower(string(to))]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if f.dimension != t.dimension {
+		return 0, fmt.Errorf("incompatible units %s (%s) and %s (%s)", from, f.dimension, to, t.dimension)
+	}
+	return v * f.factor / t.factor, nil
+}
@@ -0,0 +1,80 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// AggregateQuery 按周期聚合标准读数的查询条件
+type AggregateQuery struct {
+	DeviceID   string
+	Metric     domain.Metric       // 为空表示设备的默认通道
+	Resolution string              // 参与聚合的分辨率 (必填，避免同一时间点多个分辨率被重复计入)
+	Start, End time.Time           // 时间区间 [Start, End)
+	Period     domain.ReportPeriod // 聚合周期: HOUR / DAY / WEEK / MONTH
+	Location   *time.Location      // 周期切分所用时区 (为空表示 UTC)，需为 time.LoadLocation 加载的 IANA 时区
+}
+
+// Validate 校验查询条件
+func (q AggregateQuery) Validate() error {
+	if q.DeviceID == "" {
+		return errors.New("aggregate query: device id is required")
+	}
+	if q.Resolution == "" {
+		return errors.New("aggregate query: resolution is required")
+	}
+	if !q.Period.Valid() {
+		return fmt.Errorf("aggregate query: unsupported period %q", q.Period)
+	}
+	if !q.End.After(q.Start) {
+		return fmt.Errorf("aggregate query: end %s must be after start %s", q.End.Format(time.RFC3339), q.Start.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// AggregateBucket 一个聚合周期的结果
+type AggregateBucket struct {
+	Start time.Time // 周期开始时间 (查询时区)
+	Value float64   // 周期内 ValueDisplay 的合计或平均值
+	Count int64     // 参与聚合的读数条数
+}
+
+// StandardReadingAggregator 按周期聚合标准读数 (可选端口，由仓储适配器实现)
+// 场景: 报表统计由数据库完成聚合，避免把整段标准读数加载到内存；质量为 MISSING 的读数不参与聚合
+type StandardReadingAggregator interface {
+	// SumByPeriod 按周期求和 (适用于区间量，如各时段用量)，结果按周期升序，没有数据的周期不返回
+	SumByPeriod(ctx context.Context, q AggregateQuery) ([]AggregateBucket, error)
+
+	// AvgByPeriod 按周期求平均值 (适用于瞬时量，如功率、温度)，结果按周期升序，没有数据的周期不返回
+	AvgByPeriod(ctx context.Context, q AggregateQuery) ([]AggregateBucket, error)
+}
+
+// MergeBuckets 将更细粒度的部分和 (Value 为合计) 归并到查询周期 (适配器无法在数据库内按时区切分周期时使用)
+// avg 为 true 时各周期的 Value 为合计除以条数
+func MergeBuckets(q AggregateQuery, partials []AggregateBucket, avg bool) []AggregateBucket {
+	index := make(map[int64]int)
+	var out []AggregateBucket
+	for _, p := range partials {
+		start := q.Period.Start(p.Start, q.Location)
+		i, ok := index[start.UnixNano()]
+		if !ok {
+			i = len(out)
+			index[start.UnixNano()] = i
+			out = append(out, AggregateBucket{Start: start})
+		}
+		out[i].Value += p.Value
+		out[i].Count += p.Count
+	}
+	slices.SortFunc(out, func(a, b AggregateBucket) int { return a.Start.Compare(b.Start) })
+	if avg {
+		for i := range out {
+			out[i].Value /= float64(out[i].Count)
+		}
+	}
+	return out
+}
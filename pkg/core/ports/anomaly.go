@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// AnomalySeries 交给异常检测算法的一台设备的标准读数序列
+type AnomalySeries struct {
+	DeviceID string
+	Metric   domain.Metric
+	Interval time.Duration  // 读数的时间粒度
+	Location *time.Location // 划分日期与时段所用时区 (非 nil)
+
+	// Readings 按时间升序，已排除 MISSING 与已撤回的读数
+	// Start 之前的读数为历史基线，[Start, End) 内的读数为待检测区间
+	Readings   []domain.StandardReading
+	Start, End time.Time
+}
+
+// AnomalyDetector 异常检测算法端口
+// 实现只需返回 [Start, End) 内的异常；事件发布、读数查询由服务层 (services.AnomalyScanner) 负责。实现必须是并发安全的。
+type AnomalyDetector interface {
+	Detect(ctx context.Context, series AnomalySeries) ([]domain.Anomaly, error)
+}
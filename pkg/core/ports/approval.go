@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// ErrApprovalStatusChanged 审批记录的状态已被并发的审批改变 (Update 的比较并设置失败)
+var ErrApprovalStatusChanged = errors.New("approval status changed")
+
+// ApprovalRepository 待审批写入的存储 (可选)
+// 职责: 暂存影响范围较大的人工校准批次，直到第二人批准或驳回。
+// 记录按 ctx 的租户隔离 (见 domain.TenantFromContext)。
+type ApprovalRepository interface {
+	// Create 保存新的待审批批次并返回分配的 ID
+	Create(ctx context.Context, req domain.ApprovalRequest) (string, error)
+
+	// Get 按 ID 获取，不存在时返回 ErrNotFound
+	Get(ctx context.Context, id string) (*domain.ApprovalRequest, error)
+
+	// Update 比较并设置: 仅当记录的当前状态为 expected 时以 req 替换，否则返回 ErrApprovalStatusChanged；
+	// 不存在时返回 ErrNotFound。实现必须保证比较与写入是原子的，同一记录的并发审批只有一个成功
+	Update(ctx context.Context, req domain.ApprovalRequest, expected domain.ApprovalStatus) error
+
+	// ListPending 按提交时间升序返回至多 limit 条待审批批次 (limit <= 0 表示全部)
+	ListPending(ctx context.Context, limit int) ([]domain.ApprovalRequest, error)
+}
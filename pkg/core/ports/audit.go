@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// StandardReadingAudit 一次对已持久化标准读数的覆盖
+// Old / New 为覆盖前后的完整读数 (含值、优先级、质量与来源)，用于解释 HIGH_PRIORITY_WINS 等覆盖为何发生
+type StandardReadingAudit struct {
+	Old       domain.StandardReading `json:"old"`
+	New       domain.StandardReading `json:"new"`
+	Strategy  UpsertStrategy         `json:"strategy"`            // 本次写入的冲突策略
+	Operator  string                 `json:"operator,omitempty"`  // 操作人 (取自 IngestContext.Operator，可能为空)
+	BatchKey  string                 `json:"batch_key,omitempty"` // 写入批次的幂等键
+	ChangedAt time.Time              `json:"changed_at"`          // 覆盖发生的时间
+}
+
+// AuditRepository 标准读数变更审计日志 (只追加)
+type AuditRepository interface {
+	// Record 追加审计记录
+	Record(ctx context.Context, entries []StandardReadingAudit) error
+
+	// FindByDevice 获取设备在 [start, end] 内 (按读数时间) 的审计记录，按覆盖时间升序
+	FindByDevice(ctx context.Context, deviceID string, start, end time.Time) ([]StandardReadingAudit, error)
+}
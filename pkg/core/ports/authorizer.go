@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrForbidden 操作未获授权 (Authorizer 拒绝时应包装该错误)
+var ErrForbidden = errors.New("forbidden")
+
+// Operation 需要授权的管理操作
+type Operation string
+
+const (
+	OperationRuleWrite          Operation = "rule.write"          // 新增或修改清洗规则 (Resource 为规则 ID)
+	OperationRuleDelete         Operation = "rule.delete"         // 删除清洗规则 (Resource 为规则 ID)
+	OperationQuarantineResolve  Operation = "quarantine.resolve"  // 修正隔离记录并重新入库 (Resource 为记录 ID；重新评估时为设备类型)
+	OperationQuarantineIgnore   Operation = "quarantine.ignore"   // 忽略隔离记录 (Resource 为记录 ID)
+	OperationCalibrationIngest  Operation = "calibration.ingest"  // 以 CALIBRATION 策略写入读数 (Resource 为 BatchID，可能为空)
+	OperationCalibrationApprove Operation = "calibration.approve" // 批准或驳回待审批的校准批次 (Resource 为审批 ID)
+	OperationDeviceReveal       Operation = "device.reveal"       // 由假名还原设备ID (Resource 为假名)
+)
+
+// AuthRequest 一次授权检查: 谁 (Operator) 对什么 (Resource) 执行什么操作 (Operation)
+type AuthRequest struct {
+	Operation Operation
+	Operator  string // 操作人 (显式参数或 IngestContext.Operator，可能为空)
+	Resource  string
+}
+
+// Authorizer 管理操作的授权端口 (可选)
+// 职责: 让嵌入方在一处实现 RBAC 等策略，而不必包装每个服务方法。
+// ctx 为调用方的 Context，实现可从中读取自身的认证信息；允许时返回 nil，拒绝时返回包装 ErrForbidden 的错误。
+type Authorizer interface {
+	Authorize(ctx context.Context, req AuthRequest) error
+}
+
+// AuthorizerFunc 把函数适配为 Authorizer
+type AuthorizerFunc func(ctx context.Context, req AuthRequest) error
+
+// Authorize 实现 Authorizer
+func (f AuthorizerFunc) Authorize(ctx context.Context, req AuthRequest) error {
+	return f(ctx, req)
+}
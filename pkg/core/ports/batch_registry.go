@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrBatchAlreadyProcessed 批次 (IngestContext.BatchID) 已成功处理过，重复提交被拒绝
+var ErrBatchAlreadyProcessed = errors.New("batch already processed")
+
+// ErrBatchInProgress 批次正由另一次提交处理，尚未完成；调用方稍后重试即可得到最终结果
+var ErrBatchInProgress = errors.New("batch in progress")
+
+// BatchRegistry 摄入批次的登记表
+// 职责: 上游重试 (同一文件重复上传) 时识别已处理或正在处理的批次，避免重复摄入。
+// 批次按 ctx 的租户隔离 (见 domain.TenantFromContext)；实现必须是并发安全的。
+type BatchRegistry interface {
+	// Begin 原子地认领批次: 已完成时返回 done=true；正由另一次提交处理时返回 ErrBatchInProgress；
+	// 否则登记为处理中并返回 done=false，调用方处理结束后必须调用 MarkCompleted 或 Release
+	Begin(ctx context.Context, batchID string) (done bool, err error)
+
+	// MarkCompleted 登记批次已处理完成 (重复登记不报错，保留首次完成时间)
+	MarkCompleted(ctx context.Context, batchID string, at time.Time) error
+
+	// Release 放弃处理中的认领 (处理失败时调用)，之后同一批次可以重新提交；已完成的批次不受影响
+	Release(ctx context.Context, batchID string) error
+}
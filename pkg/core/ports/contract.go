@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// ContractRegistry 数据契约登记表 (可选)
+// 职责: 每个来源系统登记其输入结构与字段映射，摄入器按来源取出契约校验输入。
+type ContractRegistry interface {
+	// Register 登记或替换来源系统的契约 (Source 必填)
+	Register(ctx context.Context, contract domain.DataContract) error
+
+	// Get 获取来源系统当前的契约，未登记时返回 ErrNotFound
+	Get(ctx context.Context, source string) (*domain.DataContract, error)
+}
@@ -31,7 +31,8 @@ type EnergyDataStandardizer interface {
 	GetStandardReading(ctx context.Context, deviceID string, timestamp time.Time) (*domain.StandardReading, error)
 
 	// ProcessAndStandardize 直接处理输入数据并返回标准集 (用于即时转换场景)
-	ProcessAndStandardize(ctx context.Context, rawReadings []domain.Reading) ([]domain.StandardReading, error)
+	// 单台设备失败只记录在结果的 DeviceErrors 中；error 仅表示整个批次失败 (如规则加载、持久化失败或 ctx 取消)
+	ProcessAndStandardize(ctx context.Context, rawReadings []domain.Reading) (*domain.StandardizationResult, error)
 }
 // Aligner 定义时间对齐能力的接口
 // 核心职责：从散乱的时间序列中提取特定时间点的快照
@@ -39,4 +40,11 @@ type Aligner interface {
 	// FindSnapshot 在已排序的readings中查找最接近target时间点的读数
 	// 注意: readings 必须按 Timestamp 升序排列
 	FindSnapshot(readings []domain.Reading, target time.Time) *domain.Reading
-}
\ No newline at end of file
+}
+
+// DirectionalAligner 支持按调用指定方向偏好的对齐器
+type DirectionalAligner interface {
+	Aligner
+	// FindSnapshotMode 按 mode 查找 target 时间点的快照 (如计费场景只取不晚于 target 的读数)
+	FindSnapshotMode(readings []domain.Reading, target time.Time, mode domain.SnapshotMode) *domain.Reading
+}
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// DefaultDeadLetterTopic 死信消息的默认主题
+const DefaultDeadLetterTopic = "prism.dead_letters"
+
+// DeadLetter 重试后仍无法交付下游的一批原始读数及其错误信息
+type DeadLetter struct {
+	ID       int64            `json:"id,omitempty"` // 由死信仓储分配，其他实现为 0
+	Source   string           `json:"source"`       // 来源 (摄入格式，如 csv / json)
+	Readings []domain.Reading `json:"readings"`
+	Error    string           `json:"error"`    // 最后一次交付的错误
+	Attempts int              `json:"attempts"` // 交付尝试次数 (含首次)
+	FailedAt time.Time        `json:"failed_at"`
+}
+
+// DeadLetterSink 死信输出端口 (文件、消息主题、仓储等)
+// 职责: 保证下游持续故障时源数据不被静默丢弃，可在故障恢复后重放。
+// 实现必须是并发安全的；返回 nil 表示死信已持久化。
+type DeadLetterSink interface {
+	Write(ctx context.Context, letter DeadLetter) error
+}
+
+// DeadLetterRepository 可查询的死信存储 (用于排查与重放)
+type DeadLetterRepository interface {
+	DeadLetterSink
+
+	// List 按 ID 升序返回至多 limit 条死信 (limit <= 0 表示全部)
+	List(ctx context.Context, limit int) ([]DeadLetter, error)
+
+	// Delete 删除已重放或已处理的死信
+	Delete(ctx context.Context, ids []int64) error
+}
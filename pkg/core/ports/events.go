@@ -0,0 +1,135 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// EventType 变更事件类型
+type EventType string
+
+const (
+	EventStandardReadingSaved EventType = "STANDARD_READING_SAVED" // 一批标准读数已持久化
+	EventQuarantineCreated    EventType = "QUARANTINE_CREATED"     // 一条读数进入隔离区
+	EventRuleUpdated          EventType = "RULE_UPDATED"           // 清洗规则被创建或更新
+	EventAnomalyDetected      EventType = "ANOMALY_DETECTED"       // 标准读数序列上检测到语义异常
+
+	// 管道事件: 摄入与标准化过程中的环节进展 (不以持久化成功为前提)
+	EventBatchIngested      EventType = "BATCH_INGESTED"      // 摄入器处理完一次输入
+	EventReadingQuarantined EventType = "READING_QUARANTINED" // 清洗判定一条读数进入隔离区 (保存之前)
+	EventBatchStandardized  EventType = "BATCH_STANDARDIZED"  // 一批原始读数标准化完成
+	EventPersistFailed      EventType = "PERSIST_FAILED"      // 标准读数写入仓储失败
+)
+
+// Event 变更事件与管道事件 (变更事件在持久化成功后发出，管道事件在对应环节完成时发出)
+type Event interface {
+	EventType() EventType
+}
+
+// StandardReadingSaved 一批标准读数已持久化
+// Readings 为提交给仓储的读数；按 HIGH_PRIORITY_WINS 写入时，被库中更高优先级数据拦下的读数同样包含在内
+type StandardReadingSaved struct {
+	Readings   []domain.StandardReading
+	Strategy   UpsertStrategy
+	BatchKey   string // 持久化幂等键 (见 domain.BatchFingerprint)
+	OccurredAt time.Time
+}
+
+// EventType 实现 Event
+func (StandardReadingSaved) EventType() EventType { return EventStandardReadingSaved }
+
+// QuarantineCreated 一条读数进入隔离区
+type QuarantineCreated struct {
+	Record     domain.QuarantineReading
+	OccurredAt time.Time
+}
+
+// EventType 实现 Event
+func (QuarantineCreated) EventType() EventType { return EventQuarantineCreated }
+
+// RuleUpdated 清洗规则被创建或更新
+type RuleUpdated struct {
+	Rule       domain.CleaningRule
+	Created    bool   // true 表示新建，false 表示更新已有规则
+	Source     string // 变更来源 (如 "range-learner")
+	OccurredAt time.Time
+}
+
+// EventType 实现 Event
+func (RuleUpdated) EventType() EventType { return EventRuleUpdated }
+
+// AnomalyDetected 标准读数序列上检测到语义异常
+type AnomalyDetected struct {
+	Anomaly    domain.Anomaly
+	OccurredAt time.Time
+}
+
+// EventType 实现 Event
+func (AnomalyDetected) EventType() EventType { return EventAnomalyDetected }
+
+// BatchIngested 摄入器处理完一次输入 (IngestStream / IngestBatch 返回结果时)
+type BatchIngested struct {
+	Format     string // 输入格式 (如 "csv"、"json")
+	Result     domain.IngestionResult
+	OccurredAt time.Time
+}
+
+// EventType 实现 Event
+func (BatchIngested) EventType() EventType { return EventBatchIngested }
+
+// ReadingQuarantined 清洗判定一条读数进入隔离区
+// 在隔离记录保存之前发出 (异步保存时不等待落库)，保存成功后另有 QuarantineCreated
+type ReadingQuarantined struct {
+	Record     domain.QuarantineReading
+	OccurredAt time.Time
+}
+
+// EventType 实现 Event
+func (ReadingQuarantined) EventType() EventType { return EventReadingQuarantined }
+
+// BatchStandardized 一批原始读数标准化完成 (含持久化)
+type BatchStandardized struct {
+	Input        int                      // 进入管道的原始读数数
+	DeviceInputs map[string]int           // 各设备 (DeviceID) 进入管道的原始读数数
+	Quarantined  int                      // 进入隔离区的读数数
+	Readings     []domain.StandardReading // 输出的标准读数
+	DeviceErrors map[string]error         // 对齐失败的设备 (无失败时为 nil)
+	Elapsed      time.Duration
+	OccurredAt   time.Time
+}
+
+// EventType 实现 Event
+func (BatchStandardized) EventType() EventType { return EventBatchStandardized }
+
+// PersistFailed 标准读数写入仓储失败 (批次处理随之返回错误)
+type PersistFailed struct {
+	Readings   []domain.StandardReading
+	Strategy   UpsertStrategy
+	BatchKey   string
+	Err        error
+	OccurredAt time.Time
+}
+
+// EventType 实现 Event
+func (PersistFailed) EventType() EventType { return EventPersistFailed }
+
+// EventPublisher 变更事件发布端口
+// 外部系统据此响应数据变化，无需轮询仓储。发布在持久化成功之后进行，失败只记录日志，不回滚已持久化的数据；
+// 需要 "数据与消息同时提交" 的场景请使用事务性发件箱 (见 Outbox)。实现必须是并发安全的。
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// EventHandler 事件处理函数
+type EventHandler func(ctx context.Context, event Event) error
+
+// EventBus 进程内事件总线端口
+// 在 EventPublisher 之上提供订阅: 告警、审计等扩展只需订阅关心的事件类型，无需修改管道代码。
+// 实现必须是并发安全的；服务层把总线当作普通的 EventPublisher 注入 (如 WithEventPublisher)。
+type EventBus interface {
+	EventPublisher
+	// Subscribe 订阅事件 (types 为空表示订阅全部类型)，返回取消订阅函数 (可重复调用)
+	Subscribe(handler EventHandler, types ...EventType) (unsubscribe func())
+}
@@ -5,6 +5,7 @@ import "github.com/renjie/prism-core/pkg/core/domain"
 // CleaningContext 清洗规则执行时的上下文信息
 type CleaningContext struct {
 	Previous *domain.Reading // 前一条读数 (可能为nil，表示第一条数据)
+	Next     *domain.Reading // 同设备的下一条原始读数 (前瞻窗口，可能为nil，且未经清洗)
 	// 可扩展其他上下文字段，如批次信息、设备元数据等
 }
 
@@ -14,8 +15,16 @@ type CheckResult struct {
 	Passed    bool           // 是否通过检查
 	Corrected bool           // 是否进行了修正
 	Reason    string         // 失败或修正的原因描述
+	RuleID    string         // 产生该结果的规则标识 (由 Sanitizer 自动填充)
+
+	// Confidence 规则对结果读数的置信度 (0-1]
+	// 为 0 表示规则未给出，由 Sanitizer 取默认值: 原样通过为 1，修正为 DefaultCorrectedConfidence
+	Confidence float64
 }
 
+// DefaultCorrectedConfidence 规则修正读数但未给出置信度时使用的默认值
+const DefaultCorrectedConfidence = 0.8
+
 // CleaningRule 清洗规则接口
 // 这是一个策略接口，具体的业务规则（如单调性、跳变检测）由外部实现注入
 type CleaningRule interface {
@@ -24,6 +33,29 @@ type CleaningRule interface {
 	Check(ctx CleaningContext, curr domain.Reading) CheckResult
 }
 
+// IdentifiableRule 可选接口：规则实现该接口即可提供稳定的规则标识
+// 未实现时 Sanitizer 使用规则的类型名作为标识
+type IdentifiableRule interface {
+	ID() string
+}
+
+// RuleStats 单条规则在某设备类型下的触发统计
+// Checked = Passed + Corrected + Rejected
+type RuleStats struct {
+	RuleID     string
+	DeviceType domain.DeviceType
+	Checked    int64 // 执行检查次数
+	Passed     int64 // 原样通过次数
+	Corrected  int64 // 修正后通过次数
+	Rejected   int64 // 拒绝次数
+}
+
+// RuleStatsProvider 规则触发统计查询接口
+type RuleStatsProvider interface {
+	// RuleStats 返回当前累计的规则统计快照
+	RuleStats() []RuleStats
+}
+
 // Sanitizer 数据清洗器接口
 // 负责协调多个清洗规则的执行
 type Sanitizer interface {
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// ErrInsufficientHistory 历史标准读数不足以做出预测
+var ErrInsufficientHistory = errors.New("insufficient history for forecast")
+
+// Forecaster 用量预测端口
+// 预测从设备最近一条可用读数之后开始，覆盖 horizon 时长；历史不足时返回 ErrInsufficientHistory。
+// 默认实现为 services.SeasonalNaiveForecaster，外部模型 (如回归、机器学习服务) 实现该接口即可接入预算报表。
+type Forecaster interface {
+	Forecast(ctx context.Context, deviceID string, horizon time.Duration) (domain.Forecast, error)
+}
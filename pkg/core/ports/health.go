@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+// HealthChecker 仓储健康检查端口
+// 所有持久化适配器均实现该接口，供就绪探针 (readiness probe) 真正访问一次存储:
+// SQL 适配器除连通性外还会读取自身的表，以发现表缺失、权限不足等仅 Ping 无法暴露的问题。
+type HealthChecker interface {
+	// HealthCheck 返回 nil 表示存储可用；应尊重 ctx 的超时与取消
+	HealthCheck(ctx context.Context) error
+}
@@ -0,0 +1,34 @@
+package ports
+
+import "time"
+
+// PipelineCounter 标准化管道的读数计数项
+type PipelineCounter string
+
+const (
+	CounterReadingsIn  PipelineCounter = "readings_in" // 进入管道的原始读数
+	CounterCleaned     PipelineCounter = "cleaned"     // 通过清洗的读数
+	CounterQuarantined PipelineCounter = "quarantined" // 进入隔离区的读数
+	CounterAligned     PipelineCounter = "aligned"     // 对齐后产生的标准读数
+	CounterPersisted   PipelineCounter = "persisted"   // 成功持久化的标准读数
+)
+
+// PipelineStage 标准化管道的耗时阶段
+type PipelineStage string
+
+const (
+	StageClean   PipelineStage = "clean"   // 时钟修正 + 清洗 (含清洗前后钩子)
+	StageAlign   PipelineStage = "align"   // 按设备对齐与单条转换
+	StagePersist PipelineStage = "persist" // 写入 StandardReadingRepository
+	StageTotal   PipelineStage = "total"   // 整个批次
+)
+
+// StandardizerMetrics 标准化管道指标输出端口
+// 职责: 接收各阶段的读数计数与耗时 (如对接 Prometheus 的 Counter/Histogram)，定位慢批次的耗时环节。
+// 同一服务的多个批次可能并发调用，实现必须是并发安全的。
+type StandardizerMetrics interface {
+	// AddReadings 累加某计数项
+	AddReadings(counter PipelineCounter, n int)
+	// ObserveStage 记录某阶段的一次耗时
+	ObserveStage(stage PipelineStage, elapsed time.Duration)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Notification 一条告警通知
+type Notification struct {
+	Kind       string            `json:"kind"` // 通知类别 (如 "QUARANTINE_SURGE")
+	Title      string            `json:"title"`
+	Message    string            `json:"message"`
+	Labels     map[string]string `json:"labels,omitempty"` // 结构化字段 (如 device_id、rule_id)，便于接收方路由与去重
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// Notifier 告警通知端口 (Webhook、邮件、IM 机器人等)
+// 职责: 把需要人工关注的情况 (如传感器失效导致的隔离激增) 及时推送给运维，而不是等到月底对账才发现。
+// 实现必须是并发安全的；发送失败返回错误，由调用方决定是否重试。
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
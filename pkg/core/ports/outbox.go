@@ -0,0 +1,65 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// DefaultOutboxTopic 标准读数发件箱消息的默认主题
+const DefaultOutboxTopic = "prism.standard_readings"
+
+// OutboxMessage 事务性发件箱中待发布的消息
+type OutboxMessage struct {
+	ID        int64     // 发件箱内单调递增的序号 (发布顺序)
+	Topic     string    // 目标主题
+	Key       string    // 分区键: 标准读数消息为设备ID，保证同一设备的消息在下游有序
+	Payload   []byte    // 消息体 (JSON)
+	CreatedAt time.Time // 写入发件箱的时间
+}
+
+// Outbox 事务性发件箱 (Transactional Outbox)
+// 仓储在写入标准读数的同一事务内写入消息，由 OutboxRelay 异步发布到消息代理:
+// 数据与消息要么都提交要么都回滚，进程崩溃后未发布的消息在重启后继续发布 (至少一次投递)。
+type Outbox interface {
+	// Pending 按 ID 升序返回至多 limit 条未发布的消息
+	Pending(ctx context.Context, limit int) ([]OutboxMessage, error)
+
+	// MarkPublished 标记消息已发布 (适配器可直接删除)
+	MarkPublished(ctx context.Context, ids []int64) error
+}
+
+// MessagePublisher 消息代理发布端口 (如 Kafka、NATS、MQTT 的生产者)
+// Publish 返回 nil 表示代理已确认接收；下游需按 (Topic, Key, 读数唯一键) 去重以应对重复投递
+type MessagePublisher interface {
+	Publish(ctx context.Context, msg OutboxMessage) error
+}
+
+// StandardOutboxMessages 将一次写入中实际生效的标准读数按设备编码为发件箱消息 (适配器在写入事务内调用)
+// 每个设备一条消息，Payload 为该设备读数的 JSON 数组，消息顺序与设备首次出现的顺序一致
+func StandardOutboxMessages(topic string, applied []domain.StandardReading) ([]OutboxMessage, error) {
+	if topic == "" {
+		topic = DefaultOutboxTopic
+	}
+	var order []string
+	byDevice := make(map[string][]domain.StandardReading)
+	for _, sr := range applied {
+		if _, ok := byDevice[sr.DeviceID]; !ok {
+			order = append(order, sr.DeviceID)
+		}
+		byDevice[sr.DeviceID] = append(byDevice[sr.DeviceID], sr)
+	}
+
+	msgs := make([]OutboxMessage, 0, len(order))
+	for _, id := range order {
+		payload, err := json.Marshal(byDevice[id])
+		if err != nil {
+			return nil, fmt.Errorf("encode outbox message for %s: %w", id, err)
+		}
+		msgs = append(msgs, OutboxMessage{Topic: topic, Key: id, Payload: payload})
+	}
+	return msgs, nil
+}
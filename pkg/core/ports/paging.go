@@ -0,0 +1,98 @@
+package ports
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// ErrInvalidCursor 分页游标无法解析 (被篡改或来自不兼容的版本)
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// DefaultPageSize 未指定 Limit 时的每页条数
+const DefaultPageSize = 1000
+
+// PageRequest 分页查询参数
+type PageRequest struct {
+	Limit  int    // 每页最多条数 (<= 0 使用 DefaultPageSize)
+	Cursor string // 上一页返回的 NextCursor，为空表示从头开始
+}
+
+// StandardPage 一页标准读数
+type StandardPage struct {
+	Readings   []domain.StandardReading
+	NextCursor string // 下一页的游标，为空表示已到末尾
+}
+
+// StandardReadingPager 分页查询标准读数 (可选端口，由仓储适配器实现)
+// 场景: 一年的 15 分钟数据约 35k 条，一次性 FindRange 会全部加载到内存；分页查询可以流式处理
+type StandardReadingPager interface {
+	// FindRangePaged 按 (Timestamp, Metric, Resolution) 升序分页返回设备在 [start, end] 内的标准读数
+	// 游标记录上一页最后一条的排序键 (keyset 分页)，翻页期间写入的新数据不会导致重复或遗漏已返回的行
+	FindRangePaged(ctx context.Context, deviceID string, start, end time.Time, page PageRequest) (StandardPage, error)
+}
+
+// RangeCursor 分页游标对应的排序键
+type RangeCursor struct {
+	Timestamp  time.Time
+	Metric     domain.Metric
+	Resolution string
+}
+
+// CursorAfter 返回指向 sr 之后的游标
+func CursorAfter(sr domain.StandardReading) string {
+	raw := strconv.FormatInt(sr.Timestamp.UnixNano(), 10) + "|" + string(sr.Metric) + "|" + sr.Resolution
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor 解析 CursorAfter 生成的游标
+func DecodeCursor(cursor string) (RangeCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return RangeCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 {
+		return RangeCursor{}, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return RangeCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return RangeCursor{Timestamp: time.Unix(0, nanos).UTC(), Metric: domain.Metric(parts[1]), Resolution: parts[2]}, nil
+}
+
+// Before 判断游标是否位于 sr 之前 (即 sr 属于下一页)
+func (rc RangeCursor) Before(sr domain.StandardReading) bool {
+	if c := rc.Timestamp.Compare(sr.Timestamp); c != 0 {
+		return c < 0
+	}
+	if rc.Metric != sr.Metric {
+		return rc.Metric < sr.Metric
+	}
+	return rc.Resolution < sr.Resolution
+}
+
+// PageSize 返回生效的每页条数
+func (p PageRequest) PageSize() int {
+	if p.Limit <= 0 {
+		return DefaultPageSize
+	}
+	return p.Limit
+}
+
+// PageOf 由按排序键升序、最多 limit+1 条的查询结果构造一页 (适配器实现 keyset 分页时使用)
+// 多出的一条只用于判断是否还有下一页，不包含在结果中
+func PageOf(readings []domain.StandardReading, limit int) StandardPage {
+	if len(readings) <= limit {
+		return StandardPage{Readings: readings}
+	}
+	readings = readings[:limit]
+	return StandardPage{Readings: readings, NextCursor: CursorAfter(readings[limit-1])}
+}
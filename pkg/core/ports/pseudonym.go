@@ -0,0 +1,25 @@
+package ports
+
+import "context"
+
+// Pseudonymizer 设备ID假名化端口 (可选)
+// 职责: 在摄入入口把设备ID (在部分法规下属于个人数据，如 GDPR 下的电表号) 替换为不可逆推的假名，
+// 管道之后的清洗、持久化、事件与死信只接触假名。同一设备在同一租户下的假名必须稳定。
+type Pseudonymizer interface {
+	Pseudonymize(ctx context.Context, deviceID string) (string, error)
+}
+
+// PseudonymResolver 由假名还原设备ID的端口，实现必须先经过授权 (见 OperationDeviceReveal)
+// 未知假名返回 ErrNotFound，未获授权返回包装 ErrForbidden 的错误。
+type PseudonymResolver interface {
+	Reveal(ctx context.Context, pseudonym, operator string) (string, error)
+}
+
+// PseudonymRepository 假名与设备ID的映射存储 (按 ctx 的租户隔离)
+type PseudonymRepository interface {
+	// Save 保存映射 (重复保存相同映射不报错)
+	Save(ctx context.Context, pseudonym, deviceID string) error
+
+	// Lookup 按假名查找设备ID，不存在时返回 ErrNotFound
+	Lookup(ctx context.Context, pseudonym string) (string, error)
+}
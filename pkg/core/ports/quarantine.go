@@ -0,0 +1,112 @@
+package ports
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// QuarantineFilter 隔离记录查询条件，零值字段表示不过滤
+type QuarantineFilter struct {
+	ID             string
+	DeviceID       string
+	DeviceType     domain.DeviceType
+	RuleID         string
+	Code           domain.QuarantineCode
+	Status         domain.QuarantineStatus
+	ReasonContains string    // 隔离原因包含的子串 (不区分大小写)
+	Start, End     time.Time // 原始读数时间区间 [Start, End]，零值表示该端不限
+	Limit          int       // 最多返回条数 (<= 0 表示不限制，Count 忽略)
+	Offset         int       // 跳过的条数 (Count 忽略)
+}
+
+// Match 判断记录是否满足过滤条件 (不含 Limit/Offset)
+func (f QuarantineFilter) Match(q domain.QuarantineReading) bool {
+	ts := q.Reading.Timestamp
+	switch {
+	case f.ID != "" && q.ID != f.ID,
+		f.DeviceID != "" && q.Reading.DeviceInfo.ID != f.DeviceID,
+		f.DeviceType != "" && q.Reading.DeviceInfo.Type != f.DeviceType,
+		f.RuleID != "" && q.RuleID != f.RuleID,
+		f.Code != "" && q.Code != f.Code,
+		f.Status != "" && q.Status != f.Status,
+		f.ReasonContains != "" && !strings.Contains(strings.ToLower(q.Reason), strings.ToLower(f.ReasonContains)),
+		!f.Start.IsZero() && ts.Before(f.Start),
+		!f.End.IsZero() && ts.After(f.End):
+		return false
+	}
+	return true
+}
+
+// QuarantineGroupBy 隔离记录计数的分组维度
+type QuarantineGroupBy string
+
+const (
+	QuarantineGroupNone       QuarantineGroupBy = ""            // 不分组，只返回总数
+	QuarantineGroupStatus     QuarantineGroupBy = "status"      // 按状态
+	QuarantineGroupDevice     QuarantineGroupBy = "device_id"   // 按设备
+	QuarantineGroupDeviceType QuarantineGroupBy = "device_type" // 按设备类型
+	QuarantineGroupRule       QuarantineGroupBy = "rule_id"     // 按触发规则
+	QuarantineGroupCode       QuarantineGroupBy = "code"        // 按原因代码
+)
+
+// Validate 校验分组维度是否受支持
+func (g QuarantineGroupBy) Validate() error {
+	switch g {
+	case QuarantineGroupNone, QuarantineGroupStatus, QuarantineGroupDevice,
+		QuarantineGroupDeviceType, QuarantineGroupRule, QuarantineGroupCode:
+		return nil
+	}
+	return fmt.Errorf("quarantine count: unsupported group by %q", g)
+}
+
+// Key 返回记录在该维度下的分组键
+func (g QuarantineGroupBy) Key(q domain.QuarantineReading) string {
+	switch g {
+	case QuarantineGroupStatus:
+		return string(q.Status)
+	case QuarantineGroupDevice:
+		return q.Reading.DeviceInfo.ID
+	case QuarantineGroupDeviceType:
+		return string(q.Reading.DeviceInfo.Type)
+	case QuarantineGroupRule:
+		return q.RuleID
+	case QuarantineGroupCode:
+		return string(q.Code)
+	}
+	return ""
+}
+
+// QuarantineCount 一个分组的记录数 (不分组时 Key 为空)
+type QuarantineCount struct {
+	Key   string
+	Count int
+}
+
+// SortQuarantineCounts 按记录数降序、分组键升序排序 (分诊界面优先展示最多的问题)
+func SortQuarantineCounts(counts []QuarantineCount) {
+	slices.SortFunc(counts, func(a, b QuarantineCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Key, b.Key))
+	})
+}
+
+// CountQuarantine 在内存中按 groupBy 统计记录数 (供内存适配器与测试替身使用)
+func CountQuarantine(records []domain.QuarantineReading, groupBy QuarantineGroupBy) []QuarantineCount {
+	if groupBy == QuarantineGroupNone {
+		return []QuarantineCount{{Count: len(records)}}
+	}
+	counts := make(map[string]int)
+	for _, q := range records {
+		counts[groupBy.Key(q)]++
+	}
+	out := make([]QuarantineCount, 0, len(counts))
+	for key, n := range counts {
+		out = append(out, QuarantineCount{Key: key, Count: n})
+	}
+	SortQuarantineCounts(out)
+	return out
+}
@@ -0,0 +1,90 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// ReportQuery 能耗报表的生成条件
+type ReportQuery struct {
+	Device     domain.DeviceInfo   // 设备 (ID 必填；型号、类型写入报表)
+	Metric     domain.Metric       // 为空表示设备的默认通道
+	Resolution string              // 参与统计的分辨率 (必填，避免同一时间点多个分辨率被重复计入)
+	Period     domain.ReportPeriod // 统计周期: HOUR / DAY / WEEK / MONTH
+	Start, End time.Time           // 时间区间 [Start, End)，按统计周期向外取整
+
+	// Location 周期切分所用时区，需为 time.LoadLocation 加载的 IANA 时区
+	// 为空时使用设备的 Timezone，两者均为空表示 UTC；日、月周期按该时区的当地日历切分 (如 Asia/Shanghai 的一天始于 UTC 前一日 16:00)
+	Location *time.Location
+
+	// Cumulative 标准读数为累计值 (表底，如 SNAPSHOT 取值的 ENERGY 通道)
+	// 为 true 时周期用量取周期内 (含结束时刻) 最后与最早读数之差；为 false 时读数为区间量，用量为周期内读数之和
+	Cumulative bool
+}
+
+// Validate 校验报表条件
+func (q ReportQuery) Validate() error {
+	if q.Device.ID == "" {
+		return errors.New("report query: device id is required")
+	}
+	if q.Resolution == "" {
+		return errors.New("report query: resolution is required")
+	}
+	if !q.Period.Valid() {
+		return fmt.Errorf("report query: unsupported period %q", q.Period)
+	}
+	if !q.End.After(q.Start) {
+		return fmt.Errorf("report query: end %s must be after start %s", q.End.Format(time.RFC3339), q.Start.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ReportGenerator 能耗报表生成接口
+// 对应需求 2: 多维聚合 (小时、日、周、月)
+type ReportGenerator interface {
+	// Generate 按统计周期汇总标准读数，返回各周期的报表 (按开始时间升序，没有有效读数的周期不返回)
+	Generate(ctx context.Context, q ReportQuery) ([]domain.EnergyReport, error)
+}
+
+// EnergyReportFilter 已生成报表的查询条件
+type EnergyReportFilter struct {
+	DeviceID   string              // 必填
+	Metric     domain.Metric       // 为空表示设备的默认通道
+	Period     domain.ReportPeriod // 必填
+	Start, End time.Time           // 周期开始时间区间 [Start, End)
+}
+
+// Validate 校验查询条件
+func (f EnergyReportFilter) Validate() error {
+	if f.DeviceID == "" {
+		return errors.New("energy report filter: device id is required")
+	}
+	if !f.Period.Valid() {
+		return fmt.Errorf("energy report filter: unsupported period %q", f.Period)
+	}
+	if !f.End.After(f.Start) {
+		return fmt.Errorf("energy report filter: end %s must be after start %s", f.End.Format(time.RFC3339), f.Start.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// EmissionFactorProvider 排放因子来源 (可选)
+// 静态或分时段的因子使用 services.NewEmissionFactorTable；实时电网排放强度可由调用方对接外部服务实现
+type EmissionFactorProvider interface {
+	// EmissionFactor 返回设备计量通道在 at 时刻适用的排放因子 (kg CO₂e / 用量单位)，没有适用的因子时 ok=false
+	EmissionFactor(ctx context.Context, device domain.DeviceInfo, metric domain.Metric, at time.Time) (factor float64, ok bool, err error)
+}
+
+// WeatherDataProvider 气象数据来源 (可选)
+// 由调用方对接气象站或气象服务，按设备 (如其所在园区) 选择站点；度日数的基准温度由实现决定
+type WeatherDataProvider interface {
+	// DegreeDays 返回设备所在地在 [start, end) 内的实际度日数
+	DegreeDays(ctx context.Context, device domain.DeviceInfo, start, end time.Time) (domain.DegreeDays, error)
+
+	// NormalDegreeDays 返回设备所在地同一时段的常年度日数 (如 30 年平均)
+	NormalDegreeDays(ctx context.Context, device domain.DeviceInfo, start, end time.Time) (domain.DegreeDays, error)
+}
@@ -2,11 +2,16 @@ package ports
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/renjie/prism-core/pkg/core/domain"
 )
 
+// ErrNotFound 仓储查询的目标记录不存在
+// 适配器应返回该错误 (或包装该错误)，调用方通过 errors.Is 判断
+var ErrNotFound = errors.New("not found")
+
 // UpsertStrategy 定义数据持久化时的冲突解决策略
 type UpsertStrategy string
 
@@ -21,15 +26,34 @@ const (
 	UpsertStrategyHighPriorityWins UpsertStrategy = "HIGH_PRIORITY_WINS"
 )
 
+// ConflictDecision 自定义冲突裁决的结果
+type ConflictDecision int
+
+const (
+	// ConflictUseStrategy 不做特殊处理，按调用方传入的 UpsertStrategy 裁决
+	ConflictUseStrategy ConflictDecision = iota
+	// ConflictKeepExisting 保留已有数据，丢弃新数据
+	ConflictKeepExisting
+	// ConflictReplace 用新数据覆盖已有数据
+	ConflictReplace
+)
+
+// ConflictResolver 自定义冲突裁决回调: existing 为已有数据，incoming 为新数据
+// 用于内置策略之外的站点级规则 (如 "优先保留校准数据，除非已超过 90 天")，无需在核心中新增策略枚举
+type ConflictResolver func(existing, incoming domain.StandardReading) ConflictDecision
+
 // StandardReadingRepository 标准读数仓储接口
 // 对应核心竞争力: 输出“数据标准”的持久化载体
 // 职责: 存储经过 Standardizer 清洗和对齐后的“黄金数据”，供下游查询整个园区/工厂的标准历史。
+// 注意: 同一设备同一时间点可存在多个分辨率的标准读数，适配器应以 (DeviceID, Timestamp, Resolution) 作为唯一键。
 type StandardReadingRepository interface {
 	// Save 保存单个标准读数 (需指定冲突策略)
 	Save(ctx context.Context, reading domain.StandardReading, strategy UpsertStrategy) error
 
 	// SaveBatch 批量保存 (需指定冲突策略)
-	SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy UpsertStrategy) error
+	// idempotencyKey 为批次指纹 (见 domain.BatchFingerprint): 适配器应记录已应用的 key，
+	// 同一 key 再次提交时直接返回 nil，避免瞬时错误后的重试重复覆盖 (尤其是 LAST_WRITE_WINS)
+	SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy UpsertStrategy, idempotencyKey string) error
 
 	// FindExact 获取特定时间点的标准读数 (对应 GetStandardReading)
 	// 场景: "获取 D1 设备在 10:00:00 的确切标准读数"
@@ -38,6 +62,74 @@ type StandardReadingRepository interface {
 	// FindRange 获取时间范围内的标准读数
 	// 场景: 报表生成、趋势分析
 	FindRange(ctx context.Context, deviceID string, start, end time.Time) ([]domain.StandardReading, error)
+
+	// FindLatest 获取设备最新的一条标准读数 (不存在时返回 ErrNotFound)
+	// 同一时间点存在多个通道或分辨率时，按 (Metric, Resolution) 排序取第一条
+	// 场景: 看板展示 "当前表计读数"
+	FindLatest(ctx context.Context, deviceID string) (*domain.StandardReading, error)
+
+	// FindLastBefore 获取指定计量通道、指定分辨率下时间早于 before 的最近一条标准读数 (不存在时返回 ErrNotFound)
+	// metric 为空表示设备的默认通道
+	// 场景: 清洗新批次时以历史数据作为第一条读数的 Previous
+	FindLastBefore(ctx context.Context, deviceID string, metric domain.Metric, resolution string, before time.Time) (*domain.StandardReading, error)
+}
+
+// StandardReadingRetention 标准读数保留期清理 (可选端口，由支持删除的仓储适配器实现)
+// 场景: 高分辨率数据只保留较短时间，粗粒度数据 (见 WithResolutions) 保留更久，
+// 无需直接操作数据库即可清理历史数据
+type StandardReadingRetention interface {
+	// DeleteOlderThan 删除时间早于 cutoff 的标准读数，返回删除的条数
+	// deviceID 为空表示全部设备；resolution 为空表示全部分辨率
+	DeleteOlderThan(ctx context.Context, deviceID string, resolution string, cutoff time.Time) (int64, error)
+}
+
+// StandardReadingMultiFinder 多设备批量区间查询 (可选端口，由仓储适配器实现)
+// 场景: 为 500 块表的园区生成报表时，用一次 (或少量分批) 查询代替 500 次顺序的 FindRange
+type StandardReadingMultiFinder interface {
+	// FindRangeMulti 获取多个设备在 [start, end] 内的标准读数，按设备分组
+	// 每个设备的读数排序与 FindRange 相同；没有数据的设备不出现在结果中，重复的设备 ID 只查询一次
+	FindRangeMulti(ctx context.Context, deviceIDs []string, start, end time.Time) (map[string][]domain.StandardReading, error)
+}
+
+// WithdrawRequest 撤回标准读数的条件与审计信息
+type WithdrawRequest struct {
+	DeviceID   string
+	Metric     domain.Metric // 为空表示设备的默认通道
+	Resolution string        // 为空表示全部分辨率
+	Start, End time.Time     // 时间区间 [Start, End] (与 FindRange 一致)
+	Reason     string        // 撤回原因 (必填)
+	Operator   string        // 操作人 (必填)
+	At         time.Time     // 撤回时间 (为空取当前时间)
+}
+
+// Validate 校验撤回请求
+func (r WithdrawRequest) Validate() error {
+	switch {
+	case r.DeviceID == "":
+		return errors.New("withdraw: device id is required")
+	case r.Reason == "":
+		return errors.New("withdraw: reason is required")
+	case r.Operator == "":
+		return errors.New("withdraw: operator is required")
+	case r.End.Before(r.Start):
+		return errors.New("withdraw: end must not be before start")
+	}
+	return nil
+}
+
+// StandardReadingWithdrawer 撤回标准读数 (可选端口，由仓储适配器实现)
+// 场景: 审计要求不得删除已发布的数据；校准发现历史有误时将受影响的读数标记为撤回 (见 domain.Withdrawal)
+type StandardReadingWithdrawer interface {
+	// Withdraw 撤回满足条件的标准读数，返回本次新撤回的读数 (已撤回的不重复返回)，调用方据此向下游发送更正信号
+	Withdraw(ctx context.Context, req WithdrawRequest) ([]domain.StandardReading, error)
+}
+
+// StandardReadingVersioner 查询标准读数的历史版本 (可选端口，由启用了版本保留的仓储适配器实现)
+// 场景: 重新校准后对比同一时间点校准前后的数值；仓储在覆盖内容有变化的读数前保留被替换的版本
+type StandardReadingVersioner interface {
+	// FindVersions 返回设备在 timestamp 时间点各通道、各分辨率的全部版本 (含当前版本)
+	// 按 (Metric, Resolution, Version) 升序排列，同一键的最后一条即当前版本；无数据时返回空切片
+	FindVersions(ctx context.Context, deviceID string, timestamp time.Time) ([]domain.StandardReading, error)
 }
 
 // CleaningRuleRepository 清洗规则仓储接口
@@ -46,7 +138,7 @@ type CleaningRuleRepository interface {
 	// Save 保存或更新规则
 	Save(ctx context.Context, rule domain.CleaningRule) error
 
-	// GetByID 获取指定规则
+	// GetByID 获取指定规则 (不存在时返回 ErrNotFound)
 	GetByID(ctx context.Context, id string) (*domain.CleaningRule, error)
 
 	// ListByDeviceType 获取适用于特定设备类型的所有规则
@@ -57,6 +149,32 @@ type CleaningRuleRepository interface {
 
 	// Delete 删除规则
 	Delete(ctx context.Context, id string) error
+
+	// Watch 订阅规则变更，ctx 取消后通道被关闭
+	// 场景: Standardizer 收到变更后使对应设备类型的规则缓存失效，规则编辑无需重启即可在数秒内生效。
+	// 不支持变更通知的实现返回 ErrWatchNotSupported，调用方退化为依赖缓存过期。
+	Watch(ctx context.Context) (<-chan RuleChangeEvent, error)
+}
+
+// ErrWatchNotSupported 仓储不支持变更通知
+var ErrWatchNotSupported = errors.New("watch not supported")
+
+// RuleChangeType 规则变更类型
+type RuleChangeType string
+
+const (
+	RuleChangeSaved   RuleChangeType = "SAVED"   // 新增或更新
+	RuleChangeDeleted RuleChangeType = "DELETED" // 删除
+)
+
+// RuleChangeEvent 一次规则变更
+type RuleChangeEvent struct {
+	Type       RuleChangeType
+	RuleID     string
+	DeviceType domain.DeviceType // 变更后规则适用的设备类型 (删除时为原设备类型)
+	// PreviousDeviceType 规则改为适用其他设备类型时的原设备类型 (两者的缓存都需失效)；未改变时为空
+	PreviousDeviceType domain.DeviceType
+	At                 time.Time
 }
 
 // DeviceRepository 设备元数据仓储 (可选，视校验需求而定)
@@ -65,13 +183,92 @@ type DeviceRepository interface {
 	Exists(ctx context.Context, deviceID string) (bool, error)
 }
 
+// DeviceCatalog 设备元数据目录 (可选)
+// 职责: 维护设备的型号、类型、单位、时区、校准系数与标签，供管理界面编辑，无需各团队另建设备台账
+type DeviceCatalog interface {
+	DeviceRepository
+
+	// Save 新增或更新设备 (按 ID)
+	Save(ctx context.Context, device domain.DeviceInfo) error
+
+	// GetByID 获取设备 (不存在时返回 ErrNotFound)
+	GetByID(ctx context.Context, deviceID string) (*domain.DeviceInfo, error)
+
+	// List 列出设备，按 ID 升序 (deviceType 为空表示全部类型)
+	List(ctx context.Context, deviceType domain.DeviceType) ([]domain.DeviceInfo, error)
+
+	// Delete 删除设备 (不存在时为空操作)
+	Delete(ctx context.Context, deviceID string) error
+}
+
+// ErrQuarantineStatusChanged 隔离记录的状态已被并发的处理改变 (UpdateStatus 的比较并设置失败)
+var ErrQuarantineStatusChanged = errors.New("quarantine status changed")
+
 // QuarantineRepository 隔离区仓储接口
 // 职责: 存储被“拒收”或需“人工审核”的脏数据，供后续治理
 type QuarantineRepository interface {
 	// Save 保存一条隔离记录 (新增或更新状态)
 	Save(ctx context.Context, record domain.QuarantineReading) error
 
+	// UpdateStatus 比较并设置: 仅当记录的当前状态为 expected 时以 record 替换，否则返回 ErrQuarantineStatusChanged；
+	// 记录不存在 (或属于其他租户) 时返回 ErrNotFound。实现必须保证比较与写入是原子的，
+	// 同一记录的并发处理 (修正、忽略) 只有一个成功
+	UpdateStatus(ctx context.Context, record domain.QuarantineReading, expected domain.QuarantineStatus) error
+
 	// FindPending 获取待处理的隔离记录
 	// 场景: 数据管理员拉取 "NEW" 或 "REVIEW_NEEDED" 的数据进行处理
 	FindPending(ctx context.Context, limit int) ([]domain.QuarantineReading, error)
+
+	// FindPendingByDeviceType 获取某设备类型下待处理的隔离记录 (limit <= 0 表示不限制)
+	// 场景: 放宽规则阈值后重新评估隔离区
+	FindPendingByDeviceType(ctx context.Context, deviceType domain.DeviceType, limit int) ([]domain.QuarantineReading, error)
+
+	// FindByDevice 获取设备在 [start, end] 时间范围内的隔离记录 (任意状态)
+	// 场景: 规则修正后重新处理历史区间
+	FindByDevice(ctx context.Context, deviceID string, start, end time.Time) ([]domain.QuarantineReading, error)
+
+	// Find 按条件查询隔离记录 (按隔离时间、ID 排序，支持 Limit/Offset 分页)
+	// 场景: 分诊界面按设备、规则、原因、时间与状态筛选
+	Find(ctx context.Context, filter QuarantineFilter) ([]domain.QuarantineReading, error)
+
+	// Count 统计满足条件的隔离记录数，按 groupBy 分组 (见 SortQuarantineCounts 的排序)
+	// groupBy 为 QuarantineGroupNone 时返回一个 Key 为空的总数 (无记录时 Count 为 0)
+	Count(ctx context.Context, filter QuarantineFilter, groupBy QuarantineGroupBy) ([]QuarantineCount, error)
+}
+
+// RawReadingRepository 原始读数仓储接口 (可选)
+// 职责: 保留摄入的原始读数 (清洗之前)，供规则修正后重新清洗、重新标准化历史区间，并追溯标准读数的来源
+type RawReadingRepository interface {
+	// Save 保存一批原始读数；同一计量通道同一时间戳以最后写入的为准
+	Save(ctx context.Context, readings []domain.Reading) error
+
+	// FindRange 获取设备在 [start, end] 时间范围内的原始读数 (全部通道)，按时间排序
+	FindRange(ctx context.Context, deviceID string, start, end time.Time) ([]domain.Reading, error)
+}
+
+// DataGapSink 数据缺口事件输出端口
+// 职责: 接收标准化过程中发现的数据缺口 (如写入告警系统、触发补抄任务)
+type DataGapSink interface {
+	// ReportGaps 上报一批数据缺口
+	ReportGaps(ctx context.Context, gaps []domain.DataGap) error
+}
+
+// TopologyRepository 设备层级树仓储 (可选)
+// 职责: 保存 园区 → 楼栋 → 楼层 → 设备 的分组关系，供报表沿层级汇总用量
+type TopologyRepository interface {
+	// Save 新增或更新节点 (按 ID)
+	Save(ctx context.Context, nodes ...domain.TopologyNode) error
+
+	// FindSubtree 返回以 rootID 为根的子树 (根节点在前，其余按层级顺序)，根节点不存在时返回 ErrNotFound
+	FindSubtree(ctx context.Context, rootID string) ([]domain.TopologyNode, error)
+}
+
+// EnergyReportRepository 能耗报表仓储 (可选)
+// 职责: 保存已生成的报表，看板按设备、周期与时间区间直接读取，而不必每次请求都从标准读数重新汇总
+type EnergyReportRepository interface {
+	// Save 新增或覆盖报表 (按 ID；重新生成同一周期的报表即覆盖旧结果)
+	Save(ctx context.Context, reports ...domain.EnergyReport) error
+
+	// Find 返回满足条件的报表，按周期开始时间升序
+	Find(ctx context.Context, filter EnergyReportFilter) ([]domain.EnergyReport, error)
 }
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// AnomalyQuery 异常扫描条件
+type AnomalyQuery struct {
+	DeviceIDs  []string
+	Metric     domain.Metric  // 为空表示设备的默认通道
+	Resolution string         // 参与检测的分辨率 (如 "1h")，读数须为区间用量
+	Start, End time.Time      // 待检测区间 [Start, End)
+	Location   *time.Location // 划分日期与时段所用时区 (为空表示 UTC)
+}
+
+// AnomalyScanner 异常扫描任务
+// 读取待检测区间及其之前一段历史 (基线窗口) 的标准读数，交给可插拔的检测算法 (ports.AnomalyDetector)，
+// 并为每个异常发出 AnomalyDetected 事件。清洗规则只能拦截单点的脏数据，用量突增、夜间基础负荷上升等语义异常由此发现。
+type AnomalyScanner struct {
+	repo     ports.StandardReadingRepository
+	detector ports.AnomalyDetector
+	baseline time.Duration
+	events   ports.EventPublisher
+	logger   *slog.Logger
+}
+
+// AnomalyScannerOption 定义异常扫描任务配置选项 (Functional Option Pattern)
+type AnomalyScannerOption func(*AnomalyScanner)
+
+// WithAnomalyDetector 设置检测算法 (默认 NewSeasonalThresholdDetector())
+func WithAnomalyDetector(detector ports.AnomalyDetector) AnomalyScannerOption {
+	return func(s *AnomalyScanner) {
+		if detector != nil {
+			s.detector = detector
+		}
+	}
+}
+
+// WithBaselineWindow 设置待检测区间之前作为历史基线的时长 (默认 28 天，即每个星期几各 4 天)
+func WithBaselineWindow(window time.Duration) AnomalyScannerOption {
+	return func(s *AnomalyScanner) {
+		if window > 0 {
+			s.baseline = window
+		}
+	}
+}
+
+// WithAnomalyEventPublisher 设置变更事件输出: 每个异常发出一条 AnomalyDetected
+func WithAnomalyEventPublisher(publisher ports.EventPublisher) AnomalyScannerOption {
+	return func(s *AnomalyScanner) {
+		s.events = publisher
+	}
+}
+
+// WithAnomalyLogger 设置日志输出 (默认使用 slog.Default())，用于记录事件发布失败
+func WithAnomalyLogger(logger *slog.Logger) AnomalyScannerOption {
+	return func(s *AnomalyScanner) {
+		s.logger = logger
+	}
+}
+
+// NewAnomalyScanner 创建异常扫描任务
+func NewAnomalyScanner(repo ports.StandardReadingRepository, opts ...AnomalyScannerOption) *AnomalyScanner {
+	s := &AnomalyScanner{
+		repo:     repo,
+		detector: NewSeasonalThresholdDetector(),
+		baseline: 28 * 24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Scan 检测各设备在 [q.Start, q.End) 内的异常，按开始时间 (相同时按设备ID、类型) 升序返回
+// 缺失 (MISSING) 与已撤回 (WITHDRAWN) 的读数不参与检测。
+func (s *AnomalyScanner) Scan(ctx context.Context, q AnomalyQuery) ([]domain.Anomaly, error) {
+	if len(q.DeviceIDs) == 0 {
+		return nil, errors.New("anomaly query: device ids are required")
+	}
+	interval, ok := resolutionDuration(q.Resolution)
+	if !ok {
+		return nil, fmt.Errorf("anomaly query: invalid resolution %q", q.Resolution)
+	}
+	if !q.End.After(q.Start) {
+		return nil, fmt.Errorf("anomaly query: end %s must be after start %s", q.End.Format(time.RFC3339), q.Start.Format(time.RFC3339))
+	}
+	if s.repo == nil {
+		return nil, errors.New("anomaly repository is nil")
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	ids := slices.Compact(slices.Sorted(slices.Values(q.DeviceIDs)))
+	byDevice, err := FindRangeMulti(ctx, s.repo, ids, q.Start.Add(-s.baseline), q.End.Add(-time.Nanosecond)) // FindRange 为闭区间
+	if err != nil {
+		return nil, fmt.Errorf("anomaly scan: %w", err)
+	}
+	var out []domain.Anomaly
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		series := ports.AnomalySeries{DeviceID: id, Metric: q.Metric, Interval: interval, Location: loc, Start: q.Start, End: q.End}
+		for _, sr := range byDevice[id] {
+			if sr.Metric != q.Metric || sr.Resolution != q.Resolution ||
+				sr.Quality == domain.QualityMissing || sr.Quality == domain.QualityWithdrawn {
+				continue
+			}
+			series.Readings = append(series.Readings, sr)
+		}
+		slices.SortStableFunc(series.Readings, func(a, b domain.StandardReading) int { return a.Timestamp.Compare(b.Timestamp) })
+		anomalies, err := s.detector.Detect(ctx, series)
+		if err != nil {
+			return nil, fmt.Errorf("anomaly scan of %s: %w", id, err)
+		}
+		out = append(out, anomalies...)
+	}
+	slices.SortStableFunc(out, func(a, b domain.Anomaly) int {
+		return cmp.Or(a.Start.Compare(b.Start), cmp.Compare(a.DeviceID, b.DeviceID), cmp.Compare(a.Kind, b.Kind))
+	})
+	for _, a := range out {
+		publishEvent(ctx, s.logger, s.events, ports.AnomalyDetected{Anomaly: a, OccurredAt: time.Now()})
+	}
+	return out, nil
+}
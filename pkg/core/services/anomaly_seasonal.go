@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// SeasonalThresholdDetector 基于季节性阈值的默认异常检测算法
+//   - 用量突增: 以历史上 "同一日类型 (工作日/周末) 的同一时段" 的读数为基线，
+//     用量超过 中位数 + k x 稳健标准差 (1.4826 x MAD，不低于中位数的 5%) 即为异常
+//   - 夜间基础负荷上升: 夜间时段 (默认当地 00:00-05:00) 的平均用量超过历史各夜平均用量中位数的 (1 + ratio) 倍即为异常
+//
+// 中位数与 MAD 不受历史中个别异常值的影响；基线样本不足或全为 0 时不做判定。
+type SeasonalThresholdDetector struct {
+	spikeK           float64
+	minSamples       int
+	nightFrom        int
+	nightTo          int
+	baseLoadIncrease float64
+}
+
+// 编译期检查接口实现
+var _ ports.AnomalyDetector = (*SeasonalThresholdDetector)(nil)
+
+// SeasonalThresholdOption 定义季节性阈值算法配置选项 (Functional Option Pattern)
+type SeasonalThresholdOption func(*SeasonalThresholdDetector)
+
+// WithSpikeThreshold 设置用量突增阈值的稳健标准差倍数 k (默认 3)
+func WithSpikeThreshold(k float64) SeasonalThresholdOption {
+	return func(d *SeasonalThresholdDetector) {
+		if k > 0 {
+			d.spikeK = k
+		}
+	}
+}
+
+// WithBaselineSamples 设置判定所需的最少基线样本数 (同一时段的历史读数条数 / 历史夜数，默认 3)
+func WithBaselineSamples(n int) SeasonalThresholdOption {
+	return func(d *SeasonalThresholdDetector) {
+		if n > 0 {
+			d.minSamples = n
+		}
+	}
+}
+
+// WithNightHours 设置夜间时段 [from, to) (当地整点，0 <= from < to <= 24，默认 0 与 5)
+func WithNightHours(from, to int) SeasonalThresholdOption {
+	return func(d *SeasonalThresholdDetector) {
+		if from >= 0 && from < to && to <= 24 {
+			d.nightFrom, d.nightTo = from, to
+		}
+	}
+}
+
+// WithBaseLoadIncrease 设置夜间基础负荷上升的判定比例 (默认 0.2，即高于历史水平 20%)
+func WithBaseLoadIncrease(ratio float64) SeasonalThresholdOption {
+	return func(d *SeasonalThresholdDetector) {
+		if ratio > 0 {
+			d.baseLoadIncrease = ratio
+		}
+	}
+}
+
+// NewSeasonalThresholdDetector 创建季节性阈值异常检测算法
+func NewSeasonalThresholdDetector(opts ...SeasonalThresholdOption) *SeasonalThresholdDetector {
+	d := &SeasonalThresholdDetector{spikeK: 3, minSamples: 3, nightFrom: 0, nightTo: 5, baseLoadIncrease: 0.2}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Detect 检测用量突增与夜间基础负荷上升
+func (d *SeasonalThresholdDetector) Detect(ctx context.Context, series ports.AnomalySeries) ([]domain.Anomaly, error) {
+	loc := series.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	type slotKey struct {
+		dayType DayType
+		clock   time.Duration
+	}
+	type night struct {
+		first, last time.Time
+		sum         float64
+		count       int
+	}
+	baseline := make(map[slotKey][]float64)
+	var pastNights []float64
+	var nights []*night // 待检测区间内的各夜，按日期升序
+	var current *night
+	currentDay, detecting := "", false
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if detecting {
+			nights = append(nights, current)
+		} else {
+			pastNights = append(pastNights, current.sum/float64(current.count))
+		}
+		current = nil
+	}
+
+	detectedAt := time.Now()
+	var out []domain.Anomaly
+	for _, sr := range series.Readings {
+		if sr.ScaleFactor <= 0 || !sr.Timestamp.Before(series.End) {
+			continue
+		}
+		v := float64(sr.ValueScaled) / float64(sr.ScaleFactor)
+		local := sr.Timestamp.In(loc)
+		key := slotKey{dayTypeOf(local), time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second}
+		inWindow := !sr.Timestamp.Before(series.Start)
+
+		if !inWindow {
+			baseline[key] = append(baseline[key], v)
+		} else if history := baseline[key]; len(history) >= d.minSamples {
+			med := median(history)
+			deviations := make([]float64, len(history))
+			for i, h := range history {
+				deviations[i] = math.Abs(h - med)
+			}
+			if scale := max(1.4826*median(deviations), 0.05*math.Abs(med)); scale > 0 {
+				if threshold := med + d.spikeK*scale; v > threshold {
+					out = append(out, domain.Anomaly{
+						DeviceID: series.DeviceID, Metric: series.Metric, Kind: domain.AnomalyConsumptionSpike,
+						Start: sr.Timestamp, End: sr.Timestamp.Add(series.Interval),
+						Observed: v, Expected: med, Threshold: threshold,
+						Reason:     fmt.Sprintf("usage %g above seasonal threshold %g (median %g of %d %s readings at %s)", v, threshold, med, len(history), key.dayType, local.Format("15:04")),
+						DetectedAt: detectedAt,
+					})
+				}
+			}
+		}
+
+		if h := local.Hour(); h < d.nightFrom || h >= d.nightTo {
+			continue
+		}
+		if day := local.Format(time.DateOnly); day != currentDay || inWindow != detecting {
+			flush()
+			currentDay, detecting = day, inWindow
+			current = &night{first: sr.Timestamp}
+		}
+		current.last = sr.Timestamp
+		current.sum += v
+		current.count++
+	}
+	flush()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(pastNights) >= d.minSamples {
+		med := median(pastNights)
+		threshold := med * (1 + d.baseLoadIncrease)
+		for _, n := range nights {
+			if avg := n.sum / float64(n.count); med > 0 && avg > threshold {
+				out = append(out, domain.Anomaly{
+					DeviceID: series.DeviceID, Metric: series.Metric, Kind: domain.AnomalyBaseLoadIncrease,
+					Start: n.first, End: n.last.Add(series.Interval),
+					Observed: avg, Expected: med, Threshold: threshold,
+					Reason:     fmt.Sprintf("night-time average usage %g above %g (median of %d previous nights %g)", avg, threshold, len(pastNights), med),
+					DetectedAt: detectedAt,
+				})
+			}
+		}
+	}
+	return out, nil
+}
+
+// median 返回中位数 (不修改入参)
+func median(values []float64) float64 {
+	sorted := slices.Sorted(slices.Values(values))
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
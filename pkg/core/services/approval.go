@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+var (
+	// ErrApprovalNotConfigured 未配置人工校准审批 (WithCalibrationApproval)
+	ErrApprovalNotConfigured = errors.New("calibration approval not configured")
+
+	// ErrApprovalNotPending 审批记录已被批准或驳回
+	ErrApprovalNotPending = errors.New("approval request is not pending")
+
+	// ErrSelfApproval 审批人与提交人相同，或提交人未知无法确认不同 (四眼原则)
+	ErrSelfApproval = errors.New("approver must differ from submitter")
+
+	// ErrSubmitterRequired 需要审批的校准批次未携带提交人 (IngestContext.Operator)
+	ErrSubmitterRequired = errors.New("calibration batch requiring approval must carry the submitting operator")
+
+	// ErrApprovalRequired 校准读数需要审批，但入口无法暂存 (StreamingStandardizer.Push)
+	ErrApprovalRequired = errors.New("calibration readings require approval; submit them through ProcessAndStandardize")
+)
+
+// approvalGate 人工校准审批配置
+type approvalGate struct {
+	repo      ports.ApprovalRepository
+	threshold int
+}
+
+// WithCalibrationApproval 为人工校准 (IngestStrategyCalibration) 写入增加审批:
+// 批次读数数超过 threshold 时不写入，暂存到 repo 并在结果的 PendingApproval 中返回审批 ID，
+// 由另一位操作人调用 Approve 后才写入标准读数。threshold 为 0 表示所有校准批次都需审批。
+// 需要审批的批次必须在 IngestContext.Operator 中携带提交人，否则返回 ErrSubmitterRequired。
+// 审批拦截 ProcessAndStandardize 与隔离记录的人工修正 (Resolve，按 1 条读数计)；
+// StreamingStandardizer.Push 无法暂存，需要审批的校准读数返回 ErrApprovalRequired。ReprocessRange 等管理操作不受影响。
+func WithCalibrationApproval(repo ports.ApprovalRepository, threshold int) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.approvals = &approvalGate{repo: repo, threshold: max(threshold, 0)}
+	}
+}
+
+// needsApproval 判断 ctx 下 n 条读数的写入是否需要审批
+func (s *CoreStandardizer) needsApproval(ctx context.Context, n int) bool {
+	ic, _ := domain.FromContext(ctx)
+	return s.approvals != nil && ic.Strategy == domain.IngestStrategyCalibration && n > s.approvals.threshold
+}
+
+// parkForApproval 需要审批的校准批次暂存后返回结果；无需审批时返回 (nil, nil)
+func (s *CoreStandardizer) parkForApproval(ctx context.Context, rawReadings []domain.Reading) (*domain.StandardizationResult, error) {
+	if !s.needsApproval(ctx, len(rawReadings)) {
+		return nil, nil
+	}
+	return s.submitForApproval(ctx, rawReadings, "")
+}
+
+// submitForApproval 以 ctx 的 IngestContext 暂存读数，返回带审批 ID 的结果
+// quarantineID 非空表示隔离记录的人工修正 (见 ApprovalRequest.QuarantineID)
+func (s *CoreStandardizer) submitForApproval(ctx context.Context, readings []domain.Reading, quarantineID string) (*domain.StandardizationResult, error) {
+	ic, _ := domain.FromContext(ctx)
+	if ic.Operator == "" {
+		return nil, ErrSubmitterRequired
+	}
+	ctx, err := domain.ResolveTenant(ctx, readings)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.approvals.repo.Create(ctx, domain.ApprovalRequest{
+		TenantID:     domain.TenantFromContext(ctx),
+		Readings:     slices.Clone(readings),
+		TraceID:      ic.TraceID,
+		BatchID:      ic.BatchID,
+		SubmittedBy:  ic.Operator,
+		SubmittedAt:  time.Now(),
+		Status:       domain.ApprovalStatusPending,
+		QuarantineID: quarantineID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("park calibration batch for approval: %w", err)
+	}
+	loggerOr(s.logger).Info("calibration batch awaiting approval", "approval_id", id, "readings", len(readings), "operator", ic.Operator)
+	return &domain.StandardizationResult{PendingApproval: id}, nil
+}
+
+// PendingApprovals 返回待审批的校准批次 (按提交时间升序，limit <= 0 表示全部)
+func (s *CoreStandardizer) PendingApprovals(ctx context.Context, limit int) ([]domain.ApprovalRequest, error) {
+	if s.approvals == nil {
+		return nil, ErrApprovalNotConfigured
+	}
+	return s.approvals.repo.ListPending(ctx, limit)
+}
+
+// Approve 批准暂存的校准批次: 先把记录由 PENDING 标记为 APPROVED (比较并设置，并发的审批只有一个成功)，
+// 再以提交时的 IngestContext 处理并写入标准读数 (隔离记录的人工修正与 Resolve 一样不经清洗规则)。
+// operator 必须与已知的提交人不同；处理失败时记录恢复为 PENDING，可再次批准
+func (s *CoreStandardizer) Approve(ctx context.Context, id, operator string) (*domain.StandardizationResult, error) {
+	req, err := s.pendingApproval(ctx, id, operator)
+	if err != nil {
+		return nil, err
+	}
+	if req.SubmittedBy == "" {
+		return nil, fmt.Errorf("approval request %s has no recorded submitter: %w", id, ErrSelfApproval)
+	}
+	pending := *req
+	if err := s.review(ctx, req, domain.ApprovalStatusApproved, operator, ""); err != nil {
+		return nil, err
+	}
+	pctx := domain.NewContext(domain.WithTenant(ctx, req.TenantID), req.IngestContext())
+	var result *domain.StandardizationResult
+	if req.QuarantineID != "" {
+		result, err = s.applyCorrections(pctx, req.Readings)
+	} else {
+		result, err = s.process(pctx, req.Readings, processOptions{strategy: ports.UpsertStrategyHighPriorityWins, archive: true})
+	}
+	if err != nil {
+		if restoreErr := s.approvals.repo.Update(ctx, pending, domain.ApprovalStatusApproved); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf("restore approval request %s to pending: %w", id, restoreErr))
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// Reject 驳回暂存的校准批次 (读数不写入)，note 记录驳回原因
+// 隔离记录的人工修正被驳回后，该隔离记录恢复为 PENDING 以便重新处理
+func (s *CoreStandardizer) Reject(ctx context.Context, id, operator, note string) error {
+	req, err := s.pendingApproval(ctx, id, operator)
+	if err != nil {
+		return err
+	}
+	if err := s.review(ctx, req, domain.ApprovalStatusRejected, operator, note); err != nil {
+		return err
+	}
+	if req.QuarantineID == "" {
+		return nil
+	}
+	return s.reopenQuarantine(domain.WithTenant(ctx, req.TenantID), req.QuarantineID, fmt.Sprintf("correction rejected in approval %s by %s", id, operator))
+}
+
+// pendingApproval 加载待审批记录并校验审批人
+func (s *CoreStandardizer) pendingApproval(ctx context.Context, id, operator string) (*domain.ApprovalRequest, error) {
+	if s.approvals == nil {
+		return nil, ErrApprovalNotConfigured
+	}
+	if operator == "" {
+		return nil, errors.New("approval: operator is required")
+	}
+	if err := s.authorize(ctx, ports.OperationCalibrationApprove, operator, id); err != nil {
+		return nil, err
+	}
+	req, err := s.approvals.repo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("load approval request %s: %w", id, err)
+	}
+	if req.Status != domain.ApprovalStatusPending {
+		return nil, fmt.Errorf("approval request %s is %s: %w", id, req.Status, ErrApprovalNotPending)
+	}
+	if req.SubmittedBy == operator {
+		return nil, ErrSelfApproval
+	}
+	return req, nil
+}
+
+// review 把待审批记录标记为审批结论；记录已被并发的审批处理时返回 ErrApprovalNotPending
+func (s *CoreStandardizer) review(ctx context.Context, req *domain.ApprovalRequest, status domain.ApprovalStatus, operator, note string) error {
+	req.Status, req.ReviewedBy, req.ReviewedAt, req.Note = status, operator, time.Now(), note
+	err := s.approvals.repo.Update(ctx, *req, domain.ApprovalStatusPending)
+	if errors.Is(err, ports.ErrApprovalStatusChanged) {
+		return fmt.Errorf("update approval request %s: %w: %w", req.ID, ErrApprovalNotPending, err)
+	}
+	if err != nil {
+		return fmt.Errorf("update approval request %s: %w", req.ID, err)
+	}
+	return nil
+}
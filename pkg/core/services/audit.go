@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// auditingRepository 在标准读数仓储之上记录覆盖审计 (见 WithAuditRepository)
+// 写入前读取同键的已有数据，按策略推算哪些键会被覆盖；写入成功后把实际发生变化的覆盖追加到审计日志。
+// 与 resolvingRepository 相同，"读取-写入" 不是原子操作，同一设备的写入需串行；审计写入失败只记录日志，不影响已提交的数据。
+type auditingRepository struct {
+	ports.StandardReadingRepository
+	audit  ports.AuditRepository
+	logger *slog.Logger
+}
+
+// Save 保存单个标准读数并记录覆盖
+func (r *auditingRepository) Save(ctx context.Context, reading domain.StandardReading, strategy ports.UpsertStrategy) error {
+	return r.write(ctx, []domain.StandardReading{reading}, strategy, "", func() error {
+		return r.StandardReadingRepository.Save(ctx, reading, strategy)
+	})
+}
+
+// SaveBatch 批量保存并记录覆盖
+func (r *auditingRepository) SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, idempotencyKey string) error {
+	return r.write(ctx, readings, strategy, idempotencyKey, func() error {
+		return r.StandardReadingRepository.SaveBatch(ctx, readings, strategy, idempotencyKey)
+	})
+}
+
+func (r *auditingRepository) write(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, key string, save func() error) error {
+	if err := validateUpsertStrategy(strategy); err != nil {
+		return err
+	}
+	current, err := existingStandards(ctx, r.StandardReadingRepository, readings)
+	if err != nil {
+		return err
+	}
+	if err := save(); err != nil {
+		return err
+	}
+
+	entries := overwrites(current, readings, strategy)
+	if len(entries) == 0 {
+		return nil
+	}
+	var operator string
+	if info, ok := domain.FromContext(ctx); ok {
+		operator = info.Operator
+	}
+	now := time.Now()
+	for i := range entries {
+		entries[i].Strategy, entries[i].Operator, entries[i].BatchKey, entries[i].ChangedAt = strategy, operator, key, now
+	}
+	if err := r.audit.Record(ctx, entries); err != nil {
+		loggerOr(r.logger).Error("failed to record standard reading audit", "count", len(entries), "error", err)
+	}
+	return nil
+}
+
+// overwrites 推算按 strategy 写入 readings 后，已持久化的读数中哪些被替换为不同的值
+// 批次内同键的多条读数依次裁决，只与最终胜出者比较；值、精度、质量与优先级都未变化的重写 (如重放) 不计入。
+func overwrites(persisted map[resolvedKey]domain.StandardReading, readings []domain.StandardReading, strategy ports.UpsertStrategy) []ports.StandardReadingAudit {
+	var order []resolvedKey
+	winners := make(map[resolvedKey]domain.StandardReading)
+	for _, sr := range readings {
+		k := resolvedKeyOf(sr)
+		cur, ok := winners[k]
+		if !ok {
+			cur, ok = persisted[k]
+			if !ok {
+				continue // 新增，不是覆盖
+			}
+			order = append(order, k)
+		}
+		if strategy == ports.UpsertStrategyLastWriteWins || sr.Priority >= cur.Priority {
+			winners[k] = sr
+		} else {
+			winners[k] = cur
+		}
+	}
+
+	var out []ports.StandardReadingAudit
+	for _, k := range order {
+		old, winner := persisted[k], winners[k]
+		if old.ValueScaled == winner.ValueScaled && old.ScaleFactor == winner.ScaleFactor &&
+			old.Quality == winner.Quality && old.Priority == winner.Priority {
+			continue
+		}
+		out = append(out, ports.StandardReadingAudit{Old: old, New: winner})
+	}
+	return out
+}
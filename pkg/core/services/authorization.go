@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// WithAuthorizer 设置管理操作的授权检查: 隔离记录修正 / 忽略 / 重新评估、CALIBRATION 批次写入与校准审批
+// 在执行前调用 authz，被拒绝时返回其错误且不做任何修改。规则管理见 NewAuthorizedRuleRepository。
+func WithAuthorizer(authz ports.Authorizer) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.authz = authz
+	}
+}
+
+// authorize 未配置 Authorizer 时放行
+func (s *CoreStandardizer) authorize(ctx context.Context, op ports.Operation, operator, resource string) error {
+	return authorize(ctx, s.authz, op, operator, resource)
+}
+
+// authorizeIngest 人工校准 (IngestStrategyCalibration) 写入前检查 OperationCalibrationIngest 授权，其余策略直接通过
+// 批处理与流式入口共用，校准数据无论从哪个入口写入都受同一授权约束
+func (s *CoreStandardizer) authorizeIngest(ctx context.Context) error {
+	ic, _ := domain.FromContext(ctx)
+	if ic.Strategy != domain.IngestStrategyCalibration {
+		return nil
+	}
+	return s.authorize(ctx, ports.OperationCalibrationIngest, ic.Operator, ic.BatchID)
+}
+
+func authorize(ctx context.Context, authz ports.Authorizer, op ports.Operation, operator, resource string) error {
+	if authz == nil {
+		return nil
+	}
+	if err := authz.Authorize(ctx, ports.AuthRequest{Operation: op, Operator: operator, Resource: resource}); err != nil {
+		return fmt.Errorf("%s %s by %q: %w", op, resource, operator, err)
+	}
+	return nil
+}
+
+// operatorOf 返回 ctx 中 IngestContext 的操作人 (未设置时为空)
+func operatorOf(ctx context.Context) string {
+	info, _ := domain.FromContext(ctx)
+	return info.Operator
+}
+
+// authorizedRuleRepository 写操作前检查授权的规则仓储，读操作与 Watch 直接透传
+type authorizedRuleRepository struct {
+	ports.CleaningRuleRepository
+	authz ports.Authorizer
+}
+
+// NewAuthorizedRuleRepository 包装规则仓储: Save / Delete 前以 ctx 中 IngestContext 的操作人调用 authz
+// (OperationRuleWrite / OperationRuleDelete)。管理接口、配置加载与阈值学习共用同一仓储时，规则的所有写入口都受控。
+func NewAuthorizedRuleRepository(repo ports.CleaningRuleRepository, authz ports.Authorizer) ports.CleaningRuleRepository {
+	return &authorizedRuleRepository{CleaningRuleRepository: repo, authz: authz}
+}
+
+// Save 实现 ports.CleaningRuleRepository
+func (r *authorizedRuleRepository) Save(ctx context.Context, rule domain.CleaningRule) error {
+	if err := authorize(ctx, r.authz, ports.OperationRuleWrite, operatorOf(ctx), rule.ID); err != nil {
+		return err
+	}
+	return r.CleaningRuleRepository.Save(ctx, rule)
+}
+
+// Delete 实现 ports.CleaningRuleRepository
+func (r *authorizedRuleRepository) Delete(ctx context.Context, id string) error {
+	if err := authorize(ctx, r.authz, ports.OperationRuleDelete, operatorOf(ctx), id); err != nil {
+		return err
+	}
+	return r.CleaningRuleRepository.Delete(ctx, id)
+}
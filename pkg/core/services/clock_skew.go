@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// ClockSkewTag 设备标签: 控制单台设备的时钟偏移修正
+//   - "off":   不修正
+//   - "auto":  自动检测 (默认)
+//   - 时长值:  已知的固定偏移，如 "8h" 表示设备时钟超前 8 小时 (读数时间将减去 8h)
+const ClockSkewTag = "clock_skew"
+
+// ClockSkewConfig 时钟偏移检测参数
+type ClockSkewConfig struct {
+	MinOffset   time.Duration // 小于该值的偏移视为正常传输延迟 (默认 5m)
+	MaxJitter   time.Duration // 单条偏移与中位数的最大差值，超出说明偏移不恒定 (默认 2m)
+	Granularity time.Duration // 检测出的偏移按此粒度取整 (默认 1m)
+	MinSamples  int           // 检测所需的最少样本数 (默认 3)
+}
+
+// DefaultClockSkewConfig 返回默认检测参数
+func DefaultClockSkewConfig() ClockSkewConfig {
+	return ClockSkewConfig{
+		MinOffset:   5 * time.Minute,
+		MaxJitter:   2 * time.Minute,
+		Granularity: time.Minute,
+		MinSamples:  3,
+	}
+}
+
+// clockSkewCorrector 时钟偏移修正阶段
+// 对比设备上报时间 (Timestamp) 与接收时间 (ReceivedAt)，发现恒定偏移 (如时区配置错误导致的 8h 超前) 后整体平移读数时间，
+// 被平移的读数标记为 CORRECTED。
+type clockSkewCorrector struct {
+	cfg ClockSkewConfig
+}
+
+func newClockSkewCorrector(cfg ClockSkewConfig) *clockSkewCorrector {
+	def := DefaultClockSkewConfig()
+	if cfg.MinOffset <= 0 {
+		cfg.MinOffset = def.MinOffset
+	}
+	if cfg.MaxJitter <= 0 {
+		cfg.MaxJitter = def.MaxJitter
+	}
+	if cfg.Granularity <= 0 {
+		cfg.Granularity = def.Granularity
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = def.MinSamples
+	}
+	return &clockSkewCorrector{cfg: cfg}
+}
+
+// Correct 按设备检测并修正时钟偏移，原地修改 readings
+func (c *clockSkewCorrector) Correct(ctx context.Context, readings []domain.Reading) {
+	// 自动检测只适用于实时数据: 补传数据的接收时间与读数时间本就相差很大
+	detect := true
+	if info, ok := domain.FromContext(ctx); ok && info.Strategy != "" && info.Strategy != domain.IngestStrategyRealtime {
+		detect = false
+	}
+
+	byDevice := make(map[string][]int)
+	for i, r := range readings {
+		byDevice[r.DeviceInfo.ID] = append(byDevice[r.DeviceInfo.ID], i)
+	}
+
+	for _, idxs := range byDevice {
+		offset, ok := c.deviceOffset(readings, idxs, detect)
+		if !ok || offset == 0 {
+			continue
+		}
+		reason := fmt.Sprintf("clock skew %s corrected", offset)
+		for _, i := range idxs {
+			readings[i].Timestamp = readings[i].Timestamp.Add(-offset)
+			readings[i].MarkCorrected(reason)
+		}
+	}
+}
+
+// deviceOffset 确定单台设备需要修正的偏移量 (正值表示设备时钟超前)
+func (c *clockSkewCorrector) deviceOffset(readings []domain.Reading, idxs []int, detect bool) (time.Duration, bool) {
+	switch tag := readings[idxs[0]].DeviceInfo.Tags[ClockSkewTag]; tag {
+	case "off":
+		return 0, false
+	case "", "auto":
+	default:
+		fixed, err := time.ParseDuration(tag)
+		if err != nil {
+			return 0, false
+		}
+		return fixed, true
+	}
+	if !detect {
+		return 0, false
+	}
+
+	offsets := make([]time.Duration, 0, len(idxs))
+	for _, i := range idxs {
+		if readings[i].ReceivedAt.IsZero() {
+			continue
+		}
+		offsets = append(offsets, readings[i].Timestamp.Sub(readings[i].ReceivedAt))
+	}
+	if len(offsets) < c.cfg.MinSamples {
+		return 0, false
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	median := offsets[len(offsets)/2]
+	if absDuration(median) < c.cfg.MinOffset {
+		return 0, false
+	}
+	for _, o := range offsets {
+		if absDuration(o-median) > c.cfg.MaxJitter {
+			return 0, false
+		}
+	}
+	return median.Round(c.cfg.Granularity), true
+}
+
+// absDuration 返回 Duration 的绝对值
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// resolvingRepository 在标准读数仓储之上应用自定义冲突裁决 (见 WithConflictResolver)
+// 写入前读取同键的已有数据交给回调裁决，胜出的读数以 LAST_WRITE_WINS 写入底层仓储；查询直接委托给底层仓储。
+// 注意: "读取-裁决-写入" 不是原子操作，同一设备的写入需串行 (标准化器按批次顺序写入时满足)。
+type resolvingRepository struct {
+	ports.StandardReadingRepository
+	resolver ports.ConflictResolver
+}
+
+// Save 裁决后保存单个标准读数
+func (r *resolvingRepository) Save(ctx context.Context, reading domain.StandardReading, strategy ports.UpsertStrategy) error {
+	winners, err := r.resolve(ctx, []domain.StandardReading{reading}, strategy)
+	if err != nil || len(winners) == 0 {
+		return err
+	}
+	return r.StandardReadingRepository.Save(ctx, winners[0], ports.UpsertStrategyLastWriteWins)
+}
+
+// SaveBatch 裁决后批量保存 (批次内同键的读数依次与当前胜者裁决)
+func (r *resolvingRepository) SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, idempotencyKey string) error {
+	winners, err := r.resolve(ctx, readings, strategy)
+	if err != nil {
+		return err
+	}
+	return r.StandardReadingRepository.SaveBatch(ctx, winners, ports.UpsertStrategyLastWriteWins, idempotencyKey)
+}
+
+// resolvedKey 标准读数唯一键
+type resolvedKey struct {
+	deviceID   string
+	metric     domain.Metric
+	resolution string
+	unixNano   int64
+}
+
+func resolvedKeyOf(sr domain.StandardReading) resolvedKey {
+	return resolvedKey{sr.DeviceID, sr.Metric, sr.Resolution, sr.Timestamp.UnixNano()}
+}
+
+// resolve 返回需要写入的读数 (每个键至多一条，保持首次出现的顺序)
+func (r *resolvingRepository) resolve(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy) ([]domain.StandardReading, error) {
+	if err := validateUpsertStrategy(strategy); err != nil {
+		return nil, err
+	}
+	current, err := existingStandards(ctx, r.StandardReadingRepository, readings)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := make(map[resolvedKey]int) // 键 -> winners 下标
+	var winners []domain.StandardReading
+	for _, sr := range readings {
+		k := resolvedKeyOf(sr)
+		if cur, ok := current[k]; ok && !r.accept(cur, sr, strategy) {
+			continue
+		}
+		current[k] = sr
+		if i, ok := accepted[k]; ok {
+			winners[i] = sr
+			continue
+		}
+		accepted[k] = len(winners)
+		winners = append(winners, sr)
+	}
+	return winners, nil
+}
+
+// accept 判断 incoming 是否覆盖 existing
+func (r *resolvingRepository) accept(existing, incoming domain.StandardReading, strategy ports.UpsertStrategy) bool {
+	switch r.resolver(existing, incoming) {
+	case ports.ConflictKeepExisting:
+		return false
+	case ports.ConflictReplace:
+		return true
+	}
+	return strategy == ports.UpsertStrategyLastWriteWins || incoming.Priority >= existing.Priority
+}
+
+// existingStandards 按设备查询批次时间范围内的已有数据
+func existingStandards(ctx context.Context, repo ports.StandardReadingRepository, readings []domain.StandardReading) (map[resolvedKey]domain.StandardReading, error) {
+	type span struct{ start, end time.Time }
+	spans := make(map[string]span)
+	for _, sr := range readings {
+		s, ok := spans[sr.DeviceID]
+		if !ok {
+			spans[sr.DeviceID] = span{sr.Timestamp, sr.Timestamp}
+			continue
+		}
+		if sr.Timestamp.Before(s.start) {
+			s.start = sr.Timestamp
+		}
+		if sr.Timestamp.After(s.end) {
+			s.end = sr.Timestamp
+		}
+		spans[sr.DeviceID] = s
+	}
+
+	out := make(map[resolvedKey]domain.StandardReading)
+	for id, s := range spans {
+		found, err := repo.FindRange(ctx, id, s.start, s.end)
+		if err != nil {
+			return nil, err
+		}
+		for _, sr := range found {
+			out[resolvedKeyOf(sr)] = sr
+		}
+	}
+	return out, nil
+}
+
+// validateUpsertStrategy 拒绝未知策略 (与内置适配器的校验一致)
+func validateUpsertStrategy(strategy ports.UpsertStrategy) error {
+	switch strategy {
+	case ports.UpsertStrategyLastWriteWins, ports.UpsertStrategyHighPriorityWins:
+		return nil
+	}
+	return fmt.Errorf("unsupported upsert strategy %q", strategy)
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DemandQuery 需量分析条件
+type DemandQuery struct {
+	DeviceIDs  []string      // 设备；多台设备时按时间点求和，得到群组的同时最大需量 (而非各设备最大需量之和)
+	Metric     domain.Metric // 为空表示设备的默认通道
+	Resolution string        // 需量窗口即该分辨率的区间 (如 "15m")，读数须为区间用量
+	Start, End time.Time     // 时间区间 [Start, End)
+	TopN       int           // 返回的峰值窗口数 (默认 1)
+}
+
+// DemandPeak 一个需量窗口
+type DemandPeak struct {
+	Start        time.Time // 窗口开始时间
+	End          time.Time // 窗口结束时间
+	Usage        float64   // 窗口内用量 (如 kWh)
+	Demand       float64   // 需量 = 用量 x 窗口系数 (1h / 窗口长度)，如 15 分钟用量 (kWh) x 4 = 平均功率 (kW)
+	DemandScaled int64     // 需量的定点值
+	ScaleFactor  int       // 精度因子
+	Devices      int       // 该窗口有有效读数的设备数
+}
+
+// DemandResult 需量分析结果
+type DemandResult struct {
+	Interval time.Duration // 需量窗口长度
+	Peaks    []DemandPeak  // 需量最高的窗口，按需量降序 (相同需量按时间升序)；没有数据时为空
+}
+
+// Max 返回最大需量窗口 (没有数据时 ok=false)
+func (r DemandResult) Max() (DemandPeak, bool) {
+	if len(r.Peaks) == 0 {
+		return DemandPeak{}, false
+	}
+	return r.Peaks[0], true
+}
+
+// AnalyzeDemand 基于标准读数计算设备或设备群组在 [q.Start, q.End) 内的最大需量、出现时间与 Top-N 峰值窗口
+// 需量以定点整数计算: 各读数换算到最大的精度因子后求和，再精确乘以窗口系数并四舍五入。
+// 缺失 (MISSING) 与已撤回 (WITHDRAWN) 的读数不参与计算；多台设备时缺数据的设备不计入该窗口 (见 DemandPeak.Devices)。
+func AnalyzeDemand(ctx context.Context, repo ports.StandardReadingRepository, q DemandQuery) (DemandResult, error) {
+	if len(q.DeviceIDs) == 0 {
+		return DemandResult{}, errors.New("demand query: device ids are required")
+	}
+	interval, ok := resolutionDuration(q.Resolution)
+	if !ok {
+		return DemandResult{}, fmt.Errorf("demand query: invalid resolution %q", q.Resolution)
+	}
+	if !q.End.After(q.Start) {
+		return DemandResult{}, fmt.Errorf("demand query: end %s must be after start %s", q.End.Format(time.RFC3339), q.Start.Format(time.RFC3339))
+	}
+	topN := max(q.TopN, 1)
+
+	byDevice, err := FindRangeMulti(ctx, repo, q.DeviceIDs, q.Start, q.End.Add(-time.Nanosecond)) // FindRange 为闭区间
+	if err != nil {
+		return DemandResult{}, fmt.Errorf("demand analysis: %w", err)
+	}
+	var readings []domain.StandardReading
+	scale := 1
+	for _, id := range q.DeviceIDs {
+		for _, sr := range byDevice[id] {
+			if sr.Metric != q.Metric || sr.Resolution != q.Resolution ||
+				sr.Quality == domain.QualityMissing || sr.Quality == domain.QualityWithdrawn {
+				continue
+			}
+			if err := domain.ValidateScaleFactor(sr.ScaleFactor); err != nil {
+				return DemandResult{}, fmt.Errorf("demand analysis of %s: %w", sr.DeviceID, err)
+			}
+			scale = max(scale, sr.ScaleFactor)
+			readings = append(readings, sr)
+		}
+		delete(byDevice, id) // 重复的设备ID只计一次
+	}
+
+	// 同一窗口内各设备用量求和
+	type window struct {
+		start   time.Time
+		usage   int64
+		devices int
+	}
+	index := make(map[int64]int)
+	var windows []window
+	for _, sr := range readings {
+		v, err := rescale(sr, scale)
+		if err != nil {
+			return DemandResult{}, fmt.Errorf("demand analysis of %s: %w", sr.DeviceID, err)
+		}
+		key := sr.Timestamp.UnixNano()
+		i, ok := index[key]
+		if !ok {
+			i = len(windows)
+			index[key] = i
+			windows = append(windows, window{start: sr.Timestamp})
+		}
+		w := &windows[i]
+		if (v > 0 && w.usage > math.MaxInt64-v) || (v < 0 && w.usage < math.MinInt64-v) {
+			return DemandResult{}, fmt.Errorf("%w: demand at %s", domain.ErrScaleOverflow, sr.Timestamp.Format(time.RFC3339))
+		}
+		w.usage += v
+		w.devices++
+	}
+	slices.SortFunc(windows, func(a, b window) int {
+		return cmp.Or(cmp.Compare(b.usage, a.usage), a.start.Compare(b.start))
+	})
+
+	// 窗口系数 1h / interval 可能不是整数 (如 2h 窗口为 0.5)，以有理数精确相乘
+	factor := big.NewRat(int64(time.Hour), int64(interval))
+	result := DemandResult{Interval: interval}
+	for _, w := range windows[:min(topN, len(windows))] {
+		demand, err := domain.RoundRat(new(big.Rat).Mul(new(big.Rat).SetInt64(w.usage), factor), domain.RoundHalfUp)
+		if err != nil {
+			return DemandResult{}, fmt.Errorf("%w: demand at %s", err, w.start.Format(time.RFC3339))
+		}
+		result.Peaks = append(result.Peaks, DemandPeak{
+			Start:        w.start,
+			End:          w.start.Add(interval),
+			Usage:        float64(w.usage) / float64(scale),
+			Demand:       float64(demand) / float64(scale),
+			DemandScaled: demand,
+			ScaleFactor:  scale,
+			Devices:      w.devices,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// EmissionFactorTable 基于配置的排放因子表，实现 ports.EmissionFactorProvider
+// 查找时先按适用范围的精确程度 (设备类型 + 通道 > 设备类型 > 通道 > 全部)，再取 at 时刻已生效的最新因子。
+type EmissionFactorTable struct {
+	factors []domain.EmissionFactor // 按精确程度降序、生效时间降序排列
+}
+
+// 编译期检查接口实现
+var _ ports.EmissionFactorProvider = (*EmissionFactorTable)(nil)
+
+// NewEmissionFactorTable 创建排放因子表
+func NewEmissionFactorTable(factors ...domain.EmissionFactor) *EmissionFactorTable {
+	sorted := slices.Clone(factors)
+	slices.SortStableFunc(sorted, func(a, b domain.EmissionFactor) int {
+		return cmp.Or(cmp.Compare(specificity(b), specificity(a)), b.ValidFrom.Compare(a.ValidFrom))
+	})
+	return &EmissionFactorTable{factors: sorted}
+}
+
+// specificity 适用范围的精确程度: 指定设备类型优先于指定通道
+func specificity(f domain.EmissionFactor) int {
+	n := 0
+	if f.DeviceType != "" {
+		n += 2
+	}
+	if f.Metric != "" {
+		n++
+	}
+	return n
+}
+
+// EmissionFactor 返回设备计量通道在 at 时刻适用的排放因子
+func (t *EmissionFactorTable) EmissionFactor(ctx context.Context, device domain.DeviceInfo, metric domain.Metric, at time.Time) (float64, bool, error) {
+	for _, f := range t.factors {
+		if (f.DeviceType == "" || f.DeviceType == device.Type) && (f.Metric == "" || f.Metric == metric) && !f.ValidFrom.After(at) {
+			return f.KgCO2ePerUnit, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// carbon 按各区间开始时刻适用的排放因子计算周期的碳排放 (缩放因子与用量相同，精确相乘后四舍五入)
+// 未配置排放因子来源，或周期内任一区间没有适用的因子时返回 nil，不给出不完整的排放量
+func (g *CoreReportGenerator) carbon(ctx context.Context, q ports.ReportQuery, intervals []usageInterval, scale int) (*domain.CarbonEmission, error) {
+	if g.emissions == nil {
+		return nil, nil
+	}
+	total := new(big.Rat)
+	for _, u := range intervals {
+		factor, ok, err := g.emissions.EmissionFactor(ctx, q.Device, q.Metric, u.at)
+		if err != nil {
+			return nil, fmt.Errorf("emission factor: %w", err)
+		}
+		if !ok {
+			return nil, nil
+		}
+		f, ok := new(big.Rat).SetString(strconv.FormatFloat(factor, 'f', -1, 64))
+		if !ok {
+			return nil, fmt.Errorf("invalid emission factor %v", factor)
+		}
+		total.Add(total, f.Mul(f, new(big.Rat).SetInt64(u.scaled)))
+	}
+	scaled, err := domain.RoundRat(total, domain.RoundHalfUp)
+	if err != nil {
+		return nil, fmt.Errorf("%w: carbon emission", err)
+	}
+	return &domain.CarbonEmission{CO2eKg: float64(scaled) / float64(scale), CO2eScaled: scaled}, nil
+}
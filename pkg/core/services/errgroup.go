@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// errGroup 一组协作 goroutine，语义与 golang.org/x/sync/errgroup 一致:
+// 第一个返回错误的任务会取消共享的 context，使其余任务尽快退出；Wait 返回第一个错误。
+// 并发度由调用方控制 (如固定数量的 worker)。
+type errGroup struct {
+	cancel  context.CancelCauseFunc
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// newErrGroup 创建任务组及其派生 context (任一任务失败或 Wait 返回后被取消)
+func newErrGroup(ctx context.Context) (*errGroup, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &errGroup{cancel: cancel}, ctx
+}
+
+// Go 在新的 goroutine 中启动一个任务
+func (g *errGroup) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel(err)
+			})
+		}
+	}()
+}
+
+// Wait 等待全部任务结束，返回第一个错误 (如有)
+func (g *errGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel(g.err)
+	return g.err
+}
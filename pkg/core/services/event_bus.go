@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// InProcessEventBus 进程内事件总线，ports.EventBus 的默认实现
+// Publish 在调用方 goroutine 中按订阅顺序同步调用处理函数，全部处理函数都会执行，错误聚合返回
+// (服务层只记录日志，不影响管道)；耗时的处理 (如发送告警) 应在处理函数内自行异步化。
+type InProcessEventBus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   []subscription
+}
+
+type subscription struct {
+	id      int
+	types   []ports.EventType // 为空表示全部类型
+	handler ports.EventHandler
+}
+
+// 编译期检查接口实现
+var _ ports.EventBus = (*InProcessEventBus)(nil)
+
+// NewEventBus 创建进程内事件总线
+func NewEventBus() *InProcessEventBus {
+	return &InProcessEventBus{}
+}
+
+// Publish 实现 ports.EventPublisher
+func (b *InProcessEventBus) Publish(ctx context.Context, event ports.Event) error {
+	b.mu.RLock()
+	subs := slices.Clone(b.subs)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, sub := range subs {
+		if len(sub.types) > 0 && !slices.Contains(sub.types, event.EventType()) {
+			continue
+		}
+		if err := sub.handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe 实现 ports.EventBus
+func (b *InProcessEventBus) Subscribe(handler ports.EventHandler, types ...ports.EventType) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.subs = append(b.subs, subscription{id: id, types: slices.Clone(types), handler: handler})
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.subs = slices.DeleteFunc(b.subs, func(s subscription) bool { return s.id == id })
+	}
+}
+
+// Subscribe 按事件的具体类型订阅，处理函数直接接收 E (须为 ports 中的具体事件类型，如 ports.PersistFailed)
+//
+//	unsubscribe := services.Subscribe(bus, func(ctx context.Context, e ports.PersistFailed) error {
+//	    return alert(ctx, e.Err)
+//	})
+func Subscribe[E ports.Event](bus ports.EventBus, handler func(context.Context, E) error) (unsubscribe func()) {
+	var zero E
+	return bus.Subscribe(func(ctx context.Context, event ports.Event) error {
+		e, ok := event.(E)
+		if !ok {
+			return nil
+		}
+		return handler(ctx, e)
+	}, zero.EventType())
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// publishEvent 发布变更事件 (未配置发布端口时为空操作)
+// 事件在持久化成功之后发出，发布失败只记录日志 (logger 为 nil 时使用 slog.Default())
+func publishEvent(ctx context.Context, logger *slog.Logger, publisher ports.EventPublisher, event ports.Event) {
+	if publisher == nil {
+		return
+	}
+	if err := publisher.Publish(ctx, event); err != nil {
+		loggerOr(logger).Error("failed to publish event", "type", event.EventType(), "error", err)
+	}
+}
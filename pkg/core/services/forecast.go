@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// SeasonalNaiveForecaster 季节性朴素预测，ports.Forecaster 的默认实现
+// 每个未来时间点取上一个季节 (默认一周前) 同一时刻的标准读数，预测跨越多个季节时继续向前回溯。
+// 作为基线预测足够简单可解释；更复杂的模型实现 ports.Forecaster 替换即可。
+type SeasonalNaiveForecaster struct {
+	repo       ports.StandardReadingRepository
+	resolution string
+	metric     domain.Metric
+	season     time.Duration
+	now        func() time.Time
+}
+
+// 编译期检查接口实现
+var _ ports.Forecaster = (*SeasonalNaiveForecaster)(nil)
+
+// SeasonalNaiveOption 定义季节性朴素预测配置选项 (Functional Option Pattern)
+type SeasonalNaiveOption func(*SeasonalNaiveForecaster)
+
+// WithForecastMetric 设置预测的计量通道 (默认为设备的默认通道)
+func WithForecastMetric(metric domain.Metric) SeasonalNaiveOption {
+	return func(f *SeasonalNaiveForecaster) {
+		f.metric = metric
+	}
+}
+
+// WithSeason 设置季节长度 (默认 7 天，须为分辨率的整数倍)
+// 季节按固定时长回溯，夏令时切换前后的预测会错开一小时
+func WithSeason(season time.Duration) SeasonalNaiveOption {
+	return func(f *SeasonalNaiveForecaster) {
+		if season > 0 {
+			f.season = season
+		}
+	}
+}
+
+// WithForecastClock 设置时钟 (默认 time.Now)，该时刻之前一个季节内须有可用读数
+func WithForecastClock(now func() time.Time) SeasonalNaiveOption {
+	return func(f *SeasonalNaiveForecaster) {
+		if now != nil {
+			f.now = now
+		}
+	}
+}
+
+// NewSeasonalNaiveForecaster 创建季节性朴素预测，resolution 为参与预测的标准读数分辨率 (读数须为区间用量)
+func NewSeasonalNaiveForecaster(repo ports.StandardReadingRepository, resolution string, opts ...SeasonalNaiveOption) *SeasonalNaiveForecaster {
+	f := &SeasonalNaiveForecaster{repo: repo, resolution: resolution, season: 7 * 24 * time.Hour, now: time.Now}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Forecast 预测设备最近一条可用读数之后 horizon 时长内的用量
+// 缺失 (MISSING) 与已撤回 (WITHDRAWN) 的读数不参与预测，对应的未来时间点不给出。
+func (f *SeasonalNaiveForecaster) Forecast(ctx context.Context, deviceID string, horizon time.Duration) (domain.Forecast, error) {
+	if deviceID == "" {
+		return domain.Forecast{}, errors.New("forecast: device id is required")
+	}
+	if f.repo == nil {
+		return domain.Forecast{}, errors.New("forecast repository is nil")
+	}
+	interval, ok := resolutionDuration(f.resolution)
+	if !ok {
+		return domain.Forecast{}, fmt.Errorf("forecast: invalid resolution %q", f.resolution)
+	}
+	if f.season%interval != 0 {
+		return domain.Forecast{}, fmt.Errorf("forecast: season %s is not a multiple of resolution %q", f.season, f.resolution)
+	}
+	if horizon <= 0 {
+		return domain.Forecast{}, fmt.Errorf("forecast: horizon %s must be positive", horizon)
+	}
+
+	now := f.now()
+	// 最近一条读数须在一个季节之内，回溯的时间点最早为其前一个季节
+	all, err := f.repo.FindRange(ctx, deviceID, now.Add(-2*f.season), now)
+	if err != nil {
+		return domain.Forecast{}, fmt.Errorf("forecast %s: %w", deviceID, err)
+	}
+	history := make(map[int64]domain.StandardReading)
+	var last time.Time
+	for _, sr := range all {
+		if sr.Metric != f.metric || sr.Resolution != f.resolution ||
+			sr.Quality == domain.QualityMissing || sr.Quality == domain.QualityWithdrawn {
+			continue
+		}
+		history[sr.Timestamp.UnixNano()] = sr
+		if sr.Timestamp.After(last) {
+			last = sr.Timestamp
+		}
+	}
+	if len(history) == 0 || last.Before(now.Add(-f.season)) {
+		return domain.Forecast{}, fmt.Errorf("forecast %s: %w: no readings within the last %s", deviceID, ports.ErrInsufficientHistory, f.season)
+	}
+
+	start := last.Add(interval)
+	out := domain.Forecast{
+		DeviceID:    deviceID,
+		Metric:      f.metric,
+		Method:      "seasonal-naive",
+		Interval:    interval,
+		Start:       start,
+		End:         start.Add(horizon),
+		GeneratedAt: now,
+	}
+	for t := start; t.Before(out.End); t = t.Add(interval) {
+		// 回溯整数个季节，直到落在最近一条读数之前 (含)
+		seasons := int64((t.Sub(last) + f.season - 1) / f.season)
+		sr, ok := history[t.Add(-time.Duration(seasons)*f.season).UnixNano()]
+		if !ok {
+			continue
+		}
+		out.Points = append(out.Points, domain.ForecastPoint{
+			Timestamp:   t,
+			Value:       sr.ValueDisplay,
+			ValueScaled: sr.ValueScaled,
+			ScaleFactor: sr.ScaleFactor,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// GroupReportQuery 层级汇总报表的生成条件
+type GroupReportQuery struct {
+	RootID string // 层级树中汇总的起点 (如园区节点)
+
+	// DeviceType 只汇总该设备类型 (能源载体) 的设备，必填: 不同载体的计量单位不同 (kWh / m³)，不能相加
+	DeviceType domain.DeviceType
+
+	Metric     domain.Metric       // 为空表示设备的默认通道
+	Resolution string              // 参与统计的分辨率 (必填)
+	Period     domain.ReportPeriod // 统计周期: HOUR / DAY / WEEK / MONTH
+	Start, End time.Time           // 时间区间 [Start, End)，按统计周期向外取整
+	Cumulative bool                // 标准读数为累计值 (见 ports.ReportQuery)
+
+	// Location 周期切分所用时区 (为空表示 UTC)
+	// 整棵树使用同一时区，覆盖各设备的 Timezone，保证上下级的周期边界一致
+	Location *time.Location
+}
+
+// GroupReport 层级树一个节点的报表
+type GroupReport struct {
+	Node     domain.TopologyNode
+	Reports  []domain.EnergyReport // 按周期升序；分组节点为其下各节点之和，没有数据的周期不返回
+	Children []*GroupReport        // 子节点 (不含其他设备类型的设备节点)
+}
+
+// GenerateGroupReport 按层级树逐级汇总用量 (园区 → 楼栋 → 楼层 → 设备)
+// 设备节点的报表由 generator 生成；分组节点的各周期用量为子节点之和 (换算到最大的精度因子后以定点整数相加)，
+// 碳排放只在全部参与的子节点都给出排放量时汇总。设备节点的用量只取自身读数，其下挂的设备 (如总表下的分表) 不再累加，避免重复计入。
+func GenerateGroupReport(ctx context.Context, topology ports.TopologyRepository, generator ports.ReportGenerator, q GroupReportQuery) (*GroupReport, error) {
+	if q.DeviceType == "" {
+		return nil, errors.New("group report query: device type is required")
+	}
+	nodes, err := topology.FindSubtree(ctx, q.RootID)
+	if err != nil {
+		return nil, fmt.Errorf("group report %s: %w", q.RootID, err)
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// FindSubtree 按层级顺序返回，父节点总在子节点之前
+	index := make(map[string]*GroupReport, len(nodes))
+	order := make([]*GroupReport, 0, len(nodes))
+	for _, n := range nodes {
+		if n.Device != nil && n.Device.Type != q.DeviceType {
+			continue
+		}
+		g := &GroupReport{Node: n}
+		if parent, ok := index[n.ParentID]; ok {
+			parent.Children = append(parent.Children, g)
+		}
+		index[n.ID] = g
+		order = append(order, g)
+		if n.Device == nil {
+			continue
+		}
+		reports, err := generator.Generate(ctx, ports.ReportQuery{
+			Device: *n.Device, Metric: q.Metric, Resolution: q.Resolution, Period: q.Period,
+			Start: q.Start, End: q.End, Location: loc, Cumulative: q.Cumulative,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("group report %s: %w", q.RootID, err)
+		}
+		g.Reports = reports
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("group report %s: root is a device of another type", q.RootID)
+	}
+
+	// 自下而上汇总分组节点
+	for i := len(order) - 1; i >= 0; i-- {
+		g := order[i]
+		if g.Node.Device != nil {
+			continue
+		}
+		reports, err := mergeGroupReports(g.Node, q, g.Children)
+		if err != nil {
+			return nil, fmt.Errorf("group report %s: %w", g.Node.ID, err)
+		}
+		g.Reports = reports
+	}
+	return order[0], nil
+}
+
+// mergeGroupReports 把子节点的报表按周期相加，得到分组节点的报表
+func mergeGroupReports(node domain.TopologyNode, q GroupReportQuery, children []*GroupReport) ([]domain.EnergyReport, error) {
+	byStart := make(map[int64][]domain.EnergyReport)
+	for _, child := range children {
+		for _, r := range child.Reports {
+			byStart[r.StartTime.UnixNano()] = append(byStart[r.StartTime.UnixNano()], r)
+		}
+	}
+	out := make([]domain.EnergyReport, 0, len(byStart))
+	for _, parts := range byStart {
+		scale := 1
+		carbon := true
+		for _, r := range parts {
+			if err := domain.ValidateScaleFactor(r.ScaleFactor); err != nil {
+				return nil, err
+			}
+			scale = max(scale, r.ScaleFactor)
+			carbon = carbon && r.Carbon != nil
+		}
+		var usage, co2e int64
+		for _, r := range parts {
+			if err := addRescaled(&usage, r.UsageScaled, r.ScaleFactor, scale); err != nil {
+				return nil, err
+			}
+			if carbon {
+				if err := addRescaled(&co2e, r.Carbon.CO2eScaled, r.ScaleFactor, scale); err != nil {
+					return nil, err
+				}
+			}
+		}
+		first := parts[0]
+		report := domain.EnergyReport{
+			ID:          fmt.Sprintf("%s/%s/%s", node.ID, q.Period, first.StartTime.Format(time.RFC3339)),
+			DeviceType:  q.DeviceType,
+			Metric:      q.Metric,
+			Period:      q.Period,
+			StartTime:   first.StartTime,
+			EndTime:     first.EndTime,
+			TotalUsage:  float64(usage) / float64(scale),
+			UsageScaled: usage,
+			ScaleFactor: scale,
+		}
+		if carbon {
+			report.Carbon = &domain.CarbonEmission{CO2eKg: float64(co2e) / float64(scale), CO2eScaled: co2e}
+		}
+		out = append(out, report)
+	}
+	slices.SortFunc(out, func(a, b domain.EnergyReport) int { return a.StartTime.Compare(b.StartTime) })
+	return out, nil
+}
+
+// addRescaled 把精度因子为 from 的定点值换算到 to 后累加到 total (溢出时返回 ErrScaleOverflow)
+func addRescaled(total *int64, v int64, from, to int) error {
+	v, err := rescaleValue(v, from, to)
+	if err != nil {
+		return err
+	}
+	if (v > 0 && *total > math.MaxInt64-v) || (v < 0 && *total < math.MinInt64-v) {
+		return fmt.Errorf("%w: group total", domain.ErrScaleOverflow)
+	}
+	*total += v
+	return nil
+}
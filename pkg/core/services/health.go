@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// HealthChecks 按名称登记的一组仓储健康检查 (如 "standard"、"rules"、"quarantine")
+// 用于就绪探针: CheckAll 并发执行全部检查，任一失败即视为未就绪。
+type HealthChecks map[string]ports.HealthChecker
+
+// CheckAll 并发执行全部检查，返回按名称排序后合并的错误 (errors.Join)，全部通过时返回 nil
+// 单个检查的错误以 "名称: 原因" 形式包装，可用 errors.Is/As 检查底层错误；nil 检查器会被跳过。
+func (h HealthChecks) CheckAll(ctx context.Context) error {
+	names := slices.Sorted(maps.Keys(h))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		checker := h[name]
+		if checker == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := checker.HealthCheck(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// loadHistory 为批次中的每个计量通道加载已持久化的最后一条标准读数 (早于该通道本批次最早的读数)
+// 返回 ChannelID -> 读数；未配置持久层时返回 nil；查询失败只记录日志，该通道退回无历史的清洗方式
+func (s *CoreStandardizer) loadHistory(ctx context.Context, readings []domain.Reading) map[string]domain.Reading {
+	if s.repo == nil || len(readings) == 0 {
+		return nil
+	}
+
+	earliest := make(map[string]domain.Reading)
+	for _, r := range readings {
+		if first, ok := earliest[r.ChannelID()]; !ok || r.Timestamp.Before(first.Timestamp) {
+			earliest[r.ChannelID()] = r
+		}
+	}
+
+	history := make(map[string]domain.Reading, len(earliest))
+	for id, first := range earliest {
+		if prev := s.lastPersisted(ctx, first.DeviceInfo, first.Metric, first.Timestamp); prev != nil {
+			history[id] = *prev
+		}
+	}
+	return history
+}
+
+// lastPersisted 查询设备某计量通道在 before 之前最后一条标准间隔的标准读数，并还原为清洗上下文可用的读数
+func (s *CoreStandardizer) lastPersisted(ctx context.Context, device domain.DeviceInfo, metric domain.Metric, before time.Time) *domain.Reading {
+	sr, err := s.repo.FindLastBefore(ctx, device.ID, metric, domain.ResolutionTag(s.standardInterval), before)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			loggerOr(s.logger).Warn("failed to load last standard reading, cleaning without history",
+				"device_id", device.ID, "error", err)
+		}
+		return nil
+	}
+	// 已撤回的读数不可信，不作为清洗上下文
+	if sr == nil || sr.Withdrawal != nil {
+		return nil
+	}
+	// 清洗规则作用于原始值: 已校准的标准读数需还原
+	value := sr.ValueDisplay
+	if sr.Calibration != nil {
+		value = sr.Calibration.Revert(value)
+	}
+	return &domain.Reading{
+		DeviceInfo: device,
+		Metric:     metric,
+		Timestamp:  sr.Timestamp,
+		Value:      value,
+		Quality:    sr.Quality,
+		Priority:   sr.Priority,
+		Confidence: sr.Confidence,
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// 管道钩子 (Middleware)
+// 在 ProcessAndStandardize 的固定阶段注入自定义逻辑 (数据增强、指标、额外过滤等)，无需 fork 服务。
+// 同一阶段的多个钩子按注册顺序串联执行，前一个的输出是后一个的输入；任一钩子返回错误即中止该阶段。
+
+// PreCleanHook 清洗前钩子: 接收原始读数 (已做时钟偏移修正)，返回进入清洗的读数
+type PreCleanHook func(ctx context.Context, raw []domain.Reading) ([]domain.Reading, error)
+
+// PostCleanHook 清洗后钩子: 可调整有效读数与隔离记录 (如把业务上不可接受的读数移入隔离区)
+type PostCleanHook func(ctx context.Context, clean []domain.Reading, quarantined []domain.QuarantineReading) ([]domain.Reading, []domain.QuarantineReading, error)
+
+// PreAlignHook 对齐前钩子: 按设备调用，接收单台设备的有效读数
+// 各设备在 worker 池中并发执行，钩子必须是并发安全的；返回错误只影响该设备 (记入 DeviceErrors)
+type PreAlignHook func(ctx context.Context, devReadings []domain.Reading) ([]domain.Reading, error)
+
+// PrePersistHook 持久化前钩子: 接收本批次全部标准读数，其输出即为持久化与返回的结果
+// 未配置持久层时同样执行
+type PrePersistHook func(ctx context.Context, standards []domain.StandardReading) ([]domain.StandardReading, error)
+
+// WithPreCleanHook 注册清洗前钩子
+func WithPreCleanHook(hooks ...PreCleanHook) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.preClean = append(s.preClean, hooks...)
+	}
+}
+
+// WithPostCleanHook 注册清洗后钩子
+func WithPostCleanHook(hooks ...PostCleanHook) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.postClean = append(s.postClean, hooks...)
+	}
+}
+
+// WithPreAlignHook 注册对齐前钩子
+func WithPreAlignHook(hooks ...PreAlignHook) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.preAlign = append(s.preAlign, hooks...)
+	}
+}
+
+// WithPrePersistHook 注册持久化前钩子
+func WithPrePersistHook(hooks ...PrePersistHook) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.prePersist = append(s.prePersist, hooks...)
+	}
+}
+
+func (s *CoreStandardizer) runPreClean(ctx context.Context, raw []domain.Reading) ([]domain.Reading, error) {
+	var err error
+	for _, hook := range s.preClean {
+		if raw, err = hook(ctx, raw); err != nil {
+			return nil, fmt.Errorf("pre-clean hook failed: %w", err)
+		}
+	}
+	return raw, nil
+}
+
+func (s *CoreStandardizer) runPostClean(ctx context.Context, clean []domain.Reading, quarantined []domain.QuarantineReading) ([]domain.Reading, []domain.QuarantineReading, error) {
+	var err error
+	for _, hook := range s.postClean {
+		if clean, quarantined, err = hook(ctx, clean, quarantined); err != nil {
+			return nil, nil, fmt.Errorf("post-clean hook failed: %w", err)
+		}
+	}
+	return clean, quarantined, nil
+}
+
+func (s *CoreStandardizer) runPreAlign(ctx context.Context, devReadings []domain.Reading) ([]domain.Reading, error) {
+	var err error
+	for _, hook := range s.preAlign {
+		if devReadings, err = hook(ctx, devReadings); err != nil {
+			return nil, fmt.Errorf("pre-align hook failed: %w", err)
+		}
+	}
+	return devReadings, nil
+}
+
+func (s *CoreStandardizer) runPrePersist(ctx context.Context, standards []domain.StandardReading) ([]domain.StandardReading, error) {
+	var err error
+	for _, hook := range s.prePersist {
+		if standards, err = hook(ctx, standards); err != nil {
+			return nil, fmt.Errorf("pre-persist hook failed: %w", err)
+		}
+	}
+	return standards, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrStandardizerClosed 标准化服务已关闭 (Shutdown / Close 之后)，不再接收新批次
+var ErrStandardizerClosed = errors.New("standardizer closed")
+
+// inflight 在途工作计数，归零时唤醒等待者；stop 之后 begin 返回 false
+// (不使用 WaitGroup: 等待与新增可能并发，WaitGroup 不允许计数为 0 时 Add 与 Wait 并发)
+type inflight struct {
+	mu      sync.Mutex
+	n       int
+	stopped bool
+	idle    []chan struct{}
+}
+
+// begin 登记一项在途工作，已 stop 时返回 false (未登记)
+func (f *inflight) begin() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stopped {
+		return false
+	}
+	f.n++
+	return true
+}
+
+// add 调整在途计数 (不检查 stop，用于已被接收的工作)
+func (f *inflight) add(delta int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.n += delta
+	if f.n == 0 {
+		for _, ch := range f.idle {
+			close(ch)
+		}
+		f.idle = nil
+	}
+}
+
+// done 结束一项在途工作
+func (f *inflight) done() { f.add(-1) }
+
+// stop 拒绝之后的 begin
+func (f *inflight) stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped = true
+}
+
+// wait 等待在途计数归零，ctx 到期时返回 ctx 的错误
+func (f *inflight) wait(ctx context.Context) error {
+	f.mu.Lock()
+	if f.n == 0 {
+		f.mu.Unlock()
+		return nil
+	}
+	idle := make(chan struct{})
+	f.idle = append(f.idle, idle)
+	f.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Drain 等待在途批次处理完毕、隔离区队列中的记录全部保存，服务保持可用
+// 适用于滚动发布前的检查点；ctx 到期时返回 ctx 的错误 (在途工作不会被中断)
+func (s *CoreStandardizer) Drain(ctx context.Context) error {
+	if err := s.batches.wait(ctx); err != nil {
+		return fmt.Errorf("drain in-flight batches: %w", err)
+	}
+	return s.quarantine.flush(ctx)
+}
+
+// Shutdown 优雅停止: 之后的批次返回 ErrStandardizerClosed，等待在途批次处理完毕，
+// 排空隔离区队列并停止后台 worker 与规则变更订阅。
+// ctx 到期时返回 ctx 的错误，未完成的工作继续在后台进行；可以用新的 ctx 再次调用以继续等待。
+func (s *CoreStandardizer) Shutdown(ctx context.Context) error {
+	s.batches.stop()
+	if s.stopRuleWatch != nil {
+		s.stopRuleWatch()
+	}
+	if err := s.batches.wait(ctx); err != nil {
+		return fmt.Errorf("wait for in-flight batches: %w", err)
+	}
+	return s.quarantine.close(ctx)
+}
+
+// Close 等同于 Shutdown(context.Background())，无限期等待在途工作完成
+func (s *CoreStandardizer) Close() error {
+	return s.Shutdown(context.Background())
+}
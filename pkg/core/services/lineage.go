@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// ReadingLineage 一条标准读数的数据血缘: 由哪些原始读数、经过哪些规则、在哪个批次中产生
+type ReadingLineage struct {
+	Reading domain.StandardReading `json:"reading"`
+
+	// WindowStart / WindowEnd 参与该槽位取值的原始读数时间窗口 [start, end)
+	// 快照模式为 t±tolerance，聚合模式为 [t, next)
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+
+	// Raw 原始读数仓储中落在窗口内、同一计量通道的读数 (清洗前的归档副本)
+	Raw []domain.Reading `json:"raw"`
+
+	// Quarantined 窗口内被规则拒绝的读数 (未配置隔离区仓储时为空)
+	Quarantined []domain.QuarantineReading `json:"quarantined,omitempty"`
+
+	// Rules 当前对该设备类型生效的规则 ID (按执行顺序)
+	Rules []string `json:"rules"`
+
+	// Corrections 规则对取值读数所做的修正说明 (取自 QualityReason)
+	Corrections []string `json:"corrections,omitempty"`
+
+	// BatchID 写入该标准读数的摄入批次 (取自 Provenance，未记录时为空)
+	BatchID string `json:"batch_id,omitempty"`
+
+	// Audit 该时间点的覆盖历史，按覆盖时间升序 (未配置审计仓储时为空)
+	Audit []ports.StandardReadingAudit `json:"audit,omitempty"`
+}
+
+// Lineage 查询标准读数的数据血缘
+// 原始读数来自原始读数仓储 (WithRawRepository)，覆盖历史来自审计仓储 (WithAuditRepository)。
+// 规则列表反映查询时生效的规则；规则在读数产生之后变更过时，应结合 Audit 与 Corrections 判断。
+func (s *CoreStandardizer) Lineage(ctx context.Context, sr domain.StandardReading) (*ReadingLineage, error) {
+	if s.rawRepo == nil {
+		return nil, ErrRawRepositoryNotConfigured
+	}
+	ctx = domain.WithTenant(ctx, sr.TenantID)
+
+	interval, err := s.resolutionInterval(sr.Resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先按最宽的窗口取回原始读数，再根据设备信息确定实际窗口
+	lookback := max(interval, s.tolerance)
+	raw, err := s.rawRepo.FindRange(ctx, sr.DeviceID, sr.Timestamp.Add(-lookback), sr.Timestamp.Add(lookback))
+	if err != nil {
+		return nil, fmt.Errorf("load raw readings for %s failed: %w", sr.DeviceID, err)
+	}
+	var channel []domain.Reading
+	for _, r := range raw {
+		if r.Metric == sr.Metric {
+			channel = append(channel, r)
+		}
+	}
+
+	lineage := &ReadingLineage{Reading: sr, Rules: []string{}}
+	if sr.Provenance != nil {
+		lineage.BatchID = sr.Provenance.BatchID
+	}
+	if sr.QualityReason != "" {
+		lineage.Corrections = strings.Split(sr.QualityReason, "; ")
+	}
+
+	start, end := sr.Timestamp.Add(-s.tolerance), sr.Timestamp.Add(s.tolerance+1)
+	if len(channel) > 0 {
+		if mode := s.aggregationFor(channel[0]); isAggregated(mode) {
+			grid := s.grid(interval, s.gridLocation(ctx, channel[0].DeviceInfo))
+			start, end = sr.Timestamp, grid.Next(sr.Timestamp)
+		}
+		lineage.Raw = domain.Window(channel, start, end)
+
+		rules, err := s.rulesFor(ctx, channel[0].DeviceInfo.Type)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			lineage.Rules = append(lineage.Rules, ruleID(rule))
+		}
+	}
+	lineage.WindowStart, lineage.WindowEnd = start, end
+
+	if s.quarantineRepo != nil {
+		records, err := s.quarantineRepo.FindByDevice(ctx, sr.DeviceID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("load quarantine records for %s failed: %w", sr.DeviceID, err)
+		}
+		for _, q := range records {
+			if q.Reading.Metric == sr.Metric && q.Reading.Timestamp.Before(end) {
+				lineage.Quarantined = append(lineage.Quarantined, q)
+			}
+		}
+	}
+
+	if s.auditRepo != nil {
+		entries, err := s.auditRepo.FindByDevice(ctx, sr.DeviceID, sr.Timestamp, sr.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("load audit records for %s failed: %w", sr.DeviceID, err)
+		}
+		for _, e := range entries {
+			if e.New.Metric == sr.Metric && e.New.Resolution == sr.Resolution {
+				lineage.Audit = append(lineage.Audit, e)
+			}
+		}
+	}
+	return lineage, nil
+}
+
+// resolutionInterval 把分辨率标签还原为输出网格的间隔 (空标签视为标准间隔)
+func (s *CoreStandardizer) resolutionInterval(resolution string) (time.Duration, error) {
+	for _, d := range s.intervals() {
+		if resolution == "" || domain.ResolutionTag(d) == resolution {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown resolution %q", resolution)
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DayType 负荷曲线的日类型
+type DayType string
+
+const (
+	DayTypeWeekday DayType = "WEEKDAY" // 周一至周五
+	DayTypeWeekend DayType = "WEEKEND" // 周六、周日
+)
+
+// dayTypeOf 返回当地日期的日类型
+func dayTypeOf(t time.Time) DayType {
+	if wd := t.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return DayTypeWeekend
+	}
+	return DayTypeWeekday
+}
+
+// LoadProfileQuery 典型负荷曲线的生成条件
+type LoadProfileQuery struct {
+	DeviceIDs  []string       // 设备；多台设备时同一时间点的读数先求和，得到群组的负荷曲线
+	Metric     domain.Metric  // 为空表示设备的默认通道
+	Resolution string         // 曲线的时间粒度 (如 "15m"、"1h")，须能整除一天
+	Start, End time.Time      // 时间区间 [Start, End)
+	Location   *time.Location // 按当地时间划分日期与时段 (为空表示 UTC)
+}
+
+// LoadProfilePoint 负荷曲线上的一个时段
+type LoadProfilePoint struct {
+	Offset  time.Duration `json:"offset"`  // 距当地零点的偏移 (时段开始)
+	Label   string        `json:"label"`   // 时段开始的当地时间 (HH:MM)，作图的横轴标签
+	Average float64       `json:"average"` // 该时段读数的平均值
+	Samples int           `json:"samples"` // 参与平均的读数条数 (各天该时段)
+}
+
+// LoadProfile 一条 24 小时平均负荷曲线
+type LoadProfile struct {
+	Month   time.Month         `json:"month,omitempty"` // 月份 (0 表示全部月份)
+	DayType DayType            `json:"day_type"`        // 日类型
+	Days    int                `json:"days"`            // 参与统计的天数
+	Points  []LoadProfilePoint `json:"points"`          // 全天各时段 (按时段升序，长度为 24h / 粒度)
+}
+
+// LoadProfiles 负荷曲线集合
+type LoadProfiles struct {
+	Interval time.Duration `json:"interval"` // 时段粒度
+	Location string        `json:"location"` // 划分日期所用时区
+	Profiles []LoadProfile `json:"profiles"` // 先为全部月份的工作日 / 周末曲线，再按月份升序给出各月的工作日 / 周末曲线；没有数据的组合不返回
+}
+
+// Find 返回指定月份 (0 表示全部月份) 与日类型的负荷曲线
+func (p LoadProfiles) Find(month time.Month, dayType DayType) (LoadProfile, bool) {
+	for _, lp := range p.Profiles {
+		if lp.Month == month && lp.DayType == dayType {
+			return lp, true
+		}
+	}
+	return LoadProfile{}, false
+}
+
+// GenerateLoadProfiles 基于标准读数生成按 工作日/周末 与 月份 划分的 24 小时平均负荷曲线 (基线研究)
+// 每个时段的平均值为各天该时段读数的算术平均；夏令时切换日缺少或重复的时段按当地时钟归入对应时段。
+// 缺失 (MISSING) 与已撤回 (WITHDRAWN) 的读数不参与统计。
+func GenerateLoadProfiles(ctx context.Context, repo ports.StandardReadingRepository, q LoadProfileQuery) (LoadProfiles, error) {
+	if len(q.DeviceIDs) == 0 {
+		return LoadProfiles{}, errors.New("load profile query: device ids are required")
+	}
+	interval, ok := resolutionDuration(q.Resolution)
+	if !ok || interval > 24*time.Hour || (24*time.Hour)%interval != 0 {
+		return LoadProfiles{}, fmt.Errorf("load profile query: resolution %q must evenly divide a day", q.Resolution)
+	}
+	if !q.End.After(q.Start) {
+		return LoadProfiles{}, fmt.Errorf("load profile query: end %s must be after start %s", q.End.Format(time.RFC3339), q.Start.Format(time.RFC3339))
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	byDevice, err := FindRangeMulti(ctx, repo, q.DeviceIDs, q.Start, q.End.Add(-time.Nanosecond)) // FindRange 为闭区间
+	if err != nil {
+		return LoadProfiles{}, fmt.Errorf("load profile: %w", err)
+	}
+	// 同一时间点各设备的读数求和
+	sums := make(map[int64]float64)
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(q.DeviceIDs))) {
+		for _, sr := range byDevice[id] {
+			if sr.Metric != q.Metric || sr.Resolution != q.Resolution || sr.ScaleFactor <= 0 ||
+				sr.Quality == domain.QualityMissing || sr.Quality == domain.QualityWithdrawn {
+				continue
+			}
+			sums[sr.Timestamp.UnixNano()] += float64(sr.ValueScaled) / float64(sr.ScaleFactor)
+		}
+	}
+
+	type profileKey struct {
+		month   time.Month
+		dayType DayType
+	}
+	type accumulator struct {
+		sum     []float64
+		samples []int
+		days    map[string]bool
+	}
+	slots := int(24 * time.Hour / interval)
+	acc := make(map[profileKey]*accumulator)
+	for ns, v := range sums {
+		local := time.Unix(0, ns).In(loc)
+		clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+		slot := int(clock / interval)
+		day := local.Format(time.DateOnly)
+		dayType := dayTypeOf(local)
+		for _, key := range []profileKey{{0, dayType}, {local.Month(), dayType}} {
+			a := acc[key]
+			if a == nil {
+				a = &accumulator{sum: make([]float64, slots), samples: make([]int, slots), days: make(map[string]bool)}
+				acc[key] = a
+			}
+			a.sum[slot] += v
+			a.samples[slot]++
+			a.days[day] = true
+		}
+	}
+
+	keys := make([]profileKey, 0, len(acc))
+	for key := range acc {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b profileKey) int {
+		return cmp.Or(cmp.Compare(a.month, b.month), cmp.Compare(a.dayType, b.dayType))
+	})
+	out := LoadProfiles{Interval: interval, Location: loc.String(), Profiles: make([]LoadProfile, 0, len(keys))}
+	for _, key := range keys {
+		a := acc[key]
+		points := make([]LoadProfilePoint, slots)
+		for i := range points {
+			offset := time.Duration(i) * interval
+			points[i] = LoadProfilePoint{
+				Offset:  offset,
+				Label:   fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60),
+				Samples: a.samples[i],
+			}
+			if a.samples[i] > 0 {
+				points[i].Average = a.sum[i] / float64(a.samples[i])
+			}
+		}
+		out.Profiles = append(out.Profiles, LoadProfile{Month: key.month, DayType: key.dayType, Days: len(a.days), Points: points})
+	}
+	return out, nil
+}
@@ -0,0 +1,19 @@
+package services
+
+import "log/slog"
+
+// WithLogger 设置结构化日志输出 (默认使用调用时的 slog.Default())
+// 同时用于隔离区后台保存、审计、事件发布等内部组件，嵌入方可据此控制日志目标、级别与公共字段
+func WithLogger(logger *slog.Logger) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.logger = logger
+	}
+}
+
+// loggerOr 返回 logger，未配置时返回当前的 slog.Default() (跟随 slog.SetDefault)
+func loggerOr(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
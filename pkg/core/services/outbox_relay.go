@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DefaultOutboxBatchSize 发件箱每次读取的消息数
+const DefaultOutboxBatchSize = 100
+
+// OutboxRelay 发件箱转发任务: 按顺序读取未发布的消息，发布到消息代理后标记为已发布
+// 发布成功但标记失败 (或进程崩溃) 时消息会再次发布，即至少一次投递；同一发件箱只应运行一个转发任务，以保证顺序。
+type OutboxRelay struct {
+	outbox    ports.Outbox
+	publisher ports.MessagePublisher
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewOutboxRelay 创建发件箱转发任务 (batchSize <= 0 使用 DefaultOutboxBatchSize)
+func NewOutboxRelay(outbox ports.Outbox, publisher ports.MessagePublisher, batchSize int) (*OutboxRelay, error) {
+	if outbox == nil {
+		return nil, errors.New("outbox is nil")
+	}
+	if publisher == nil {
+		return nil, errors.New("message publisher is nil")
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultOutboxBatchSize
+	}
+	return &OutboxRelay{outbox: outbox, publisher: publisher, batchSize: batchSize}, nil
+}
+
+// RunOnce 发布当前全部未发布的消息，返回发布的条数
+// 某条消息发布失败时停止 (后续消息不越过它发布)，已发布的消息照常标记，下次执行从失败的消息继续
+func (r *OutboxRelay) RunOnce(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		msgs, err := r.outbox.Pending(ctx, r.batchSize)
+		if err != nil {
+			return total, fmt.Errorf("load pending outbox messages: %w", err)
+		}
+		if len(msgs) == 0 {
+			return total, nil
+		}
+
+		published := make([]int64, 0, len(msgs))
+		var pubErr error
+		for _, msg := range msgs {
+			if pubErr = r.publisher.Publish(ctx, msg); pubErr != nil {
+				pubErr = fmt.Errorf("publish outbox message %d to %s: %w", msg.ID, msg.Topic, pubErr)
+				break
+			}
+			published = append(published, msg.ID)
+		}
+		if len(published) > 0 {
+			if err := r.outbox.MarkPublished(ctx, published); err != nil {
+				return total, errors.Join(pubErr, fmt.Errorf("mark outbox messages published: %w", err))
+			}
+			total += len(published)
+		}
+		if pubErr != nil || len(msgs) < r.batchSize {
+			return total, pubErr
+		}
+	}
+}
+
+// SetLogger 设置 Run 的日志输出 (nil 使用 slog.Default())，须在 Run 之前调用
+func (r *OutboxRelay) SetLogger(logger *slog.Logger) {
+	r.logger = logger
+}
+
+// Run 启动时及之后每隔 interval 转发一次发件箱，直到 ctx 取消 (见 runEvery)；转发失败只记录日志
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid outbox relay interval %s", interval)
+	}
+	return runEvery(ctx, interval, func(ctx context.Context) {
+		if _, err := r.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			loggerOr(r.logger).Error("outbox relay failed", "error", err)
+		}
+	})
+}
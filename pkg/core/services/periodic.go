@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// runEvery 立即执行一次 fn，之后每隔 interval 执行一次，直到 ctx 取消 (返回 ctx.Err())
+// fn 自行处理单次执行的错误，循环不会因此终止；interval 须 > 0，由调用方校验
+func runEvery(ctx context.Context, interval time.Duration, fn func(context.Context)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		fn(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
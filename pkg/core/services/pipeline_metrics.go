@@ -0,0 +1,28 @@
+package services
+
+import (
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// WithMetrics 设置管道指标输出 (默认不输出)
+// 每个批次上报各阶段的读数计数与耗时，见 ports.StandardizerMetrics
+func WithMetrics(m ports.StandardizerMetrics) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		if m != nil {
+			s.metrics = m
+		}
+	}
+}
+
+// noopMetrics 未配置指标输出时的空实现
+type noopMetrics struct{}
+
+func (noopMetrics) AddReadings(ports.PipelineCounter, int)          {}
+func (noopMetrics) ObserveStage(ports.PipelineStage, time.Duration) {}
+
+// observeSince 记录阶段耗时 (用法: defer s.observeSince(stage, time.Now()))
+func (s *CoreStandardizer) observeSince(stage ports.PipelineStage, start time.Time) {
+	s.metrics.ObserveStage(stage, time.Since(start))
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// maxPooledReadings 超过该容量的切片不放回池中，避免个别超大设备组长期占用内存
+const maxPooledReadings = 1 << 16
+
+// readingSlicePool 复用设备分组的读数切片 (百万级补数时可显著降低 GC 压力)
+var readingSlicePool = sync.Pool{
+	New: func() any {
+		s := make([]domain.Reading, 0, 64)
+		return &s
+	},
+}
+
+// getReadingSlice 从池中取出长度为 0、容量不少于 n 的读数切片
+func getReadingSlice(n int) *[]domain.Reading {
+	p := readingSlicePool.Get().(*[]domain.Reading)
+	if cap(*p) < n {
+		*p = make([]domain.Reading, 0, n)
+	}
+	*p = (*p)[:0]
+	return p
+}
+
+// putReadingSlice 清空并归还读数切片；调用方之后不得再持有该切片
+func putReadingSlice(p *[]domain.Reading) {
+	if cap(*p) > maxPooledReadings {
+		return
+	}
+	clear(*p) // 释放 DeviceInfo 中的 map/字符串引用
+	*p = (*p)[:0]
+	readingSlicePool.Put(p)
+}
+
+// deviceBatch 单台设备 (单个计量通道) 在本批次中的有效读数 (切片来自 readingSlicePool)
+type deviceBatch struct {
+	channel     string // ChannelID (默认通道即设备ID)
+	readings    *[]domain.Reading
+	first, last time.Time
+}
+
+// groupByDevice 按设备 (多通道设备按计量通道) 分组，先计数再从池中按需取切片，避免 append 反复扩容
+func groupByDevice(readings []domain.Reading) map[string]*deviceBatch {
+	counts := make(map[string]int)
+	for _, r := range readings {
+		counts[r.ChannelID()]++
+	}
+
+	groups := make(map[string]*deviceBatch, len(counts))
+	for _, r := range readings {
+		channel := r.ChannelID()
+		b, ok := groups[channel]
+		if !ok {
+			b = &deviceBatch{
+				channel:  channel,
+				readings: getReadingSlice(counts[channel]),
+				first:    r.Timestamp,
+				last:     r.Timestamp,
+			}
+			groups[channel] = b
+		}
+		*b.readings = append(*b.readings, r)
+		if r.Timestamp.Before(b.first) {
+			b.first = r.Timestamp
+		}
+		if r.Timestamp.After(b.last) {
+			b.last = r.Timestamp
+		}
+	}
+	return groups
+}
+
+// estimateSlots 估算一台设备在全部分辨率上产生的标准读数数量 (用于预分配)
+func (s *CoreStandardizer) estimateSlots(b *deviceBatch) int {
+	span := b.last.Sub(b.first)
+	n := 0
+	for _, interval := range s.intervals() {
+		n += int(span/interval) + 2
+	}
+	return n
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// ingestPriority 返回 ctx 中 IngestContext 策略对应的优先级 (未携带时按实时数据处理)
+func ingestPriority(ctx context.Context) int {
+	if info, ok := domain.FromContext(ctx); ok {
+		return info.Strategy.GetPriority()
+	}
+	return domain.IngestStrategyRealtime.GetPriority()
+}
+
+// applyIngestPriority 返回读数的副本，其中未设置 Priority 的读数填入批次策略优先级 (不修改调用方的切片)
+// 使同一批次中混合的不同来源 (读数级 Priority) 可以在去重与对齐时按优先级裁决
+func applyIngestPriority(ctx context.Context, readings []domain.Reading) []domain.Reading {
+	priority := ingestPriority(ctx)
+	out := slices.Clone(readings)
+	for i := range out {
+		if out[i].Priority == 0 {
+			out[i].Priority = priority
+		}
+	}
+	return out
+}
+
+// dropOutranked 在清洗去重之前裁决同一计量通道同一时间戳的读数: 只保留优先级最高者 (保持到达顺序)，
+// 被覆盖的读数作为重复读数隔离。去重策略 (如默认的 KEEP_FIRST) 随后只在同优先级的读数之间取舍，
+// 低优先级读数不会因先到达而胜出
+func dropOutranked(readings []domain.Reading) ([]domain.Reading, []domain.QuarantineReading) {
+	type key struct {
+		channel  string
+		unixNano int64
+	}
+	top := make(map[key]int, len(readings))
+	for _, r := range readings {
+		k := key{r.ChannelID(), r.Timestamp.UnixNano()}
+		if p, ok := top[k]; !ok || r.Priority > p {
+			top[k] = r.Priority
+		}
+	}
+	if len(top) == len(readings) {
+		return readings, nil
+	}
+
+	kept := make([]domain.Reading, 0, len(top))
+	var dropped []domain.QuarantineReading
+	for _, r := range readings {
+		if p := top[key{r.ChannelID(), r.Timestamp.UnixNano()}]; r.Priority < p {
+			dropped = append(dropped, domain.QuarantineReading{
+				Reading:   r,
+				Status:    domain.QuarantineStatusPending,
+				Reason:    fmt.Sprintf("Duplicate timestamp outranked by priority %d", p),
+				Code:      domain.QuarantineCodeDuplicate,
+				CreatedAt: time.Now(),
+			})
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept, dropped
+}
+
+// highestPriority 从槽位候选读数中筛选出优先级最高的子集
+// 候选读数优先级不一致时返回裁决记录 (胜出方与被覆盖方的优先级及被覆盖条数)，
+// 由调用方补全设备、时间点与分辨率
+func highestPriority(window []domain.Reading) ([]domain.Reading, *domain.PriorityConflict) {
+	if len(window) < 2 {
+		return window, nil
+	}
+	top, low := window[0].Priority, window[0].Priority
+	for _, r := range window[1:] {
+		top = max(top, r.Priority)
+		low = min(low, r.Priority)
+	}
+	if top == low {
+		return window, nil
+	}
+
+	winners := make([]domain.Reading, 0, len(window))
+	conflict := &domain.PriorityConflict{WinnerPriority: top, LoserPriority: low}
+	for _, r := range window {
+		if r.Priority == top {
+			winners = append(winners, r)
+			continue
+		}
+		conflict.Overridden++
+		// 被覆盖方中取最高的优先级 (即差一点胜出的来源)
+		if conflict.LoserPriority < r.Priority {
+			conflict.LoserPriority = r.Priority
+		}
+	}
+	return winners, conflict
+}
+
+// sortConflicts 按 (DeviceID, Metric, Timestamp) 排序，使输出与 worker 完成顺序无关
+func sortConflicts(conflicts []domain.PriorityConflict) {
+	slices.SortFunc(conflicts, func(a, b domain.PriorityConflict) int {
+		if c := strings.Compare(a.DeviceID, b.DeviceID); c != 0 {
+			return c
+		}
+		if c := strings.Compare(string(a.Metric), string(b.Metric)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Timestamp.UnixNano(), b.Timestamp.UnixNano())
+	})
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// PseudonymPrefix HMACPseudonymizer 生成的假名前缀
+const PseudonymPrefix = "px-"
+
+// minPseudonymKeyBytes HMAC 密钥的最小长度
+const minPseudonymKeyBytes = 16
+
+// HMACPseudonymizer 以带密钥的 HMAC-SHA256 生成设备假名 (实现 ports.Pseudonymizer 与 ports.PseudonymResolver)
+// 假名为 "px-" + 摘要前 16 字节的十六进制，由 (租户, 设备ID) 与密钥唯一确定: 同一设备始终得到同一假名，
+// 没有密钥无法由假名反推或验证设备ID。映射写入 PseudonymRepository，只能经授权的 Reveal 还原。
+type HMACPseudonymizer struct {
+	key   []byte
+	repo  ports.PseudonymRepository
+	authz ports.Authorizer
+	known sync.Map // 已保存映射的 pseudonymKey -> 假名
+}
+
+// pseudonymKey 假名缓存的键: 租户与设备ID分别比较，任何取值组合都不会相互冲突
+type pseudonymKey struct {
+	tenantID, deviceID string
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.Pseudonymizer     = (*HMACPseudonymizer)(nil)
+	_ ports.PseudonymResolver = (*HMACPseudonymizer)(nil)
+)
+
+// NewHMACPseudonymizer 创建假名化器；key 至少 16 字节且须妥善保管 (更换密钥会使全部假名变化)
+// authz 为 nil 时 Reveal 一律拒绝
+func NewHMACPseudonymizer(key []byte, repo ports.PseudonymRepository, authz ports.Authorizer) (*HMACPseudonymizer, error) {
+	if len(key) < minPseudonymKeyBytes {
+		return nil, fmt.Errorf("pseudonym key must be at least %d bytes", minPseudonymKeyBytes)
+	}
+	if repo == nil {
+		return nil, errors.New("pseudonym repository is required")
+	}
+	return &HMACPseudonymizer{key: append([]byte(nil), key...), repo: repo, authz: authz}, nil
+}
+
+// Pseudonymize 返回 ctx 租户下设备的假名，首次出现时保存映射
+func (p *HMACPseudonymizer) Pseudonymize(ctx context.Context, deviceID string) (string, error) {
+	tenantID := domain.TenantFromContext(ctx)
+	key := pseudonymKey{tenantID: tenantID, deviceID: deviceID}
+	if cached, ok := p.known.Load(key); ok {
+		return cached.(string), nil
+	}
+	mac := hmac.New(sha256.New, p.key)
+	// 租户与设备ID之间用 0 字节分隔，避免 ("a", "b/c") 与 ("a/b", "c") 得到相同输入
+	mac.Write([]byte(tenantID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(deviceID))
+	pseudonym := PseudonymPrefix + hex.EncodeToString(mac.Sum(nil)[:16])
+
+	if err := p.repo.Save(ctx, pseudonym, deviceID); err != nil {
+		return "", fmt.Errorf("save pseudonym for device: %w", err)
+	}
+	p.known.Store(key, pseudonym)
+	return pseudonym, nil
+}
+
+// Reveal 经 Authorizer (OperationDeviceReveal) 授权后由假名还原 ctx 租户下的设备ID
+func (p *HMACPseudonymizer) Reveal(ctx context.Context, pseudonym, operator string) (string, error) {
+	if p.authz == nil {
+		return "", fmt.Errorf("%s %s: no authorizer configured: %w", ports.OperationDeviceReveal, pseudonym, ports.ErrForbidden)
+	}
+	if err := authorize(ctx, p.authz, ports.OperationDeviceReveal, operator, pseudonym); err != nil {
+		return "", err
+	}
+	deviceID, err := p.repo.Lookup(ctx, pseudonym)
+	if err != nil {
+		return "", fmt.Errorf("reveal %s: %w", pseudonym, err)
+	}
+	return deviceID, nil
+}
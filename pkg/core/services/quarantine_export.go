@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// ExportFormat 隔离记录导出格式
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"  // 带表头的 CSV (UTF-8)，可直接用 Excel 打开
+	ExportFormatJSON ExportFormat = "json" // JSON 数组，每个元素一条记录
+)
+
+// quarantineExportPageSize 导出时每次从仓储读取的记录数
+const quarantineExportPageSize = 500
+
+// quarantineExportColumns CSV 表头 (与 quarantineExportRow 的 JSON 字段一致)
+// corrected_value 列留给数据管理员离线填写修正值
+var quarantineExportColumns = []string{
+	"id", "device_id", "device_type", "metric", "timestamp", "value",
+	"code", "rule_id", "reason", "status", "created_at", "batch_id",
+	"trace_id", "ingested_by", "operator", "note", "corrected_value",
+}
+
+// quarantineExportRow 导出的一条扁平化隔离记录
+type quarantineExportRow struct {
+	ID             string   `json:"id"`
+	DeviceID       string   `json:"device_id"`
+	DeviceType     string   `json:"device_type"`
+	Metric         string   `json:"metric"`
+	Timestamp      string   `json:"timestamp"`
+	Value          *float64 `json:"value"` // 缺失值 (NaN) 导出为 null / 空单元格
+	Code           string   `json:"code"`
+	RuleID         string   `json:"rule_id"`
+	Reason         string   `json:"reason"`
+	Status         string   `json:"status"`
+	CreatedAt      string   `json:"created_at"`
+	BatchID        string   `json:"batch_id"`
+	TraceID        string   `json:"trace_id"`    // 导入调用的 TraceID
+	IngestedBy     string   `json:"ingested_by"` // 导入操作人 (Operator 为隔离记录的处理人)
+	Operator       string   `json:"operator"`
+	Note           string   `json:"note"`
+	CorrectedValue *float64 `json:"corrected_value"`
+}
+
+func newQuarantineExportRow(q domain.QuarantineReading) quarantineExportRow {
+	row := quarantineExportRow{
+		ID:             q.ID,
+		DeviceID:       q.Reading.DeviceInfo.ID,
+		DeviceType:     string(q.Reading.DeviceInfo.Type),
+		Metric:         string(q.Reading.Metric),
+		Timestamp:      formatExportTime(q.Reading.Timestamp),
+		Code:           string(q.Code),
+		RuleID:         q.RuleID,
+		Reason:         q.Reason,
+		Status:         string(q.Status),
+		CreatedAt:      formatExportTime(q.CreatedAt),
+		BatchID:        q.BatchID,
+		Operator:       q.Operator,
+		Note:           q.Note,
+		CorrectedValue: q.CorrectedValue,
+	}
+	if p := q.Provenance; p != nil {
+		row.TraceID, row.IngestedBy = p.TraceID, p.Operator
+	}
+	if v := q.Reading.Value; !math.IsNaN(v) && !math.IsInf(v, 0) {
+		row.Value = &v
+	}
+	return row
+}
+
+func (r quarantineExportRow) record() []string {
+	return []string{
+		r.ID, r.DeviceID, r.DeviceType, r.Metric, r.Timestamp, formatExportValue(r.Value),
+		r.Code, r.RuleID, r.Reason, r.Status, r.CreatedAt, r.BatchID,
+		r.TraceID, r.IngestedBy, r.Operator, r.Note, formatExportValue(r.CorrectedValue),
+	}
+}
+
+// ExportQuarantine 将满足 filter 的隔离记录按 format 流式写入 w，返回写出的记录数
+// 记录按仓储的 Find 顺序分页读取 (filter.Offset/Limit 仍然生效)，内存占用与结果集大小无关。
+// 时间以 UTC RFC3339 格式输出；写出部分记录后失败时返回已写出的条数与错误。
+func ExportQuarantine(ctx context.Context, repo ports.QuarantineRepository, filter ports.QuarantineFilter, format ExportFormat, w io.Writer) (int, error) {
+	var enc quarantineEncoder
+	switch format {
+	case ExportFormatCSV:
+		enc = &csvQuarantineEncoder{w: csv.NewWriter(w)}
+	case ExportFormatJSON:
+		enc = &jsonQuarantineEncoder{w: w}
+	default:
+		return 0, fmt.Errorf("export quarantine: unsupported format %q", format)
+	}
+	if err := enc.begin(); err != nil {
+		return 0, err
+	}
+
+	remaining, offset, written := filter.Limit, max(filter.Offset, 0), 0
+	for remaining <= 0 || written < remaining {
+		page := filter
+		page.Offset, page.Limit = offset, quarantineExportPageSize
+		if remaining > 0 {
+			page.Limit = min(page.Limit, remaining-written)
+		}
+		records, err := repo.Find(ctx, page)
+		if err != nil {
+			return written, fmt.Errorf("export quarantine: %w", err)
+		}
+		for _, q := range records {
+			if err := enc.write(newQuarantineExportRow(q)); err != nil {
+				return written, fmt.Errorf("export quarantine record %s: %w", q.ID, err)
+			}
+			written++
+		}
+		if len(records) < page.Limit {
+			break
+		}
+		offset += len(records)
+	}
+	if err := enc.end(); err != nil {
+		return written, fmt.Errorf("export quarantine: %w", err)
+	}
+	return written, nil
+}
+
+// quarantineEncoder 导出格式的编码器
+type quarantineEncoder interface {
+	begin() error
+	write(row quarantineExportRow) error
+	end() error
+}
+
+type csvQuarantineEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvQuarantineEncoder) begin() error { return e.w.Write(quarantineExportColumns) }
+
+func (e *csvQuarantineEncoder) write(row quarantineExportRow) error { return e.w.Write(row.record()) }
+
+func (e *csvQuarantineEncoder) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonQuarantineEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonQuarantineEncoder) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonQuarantineEncoder) write(row quarantineExportRow) error {
+	body, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	sep := "\n"
+	if e.count > 0 {
+		sep = ",\n"
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	_, err = e.w.Write(body)
+	return err
+}
+
+func (e *jsonQuarantineEncoder) end() error {
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func formatExportValue(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
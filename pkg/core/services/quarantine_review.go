@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// ErrQuarantineRepositoryNotConfigured 未配置隔离区仓储
+var ErrQuarantineRepositoryNotConfigured = errors.New("quarantine repository not configured")
+
+// QuarantineReevaluation 隔离区重新评估的结果
+type QuarantineReevaluation struct {
+	Resolved     []domain.QuarantineReading    // 按当前规则已通过、已重新入库并标记为 RESOLVED 的记录
+	StillPending int                           // 仍未通过的记录数 (状态不变)
+	Result       *domain.StandardizationResult // 重新入库产生的标准读数
+}
+
+// ReevaluateQuarantine 以当前规则链重新评估某设备类型下的 PENDING 隔离记录
+// 现在能通过的读数会被重新标准化并持久化，对应记录标记为 RESOLVED；
+// 仍不通过的记录保持 PENDING，不会产生新的隔离记录。用于放宽过严的阈值后闭环处理隔离区。
+func (s *CoreStandardizer) ReevaluateQuarantine(ctx context.Context, deviceType domain.DeviceType, limit int) (*QuarantineReevaluation, error) {
+	if s.quarantineRepo == nil {
+		return nil, ErrQuarantineRepositoryNotConfigured
+	}
+	if err := s.authorize(ctx, ports.OperationQuarantineResolve, operatorOf(ctx), string(deviceType)); err != nil {
+		return nil, err
+	}
+
+	records, err := s.quarantineRepo.FindPendingByDeviceType(ctx, deviceType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("load pending quarantine for %s failed: %w", deviceType, err)
+	}
+	if len(records) == 0 {
+		return &QuarantineReevaluation{Result: &domain.StandardizationResult{}}, nil
+	}
+
+	// 确保使用最新规则
+	s.InvalidateRules(deviceType)
+	rules, err := s.rulesFor(ctx, deviceType)
+	if err != nil {
+		return nil, fmt.Errorf("load rules for %s failed: %w", deviceType, err)
+	}
+
+	readings := make([]domain.Reading, len(records))
+	for i, q := range records {
+		readings[i] = q.Reading
+	}
+	sanitizer := newChainSanitizer(s.ruleMetrics, s.duplicatePolicy, rules...)
+	clean, _ := sanitizer.cleanSeeded(readings, s.loadHistory(ctx, readings))
+	clean, _ = s.guardScale(clean)
+
+	result, err := s.alignAndPersist(ctx, clean, processOptions{strategy: ports.UpsertStrategyHighPriorityWins})
+	if err != nil {
+		return nil, err
+	}
+
+	type readingKey struct {
+		channel  string
+		unixNano int64
+	}
+	passed := make(map[readingKey]bool, len(clean))
+	for _, r := range clean {
+		passed[readingKey{r.ChannelID(), r.Timestamp.UnixNano()}] = true
+	}
+
+	out := &QuarantineReevaluation{Result: result}
+	now := time.Now()
+	for _, q := range records {
+		// 同一时间戳的多条记录只要有一条重新入库即全部视为已解决
+		if !passed[readingKey{q.Reading.ChannelID(), q.Reading.Timestamp.UnixNano()}] {
+			out.StillPending++
+			continue
+		}
+		// 对齐失败的设备保持 PENDING，留待下次评估
+		if result.DeviceErrors[q.Reading.ChannelID()] != nil {
+			out.StillPending++
+			continue
+		}
+		q.Status = domain.QuarantineStatusResolved
+		q.UpdatedAt = now
+		// 评估期间已被人工修正或忽略的记录保持其处理结果
+		if err := s.quarantineRepo.UpdateStatus(ctx, q, domain.QuarantineStatusPending); errors.Is(err, ports.ErrQuarantineStatusChanged) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("resolve quarantine record %s failed: %w", q.ID, err)
+		}
+		out.Resolved = append(out.Resolved, q)
+	}
+	return out, nil
+}
+
+// ErrQuarantineNotPending 隔离记录已被处理 (RESOLVED / IGNORED)，不能再次处理
+var ErrQuarantineNotPending = errors.New("quarantine record is not pending")
+
+// Resolve 以人工修正值重新入库一条 PENDING 隔离记录，并将其标记为 RESOLVED
+// 修正后的读数以 IngestStrategyCalibration (优先级 1000) 重新对齐并持久化，覆盖同槽位的已有数据；
+// 人工修正视为最终裁决，不再经过清洗规则 (仍做定点换算溢出检查)。记录不存在时返回 ports.ErrNotFound。
+// 记录先由 PENDING 标记为 RESOLVED (比较并设置，并发的修正与忽略只有一个成功) 再重新入库，失败时恢复为 PENDING。
+// 配置了 WithCalibrationApproval 且 1 条读数即需审批 (threshold 为 0) 时，修正值暂存待审批而不写入，
+// 记录仍标记为 RESOLVED 并在 Note 中记录审批 ID，结果的 PendingApproval 为审批 ID。
+func (s *CoreStandardizer) Resolve(ctx context.Context, id string, correctedValue float64, operator string) (*domain.StandardizationResult, error) {
+	if err := s.authorize(ctx, ports.OperationQuarantineResolve, operator, id); err != nil {
+		return nil, err
+	}
+	q, err := s.pendingQuarantine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	info, _ := domain.FromContext(ctx)
+	info.Strategy, info.Operator = domain.IngestStrategyCalibration, operator
+	ctx = domain.NewContext(ctx, info)
+
+	corrected := q.Reading
+	corrected.Value = correctedValue
+	corrected.Priority = domain.IngestStrategyCalibration.GetPriority()
+	corrected.Confidence = 1
+	corrected.MarkCorrected(fmt.Sprintf("quarantine %s resolved by %s", q.ID, operator))
+	if _, overflow := s.guardScale([]domain.Reading{corrected}); len(overflow) > 0 {
+		return nil, fmt.Errorf("resolve quarantine record %s: %s", q.ID, overflow[0].Reason)
+	}
+
+	pending := q
+	q.Status = domain.QuarantineStatusResolved
+	q.Operator = operator
+	q.CorrectedValue = &correctedValue
+	q.UpdatedAt = time.Now()
+	if err := s.transitionQuarantine(ctx, q, domain.QuarantineStatusPending); err != nil {
+		return nil, err
+	}
+	// restore 处理失败时把记录恢复为 PENDING，可再次处理
+	restore := func(err error) error {
+		if restoreErr := s.transitionQuarantine(ctx, pending, domain.QuarantineStatusResolved); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf("restore quarantine record %s to pending: %w", q.ID, restoreErr))
+		}
+		return err
+	}
+
+	if !s.needsApproval(ctx, 1) {
+		result, err := s.applyCorrections(ctx, []domain.Reading{corrected})
+		if err != nil {
+			return nil, restore(fmt.Errorf("resolve quarantine record %s: %w", q.ID, err))
+		}
+		return result, nil
+	}
+	result, err := s.submitForApproval(ctx, []domain.Reading{corrected}, q.ID)
+	if err != nil {
+		return nil, restore(fmt.Errorf("resolve quarantine record %s: %w", q.ID, err))
+	}
+	q.Note = fmt.Sprintf("correction awaiting approval %s", result.PendingApproval)
+	if err := s.transitionQuarantine(ctx, q, domain.QuarantineStatusResolved); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyCorrections 不经清洗规则对齐并持久化人工修正的读数 (Resolve 与其审批通过后共用)
+func (s *CoreStandardizer) applyCorrections(ctx context.Context, corrected []domain.Reading) (*domain.StandardizationResult, error) {
+	if _, overflow := s.guardScale(corrected); len(overflow) > 0 {
+		return nil, errors.New(overflow[0].Reason)
+	}
+	result, err := s.alignAndPersist(ctx, corrected, processOptions{strategy: ports.UpsertStrategyHighPriorityWins})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range corrected {
+		if devErr := result.DeviceErrors[r.ChannelID()]; devErr != nil {
+			return nil, devErr
+		}
+	}
+	return result, nil
+}
+
+// Ignore 将一条 PENDING 隔离记录标记为 IGNORED (确认无效，不重新入库)
+// 处理人取自 ctx 中 IngestContext 的 Operator (如有)。记录不存在时返回 ports.ErrNotFound，
+// 已被并发的处理改变时返回 ErrQuarantineNotPending。
+func (s *CoreStandardizer) Ignore(ctx context.Context, id string, reason string) error {
+	if err := s.authorize(ctx, ports.OperationQuarantineIgnore, operatorOf(ctx), id); err != nil {
+		return err
+	}
+	q, err := s.pendingQuarantine(ctx, id)
+	if err != nil {
+		return err
+	}
+	if info, ok := domain.FromContext(ctx); ok {
+		q.Operator = info.Operator
+	}
+	q.Status = domain.QuarantineStatusIgnored
+	q.Note = reason
+	q.UpdatedAt = time.Now()
+	return s.transitionQuarantine(ctx, q, domain.QuarantineStatusPending)
+}
+
+// reopenQuarantine 把修正被驳回的隔离记录由 RESOLVED 恢复为 PENDING，清除处理记录
+func (s *CoreStandardizer) reopenQuarantine(ctx context.Context, id, note string) error {
+	if s.quarantineRepo == nil {
+		return ErrQuarantineRepositoryNotConfigured
+	}
+	found, err := s.quarantineRepo.Find(ctx, ports.QuarantineFilter{ID: id, Limit: 1})
+	if err != nil {
+		return fmt.Errorf("load quarantine record %s failed: %w", id, err)
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("quarantine record %s: %w", id, ports.ErrNotFound)
+	}
+	q := found[0]
+	q.Status, q.Operator, q.CorrectedValue, q.Note, q.UpdatedAt = domain.QuarantineStatusPending, "", nil, note, time.Now()
+	return s.transitionQuarantine(ctx, q, domain.QuarantineStatusResolved)
+}
+
+// transitionQuarantine 仅当记录当前状态为 expected 时保存 q；状态已被并发的处理改变时返回 ErrQuarantineNotPending
+func (s *CoreStandardizer) transitionQuarantine(ctx context.Context, q domain.QuarantineReading, expected domain.QuarantineStatus) error {
+	err := s.quarantineRepo.UpdateStatus(ctx, q, expected)
+	if errors.Is(err, ports.ErrQuarantineStatusChanged) {
+		return fmt.Errorf("update quarantine record %s: %w: %w", q.ID, ErrQuarantineNotPending, err)
+	}
+	if err != nil {
+		return fmt.Errorf("update quarantine record %s: %w", q.ID, err)
+	}
+	return nil
+}
+
+// pendingQuarantine 按 ID 加载一条 PENDING 隔离记录
+func (s *CoreStandardizer) pendingQuarantine(ctx context.Context, id string) (domain.QuarantineReading, error) {
+	if s.quarantineRepo == nil {
+		return domain.QuarantineReading{}, ErrQuarantineRepositoryNotConfigured
+	}
+	found, err := s.quarantineRepo.Find(ctx, ports.QuarantineFilter{ID: id, Limit: 1})
+	if err != nil {
+		return domain.QuarantineReading{}, fmt.Errorf("load quarantine record %s failed: %w", id, err)
+	}
+	if len(found) == 0 {
+		return domain.QuarantineReading{}, fmt.Errorf("quarantine record %s: %w", id, ports.ErrNotFound)
+	}
+	if found[0].Status != domain.QuarantineStatusPending {
+		return domain.QuarantineReading{}, fmt.Errorf("quarantine record %s is %s: %w", id, found[0].Status, ErrQuarantineNotPending)
+	}
+	return found[0], nil
+}
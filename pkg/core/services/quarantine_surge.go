@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// NotificationQuarantineSurge 隔离激增通知的类别
+const NotificationQuarantineSurge = "QUARANTINE_SURGE"
+
+// QuarantineSurgeMonitor 隔离激增监控
+// 订阅 ReadingQuarantined 与 BatchStandardized 事件 (见 Subscribe)，在滑动窗口内统计隔离率:
+// 设备的隔离率 = 该设备被隔离的读数 / 该设备进入管道的读数，规则的隔离率 = 该规则隔离的读数 / 全部进入管道的读数。
+// 隔离率与隔离条数同时达到阈值时发送通知，传感器失效在几分钟内即可发现，不必等到月底对账。
+//
+// 每个批次标准化完成 (BatchStandardized) 后评估一次；同一设备或规则在冷却期内只通知一次。
+// 通知在发布方 goroutine 中同步发送，发送失败的错误由事件发布方记录日志。
+type QuarantineSurgeMonitor struct {
+	notifier ports.Notifier
+	window   time.Duration
+	cooldown time.Duration
+	rate     float64
+	minCount int
+	now      func() time.Time
+
+	mu        sync.Mutex
+	samples   []surgeSample
+	lastAlert map[string]time.Time // 通知键 ("device:<ID>" / "rule:<ID>") -> 上次通知时间
+}
+
+// surgeSample 窗口内的一条统计样本 (一个批次中一台设备的输入数，或一条隔离记录)
+type surgeSample struct {
+	at          time.Time
+	deviceID    string
+	ruleID      string
+	inputs      int
+	quarantined int
+}
+
+// 编译期检查接口实现
+var _ ports.EventPublisher = (*QuarantineSurgeMonitor)(nil)
+
+// SurgeOption 定义隔离激增监控配置选项 (Functional Option Pattern)
+type SurgeOption func(*QuarantineSurgeMonitor)
+
+// WithSurgeWindow 设置统计窗口 (默认 15 分钟)
+func WithSurgeWindow(window time.Duration) SurgeOption {
+	return func(m *QuarantineSurgeMonitor) {
+		if window > 0 {
+			m.window = window
+		}
+	}
+}
+
+// WithSurgeThreshold 设置通知阈值: 隔离率不低于 rate (0-1，默认 0.5) 且隔离条数不少于 minQuarantined (默认 10)
+// 隔离条数下限避免零星的几条坏数据在低频设备上触发通知
+func WithSurgeThreshold(rate float64, minQuarantined int) SurgeOption {
+	return func(m *QuarantineSurgeMonitor) {
+		if rate > 0 && rate <= 1 {
+			m.rate = rate
+		}
+		if minQuarantined > 0 {
+			m.minCount = minQuarantined
+		}
+	}
+}
+
+// WithSurgeCooldown 设置同一设备或规则两次通知的最小间隔 (默认 1 小时)
+func WithSurgeCooldown(cooldown time.Duration) SurgeOption {
+	return func(m *QuarantineSurgeMonitor) {
+		if cooldown > 0 {
+			m.cooldown = cooldown
+		}
+	}
+}
+
+// WithSurgeClock 设置时钟 (默认 time.Now)
+func WithSurgeClock(now func() time.Time) SurgeOption {
+	return func(m *QuarantineSurgeMonitor) {
+		if now != nil {
+			m.now = now
+		}
+	}
+}
+
+// NewQuarantineSurgeMonitor 创建隔离激增监控 (多个通知目标可组合为一个 Notifier)
+func NewQuarantineSurgeMonitor(notifier ports.Notifier, opts ...SurgeOption) (*QuarantineSurgeMonitor, error) {
+	if notifier == nil {
+		return nil, errors.New("surge notifier is nil")
+	}
+	m := &QuarantineSurgeMonitor{
+		notifier:  notifier,
+		window:    15 * time.Minute,
+		cooldown:  time.Hour,
+		rate:      0.5,
+		minCount:  10,
+		now:       time.Now,
+		lastAlert: make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Subscribe 订阅事件总线上的隔离与批次事件，返回取消订阅函数
+func (m *QuarantineSurgeMonitor) Subscribe(bus ports.EventBus) (unsubscribe func()) {
+	return bus.Subscribe(m.Publish, ports.EventReadingQuarantined, ports.EventBatchStandardized)
+}
+
+// Publish 实现 ports.EventPublisher (也可直接通过 WithEventPublisher 注入，其他事件忽略)
+func (m *QuarantineSurgeMonitor) Publish(ctx context.Context, event ports.Event) error {
+	switch e := event.(type) {
+	case ports.ReadingQuarantined:
+		m.mu.Lock()
+		m.samples = append(m.samples, surgeSample{at: m.now(), deviceID: e.Record.Reading.DeviceInfo.ID, ruleID: e.Record.RuleID, quarantined: 1})
+		m.mu.Unlock()
+	case ports.BatchStandardized:
+		m.mu.Lock()
+		now := m.now()
+		for id, n := range e.DeviceInputs {
+			m.samples = append(m.samples, surgeSample{at: now, deviceID: id, inputs: n})
+		}
+		alerts := m.evaluate(now)
+		m.mu.Unlock()
+
+		var errs []error
+		for _, n := range alerts {
+			if err := m.notifier.Notify(ctx, n); err != nil {
+				errs = append(errs, fmt.Errorf("notify %s: %w", n.Title, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// surgeCount 一个通知键在窗口内的统计
+type surgeCount struct {
+	inputs, quarantined int
+}
+
+// evaluate 清理窗口外的样本并返回需要发送的通知 (调用方持有 m.mu)
+func (m *QuarantineSurgeMonitor) evaluate(now time.Time) []ports.Notification {
+	cutoff := now.Add(-m.window)
+	m.samples = slices.DeleteFunc(m.samples, func(s surgeSample) bool { return s.at.Before(cutoff) })
+	maps.DeleteFunc(m.lastAlert, func(_ string, at time.Time) bool { return now.Sub(at) >= m.cooldown })
+
+	devices := make(map[string]surgeCount)
+	rules := make(map[string]surgeCount)
+	total := 0
+	for _, s := range m.samples {
+		c := devices[s.deviceID]
+		c.inputs += s.inputs
+		c.quarantined += s.quarantined
+		devices[s.deviceID] = c
+		total += s.inputs
+		if s.quarantined > 0 && s.ruleID != "" {
+			r := rules[s.ruleID]
+			r.quarantined += s.quarantined
+			rules[s.ruleID] = r
+		}
+	}
+
+	var out []ports.Notification
+	for _, id := range slices.Sorted(maps.Keys(devices)) {
+		if n, ok := m.check(now, "device", id, devices[id]); ok {
+			out = append(out, n)
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(rules)) {
+		c := rules[id]
+		c.inputs = total
+		if n, ok := m.check(now, "rule", id, c); ok {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// check 判断一个通知键是否超过阈值且不在冷却期内
+func (m *QuarantineSurgeMonitor) check(now time.Time, scope, id string, c surgeCount) (ports.Notification, bool) {
+	// 隔离事件可能先于同批次的输入计数到达，分母至少取隔离条数
+	rate := float64(c.quarantined) / float64(max(c.inputs, c.quarantined, 1))
+	if c.quarantined < m.minCount || rate < m.rate {
+		return ports.Notification{}, false
+	}
+	key := scope + ":" + id
+	if last, ok := m.lastAlert[key]; ok && now.Sub(last) < m.cooldown {
+		return ports.Notification{}, false
+	}
+	m.lastAlert[key] = now
+	return ports.Notification{
+		Kind:    NotificationQuarantineSurge,
+		Title:   fmt.Sprintf("Quarantine surge on %s %s", scope, id),
+		Message: fmt.Sprintf("%d of %d readings (%.1f%%) quarantined for %s %s in the last %s", c.quarantined, max(c.inputs, c.quarantined), rate*100, scope, id, m.window),
+		Labels: map[string]string{
+			"scope":       scope,
+			scope + "_id": id,
+			"quarantined": strconv.Itoa(c.quarantined),
+			"inputs":      strconv.Itoa(max(c.inputs, c.quarantined)),
+			"rate":        strconv.FormatFloat(rate, 'f', 4, 64),
+			"window":      m.window.String(),
+		},
+		OccurredAt: now,
+	}, true
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// QuarantineMode 定义隔离区数据的持久化方式
+type QuarantineMode string
+
+const (
+	// QuarantineAsync 有界异步队列 (默认): 后台 worker 逐条保存，队列满时阻塞调用方；
+	// 进程退出前应调用 Flush 或 Close，确保队列中的记录落盘
+	QuarantineAsync QuarantineMode = "ASYNC"
+	// QuarantineSync 同步保存: 保存失败时整个批次返回错误
+	QuarantineSync QuarantineMode = "SYNC"
+	// QuarantineCallback 同步交给回调处理 (不使用 QuarantineRepository)
+	QuarantineCallback QuarantineMode = "CALLBACK"
+)
+
+// DefaultQuarantineQueueSize 异步模式默认队列容量
+const DefaultQuarantineQueueSize = 1024
+
+// quarantineSaveTimeout 异步模式下单条记录的保存超时
+const quarantineSaveTimeout = 30 * time.Second
+
+// QuarantinePolicy 隔离区持久化策略
+type QuarantinePolicy struct {
+	Mode      QuarantineMode
+	QueueSize int                                                                 // ASYNC: 队列容量 (<= 0 使用默认值)
+	Callback  func(ctx context.Context, records []domain.QuarantineReading) error // CALLBACK: 处理函数
+}
+
+// DefaultQuarantinePolicy 返回默认策略 (有界异步队列)
+func DefaultQuarantinePolicy() QuarantinePolicy {
+	return QuarantinePolicy{Mode: QuarantineAsync, QueueSize: DefaultQuarantineQueueSize}
+}
+
+// quarantineWriter 按策略持久化隔离记录
+type quarantineWriter struct {
+	repo   ports.QuarantineRepository
+	policy QuarantinePolicy
+	events ports.EventPublisher // 可选: 保存成功后发出 QuarantineCreated
+	logger *slog.Logger         // 后台保存失败的日志输出 (nil 使用 slog.Default())
+
+	mu      sync.RWMutex
+	closed  bool
+	start   sync.Once
+	stop    sync.Once
+	queue   chan domain.QuarantineReading
+	pending inflight // 已入队未保存的记录数
+}
+
+func newQuarantineWriter(repo ports.QuarantineRepository, policy QuarantinePolicy) *quarantineWriter {
+	if policy.Mode == "" {
+		policy.Mode = QuarantineAsync
+	}
+	if policy.QueueSize <= 0 {
+		policy.QueueSize = DefaultQuarantineQueueSize
+	}
+	return &quarantineWriter{repo: repo, policy: policy}
+}
+
+// write 按策略保存一批隔离记录 (保存前为每条记录发出 ReadingQuarantined)
+// 未配置仓储 (或回调) 时为空操作；异步模式下只在入队被 ctx 取消时返回错误
+func (w *quarantineWriter) write(ctx context.Context, records []domain.QuarantineReading) error {
+	if len(records) == 0 {
+		return nil
+	}
+	provenance := provenanceOf(ctx)
+	for i := range records {
+		q := &records[i]
+		if q.TenantID == "" {
+			q.TenantID = q.Reading.DeviceInfo.TenantID
+		}
+		if q.Provenance == nil && provenance != nil {
+			p := *provenance
+			q.Provenance = &p
+		}
+		if q.BatchID == "" && q.Provenance != nil {
+			q.BatchID = q.Provenance.BatchID
+		}
+	}
+	if w.events != nil {
+		now := time.Now()
+		for _, q := range records {
+			publishEvent(ctx, w.logger, w.events, ports.ReadingQuarantined{Record: q, OccurredAt: now})
+		}
+	}
+
+	switch w.policy.Mode {
+	case QuarantineCallback:
+		if w.policy.Callback == nil {
+			return nil
+		}
+		if err := w.policy.Callback(ctx, records); err != nil {
+			return err
+		}
+		for _, q := range records {
+			w.created(ctx, q)
+		}
+		return nil
+	case QuarantineSync:
+		return w.saveAll(ctx, records)
+	}
+
+	if w.repo == nil {
+		return nil
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		// 已关闭后到达的记录退回同步保存，不再静默丢弃
+		return w.saveAll(ctx, records)
+	}
+
+	w.start.Do(w.run)
+	for _, q := range records {
+		w.pending.add(1)
+		select {
+		case w.queue <- q:
+		case <-ctx.Done():
+			w.pending.done()
+			return fmt.Errorf("enqueue quarantine record: %w", ctx.Err())
+		}
+	}
+	return nil
+}
+
+// saveAll 同步保存全部记录，遇到第一个错误即返回
+func (w *quarantineWriter) saveAll(ctx context.Context, records []domain.QuarantineReading) error {
+	if w.repo == nil {
+		return nil
+	}
+	for _, q := range records {
+		if err := w.repo.Save(ctx, q); err != nil {
+			return fmt.Errorf("save quarantine record %s@%s: %w",
+				q.Reading.DeviceInfo.ID, q.Reading.Timestamp.Format(time.RFC3339), err)
+		}
+		w.created(ctx, q)
+	}
+	return nil
+}
+
+// created 发出 QuarantineCreated 事件
+func (w *quarantineWriter) created(ctx context.Context, q domain.QuarantineReading) {
+	publishEvent(ctx, w.logger, w.events, ports.QuarantineCreated{Record: q, OccurredAt: time.Now()})
+}
+
+// run 启动异步保存 worker
+func (w *quarantineWriter) run() {
+	w.queue = make(chan domain.QuarantineReading, w.policy.QueueSize)
+	go func() {
+		for q := range w.queue {
+			// 使用带超时的上下文，避免无限阻塞
+			ctx, cancel := context.WithTimeout(context.Background(), quarantineSaveTimeout)
+			if err := w.repo.Save(ctx, q); err != nil {
+				loggerOr(w.logger).Error("failed to save quarantine reading",
+					"device_id", q.Reading.DeviceInfo.ID,
+					"timestamp", q.Reading.Timestamp,
+					"reason", q.Reason,
+					"error", err)
+			} else {
+				w.created(ctx, q)
+			}
+			cancel()
+			w.pending.done()
+		}
+	}()
+}
+
+// flush 等待异步队列中已入队的记录全部处理完毕
+func (w *quarantineWriter) flush(ctx context.Context) error {
+	if err := w.pending.wait(ctx); err != nil {
+		return fmt.Errorf("flush quarantine queue: %w", err)
+	}
+	return nil
+}
+
+// close 排空队列并停止后台 worker；之后的记录改为同步保存
+// ctx 到期时 worker 保持运行，可再次调用 close 继续等待
+func (w *quarantineWriter) close(ctx context.Context) error {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+
+	if err := w.flush(ctx); err != nil {
+		return err
+	}
+	w.stop.Do(func() {
+		if w.queue != nil {
+			close(w.queue)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// LearnedRangeRulePrefix 自动学习的 RANGE 规则ID前缀 (完整ID为 前缀 + 设备ID)
+const LearnedRangeRulePrefix = "auto-range:"
+
+// RangeLearner 范围阈值学习任务
+// 基于历史标准读数计算每台设备的分位数上下限 (默认 30 天 p0.1 ~ p99.9)，
+// 并通过 CleaningRuleRepository 写入/更新仅作用于该设备的 RANGE 规则，取代人工拍脑袋的阈值。
+type RangeLearner struct {
+	readings   ports.StandardReadingRepository
+	rules      ports.CleaningRuleRepository
+	window     time.Duration
+	lowerPct   float64
+	upperPct   float64
+	minSamples int
+	action     domain.RuleAction
+	priority   int
+	events     ports.EventPublisher
+	logger     *slog.Logger
+}
+
+// RangeLearnerOption 定义学习任务配置选项 (Functional Option Pattern)
+type RangeLearnerOption func(*RangeLearner)
+
+// WithLearningWindow 设置学习的历史窗口 (默认 30 天)
+func WithLearningWindow(window time.Duration) RangeLearnerOption {
+	return func(l *RangeLearner) {
+		if window > 0 {
+			l.window = window
+		}
+	}
+}
+
+// WithPercentiles 设置上下限使用的分位数 (0-100，默认 0.1 与 99.9)
+func WithPercentiles(lower, upper float64) RangeLearnerOption {
+	return func(l *RangeLearner) {
+		if lower >= 0 && upper <= 100 && lower < upper {
+			l.lowerPct, l.upperPct = lower, upper
+		}
+	}
+}
+
+// WithMinSamples 设置学习所需的最少样本数 (默认 96，即 15 分钟粒度下的一天)
+func WithMinSamples(n int) RangeLearnerOption {
+	return func(l *RangeLearner) {
+		if n > 0 {
+			l.minSamples = n
+		}
+	}
+}
+
+// WithLearnedRuleAction 设置新建规则的处理策略与优先级 (默认 REJECT, 0)
+// 已存在的规则保留其原有的 Action / Priority / Enabled 等人工配置
+func WithLearnedRuleAction(action domain.RuleAction, priority int) RangeLearnerOption {
+	return func(l *RangeLearner) {
+		l.action = action
+		l.priority = priority
+	}
+}
+
+// WithLearnerEventPublisher 设置变更事件输出: 规则写入成功后发出 RuleUpdated
+func WithLearnerEventPublisher(publisher ports.EventPublisher) RangeLearnerOption {
+	return func(l *RangeLearner) {
+		l.events = publisher
+	}
+}
+
+// WithLearnerLogger 设置日志输出 (默认使用 slog.Default())，用于记录事件发布失败
+func WithLearnerLogger(logger *slog.Logger) RangeLearnerOption {
+	return func(l *RangeLearner) {
+		l.logger = logger
+	}
+}
+
+// NewRangeLearner 创建范围阈值学习任务
+func NewRangeLearner(readings ports.StandardReadingRepository, rules ports.CleaningRuleRepository, opts ...RangeLearnerOption) *RangeLearner {
+	l := &RangeLearner{
+		readings:   readings,
+		rules:      rules,
+		window:     30 * 24 * time.Hour,
+		lowerPct:   0.1,
+		upperPct:   99.9,
+		minSamples: 96,
+		action:     domain.ActionReject,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Learn 为每台设备学习阈值并写入规则，返回已写入的规则
+// 样本不足的设备会被跳过；单台设备失败不影响其他设备，所有错误聚合返回。
+func (l *RangeLearner) Learn(ctx context.Context, devices []domain.DeviceInfo, now time.Time) ([]domain.CleaningRule, error) {
+	var saved []domain.CleaningRule
+	var errs []error
+
+	for _, dev := range devices {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		rule, ok, err := l.learnOne(ctx, dev, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("learn range for %s: %w", dev.ID, err))
+			continue
+		}
+		if ok {
+			saved = append(saved, rule)
+		}
+	}
+	return saved, errors.Join(errs...)
+}
+
+// learnOne 学习单台设备的阈值，样本不足时返回 ok=false
+func (l *RangeLearner) learnOne(ctx context.Context, dev domain.DeviceInfo, now time.Time) (domain.CleaningRule, bool, error) {
+	history, err := l.readings.FindRange(ctx, dev.ID, now.Add(-l.window), now)
+	if err != nil {
+		return domain.CleaningRule{}, false, err
+	}
+	if len(history) < l.minSamples {
+		return domain.CleaningRule{}, false, nil
+	}
+
+	values := make([]float64, 0, len(history))
+	for _, sr := range history {
+		values = append(values, sr.ValueDisplay)
+	}
+	sort.Float64s(values)
+
+	id := LearnedRangeRulePrefix + dev.ID
+	rule := domain.CleaningRule{
+		ID:         id,
+		DeviceType: dev.Type,
+		Type:       domain.RuleTypeRange,
+		Action:     l.action,
+		Enabled:    true,
+		Priority:   l.priority,
+		DeviceIDs:  []string{dev.ID},
+	}
+
+	existing, err := l.rules.GetByID(ctx, id)
+	switch {
+	case err == nil && existing != nil:
+		rule = *existing
+	case err != nil && !errors.Is(err, ports.ErrNotFound):
+		return domain.CleaningRule{}, false, err
+	}
+
+	rule.Parameters = map[string]any{
+		"min":        percentile(values, l.lowerPct),
+		"max":        percentile(values, l.upperPct),
+		"learned_at": now.UTC().Format(time.RFC3339),
+		"samples":    float64(len(values)),
+	}
+
+	if err := l.rules.Save(ctx, rule); err != nil {
+		return domain.CleaningRule{}, false, err
+	}
+	publishEvent(ctx, l.logger, l.events, ports.RuleUpdated{Rule: rule, Created: existing == nil, Source: "range-learner", OccurredAt: now})
+	return rule, true, nil
+}
+
+// percentile 计算已排序数据的分位数 (p 取值 0-100，线性插值)
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
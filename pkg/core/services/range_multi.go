@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// FindRangeMulti 获取多个设备在 [start, end] 内的标准读数，按设备分组
+// 仓储实现了 ports.StandardReadingMultiFinder 时使用其批量查询，否则退化为逐个设备调用 FindRange
+func FindRangeMulti(ctx context.Context, repo ports.StandardReadingRepository, deviceIDs []string, start, end time.Time) (map[string][]domain.StandardReading, error) {
+	if finder, ok := repo.(ports.StandardReadingMultiFinder); ok {
+		return finder.FindRangeMulti(ctx, deviceIDs, start, end)
+	}
+	out := make(map[string][]domain.StandardReading, len(deviceIDs))
+	for _, id := range deviceIDs {
+		if _, done := out[id]; done {
+			continue
+		}
+		found, err := repo.FindRange(ctx, id, start, end)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) > 0 {
+			out[id] = found
+		}
+	}
+	return out, nil
+}
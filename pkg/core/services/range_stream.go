@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// StreamRange 分页遍历设备在 [start, end] 内的标准读数，每取回一页调用一次 fn
+// 内存占用只与 pageSize 有关 (<= 0 使用 ports.DefaultPageSize)；fn 返回错误或 ctx 取消时停止遍历并返回该错误
+func StreamRange(ctx context.Context, pager ports.StandardReadingPager, deviceID string, start, end time.Time, pageSize int, fn func([]domain.StandardReading) error) error {
+	req := ports.PageRequest{Limit: pageSize}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := pager.FindRangePaged(ctx, deviceID, start, end, req)
+		if err != nil {
+			return err
+		}
+		if len(page.Readings) > 0 {
+			if err := fn(page.Readings); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		req.Cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DefaultReconcileTolerance 用量相对偏差的默认容忍度 (0.1%)
+const DefaultReconcileTolerance = 0.001
+
+// ReconcileQuery 原始归档与标准读数的对账条件
+type ReconcileQuery struct {
+	DeviceIDs  []string
+	Metric     domain.Metric  // 为空表示设备的默认通道
+	Resolution string         // 对账的标准读数分辨率 (如 "15m")
+	Start, End time.Time      // 时间区间 [Start, End)，按当地日历日切分
+	Location   *time.Location // 日切分与标准时间网格的时区 (为空表示 UTC)，需与标准化时一致
+	Anchor     time.Duration  // 标准时间网格的偏移 (见 WithGridAnchor)
+
+	// Cumulative 读数为累计值 (表底): 日用量取日内最后与最早读数之差；为 false 时读数为区间量，日用量为读数之和
+	Cumulative bool
+
+	// Tolerance 日用量的相对偏差容忍度 (如 0.001 表示 0.1%)，<= 0 时使用 DefaultReconcileTolerance
+	Tolerance float64
+}
+
+// Discrepancy 单台设备单日的对账差异
+type Discrepancy struct {
+	DeviceID string    `json:"device_id"`
+	Day      time.Time `json:"day"` // 当地零点
+
+	RawCount      int     `json:"raw_count"`      // 有效原始读数数 (不含缺失值)
+	RawSlots      int     `json:"raw_slots"`      // 原始读数覆盖的标准时间点数，即应有的标准读数数
+	StandardCount int     `json:"standard_count"` // 可用标准读数数 (不含 MISSING 与已撤回)
+	RawUsage      float64 `json:"raw_usage"`      // 按原始读数的校准系数换算后的用量
+	StandardUsage float64 `json:"standard_usage"`
+	Deviation     float64 `json:"deviation"` // |StandardUsage - RawUsage| / |RawUsage| (RawUsage 为 0 时为绝对差)
+
+	Issues []string `json:"issues"` // 人类可读的差异说明
+}
+
+// Reconcile 逐设备、逐日比较原始归档与标准读数的点数与用量，返回超出容忍度的差异 (按设备ID、日期排序)
+// 用于发现管道中的静默丢数: 原始读数已归档但对应的标准读数缺失，或标准用量与原始用量不符。
+// 被规则隔离或修正的读数同样会造成差异，对账结果应结合隔离区记录解读。
+func Reconcile(ctx context.Context, raw ports.RawReadingRepository, standard ports.StandardReadingRepository, q ReconcileQuery) ([]Discrepancy, error) {
+	if len(q.DeviceIDs) == 0 {
+		return nil, errors.New("reconcile query: device ids are required")
+	}
+	interval, ok := resolutionDuration(q.Resolution)
+	if !ok {
+		return nil, fmt.Errorf("reconcile query: invalid resolution %q", q.Resolution)
+	}
+	if !q.End.After(q.Start) {
+		return nil, fmt.Errorf("reconcile query: end %s must be after start %s", q.End.Format(time.RFC3339), q.Start.Format(time.RFC3339))
+	}
+	tolerance := q.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultReconcileTolerance
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	grid := domain.NewTimeGrid(interval, loc).WithAnchor(q.Anchor)
+
+	ids := slices.Compact(slices.Sorted(slices.Values(q.DeviceIDs)))
+	last := q.End.Add(-time.Nanosecond) // FindRange 为闭区间
+	byDevice, err := FindRangeMulti(ctx, standard, ids, q.Start, last)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile: %w", err)
+	}
+
+	var out []Discrepancy
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rawReadings, err := raw.FindRange(ctx, id, q.Start, last)
+		if err != nil {
+			return nil, fmt.Errorf("reconcile: load raw readings for %s: %w", id, err)
+		}
+		days := make(map[time.Time]*reconcileDay)
+		day := func(t time.Time) *reconcileDay {
+			start := domain.ReportPeriodDay.Start(t, loc)
+			d, ok := days[start]
+			if !ok {
+				d = &reconcileDay{slots: make(map[int64]bool)}
+				days[start] = d
+			}
+			return d
+		}
+		for _, r := range rawReadings {
+			if r.Metric != q.Metric || r.Quality == domain.QualityMissing || math.IsNaN(r.Value) {
+				continue
+			}
+			// 标准读数保存的是校准后的值，原始读数按归档时的校准系数换算后再比较
+			value, _ := calibrate(r)
+			d := day(r.Timestamp)
+			d.raw = append(d.raw, reconcilePoint{r.Timestamp, value})
+			d.slots[grid.Floor(r.Timestamp).UnixNano()] = true
+		}
+		for _, sr := range byDevice[id] {
+			if sr.Metric != q.Metric || sr.Resolution != q.Resolution ||
+				sr.Quality == domain.QualityMissing || sr.Quality == domain.QualityWithdrawn {
+				continue
+			}
+			d := day(sr.Timestamp)
+			d.standard = append(d.standard, reconcilePoint{sr.Timestamp, sr.ValueDisplay})
+		}
+
+		for _, start := range slices.SortedFunc(maps.Keys(days), time.Time.Compare) {
+			if d, ok := days[start].compare(q.Cumulative, tolerance); !ok {
+				d.DeviceID, d.Day = id, start
+				out = append(out, d)
+			}
+		}
+	}
+	return out, nil
+}
+
+// reconcilePoint 参与用量计算的一个读数
+type reconcilePoint struct {
+	ts    time.Time
+	value float64
+}
+
+// reconcileDay 一台设备一日的对账数据
+type reconcileDay struct {
+	raw      []reconcilePoint
+	standard []reconcilePoint
+	slots    map[int64]bool // 原始读数所在的标准时间点
+}
+
+// compare 比较点数与用量，无差异时返回 ok=true
+func (d *reconcileDay) compare(cumulative bool, tolerance float64) (Discrepancy, bool) {
+	rawUsage, stdUsage := reconcileUsage(d.raw, cumulative), reconcileUsage(d.standard, cumulative)
+	deviation := math.Abs(stdUsage - rawUsage)
+	if rawUsage != 0 {
+		deviation /= math.Abs(rawUsage)
+	}
+	out := Discrepancy{
+		RawCount: len(d.raw), RawSlots: len(d.slots), StandardCount: len(d.standard),
+		RawUsage: rawUsage, StandardUsage: stdUsage, Deviation: deviation,
+	}
+	if missing := out.RawSlots - out.StandardCount; missing > 0 {
+		out.Issues = append(out.Issues, fmt.Sprintf("%d standard readings missing for archived raw data", missing))
+	}
+	if deviation > tolerance {
+		out.Issues = append(out.Issues, fmt.Sprintf("usage %.6g deviates from raw %.6g by %.4g%%", stdUsage, rawUsage, deviation*100))
+	}
+	return out, len(out.Issues) == 0
+}
+
+// reconcileUsage 区间量为读数之和，累计量为最后与最早读数之差
+func reconcileUsage(points []reconcilePoint, cumulative bool) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	if !cumulative {
+		var sum float64
+		for _, p := range points {
+			sum += p.value
+		}
+		return sum
+	}
+	first := slices.MinFunc(points, func(a, b reconcilePoint) int { return a.ts.Compare(b.ts) })
+	last := slices.MaxFunc(points, func(a, b reconcilePoint) int { return a.ts.Compare(b.ts) })
+	return last.value - first.value
+}
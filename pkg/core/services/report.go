@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// CoreReportGenerator ports.ReportGenerator 的默认实现
+// 从仓储读取标准读数，在内存中按统计周期汇总；合计以定点整数计算 (各读数换算到区间内最大的精度因子)，避免浮点累加误差。
+// 缺失 (MISSING) 与已撤回 (WITHDRAWN) 的读数不参与统计。
+type CoreReportGenerator struct {
+	repo           ports.StandardReadingRepository
+	emissions      ports.EmissionFactorProvider
+	weather        ports.WeatherDataProvider
+	degreeDayBasis domain.DegreeDayBasis
+}
+
+// 编译期检查接口实现
+var _ ports.ReportGenerator = (*CoreReportGenerator)(nil)
+
+// ReportOption 定义报表生成服务配置选项 (Functional Option Pattern)
+type ReportOption func(*CoreReportGenerator)
+
+// WithEmissionFactors 配置排放因子来源，报表同时给出各周期的碳排放 (CO₂e)
+// 未配置时报表不含碳排放；静态或分时段的因子可使用 NewEmissionFactorTable
+func WithEmissionFactors(provider ports.EmissionFactorProvider) ReportOption {
+	return func(g *CoreReportGenerator) {
+		g.emissions = provider
+	}
+}
+
+// NewReportGenerator 创建报表生成服务
+func NewReportGenerator(repo ports.StandardReadingRepository, opts ...ReportOption) *CoreReportGenerator {
+	g := &CoreReportGenerator{repo: repo}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Generate 按统计周期汇总设备在 [q.Start, q.End) 内的标准读数 (按周期向外取整)
+func (g *CoreReportGenerator) Generate(ctx context.Context, q ports.ReportQuery) ([]domain.EnergyReport, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	if g.repo == nil {
+		return nil, errors.New("report repository is nil")
+	}
+	loc, err := reportLocation(q)
+	if err != nil {
+		return nil, err
+	}
+	start := q.Period.Start(q.Start, loc)
+	end := q.Period.Next(q.Period.Start(q.End.Add(-time.Nanosecond), loc), loc)
+
+	// FindRange 为闭区间: 累计值需要最后一个周期结束时刻的读数，区间量不含结束时刻
+	last := end.Add(-time.Nanosecond)
+	if q.Cumulative {
+		last = end
+	}
+	all, err := g.repo.FindRange(ctx, q.Device.ID, start, last)
+	if err != nil {
+		return nil, fmt.Errorf("report %s: %w", q.Device.ID, err)
+	}
+	readings := make([]domain.StandardReading, 0, len(all))
+	for _, sr := range all {
+		if sr.Metric != q.Metric || sr.Resolution != q.Resolution ||
+			sr.Quality == domain.QualityMissing || sr.Quality == domain.QualityWithdrawn {
+			continue
+		}
+		readings = append(readings, sr)
+	}
+	slices.SortStableFunc(readings, func(a, b domain.StandardReading) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	var reports []domain.EnergyReport
+	i := 0
+	for ps := start; ps.Before(end); ps = q.Period.Next(ps, loc) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pe := q.Period.Next(ps, loc)
+		for i < len(readings) && readings[i].Timestamp.Before(ps) {
+			i++
+		}
+		j := i
+		for j < len(readings) && (readings[j].Timestamp.Before(pe) || q.Cumulative && readings[j].Timestamp.Equal(pe)) {
+			j++
+		}
+		intervals, scale, ok, err := reportUsage(readings[i:j], q.Cumulative)
+		if err != nil {
+			return nil, fmt.Errorf("report %s at %s: %w", q.Device.ID, ps.Format(time.RFC3339), err)
+		}
+		if !ok {
+			continue
+		}
+		usage, err := totalUsage(intervals)
+		if err != nil {
+			return nil, fmt.Errorf("report %s at %s: %w", q.Device.ID, ps.Format(time.RFC3339), err)
+		}
+		carbon, err := g.carbon(ctx, q, intervals, scale)
+		if err != nil {
+			return nil, fmt.Errorf("report %s at %s: %w", q.Device.ID, ps.Format(time.RFC3339), err)
+		}
+		weather, err := g.weatherNormalization(ctx, q, ps, pe, usage, scale)
+		if err != nil {
+			return nil, fmt.Errorf("report %s at %s: %w", q.Device.ID, ps.Format(time.RFC3339), err)
+		}
+		reports = append(reports, domain.EnergyReport{
+			ID:          fmt.Sprintf("%s/%s/%s", domain.ChannelID(q.Device.ID, q.Metric), q.Period, ps.Format(time.RFC3339)),
+			DeviceID:    q.Device.ID,
+			DeviceModel: q.Device.Model,
+			DeviceType:  q.Device.Type,
+			Metric:      q.Metric,
+			Period:      q.Period,
+			StartTime:   ps,
+			EndTime:     pe,
+			TotalUsage:  float64(usage) / float64(scale),
+			UsageScaled: usage,
+			ScaleFactor: scale,
+			Carbon:      carbon,
+			Weather:     weather,
+		})
+	}
+	return reports, nil
+}
+
+// reportLocation 返回周期切分所用时区: 查询指定的时区 > 设备时区 > UTC
+func reportLocation(q ports.ReportQuery) (*time.Location, error) {
+	if q.Location != nil {
+		return q.Location, nil
+	}
+	if q.Device.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(q.Device.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("report %s: invalid device timezone: %w", q.Device.ID, err)
+	}
+	return loc, nil
+}
+
+// usageInterval 一段区间的用量 (换算到周期精度因子的定点值)
+type usageInterval struct {
+	at     time.Time // 区间开始时间 (时变排放因子按该时刻取值)
+	scaled int64
+}
+
+// reportUsage 把一个周期的读数换算为区间用量 (定点整数) 并返回其精度因子，读数不足时返回 ok=false
+// 区间量的每条读数即一段用量；累计值取相邻读数之差 (至少需要两条读数)
+func reportUsage(readings []domain.StandardReading, cumulative bool) ([]usageInterval, int, bool, error) {
+	if len(readings) == 0 || cumulative && len(readings) < 2 {
+		return nil, 0, false, nil
+	}
+	scale := 1
+	for _, sr := range readings {
+		if err := domain.ValidateScaleFactor(sr.ScaleFactor); err != nil {
+			return nil, 0, false, err
+		}
+		scale = max(scale, sr.ScaleFactor)
+	}
+	values := make([]int64, len(readings))
+	for i, sr := range readings {
+		v, err := rescale(sr, scale)
+		if err != nil {
+			return nil, 0, false, err
+		}
+		values[i] = v
+	}
+	if !cumulative {
+		out := make([]usageInterval, len(readings))
+		for i, sr := range readings {
+			out[i] = usageInterval{at: sr.Timestamp, scaled: values[i]}
+		}
+		return out, scale, true, nil
+	}
+	out := make([]usageInterval, 0, len(readings)-1)
+	for i := 1; i < len(values); i++ {
+		prev, cur := values[i-1], values[i]
+		if (prev < 0 && cur > math.MaxInt64+prev) || (prev > 0 && cur < math.MinInt64+prev) {
+			return nil, 0, false, fmt.Errorf("%w: usage %d - %d", domain.ErrScaleOverflow, cur, prev)
+		}
+		out = append(out, usageInterval{at: readings[i-1].Timestamp, scaled: cur - prev})
+	}
+	return out, scale, true, nil
+}
+
+// totalUsage 返回各区间用量之和
+func totalUsage(intervals []usageInterval) (int64, error) {
+	var total int64
+	for _, u := range intervals {
+		v := u.scaled
+		if (v > 0 && total > math.MaxInt64-v) || (v < 0 && total < math.MinInt64-v) {
+			return 0, fmt.Errorf("%w: total usage", domain.ErrScaleOverflow)
+		}
+		total += v
+	}
+	return total, nil
+}
+
+// rescale 把标准读数的定点值换算到精度因子 scale (scale 为读数精度因子的整数倍)
+func rescale(sr domain.StandardReading, scale int) (int64, error) {
+	return rescaleValue(sr.ValueScaled, sr.ScaleFactor, scale)
+}
+
+// rescaleValue 把精度因子为 from 的定点值换算到精度因子 to (to 为 from 的整数倍)
+func rescaleValue(v int64, from, to int) (int64, error) {
+	mul := int64(to / from)
+	if v > math.MaxInt64/mul || v < math.MinInt64/mul {
+		return 0, fmt.Errorf("%w: %d x %d", domain.ErrScaleOverflow, v, mul)
+	}
+	return v * mul, nil
+}
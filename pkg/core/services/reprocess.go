@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// ErrRawRepositoryNotConfigured 未配置原始读数仓储，无法重新处理历史区间
+var ErrRawRepositoryNotConfigured = errors.New("raw reading repository not configured")
+
+// WithRawRepository 设置原始读数仓储: 每个批次在清洗之前先归档原始读数，ReprocessRange 从中取回历史数据
+// 归档失败时整个批次返回错误 (尚未写入任何标准读数)，以免出现无法追溯、无法重新处理的标准读数
+func WithRawRepository(repo ports.RawReadingRepository) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.rawRepo = repo
+	}
+}
+
+// ReprocessOptions 历史区间重新处理的参数
+type ReprocessOptions struct {
+	// Strategy 重新持久化时的冲突策略 (默认 HIGH_PRIORITY_WINS)
+	// 规则修正后需要覆盖已有标准读数时使用 LAST_WRITE_WINS
+	Strategy ports.UpsertStrategy
+
+	// IncludeQuarantined 同时取回该区间的隔离记录，按当前规则重新判定
+	// 原始读数中已存在相同时间戳时以原始读数为准
+	IncludeQuarantined bool
+}
+
+// ReprocessRange 使用当前规则重新处理设备在 [start, end] 内的历史数据
+// 从原始读数仓储 (及可选的隔离区) 取回数据，重新清洗、对齐，并按 opts.Strategy 重新持久化。
+// 只输出并持久化时间点落在 [start, end] 内的标准读数，区间外的已有数据不受影响。
+func (s *CoreStandardizer) ReprocessRange(ctx context.Context, deviceID string, start, end time.Time, opts ReprocessOptions) (*domain.StandardizationResult, error) {
+	if s.rawRepo == nil {
+		return nil, ErrRawRepositoryNotConfigured
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("invalid reprocess range: end %s before start %s", end, start)
+	}
+
+	raw, err := s.rawRepo.FindRange(ctx, deviceID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("load raw readings for %s failed: %w", deviceID, err)
+	}
+
+	if opts.IncludeQuarantined && s.quarantineRepo != nil {
+		records, err := s.quarantineRepo.FindByDevice(ctx, deviceID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("load quarantine records for %s failed: %w", deviceID, err)
+		}
+		raw = mergeQuarantined(raw, records)
+	}
+
+	if len(raw) == 0 {
+		return &domain.StandardizationResult{}, nil
+	}
+
+	// 确保使用最新规则
+	s.InvalidateRules(raw[0].DeviceInfo.Type)
+
+	strategy := opts.Strategy
+	if strategy == "" {
+		strategy = ports.UpsertStrategyHighPriorityWins
+	}
+	return s.process(ctx, raw, processOptions{
+		strategy: strategy,
+		keep: func(sr domain.StandardReading) bool {
+			return !sr.Timestamp.Before(start) && !sr.Timestamp.After(end)
+		},
+	})
+}
+
+// archiveRaw 在任何修改 (时钟偏移修正、预处理钩子、清洗) 之前归档原始读数
+// 归档副本补全批次策略优先级，重新处理时不再依赖当初的 IngestContext
+func (s *CoreStandardizer) archiveRaw(ctx context.Context, rawReadings []domain.Reading, opts processOptions) error {
+	if !opts.archive || s.rawRepo == nil || len(rawReadings) == 0 {
+		return nil
+	}
+	archived := slices.Clone(rawReadings)
+	applyIngestPriority(ctx, archived)
+	if err := s.rawRepo.Save(ctx, archived); err != nil {
+		return fmt.Errorf("archive raw readings failed: %w", err)
+	}
+	return nil
+}
+
+// mergeQuarantined 把隔离记录中的读数并入原始读数 (同一计量通道上时间戳已存在的跳过)
+func mergeQuarantined(raw []domain.Reading, records []domain.QuarantineReading) []domain.Reading {
+	type readingKey struct {
+		metric   domain.Metric
+		unixNano int64
+	}
+	seen := make(map[readingKey]bool, len(raw))
+	for _, r := range raw {
+		seen[readingKey{r.Metric, r.Timestamp.UnixNano()}] = true
+	}
+	for _, q := range records {
+		key := readingKey{q.Reading.Metric, q.Reading.Timestamp.UnixNano()}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		raw = append(raw, q.Reading)
+	}
+	return raw
+}
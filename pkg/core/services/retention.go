@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// RetentionRule 单条保留期规则: 分辨率为 Resolution 的标准读数只保留最近 MaxAge
+// 典型配置: 15m 保留 90 天、1h 保留 2 年、1d 永久保留 (不配置规则即可)；
+// 粗粒度读数由 WithResolutions 在标准化时一并生成，清理高分辨率数据后历史仍以粗粒度保留 (即 "rollup")
+type RetentionRule struct {
+	Resolution string        // 分辨率标签 (如 "15m")，为空表示全部分辨率
+	MaxAge     time.Duration // 保留时长 (必须 > 0)
+	DeviceID   string        // 可选: 仅作用于该设备 (为空表示全部设备)
+}
+
+// RetentionResult 一次清理的结果
+type RetentionResult struct {
+	Deleted map[string]int64 // 分辨率标签 -> 删除条数 (全部分辨率的规则以 "" 为键)
+}
+
+// Total 返回删除的总条数
+func (r RetentionResult) Total() int64 {
+	var n int64
+	for _, c := range r.Deleted {
+		n += c
+	}
+	return n
+}
+
+// RetentionRunner 标准读数保留期清理任务
+// 按规则调用仓储的 DeleteOlderThan，可单次执行 (RunOnce) 或按固定间隔常驻运行 (Run)。
+type RetentionRunner struct {
+	repo   ports.StandardReadingRetention
+	rules  []RetentionRule
+	logger *slog.Logger
+}
+
+// NewRetentionRunner 创建保留期清理任务，规则不合法时返回错误
+func NewRetentionRunner(repo ports.StandardReadingRetention, rules ...RetentionRule) (*RetentionRunner, error) {
+	if repo == nil {
+		return nil, errors.New("retention repository is nil")
+	}
+	for _, rule := range rules {
+		if rule.MaxAge <= 0 {
+			return nil, fmt.Errorf("invalid retention for resolution %q: max age %s must be positive", rule.Resolution, rule.MaxAge)
+		}
+		if rule.Resolution != "" && !validResolution(rule.Resolution) {
+			return nil, fmt.Errorf("invalid retention resolution %q", rule.Resolution)
+		}
+	}
+	return &RetentionRunner{repo: repo, rules: rules}, nil
+}
+
+// RunOnce 以 now 为基准执行全部规则
+// 单条规则失败不影响其他规则，所有错误聚合返回。
+func (r *RetentionRunner) RunOnce(ctx context.Context, now time.Time) (RetentionResult, error) {
+	result := RetentionResult{Deleted: make(map[string]int64, len(r.rules))}
+	var errs []error
+	for _, rule := range r.rules {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		n, err := r.repo.DeleteOlderThan(ctx, rule.DeviceID, rule.Resolution, now.Add(-rule.MaxAge))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("apply retention for resolution %q: %w", rule.Resolution, err))
+			continue
+		}
+		result.Deleted[rule.Resolution] += n
+	}
+	return result, errors.Join(errs...)
+}
+
+// SetLogger 设置 Run 的日志输出 (nil 使用 slog.Default())，须在 Run 之前调用
+func (r *RetentionRunner) SetLogger(logger *slog.Logger) {
+	r.logger = logger
+}
+
+// Run 启动时及之后每隔 interval 以当前时间清理一次，直到 ctx 取消 (见 runEvery)；清理失败只记录日志
+func (r *RetentionRunner) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid retention interval %s", interval)
+	}
+	return runEvery(ctx, interval, func(ctx context.Context) {
+		result, err := r.RunOnce(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			loggerOr(r.logger).Error("retention run failed", "error", err)
+		}
+		if n := result.Total(); n > 0 {
+			loggerOr(r.logger).Info("retention purged standard readings", "deleted", n)
+		}
+	})
+}
+
+// validResolution 判断分辨率标签是否可由 domain.ResolutionTag 生成
+func validResolution(tag string) bool {
+	_, ok := resolutionDuration(tag)
+	return ok
+}
+
+// resolutionDuration 解析由 domain.ResolutionTag 生成的分辨率标签，返回对应的时长
+func resolutionDuration(tag string) (time.Duration, bool) {
+	d, err := time.ParseDuration(tag)
+	if err != nil {
+		// 天级标签 (如 "1d") 不是合法的 time.Duration
+		var days int
+		if _, err := fmt.Sscanf(tag, "%dd", &days); err != nil || days <= 0 {
+			return 0, false
+		}
+		d = time.Duration(days) * 24 * time.Hour
+	}
+	return d, d > 0 && domain.ResolutionTag(d) == tag
+}
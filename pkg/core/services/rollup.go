@@ -0,0 +1,229 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// RollupRule 单条汇总规则: 把分辨率为 Source 的标准读数按 Target 周期汇总，写回分辨率为 domain.ResolutionTag(Target) 的标准读数
+// 典型配置: 15m -> 1h (SUM)、1h -> 1d (SUM)；规则按顺序执行，后一条规则可以以前一条规则的输出为源
+type RollupRule struct {
+	Source   string                 // 源分辨率标签 (如 "15m")
+	Target   time.Duration          // 目标周期 (如 time.Hour)，必须是源分辨率的整数倍
+	Mode     domain.AggregationMode // 聚合方式: SUM (默认，区间量) / MEAN (瞬时量) / MAX / MIN / FIRST / LAST
+	Lookback time.Duration          // RunOnce 每次重算的时间范围 (默认 2 个目标周期)，范围内迟到的数据会被重新汇总
+	Location *time.Location         // 周期切分所用时区 (为空表示 UTC)，日周期按当地日历切分
+}
+
+// grid 目标周期的时间网格
+func (r RollupRule) grid() domain.TimeGrid {
+	return domain.NewTimeGrid(r.Target, r.Location)
+}
+
+// RollupResult 一次汇总的结果
+type RollupResult struct {
+	Written map[string]int // 目标分辨率标签 -> 写入条数
+}
+
+// Total 返回写入的总条数
+func (r RollupResult) Total() int {
+	var n int
+	for _, c := range r.Written {
+		n += c
+	}
+	return n
+}
+
+// RollupRunner 标准读数汇总 (降采样) 任务
+// 从仓储读取源分辨率的标准读数，按目标周期聚合后写回仓储，使长时间范围的查询只需扫描粗粒度数据；
+// 配合 RetentionRunner 清理高分辨率数据后，历史仍以汇总结果保留。
+//
+// 汇总结果完全由源数据决定: 每次重算以 LAST_WRITE_WINS 覆盖目标分辨率的读数，重复执行结果不变，
+// 迟到的源数据在下一次覆盖其周期的重算中生效。因此目标分辨率应只由汇总任务写入 (不要同时用 WithResolutions 输出)。
+type RollupRunner struct {
+	repo   ports.StandardReadingRepository
+	rules  []RollupRule
+	logger *slog.Logger
+}
+
+// NewRollupRunner 创建汇总任务，规则不合法时返回错误
+func NewRollupRunner(repo ports.StandardReadingRepository, rules ...RollupRule) (*RollupRunner, error) {
+	if repo == nil {
+		return nil, errors.New("rollup repository is nil")
+	}
+	checked := make([]RollupRule, len(rules))
+	for i, rule := range rules {
+		source, ok := resolutionDuration(rule.Source)
+		if !ok {
+			return nil, fmt.Errorf("invalid rollup source resolution %q", rule.Source)
+		}
+		if rule.Target <= source || rule.Target%source != 0 {
+			return nil, fmt.Errorf("invalid rollup target %s for source %q: must be a larger multiple of the source", rule.Target, rule.Source)
+		}
+		switch rule.Mode {
+		case "":
+			rule.Mode = domain.AggregationSum
+		case domain.AggregationSum, domain.AggregationMean, domain.AggregationMax, domain.AggregationMin,
+			domain.AggregationFirst, domain.AggregationLast:
+		default:
+			return nil, fmt.Errorf("unsupported rollup mode %q", rule.Mode)
+		}
+		if rule.Lookback < 0 {
+			return nil, fmt.Errorf("invalid rollup lookback %s", rule.Lookback)
+		}
+		if rule.Lookback == 0 {
+			rule.Lookback = 2 * rule.Target
+		}
+		checked[i] = rule
+	}
+	return &RollupRunner{repo: repo, rules: checked}, nil
+}
+
+// RunOnce 以 now 为基准为每台设备执行全部规则: 重算 [now-Lookback, now) 内已结束的目标周期 (进行中的周期不汇总)
+// 单台设备或单条规则失败不影响其他设备与规则，所有错误聚合返回。
+func (r *RollupRunner) RunOnce(ctx context.Context, deviceIDs []string, now time.Time) (RollupResult, error) {
+	result := RollupResult{Written: make(map[string]int, len(r.rules))}
+	var errs []error
+	for _, id := range deviceIDs {
+		for _, rule := range r.rules {
+			if err := ctx.Err(); err != nil {
+				return result, errors.Join(append(errs, err)...)
+			}
+			grid := rule.grid()
+			if err := r.rollup(ctx, id, rule, grid.Floor(now.Add(-rule.Lookback)), grid.Floor(now), result); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// Recompute 重算设备在 [start, end) 内的全部目标周期 (按目标周期向外取整)
+// 场景: 补录或重新校准的数据早于 Lookback 覆盖的范围时，由调用方显式触发重算
+func (r *RollupRunner) Recompute(ctx context.Context, deviceID string, start, end time.Time) (RollupResult, error) {
+	result := RollupResult{Written: make(map[string]int, len(r.rules))}
+	var errs []error
+	for _, rule := range r.rules {
+		if err := ctx.Err(); err != nil {
+			return result, errors.Join(append(errs, err)...)
+		}
+		grid := rule.grid()
+		if err := r.rollup(ctx, deviceID, rule, grid.Floor(start), grid.Ceil(end), result); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// SetLogger 设置 Run 的日志输出 (nil 使用 slog.Default())，须在 Run 之前调用
+func (r *RollupRunner) SetLogger(logger *slog.Logger) {
+	r.logger = logger
+}
+
+// Run 启动时及之后每隔 interval 汇总一次，直到 ctx 取消 (见 runEvery)
+// devices 在每次汇总前调用，返回需要汇总的设备；列举或汇总失败只记录日志
+func (r *RollupRunner) Run(ctx context.Context, interval time.Duration, devices func(context.Context) ([]string, error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid rollup interval %s", interval)
+	}
+	return runEvery(ctx, interval, func(ctx context.Context) {
+		ids, err := devices(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				loggerOr(r.logger).Error("rollup device listing failed", "error", err)
+			}
+			return
+		}
+		result, err := r.RunOnce(ctx, ids, time.Now())
+		if err != nil && ctx.Err() == nil {
+			loggerOr(r.logger).Error("rollup run failed", "error", err)
+		}
+		if n := result.Total(); n > 0 {
+			loggerOr(r.logger).Info("rollup wrote standard readings", "written", n)
+		}
+	})
+}
+
+// rollupKey 汇总分组: 同一通道同一目标周期
+type rollupKey struct {
+	metric domain.Metric
+	start  int64
+}
+
+// rollup 汇总设备在 [start, end) 内的源读数并写回仓储
+// 缺失 (MISSING) 与已撤回 (WITHDRAWN) 的读数不参与汇总；没有源读数的周期不写入
+func (r *RollupRunner) rollup(ctx context.Context, deviceID string, rule RollupRule, start, end time.Time, result RollupResult) error {
+	if !end.After(start) {
+		return nil
+	}
+	target := domain.ResolutionTag(rule.Target)
+	readings, err := r.repo.FindRange(ctx, deviceID, start, end.Add(-time.Nanosecond)) // FindRange 为闭区间
+	if err != nil {
+		return fmt.Errorf("rollup %s to %s for %s: %w", rule.Source, target, deviceID, err)
+	}
+
+	grid := rule.grid()
+	groups := make(map[rollupKey][]domain.Reading)
+	scale := make(map[rollupKey]int)
+	for _, sr := range readings {
+		if sr.Resolution != rule.Source || sr.Quality == domain.QualityMissing || sr.Quality == domain.QualityWithdrawn {
+			continue
+		}
+		key := rollupKey{sr.Metric, grid.Floor(sr.Timestamp).UnixNano()}
+		groups[key] = append(groups[key], domain.Reading{
+			Timestamp:     sr.Timestamp,
+			Value:         sr.ValueDisplay,
+			Metric:        sr.Metric,
+			Quality:       sr.Quality,
+			QualityReason: sr.QualityReason,
+			Priority:      sr.Priority,
+			Confidence:    sr.Confidence,
+		})
+		scale[key] = max(scale[key], sr.ScaleFactor)
+	}
+
+	out := make([]domain.StandardReading, 0, len(groups))
+	for key, group := range groups {
+		agg, ok := domain.Aggregate(group, rule.Mode)
+		if !ok {
+			continue
+		}
+		quality := agg.Quality
+		if quality == "" {
+			quality = domain.QualityValid
+		}
+		out = append(out, domain.StandardReading{
+			DeviceID:      deviceID,
+			Metric:        key.metric,
+			Timestamp:     time.Unix(0, key.start).In(grid.Location),
+			Resolution:    target,
+			ValueScaled:   domain.ScaleDecimal(agg.Value, scale[key], domain.RoundHalfUp),
+			ScaleFactor:   scale[key],
+			ValueDisplay:  agg.Value,
+			Quality:       quality,
+			QualityReason: agg.QualityReason,
+			Confidence:    agg.Confidence,
+			SourceType:    domain.ReadingTypeStandard,
+			Priority:      agg.Priority,
+		})
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	slices.SortFunc(out, func(a, b domain.StandardReading) int {
+		return cmp.Or(a.Timestamp.Compare(b.Timestamp), cmp.Compare(a.Metric, b.Metric))
+	})
+	if err := r.repo.SaveBatch(ctx, out, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		return fmt.Errorf("rollup %s to %s for %s: %w", rule.Source, target, deviceID, err)
+	}
+	result.Written[target] += len(out)
+	return nil
+}
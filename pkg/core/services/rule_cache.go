@@ -0,0 +1,73 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DefaultRuleCacheTTL 动态规则缓存的默认有效期
+const DefaultRuleCacheTTL = 30 * time.Second
+
+// ruleCacheEntry 某设备类型下已编译的规则链
+type ruleCacheEntry struct {
+	rules     []ports.CleaningRule
+	expiresAt time.Time
+}
+
+// ruleCache 按设备类型缓存已编译的动态规则 (并发安全)
+// 避免每个批次都访问 CleaningRuleRepository
+type ruleCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration // <= 0 表示禁用缓存
+	entries map[domain.DeviceType]ruleCacheEntry
+}
+
+func newRuleCache(ttl time.Duration) *ruleCache {
+	return &ruleCache{
+		ttl:     ttl,
+		entries: make(map[domain.DeviceType]ruleCacheEntry),
+	}
+}
+
+// get 获取未过期的缓存规则
+func (c *ruleCache) get(deviceType domain.DeviceType) ([]ports.CleaningRule, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[deviceType]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.rules, true
+}
+
+// put 写入缓存
+func (c *ruleCache) put(deviceType domain.DeviceType, rules []ports.CleaningRule) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[deviceType] = ruleCacheEntry{rules: rules, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// invalidate 使指定设备类型的缓存失效 (不指定则清空全部)
+func (c *ruleCache) invalidate(deviceTypes ...domain.DeviceType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(deviceTypes) == 0 {
+		c.entries = make(map[domain.DeviceType]ruleCacheEntry)
+		return
+	}
+	for _, dt := range deviceTypes {
+		delete(c.entries, dt)
+	}
+}
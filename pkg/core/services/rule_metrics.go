@@ -0,0 +1,85 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// ruleStatsKey 统计维度: 规则 x 设备类型
+type ruleStatsKey struct {
+	ruleID     string
+	deviceType domain.DeviceType
+}
+
+// RuleMetrics 规则触发计数器 (并发安全)
+// 用于定位隔离区数据主要由哪些规则产生
+type RuleMetrics struct {
+	mu    sync.Mutex
+	stats map[ruleStatsKey]*ports.RuleStats
+}
+
+// NewRuleMetrics 创建空的规则计数器
+func NewRuleMetrics() *RuleMetrics {
+	return &RuleMetrics{stats: make(map[ruleStatsKey]*ports.RuleStats)}
+}
+
+// Record 记录一次规则检查结果
+func (m *RuleMetrics) Record(deviceType domain.DeviceType, result ports.CheckResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := ruleStatsKey{ruleID: result.RuleID, deviceType: deviceType}
+	st, ok := m.stats[key]
+	if !ok {
+		st = &ports.RuleStats{RuleID: result.RuleID, DeviceType: deviceType}
+		m.stats[key] = st
+	}
+
+	st.Checked++
+	switch {
+	case !result.Passed:
+		st.Rejected++
+	case result.Corrected:
+		st.Corrected++
+	default:
+		st.Passed++
+	}
+}
+
+// RuleStats 实现 ports.RuleStatsProvider
+// 返回结果按 RuleID、DeviceType 排序
+func (m *RuleMetrics) RuleStats() []ports.RuleStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]ports.RuleStats, 0, len(m.stats))
+	for _, st := range m.stats {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].RuleID != out[j].RuleID {
+			return out[i].RuleID < out[j].RuleID
+		}
+		return out[i].DeviceType < out[j].DeviceType
+	})
+	return out
+}
+
+// Reset 清空所有计数
+func (m *RuleMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats = make(map[ruleStatsKey]*ports.RuleStats)
+}
+
+// ruleID 获取规则标识: 优先使用 ports.IdentifiableRule，否则退化为类型名
+func ruleID(rule ports.CleaningRule) string {
+	if r, ok := rule.(ports.IdentifiableRule); ok && r.ID() != "" {
+		return r.ID()
+	}
+	return fmt.Sprintf("%T", rule)
+}
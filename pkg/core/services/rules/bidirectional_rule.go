@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// rolloverBand 判定翻转时使用的量程比例
+// 前值处于量程顶部 10% 且当前值处于量程底部 10% 时，视为计数器翻转 (反之亦然)
+const rolloverBand = 0.1
+
+// BidirectionalMonotonicRule 实现双向计量电表的读数检查
+// 净计量 (Net-Metering) 电表在反向输出时读数会合法下降，
+// 因此本规则不做回退检查，而是校验翻转 (Rollover) 与变化速率。
+type BidirectionalMonotonicRule struct {
+	MaxRate  float64 // 允许的最大变化速率 (单位/小时，取绝对值)
+	Rollover float64 // 表计量程 (读数达到该值后归零)，0 表示不处理翻转
+	Action   domain.RuleAction
+}
+
+// Check 检查读数的变化速率是否在允许范围内
+// 返回 CheckResult 包含完整的检查结果信息
+func (r *BidirectionalMonotonicRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	// 第一条数据无法判断变化，默认通过
+	if ctx.Previous == nil {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+
+	hours := curr.Timestamp.Sub(ctx.Previous.Timestamp).Hours()
+	if hours <= 0 {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+
+	delta := r.delta(ctx.Previous.Value, curr.Value)
+	rate := math.Abs(delta) / hours
+	if rate <= r.MaxRate {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+
+	switch r.Action {
+	case domain.ActionCorrect:
+		// 修正策略: 保持上一刻的值
+		fixed := curr
+		fixed.Value = ctx.Previous.Value
+		return ports.CheckResult{
+			Reading:   fixed,
+			Passed:    true,
+			Corrected: true,
+			Reason:    fmt.Sprintf("value %.2f corrected to %.2f: rate %.2f/h exceeds %.2f/h", curr.Value, ctx.Previous.Value, rate, r.MaxRate),
+		}
+
+	case domain.ActionReject:
+		fallthrough
+	default:
+		return ports.CheckResult{
+			Reading: curr,
+			Passed:  false,
+			Reason:  fmt.Sprintf("rate %.2f/h exceeds %.2f/h (%.2f -> %.2f)", rate, r.MaxRate, ctx.Previous.Value, curr.Value),
+		}
+	}
+}
+
+// delta 计算两次读数之间的净变化量，并对计数器翻转做补偿
+func (r *BidirectionalMonotonicRule) delta(prev, curr float64) float64 {
+	delta := curr - prev
+	if r.Rollover <= 0 {
+		return delta
+	}
+
+	low := r.Rollover * rolloverBand
+	high := r.Rollover - low
+	switch {
+	case prev >= high && curr <= low:
+		// 正向翻转: 999990 -> 10
+		return curr + r.Rollover - prev
+	case prev <= low && curr >= high:
+		// 反向翻转 (输出电能导致读数越过零点): 10 -> 999990
+		return curr - r.Rollover - prev
+	default:
+		return delta
+	}
+}
@@ -0,0 +1,47 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// CompositeRule 实现子规则的布尔组合
+// 子规则只作为“条件”使用: 子规则拒绝或修正读数即视为该条件触发。
+// 组合条件成立时拒绝读数，例如 “超出范围 AND 非工作时间”。
+type CompositeRule struct {
+	Operator domain.CompositeOperator
+	Children []ports.CleaningRule
+}
+
+// Check 按运算符组合子规则的触发结果
+// 返回 CheckResult 包含完整的检查结果信息
+func (r *CompositeRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	var reasons []string
+	triggered := 0
+	for _, child := range r.Children {
+		res := child.Check(ctx, curr)
+		if !res.Passed || res.Corrected {
+			triggered++
+			reasons = append(reasons, res.Reason)
+		}
+	}
+
+	var matched bool
+	switch r.Operator {
+	case domain.CompositeAnd:
+		matched = len(r.Children) > 0 && triggered == len(r.Children)
+	case domain.CompositeOr:
+		matched = triggered > 0
+	case domain.CompositeNot:
+		matched = triggered == 0
+		reasons = []string{"negated condition not triggered"}
+	}
+
+	if !matched {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+	return reject(curr, fmt.Sprintf("composite %s condition matched: %s", r.Operator, strings.Join(reasons, "; ")))
+}
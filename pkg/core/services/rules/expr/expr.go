@@ -0,0 +1,357 @@
+// Package expr 实现脚本规则使用的沙箱表达式语言
+//
+// 语法采用 Go 表达式子集 (由 go/parser 解析)，只允许:
+//   - 字面量: 数字、字符串、true / false
+//   - 变量与字段访问: value, prev.value, device.tags["site"] 等 (由 Env 提供)
+//   - 运算符: + - * / %  == != < <= > >=  && || !
+//   - 白名单函数: abs, min, max, sqrt
+//
+// 表达式没有循环、赋值和任意函数调用，求值必然终止，且无法访问宿主进程的任何状态。
+package expr
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+)
+
+// MaxSourceLen 表达式源码的最大长度
+const MaxSourceLen = 4096
+
+// Env 表达式求值环境: 变量名 -> 值
+// 值的类型只能是 float64 / bool / string / map[string]any / map[string]string
+type Env map[string]any
+
+// Program 编译后的表达式
+type Program struct {
+	src  string
+	root ast.Expr
+}
+
+// Compile 解析并校验表达式，只接受白名单内的语法结构
+func Compile(src string) (*Program, error) {
+	if len(src) > MaxSourceLen {
+		return nil, fmt.Errorf("expression too long: %d > %d bytes", len(src), MaxSourceLen)
+	}
+	root, err := parser.ParseExpr(src)
+	if err != nil {
+		return nil, fmt.Errorf("parse expression: %w", err)
+	}
+
+	var verr error
+	ast.Inspect(root, func(n ast.Node) bool {
+		if verr != nil || n == nil {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.BasicLit:
+			if node.Kind != token.INT && node.Kind != token.FLOAT && node.Kind != token.STRING {
+				verr = fmt.Errorf("unsupported literal %s", node.Value)
+			}
+		case *ast.Ident, *ast.ParenExpr, *ast.SelectorExpr, *ast.IndexExpr:
+		case *ast.BinaryExpr:
+			if _, ok := binaryOps[node.Op]; !ok {
+				verr = fmt.Errorf("unsupported operator %s", node.Op)
+			}
+		case *ast.UnaryExpr:
+			if node.Op != token.SUB && node.Op != token.NOT && node.Op != token.ADD {
+				verr = fmt.Errorf("unsupported operator %s", node.Op)
+			}
+		case *ast.CallExpr:
+			fn, ok := node.Fun.(*ast.Ident)
+			if !ok || builtins[fn.Name] == nil {
+				verr = fmt.Errorf("unsupported function call")
+			}
+		default:
+			verr = fmt.Errorf("unsupported syntax %T", n)
+		}
+		return verr == nil
+	})
+	if verr != nil {
+		return nil, verr
+	}
+	return &Program{src: src, root: root}, nil
+}
+
+// String 返回表达式源码
+func (p *Program) String() string {
+	return p.src
+}
+
+// Eval 在给定环境下对表达式求值
+func (p *Program) Eval(env Env) (any, error) {
+	return eval(p.root, env)
+}
+
+// EvalBool 求值并要求结果为布尔值
+func (p *Program) EvalBool(env Env) (bool, error) {
+	v, err := p.Eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q: expected bool, got %T", p.src, v)
+	}
+	return b, nil
+}
+
+// EvalFloat 求值并要求结果为数值
+func (p *Program) EvalFloat(env Env) (float64, error) {
+	v, err := p.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("expression %q: expected number, got %T", p.src, v)
+	}
+	return f, nil
+}
+
+var binaryOps = map[token.Token]struct{}{
+	token.ADD: {}, token.SUB: {}, token.MUL: {}, token.QUO: {}, token.REM: {},
+	token.EQL: {}, token.NEQ: {}, token.LSS: {}, token.LEQ: {}, token.GTR: {}, token.GEQ: {},
+	token.LAND: {}, token.LOR: {},
+}
+
+// builtins 白名单函数
+var builtins = map[string]func(args []float64) (float64, error){
+	"abs": func(args []float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("abs expects 1 argument")
+		}
+		return math.Abs(args[0]), nil
+	},
+	"sqrt": func(args []float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("sqrt expects 1 argument")
+		}
+		return math.Sqrt(args[0]), nil
+	},
+	"min": func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("min expects at least 1 argument")
+		}
+		m := args[0]
+		for _, a := range args[1:] {
+			m = math.Min(m, a)
+		}
+		return m, nil
+	},
+	"max": func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("max expects at least 1 argument")
+		}
+		m := args[0]
+		for _, a := range args[1:] {
+			m = math.Max(m, a)
+		}
+		return m, nil
+	},
+}
+
+func eval(n ast.Expr, env Env) (any, error) {
+	switch node := n.(type) {
+	case *ast.BasicLit:
+		if node.Kind == token.STRING {
+			return strconv.Unquote(node.Value)
+		}
+		return strconv.ParseFloat(node.Value, 64)
+
+	case *ast.Ident:
+		switch node.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		v, ok := env[node.Name]
+		if !ok {
+			return nil, fmt.Errorf("undefined variable %s", node.Name)
+		}
+		return v, nil
+
+	case *ast.ParenExpr:
+		return eval(node.X, env)
+
+	case *ast.SelectorExpr:
+		x, err := eval(node.X, env)
+		if err != nil {
+			return nil, err
+		}
+		return lookup(x, node.Sel.Name)
+
+	case *ast.IndexExpr:
+		x, err := eval(node.X, env)
+		if err != nil {
+			return nil, err
+		}
+		idx, err := eval(node.Index, env)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := idx.(string)
+		if !ok {
+			return nil, fmt.Errorf("index must be a string, got %T", idx)
+		}
+		return lookup(x, key)
+
+	case *ast.UnaryExpr:
+		x, err := eval(node.X, env)
+		if err != nil {
+			return nil, err
+		}
+		if node.Op == token.NOT {
+			b, ok := x.(bool)
+			if !ok {
+				return nil, fmt.Errorf("operator ! expects bool, got %T", x)
+			}
+			return !b, nil
+		}
+		f, ok := x.(float64)
+		if !ok {
+			return nil, fmt.Errorf("operator %s expects number, got %T", node.Op, x)
+		}
+		if node.Op == token.SUB {
+			return -f, nil
+		}
+		return f, nil
+
+	case *ast.BinaryExpr:
+		return evalBinary(node, env)
+
+	case *ast.CallExpr:
+		fn := builtins[node.Fun.(*ast.Ident).Name]
+		args := make([]float64, 0, len(node.Args))
+		for _, a := range node.Args {
+			v, err := eval(a, env)
+			if err != nil {
+				return nil, err
+			}
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("function arguments must be numbers, got %T", v)
+			}
+			args = append(args, f)
+		}
+		return fn(args)
+	}
+	return nil, fmt.Errorf("unsupported syntax %T", n)
+}
+
+func evalBinary(node *ast.BinaryExpr, env Env) (any, error) {
+	x, err := eval(node.X, env)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逻辑运算短路求值，便于编写 `!has_prev || value >= prev.value` 这类保护条件
+	if node.Op == token.LAND || node.Op == token.LOR {
+		xb, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator %s expects bool, got %T", node.Op, x)
+		}
+		if (node.Op == token.LAND && !xb) || (node.Op == token.LOR && xb) {
+			return xb, nil
+		}
+		y, err := eval(node.Y, env)
+		if err != nil {
+			return nil, err
+		}
+		yb, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator %s expects bool, got %T", node.Op, y)
+		}
+		return yb, nil
+	}
+
+	y, err := eval(node.Y, env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch xv := x.(type) {
+	case float64:
+		yv, ok := y.(float64)
+		if !ok {
+			return nil, fmt.Errorf("mismatched operands %T %s %T", x, node.Op, y)
+		}
+		switch node.Op {
+		case token.ADD:
+			return xv + yv, nil
+		case token.SUB:
+			return xv - yv, nil
+		case token.MUL:
+			return xv * yv, nil
+		case token.QUO:
+			if yv == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return xv / yv, nil
+		case token.REM:
+			if yv == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return math.Mod(xv, yv), nil
+		case token.EQL:
+			return xv == yv, nil
+		case token.NEQ:
+			return xv != yv, nil
+		case token.LSS:
+			return xv < yv, nil
+		case token.LEQ:
+			return xv <= yv, nil
+		case token.GTR:
+			return xv > yv, nil
+		case token.GEQ:
+			return xv >= yv, nil
+		}
+	case string:
+		yv, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("mismatched operands %T %s %T", x, node.Op, y)
+		}
+		switch node.Op {
+		case token.EQL:
+			return xv == yv, nil
+		case token.NEQ:
+			return xv != yv, nil
+		case token.ADD:
+			return xv + yv, nil
+		}
+	case bool:
+		yv, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("mismatched operands %T %s %T", x, node.Op, y)
+		}
+		switch node.Op {
+		case token.EQL:
+			return xv == yv, nil
+		case token.NEQ:
+			return xv != yv, nil
+		}
+	}
+	return nil, fmt.Errorf("operator %s not supported for %T", node.Op, x)
+}
+
+// lookup 字段或键访问
+func lookup(x any, key string) (any, error) {
+	switch m := x.(type) {
+	case map[string]any:
+		v, ok := m[key]
+		if !ok {
+			return nil, fmt.Errorf("undefined field %s", key)
+		}
+		return v, nil
+	case map[string]string:
+		// 标签缺失时返回空串，便于 device.tags["site"] == "A" 这类判断
+		return m[key], nil
+	case nil:
+		return nil, fmt.Errorf("field %s of nil value", key)
+	}
+	return nil, fmt.Errorf("cannot access field %s of %T", key, x)
+}
@@ -0,0 +1,30 @@
+package rules
+
+import (
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// IdentifiedRule 为任意规则附加稳定的规则标识 (通常为 domain.CleaningRule.ID)
+// 实现了 ports.IdentifiableRule，Sanitizer 据此统计规则触发次数并记录隔离原因
+type IdentifiedRule struct {
+	RuleID string
+	Inner  ports.CleaningRule
+}
+
+// WithID 包装规则并附加标识
+func WithID(id string, rule ports.CleaningRule) *IdentifiedRule {
+	return &IdentifiedRule{RuleID: id, Inner: rule}
+}
+
+// ID 实现 ports.IdentifiableRule
+func (r *IdentifiedRule) ID() string {
+	return r.RuleID
+}
+
+// Check 委托给被包装的规则，并在结果中填充规则标识
+func (r *IdentifiedRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	result := r.Inner.Check(ctx, curr)
+	result.RuleID = r.RuleID
+	return result
+}
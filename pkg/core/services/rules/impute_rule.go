@@ -0,0 +1,83 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// 插补值的置信度: 线性插值有前后两个观测值支撑，比沿用前值更可信
+const (
+	locfConfidence   = 0.5
+	linearConfidence = 0.7
+)
+
+// ImputeRule 实现缺失值插补
+// 对摄入层标记为 MISSING 的读数按配置的策略补值，补值结果标记为 ESTIMATED。
+// 注意: 该规则应配置为规则链中的第一条，确保后续规则看到的是插补后的值。
+type ImputeRule struct {
+	Strategy domain.ImputeStrategy
+}
+
+// Check 对缺失值进行插补，非缺失值直接通过
+// 返回 CheckResult 包含完整的检查结果信息
+func (r *ImputeRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	if !curr.IsMissing() {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+
+	prev := ctx.Previous
+	if prev != nil && prev.DeviceInfo.ID != curr.DeviceInfo.ID {
+		prev = nil
+	}
+
+	switch r.Strategy {
+	case domain.ImputeLOCF:
+		if prev == nil {
+			return reject(curr, "missing value: no previous reading to carry forward")
+		}
+		return estimated(curr, prev.Value, locfConfidence, fmt.Sprintf("missing value imputed by LOCF: %.2f", prev.Value))
+
+	case domain.ImputeLinear:
+		next := ctx.Next
+		if prev == nil || next == nil || next.IsMissing() {
+			return reject(curr, "missing value: no bracketing readings for linear interpolation")
+		}
+		span := next.Timestamp.Sub(prev.Timestamp)
+		if span <= 0 {
+			return reject(curr, "missing value: invalid interpolation window")
+		}
+		ratio := float64(curr.Timestamp.Sub(prev.Timestamp)) / float64(span)
+		value := prev.Value + (next.Value-prev.Value)*ratio
+		return estimated(curr, value, linearConfidence, fmt.Sprintf("missing value imputed by linear interpolation: %.2f", value))
+
+	case domain.ImputeReject:
+		fallthrough
+	default:
+		return reject(curr, "missing value")
+	}
+}
+
+// estimated 构造插补成功的结果
+func estimated(curr domain.Reading, value, confidence float64, reason string) ports.CheckResult {
+	fixed := curr
+	fixed.Value = value
+	fixed.Quality = domain.QualityEstimated
+	return ports.CheckResult{
+		Reading:    fixed,
+		Passed:     true,
+		Corrected:  true,
+		Reason:     reason,
+		Confidence: confidence,
+	}
+}
+
+// reject 构造拒绝结果
+func reject(curr domain.Reading, reason string) ports.CheckResult {
+	return ports.CheckResult{
+		Reading: curr,
+		Passed:  false,
+		Reason:  reason,
+	}
+}
@@ -0,0 +1,26 @@
+package rules
+
+import (
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// ScopedRule 为规则附加设备作用范围与豁免名单
+// 不在作用范围内或命中豁免名单的设备直接通过，不执行被包装的规则
+type ScopedRule struct {
+	Config domain.CleaningRule // 仅使用 DeviceIDs 与 Exemptions
+	Inner  ports.CleaningRule
+}
+
+// WithScope 包装规则并附加作用范围与豁免名单
+func WithScope(config domain.CleaningRule, rule ports.CleaningRule) *ScopedRule {
+	return &ScopedRule{Config: config, Inner: rule}
+}
+
+// Check 范围外或豁免设备直接通过，其余委托给被包装的规则
+func (r *ScopedRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	if !r.Config.AppliesTo(curr.DeviceInfo) {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+	return r.Inner.Check(ctx, curr)
+}
@@ -0,0 +1,93 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services/rules/expr"
+)
+
+// ScriptRule 实现基于沙箱表达式的脚本规则
+// Condition 为真表示读数通过；为假时按 Action 拒绝，或用 Correction 计算修正值。
+//
+// 可用变量:
+//
+//	value, ts, hour, weekday        当前读数 (ts 为 Unix 秒，hour/weekday 按 UTC)
+//	prev.value, prev.ts             前一条有效读数 (has_prev 为假时不可访问)
+//	next.value, next.ts             同设备的下一条原始读数 (has_next 为假时不可访问)
+//	dt                              距前一条读数的秒数 (无前值时为 0)
+//	device.id, device.type, device.model, device.tags["key"]
+type ScriptRule struct {
+	Condition  *expr.Program
+	Correction *expr.Program // 可选，仅 Action=CORRECT 时使用
+	Action     domain.RuleAction
+}
+
+// Check 对当前读数执行脚本
+// 脚本求值出错时视为未通过，错误信息写入 Reason
+func (r *ScriptRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	env := scriptEnv(ctx, curr)
+
+	ok, err := r.Condition.EvalBool(env)
+	if err != nil {
+		return reject(curr, fmt.Sprintf("script error: %v", err))
+	}
+	if ok {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+
+	if r.Action == domain.ActionCorrect && r.Correction != nil {
+		value, err := r.Correction.EvalFloat(env)
+		if err != nil {
+			return reject(curr, fmt.Sprintf("script correction error: %v", err))
+		}
+		fixed := curr
+		fixed.Value = value
+		return ports.CheckResult{
+			Reading:   fixed,
+			Passed:    true,
+			Corrected: true,
+			Reason:    fmt.Sprintf("value %.2f corrected to %.2f by script: %s", curr.Value, value, r.Condition),
+		}
+	}
+
+	return reject(curr, fmt.Sprintf("script condition failed: %s", r.Condition))
+}
+
+// scriptEnv 构造脚本求值环境
+func scriptEnv(ctx ports.CleaningContext, curr domain.Reading) expr.Env {
+	utc := curr.Timestamp.UTC()
+	env := expr.Env{
+		"value":    curr.Value,
+		"ts":       float64(curr.Timestamp.Unix()),
+		"hour":     float64(utc.Hour()),
+		"weekday":  float64(utc.Weekday()),
+		"has_prev": ctx.Previous != nil,
+		"has_next": ctx.Next != nil && !ctx.Next.IsMissing(),
+		"prev":     nil,
+		"next":     nil,
+		"dt":       0.0,
+		"device": map[string]any{
+			"id":    curr.DeviceInfo.ID,
+			"type":  string(curr.DeviceInfo.Type),
+			"model": curr.DeviceInfo.Model,
+			"tags":  curr.DeviceInfo.Tags,
+		},
+	}
+	if ctx.Previous != nil {
+		env["prev"] = readingFields(*ctx.Previous)
+		env["dt"] = curr.Timestamp.Sub(ctx.Previous.Timestamp).Seconds()
+	}
+	if ctx.Next != nil && !ctx.Next.IsMissing() {
+		env["next"] = readingFields(*ctx.Next)
+	}
+	return env
+}
+
+func readingFields(r domain.Reading) map[string]any {
+	return map[string]any{
+		"value": r.Value,
+		"ts":    float64(r.Timestamp.Unix()),
+	}
+}
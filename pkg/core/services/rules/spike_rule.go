@@ -0,0 +1,63 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// SpikeRule 实现孤立尖峰 (Spike-and-Return) 检测
+// 借助一条读数的前瞻窗口判断当前值是否偏离前后读数构成的趋势线：
+// 若偏离超过阈值而下一条读数又回到趋势上，则只剔除这一个尖峰，
+// 避免先拒绝尖峰、再把合法的下一条读数当作“回退”隔离。
+type SpikeRule struct {
+	Threshold float64 // 相对趋势线的最大允许偏离量 (绝对值)
+	Action    domain.RuleAction
+}
+
+// Check 检查当前读数是否为孤立尖峰
+// 返回 CheckResult 包含完整的检查结果信息
+func (r *SpikeRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	prev, next := ctx.Previous, ctx.Next
+	// 缺少前后读数时无法判断趋势，默认通过
+	if prev == nil || next == nil || next.IsMissing() || prev.DeviceInfo.ID != curr.DeviceInfo.ID {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+
+	span := next.Timestamp.Sub(prev.Timestamp)
+	if span <= 0 {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+
+	// 前后读数本身的跳变超过阈值，说明趋势确实发生变化，而非孤立尖峰
+	if math.Abs(next.Value-prev.Value) > r.Threshold {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+
+	ratio := float64(curr.Timestamp.Sub(prev.Timestamp)) / float64(span)
+	expected := prev.Value + (next.Value-prev.Value)*ratio
+	deviation := math.Abs(curr.Value - expected)
+	if deviation <= r.Threshold {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+
+	switch r.Action {
+	case domain.ActionCorrect:
+		// 修正策略: 用趋势线上的值替换尖峰
+		fixed := curr
+		fixed.Value = expected
+		return ports.CheckResult{
+			Reading:   fixed,
+			Passed:    true,
+			Corrected: true,
+			Reason:    fmt.Sprintf("spike %.2f corrected to trend value %.2f", curr.Value, expected),
+		}
+
+	case domain.ActionReject:
+		fallthrough
+	default:
+		return reject(curr, fmt.Sprintf("isolated spike %.2f deviates %.2f from trend (threshold %.2f)", curr.Value, deviation, r.Threshold))
+	}
+}
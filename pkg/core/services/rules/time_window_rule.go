@@ -0,0 +1,49 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// TimeWindowRule 实现时间窗口检查
+// 读数时间落在窗口 (如工作时间 08:00-18:00, 周一至周五) 之外时触发。
+// 单独使用时会拒绝窗口外的读数，更常见的用法是作为 CompositeRule 的条件。
+type TimeWindowRule struct {
+	Start    time.Duration         // 窗口开始 (距当日零点的偏移)
+	End      time.Duration         // 窗口结束 (不含)，小于 Start 表示跨越午夜
+	Weekdays map[time.Weekday]bool // 生效的星期 (为空表示每天)
+	Location *time.Location        // 判定所用时区 (为空表示 UTC)
+}
+
+// Check 检查读数是否落在时间窗口内
+// 返回 CheckResult 包含完整的检查结果信息
+func (r *TimeWindowRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	if r.inWindow(curr.Timestamp) {
+		return ports.CheckResult{Reading: curr, Passed: true}
+	}
+	return reject(curr, fmt.Sprintf("timestamp %s outside time window", curr.Timestamp.Format(time.RFC3339)))
+}
+
+// inWindow 判断时间点是否落在窗口内
+func (r *TimeWindowRule) inWindow(ts time.Time) bool {
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := ts.In(loc)
+
+	if len(r.Weekdays) > 0 && !r.Weekdays[local.Weekday()] {
+		return false
+	}
+
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	offset := local.Sub(midnight)
+	if r.Start <= r.End {
+		return offset >= r.Start && offset < r.End
+	}
+	// 跨越午夜的窗口，如 22:00-06:00
+	return offset >= r.Start || offset < r.End
+}
@@ -0,0 +1,48 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// 时间戳合理性检查的隔离原因前缀，便于在隔离区按原因筛选
+const (
+	ReasonFutureTimestamp = "future timestamp"
+	ReasonStaleTimestamp  = "stale timestamp"
+)
+
+// TimestampSanityRule 实现时间戳合理性检查
+// RTC 电池失效的设备会上报 1970 / 2099 之类的时间戳，直接进入标准库会污染历史数据。
+// 该规则拒绝超出未来容差或早于历史视界的读数，两种情况使用不同的隔离原因。
+// 时间戳无法“修正”，因此该规则总是拒绝。
+type TimestampSanityRule struct {
+	MaxFutureSkew time.Duration    // 允许超前当前时间的最大偏差
+	MaxAge        time.Duration    // 允许的最大数据年龄 (<= 0 表示不检查过期)
+	Now           func() time.Time // 时钟 (为空时使用 time.Now，便于测试注入)
+}
+
+// Check 检查读数时间戳是否落在 [now - MaxAge, now + MaxFutureSkew] 内
+// 返回 CheckResult 包含完整的检查结果信息
+func (r *TimestampSanityRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	now := time.Now()
+	if r.Now != nil {
+		now = r.Now()
+	}
+
+	if limit := now.Add(r.MaxFutureSkew); curr.Timestamp.After(limit) {
+		return reject(curr, fmt.Sprintf("%s: %s is %s ahead of now (max skew %s)",
+			ReasonFutureTimestamp, curr.Timestamp.Format(time.RFC3339), curr.Timestamp.Sub(now).Round(time.Second), r.MaxFutureSkew))
+	}
+
+	if r.MaxAge > 0 {
+		if horizon := now.Add(-r.MaxAge); curr.Timestamp.Before(horizon) {
+			return reject(curr, fmt.Sprintf("%s: %s is older than horizon %s",
+				ReasonStaleTimestamp, curr.Timestamp.Format(time.RFC3339), r.MaxAge))
+		}
+	}
+
+	return ports.CheckResult{Reading: curr, Passed: true}
+}
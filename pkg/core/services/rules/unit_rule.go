@@ -0,0 +1,69 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// UnitAwareRule 为规则的阈值参数附加计量单位
+// 设备原生单位与规则单位不同时，先把读数 (及上下文读数) 换算到规则单位再执行检查，
+// 修正后的值再换算回设备单位。阈值均为线性比较，这与把阈值换算到设备单位等价。
+// 设备未声明单位时视为与规则单位一致。
+type UnitAwareRule struct {
+	Unit  domain.Unit // 规则参数的单位
+	Inner ports.CleaningRule
+}
+
+// WithUnit 包装规则并声明其阈值单位
+func WithUnit(unit domain.Unit, rule ports.CleaningRule) *UnitAwareRule {
+	return &UnitAwareRule{Unit: unit, Inner: rule}
+}
+
+// Check 在规则单位下执行被包装的规则
+func (r *UnitAwareRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	native := curr.DeviceInfo.Unit
+	if native == "" || native == r.Unit {
+		return r.Inner.Check(ctx, curr)
+	}
+
+	converted, err := toUnit(curr, native, r.Unit)
+	if err != nil {
+		return reject(curr, fmt.Sprintf("unit mismatch: %v", err))
+	}
+
+	var convCtx ports.CleaningContext
+	if ctx.Previous != nil {
+		// 上下文读数与当前读数来自同一设备，换算不会失败
+		prev, _ := toUnit(*ctx.Previous, native, r.Unit)
+		convCtx.Previous = &prev
+	}
+	if ctx.Next != nil {
+		next, _ := toUnit(*ctx.Next, native, r.Unit)
+		convCtx.Next = &next
+	}
+
+	result := r.Inner.Check(convCtx, converted)
+	if result.Reading.Value == converted.Value {
+		// 取值未被修改: 还原原始值，避免往返换算引入浮点误差
+		result.Reading.Value = curr.Value
+		return result
+	}
+	back, _ := domain.ConvertValue(result.Reading.Value, r.Unit, native)
+	result.Reading.Value = back
+	return result
+}
+
+// toUnit 换算读数取值 (缺失值保持不变)
+func toUnit(r domain.Reading, from, to domain.Unit) (domain.Reading, error) {
+	if r.IsMissing() {
+		return r, nil
+	}
+	v, err := domain.ConvertValue(r.Value, from, to)
+	if err != nil {
+		return r, err
+	}
+	r.Value = v
+	return r, nil
+}
@@ -0,0 +1,249 @@
+// Package ruletest 提供规则链的黄金数据集 (Golden Dataset) 回归测试工具
+//
+// 用户将带标注的读数写成 CSV 夹具，在自己的测试中断言规则链能复现这些标注，
+// 从而用回归测试为规则变更把关:
+//
+//	func TestElecRules(t *testing.T) {
+//		cases, err := ruletest.LoadCSVFile("testdata/elec_golden.csv")
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		ruletest.Run(t, services.NewSanitizer(myRules...), cases)
+//	}
+//
+// CSV 格式 (首行为表头，列顺序不限):
+//
+//	device_id,type,timestamp,value,expected,expected_value
+//	E1,ELEC,2023-01-01T10:00:00Z,100,PASS,
+//	E1,ELEC,2023-01-01T10:15:00Z,900,REJECT,
+//	E1,ELEC,2023-01-01T10:30:00Z,,CORRECT,101
+//
+// type 与 expected_value 为可选列；value 为空表示缺失值。
+package ruletest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// Verdict 期望的清洗结论
+type Verdict string
+
+const (
+	VerdictPass    Verdict = "PASS"    // 原样通过
+	VerdictCorrect Verdict = "CORRECT" // 修正后通过
+	VerdictReject  Verdict = "REJECT"  // 被隔离
+)
+
+// valueTolerance 比较修正值时允许的浮点误差
+const valueTolerance = 1e-9
+
+// Case 一条带标注的读数
+type Case struct {
+	Line          int // CSV 行号 (用于报错定位)
+	Reading       domain.Reading
+	Expected      Verdict
+	ExpectedValue *float64 // 可选: 期望的修正后取值
+}
+
+// Mismatch 规则链结果与标注不一致的记录
+type Mismatch struct {
+	Case   Case
+	Got    Verdict
+	Value  float64 // 清洗后的取值 (REJECT 时为原值)
+	Reason string  // 隔离原因 (仅 REJECT)
+}
+
+// String 返回可读的差异描述
+func (m Mismatch) String() string {
+	msg := fmt.Sprintf("line %d (%s @ %s): expected %s", m.Case.Line, m.Case.Reading.DeviceInfo.ID,
+		m.Case.Reading.Timestamp.Format(time.RFC3339), m.Case.Expected)
+	if m.Case.ExpectedValue != nil {
+		msg += fmt.Sprintf(" (value %v)", *m.Case.ExpectedValue)
+	}
+	msg += fmt.Sprintf(", got %s (value %v)", m.Got, m.Value)
+	if m.Reason != "" {
+		msg += ": " + m.Reason
+	}
+	return msg
+}
+
+// LoadCSVFile 从文件加载标注数据
+func LoadCSVFile(path string) ([]Case, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadCSV(f)
+}
+
+// LoadCSV 从 CSV 流加载标注数据
+// 同一设备的时间戳必须唯一，以便将清洗结果对应回标注
+func LoadCSV(r io.Reader) ([]Case, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	cols := make(map[string]int)
+	for i, h := range headers {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, req := range []string{"device_id", "timestamp", "value", "expected"} {
+		if _, ok := cols[req]; !ok {
+			return nil, fmt.Errorf("missing required header: %s", req)
+		}
+	}
+
+	var cases []Case
+	seen := make(map[caseKey]int)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		get := func(col string) string {
+			if idx, ok := cols[col]; ok && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+
+		c, err := parseCase(line, get)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		key := keyOf(c.Reading)
+		if prevLine, dup := seen[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate device/timestamp (first seen at line %d)", line, prevLine)
+		}
+		seen[key] = line
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+func parseCase(line int, get func(string) string) (Case, error) {
+	c := Case{Line: line}
+
+	c.Reading.DeviceInfo = domain.DeviceInfo{ID: get("device_id"), Type: domain.DeviceType(get("type"))}
+	if c.Reading.DeviceInfo.ID == "" {
+		return c, fmt.Errorf("device_id is empty")
+	}
+
+	tsStr := get("timestamp")
+	ts, err := time.Parse(time.RFC3339, tsStr)
+	if err != nil {
+		if ts, err = time.Parse("2006-01-02 15:04:05", tsStr); err != nil {
+			return c, fmt.Errorf("invalid timestamp format: %s", tsStr)
+		}
+	}
+	c.Reading.Timestamp = ts
+
+	if valStr := get("value"); valStr == "" {
+		c.Reading.Quality = domain.QualityMissing
+	} else if c.Reading.Value, err = strconv.ParseFloat(valStr, 64); err != nil {
+		return c, fmt.Errorf("invalid value format: %s", valStr)
+	}
+
+	c.Expected = Verdict(strings.ToUpper(get("expected")))
+	switch c.Expected {
+	case VerdictPass, VerdictCorrect, VerdictReject:
+	default:
+		return c, fmt.Errorf("invalid expected verdict %q (want PASS / CORRECT / REJECT)", c.Expected)
+	}
+
+	if evStr := get("expected_value"); evStr != "" {
+		ev, err := strconv.ParseFloat(evStr, 64)
+		if err != nil {
+			return c, fmt.Errorf("invalid expected_value format: %s", evStr)
+		}
+		c.ExpectedValue = &ev
+	}
+	return c, nil
+}
+
+// Evaluate 用给定的 Sanitizer 清洗标注数据，返回与标注不一致的记录
+// 清洗结果与输入取值或质量不同即判定为 CORRECT
+func Evaluate(sanitizer ports.Sanitizer, cases []Case) []Mismatch {
+	input := make([]domain.Reading, len(cases))
+	for i, c := range cases {
+		input[i] = c.Reading
+	}
+
+	clean, quarantined := sanitizer.Clean(input)
+
+	cleanByKey := make(map[caseKey]domain.Reading, len(clean))
+	for _, r := range clean {
+		cleanByKey[keyOf(r)] = r
+	}
+	rejectByKey := make(map[caseKey]domain.QuarantineReading, len(quarantined))
+	for _, q := range quarantined {
+		rejectByKey[keyOf(q.Reading)] = q
+	}
+
+	var mismatches []Mismatch
+	for _, c := range cases {
+		key := keyOf(c.Reading)
+		got := Mismatch{Case: c, Value: c.Reading.Value}
+
+		if q, rejected := rejectByKey[key]; rejected {
+			got.Got = VerdictReject
+			got.Reason = q.Reason
+		} else if r, ok := cleanByKey[key]; ok {
+			got.Value = r.Value
+			got.Got = VerdictPass
+			if !sameValue(r.Value, c.Reading.Value) || r.Quality != c.Reading.Quality {
+				got.Got = VerdictCorrect
+			}
+		} else {
+			got.Got = VerdictReject
+			got.Reason = "reading missing from sanitizer output"
+		}
+
+		if got.Got != c.Expected || (c.ExpectedValue != nil && got.Got != VerdictReject && !sameValue(got.Value, *c.ExpectedValue)) {
+			mismatches = append(mismatches, got)
+		}
+	}
+	return mismatches
+}
+
+// Run 断言 Sanitizer 能复现全部标注，每条不一致记录报告一次错误
+func Run(t testing.TB, sanitizer ports.Sanitizer, cases []Case) {
+	t.Helper()
+	for _, m := range Evaluate(sanitizer, cases) {
+		t.Error(m.String())
+	}
+}
+
+type caseKey struct {
+	deviceID string
+	unixNano int64
+}
+
+func keyOf(r domain.Reading) caseKey {
+	return caseKey{deviceID: r.DeviceInfo.ID, unixNano: r.Timestamp.UnixNano()}
+}
+
+func sameValue(a, b float64) bool {
+	return math.Abs(a-b) <= valueTolerance
+}
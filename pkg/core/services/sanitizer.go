@@ -10,83 +10,268 @@ import (
 
 // ChainSanitizer 基于责任链模式的清洗器实现
 type ChainSanitizer struct {
-	rules []ports.CleaningRule
+	rules           []ports.CleaningRule
+	metrics         *RuleMetrics           // 规则触发计数器
+	duplicatePolicy domain.DuplicatePolicy // 重复时间戳处理策略
 }
 
-// NewSanitizer 创建默认的基于规则链的清洗器
+// NewSanitizer 创建默认的基于规则链的清洗器 (重复时间戳保留最先到达的一条)
 func NewSanitizer(rules ...ports.CleaningRule) ports.Sanitizer {
-	return &ChainSanitizer{rules: rules}
+	return newChainSanitizer(NewRuleMetrics(), domain.DuplicateKeepFirst, rules...)
+}
+
+// NewSanitizerWithPolicy 创建指定重复时间戳策略的清洗器
+func NewSanitizerWithPolicy(policy domain.DuplicatePolicy, rules ...ports.CleaningRule) ports.Sanitizer {
+	return newChainSanitizer(NewRuleMetrics(), policy, rules...)
+}
+
+// newChainSanitizer 创建共享计数器的清洗器 (供 Standardizer 汇总多批次统计)
+func newChainSanitizer(metrics *RuleMetrics, policy domain.DuplicatePolicy, rules ...ports.CleaningRule) *ChainSanitizer {
+	if policy == "" {
+		policy = domain.DuplicateKeepFirst
+	}
+	return &ChainSanitizer{rules: rules, metrics: metrics, duplicatePolicy: policy}
+}
+
+// RuleStats 实现 ports.RuleStatsProvider，返回该清洗器累计的规则触发统计
+func (s *ChainSanitizer) RuleStats() []ports.RuleStats {
+	return s.metrics.RuleStats()
 }
 
 // Clean 实现 ports.Sanitizer 接口
 // 返回的 clean 数据已按时间戳升序排列
 func (s *ChainSanitizer) Clean(readings []domain.Reading) ([]domain.Reading, []domain.QuarantineReading) {
+	return s.cleanSeeded(readings, nil)
+}
+
+// cleanSeeded 与 Clean 相同，但以 history (ChannelID -> 历史最后一条有效读数) 作为各计量通道第一条读数的 Previous
+func (s *ChainSanitizer) cleanSeeded(readings []domain.Reading, history map[string]domain.Reading) ([]domain.Reading, []domain.QuarantineReading) {
 	if len(readings) == 0 {
 		return nil, nil
 	}
 
-	// 1. 预处理：时间排序
-	sort.Slice(readings, func(i, j int) bool {
+	// 1. 预处理：时间排序 (稳定排序，同一时间戳保持到达顺序，供去重策略使用)
+	sort.SliceStable(readings, func(i, j int) bool {
 		return readings[i].Timestamp.Before(readings[j].Timestamp)
 	})
 
-	var clean []domain.Reading
-	var quarantined []domain.QuarantineReading
-	var prev *domain.Reading
-
-	for _, curr := range readings {
-		// 0. 内置规则: 同设备下的时间戳去重
-		if prev != nil && prev.DeviceInfo.ID == curr.DeviceInfo.ID && prev.Timestamp.Equal(curr.Timestamp) {
-			// 重复数据视为 Dirty Data? 或者只是 Drop?
-			// 策略：视为 Duplicate Error，进入 Quarantine
-			q := domain.QuarantineReading{
-				Reading:   curr,
-				Status:    domain.QuarantineStatusPending,
-				Reason:    "Duplicate timestamp",
-				CreatedAt: time.Now(),
-			}
-			quarantined = append(quarantined, q)
-			continue
-		}
+	// 2. 内置规则: 同设备下的时间戳去重
+	readings, quarantined := s.dedupe(readings)
+
+	// 预计算前瞻窗口：每条读数对应的同一计量通道下一条读数
+	next := lookahead(readings)
 
-		// 执行规则链
-		passed := true
-		failReason := ""
+	clean := make([]domain.Reading, 0, len(readings))
+	// 每个计量通道最近一条通过清洗的读数 (规则上下文中的 Previous 不跨设备、不跨通道)
+	lastClean := make(map[string]domain.Reading, len(history))
+	for id, r := range history {
+		lastClean[id] = r
+	}
+
+	for i, curr := range readings {
+		var prev *domain.Reading
+		if last, ok := lastClean[curr.ChannelID()]; ok {
+			prev = &last
+		}
 
 		// 构建清洗上下文
 		cleanCtx := ports.CleaningContext{
 			Previous: prev,
+			Next:     next[i],
+		}
+
+		result, q := s.check(cleanCtx, curr)
+		if q != nil {
+			quarantined = append(quarantined, *q)
+			continue
+		}
+		clean = append(clean, result)
+		// 注意：记录的是已经进入 clean 列表的、可能被修正过的最终值
+		lastClean[curr.ChannelID()] = result
+	}
+	return clean, quarantined
+}
+
+// check 对单条读数执行规则链
+// 通过 (可能被修正) 时返回最终读数；被拒绝时返回隔离记录
+func (s *ChainSanitizer) check(cleanCtx ports.CleaningContext, curr domain.Reading) (domain.Reading, *domain.QuarantineReading) {
+	passed := true
+	confidence := 1.0
+	failReason := ""
+	failRuleID := ""
+	failCode := domain.QuarantineCodeRuleRejected
+
+	// 每次进入规则检查时，使用当前的 curr 副本
+	// 这样不同规则可以像流水线一样依次修改数据 (Pipe and Filter)
+	tempReading := curr
+
+	for _, rule := range s.rules {
+		result := rule.Check(cleanCtx, tempReading)
+		if result.RuleID == "" {
+			result.RuleID = ruleID(rule)
+		}
+		s.metrics.Record(curr.DeviceInfo.Type, result)
+
+		if !result.Passed {
+			passed = false
+			failReason = result.Reason
+			failRuleID = result.RuleID
+			break
+		}
+		// 将这一步可能修正过的结果传递给下一个规则，置信度逐条相乘
+		confidence *= resultConfidence(result)
+		tempReading = result.Reading
+		if result.Corrected {
+			tempReading.MarkCorrected(result.Reason)
+		}
+	}
+
+	// 内置规则: 规则链结束后仍为缺失值的读数不允许进入下游
+	if passed && tempReading.IsMissing() {
+		passed = false
+		failReason = "Missing value not imputed"
+		failCode = domain.QuarantineCodeMissingValue
+	}
+
+	if !passed {
+		// 只有 REJECT 的才进入这里 (CleanRule内如果自动更正则会返回ok=true)
+		return curr, &domain.QuarantineReading{
+			Reading:   curr,
+			Status:    domain.QuarantineStatusPending,
+			Reason:    failReason,
+			Code:      failCode,
+			RuleID:    failRuleID,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	if tempReading.Confidence > 0 {
+		confidence *= tempReading.Confidence // 保留上游 (如去重合并) 已评估的置信度
+	}
+	tempReading.Confidence = confidence
+	return tempReading, nil
+}
+
+// lookahead 返回每条读数对应的同一计量通道下一条读数 (不存在则为 nil)
+// 前提: readings 已按时间排序
+func lookahead(readings []domain.Reading) []*domain.Reading {
+	next := make([]*domain.Reading, len(readings))
+	lastSeen := make(map[string]int)
+	for i := len(readings) - 1; i >= 0; i-- {
+		id := readings[i].ChannelID()
+		if j, ok := lastSeen[id]; ok {
+			next[i] = &readings[j]
+		}
+		lastSeen[id] = i
+	}
+	return next
+}
+
+// dedupe 按 duplicatePolicy 处理同一计量通道同时间戳的重复读数
+// 前提: readings 已按时间稳定排序；返回去重后的读数 (保持时间顺序) 与被丢弃的重复读数
+func (s *ChainSanitizer) dedupe(readings []domain.Reading) ([]domain.Reading, []domain.QuarantineReading) {
+	type dupKey struct {
+		channel  string
+		unixNano int64
+	}
+
+	groups := make(map[dupKey][]int)
+	order := make([]dupKey, 0, len(readings))
+	for i, r := range readings {
+		key := dupKey{channel: r.ChannelID(), unixNano: r.Timestamp.UnixNano()}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
 		}
+		groups[key] = append(groups[key], i)
+	}
+	if len(order) == len(readings) {
+		return readings, nil
+	}
 
-		// 每次进入规则检查时，使用当前的 curr 副本
-		// 这样不同规则可以像流水线一样依次修改数据 (Pipe and Filter)
-		tempReading := curr
+	kept := make([]domain.Reading, 0, len(order))
+	var dropped []domain.QuarantineReading
+	for _, key := range order {
+		idxs := groups[key]
+		if len(idxs) == 1 {
+			kept = append(kept, readings[idxs[0]])
+			continue
+		}
 
-		for _, rule := range s.rules {
-			result := rule.Check(cleanCtx, tempReading)
-			if !result.Passed {
-				passed = false
-				failReason = result.Reason
-				break
+		winner := idxs[0]
+		switch s.duplicatePolicy {
+		case domain.DuplicateKeepLast:
+			winner = idxs[len(idxs)-1]
+		case domain.DuplicateKeepHighestPrio:
+			for _, i := range idxs[1:] {
+				if readings[i].Priority > readings[winner].Priority {
+					winner = i
+				}
 			}
-			// 将这一步可能修正过的结果传递给下一个规则
-			tempReading = result.Reading
+		case domain.DuplicateAverage:
+			kept = append(kept, averageDuplicates(readings, idxs))
+			continue
 		}
 
-		if passed {
-			clean = append(clean, tempReading)
-			// 注意：prev 指向的是已经进入 clean 列表的、可能被修正过的最终值
-			prev = &clean[len(clean)-1]
-		} else {
-			// 只有 REJECT 的才进入这里 (CleanRule内如果自动更正则会返回ok=true)
-			q := domain.QuarantineReading{
-				Reading:   curr,
+		kept = append(kept, readings[winner])
+		for _, i := range idxs {
+			if i == winner {
+				continue
+			}
+			dropped = append(dropped, domain.QuarantineReading{
+				Reading:   readings[i],
 				Status:    domain.QuarantineStatusPending,
-				Reason:    failReason,
+				Reason:    "Duplicate timestamp",
+				Code:      domain.QuarantineCodeDuplicate,
 				CreatedAt: time.Now(),
-			}
-			quarantined = append(quarantined, q)
+			})
 		}
 	}
-	return clean, quarantined
+	return kept, dropped
+}
+
+// averageDuplicates 将重复读数合并为平均值 (忽略缺失值)
+// 各副本取值不一致时，合并结果标记为 CORRECTED
+func averageDuplicates(readings []domain.Reading, idxs []int) domain.Reading {
+	merged := readings[idxs[0]]
+	var sum, first float64
+	var n int
+	differs := false
+	for _, i := range idxs {
+		r := readings[i]
+		if r.IsMissing() {
+			continue
+		}
+		if n == 0 {
+			first = r.Value
+		} else if r.Value != first {
+			differs = true
+		}
+		sum += r.Value
+		n++
+		if r.Priority > merged.Priority {
+			merged.Priority = r.Priority
+		}
+	}
+	if n == 0 {
+		return merged
+	}
+	merged.Value = sum / float64(n)
+	merged.Quality = ""
+	merged.QualityReason = ""
+	if differs {
+		merged.MarkCorrected("Averaged duplicate timestamps")
+	}
+	return merged
+}
+
+// resultConfidence 返回单条规则结果的置信度，规则未给出时取默认值
+func resultConfidence(result ports.CheckResult) float64 {
+	switch {
+	case result.Confidence > 0:
+		return min(result.Confidence, 1)
+	case result.Corrected:
+		return ports.DefaultCorrectedConfidence
+	default:
+		return 1
+	}
 }
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// scaleSafeBound 浮点估算值低于该界限 (2^62) 时换算结果必然落在 int64 范围内
+const scaleSafeBound = 1 << 62
+
+// guardScale 将无法安全换算为定点整数的读数移入隔离区
+// 精度因子不合法 (见 domain.ValidateScaleFactor)，或校准后的值 × 精度因子超出 int64 范围时，
+// 与其饱和为边界值写入标准库，不如隔离待查 (常见于 Wh 数据被当作 kWh 上报)。
+// 返回的有效读数复用 readings 的底层数组。
+func (s *CoreStandardizer) guardScale(readings []domain.Reading) ([]domain.Reading, []domain.QuarantineReading) {
+	kept := readings[:0]
+	var rejected []domain.QuarantineReading
+	for _, r := range readings {
+		if err := s.checkScale(r); err != nil {
+			code := domain.QuarantineCodeScaleOverflow
+			if errors.Is(err, domain.ErrInvalidScaleFactor) {
+				code = domain.QuarantineCodeInvalidFactor
+			}
+			rejected = append(rejected, domain.QuarantineReading{
+				Reading:   r,
+				Status:    domain.QuarantineStatusPending,
+				Reason:    err.Error(),
+				Code:      code,
+				CreatedAt: time.Now(),
+			})
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept, rejected
+}
+
+// checkScale 校验单条读数能否换算为定点整数
+func (s *CoreStandardizer) checkScale(r domain.Reading) error {
+	factor := s.scaleFactorFor(r.DeviceInfo.Type)
+	if err := domain.ValidateScaleFactor(factor); err != nil {
+		return fmt.Errorf("device type %s: %w", r.DeviceInfo.Type, err)
+	}
+	value, _ := calibrate(r)
+	if math.Abs(value)*float64(factor) < scaleSafeBound {
+		return nil // 远离 int64 边界，无需精确换算
+	}
+	if _, err := domain.ScaleDecimalChecked(value, factor, s.rounding); err != nil {
+		return err
+	}
+	return nil
+}
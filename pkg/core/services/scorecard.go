@@ -0,0 +1,219 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// ScorecardQuery 数据质量评分卡的统计条件
+type ScorecardQuery struct {
+	Devices    []domain.DeviceInfo // 设备 (ID 必填；按 Type 汇总设备类型的评分)
+	Metric     domain.Metric       // 为空表示设备的默认通道
+	Resolution string              // 参与统计的标准读数分辨率 (必填)
+	Period     domain.ReportPeriod // 趋势的统计周期 (默认 WEEK)
+	Start, End time.Time           // 时间区间 [Start, End)，按统计周期向外取整
+	Location   *time.Location      // 周期切分所用时区 (为空表示 UTC)
+}
+
+// QualityCounts 一组读数的质量计数
+type QualityCounts struct {
+	Valid        int     `json:"valid"`        // VALID 标准读数条数
+	Corrected    int     `json:"corrected"`    // 经规则修正 (CORRECTED) 的标准读数条数
+	Interpolated int     `json:"interpolated"` // 插值或估算 (INTERPOLATED / ESTIMATED) 的标准读数条数
+	Missing      int     `json:"missing"`      // 缺口占位 (MISSING) 的标准读数条数
+	Withdrawn    int     `json:"withdrawn"`    // 已撤回的标准读数条数 (不计入得分)
+	Quarantined  int     `json:"quarantined"`  // 被隔离的原始读数条数 (任意处理状态)
+	Score        float64 `json:"score"`        // 质量得分 (百分比): Valid / (Valid + Corrected + Interpolated + Missing + Quarantined) x 100，无数据时为 0
+}
+
+func (c *QualityCounts) add(o QualityCounts) {
+	c.Valid += o.Valid
+	c.Corrected += o.Corrected
+	c.Interpolated += o.Interpolated
+	c.Missing += o.Missing
+	c.Withdrawn += o.Withdrawn
+	c.Quarantined += o.Quarantined
+}
+
+func (c *QualityCounts) score() {
+	c.Score = 0
+	if total := c.Valid + c.Corrected + c.Interpolated + c.Missing + c.Quarantined; total > 0 {
+		c.Score = float64(c.Valid) / float64(total) * 100
+	}
+}
+
+// ScorecardPeriod 一个统计周期的质量计数
+type ScorecardPeriod struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	QualityCounts
+}
+
+// ScorecardEntry 一台设备或一个设备类型的评分
+type ScorecardEntry struct {
+	Key     string            `json:"key"`     // 设备ID或设备类型
+	Devices int               `json:"devices"` // 包含的设备数
+	Total   QualityCounts     `json:"total"`   // 整个区间的合计
+	Trend   []ScorecardPeriod `json:"trend"`   // 各统计周期 (按时间升序，包含没有数据的周期)
+}
+
+// QualityScorecard 数据质量评分卡
+type QualityScorecard struct {
+	Period   domain.ReportPeriod `json:"period"`
+	Location string              `json:"location"`
+	Overall  ScorecardEntry      `json:"overall"` // 全部设备 (Key 为空)
+	Types    []ScorecardEntry    `json:"types"`   // 按设备类型，得分升序 (相同时按类型)
+	Devices  []ScorecardEntry    `json:"devices"` // 按设备，得分升序 (相同时按设备ID)
+}
+
+// BuildQualityScorecard 按设备、设备类型与统计周期汇总标准读数的质量标记与隔离记录，生成数据质量评分卡
+// 数据管理员据此查看每周的质量趋势，而不必逐条翻阅隔离区；得分最低的设备与类型排在最前。
+// 标准读数按时间戳、隔离记录按原始读数时间戳归入周期；quarantine 为 nil 时不统计隔离记录。
+func BuildQualityScorecard(ctx context.Context, standard ports.StandardReadingRepository, quarantine ports.QuarantineRepository, q ScorecardQuery) (QualityScorecard, error) {
+	if len(q.Devices) == 0 {
+		return QualityScorecard{}, errors.New("scorecard query: devices are required")
+	}
+	if q.Resolution == "" {
+		return QualityScorecard{}, errors.New("scorecard query: resolution is required")
+	}
+	period := cmp.Or(q.Period, domain.ReportPeriodWeek)
+	if !period.Valid() {
+		return QualityScorecard{}, fmt.Errorf("scorecard query: invalid period %q", period)
+	}
+	if !q.End.After(q.Start) {
+		return QualityScorecard{}, fmt.Errorf("scorecard query: end %s must be after start %s", q.End.Format(time.RFC3339), q.Start.Format(time.RFC3339))
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	start := period.Start(q.Start, loc)
+	end := period.Next(period.Start(q.End.Add(-time.Nanosecond), loc), loc)
+	var bounds []time.Time
+	for ps := start; ps.Before(end); ps = period.Next(ps, loc) {
+		bounds = append(bounds, ps)
+	}
+	bounds = append(bounds, end)
+	newTrend := func() []ScorecardPeriod {
+		trend := make([]ScorecardPeriod, len(bounds)-1)
+		for i := range trend {
+			trend[i] = ScorecardPeriod{Start: bounds[i], End: bounds[i+1]}
+		}
+		return trend
+	}
+	// slot 返回时刻所在周期的下标 (bounds 升序)
+	slot := func(t time.Time) int {
+		i, _ := slices.BinarySearchFunc(bounds, t, func(b, t time.Time) int {
+			if b.After(t) {
+				return 1
+			}
+			return -1
+		})
+		return i - 1
+	}
+
+	devices := make([]domain.DeviceInfo, 0, len(q.Devices))
+	ids := make([]string, 0, len(q.Devices))
+	for _, d := range q.Devices {
+		if d.ID == "" {
+			return QualityScorecard{}, errors.New("scorecard query: device id is required")
+		}
+		if !slices.Contains(ids, d.ID) { // 重复的设备只计一次
+			ids = append(ids, d.ID)
+			devices = append(devices, d)
+		}
+	}
+	byDevice, err := FindRangeMulti(ctx, standard, ids, start, end.Add(-time.Nanosecond)) // FindRange 为闭区间
+	if err != nil {
+		return QualityScorecard{}, fmt.Errorf("quality scorecard: %w", err)
+	}
+
+	card := QualityScorecard{Period: period, Location: loc.String(), Overall: ScorecardEntry{Devices: len(devices), Trend: newTrend()}}
+	types := make(map[domain.DeviceType]*ScorecardEntry)
+	for _, d := range devices {
+		if err := ctx.Err(); err != nil {
+			return QualityScorecard{}, err
+		}
+		entry := ScorecardEntry{Key: d.ID, Devices: 1, Trend: newTrend()}
+		for _, sr := range byDevice[d.ID] {
+			if sr.Metric != q.Metric || sr.Resolution != q.Resolution {
+				continue
+			}
+			i := slot(sr.Timestamp)
+			if i < 0 || i >= len(entry.Trend) {
+				continue
+			}
+			c := &entry.Trend[i].QualityCounts
+			switch sr.Quality {
+			case domain.QualityCorrected:
+				c.Corrected++
+			case domain.QualityInterpolated, domain.QualityEstimated:
+				c.Interpolated++
+			case domain.QualityMissing:
+				c.Missing++
+			case domain.QualityWithdrawn:
+				c.Withdrawn++
+			default:
+				c.Valid++
+			}
+		}
+		if quarantine != nil {
+			records, err := quarantine.FindByDevice(ctx, d.ID, start, end.Add(-time.Nanosecond))
+			if err != nil {
+				return QualityScorecard{}, fmt.Errorf("quality scorecard of %s: %w", d.ID, err)
+			}
+			for _, rec := range records {
+				if rec.Reading.Metric != q.Metric {
+					continue
+				}
+				if i := slot(rec.Reading.Timestamp); i >= 0 && i < len(entry.Trend) {
+					entry.Trend[i].Quarantined++
+				}
+			}
+		}
+
+		te := types[d.Type]
+		if te == nil {
+			te = &ScorecardEntry{Key: string(d.Type), Trend: newTrend()}
+			types[d.Type] = te
+		}
+		te.Devices++
+		for i, p := range entry.Trend {
+			te.Trend[i].add(p.QualityCounts)
+			card.Overall.Trend[i].add(p.QualityCounts)
+		}
+		card.Devices = append(card.Devices, entry)
+	}
+	for _, te := range types {
+		card.Types = append(card.Types, *te)
+	}
+
+	// finish 汇总整个区间的合计并计算各周期与合计的得分
+	finish := func(e *ScorecardEntry) {
+		for i := range e.Trend {
+			e.Trend[i].score()
+			e.Total.add(e.Trend[i].QualityCounts)
+		}
+		e.Total.score()
+	}
+	finish(&card.Overall)
+	for i := range card.Types {
+		finish(&card.Types[i])
+	}
+	for i := range card.Devices {
+		finish(&card.Devices[i])
+	}
+	byScore := func(a, b ScorecardEntry) int {
+		return cmp.Or(cmp.Compare(a.Total.Score, b.Total.Score), cmp.Compare(a.Key, b.Key))
+	}
+	slices.SortFunc(card.Types, byScore)
+	slices.SortFunc(card.Devices, byScore)
+	return card, nil
+}
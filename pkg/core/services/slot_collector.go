@@ -0,0 +1,61 @@
+package services
+
+import (
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+// slotCollector 在遍历时间网格时记录槽位事件: 把连续缺失的时间点合并为 DataGap，并收集优先级冲突
+type slotCollector struct {
+	deviceID  string
+	metric    domain.Metric
+	interval  time.Duration
+	open      *domain.DataGap
+	gaps      []domain.DataGap
+	conflicts []domain.PriorityConflict
+}
+
+func newSlotCollector(deviceID string, metric domain.Metric, interval time.Duration) *slotCollector {
+	return &slotCollector{deviceID: deviceID, metric: metric, interval: interval}
+}
+
+// miss 记录一个缺失的时间点 (nil 接收者为空操作)
+func (c *slotCollector) miss(t time.Time) {
+	if c == nil {
+		return
+	}
+	if c.open == nil {
+		c.open = &domain.DataGap{DeviceID: c.deviceID, Metric: c.metric, From: t, Interval: c.interval}
+	}
+	c.open.To = t
+	c.open.ExpectedCount++
+}
+
+// hit 记录一个有数据的时间点，结束当前缺口 (nil 接收者为空操作)
+func (c *slotCollector) hit() {
+	if c == nil || c.open == nil {
+		return
+	}
+	c.open.DetectedAt = time.Now()
+	c.gaps = append(c.gaps, *c.open)
+	c.open = nil
+}
+
+// conflict 记录槽位 t 上的优先级裁决 (nil 接收者为空操作)
+func (c *slotCollector) conflict(t time.Time, pc domain.PriorityConflict) {
+	if c == nil {
+		return
+	}
+	pc.DeviceID = c.deviceID
+	pc.Metric = c.metric
+	pc.Timestamp = t
+	pc.Resolution = domain.ResolutionTag(c.interval)
+	c.conflicts = append(c.conflicts, pc)
+}
+
+// done 结束遍历并返回全部缺口
+func (c *slotCollector) done() []domain.DataGap {
+	c.hit()
+	return c.gaps
+}
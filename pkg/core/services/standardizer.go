@@ -2,10 +2,11 @@ package services
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,13 +17,49 @@ import (
 // CoreStandardizer 核心数据标准化服务
 // 实现了 EnergyDataStandardizer 接口
 type CoreStandardizer struct {
-	sanitizer        ports.Sanitizer
-	aligner          ports.Aligner
+	sanitizer        *ChainSanitizer
+	staticRules      []ports.CleaningRule   // 静态注入的清洗规则 (未配置 ruleRepo 时使用)
+	duplicatePolicy  domain.DuplicatePolicy // 重复时间戳处理策略
+	aligner          ports.DirectionalAligner
+	snapshotMode     domain.SnapshotMode // 快照模式下的查找方式 (默认 NEAREST)
+	tolerance        time.Duration       // 对齐容差 (流式模式据此判断槽位何时可以输出)
 	standardInterval time.Duration
+	gridAnchor       time.Duration   // 网格点偏移 (如 5m: 15m 槽位落在 :05/:20/:35/:50)
+	resolutions      []time.Duration // 额外输出的分辨率 (与 standardInterval 一并输出)
+	aggregation      map[domain.DeviceType]domain.AggregationMode
+	metricAgg        map[domain.Metric]domain.AggregationMode
+	scaleFactor      int                             // 默认精度因子
+	typeScaleFactors map[domain.DeviceType]int       // 按设备类型覆盖的精度因子
+	rounding         domain.RoundingMode             // 定点换算舍入方式
 	concurrencyLimit int                             // 并发限制
 	repo             ports.StandardReadingRepository // 可选持久层依赖
+	rawRepo          ports.RawReadingRepository      // 可选原始读数仓储 (重新处理历史区间)
 	ruleRepo         ports.CleaningRuleRepository    // 可选规则持久层
 	quarantineRepo   ports.QuarantineRepository      // 可选隔离区持久层 (for Bad Data)
+	quarantinePolicy QuarantinePolicy                // 隔离区持久化策略
+	quarantine       *quarantineWriter               // 按策略保存隔离记录
+	ruleMetrics      *RuleMetrics                    // 规则触发计数器 (静态与动态规则共享)
+	metrics          ports.StandardizerMetrics       // 管道计数与阶段耗时输出
+	ruleCache        *ruleCache                      // 动态规则缓存 (按设备类型)
+	stopRuleWatch    context.CancelFunc              // 停止订阅规则变更 (未订阅时为 nil)
+	batches          inflight                        // 在途批次 (Drain / Shutdown 等待其完成)
+	clockSkew        *clockSkewCorrector             // 可选时钟偏移修正阶段
+	gapSink          ports.DataGapSink               // 可选数据缺口事件输出
+	gapFill          domain.GapFillStrategy          // 空槽位填充策略
+	location         *time.Location                  // 时间网格默认时区 (默认 UTC)
+	zoneCache        sync.Map                        // 设备时区缓存: name -> *time.Location
+	conflictResolver ports.ConflictResolver          // 可选自定义冲突裁决 (包装 repo)
+	events           ports.EventPublisher            // 可选变更事件输出
+	auditRepo        ports.AuditRepository           // 可选覆盖审计日志 (包装 repo)
+	approvals        *approvalGate                   // 可选人工校准审批 (见 approval.go)
+	authz            ports.Authorizer                // 可选管理操作授权 (见 authorization.go)
+	logger           *slog.Logger                    // 日志输出 (nil 使用 slog.Default())
+
+	// 管道钩子 (见 hooks.go)
+	preClean   []PreCleanHook
+	postClean  []PostCleanHook
+	preAlign   []PreAlignHook
+	prePersist []PrePersistHook
 }
 
 // StandardizerOption 定义配置选项函数 (Functional Option Pattern)
@@ -35,6 +72,14 @@ func WithQuarantineRepository(repo ports.QuarantineRepository) StandardizerOptio
 	}
 }
 
+// WithQuarantinePolicy 设置隔离区数据的持久化策略 (默认有界异步队列)
+// 异步模式下进程退出前应调用 Flush 或 Close，否则队列中的记录可能丢失
+func WithQuarantinePolicy(policy QuarantinePolicy) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.quarantinePolicy = policy
+	}
+}
+
 // WithRuleRepository 设置规则持久层依赖
 func WithRuleRepository(repo ports.CleaningRuleRepository) StandardizerOption {
 	return func(s *CoreStandardizer) {
@@ -42,11 +87,100 @@ func WithRuleRepository(repo ports.CleaningRuleRepository) StandardizerOption {
 	}
 }
 
+// WithResolutions 在一次处理中同时输出多个分辨率的标准读数 (如 15m, 1h, 1d)
+// 标准间隔 (WithAlignment) 始终输出；每条标准读数通过 Resolution 字段区分粒度。
+// 原始数据只需读取和清洗一次。
+func WithResolutions(intervals ...time.Duration) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.resolutions = intervals
+	}
+}
+
+// WithAggregation 设置某设备类型的槽位取值方式 (默认 SNAPSHOT 最近邻快照)
+// 非快照模式下，槽位 t 的值由区间 [t, t+interval) 内的读数聚合而来，
+// 例如区间电量表用 SUM，仪表类瞬时量用 MEAN。
+func WithAggregation(deviceType domain.DeviceType, mode domain.AggregationMode) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		if s.aggregation == nil {
+			s.aggregation = make(map[domain.DeviceType]domain.AggregationMode)
+		}
+		s.aggregation[deviceType] = mode
+	}
+}
+
+// WithMetricAggregation 设置某计量通道的槽位取值方式，优先于 WithAggregation 的设备类型配置
+// 例如同一电表的 ENERGY 通道取快照，POWER 通道取区间均值 (MEAN)
+func WithMetricAggregation(metric domain.Metric, mode domain.AggregationMode) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		if s.metricAgg == nil {
+			s.metricAgg = make(map[domain.Metric]domain.AggregationMode)
+		}
+		s.metricAgg[metric] = mode
+	}
+}
+
+// WithPrecision 设置默认精度因子 (默认 DefaultScaleFactor，即 4 位小数)
+func WithPrecision(factor int) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		if factor > 0 {
+			s.scaleFactor = factor
+		}
+	}
+}
+
+// WithDeviceTypePrecision 为某设备类型单独设置精度因子
+// 例如燃气表通常只需 3 位小数 (1000)，电表为 4 位 (10000)
+func WithDeviceTypePrecision(deviceType domain.DeviceType, factor int) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		if factor <= 0 {
+			return
+		}
+		if s.typeScaleFactors == nil {
+			s.typeScaleFactors = make(map[domain.DeviceType]int)
+		}
+		s.typeScaleFactors[deviceType] = factor
+	}
+}
+
+// WithRoundingMode 设置定点换算的舍入方式 (默认 HALF_UP)
+// 部分地区的计量法规要求使用银行家舍入 (HALF_EVEN)
+func WithRoundingMode(mode domain.RoundingMode) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.rounding = mode
+	}
+}
+
+// WithRuleCacheTTL 设置动态规则缓存有效期 (默认 30s，<= 0 表示禁用缓存)
+func WithRuleCacheTTL(ttl time.Duration) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.ruleCache = newRuleCache(ttl)
+	}
+}
+
 // WithAlignment 设置时间对齐参数 (默认 15m, 5m)
 func WithAlignment(interval, tolerance time.Duration) StandardizerOption {
 	return func(s *CoreStandardizer) {
 		s.standardInterval = interval
 		s.aligner = domain.NewAligner(tolerance)
+		s.tolerance = tolerance
+	}
+}
+
+// WithSnapshotMode 设置快照对齐的查找方式 (默认 NEAREST)
+// 计费场景应使用 AT_OR_BEFORE: 槽位只取不晚于网格时间的最后一条读数，避免把未来的用量归入过去的槽位；
+// 变化较快的表计可使用 INTERPOLATE: 网格点前后的读数均在容差内时线性插值 (标记为 INTERPOLATED)
+func WithSnapshotMode(mode domain.SnapshotMode) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.snapshotMode = mode
+	}
+}
+
+// WithGridAnchor 设置时间网格的锚点偏移 (默认 0，即 :00/:15/:30/:45)
+// 适用于抄表时间固定偏离整点的老旧表计，例如 offset=5m 时 15m 槽位为 :05/:20/:35/:50；
+// 偏移对全部输出分辨率生效 (按各自间隔取模)。
+func WithGridAnchor(offset time.Duration) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.gridAnchor = offset
 	}
 }
 
@@ -57,10 +191,73 @@ func WithRepository(repo ports.StandardReadingRepository) StandardizerOption {
 	}
 }
 
+// WithConflictResolver 设置自定义冲突裁决 (如 "优先保留校准数据，除非已超过 90 天")
+// 写入标准读数前读取同键的已有数据交给 resolver 裁决；返回 ports.ConflictUseStrategy 时按本次处理的 UpsertStrategy 裁决
+func WithConflictResolver(resolver ports.ConflictResolver) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.conflictResolver = resolver
+	}
+}
+
+// WithAuditRepository 记录标准读数的覆盖审计 (旧值、新值、优先级、操作人与时间)
+// 每次写入前额外读取一次同键的已有数据；只记录值、精度、质量或优先级实际变化的覆盖
+func WithAuditRepository(audit ports.AuditRepository) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.auditRepo = audit
+	}
+}
+
+// WithEventPublisher 设置事件输出 (可传入 EventBus): 标准读数持久化成功后发出 StandardReadingSaved，
+// 隔离记录保存成功后发出 QuarantineCreated；管道事件 ReadingQuarantined、BatchStandardized、PersistFailed 在对应环节发出
+func WithEventPublisher(publisher ports.EventPublisher) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.events = publisher
+	}
+}
+
 // WithCleaningRules 设置清洗规则
 func WithCleaningRules(rules ...ports.CleaningRule) StandardizerOption {
 	return func(s *CoreStandardizer) {
-		s.sanitizer = NewSanitizer(rules...)
+		s.staticRules = rules
+	}
+}
+
+// WithDuplicatePolicy 设置同设备同时间戳重复读数的处理策略 (默认 KEEP_FIRST)
+func WithDuplicatePolicy(policy domain.DuplicatePolicy) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.duplicatePolicy = policy
+	}
+}
+
+// WithClockSkewCorrection 启用时钟偏移修正阶段 (默认关闭)
+// 单台设备可通过 ClockSkewTag 标签关闭修正或指定固定偏移
+func WithClockSkewCorrection(cfg ClockSkewConfig) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.clockSkew = newClockSkewCorrector(cfg)
+	}
+}
+
+// WithGapSink 设置数据缺口事件输出端口
+// 配置后，标准化过程中发现的缺失时间点会汇总为 DataGap 上报
+func WithGapSink(sink ports.DataGapSink) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.gapSink = sink
+	}
+}
+
+// WithGapFill 设置时间网格空槽位的填充策略 (默认 NONE)
+// 填充产生的标准读数标记为 INTERPOLATED；缺口事件仍会照常上报
+func WithGapFill(strategy domain.GapFillStrategy) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.gapFill = strategy
+	}
+}
+
+// WithLocation 设置时间网格的默认时区 (默认 UTC)
+// 优先级: 设备 Timezone > IngestContext.Location > 本选项
+func WithLocation(loc *time.Location) StandardizerOption {
+	return func(s *CoreStandardizer) {
+		s.location = loc
 	}
 }
 
@@ -78,11 +275,21 @@ func WithConcurrencyLimit(limit int) StandardizerOption {
 func NewCoreStandardizer(opts ...StandardizerOption) ports.EnergyDataStandardizer {
 	// 默认配置
 	s := &CoreStandardizer{
-		sanitizer:        NewSanitizer(),                 // 默认无规则
+		duplicatePolicy:  domain.DuplicateKeepFirst,      // 默认保留先到达的读数
+		gapFill:          domain.GapFillNone,             // 默认不填充空槽位
+		location:         time.UTC,                       // 默认按 UTC 对齐
 		aligner:          domain.NewAligner(time.Minute), // 默认容差 1m
+		snapshotMode:     domain.SnapshotNearest,         // 默认最近邻
+		tolerance:        time.Minute,                    // 与 aligner 容差保持一致
 		standardInterval: 15 * time.Minute,               // 默认间隔 15m
 		concurrencyLimit: 100,                            // 默认并发 100
+		scaleFactor:      DefaultScaleFactor,             // 默认 4 位小数
+		rounding:         domain.RoundHalfUp,             // 默认四舍五入
+		quarantinePolicy: DefaultQuarantinePolicy(),      // 默认有界异步队列
 		repo:             nil,
+		ruleMetrics:      NewRuleMetrics(),
+		metrics:          noopMetrics{},
+		ruleCache:        newRuleCache(DefaultRuleCacheTTL),
 	}
 
 	// 应用选项
@@ -90,6 +297,22 @@ func NewCoreStandardizer(opts ...StandardizerOption) ports.EnergyDataStandardize
 		opt(s)
 	}
 
+	// 默认无规则；规则与去重策略均可能由选项配置，统一在选项应用后构建
+	s.sanitizer = newChainSanitizer(s.ruleMetrics, s.duplicatePolicy, s.staticRules...)
+	s.quarantine = newQuarantineWriter(s.quarantineRepo, s.quarantinePolicy)
+	s.quarantine.events = s.events
+	s.quarantine.logger = s.logger
+	// 审计在裁决之内: 裁决后的胜者以 LAST_WRITE_WINS 写入，审计看到的是实际写入的数据
+	if s.repo != nil && s.auditRepo != nil {
+		s.repo = &auditingRepository{StandardReadingRepository: s.repo, audit: s.auditRepo, logger: s.logger}
+	}
+	if s.repo != nil && s.conflictResolver != nil {
+		s.repo = &resolvingRepository{StandardReadingRepository: s.repo, resolver: s.conflictResolver}
+	}
+	if s.ruleRepo != nil {
+		s.watchRules()
+	}
+
 	return s
 }
 
@@ -103,17 +326,85 @@ func (s *CoreStandardizer) GetStandardReading(ctx context.Context, deviceID stri
 	return s.repo.FindExact(ctx, deviceID, timestamp)
 }
 
-func (s *CoreStandardizer) ProcessAndStandardize(ctx context.Context, rawReadings []domain.Reading) ([]domain.StandardReading, error) {
+// RuleStats 实现 ports.RuleStatsProvider
+// 返回自服务创建以来各规则 (按设备类型) 的检查/通过/修正/拒绝次数
+func (s *CoreStandardizer) RuleStats() []ports.RuleStats {
+	return s.ruleMetrics.RuleStats()
+}
+
+// Flush 等待已入队的隔离记录全部保存 (仅异步模式下需要)
+func (s *CoreStandardizer) Flush(ctx context.Context) error {
+	return s.quarantine.flush(ctx)
+}
+
+// InvalidateRules 使动态规则缓存失效，下一批次将重新从 CleaningRuleRepository 加载
+// 不指定设备类型时清空全部缓存；规则变更后应调用此方法使其立即生效
+func (s *CoreStandardizer) InvalidateRules(deviceTypes ...domain.DeviceType) {
+	s.ruleCache.invalidate(deviceTypes...)
+}
+
+func (s *CoreStandardizer) ProcessAndStandardize(ctx context.Context, rawReadings []domain.Reading) (*domain.StandardizationResult, error) {
+	if err := s.authorizeIngest(ctx); err != nil {
+		return nil, err
+	}
+	if parked, err := s.parkForApproval(ctx, rawReadings); parked != nil || err != nil {
+		return parked, err
+	}
+	// Use Priority-based upsert strategy to respect data governance rules
+	return s.process(ctx, rawReadings, processOptions{strategy: ports.UpsertStrategyHighPriorityWins, archive: true})
+}
+
+// processOptions 单次处理的持久化参数
+type processOptions struct {
+	strategy ports.UpsertStrategy              // 持久化冲突策略
+	keep     func(domain.StandardReading) bool // 可选: 过滤需要输出与持久化的标准读数
+	archive  bool                              // 归档原始读数 (配置了 WithRawRepository 时)；重新处理的数据本就来自归档，无需再次写入
+}
+
+// process 标准化主流程: 清洗 -> 隔离 -> 对齐 -> 持久化
+func (s *CoreStandardizer) process(ctx context.Context, rawReadings []domain.Reading, opts processOptions) (*domain.StandardizationResult, error) {
+	if !s.batches.begin() {
+		return nil, ErrStandardizerClosed
+	}
+	defer s.batches.done()
+	// 租户隔离: 批次只能属于一个租户，之后的查询与持久化都在该租户内进行
+	ctx, err := domain.ResolveTenant(ctx, rawReadings)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	defer s.observeSince(ports.StageTotal, start)
+	s.metrics.AddReadings(ports.CounterReadingsIn, len(rawReadings))
+	if err := s.archiveRaw(ctx, rawReadings, opts); err != nil {
+		return nil, err
+	}
+	cleanStart := time.Now()
+
+	// Step 0: 可选的时钟偏移修正 (需在清洗之前，清洗依赖时间顺序)
+	if s.clockSkew != nil {
+		s.clockSkew.Correct(ctx, rawReadings)
+	}
+
+	rawReadings, err = s.runPreClean(ctx, rawReadings)
+	if err != nil {
+		return nil, err
+	}
+	// 读数级优先级缺省时取批次策略优先级，去重与对齐时先按优先级裁决
+	rawReadings = applyIngestPriority(ctx, rawReadings)
+	rawReadings, outranked := dropOutranked(rawReadings)
+
 	// Step 1: A. 数据清洗 (替别人做“脏活累活”)
 	// 剔除空值、负值、重复值和异常跳变
 	// 这一步是批量操作，因为清洗依赖上下文（如前后值的跳变）
 	var cleanReadings []domain.Reading
 	var quarantinedReadings []domain.QuarantineReading
 
+	// 配置了持久层时，以各设备已持久化的最后一条标准读数作为清洗上下文的起点
+	history := s.loadHistory(ctx, rawReadings)
+
 	if s.ruleRepo != nil {
 		// 动态加载规则清洗
-		var err error
-		cleanReadings, quarantinedReadings, err = s.cleanWithDynamicRules(ctx, rawReadings)
+		cleanReadings, quarantinedReadings, err = s.cleanWithDynamicRules(ctx, rawReadings, history)
 		if err != nil {
 			// Fallback or error? For now log and return partial?
 			// To be safe, return error
@@ -121,146 +412,520 @@ func (s *CoreStandardizer) ProcessAndStandardize(ctx context.Context, rawReading
 		}
 	} else {
 		// 使用默认规则清洗
-		cleanReadings, quarantinedReadings = s.sanitizer.Clean(rawReadings)
-	}
-
-	// 异步保存隔离区数据 (以免阻塞主流程)
-	if len(quarantinedReadings) > 0 && s.quarantineRepo != nil {
-		go func(qs []domain.QuarantineReading) {
-			// 使用带超时的上下文，避免无限阻塞
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
-
-			for _, q := range qs {
-				if err := s.quarantineRepo.Save(ctx, q); err != nil {
-					slog.Error("failed to save quarantine reading",
-						"device_id", q.Reading.DeviceInfo.ID,
-						"timestamp", q.Reading.Timestamp,
-						"reason", q.Reason,
-						"error", err)
-				}
-			}
-		}(quarantinedReadings)
+		cleanReadings, quarantinedReadings = s.sanitizer.cleanSeeded(rawReadings, history)
+	}
+
+	quarantinedReadings = append(outranked, quarantinedReadings...)
+	cleanReadings, quarantinedReadings, err = s.runPostClean(ctx, cleanReadings, quarantinedReadings)
+	if err != nil {
+		return nil, err
+	}
+	cleanReadings, overflow := s.guardScale(cleanReadings)
+	quarantinedReadings = append(quarantinedReadings, overflow...)
+	s.observeSince(ports.StageClean, cleanStart)
+	s.metrics.AddReadings(ports.CounterCleaned, len(cleanReadings))
+	s.metrics.AddReadings(ports.CounterQuarantined, len(quarantinedReadings))
+
+	// 按策略保存隔离区数据 (默认异步入队，以免阻塞主流程)
+	if err := s.quarantine.write(ctx, quarantinedReadings); err != nil {
+		return nil, fmt.Errorf("failed to persist quarantine: %w", err)
+	}
+
+	result, err := s.alignAndPersist(ctx, cleanReadings, opts)
+	if err != nil {
+		return nil, err
 	}
+	if s.events == nil {
+		return result, nil
+	}
+	inputs := make(map[string]int)
+	for _, r := range rawReadings {
+		inputs[r.DeviceInfo.ID]++
+	}
+	publishEvent(ctx, s.logger, s.events, ports.BatchStandardized{
+		Input:        len(rawReadings),
+		DeviceInputs: inputs,
+		Quarantined:  len(quarantinedReadings),
+		Readings:     result.Readings,
+		DeviceErrors: result.DeviceErrors,
+		Elapsed:      time.Since(start),
+		OccurredAt:   time.Now(),
+	})
+	return result, nil
+}
 
+// alignAndPersist 对已清洗的读数做对齐、排序并持久化 (主流程的后半段)
+func (s *CoreStandardizer) alignAndPersist(ctx context.Context, cleanReadings []domain.Reading, opts processOptions) (*domain.StandardizationResult, error) {
 	// Step 3 (Optimization): Concurrency Strategy (Sharding by DeviceID)
-	deviceGroups := make(map[string][]domain.Reading)
-	for _, r := range cleanReadings {
-		deviceGroups[r.DeviceInfo.ID] = append(deviceGroups[r.DeviceInfo.ID], r)
+	alignStart := time.Now()
+	out, deviceErrors, err := s.standardizeGroups(ctx, groupByDevice(cleanReadings))
+	if err != nil {
+		return nil, err
+	}
+	standards, gaps := out.standards, out.gaps
+	s.observeSince(ports.StageAlign, alignStart)
+	s.metrics.AddReadings(ports.CounterAligned, len(standards))
+	for id, devErr := range deviceErrors {
+		loggerOr(s.logger).Error("failed to standardize device", "device_id", id, "error", devErr)
 	}
 
-	var standards []domain.StandardReading
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(deviceGroups))
+	// 上报数据缺口 (失败不影响标准化结果)
+	if len(gaps) > 0 && s.gapSink != nil {
+		if err := s.gapSink.ReportGaps(ctx, gaps); err != nil {
+			loggerOr(s.logger).Error("failed to report data gaps", "count", len(gaps), "error", err)
+		}
+	}
 
-	// Semaphore for bounded concurrency
-	sem := make(chan struct{}, s.concurrencyLimit)
+	if opts.keep != nil {
+		standards = slices.DeleteFunc(standards, func(sr domain.StandardReading) bool { return !opts.keep(sr) })
+	}
+	stampProvenance(ctx, standards)
 
-	for _, readings := range deviceGroups {
-		wg.Add(1)
-		sem <- struct{}{} // Acquire token
+	// 输出顺序与 worker 完成顺序无关: 按 (DeviceID, Timestamp) 排序，
+	// 同一时间点的多分辨率读数保持标准间隔在前
+	sortStandards(standards)
 
-		go func(devReadings []domain.Reading) {
-			defer wg.Done()
-			defer func() { <-sem }() // Release token
+	standards, err = s.runPrePersist(ctx, standards)
+	if err != nil {
+		return nil, err
+	}
 
-			if len(devReadings) == 0 {
-				return
-			}
+	// Step 3: Persistence (if configured)
+	if s.repo != nil && len(standards) > 0 {
+		persistStart := time.Now()
+		if err := s.persist(ctx, standards, opts.strategy); err != nil {
+			return nil, err
+		}
+		s.observeSince(ports.StagePersist, persistStart)
+		s.metrics.AddReadings(ports.CounterPersisted, len(standards))
+	}
 
-			// 注意: 数据已经在 Sanitizer.Clean() 中按时间排序
-			// 但按设备分组后可能打乱顺序，需要重新排序
-			sort.Slice(devReadings, func(i, j int) bool {
-				return devReadings[i].Timestamp.Before(devReadings[j].Timestamp)
-			})
-
-			// Step C: Frequency Alignment (Time Alignment)
-			// Generate time grid based on standard interval
-			startTime := devReadings[0].Timestamp.Truncate(s.standardInterval)
-			endTime := devReadings[len(devReadings)-1].Timestamp
-			// Align endTime to grid ceiling
-			if rem := endTime.Sub(endTime.Truncate(s.standardInterval)); rem > 0 {
-				endTime = endTime.Truncate(s.standardInterval).Add(s.standardInterval)
-			} else {
-				endTime = endTime.Truncate(s.standardInterval)
-			}
+	sortConflicts(out.conflicts)
+	return &domain.StandardizationResult{Readings: standards, DeviceErrors: deviceErrors, Conflicts: out.conflicts}, nil
+}
+
+// persist 以批次幂等键写入标准读数，成功后发出 StandardReadingSaved，失败时发出 PersistFailed
+func (s *CoreStandardizer) persist(ctx context.Context, standards []domain.StandardReading, strategy ports.UpsertStrategy) error {
+	key := batchKey(ctx, standards)
+	if err := s.repo.SaveBatch(ctx, standards, strategy, key); err != nil {
+		publishEvent(ctx, s.logger, s.events, ports.PersistFailed{Readings: standards, Strategy: strategy, BatchKey: key, Err: err, OccurredAt: time.Now()})
+		return fmt.Errorf("failed to persist standards: %w", err)
+	}
+	publishEvent(ctx, s.logger, s.events, ports.StandardReadingSaved{Readings: standards, Strategy: strategy, BatchKey: key, OccurredAt: time.Now()})
+	return nil
+}
 
-			var groupStandards []domain.StandardReading
+// provenanceOf 返回 ctx 中 IngestContext 的来源信息 (未设置时为 nil)
+func provenanceOf(ctx context.Context) *domain.Provenance {
+	info, ok := domain.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return info.Provenance()
+}
 
-			for t := startTime; !t.After(endTime); t = t.Add(s.standardInterval) {
-				// Context cancellation check (Fast fail)
-				select {
-				case <-ctx.Done():
-					errChan <- ctx.Err()
-					return
-				default:
-				}
+// stampProvenance 为未记录来源的标准读数填写 ctx 中的来源信息 (各读数持有独立副本)
+func stampProvenance(ctx context.Context, standards []domain.StandardReading) {
+	provenance := provenanceOf(ctx)
+	if provenance == nil {
+		return
+	}
+	for i := range standards {
+		if standards[i].Provenance == nil {
+			p := *provenance
+			standards[i].Provenance = &p
+		}
+	}
+}
+
+// batchKey 计算持久化幂等键 (批次号取自 IngestContext)
+func batchKey(ctx context.Context, standards []domain.StandardReading) string {
+	var batchID string
+	if info, ok := domain.FromContext(ctx); ok {
+		batchID = info.BatchID
+	}
+	return domain.BatchFingerprint(batchID, standards)
+}
+
+// sortStandards 按 (DeviceID, Metric, Timestamp) 稳定排序
+func sortStandards(standards []domain.StandardReading) {
+	slices.SortStableFunc(standards, func(a, b domain.StandardReading) int {
+		if c := strings.Compare(a.DeviceID, b.DeviceID); c != 0 {
+			return c
+		}
+		if c := strings.Compare(string(a.Metric), string(b.Metric)); c != 0 {
+			return c
+		}
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+}
+
+// deviceOutput 设备 (计量通道) 的标准化产出
+type deviceOutput struct {
+	standards []domain.StandardReading
+	gaps      []domain.DataGap
+	conflicts []domain.PriorityConflict
+}
+
+// merge 追加另一份产出
+func (o *deviceOutput) merge(other deviceOutput) {
+	o.standards = append(o.standards, other.standards...)
+	o.gaps = append(o.gaps, other.gaps...)
+	o.conflicts = append(o.conflicts, other.conflicts...)
+}
 
-				// Find snapshot for this time slot
-				snapshot := s.aligner.FindSnapshot(devReadings, t)
-				if snapshot != nil {
-					// Step 2: B. 单条转换
-					sr := s.standardizeOne(ctx, *snapshot)
-					sr.Timestamp = t // Force alignment to the grid time
-					groupStandards = append(groupStandards, sr)
+// standardizeGroups 使用固定大小的 worker 池处理各设备组
+// worker 数量为 min(concurrencyLimit, 设备数)，每个 worker 先在本地累积结果，结束时合并一次，
+// 避免数万台设备时的 goroutine 调度与锁竞争开销。
+// 单台设备出错只记入 deviceErrors，不影响其他设备；ctx 取消则立即停止全部工作 (快速失败)。
+func (s *CoreStandardizer) standardizeGroups(ctx context.Context, deviceGroups map[string]*deviceBatch) (deviceOutput, map[string]error, error) {
+	workers := min(s.concurrencyLimit, len(deviceGroups))
+	if workers == 0 {
+		return deviceOutput{}, nil, nil
+	}
+
+	// 按 网格槽位数 × 设备数 预分配结果，避免大批次下反复扩容
+	estimated := 0
+	for _, b := range deviceGroups {
+		estimated += s.estimateSlots(b)
+	}
+	out := deviceOutput{standards: make([]domain.StandardReading, 0, estimated)}
+	var deviceErrors map[string]error
+	var mu sync.Mutex
+
+	g, gctx := newErrGroup(ctx)
+	jobs := make(chan *deviceBatch)
+
+	for range workers {
+		g.Go(func() error {
+			local := deviceOutput{standards: make([]domain.StandardReading, 0, estimated/workers+1)}
+			localErrors := make(map[string]error)
+			for b := range jobs {
+				devOut, err := s.standardizeDevice(gctx, *b.readings)
+				putReadingSlice(b.readings) // 标准读数均为值拷贝，切片可立即归还
+				if err != nil {
+					if gctx.Err() != nil {
+						return err // 批次级失败 (已取消)
+					}
+					localErrors[b.channel] = err
+					continue
 				}
+				local.merge(devOut)
 			}
 
 			mu.Lock()
-			standards = append(standards, groupStandards...)
+			out.merge(local)
+			for id, err := range localErrors {
+				if deviceErrors == nil {
+					deviceErrors = make(map[string]error)
+				}
+				deviceErrors[id] = err
+			}
 			mu.Unlock()
+			return nil
+		})
+	}
 
-		}(readings)
+	// 分发设备组；出错取消后停止分发，worker 随 jobs 关闭退出
+dispatch:
+	for id, b := range deviceGroups {
+		select {
+		case jobs <- b:
+			delete(deviceGroups, id)
+		case <-gctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	// 未分发的设备组同样归还
+	for _, b := range deviceGroups {
+		putReadingSlice(b.readings)
 	}
 
-	wg.Wait()
-	close(errChan)
+	if err := g.Wait(); err != nil {
+		return deviceOutput{}, nil, err
+	}
+	// 上游取消时分发可能提前结束，结果不完整
+	if err := ctx.Err(); err != nil {
+		return deviceOutput{}, nil, err
+	}
+	return out, deviceErrors, nil
+}
 
-	// 收集所有错误
-	var errs []error
-	for err := range errChan {
-		errs = append(errs, err)
+// standardizeDevice 对单台设备的有效读数做频率对齐与单条转换，并收集数据缺口与优先级冲突
+func (s *CoreStandardizer) standardizeDevice(ctx context.Context, devReadings []domain.Reading) (deviceOutput, error) {
+	devReadings, err := s.runPreAlign(ctx, devReadings)
+	if err != nil {
+		return deviceOutput{}, err
 	}
-	if len(errs) > 0 {
-		return nil, errors.Join(errs...)
+	if len(devReadings) == 0 {
+		return deviceOutput{}, nil
 	}
 
-	// Step 3: Persistence (if configured)
-	if s.repo != nil && len(standards) > 0 {
-		// Use Priority-based upsert strategy to respect data governance rules
-		if err := s.repo.SaveBatch(ctx, standards, ports.UpsertStrategyHighPriorityWins); err != nil {
-			return nil, fmt.Errorf("failed to persist standards: %w", err)
+	// 注意: 数据已经在 Sanitizer.Clean() 中按时间排序
+	// 但按设备分组后可能打乱顺序，需要重新排序
+	sort.Slice(devReadings, func(i, j int) bool {
+		return devReadings[i].Timestamp.Before(devReadings[j].Timestamp)
+	})
+
+	// Step C: Frequency Alignment (Time Alignment)
+	// 缺口与冲突只在标准间隔上记录，避免同一事件在各分辨率上重复上报
+	loc := s.gridLocation(ctx, devReadings[0].DeviceInfo)
+	slots := newSlotCollector(devReadings[0].DeviceInfo.ID, devReadings[0].Metric, s.standardInterval)
+
+	var out deviceOutput
+	for _, interval := range s.intervals() {
+		collector := slots
+		if interval != s.standardInterval {
+			collector = nil
+		}
+		resStandards, err := s.alignGrid(ctx, devReadings, s.grid(interval, loc), collector)
+		if err != nil {
+			return deviceOutput{}, err
+		}
+		out.standards = append(out.standards, resStandards...)
+	}
+
+	out.gaps = slots.done()
+	out.conflicts = slots.conflicts
+	return out, nil
+}
+
+// grid 构建设备使用的时间网格 (应用锚点偏移)
+func (s *CoreStandardizer) grid(interval time.Duration, loc *time.Location) domain.TimeGrid {
+	return domain.NewTimeGrid(interval, loc).WithAnchor(s.gridAnchor)
+}
+
+// slotValue 计算槽位 t 的取值: 快照模式下取最近邻读数，其他模式聚合 [t, next) 区间内的读数
+// 候选读数来自不同优先级的来源时，只在最高优先级的读数中取值，并返回裁决记录
+func (s *CoreStandardizer) slotValue(devReadings []domain.Reading, grid domain.TimeGrid, t time.Time, mode domain.AggregationMode) (*domain.Reading, *domain.PriorityConflict) {
+	if !isAggregated(mode) {
+		candidates, conflict := highestPriority(domain.Window(devReadings, t.Add(-s.tolerance), t.Add(s.tolerance+1)))
+		return s.aligner.FindSnapshotMode(candidates, t, s.snapshotMode), conflict
+	}
+	candidates, conflict := highestPriority(domain.Window(devReadings, t, grid.Next(t)))
+	agg, ok := domain.Aggregate(candidates, mode)
+	if !ok {
+		return nil, nil
+	}
+	return &agg, conflict
+}
+
+// standardizeSlot 计算并转换槽位 t 的标准读数；空槽位按 gapFill 策略填充，无法填充时返回 ok=false
+func (s *CoreStandardizer) standardizeSlot(ctx context.Context, devReadings []domain.Reading, grid domain.TimeGrid, t time.Time, mode domain.AggregationMode, slots *slotCollector) (domain.StandardReading, bool) {
+	resolution := domain.ResolutionTag(grid.Interval)
+
+	// Find snapshot (or aggregate) for this time slot
+	snapshot, conflict := s.slotValue(devReadings, grid, t, mode)
+	if conflict != nil && snapshot != nil {
+		slots.conflict(t, *conflict)
+	}
+	if snapshot == nil {
+		slots.miss(t)
+		if mode == domain.AggregationSum {
+			return domain.StandardReading{}, false // 求和型数据无法插值
+		}
+		filled, ok := s.fillSlot(devReadings, t)
+		if !ok {
+			return domain.StandardReading{}, false
+		}
+		sr := s.standardizeOne(ctx, filled)
+		sr.Resolution = resolution
+		return sr, true
+	}
+	slots.hit()
+
+	// Step 2: B. 单条转换
+	sr := s.standardizeOne(ctx, *snapshot)
+	sr.Timestamp = t // Force alignment to the grid time
+	sr.Resolution = resolution
+	return sr, true
+}
+
+// aggregationFor 返回读数所属通道的槽位取值方式 (计量通道配置优先于设备类型配置)
+func (s *CoreStandardizer) aggregationFor(r domain.Reading) domain.AggregationMode {
+	if mode, ok := s.metricAgg[r.Metric]; ok && r.Metric != "" {
+		return mode
+	}
+	return s.aggregation[r.DeviceInfo.Type]
+}
+
+// isAggregated 判断是否为区间聚合模式 (非最近邻快照)
+func isAggregated(mode domain.AggregationMode) bool {
+	return mode != "" && mode != domain.AggregationSnapshot
+}
+
+// lastSlot 返回以 last 为最后一条读数时需要输出的最后一个槽位
+// 聚合模式下槽位覆盖 [t, next)，最后一个槽位是最后一条读数所在的区间
+func lastSlot(grid domain.TimeGrid, last time.Time, mode domain.AggregationMode) time.Time {
+	if isAggregated(mode) {
+		return grid.Floor(last)
+	}
+	return grid.Ceil(last)
+}
+
+// intervals 返回需要输出的全部分辨率 (标准间隔在前，去重)
+func (s *CoreStandardizer) intervals() []time.Duration {
+	out := []time.Duration{s.standardInterval}
+	for _, d := range s.resolutions {
+		if d > 0 && !slices.Contains(out, d) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// alignGrid 在一个时间网格上生成标准读数 (Generate time grid aligned to the device's local time)
+// gaps 为 nil 时不收集缺口
+func (s *CoreStandardizer) alignGrid(ctx context.Context, devReadings []domain.Reading, grid domain.TimeGrid, slots *slotCollector) ([]domain.StandardReading, error) {
+	mode := s.aggregationFor(devReadings[0])
+	startTime := grid.Floor(devReadings[0].Timestamp)
+	endTime := lastSlot(grid, devReadings[len(devReadings)-1].Timestamp, mode)
+
+	out := make([]domain.StandardReading, 0, int(endTime.Sub(startTime)/grid.Interval)+1)
+	for t := startTime; !t.After(endTime); t = grid.Next(t) {
+		// Context cancellation check (Fast fail)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		if sr, ok := s.standardizeSlot(ctx, devReadings, grid, t, mode, slots); ok {
+			out = append(out, sr)
 		}
 	}
+	return out, nil
+}
+
+// gridLocation 解析设备使用的时间网格时区
+// 设备时区无法识别时记录告警并退回调用方/服务默认时区
+func (s *CoreStandardizer) gridLocation(ctx context.Context, device domain.DeviceInfo) *time.Location {
+	if device.Timezone != "" {
+		if cached, ok := s.zoneCache.Load(device.Timezone); ok {
+			return cached.(*time.Location)
+		}
+		loc, err := time.LoadLocation(device.Timezone)
+		if err == nil {
+			s.zoneCache.Store(device.Timezone, loc)
+			return loc
+		}
+		loggerOr(s.logger).Warn("unknown device timezone, falling back to default",
+			"device_id", device.ID, "timezone", device.Timezone, "error", err)
+	}
+	if info, ok := domain.FromContext(ctx); ok && info.Location != nil {
+		return info.Location
+	}
+	if s.location != nil {
+		return s.location
+	}
+	return time.UTC
+}
+
+// 空槽位填充值的置信度
+const (
+	gapFillLinearConfidence = 0.7
+	gapFillLOCFConfidence   = 0.5
+)
+
+// fillSlot 按 gapFill 策略为空槽位 t 构造填充读数
+// 前提: devReadings 已按时间排序；缺少所需的相邻读数时返回 ok=false
+func (s *CoreStandardizer) fillSlot(devReadings []domain.Reading, t time.Time) (domain.Reading, bool) {
+	prev, next := domain.Bracket(devReadings, t)
+	if prev == nil {
+		return domain.Reading{}, false
+	}
+
+	filled := domain.Reading{
+		DeviceInfo: prev.DeviceInfo,
+		Metric:     prev.Metric,
+		Timestamp:  t,
+		Quality:    domain.QualityInterpolated,
+	}
 
-	return standards, nil
+	switch s.gapFill {
+	case domain.GapFillLOCF:
+		filled.Value = prev.Value
+		filled.Confidence = gapFillLOCFConfidence
+	case domain.GapFillLinear:
+		if next == nil {
+			return domain.Reading{}, false
+		}
+		span := next.Timestamp.Sub(prev.Timestamp)
+		filled.Value = prev.Value
+		if span > 0 {
+			ratio := float64(t.Sub(prev.Timestamp)) / float64(span)
+			filled.Value = prev.Value + (next.Value-prev.Value)*ratio
+		}
+		filled.Confidence = gapFillLinearConfidence
+	default:
+		return domain.Reading{}, false
+	}
+	return filled, true
 }
 
 // DefaultScaleFactor 默认精度因子 (支持4位小数精度)
 const DefaultScaleFactor = 10000
 
+// scaleFactorFor 返回设备类型使用的精度因子 (未单独配置时使用默认值)
+func (s *CoreStandardizer) scaleFactorFor(deviceType domain.DeviceType) int {
+	if factor, ok := s.typeScaleFactors[deviceType]; ok {
+		return factor
+	}
+	return s.scaleFactor
+}
+
+// calibrate 返回读数校准后的值与实际应用的校准系数 (未校准时为 nil)
+func calibrate(r domain.Reading) (float64, *domain.Calibration) {
+	c := r.DeviceInfo.Calibration
+	if c == nil || c.IsIdentity() {
+		return r.Value, nil
+	}
+	applied := *c
+	return applied.Apply(r.Value), &applied
+}
+
 // standardizeOne 封装单条数据的转换逻辑 (SR - Single Responsibility: Mapping)
 func (s *CoreStandardizer) standardizeOne(ctx context.Context, r domain.Reading) domain.StandardReading {
 	// Determine Priority from Context
-	priority := domain.IngestStrategyRealtime.GetPriority() // Default
-	if info, ok := domain.FromContext(ctx); ok {
-		priority = info.Strategy.GetPriority()
+	priority := ingestPriority(ctx)
+	if r.Priority > 0 {
+		priority = r.Priority // 读数级优先级优先
 	}
 
+	// 校准: 寄存器原始值 -> 一次值 (如 CT 变比 40 的回路 2.5 -> 100)
+	value, calibration := calibrate(r)
+
 	// 精度转换: 浮点数 -> 高精度整型
 	// 例如: 123.4567 * 10000 = 1234567
-	scaledValue := int64(r.Value * float64(DefaultScaleFactor))
+	scaleFactor := s.scaleFactorFor(r.DeviceInfo.Type)
+	// 按十进制精确换算并按配置舍入，避免浮点乘法截断 (100.00019 -> 1000002 而非 1000001)
+	scaledValue := domain.ScaleDecimal(value, scaleFactor, s.rounding)
+
+	// 经过清洗剩下的都是有效值，除非清洗阶段显式标记了质量 (如插补产生的 ESTIMATED)
+	quality := domain.QualityValid
+	if r.Quality != "" {
+		quality = r.Quality
+	}
+	confidence := r.Confidence
+	if confidence <= 0 {
+		confidence = 1 // 未经规则评估
+	}
 
 	// 2. 结构封装
 	return domain.StandardReading{
-		DeviceID:     r.DeviceInfo.ID,
-		Timestamp:    r.Timestamp,
-		ValueScaled:  scaledValue,
-		ScaleFactor:  DefaultScaleFactor,
-		ValueDisplay: r.Value,
-		SourceType:   domain.ReadingTypeStandard,
-		Quality:      domain.QualityValid, // 经过清洗剩下的都是有效值
+		TenantID:      r.DeviceInfo.TenantID,
+		DeviceID:      r.DeviceInfo.ID,
+		Metric:        r.Metric,
+		Timestamp:     r.Timestamp,
+		ValueScaled:   scaledValue,
+		ScaleFactor:   scaleFactor,
+		ValueDisplay:  value,
+		SourceType:    domain.ReadingTypeStandard,
+		Calibration:   calibration,
+		Quality:       quality,
+		QualityReason: r.QualityReason,
+		Confidence:    confidence,
 
 		// Backfilling & Governance Support
 		IngestedAt: time.Now(),
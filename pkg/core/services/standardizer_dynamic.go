@@ -12,7 +12,8 @@ import (
 )
 
 // cleanWithDynamicRules 根据设备类型动态加载规则进行清洗
-func (s *CoreStandardizer) cleanWithDynamicRules(ctx context.Context, readings []domain.Reading) ([]domain.Reading, []domain.QuarantineReading, error) {
+// history 为各设备的历史最后一条有效读数 (可为 nil)
+func (s *CoreStandardizer) cleanWithDynamicRules(ctx context.Context, readings []domain.Reading, history map[string]domain.Reading) ([]domain.Reading, []domain.QuarantineReading, error) {
 	// 1. Group by DeviceType
 	typeGroups := make(map[domain.DeviceType][]domain.Reading)
 	for _, r := range readings {
@@ -31,31 +32,16 @@ func (s *CoreStandardizer) cleanWithDynamicRules(ctx context.Context, readings [
 		go func(dt domain.DeviceType, curReadings []domain.Reading) {
 			defer wg.Done()
 
-			// a. Load Rules
-			domainRules, err := s.ruleRepo.ListEnabledByDeviceType(ctx, dt)
+			// a. Load Rules (cached by device type)
+			execRules, err := s.loadRules(ctx, dt)
 			if err != nil {
-				errChan <- fmt.Errorf("load rules for %s failed: %w", dt, err)
+				errChan <- err
 				return
 			}
 
-			// b. Convert Rules
-			var execRules []ports.CleaningRule
-			ruleFactory := factory.GetRuleFactory()
-
-			for _, dr := range domainRules {
-				idx, err := ruleFactory.CreateRule(dr)
-				if err != nil {
-					// Log warning but continue? Or fail?
-					// Strict mode: fail
-					errChan <- fmt.Errorf("convert rule %s failed: %w", dr.ID, err)
-					return
-				}
-				execRules = append(execRules, idx)
-			}
-
-			// c. Sanitize
-			localSanitizer := NewSanitizer(execRules...)
-			cleanedRows, rejectedRows := localSanitizer.Clean(curReadings)
+			// b. Sanitize
+			localSanitizer := newChainSanitizer(s.ruleMetrics, s.duplicatePolicy, execRules...)
+			cleanedRows, rejectedRows := localSanitizer.cleanSeeded(curReadings, history)
 
 			mu.Lock()
 			result = append(result, cleanedRows...)
@@ -78,3 +64,63 @@ func (s *CoreStandardizer) cleanWithDynamicRules(ctx context.Context, readings [
 
 	return result, quarantined, nil
 }
+
+// loadRules 加载并编译某设备类型下启用的规则，优先命中缓存
+func (s *CoreStandardizer) loadRules(ctx context.Context, dt domain.DeviceType) ([]ports.CleaningRule, error) {
+	if cached, ok := s.ruleCache.get(dt); ok {
+		return cached, nil
+	}
+
+	domainRules, err := s.ruleRepo.ListEnabledByDeviceType(ctx, dt)
+	if err != nil {
+		return nil, fmt.Errorf("load rules for %s failed: %w", dt, err)
+	}
+
+	// Convert Rules
+	var execRules []ports.CleaningRule
+	ruleFactory := factory.GetRuleFactory()
+
+	for _, dr := range domainRules {
+		idx, err := ruleFactory.CreateRule(dr)
+		if err != nil {
+			// Strict mode: fail
+			return nil, fmt.Errorf("convert rule %s failed: %w", dr.ID, err)
+		}
+		execRules = append(execRules, idx)
+	}
+
+	s.ruleCache.put(dt, execRules)
+	return execRules, nil
+}
+
+// rulesFor 返回某设备类型使用的规则: 配置了 ruleRepo 时动态加载，否则使用静态规则
+func (s *CoreStandardizer) rulesFor(ctx context.Context, dt domain.DeviceType) ([]ports.CleaningRule, error) {
+	if s.ruleRepo == nil {
+		return s.staticRules, nil
+	}
+	return s.loadRules(ctx, dt)
+}
+
+// watchRules 订阅规则仓储的变更，收到事件后使相关设备类型的规则缓存失效
+// 仓储不支持订阅时退化为依赖缓存过期 (WithRuleCacheTTL)
+func (s *CoreStandardizer) watchRules() {
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := s.ruleRepo.Watch(ctx)
+	if err != nil {
+		cancel()
+		if !errors.Is(err, ports.ErrWatchNotSupported) {
+			loggerOr(s.logger).Warn("watch cleaning rules failed, relying on cache expiry", "error", err)
+		}
+		return
+	}
+	s.stopRuleWatch = cancel
+	go func() {
+		for event := range changes {
+			if event.PreviousDeviceType != "" {
+				s.InvalidateRules(event.DeviceType, event.PreviousDeviceType)
+			} else {
+				s.InvalidateRules(event.DeviceType)
+			}
+		}
+	}()
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// ErrServiceStopped 服务已停止，不再接收读数
+var ErrServiceStopped = errors.New("standardizer service stopped")
+
+// 常驻服务默认参数
+const (
+	DefaultServiceQueueSize       = 10000
+	DefaultServiceBatchSize       = 1000
+	DefaultServiceFlushInterval   = time.Second
+	DefaultServiceMaxRetries      = 3
+	DefaultServiceRetryBackoff    = 500 * time.Millisecond
+	DefaultServiceShutdownTimeout = 30 * time.Second
+)
+
+// ServiceOptions 常驻标准化服务的参数 (零值字段使用默认值)
+type ServiceOptions struct {
+	QueueSize       int           // 内部有界队列容量，队列满时 Enqueue 阻塞
+	BatchSize       int           // 微批的最大读数数，攒满即处理
+	FlushInterval   time.Duration // 微批的最长等待时间，到期即处理未攒满的批次
+	MaxRetries      int           // 持久化失败的重试次数 (< 0 表示不重试)
+	RetryBackoff    time.Duration // 首次重试的等待时间，之后逐次翻倍
+	ShutdownTimeout time.Duration // 停止时处理剩余读数的最长时间
+
+	// Options 标准化配置 (与 NewCoreStandardizer 相同)
+	Options []StandardizerOption
+
+	// OnResult 可选: 每个微批处理完成后回调 (如上报设备级错误)
+	OnResult func(ctx context.Context, result *domain.StandardizationResult)
+	// OnError 可选: 微批在重试后仍失败时回调 (默认记录日志)；readings 为该批次的全部读数，可用于落盘重放
+	OnError func(ctx context.Context, readings []domain.Reading, err error)
+}
+
+func (o ServiceOptions) withDefaults() ServiceOptions {
+	if o.QueueSize <= 0 {
+		o.QueueSize = DefaultServiceQueueSize
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultServiceBatchSize
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = DefaultServiceFlushInterval
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = DefaultServiceMaxRetries
+	}
+	if o.RetryBackoff <= 0 {
+		o.RetryBackoff = DefaultServiceRetryBackoff
+	}
+	if o.ShutdownTimeout <= 0 {
+		o.ShutdownTimeout = DefaultServiceShutdownTimeout
+	}
+	return o
+}
+
+// StandardizerService 常驻标准化服务
+// 持有内部有界队列: 调用方通过 Enqueue 提交读数，服务按 数量/时间 攒成微批后标准化、持久化，
+// 持久化失败按指数退避重试 (SaveBatch 带幂等键，重试安全)。可直接作为守护进程的组件使用。
+type StandardizerService struct {
+	core  *CoreStandardizer
+	opts  ServiceOptions
+	queue chan queuedReading
+
+	mu      sync.RWMutex // 保护 stopped 与 queue 的关闭
+	stopped bool
+
+	done chan struct{}
+	err  error
+}
+
+// RunStandardizerService 启动常驻标准化服务，直到 ctx 取消
+// ctx 取消后服务不再接收读数，在 ShutdownTimeout 内处理完队列中剩余的读数并排空隔离区队列；
+// 调用 Wait 等待停止完成。
+func RunStandardizerService(ctx context.Context, opts ServiceOptions) *StandardizerService {
+	opts = opts.withDefaults()
+	core := NewCoreStandardizer(opts.Options...).(*CoreStandardizer)
+	if core.repo != nil && opts.MaxRetries > 0 {
+		core.repo = &retryingRepository{StandardReadingRepository: core.repo, retries: opts.MaxRetries, backoff: opts.RetryBackoff, logger: core.logger}
+	}
+
+	s := &StandardizerService{
+		core:  core,
+		opts:  opts,
+		queue: make(chan queuedReading, opts.QueueSize),
+		done:  make(chan struct{}),
+	}
+	go s.stopOnCancel(ctx)
+	go s.run(ctx)
+	return s
+}
+
+// queuedReading 队列中的读数及其提交时的上下文
+type queuedReading struct {
+	reading domain.Reading
+	scope   enqueueScope
+}
+
+// enqueueScope Enqueue 时 ctx 携带的 IngestContext 与租户，微批按其分组处理
+type enqueueScope struct {
+	info    domain.IngestContext
+	hasInfo bool
+	tenant  string
+}
+
+// context 在服务的处理 ctx 上恢复提交时的 IngestContext 与租户 (提交时未携带的沿用服务 ctx 中的值)
+func (sc enqueueScope) context(ctx context.Context) context.Context {
+	if sc.hasInfo {
+		ctx = domain.NewContext(ctx, sc.info)
+	}
+	if sc.tenant != "" {
+		ctx = domain.WithTenant(ctx, sc.tenant)
+	}
+	return ctx
+}
+
+// Enqueue 提交读数，队列满时阻塞直到有空位或 ctx 取消
+// ctx 携带的 IngestContext (策略、TraceID、操作人、批次号、时区) 与租户随读数入队，
+// 处理时按提交时的上下文分组，不同来源的读数可以交替提交而互不混淆
+func (s *StandardizerService) Enqueue(ctx context.Context, readings ...domain.Reading) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return ErrServiceStopped
+	}
+
+	var scope enqueueScope
+	scope.info, scope.hasInfo = domain.FromContext(ctx)
+	scope.tenant = domain.TenantFromContext(ctx)
+	for _, r := range readings {
+		select {
+		case s.queue <- queuedReading{reading: r, scope: scope}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Wait 等待服务停止，返回关闭隔离区队列时的错误
+func (s *StandardizerService) Wait() error {
+	<-s.done
+	return s.err
+}
+
+// RuleStats 返回服务累计的规则触发统计
+func (s *StandardizerService) RuleStats() []ports.RuleStats {
+	return s.core.RuleStats()
+}
+
+// Shutdown 停止接收读数，等待队列中剩余的读数处理完毕、隔离区队列排空 (与取消 Run 的 ctx 效果相同)
+// ctx 到期时立即返回 ctx 的错误，后台收尾仍在 ShutdownTimeout 内继续，可调用 Wait 等待
+func (s *StandardizerService) Shutdown(ctx context.Context) error {
+	s.stop()
+	select {
+	case <-s.done:
+		return s.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stopOnCancel 在 ctx 取消后停止服务 (见 stop: 阻塞中的 Enqueue 先完成入队，之后的 Enqueue 返回 ErrServiceStopped)
+func (s *StandardizerService) stopOnCancel(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		s.stop()
+	case <-s.done:
+	}
+}
+
+// stop 拒绝新读数并关闭队列
+// 需等待持有读锁的 Enqueue 结束: run 仍在消费队列，阻塞中的 Enqueue 在腾出空位后完成入队 (或在其 ctx 取消时返回)，
+// 并不会因停止而提前返回；其读数随剩余队列一起处理
+func (s *StandardizerService) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+	close(s.queue)
+}
+
+// run 消费队列，按数量或时间切分微批
+func (s *StandardizerService) run(ctx context.Context) {
+	defer close(s.done)
+
+	// 批次处理不随 ctx 取消而中断，保留 ctx 中的值 (如 IngestContext)
+	procCtx := context.WithoutCancel(ctx)
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]queuedReading, 0, s.opts.BatchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		for _, group := range groupByScope(batch) {
+			s.process(group.scope.context(ctx), group.readings)
+		}
+		batch = make([]queuedReading, 0, s.opts.BatchSize) // 已交给下游，不复用
+	}
+
+	for {
+		select {
+		case r, ok := <-s.queue:
+			if !ok {
+				shutdownCtx, cancel := context.WithTimeout(procCtx, s.opts.ShutdownTimeout)
+				flush(shutdownCtx)
+				s.err = s.core.Shutdown(shutdownCtx)
+				cancel()
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) >= s.opts.BatchSize {
+				flush(procCtx)
+			}
+		case <-ticker.C:
+			flush(procCtx)
+		}
+	}
+}
+
+// scopedBatch 微批中同一提交上下文的读数
+type scopedBatch struct {
+	scope    enqueueScope
+	readings []domain.Reading
+}
+
+// groupByScope 按提交上下文拆分微批 (按首次出现的顺序，组内保持入队顺序)
+func groupByScope(batch []queuedReading) []scopedBatch {
+	var groups []scopedBatch
+	index := make(map[enqueueScope]int)
+	for _, q := range batch {
+		i, ok := index[q.scope]
+		if !ok {
+			i = len(groups)
+			index[q.scope] = i
+			groups = append(groups, scopedBatch{scope: q.scope})
+		}
+		groups[i].readings = append(groups[i].readings, q.reading)
+	}
+	return groups
+}
+
+// process 标准化并持久化一个微批
+func (s *StandardizerService) process(ctx context.Context, batch []domain.Reading) {
+	result, err := s.core.ProcessAndStandardize(ctx, batch)
+	if err != nil {
+		if s.opts.OnError != nil {
+			s.opts.OnError(ctx, batch, err)
+			return
+		}
+		loggerOr(s.core.logger).Error("standardizer service batch failed", "readings", len(batch), "error", err)
+		return
+	}
+	if s.opts.OnResult != nil {
+		s.opts.OnResult(ctx, result)
+	}
+}
+
+// retryingRepository 为 SaveBatch 增加指数退避重试
+type retryingRepository struct {
+	ports.StandardReadingRepository
+	retries int
+	backoff time.Duration
+	logger  *slog.Logger
+}
+
+func (r *retryingRepository) SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, idempotencyKey string) error {
+	delay := r.backoff
+	for attempt := 0; ; attempt++ {
+		err := r.StandardReadingRepository.SaveBatch(ctx, readings, strategy, idempotencyKey)
+		if err == nil || attempt >= r.retries {
+			return err
+		}
+		loggerOr(r.logger).Warn("persist standards failed, retrying",
+			"attempt", attempt+1, "readings", len(readings), "backoff", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		delay *= 2
+	}
+}
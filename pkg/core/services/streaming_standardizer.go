@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// StreamingStandardizer 流式 (增量) 标准化服务
+// 与 CoreStandardizer 共用同一套 StandardizerOption 配置，但逐条接收读数:
+// 在内存中维护每台设备的状态 (最近一条有效读数、尚未输出的槽位)，槽位一旦确定即输出标准读数，
+// 实时链路无需为了调用 ProcessAndStandardize 人为攒批。
+// 同一设备的读数需按时间顺序到达，乱序或重复时间戳的读数进入隔离区。
+// 设备状态按租户隔离 (见 domain.ResolveTenant)，不同租户的同名设备互不影响。
+type StreamingStandardizer struct {
+	core    *CoreStandardizer
+	mu      sync.Mutex
+	devices map[string]*streamState // 键为 Reading.ChannelID() (非默认租户带租户前缀)
+}
+
+// streamState 单台设备的流式状态
+type streamState struct {
+	tenant   string                      // 设备所属租户，输出与持久化在该租户内进行
+	lastSeen time.Time                   // 最近一条到达的读数时间 (用于乱序检测)
+	last     *domain.Reading             // 最近一条通过清洗的读数 (规则上下文中的 Previous)
+	pending  []domain.Reading            // 尚未被全部槽位消费的有效读数 (按时间排序)
+	grids    []domain.TimeGrid           // 各分辨率的时间网格 (标准间隔在前)
+	cursors  map[time.Duration]time.Time // 各分辨率下一个待输出的槽位
+	mode     domain.AggregationMode
+}
+
+// NewStreamingStandardizer 初始化流式标准化服务
+func NewStreamingStandardizer(opts ...StandardizerOption) *StreamingStandardizer {
+	return &StreamingStandardizer{
+		core:    NewCoreStandardizer(opts...).(*CoreStandardizer),
+		devices: make(map[string]*streamState),
+	}
+}
+
+// Push 接收一条原始读数，返回因这条读数而确定的标准读数 (可能为空)
+// 快照模式下槽位 t 在收到晚于 t+容差 的读数后输出；聚合模式下在收到不早于下一槽位的读数后输出。
+// 人工校准读数与 ProcessAndStandardize 一样先经 Authorizer 授权 (OperationCalibrationIngest)；
+// 流式入口无法暂存待审批的读数，WithCalibrationApproval 要求审批的校准读数返回 ErrApprovalRequired。
+func (s *StreamingStandardizer) Push(ctx context.Context, reading domain.Reading) ([]domain.StandardReading, error) {
+	if err := s.core.authorizeIngest(ctx); err != nil {
+		return nil, err
+	}
+	if s.core.needsApproval(ctx, 1) {
+		return nil, ErrApprovalRequired
+	}
+	if !s.core.batches.begin() {
+		return nil, ErrStandardizerClosed
+	}
+	defer s.core.batches.done()
+	batch := []domain.Reading{reading}
+	ctx, err := domain.ResolveTenant(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.core.clockSkew != nil {
+		s.core.clockSkew.Correct(ctx, batch)
+	}
+	reading = batch[0]
+
+	// ResolveTenant 已为读数补全租户，ChannelID 按租户区分同名设备
+	st := s.devices[reading.ChannelID()]
+	if st == nil {
+		st = &streamState{tenant: domain.TenantFromContext(ctx), cursors: make(map[time.Duration]time.Time)}
+		s.devices[reading.ChannelID()] = st
+	}
+
+	// 内置规则: 流式模式无法回填已输出的槽位，乱序与重复读数直接隔离
+	if !st.lastSeen.IsZero() && !reading.Timestamp.After(st.lastSeen) {
+		reason, code := "Out-of-order reading", domain.QuarantineCodeOutOfOrder
+		if reading.Timestamp.Equal(st.lastSeen) {
+			reason, code = "Duplicate timestamp", domain.QuarantineCodeDuplicate
+		}
+		err := s.core.quarantine.write(ctx, []domain.QuarantineReading{{
+			Reading:   reading,
+			Status:    domain.QuarantineStatusPending,
+			Reason:    reason,
+			Code:      code,
+			CreatedAt: time.Now(),
+		}})
+		return nil, err
+	}
+	st.lastSeen = reading.Timestamp
+
+	rules, err := s.core.rulesFor(ctx, reading.DeviceInfo.Type)
+	if err != nil {
+		return nil, fmt.Errorf("streaming cleaning failed: %w", err)
+	}
+	if st.last == nil && len(st.grids) == 0 && s.core.repo != nil {
+		// 设备的第一条读数以已持久化的历史作为 Previous
+		st.last = s.core.lastPersisted(ctx, reading.DeviceInfo, reading.Metric, reading.Timestamp)
+	}
+	sanitizer := newChainSanitizer(s.core.ruleMetrics, s.core.duplicatePolicy, rules...)
+	clean, q := sanitizer.check(ports.CleaningContext{Previous: st.last}, reading)
+	if q == nil {
+		if _, overflow := s.core.guardScale([]domain.Reading{clean}); len(overflow) > 0 {
+			q = &overflow[0]
+		}
+	}
+	if q != nil {
+		return nil, s.core.quarantine.write(ctx, []domain.QuarantineReading{*q})
+	}
+
+	if len(st.grids) == 0 {
+		s.initState(ctx, st, clean)
+	}
+	st.last = &clean
+	st.pending = append(st.pending, clean)
+
+	standards, gaps, err := s.advance(ctx, st, clean.Timestamp, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.emit(ctx, standards, gaps)
+}
+
+// Flush 输出所有设备尚未确定的槽位 (直到各设备最后一条读数对应的槽位)
+// 用于停机或窗口结束时收尾；Flush 之后到达的读数不会再回填已输出的槽位。
+// 各租户的读数分别在各自租户内输出与持久化，ctx 中的租户不参与。
+func (s *StreamingStandardizer) Flush(ctx context.Context) ([]domain.StandardReading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.devices))
+	for id := range s.devices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	type tenantOutput struct {
+		standards []domain.StandardReading
+		gaps      []domain.DataGap
+	}
+	var tenants []string
+	byTenant := make(map[string]*tenantOutput)
+	for _, id := range ids {
+		st := s.devices[id]
+		if len(st.grids) == 0 {
+			continue
+		}
+		devStandards, devGaps, err := s.advance(ctx, st, st.last.Timestamp, true)
+		if err != nil {
+			return nil, err
+		}
+		out, ok := byTenant[st.tenant]
+		if !ok {
+			out = &tenantOutput{}
+			byTenant[st.tenant] = out
+			tenants = append(tenants, st.tenant)
+		}
+		out.standards = append(out.standards, devStandards...)
+		out.gaps = append(out.gaps, devGaps...)
+	}
+
+	var standards []domain.StandardReading
+	for _, tenant := range tenants {
+		out := byTenant[tenant]
+		emitted, err := s.emit(domain.WithTenant(ctx, tenant), out.standards, out.gaps)
+		if err != nil {
+			return nil, err
+		}
+		standards = append(standards, emitted...)
+	}
+	sortStandards(standards)
+	return standards, nil
+}
+
+// Shutdown 优雅停止: 之后的 Push 返回 ErrStandardizerClosed，等待在途的 Push 完成后
+// 以 Flush 输出并持久化全部未确定的槽位，再排空隔离区队列 (见 CoreStandardizer.Shutdown)
+func (s *StreamingStandardizer) Shutdown(ctx context.Context) error {
+	s.core.batches.stop()
+	if err := s.core.batches.wait(ctx); err != nil {
+		return fmt.Errorf("wait for in-flight readings: %w", err)
+	}
+	_, flushErr := s.Flush(ctx)
+	return errors.Join(flushErr, s.core.Shutdown(ctx))
+}
+
+// Close 排空隔离区队列并停止后台 worker，不输出未确定的槽位 (需要时先调用 Flush，或改用 Shutdown)
+func (s *StreamingStandardizer) Close() error {
+	return s.core.Close()
+}
+
+// initState 以设备的第一条有效读数初始化时间网格与槽位游标
+func (s *StreamingStandardizer) initState(ctx context.Context, st *streamState, first domain.Reading) {
+	loc := s.core.gridLocation(ctx, first.DeviceInfo)
+	st.mode = s.core.aggregationFor(first)
+	for _, interval := range s.core.intervals() {
+		grid := s.core.grid(interval, loc)
+		st.grids = append(st.grids, grid)
+		st.cursors[interval] = grid.Floor(first.Timestamp)
+	}
+}
+
+// advance 输出在 until 时刻已经确定的槽位；final 为 true 时输出到最后一条读数对应的槽位为止
+// 缺口只在标准间隔上检测
+func (s *StreamingStandardizer) advance(ctx context.Context, st *streamState, until time.Time, final bool) ([]domain.StandardReading, []domain.DataGap, error) {
+	var out []domain.StandardReading
+	slots := newSlotCollector(st.last.DeviceInfo.ID, st.last.Metric, s.core.standardInterval)
+
+	for i, grid := range st.grids {
+		collector := slots
+		if i > 0 {
+			collector = nil
+		}
+
+		t := st.cursors[grid.Interval]
+		for s.slotReady(grid, t, st, until, final) {
+			select {
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			default:
+			}
+
+			if sr, ok := s.core.standardizeSlot(ctx, st.pending, grid, t, st.mode, collector); ok {
+				out = append(out, sr)
+			}
+			t = grid.Next(t)
+		}
+		st.cursors[grid.Interval] = t
+	}
+
+	st.prune(s.core.tolerance)
+	return out, slots.done(), nil
+}
+
+// slotReady 判断槽位 t 是否已经可以输出
+func (s *StreamingStandardizer) slotReady(grid domain.TimeGrid, t time.Time, st *streamState, until time.Time, final bool) bool {
+	if final {
+		return !t.After(lastSlot(grid, st.last.Timestamp, st.mode))
+	}
+	if isAggregated(st.mode) {
+		return !until.Before(grid.Next(t))
+	}
+	return until.After(t.Add(s.core.tolerance))
+}
+
+// prune 丢弃不会再被任何槽位用到的读数 (保留边界前一条，供空槽位填充使用)
+func (st *streamState) prune(tolerance time.Duration) {
+	var bound time.Time
+	for _, cursor := range st.cursors {
+		if bound.IsZero() || cursor.Before(bound) {
+			bound = cursor
+		}
+	}
+	bound = bound.Add(-tolerance)
+
+	idx := sort.Search(len(st.pending), func(i int) bool {
+		return !st.pending[i].Timestamp.Before(bound)
+	})
+	if idx > 1 {
+		st.pending = append([]domain.Reading(nil), st.pending[idx-1:]...)
+	}
+}
+
+// emit 按 (DeviceID, Timestamp) 排序后上报缺口并持久化标准读数 (如已配置)
+func (s *StreamingStandardizer) emit(ctx context.Context, standards []domain.StandardReading, gaps []domain.DataGap) ([]domain.StandardReading, error) {
+	sortStandards(standards)
+	stampProvenance(ctx, standards)
+	if len(gaps) > 0 && s.core.gapSink != nil {
+		if err := s.core.gapSink.ReportGaps(ctx, gaps); err != nil {
+			loggerOr(s.core.logger).Error("failed to report data gaps", "count", len(gaps), "error", err)
+		}
+	}
+	if s.core.repo != nil && len(standards) > 0 {
+		if err := s.core.persist(ctx, standards, ports.UpsertStrategyHighPriorityWins); err != nil {
+			return nil, err
+		}
+	}
+	return standards, nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// SummaryQuery 区间用量统计摘要的条件
+type SummaryQuery struct {
+	DeviceIDs  []string
+	Metric     domain.Metric // 为空表示设备的默认通道
+	Resolution string        // 参与统计的分辨率 (如 "15m")，读数须为区间用量
+	Start, End time.Time     // 时间区间 [Start, End)
+}
+
+// UsageSummary 一台设备的区间用量统计摘要
+// 统计量以定点整数计算 (精度因子为区间内最大的精度因子)，浮点字段仅供展示
+type UsageSummary struct {
+	DeviceID    string `json:"device_id"`
+	Count       int    `json:"count"`        // 参与统计的区间数 (为 0 时其余字段均为零值)
+	ScaleFactor int    `json:"scale_factor"` // 各定点值的精度因子
+
+	TotalScaled  int64 `json:"total_scaled"`
+	MinScaled    int64 `json:"min_scaled"`
+	MaxScaled    int64 `json:"max_scaled"`
+	MeanScaled   int64 `json:"mean_scaled"`   // 四舍五入
+	MedianScaled int64 `json:"median_scaled"` // 偶数个区间取中间两值的平均 (四舍五入)
+	P95Scaled    int64 `json:"p95_scaled"`    // 最近秩法 (nearest-rank)，结果总是某个实际区间的用量
+
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Mean       float64 `json:"mean"`
+	Median     float64 `json:"median"`
+	P95        float64 `json:"p95"`
+	LoadFactor float64 `json:"load_factor"` // 负荷率 = 平均区间用量 / 最大区间用量 (0-1，最大值不为正时为 0)
+}
+
+// SummarizeUsage 统计各设备在 [q.Start, q.End) 内区间用量的最小值、最大值、平均值、中位数、P95 与负荷率，按设备ID升序返回
+// 缺失 (MISSING) 与已撤回 (WITHDRAWN) 的读数不参与统计；没有读数的设备 Count 为 0。
+func SummarizeUsage(ctx context.Context, repo ports.StandardReadingRepository, q SummaryQuery) ([]UsageSummary, error) {
+	if len(q.DeviceIDs) == 0 {
+		return nil, errors.New("summary query: device ids are required")
+	}
+	if _, ok := resolutionDuration(q.Resolution); !ok {
+		return nil, fmt.Errorf("summary query: invalid resolution %q", q.Resolution)
+	}
+	if !q.End.After(q.Start) {
+		return nil, fmt.Errorf("summary query: end %s must be after start %s", q.End.Format(time.RFC3339), q.Start.Format(time.RFC3339))
+	}
+
+	ids := slices.Compact(slices.Sorted(slices.Values(q.DeviceIDs)))
+	byDevice, err := FindRangeMulti(ctx, repo, ids, q.Start, q.End.Add(-time.Nanosecond)) // FindRange 为闭区间
+	if err != nil {
+		return nil, fmt.Errorf("usage summary: %w", err)
+	}
+	out := make([]UsageSummary, 0, len(ids))
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var readings []domain.StandardReading
+		scale := 1
+		for _, sr := range byDevice[id] {
+			if sr.Metric != q.Metric || sr.Resolution != q.Resolution ||
+				sr.Quality == domain.QualityMissing || sr.Quality == domain.QualityWithdrawn {
+				continue
+			}
+			if err := domain.ValidateScaleFactor(sr.ScaleFactor); err != nil {
+				return nil, fmt.Errorf("usage summary of %s: %w", id, err)
+			}
+			scale = max(scale, sr.ScaleFactor)
+			readings = append(readings, sr)
+		}
+		s, err := summarize(id, readings, scale)
+		if err != nil {
+			return nil, fmt.Errorf("usage summary of %s: %w", id, err)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// summarize 计算一台设备的统计摘要 (readings 已过滤，scale 为其最大精度因子)
+func summarize(deviceID string, readings []domain.StandardReading, scale int) (UsageSummary, error) {
+	s := UsageSummary{DeviceID: deviceID, Count: len(readings)}
+	if len(readings) == 0 {
+		return s, nil
+	}
+	values := make([]int64, len(readings))
+	for i, sr := range readings {
+		v, err := rescale(sr, scale)
+		if err != nil {
+			return UsageSummary{}, err
+		}
+		values[i] = v
+	}
+	slices.Sort(values)
+	total, err := totalScaled(values)
+	if err != nil {
+		return UsageSummary{}, err
+	}
+	n := int64(len(values))
+	mean, err := domain.RoundRat(big.NewRat(total, n), domain.RoundHalfUp)
+	if err != nil {
+		return UsageSummary{}, err
+	}
+	median := values[n/2]
+	if n%2 == 0 {
+		// 中间两值之和可能溢出，以有理数求平均
+		sum := new(big.Rat).Add(new(big.Rat).SetInt64(values[n/2-1]), new(big.Rat).SetInt64(values[n/2]))
+		if median, err = domain.RoundRat(sum.Quo(sum, big.NewRat(2, 1)), domain.RoundHalfUp); err != nil {
+			return UsageSummary{}, err
+		}
+	}
+	rank := (95*n + 99) / 100 // ceil(0.95 x n)
+
+	s.ScaleFactor = scale
+	s.TotalScaled = total
+	s.MinScaled, s.MaxScaled = values[0], values[n-1]
+	s.MeanScaled, s.MedianScaled, s.P95Scaled = mean, median, values[rank-1]
+	display := func(v int64) float64 { return float64(v) / float64(scale) }
+	s.Min, s.Max, s.Mean, s.Median, s.P95 = display(s.MinScaled), display(s.MaxScaled), display(mean), display(median), display(s.P95Scaled)
+	if s.MaxScaled > 0 {
+		s.LoadFactor, _ = new(big.Rat).SetFrac(big.NewInt(total), new(big.Int).Mul(big.NewInt(n), big.NewInt(s.MaxScaled))).Float64()
+	}
+	return s, nil
+}
+
+// totalScaled 返回定点值之和 (溢出时返回 ErrScaleOverflow)
+func totalScaled(values []int64) (int64, error) {
+	var total int64
+	for _, v := range values {
+		if (v > 0 && total > math.MaxInt64-v) || (v < 0 && total < math.MinInt64-v) {
+			return 0, fmt.Errorf("%w: usage total", domain.ErrScaleOverflow)
+		}
+		total += v
+	}
+	return total, nil
+}
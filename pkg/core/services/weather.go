@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// WithWeatherNormalization 配置气象数据来源，报表同时给出各周期的度日数与气候修正后的用量
+// basis 为修正依据的度日数 (为空表示 TOTAL)；未配置时报表不含气候修正
+func WithWeatherNormalization(provider ports.WeatherDataProvider, basis domain.DegreeDayBasis) ReportOption {
+	return func(g *CoreReportGenerator) {
+		g.weather = provider
+		g.degreeDayBasis = basis
+		if basis == "" {
+			g.degreeDayBasis = domain.DegreeDayTotal
+		}
+	}
+}
+
+// weatherNormalization 按度日法修正周期用量: 用量 x 常年度日数 / 实际度日数 (精确相乘后四舍五入)
+// 实际度日数为 0 时周期内没有气候驱动的负荷，修正用量等于实际用量
+func (g *CoreReportGenerator) weatherNormalization(ctx context.Context, q ports.ReportQuery, start, end time.Time, usage int64, scale int) (*domain.WeatherNormalization, error) {
+	if g.weather == nil {
+		return nil, nil
+	}
+	actual, err := g.weather.DegreeDays(ctx, q.Device, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("degree days: %w", err)
+	}
+	normal, err := g.weather.NormalDegreeDays(ctx, q.Device, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("normal degree days: %w", err)
+	}
+	w := &domain.WeatherNormalization{Basis: g.degreeDayBasis, Actual: actual, Normal: normal, NormalizedScaled: usage}
+	if dd := g.degreeDayBasis.Of(actual); dd != 0 {
+		num, ok := new(big.Rat).SetString(strconv.FormatFloat(g.degreeDayBasis.Of(normal), 'f', -1, 64))
+		if !ok {
+			return nil, fmt.Errorf("invalid normal degree days %+v", normal)
+		}
+		den, ok := new(big.Rat).SetString(strconv.FormatFloat(dd, 'f', -1, 64))
+		if !ok {
+			return nil, fmt.Errorf("invalid degree days %+v", actual)
+		}
+		normalized := num.Mul(num, new(big.Rat).SetInt64(usage))
+		w.NormalizedScaled, err = domain.RoundRat(normalized.Quo(normalized, den), domain.RoundHalfUp)
+		if err != nil {
+			return nil, fmt.Errorf("%w: normalized usage", err)
+		}
+		w.UsagePerDegreeDay = float64(usage) / float64(scale) / dd
+	}
+	w.NormalizedUsage = float64(w.NormalizedScaled) / float64(scale)
+	return w, nil
+}
@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// DefaultLatencyBuckets 默认的耗时直方图上界 (秒)
+var DefaultLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics prism 管道的 Prometheus 采集器
+// - 摄入: prism_ingest_records_total{format,outcome} 与 prism_ingest_errors_total{format} (每秒记录数与错误率用 rate() 计算)
+// - 清洗: prism_rule_checks_total{rule_id,device_type,result} (各规则的隔离率 = rejected / 全部结果)
+// - 标准化: prism_pipeline_readings_total{counter} 与 prism_standardize_stage_duration_seconds{stage}
+// - 仓储写入: prism_repository_writes_total{repository,outcome} 与 prism_repository_write_duration_seconds{repository}
+// 实现了 ports.StandardizerMetrics，可直接传给 services.WithMetrics；全部方法并发安全。
+type Metrics struct {
+	namespace string
+	buckets   []float64
+
+	ingestRecords *counterVec
+	ingestErrors  *counterVec
+	readings      *counterVec
+	stages        *histogramVec
+	repoWrites    *counterVec
+	repoDuration  *histogramVec
+
+	mu    sync.Mutex
+	rules []ports.RuleStatsProvider
+}
+
+// 编译期检查接口实现
+var (
+	_ ports.StandardizerMetrics = (*Metrics)(nil)
+	_ Collector                 = (*Metrics)(nil)
+)
+
+// Option 定义采集器配置选项 (Functional Option Pattern)
+type Option func(*Metrics)
+
+// WithNamespace 设置指标名前缀 (默认 "prism")
+func WithNamespace(namespace string) Option {
+	return func(m *Metrics) {
+		if namespace != "" {
+			m.namespace = namespace
+		}
+	}
+}
+
+// WithLatencyBuckets 设置耗时直方图的上界 (秒，升序；默认 DefaultLatencyBuckets)
+func WithLatencyBuckets(buckets ...float64) Option {
+	return func(m *Metrics) {
+		if len(buckets) > 0 {
+			m.buckets = buckets
+		}
+	}
+}
+
+// New 创建采集器并注册到 reg
+func New(reg Registerer, opts ...Option) (*Metrics, error) {
+	m := &Metrics{namespace: "prism", buckets: DefaultLatencyBuckets}
+	for _, opt := range opts {
+		opt(m)
+	}
+	ns := m.namespace + "_"
+	m.ingestRecords = newCounterVec(ns+"ingest_records_total", "Records processed by ingestors, by input format and outcome (success, failed, skipped).", "format", "outcome")
+	m.ingestErrors = newCounterVec(ns+"ingest_errors_total", "Ingestion calls that returned an error, by input format.", "format")
+	m.readings = newCounterVec(ns+"pipeline_readings_total", "Readings passing through each step of the standardization pipeline.", "counter")
+	m.stages = newHistogramVec(ns+"standardize_stage_duration_seconds", "Duration of standardization pipeline stages.", m.buckets, "stage")
+	m.repoWrites = newCounterVec(ns+"repository_writes_total", "Standard readings submitted to the repository, by outcome (success, failed).", "repository", "outcome")
+	m.repoDuration = newHistogramVec(ns+"repository_write_duration_seconds", "Duration of repository write calls.", m.buckets, "repository")
+	if err := reg.Register(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AddReadings 实现 ports.StandardizerMetrics
+func (m *Metrics) AddReadings(counter ports.PipelineCounter, n int) {
+	m.readings.add(float64(n), string(counter))
+}
+
+// ObserveStage 实现 ports.StandardizerMetrics
+func (m *Metrics) ObserveStage(stage ports.PipelineStage, elapsed time.Duration) {
+	m.stages.observe(elapsed.Seconds(), string(stage))
+}
+
+// WatchRuleStats 抓取时从清洗规则统计 (如 *services.CoreStandardizer) 读取各规则的检查结果
+// 标准化服务通常以本采集器为 WithMetrics 创建，因此在创建之后再调用本方法
+func (m *Metrics) WatchRuleStats(provider ports.RuleStatsProvider) {
+	if provider == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules = append(m.rules, provider)
+}
+
+// ObserveIngestion 记录一次摄入调用的结果 (result 可为 nil)
+func (m *Metrics) ObserveIngestion(format string, result *domain.IngestionResult, err error) {
+	if result != nil {
+		m.ingestRecords.add(float64(result.Success), format, "success")
+		m.ingestRecords.add(float64(result.Failed), format, "failed")
+		m.ingestRecords.add(float64(result.Skipped), format, "skipped")
+	}
+	if err != nil {
+		m.ingestErrors.add(1, format)
+	}
+}
+
+// ObserveWrite 记录一次仓储写入 (n 为提交的标准读数条数)
+func (m *Metrics) ObserveWrite(repository string, n int, elapsed time.Duration, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "failed"
+	}
+	m.repoWrites.add(float64(n), repository, outcome)
+	m.repoDuration.observe(elapsed.Seconds(), repository)
+}
+
+// Collect 实现 Collector
+func (m *Metrics) Collect() []Family {
+	out := []Family{
+		m.ingestRecords.family(),
+		m.ingestErrors.family(),
+		m.readings.family(),
+		m.stages.family(),
+		m.repoWrites.family(),
+		m.repoDuration.family(),
+	}
+	rules := newCounterVec(m.namespace+"_rule_checks_total", "Cleaning rule checks, by rule, device type and result (passed, corrected, rejected).", "rule_id", "device_type", "result")
+	m.mu.Lock()
+	providers := slices.Clone(m.rules)
+	m.mu.Unlock()
+	for _, p := range providers {
+		for _, s := range p.RuleStats() {
+			rules.add(float64(s.Passed), s.RuleID, string(s.DeviceType), "passed")
+			rules.add(float64(s.Corrected), s.RuleID, string(s.DeviceType), "corrected")
+			rules.add(float64(s.Rejected), s.RuleID, string(s.DeviceType), "rejected")
+		}
+	}
+	return append(out, rules.family())
+}
+
+// InstrumentIngestor 包装摄入器，记录每次调用的记录数与错误
+// IngestBatch 以 format 参数作为标签；IngestStream 没有格式参数，使用 streamFormat (如 "json")
+func (m *Metrics) InstrumentIngestor(streamFormat string, ingestor ports.UniversalIngestor) ports.UniversalIngestor {
+	return &instrumentedIngestor{metrics: m, streamFormat: streamFormat, next: ingestor}
+}
+
+type instrumentedIngestor struct {
+	metrics      *Metrics
+	streamFormat string
+	next         ports.UniversalIngestor
+}
+
+func (i *instrumentedIngestor) IngestStream(ctx context.Context, stream io.Reader) (*domain.IngestionResult, error) {
+	res, err := i.next.IngestStream(ctx, stream)
+	i.metrics.ObserveIngestion(i.streamFormat, res, err)
+	return res, err
+}
+
+func (i *instrumentedIngestor) IngestBatch(ctx context.Context, file io.Reader, format string) (*domain.IngestionResult, error) {
+	res, err := i.next.IngestBatch(ctx, file, format)
+	i.metrics.ObserveIngestion(format, res, err)
+	return res, err
+}
+
+// InstrumentRepository 包装标准读数仓储，记录 Save / SaveBatch 的写入条数与耗时
+// 包装后只暴露 ports.StandardReadingRepository 的方法，聚合、保留期清理等可选端口请直接使用原仓储；
+// 仓储只用于标准化管道写入时，也可不包装，改用 prism_pipeline_readings_total{counter="persisted"}。
+func (m *Metrics) InstrumentRepository(name string, repo ports.StandardReadingRepository) ports.StandardReadingRepository {
+	return &instrumentedRepository{StandardReadingRepository: repo, metrics: m, name: name}
+}
+
+type instrumentedRepository struct {
+	ports.StandardReadingRepository
+	metrics *Metrics
+	name    string
+}
+
+func (r *instrumentedRepository) Save(ctx context.Context, reading domain.StandardReading, strategy ports.UpsertStrategy) error {
+	start := time.Now()
+	err := r.StandardReadingRepository.Save(ctx, reading, strategy)
+	r.metrics.ObserveWrite(r.name, 1, time.Since(start), err)
+	return err
+}
+
+func (r *instrumentedRepository) SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, idempotencyKey string) error {
+	start := time.Now()
+	err := r.StandardReadingRepository.SaveBatch(ctx, readings, strategy, idempotencyKey)
+	r.metrics.ObserveWrite(r.name, len(readings), time.Since(start), err)
+	return err
+}
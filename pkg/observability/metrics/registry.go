@@ -0,0 +1,177 @@
+// Package metrics 提供 Prometheus 指标采集器: 摄入、清洗、标准化耗时与仓储写入吞吐
+// 指标以 Prometheus 文本格式 (0.0.4) 暴露，不依赖 client_golang；采集器通过可注入的 Registerer 注册，
+// 嵌入方可使用本包的 Registry (实现了 http.Handler，直接挂到 /metrics)，也可实现 Registerer 桥接到自有的注册表。
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MetricType 指标类型
+type MetricType string
+
+const (
+	TypeCounter   MetricType = "counter"
+	TypeGauge     MetricType = "gauge"
+	TypeHistogram MetricType = "histogram"
+)
+
+// Label 标签
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample 一个时间序列的当前值
+// 直方图的 Buckets 为各上界 (升序，不含 +Inf) 的累计计数，Count / Sum 为观测次数与总和
+type Sample struct {
+	Labels  []Label
+	Value   float64 // counter / gauge 的值
+	Buckets []Bucket
+	Count   uint64
+	Sum     float64
+}
+
+// Bucket 直方图的一个累计桶
+type Bucket struct {
+	UpperBound float64
+	Count      uint64
+}
+
+// Family 同名指标的全部时间序列
+type Family struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []Sample
+}
+
+// Collector 指标采集器: 每次抓取时返回当前的指标快照 (必须是并发安全的)
+type Collector interface {
+	Collect() []Family
+}
+
+// Registerer 采集器注册端口
+// 本包的 Registry 为默认实现；已使用其他指标库的嵌入方可实现该接口，在抓取时调用 Collector.Collect 转换输出
+type Registerer interface {
+	Register(c Collector) error
+}
+
+// Registry 采集器注册表，按 Prometheus 文本格式输出全部指标
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+// 编译期检查接口实现
+var (
+	_ Registerer   = (*Registry)(nil)
+	_ http.Handler = (*Registry)(nil)
+)
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register 注册采集器；与已注册采集器的指标重名时返回错误
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make(map[string]bool)
+	for _, existing := range r.collectors {
+		for _, f := range existing.Collect() {
+			names[f.Name] = true
+		}
+	}
+	for _, f := range c.Collect() {
+		if names[f.Name] {
+			return fmt.Errorf("metrics: duplicate metric %q", f.Name)
+		}
+	}
+	r.collectors = append(r.collectors, c)
+	return nil
+}
+
+// Gather 返回全部指标，按名称排序
+func (r *Registry) Gather() []Family {
+	r.mu.Lock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.Unlock()
+
+	var out []Family
+	for _, c := range collectors {
+		out = append(out, c.Collect()...)
+	}
+	slices.SortFunc(out, func(a, b Family) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+// WriteText 以 Prometheus 文本格式输出全部指标
+func (r *Registry) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range r.Gather() {
+		writeFamily(bw, f)
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP 实现 http.Handler，供 Prometheus 抓取
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := r.WriteText(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeFamily(w *bufio.Writer, f Family) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.Name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(f.Help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.Name, f.Type)
+	for _, s := range f.Samples {
+		if f.Type != TypeHistogram {
+			fmt.Fprintf(w, "%s%s %s\n", f.Name, formatLabels(s.Labels), formatFloat(s.Value))
+			continue
+		}
+		for _, b := range s.Buckets {
+			le := append(slices.Clone(s.Labels), Label{"le", formatFloat(b.UpperBound)})
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.Name, formatLabels(le), b.Count)
+		}
+		inf := append(slices.Clone(s.Labels), Label{"le", "+Inf"})
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.Name, formatLabels(inf), s.Count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.Name, formatLabels(s.Labels), formatFloat(s.Sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.Name, formatLabels(s.Labels), s.Count)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l.Name + `="` + labelEscaper.Replace(l.Value) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
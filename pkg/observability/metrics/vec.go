@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// labelKey 标签值以 \xff 连接作为序列键 (标签值不会包含该字节)
+func labelKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// counterVec 带标签的计数器
+type counterVec struct {
+	name, help string
+	labels     []string
+
+	mu     sync.Mutex
+	values map[string]float64
+	order  map[string][]string // 序列键 -> 标签值
+}
+
+func newCounterVec(name, help string, labels ...string) *counterVec {
+	return &counterVec{name: name, help: help, labels: labels, values: make(map[string]float64), order: make(map[string][]string)}
+}
+
+// add 累加 (v 须非负)
+func (c *counterVec) add(v float64, values ...string) {
+	if v < 0 {
+		return
+	}
+	key := labelKey(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.order[key]; !ok {
+		c.order[key] = slices.Clone(values)
+	}
+	c.values[key] += v
+}
+
+func (c *counterVec) family() Family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f := Family{Name: c.name, Help: c.help, Type: TypeCounter}
+	for _, key := range sortedKeys(c.order) {
+		f.Samples = append(f.Samples, Sample{Labels: pairLabels(c.labels, c.order[key]), Value: c.values[key]})
+	}
+	return f
+}
+
+// histogramVec 带标签的直方图
+type histogramVec struct {
+	name, help string
+	labels     []string
+	buckets    []float64 // 升序上界
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64 // 各桶 (非累计) 计数
+	count  uint64
+	sum    float64
+}
+
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *histogramVec {
+	return &histogramVec{name: name, help: help, labels: labels, buckets: buckets, series: make(map[string]*histogramSeries)}
+}
+
+func (h *histogramVec) observe(v float64, values ...string) {
+	key := labelKey(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{values: slices.Clone(values), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i, _ := slices.BinarySearch(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *histogramVec) family() Family {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f := Family{Name: h.name, Help: h.help, Type: TypeHistogram}
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		sample := Sample{Labels: pairLabels(h.labels, s.values), Count: s.count, Sum: s.sum, Buckets: make([]Bucket, len(h.buckets))}
+		var cumulative uint64
+		for i, ub := range h.buckets {
+			cumulative += s.counts[i]
+			sample.Buckets[i] = Bucket{UpperBound: ub, Count: cumulative}
+		}
+		f.Samples = append(f.Samples, sample)
+	}
+	return f
+}
+
+func pairLabels(names, values []string) []Label {
+	out := make([]Label, len(names))
+	for i, n := range names {
+		out[i] = Label{Name: n, Value: values[i]}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(func(yield func(string) bool) {
+		for k := range m {
+			if !yield(k) {
+				return
+			}
+		}
+	})
+}
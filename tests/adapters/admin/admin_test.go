@@ -0,0 +1,227 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/admin"
+	"github.com/renjie/prism-core/pkg/adapters/factory"
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func do(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+// doAs 以认证中间件设置的操作人发起请求
+func doAs(t *testing.T, h http.Handler, operator, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req = req.WithContext(domain.NewContext(req.Context(), domain.IngestContext{Operator: operator}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("expected status %d, got %d: %s", status, rec.Code, rec.Body.String())
+	}
+}
+
+func TestRuleCRUD(t *testing.T) {
+	repo := memory.NewCleaningRuleRepository(nil)
+	validate := func(rule domain.CleaningRule) error {
+		_, err := factory.NewRuleFactory().CreateRule(rule)
+		return err
+	}
+	h := admin.NewHandler(admin.WithRules(repo, validate))
+
+	rule := `{"id":"r1","device_type":"ELEC","type":"RANGE","action":"REJECT","enabled":true,"parameters":{"min":0,"max":100}}`
+	expectStatus(t, do(t, h, "POST", "/rules", rule), http.StatusCreated)
+	expectStatus(t, do(t, h, "POST", "/rules", rule), http.StatusConflict)
+	expectStatus(t, do(t, h, "POST", "/rules", `{"id":"r2","device_type":"ELEC","type":"NOPE"}`), http.StatusBadRequest)
+	expectStatus(t, do(t, h, "PUT", "/rules/r1", `{"id":"other","device_type":"ELEC","type":"RANGE"}`), http.StatusBadRequest)
+
+	expectStatus(t, do(t, h, "PUT", "/rules/r1", `{"device_type":"ELEC","type":"RANGE","action":"REJECT","enabled":false,"parameters":{"min":0,"max":500}}`), http.StatusOK)
+	rec := do(t, h, "GET", "/rules/r1", "")
+	expectStatus(t, rec, http.StatusOK)
+	var got domain.CleaningRule
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Enabled || got.Parameters["max"] != float64(500) {
+		t.Errorf("expected updated rule, got %+v", got)
+	}
+
+	expectStatus(t, do(t, h, "GET", "/rules", ""), http.StatusBadRequest)
+	rec = do(t, h, "GET", "/rules?device_type=ELEC&enabled=true", "")
+	expectStatus(t, rec, http.StatusOK)
+	if body := strings.TrimSpace(rec.Body.String()); body != "[]" {
+		t.Errorf("expected no enabled rules, got %s", body)
+	}
+
+	expectStatus(t, do(t, h, "DELETE", "/rules/r1", ""), http.StatusNoContent)
+	expectStatus(t, do(t, h, "GET", "/rules/r1", ""), http.StatusNotFound)
+	// 未配置的部分不注册路由
+	expectStatus(t, do(t, h, "GET", "/devices", ""), http.StatusNotFound)
+}
+
+func TestQuarantineTriage(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "D1", Type: "ELEC"}
+	quarantine := memory.NewQuarantineRepository()
+	for i, id := range []string{"q1", "q2", "q3"} {
+		_ = quarantine.Save(ctx, domain.QuarantineReading{
+			ID: id, RuleID: "range", Reason: "negative value", Status: domain.QuarantineStatusPending,
+			Reading: domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(time.Duration(i) * 15 * time.Minute), Value: -1},
+		})
+	}
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(memory.NewStandardReadingRepository()),
+		services.WithQuarantineRepository(quarantine),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 200, Action: domain.ActionReject}),
+	).(*services.CoreStandardizer)
+	h := admin.NewHandler(admin.WithQuarantine(quarantine, standardizer))
+
+	rec := do(t, h, "GET", "/quarantine?status=PENDING&limit=2", "")
+	expectStatus(t, rec, http.StatusOK)
+	var page []domain.QuarantineReading
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(page))
+	}
+	expectStatus(t, do(t, h, "GET", "/quarantine?start=yesterday", ""), http.StatusBadRequest)
+
+	expectStatus(t, do(t, h, "POST", "/quarantine/q1/resolve", `{"value":12.5,"operator":"alice"}`), http.StatusUnauthorized)
+	rec = doAs(t, h, "alice", "POST", "/quarantine/q1/resolve", `{"value":12.5}`)
+	expectStatus(t, rec, http.StatusOK)
+	if !strings.Contains(rec.Body.String(), `"readings":[{`) {
+		t.Errorf("expected re-ingested readings, got %s", rec.Body.String())
+	}
+	expectStatus(t, doAs(t, h, "alice", "POST", "/quarantine/q1/resolve", `{"value":13,"operator":"alice"}`), http.StatusConflict)
+	// 忽略同样必须留下已认证的处理人
+	expectStatus(t, do(t, h, "POST", "/quarantine/q2/ignore", `{"reason":"meter replaced"}`), http.StatusUnauthorized)
+	expectStatus(t, do(t, h, "POST", "/quarantine/q2/ignore", `{"reason":"meter replaced","operator":"bob"}`), http.StatusUnauthorized)
+	expectStatus(t, doAs(t, h, "bob", "POST", "/quarantine/q2/ignore", `{"reason":"meter replaced","operator":"bob"}`), http.StatusNoContent)
+	expectStatus(t, doAs(t, h, "bob", "POST", "/quarantine/missing/ignore", `{"reason":"x"}`), http.StatusNotFound)
+	expectStatus(t, do(t, h, "POST", "/quarantine/q3/ignore", `{}`), http.StatusBadRequest)
+
+	ignored, _ := quarantine.Find(ctx, ports.QuarantineFilter{ID: "q2"})
+	if len(ignored) != 1 || ignored[0].Status != domain.QuarantineStatusIgnored || ignored[0].Operator != "bob" {
+		t.Errorf("expected q2 ignored by bob, got %+v", ignored)
+	}
+
+	rec = do(t, h, "GET", "/quarantine/counts?group_by=status", "")
+	expectStatus(t, rec, http.StatusOK)
+	for _, want := range []string{`{"key":"PENDING","count":1}`, `{"key":"RESOLVED","count":1}`, `{"key":"IGNORED","count":1}`} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("missing %s in %s", want, rec.Body.String())
+		}
+	}
+	expectStatus(t, do(t, h, "GET", "/quarantine/counts?group_by=color", ""), http.StatusBadRequest)
+}
+
+func TestDeviceMetadata(t *testing.T) {
+	devices := memory.NewDeviceRepository([]string{"D2"})
+	h := admin.NewHandler(admin.WithDevices(devices))
+
+	expectStatus(t, do(t, h, "PUT", "/devices/D1", `{"type":"ELEC","unit":"kWh","timezone":"Asia/Shanghai","tags":{"site":"A"}}`), http.StatusOK)
+	expectStatus(t, do(t, h, "PUT", "/devices/D3", `{"timezone":"Mars/Olympus"}`), http.StatusBadRequest)
+	expectStatus(t, do(t, h, "PUT", "/devices/D3", `{"colour":"red"}`), http.StatusBadRequest)
+
+	rec := do(t, h, "GET", "/devices/D1", "")
+	expectStatus(t, rec, http.StatusOK)
+	var got domain.DeviceInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "D1" || got.Timezone != "Asia/Shanghai" || got.Tags["site"] != "A" {
+		t.Errorf("unexpected device %+v", got)
+	}
+
+	rec = do(t, h, "GET", "/devices?type=ELEC", "")
+	expectStatus(t, rec, http.StatusOK)
+	var list []domain.DeviceInfo
+	_ = json.Unmarshal(rec.Body.Bytes(), &list)
+	if len(list) != 1 || list[0].ID != "D1" {
+		t.Errorf("expected only D1 of type ELEC, got %+v", list)
+	}
+
+	expectStatus(t, do(t, h, "DELETE", "/devices/D1", ""), http.StatusNoContent)
+	expectStatus(t, do(t, h, "GET", "/devices/D1", ""), http.StatusNotFound)
+	if ok, _ := devices.Exists(context.Background(), "D2"); !ok {
+		t.Error("expected D2 to remain registered")
+	}
+}
+
+func TestRuleWritesRequireAuthorization(t *testing.T) {
+	authz := ports.AuthorizerFunc(func(_ context.Context, req ports.AuthRequest) error {
+		if req.Operator != "admin" {
+			return ports.ErrForbidden
+		}
+		return nil
+	})
+	h := admin.NewHandler(admin.WithRules(services.NewAuthorizedRuleRepository(memory.NewCleaningRuleRepository(nil), authz), nil))
+	rule := `{"id":"r1","device_type":"ELEC","type":"RANGE","action":"REJECT","enabled":true}`
+
+	expectStatus(t, do(t, h, "POST", "/rules", rule), http.StatusForbidden)
+
+	// 认证中间件把操作人写入 IngestContext
+	req := httptest.NewRequest("POST", "/rules", strings.NewReader(rule))
+	req = req.WithContext(domain.NewContext(req.Context(), domain.IngestContext{Operator: "admin"}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	expectStatus(t, rec, http.StatusCreated)
+	expectStatus(t, do(t, h, "GET", "/rules/r1", ""), http.StatusOK)
+}
+
+func TestQuarantineOperatorComesFromContext(t *testing.T) {
+	ctx := context.Background()
+	quarantine := memory.NewQuarantineRepository()
+	for _, id := range []string{"q1", "q2"} {
+		_ = quarantine.Save(ctx, domain.QuarantineReading{
+			ID: id, RuleID: "range", Status: domain.QuarantineStatusPending,
+			Reading: domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "D1", Type: "ELEC"}, Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Value: -1},
+		})
+	}
+	authz := ports.AuthorizerFunc(func(_ context.Context, req ports.AuthRequest) error {
+		if req.Operator != "admin" {
+			return ports.ErrForbidden
+		}
+		return nil
+	})
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(memory.NewStandardReadingRepository()),
+		services.WithQuarantineRepository(quarantine),
+		services.WithAuthorizer(authz),
+	).(*services.CoreStandardizer)
+	h := admin.NewHandler(admin.WithQuarantine(quarantine, standardizer))
+
+	// 请求体声称是 admin，但认证中间件识别的是 mallory
+	expectStatus(t, doAs(t, h, "mallory", "POST", "/quarantine/q1/resolve", `{"value":12.5,"operator":"admin"}`), http.StatusForbidden)
+	expectStatus(t, doAs(t, h, "mallory", "POST", "/quarantine/q2/ignore", `{"reason":"x","operator":"admin"}`), http.StatusForbidden)
+	expectStatus(t, doAs(t, h, "mallory", "POST", "/quarantine/q2/ignore", `{"reason":"x"}`), http.StatusForbidden)
+
+	pending, _ := quarantine.Find(ctx, ports.QuarantineFilter{Status: domain.QuarantineStatusPending})
+	if len(pending) != 2 {
+		t.Fatalf("expected both records still pending, got %+v", pending)
+	}
+	expectStatus(t, doAs(t, h, "admin", "POST", "/quarantine/q2/ignore", `{"reason":"x"}`), http.StatusNoContent)
+}
@@ -0,0 +1,87 @@
+package deadletter_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/deadletter"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+func sampleLetter(source string) ports.DeadLetter {
+	return ports.DeadLetter{
+		Source:   source,
+		Readings: []domain.Reading{{DeviceInfo: domain.DeviceInfo{ID: "M1"}, Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Value: 10}},
+		Error:    "storage unavailable",
+		Attempts: 3,
+		FailedAt: time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC),
+	}
+}
+
+func TestFileSinkAppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dlq.jsonl")
+	for _, source := range []string{"csv", "json"} {
+		sink, err := deadletter.NewFileSink(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Write(context.Background(), sampleLetter(source)); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Write(context.Background(), sampleLetter(source)); err == nil {
+			t.Error("expected Write after Close to fail")
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var sources []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var l ports.DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			t.Fatal(err)
+		}
+		if len(l.Readings) != 1 || l.Readings[0].DeviceInfo.ID != "M1" || l.Attempts != 3 {
+			t.Errorf("unexpected dead letter %+v", l)
+		}
+		sources = append(sources, l.Source)
+	}
+	if len(sources) != 2 || sources[0] != "csv" || sources[1] != "json" {
+		t.Errorf("expected both letters appended in order, got %v", sources)
+	}
+}
+
+type recordingPublisher struct{ msgs []ports.OutboxMessage }
+
+func (p *recordingPublisher) Publish(ctx context.Context, msg ports.OutboxMessage) error {
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func TestPublisherSink(t *testing.T) {
+	pub := &recordingPublisher{}
+	sink := deadletter.NewPublisherSink(pub, "")
+	if err := sink.Write(context.Background(), sampleLetter("csv")); err != nil {
+		t.Fatal(err)
+	}
+	if len(pub.msgs) != 1 || pub.msgs[0].Topic != ports.DefaultDeadLetterTopic || pub.msgs[0].Key != "csv" {
+		t.Fatalf("unexpected messages %+v", pub.msgs)
+	}
+	var l ports.DeadLetter
+	if err := json.Unmarshal(pub.msgs[0].Payload, &l); err != nil || l.Error != "storage unavailable" {
+		t.Errorf("unexpected payload %s (%v)", pub.msgs[0].Payload, err)
+	}
+}
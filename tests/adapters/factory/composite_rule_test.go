@@ -0,0 +1,59 @@
+package factory_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/factory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+func TestCompositeRuleFromJSON(t *testing.T) {
+	// "out of range AND outside business hours"
+	var rule domain.CleaningRule
+	config := `{
+		"id": "night-overload",
+		"type": "COMPOSITE",
+		"enabled": true,
+		"parameters": {
+			"operator": "AND",
+			"rules": [
+				{"type": "RANGE", "parameters": {"min": 0, "max": 100}},
+				{"type": "TIME_WINDOW", "parameters": {"start": "08:00", "end": "18:00"}}
+			]
+		}
+	}`
+	if err := json.Unmarshal([]byte(config), &rule); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	check, err := factory.NewRuleFactory().CreateRule(rule)
+	if err != nil {
+		t.Fatalf("CreateRule failed: %v", err)
+	}
+
+	day, _ := time.Parse(time.RFC3339, "2023-01-02T10:00:00Z")
+	night, _ := time.Parse(time.RFC3339, "2023-01-02T23:00:00Z")
+
+	cases := []struct {
+		name   string
+		ts     time.Time
+		value  float64
+		passed bool
+	}{
+		{"in range at night", night, 50, true},
+		{"out of range during business hours", day, 500, true},
+		{"out of range at night", night, 500, false},
+	}
+	for _, c := range cases {
+		res := check.Check(ports.CleaningContext{}, domain.Reading{Timestamp: c.ts, Value: c.value})
+		if res.Passed != c.passed {
+			t.Errorf("%s: expected passed=%v, got %v (%s)", c.name, c.passed, res.Passed, res.Reason)
+		}
+		if res.RuleID != "night-overload" {
+			t.Errorf("%s: expected rule id to be attached, got %q", c.name, res.RuleID)
+		}
+	}
+}
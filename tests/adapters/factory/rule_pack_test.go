@@ -0,0 +1,76 @@
+package factory_test
+
+import (
+	"testing"
+
+	"github.com/renjie/prism-core/pkg/adapters/factory"
+	"github.com/renjie/prism-core/pkg/adapters/factory/factorytest"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func buildPackRule(params map[string]interface{}, action domain.RuleAction) (ports.CleaningRule, error) {
+	return &rules.RangeRule{Min: 0, Max: 1000, Action: domain.ActionReject}, nil
+}
+
+func TestRegisterPackConflicts(t *testing.T) {
+	f := factory.NewRuleFactory()
+	pack := factory.RulePack{Namespace: "acme", Rules: map[string]factory.RuleBuilder{"CAP": buildPackRule}}
+
+	if err := f.RegisterPack(pack); err != nil {
+		t.Fatalf("RegisterPack failed: %v", err)
+	}
+	if err := f.RegisterPack(pack); err == nil {
+		t.Fatalf("expected conflict error on duplicate registration")
+	}
+	if err := f.RegisterPack(factory.RulePack{Namespace: "Bad Namespace", Rules: pack.Rules}); err == nil {
+		t.Fatalf("expected error for invalid namespace")
+	}
+
+	if _, err := f.CreateRule(domain.CleaningRule{Type: factory.NamespacedType("acme", "CAP")}); err != nil {
+		t.Fatalf("CreateRule for namespaced type failed: %v", err)
+	}
+}
+
+func TestBuiltinRulesConformance(t *testing.T) {
+	f := factory.NewRuleFactory()
+	builderFor := func(ruleType domain.RuleType) factory.RuleBuilder {
+		return func(params map[string]interface{}, action domain.RuleAction) (ports.CleaningRule, error) {
+			return f.CreateRule(domain.CleaningRule{Type: ruleType, Parameters: params, Action: action})
+		}
+	}
+
+	suites := map[domain.RuleType]factorytest.Suite{
+		domain.RuleTypeRange: {
+			ValidParams:   map[string]any{"min": 0.0, "max": 1000.0},
+			InvalidParams: []map[string]any{nil, {"min": "0"}},
+			Actions:       []domain.RuleAction{"", domain.ActionReject, domain.ActionCorrect},
+		},
+		domain.RuleTypeSpike: {
+			ValidParams:   map[string]any{"threshold": 10.0},
+			InvalidParams: []map[string]any{nil, {"threshold": -1.0}},
+			Actions:       []domain.RuleAction{"", domain.ActionReject, domain.ActionCorrect},
+		},
+		domain.RuleTypeBidirectional: {
+			ValidParams:   map[string]any{"max_rate": 100.0},
+			InvalidParams: []map[string]any{nil, {"max_rate": 10.0, "rollover": "x"}},
+		},
+		domain.RuleTypeScript: {
+			ValidParams:   map[string]any{"condition": "!has_prev || value >= prev.value", "correction": "prev.value"},
+			InvalidParams: []map[string]any{nil, {"condition": "os.Exit(1)"}, {"condition": "func() {}"}},
+			Actions:       []domain.RuleAction{"", domain.ActionReject, domain.ActionCorrect},
+		},
+		domain.RuleTypeImpute: {
+			ValidParams:   map[string]any{"strategy": "LINEAR"},
+			InvalidParams: []map[string]any{{"strategy": "MEDIAN"}},
+		},
+	}
+
+	for ruleType, suite := range suites {
+		suite.Builder = builderFor(ruleType)
+		t.Run(string(ruleType), func(t *testing.T) {
+			factorytest.Run(t, suite)
+		})
+	}
+}
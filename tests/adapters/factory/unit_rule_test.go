@@ -0,0 +1,32 @@
+package factory_test
+
+import (
+	"testing"
+
+	"github.com/renjie/prism-core/pkg/adapters/factory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+func TestRangeRuleWithUnit(t *testing.T) {
+	rule, err := factory.NewRuleFactory().CreateRule(domain.CleaningRule{
+		Type:       domain.RuleTypeRange,
+		Parameters: map[string]any{"min": 0.0, "max": 500.0, "unit": "kWh"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRule failed: %v", err)
+	}
+
+	whMeter := domain.DeviceInfo{ID: "E1", Unit: domain.UnitWh}
+	if res := rule.Check(ports.CleaningContext{}, domain.Reading{DeviceInfo: whMeter, Value: 400000}); !res.Passed {
+		t.Errorf("400000 Wh should be within 500 kWh: %s", res.Reason)
+	}
+	if res := rule.Check(ports.CleaningContext{}, domain.Reading{DeviceInfo: whMeter, Value: 600000}); res.Passed {
+		t.Errorf("600000 Wh should exceed 500 kWh")
+	}
+
+	gasMeter := domain.DeviceInfo{ID: "G1", Unit: domain.UnitM3}
+	if res := rule.Check(ports.CleaningContext{}, domain.Reading{DeviceInfo: gasMeter, Value: 1}); res.Passed {
+		t.Errorf("incompatible units should be rejected")
+	}
+}
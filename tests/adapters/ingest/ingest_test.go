@@ -0,0 +1,299 @@
+package ingest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/ingest"
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestIngestionResultReportsThroughput(t *testing.T) {
+	discard := func(context.Context, []domain.Reading) error { return nil }
+	cases := map[string]struct {
+		ingestor ports.UniversalIngestor
+		input    string
+	}{
+		"csv": {
+			ingest.NewCsvUniversalIngestor(discard),
+			"device_id,type,timestamp,value\n" +
+				"M1,ELEC,2024-01-01T00:00:00Z,10\n" +
+				"M1,ELEC,2024-01-01T00:15:00Z,11\n",
+		},
+		"json": {
+			ingest.NewJsonUniversalIngestor(discard),
+			`[{"device_id":"M1","type":"ELEC","timestamp":"2024-01-01T00:00:00Z","value":10},` +
+				`{"device_id":"M1","type":"ELEC","timestamp":"2024-01-01T00:15:00Z","value":11}]`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			result, err := tc.ingestor.IngestStream(context.Background(), strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Total != 2 {
+				t.Fatalf("expected 2 records, got %+v", result)
+			}
+			if result.BytesRead != int64(len(tc.input)) {
+				t.Errorf("expected %d bytes read, got %d", len(tc.input), result.BytesRead)
+			}
+			if result.Duration <= 0 || result.RecordsPerSecond <= 0 {
+				t.Errorf("expected positive duration and throughput, got %v and %v", result.Duration, result.RecordsPerSecond)
+			}
+		})
+	}
+}
+
+func TestDeliveryFailureIsDeadLettered(t *testing.T) {
+	calls := 0
+	failing := func(context.Context, []domain.Reading) error {
+		calls++
+		return errors.New("storage unavailable")
+	}
+	dlq := memory.NewDeadLetterRepository()
+	ingestor := ingest.NewCsvUniversalIngestor(failing,
+		ingest.WithDeliveryRetry(3, time.Millisecond), ingest.WithDeadLetterSink(dlq))
+
+	csv := "device_id,type,timestamp,value\n" +
+		"M1,ELEC,2024-01-01T00:00:00Z,10\n" +
+		"M1,ELEC,bad,11\n"
+	result, err := ingestor.IngestStream(context.Background(), strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("expected dead-lettered batch not to abort ingestion, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 delivery attempts, got %d", calls)
+	}
+	if result.Success != 0 || result.Failed != 1 || result.DeadLettered != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	letters, _ := dlq.List(context.Background(), 0)
+	if len(letters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(letters))
+	}
+	l := letters[0]
+	if l.Source != "csv" || l.Attempts != 3 || l.Error != "storage unavailable" || len(l.Readings) != 1 || l.Readings[0].Value != 10 {
+		t.Errorf("unexpected dead letter %+v", l)
+	}
+}
+
+func TestDeliveryFailureWithoutSinkAborts(t *testing.T) {
+	failing := func(context.Context, []domain.Reading) error { return errors.New("storage unavailable") }
+	ingestor := ingest.NewJsonUniversalIngestor(failing)
+	input := `[{"device_id":"M1","type":"ELEC","timestamp":"2024-01-01T00:00:00Z","value":10}]`
+	if _, err := ingestor.IngestStream(context.Background(), strings.NewReader(input)); err == nil {
+		t.Fatal("expected delivery error without a dead letter sink")
+	}
+}
+
+func TestResubmittedBatchIsRejectedOrSkipped(t *testing.T) {
+	csv := "device_id,type,timestamp,value\n" +
+		"M1,ELEC,2024-01-01T00:00:00Z,10\n" +
+		"M1,ELEC,2024-01-01T00:15:00Z,11\n"
+	ctx := domain.NewContext(context.Background(), domain.IngestContext{BatchID: "upload-1"})
+
+	for _, skip := range []bool{false, true} {
+		delivered := 0
+		downstream := func(_ context.Context, readings []domain.Reading) error {
+			delivered += len(readings)
+			return nil
+		}
+		ingestor := ingest.NewCsvUniversalIngestor(downstream, ingest.WithBatchRegistry(memory.NewBatchRegistry(), skip))
+
+		if _, err := ingestor.IngestStream(ctx, strings.NewReader(csv)); err != nil {
+			t.Fatal(err)
+		}
+		result, err := ingestor.IngestStream(ctx, strings.NewReader(csv))
+		if delivered != 2 {
+			t.Errorf("skip=%v: expected the batch to be delivered once, got %d readings", skip, delivered)
+		}
+		if !skip {
+			if !errors.Is(err, ports.ErrBatchAlreadyProcessed) {
+				t.Errorf("expected ErrBatchAlreadyProcessed, got %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Total != 2 || result.Success != 0 || result.Skipped != 2 {
+			t.Errorf("expected the resubmission to be counted as skipped, got %+v", result)
+		}
+	}
+
+	// 其他批次不受影响
+	other := domain.NewContext(context.Background(), domain.IngestContext{BatchID: "upload-2"})
+	registry := memory.NewBatchRegistry()
+	_ = registry.MarkCompleted(ctx, "upload-1", time.Now())
+	ingestor := ingest.NewCsvUniversalIngestor(func(context.Context, []domain.Reading) error { return nil }, ingest.WithBatchRegistry(registry, false))
+	if result, err := ingestor.IngestStream(other, strings.NewReader(csv)); err != nil || result.Success != 2 {
+		t.Errorf("expected a new batch to be ingested, got %+v, %v", result, err)
+	}
+}
+
+func TestConcurrentBatchSubmissionsIngestOnce(t *testing.T) {
+	csv := "device_id,type,timestamp,value\n" +
+		"M1,ELEC,2024-01-01T00:00:00Z,10\n" +
+		"M1,ELEC,2024-01-01T00:15:00Z,11\n"
+	ctx := domain.NewContext(context.Background(), domain.IngestContext{BatchID: "upload-1"})
+
+	var delivered atomic.Int64
+	ingestor := ingest.NewCsvUniversalIngestor(func(_ context.Context, readings []domain.Reading) error {
+		delivered.Add(int64(len(readings)))
+		return nil
+	}, ingest.WithBatchRegistry(memory.NewBatchRegistry(), false))
+
+	const submissions = 32
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make(chan error, submissions)
+	)
+	for range submissions {
+		wg.Go(func() {
+			<-start
+			_, err := ingestor.IngestStream(ctx, strings.NewReader(csv))
+			errs <- err
+		})
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ports.ErrBatchInProgress), errors.Is(err, ports.ErrBatchAlreadyProcessed):
+		default:
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	if succeeded != 1 || delivered.Load() != 2 {
+		t.Errorf("expected exactly one submission to deliver the batch, got %d successes and %d readings", succeeded, delivered.Load())
+	}
+}
+
+func TestBatchClaimReleasedOnFailure(t *testing.T) {
+	csv := "device_id,type,timestamp,value\nM1,ELEC,2024-01-01T00:00:00Z,10\n"
+	ctx := domain.NewContext(context.Background(), domain.IngestContext{BatchID: "upload-1"})
+	registry := memory.NewBatchRegistry()
+
+	entered, unblock := make(chan struct{}), make(chan struct{})
+	failing := ingest.NewCsvUniversalIngestor(func(context.Context, []domain.Reading) error {
+		close(entered)
+		<-unblock
+		return errors.New("downstream unavailable")
+	}, ingest.WithBatchRegistry(registry, true))
+	ok := ingest.NewCsvUniversalIngestor(func(context.Context, []domain.Reading) error { return nil }, ingest.WithBatchRegistry(registry, true))
+
+	failed := make(chan error, 1)
+	go func() {
+		_, err := failing.IngestStream(ctx, strings.NewReader(csv))
+		failed <- err
+	}()
+	<-entered
+	if _, err := ok.IngestStream(ctx, strings.NewReader(csv)); !errors.Is(err, ports.ErrBatchInProgress) {
+		t.Errorf("expected a submission during processing to be rejected as in progress, got %v", err)
+	}
+	close(unblock)
+	if err := <-failed; err == nil {
+		t.Fatal("expected the first submission to fail")
+	}
+
+	// 失败释放认领后，同一批次可以重新提交
+	if result, err := ok.IngestStream(ctx, strings.NewReader(csv)); err != nil || result.Success != 1 {
+		t.Errorf("expected the retried batch to be ingested, got %+v, %v", result, err)
+	}
+	if result, err := ok.IngestStream(ctx, strings.NewReader(csv)); err != nil || result.Skipped != 1 {
+		t.Errorf("expected the completed batch to be skipped, got %+v, %v", result, err)
+	}
+}
+
+func TestIngestPseudonymizesDeviceIDs(t *testing.T) {
+	var delivered []domain.Reading
+	downstream := func(_ context.Context, readings []domain.Reading) error {
+		delivered = append(delivered, readings...)
+		return nil
+	}
+	allowAll := ports.AuthorizerFunc(func(context.Context, ports.AuthRequest) error { return nil })
+	p, err := services.NewHMACPseudonymizer([]byte("0123456789abcdef"), memory.NewPseudonymRepository(), allowAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ingestor := ingest.NewJsonUniversalIngestor(downstream, ingest.WithPseudonymizer(p))
+	input := `[{"device_id":"METER-001","type":"ELEC","timestamp":"2024-01-01T00:00:00Z","value":10},` +
+		`{"device_id":"METER-001","type":"ELEC","timestamp":"2024-01-01T00:15:00Z","value":11}]`
+	if _, err := ingestor.IngestStream(context.Background(), strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if len(delivered) != 2 || delivered[0].DeviceInfo.ID == "METER-001" || delivered[0].DeviceInfo.ID != delivered[1].DeviceInfo.ID {
+		t.Fatalf("expected a stable pseudonym downstream, got %+v", delivered)
+	}
+	if id, err := p.Reveal(context.Background(), delivered[0].DeviceInfo.ID, "dpo"); err != nil || id != "METER-001" {
+		t.Errorf("expected the pseudonym to be revealable, got %q, %v", id, err)
+	}
+}
+
+func TestDataContractMapsAndDetectsDrift(t *testing.T) {
+	registry := memory.NewContractRegistry()
+	_ = registry.Register(context.Background(), domain.DataContract{
+		Source:   "scada",
+		Version:  "v2",
+		Fields:   []string{"meter_no", "ts", "kwh"},
+		Optional: []string{"type"},
+		Mapping:  map[string]string{"meter_no": "device_id", "ts": "timestamp", "kwh": "value"},
+	})
+	var delivered []domain.Reading
+	downstream := func(_ context.Context, readings []domain.Reading) error {
+		delivered = append(delivered, readings...)
+		return nil
+	}
+	csvIngestor := ingest.NewCsvUniversalIngestor(downstream, ingest.WithDataContract(registry, "scada"))
+	jsonIngestor := ingest.NewJsonUniversalIngestor(downstream, ingest.WithDataContract(registry, "scada"))
+
+	if _, err := csvIngestor.IngestStream(context.Background(), strings.NewReader("meter_no,ts,kwh\nM1,2024-01-01T00:00:00Z,10\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jsonIngestor.IngestStream(context.Background(), strings.NewReader(`[{"meter_no":"M2","ts":"2024-01-01T00:00:00Z","kwh":11,"type":"ELEC"}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if len(delivered) != 2 || delivered[0].DeviceInfo.ID != "M1" || delivered[0].Value != 10 || delivered[1].DeviceInfo.ID != "M2" || delivered[1].Value != 11 {
+		t.Fatalf("expected mapped readings, got %+v", delivered)
+	}
+
+	// 上游把 kwh 改名为 energy: 不能静默产生读数
+	drifted := map[string]ports.UniversalIngestor{
+		"csv":  csvIngestor,
+		"json": jsonIngestor,
+	}
+	inputs := map[string]string{
+		"csv":  "meter_no,ts,energy\nM1,2024-01-01T00:15:00Z,12\n",
+		"json": `[{"meter_no":"M2","ts":"2024-01-01T00:15:00Z","energy":12}]`,
+	}
+	for name, ingestor := range drifted {
+		_, err := ingestor.IngestStream(context.Background(), strings.NewReader(inputs[name]))
+		if !errors.Is(err, domain.ErrContractViolation) || !strings.Contains(err.Error(), "missing kwh") || !strings.Contains(err.Error(), "unexpected energy") {
+			t.Errorf("%s: expected a contract violation, got %v", name, err)
+		}
+	}
+	if len(delivered) != 2 {
+		t.Errorf("expected drifted input not to be delivered, got %d readings", len(delivered))
+	}
+
+	unknown := ingest.NewCsvUniversalIngestor(downstream, ingest.WithDataContract(registry, "erp"))
+	if _, err := unknown.IngestStream(context.Background(), strings.NewReader("device_id,timestamp,value\n")); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected an unregistered source to fail, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/notify"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+var notification = ports.Notification{
+	Kind:       "QUARANTINE_SURGE",
+	Title:      "Quarantine surge on device D1",
+	Message:    "12 of 12 readings quarantined",
+	Labels:     map[string]string{"device_id": "D1", "scope": "device"},
+	OccurredAt: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got ports.Notification
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	webhook, err := notify.NewWebhookNotifier(server.URL+"/hook", notify.WithHeader("Authorization", "Bearer token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := webhook.Notify(context.Background(), notification); err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != notification.Title || got.Labels["device_id"] != "D1" || auth != "Bearer token" {
+		t.Errorf("unexpected webhook request: %+v (auth %q)", got, auth)
+	}
+
+	failing, _ := notify.NewWebhookNotifier(server.URL + "/fail")
+	if err := failing.Notify(context.Background(), notification); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
+
+func TestEmailNotifierAndFanout(t *testing.T) {
+	var msg string
+	var rcpt []string
+	email, err := notify.NewEmailNotifier("smtp.example.com:587", "prism@example.com", []string{"ops@example.com"},
+		notify.WithSendMail(func(addr string, auth smtp.Auth, from string, to []string, body []byte) error {
+			msg, rcpt = string(body), to
+			return nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := notifierFunc(func(context.Context, ports.Notification) error { return errors.New("im bot down") })
+
+	err = notify.Fanout{failing, email}.Notify(context.Background(), notification)
+	if err == nil || !strings.Contains(err.Error(), "im bot down") {
+		t.Fatalf("expected fanout to return the failing target's error, got %v", err)
+	}
+	if len(rcpt) != 1 || !strings.Contains(msg, "Subject: [prism] Quarantine surge on device D1\r\n") ||
+		!strings.Contains(msg, "device_id: D1\r\nscope: device\r\n") {
+		t.Errorf("unexpected email (sent to %v):\n%s", rcpt, msg)
+	}
+}
+
+type notifierFunc func(context.Context, ports.Notification) error
+
+func (f notifierFunc) Notify(ctx context.Context, n ports.Notification) error { return f(ctx, n) }
@@ -0,0 +1,449 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestStandardRepositoryUpsertStrategies(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	sr := func(value int64, priority int) domain.StandardReading {
+		return domain.StandardReading{DeviceID: "D1", Timestamp: tBase, Resolution: "15m", ValueScaled: value, Priority: priority}
+	}
+
+	repo := memory.NewStandardReadingRepository()
+	_ = repo.Save(ctx, sr(1, 1000), ports.UpsertStrategyHighPriorityWins)
+	_ = repo.Save(ctx, sr(2, 50), ports.UpsertStrategyHighPriorityWins) // lower priority: ignored
+	if got, _ := repo.FindExact(ctx, "D1", tBase); got.ValueScaled != 1 {
+		t.Errorf("HIGH_PRIORITY_WINS: expected calibration value to survive, got %d", got.ValueScaled)
+	}
+	_ = repo.Save(ctx, sr(3, 50), ports.UpsertStrategyLastWriteWins)
+	if got, _ := repo.FindExact(ctx, "D1", tBase); got.ValueScaled != 3 {
+		t.Errorf("LAST_WRITE_WINS: expected overwrite, got %d", got.ValueScaled)
+	}
+
+	// A replayed batch is not applied again
+	_ = repo.SaveBatch(ctx, []domain.StandardReading{sr(4, 50)}, ports.UpsertStrategyLastWriteWins, "k")
+	_ = repo.Save(ctx, sr(5, 50), ports.UpsertStrategyLastWriteWins)
+	_ = repo.SaveBatch(ctx, []domain.StandardReading{sr(4, 50)}, ports.UpsertStrategyLastWriteWins, "k")
+	if got, _ := repo.FindExact(ctx, "D1", tBase); got.ValueScaled != 5 {
+		t.Errorf("expected replayed batch to be skipped, got %d", got.ValueScaled)
+	}
+
+	if err := repo.Save(ctx, sr(6, 50), ""); err == nil {
+		t.Error("expected an error for an empty strategy")
+	}
+}
+
+func TestStandardRepositoryFailedBatchCanBeRetried(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	outbox := memory.NewOutbox()
+	repo := memory.NewStandardReadingRepository(memory.WithOutbox(outbox, "standards"))
+
+	// NaN cannot be encoded into the outbox message: the batch fails before it is fully applied
+	bad := domain.StandardReading{DeviceID: "D1", Timestamp: tBase, Resolution: "15m", ValueDisplay: math.NaN()}
+	if err := repo.SaveBatch(ctx, []domain.StandardReading{bad}, ports.UpsertStrategyLastWriteWins, "k"); err == nil {
+		t.Fatal("expected the batch to fail")
+	}
+
+	good := domain.StandardReading{DeviceID: "D1", Timestamp: tBase, Resolution: "15m", ValueScaled: 1, ValueDisplay: 1}
+	if err := repo.SaveBatch(ctx, []domain.StandardReading{good}, ports.UpsertStrategyLastWriteWins, "k"); err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	if got, _ := repo.FindExact(ctx, "D1", tBase); got.ValueScaled != 1 {
+		t.Errorf("expected the retried batch to be applied, got %+v", got)
+	}
+	if outbox.Len() != 1 {
+		t.Errorf("expected the retried batch to be published, got %d messages", outbox.Len())
+	}
+}
+
+func TestStandardRepositoryBoundsIdempotencyKeys(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	sr := func(value int64) []domain.StandardReading {
+		return []domain.StandardReading{{DeviceID: "D1", Timestamp: tBase, Resolution: "15m", ValueScaled: value}}
+	}
+
+	repo := memory.NewStandardReadingRepository(memory.WithMaxSize(2))
+	for i, key := range []string{"k1", "k2", "k3"} {
+		_ = repo.SaveBatch(ctx, sr(int64(i+1)), ports.UpsertStrategyLastWriteWins, key)
+	}
+
+	// k1 was evicted by the size bound and is applied again; k3 is still remembered
+	_ = repo.SaveBatch(ctx, sr(10), ports.UpsertStrategyLastWriteWins, "k1")
+	if got, _ := repo.FindExact(ctx, "D1", tBase); got.ValueScaled != 10 {
+		t.Errorf("expected the evicted key to be forgotten, got %d", got.ValueScaled)
+	}
+	_ = repo.SaveBatch(ctx, sr(20), ports.UpsertStrategyLastWriteWins, "k3")
+	if got, _ := repo.FindExact(ctx, "D1", tBase); got.ValueScaled != 10 {
+		t.Errorf("expected the recent key to be remembered, got %d", got.ValueScaled)
+	}
+}
+
+func TestStandardRepositoryVersions(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	sr := func(value int64, priority int) domain.StandardReading {
+		return domain.StandardReading{DeviceID: "D1", Timestamp: tBase, Resolution: "15m", ValueScaled: value, Priority: priority}
+	}
+
+	repo := memory.NewStandardReadingRepository(memory.WithVersioning())
+	_ = repo.Save(ctx, sr(1, 100), ports.UpsertStrategyHighPriorityWins)
+	_ = repo.Save(ctx, sr(1, 100), ports.UpsertStrategyHighPriorityWins) // replay: no new version
+	_ = repo.Save(ctx, sr(2, 50), ports.UpsertStrategyHighPriorityWins)  // rejected: no new version
+	_ = repo.SaveBatch(ctx, []domain.StandardReading{sr(3, 1000)}, ports.UpsertStrategyHighPriorityWins, "")
+
+	versions, err := repo.FindVersions(ctx, "D1", tBase)
+	if err != nil {
+		t.Fatalf("FindVersions failed: %v", err)
+	}
+	if len(versions) != 2 || versions[0].ValueScaled != 1 || versions[0].Version != 1 ||
+		versions[1].ValueScaled != 3 || versions[1].Version != 2 {
+		t.Fatalf("expected the calibrated value to supersede version 1, got %+v", versions)
+	}
+	if got, _ := repo.FindExact(ctx, "D1", tBase); got.Version != 0 {
+		t.Errorf("expected plain queries to leave Version unset, got %d", got.Version)
+	}
+
+	// Retention removes history together with the current reading
+	if _, err := repo.DeleteOlderThan(ctx, "", "", tBase.Add(time.Hour)); err != nil {
+		t.Fatalf("DeleteOlderThan failed: %v", err)
+	}
+	_ = repo.Save(ctx, sr(4, 50), ports.UpsertStrategyLastWriteWins)
+	if versions, _ := repo.FindVersions(ctx, "D1", tBase); len(versions) != 1 || versions[0].Version != 1 {
+		t.Errorf("expected history to be deleted with the reading, got %+v", versions)
+	}
+
+	// Without WithVersioning only the current version is kept
+	plain := memory.NewStandardReadingRepository()
+	_ = plain.Save(ctx, sr(1, 50), ports.UpsertStrategyLastWriteWins)
+	_ = plain.Save(ctx, sr(2, 50), ports.UpsertStrategyLastWriteWins)
+	if versions, _ := plain.FindVersions(ctx, "D1", tBase); len(versions) != 1 || versions[0].ValueScaled != 2 {
+		t.Errorf("expected only the current version, got %+v", versions)
+	}
+}
+
+func TestStandardRepositoryLatest(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+
+	if _, err := repo.FindLatest(ctx, "D1"); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown device, got %v", err)
+	}
+	_ = repo.SaveBatch(ctx, []domain.StandardReading{
+		{DeviceID: "D1", Timestamp: tBase, Resolution: "15m", ValueScaled: 1},
+		{DeviceID: "D1", Timestamp: tBase.Add(15 * time.Minute), Resolution: "15m", ValueScaled: 2},
+		{DeviceID: "D1", Timestamp: tBase.Add(15 * time.Minute), Resolution: "1h", ValueScaled: 3},
+		{DeviceID: "D1", Timestamp: tBase.Add(30 * time.Minute), Resolution: "1h", Metric: domain.MetricPower, ValueScaled: 4},
+	}, ports.UpsertStrategyLastWriteWins, "")
+
+	if got, err := repo.FindLatest(ctx, "D1"); err != nil || got.ValueScaled != 4 {
+		t.Errorf("expected the most recent reading, got %+v (%v)", got, err)
+	}
+	if got, err := repo.FindLastBefore(ctx, "D1", "", "15m", tBase.Add(30*time.Minute)); err != nil || got.ValueScaled != 2 {
+		t.Errorf("expected the last 15m reading before 10:30, got %+v (%v)", got, err)
+	}
+	if _, err := repo.FindLastBefore(ctx, "D1", "", "15m", tBase); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected ErrNotFound before the first reading, got %v", err)
+	}
+}
+
+func TestStandardRepositoryTTLAndMaxSize(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := tBase
+	clock := func() time.Time { return now }
+
+	repo := memory.NewStandardReadingRepository(memory.WithTTL(time.Hour), memory.WithMaxSize(2), memory.WithClock(clock))
+	for i := range 3 {
+		_ = repo.Save(ctx, domain.StandardReading{DeviceID: "D1", Timestamp: tBase.Add(time.Duration(i) * 15 * time.Minute), Resolution: "15m"},
+			ports.UpsertStrategyHighPriorityWins)
+	}
+	got, _ := repo.FindRange(ctx, "D1", tBase, tBase.Add(time.Hour))
+	if len(got) != 2 || !got[0].Timestamp.Equal(tBase.Add(15*time.Minute)) {
+		t.Errorf("expected the oldest write to be evicted, got %+v", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := repo.FindLastBefore(ctx, "D1", "", "15m", tBase.Add(time.Hour)); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected entries to expire after the TTL, got %v", err)
+	}
+	if repo.Len() != 0 {
+		t.Errorf("expected an empty repository, got %d entries", repo.Len())
+	}
+}
+
+func TestQuarantineRepositoryPending(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewQuarantineRepository()
+	dev := domain.DeviceInfo{ID: "D1", Type: domain.DeviceTypeElec}
+
+	_ = repo.Save(ctx, domain.QuarantineReading{Reading: domain.Reading{DeviceInfo: dev}})
+	_ = repo.Save(ctx, domain.QuarantineReading{Reading: domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "W1", Type: domain.DeviceTypeWater}}})
+
+	pending, _ := repo.FindPendingByDeviceType(ctx, domain.DeviceTypeElec, 0)
+	if len(pending) != 1 || pending[0].ID == "" || pending[0].Status != domain.QuarantineStatusPending {
+		t.Fatalf("expected one pending record with a generated id, got %+v", pending)
+	}
+
+	resolved := pending[0]
+	resolved.Status = domain.QuarantineStatusResolved
+	_ = repo.Save(ctx, resolved)
+	if all, _ := repo.FindPending(ctx, 0); len(all) != 1 || all[0].Reading.DeviceInfo.ID != "W1" {
+		t.Errorf("expected the resolved record to leave the pending list, got %+v", all)
+	}
+}
+
+func TestQuarantineRepositoryUpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewQuarantineRepository()
+	_ = repo.Save(ctx, domain.QuarantineReading{Reading: domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "D1"}}})
+	pending, _ := repo.FindPending(ctx, 0)
+
+	resolved := pending[0]
+	resolved.Status = domain.QuarantineStatusResolved
+	if err := repo.UpdateStatus(ctx, resolved, domain.QuarantineStatusPending); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	ignored := pending[0]
+	ignored.Status = domain.QuarantineStatusIgnored
+	if err := repo.UpdateStatus(ctx, ignored, domain.QuarantineStatusPending); !errors.Is(err, ports.ErrQuarantineStatusChanged) {
+		t.Errorf("expected ErrQuarantineStatusChanged for a stale status, got %v", err)
+	}
+	if got, _ := repo.Find(ctx, ports.QuarantineFilter{ID: resolved.ID}); got[0].Status != domain.QuarantineStatusResolved {
+		t.Errorf("expected the first update to stick, got %s", got[0].Status)
+	}
+	if err := repo.UpdateStatus(domain.WithTenant(ctx, "other"), ignored, domain.QuarantineStatusResolved); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected ErrNotFound from another tenant, got %v", err)
+	}
+}
+
+func TestQuarantineRepositoryFindAndCount(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewQuarantineRepository()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	elec := domain.DeviceInfo{ID: "D1", Type: domain.DeviceTypeElec}
+	water := domain.DeviceInfo{ID: "W1", Type: domain.DeviceTypeWater}
+
+	_ = repo.Save(ctx, domain.QuarantineReading{Reading: domain.Reading{DeviceInfo: elec, Timestamp: tBase}, RuleID: "range", Reason: "Value -5 below range min 0"})
+	_ = repo.Save(ctx, domain.QuarantineReading{Reading: domain.Reading{DeviceInfo: elec, Timestamp: tBase.Add(time.Hour)}, RuleID: "range", Reason: "Value 900 above range MAX"})
+	_ = repo.Save(ctx, domain.QuarantineReading{Reading: domain.Reading{DeviceInfo: water, Timestamp: tBase}, RuleID: "spike", Status: domain.QuarantineStatusIgnored})
+
+	found, err := repo.Find(ctx, ports.QuarantineFilter{RuleID: "range", ReasonContains: "max", End: tBase.Add(2 * time.Hour)})
+	if err != nil || len(found) != 1 || found[0].Reading.Timestamp != tBase.Add(time.Hour) {
+		t.Fatalf("expected the case-insensitive reason match, got %+v (%v)", found, err)
+	}
+	if page, _ := repo.Find(ctx, ports.QuarantineFilter{Offset: 1, Limit: 1}); len(page) != 1 || page[0].Reading.Timestamp != tBase.Add(time.Hour) {
+		t.Errorf("expected offset paging in creation order, got %+v", page)
+	}
+
+	counts, err := repo.Count(ctx, ports.QuarantineFilter{}, ports.QuarantineGroupRule)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if want := []ports.QuarantineCount{{Key: "range", Count: 2}, {Key: "spike", Count: 1}}; !slices.Equal(counts, want) {
+		t.Errorf("expected %v, got %v", want, counts)
+	}
+	if total, _ := repo.Count(ctx, ports.QuarantineFilter{Status: domain.QuarantineStatusPending, DeviceType: domain.DeviceTypeWater}, ports.QuarantineGroupNone); len(total) != 1 || total[0].Count != 0 {
+		t.Errorf("expected a zero total, got %v", total)
+	}
+	if _, err := repo.Count(ctx, ports.QuarantineFilter{}, "reason"); err == nil {
+		t.Error("expected unsupported group by to be rejected")
+	}
+}
+
+func TestRepositoriesWithStandardizer(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "D1", Type: domain.DeviceTypeElec}
+
+	standards := memory.NewStandardReadingRepository()
+	quarantine := memory.NewQuarantineRepository()
+	standardizer := services.NewCoreStandardizer(
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 1e6, Action: domain.ActionReject}),
+		services.WithRepository(standards),
+		services.WithQuarantineRepository(quarantine),
+		services.WithQuarantinePolicy(services.QuarantinePolicy{Mode: services.QuarantineSync}),
+	)
+	_, err := standardizer.ProcessAndStandardize(ctx, []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 100},
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: -5}, // rejected by the range rule
+		{DeviceInfo: dev, Timestamp: tBase.Add(30 * time.Minute), Value: 110},
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+
+	if got, _ := standards.FindRange(ctx, "D1", tBase, tBase.Add(time.Hour)); len(got) != 2 {
+		t.Errorf("expected 2 persisted standards, got %+v", got)
+	}
+	if quarantine.Len() != 1 {
+		t.Errorf("expected 1 quarantine record, got %d", quarantine.Len())
+	}
+}
+
+func TestStandardRepositoryAggregation(t *testing.T) {
+	ctx := context.Background()
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	repo := memory.NewStandardReadingRepository()
+
+	// Hourly usage from 2023-01-01 14:00 UTC (22:00 local) for 4 hours: spans two local days
+	tBase := time.Date(2023, 1, 1, 14, 0, 0, 0, time.UTC)
+	var batch []domain.StandardReading
+	for i := range 4 {
+		batch = append(batch, domain.StandardReading{DeviceID: "D1", Timestamp: tBase.Add(time.Duration(i) * time.Hour), Resolution: "1h", ValueDisplay: float64(i + 1)})
+	}
+	batch = append(batch, domain.StandardReading{DeviceID: "D1", Timestamp: tBase.Add(4 * time.Hour), Resolution: "1h", Quality: domain.QualityMissing})
+	_ = repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, "")
+
+	q := ports.AggregateQuery{DeviceID: "D1", Resolution: "1h", Start: tBase, End: tBase.Add(24 * time.Hour), Period: domain.ReportPeriodDay, Location: shanghai}
+	sums, err := repo.SumByPeriod(ctx, q)
+	if err != nil {
+		t.Fatalf("SumByPeriod failed: %v", err)
+	}
+	if len(sums) != 2 || sums[0].Value != 3 || sums[1].Value != 7 || sums[1].Count != 2 {
+		t.Fatalf("expected local-day sums 3 and 7, got %+v", sums)
+	}
+	if !sums[1].Start.Equal(time.Date(2023, 1, 2, 0, 0, 0, 0, shanghai)) {
+		t.Errorf("expected the second bucket to start at local midnight, got %s", sums[1].Start)
+	}
+
+	avgs, _ := repo.AvgByPeriod(ctx, q)
+	if len(avgs) != 2 || avgs[1].Value != 3.5 {
+		t.Errorf("expected a local-day average of 3.5, got %+v", avgs)
+	}
+
+	q.Period = "QUARTER"
+	if _, err := repo.SumByPeriod(ctx, q); err == nil {
+		t.Error("expected an error for an unsupported period")
+	}
+}
+
+func TestStandardRepositoryWithdraw(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := tBase.Add(48 * time.Hour)
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for i := range 4 {
+		batch = append(batch, domain.StandardReading{DeviceID: "D1", Timestamp: tBase.Add(time.Duration(i) * time.Hour), Resolution: "1h", Quality: domain.QualityValid})
+	}
+	_ = repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, "")
+
+	req := ports.WithdrawRequest{DeviceID: "D1", Start: tBase.Add(time.Hour), End: tBase.Add(2 * time.Hour), Reason: "CT ratio misconfigured", Operator: "alice", At: at}
+	withdrawn, err := repo.Withdraw(ctx, req)
+	if err != nil {
+		t.Fatalf("Withdraw failed: %v", err)
+	}
+	if len(withdrawn) != 2 || !withdrawn[0].Timestamp.Equal(tBase.Add(time.Hour)) {
+		t.Fatalf("expected the 11:00 and 12:00 readings to be withdrawn, got %+v", withdrawn)
+	}
+	w := withdrawn[0].Withdrawal
+	if withdrawn[0].Quality != domain.QualityWithdrawn || w == nil || w.Operator != "alice" || w.PreviousQuality != domain.QualityValid || !w.At.Equal(at) {
+		t.Errorf("unexpected withdrawal record: %+v / %+v", withdrawn[0], w)
+	}
+
+	// Withdrawn readings stay queryable; a second withdrawal reports nothing new
+	if all, _ := repo.FindRange(ctx, "D1", tBase, tBase.Add(3*time.Hour)); len(all) != 4 {
+		t.Errorf("withdrawal must not delete readings, got %d", len(all))
+	}
+	if again, _ := repo.Withdraw(ctx, req); len(again) != 0 {
+		t.Errorf("expected already withdrawn readings to be skipped, got %d", len(again))
+	}
+
+	// A corrected reading written to the same key supersedes the withdrawal
+	_ = repo.Save(ctx, domain.StandardReading{DeviceID: "D1", Timestamp: tBase.Add(time.Hour), Resolution: "1h", Quality: domain.QualityCorrected}, ports.UpsertStrategyLastWriteWins)
+	if got, _ := repo.FindLastBefore(ctx, "D1", "", "1h", tBase.Add(90*time.Minute)); got.Withdrawal != nil {
+		t.Errorf("expected the rewrite to clear the withdrawal, got %+v", got.Withdrawal)
+	}
+
+	if _, err := repo.Withdraw(ctx, ports.WithdrawRequest{DeviceID: "D1", Reason: "x"}); err == nil {
+		t.Error("expected an error without an operator")
+	}
+}
+
+func TestCleaningRuleRepositoryWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	repo := memory.NewCleaningRuleRepository(nil)
+	changes, err := repo.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	rule := domain.CleaningRule{ID: "r1", DeviceType: domain.DeviceTypeElec}
+	_ = repo.Save(ctx, rule)
+	rule.DeviceType = domain.DeviceTypeWater
+	_ = repo.Save(ctx, rule)
+	_ = repo.Delete(ctx, "r1")
+	_ = repo.Delete(ctx, "r1") // no-op: nothing to report
+
+	want := []ports.RuleChangeEvent{
+		{Type: ports.RuleChangeSaved, RuleID: "r1", DeviceType: domain.DeviceTypeElec},
+		{Type: ports.RuleChangeSaved, RuleID: "r1", DeviceType: domain.DeviceTypeWater, PreviousDeviceType: domain.DeviceTypeElec},
+		{Type: ports.RuleChangeDeleted, RuleID: "r1", DeviceType: domain.DeviceTypeWater},
+	}
+	for i, w := range want {
+		got := <-changes
+		got.At = time.Time{}
+		if got != w {
+			t.Errorf("event %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestEnergyReportRepositoryStoresGeneratedReports(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	standard := memory.NewStandardReadingRepository()
+	for h := range 48 {
+		sr := domain.StandardReading{DeviceID: "M1", Metric: domain.MetricEnergy, Timestamp: day.Add(time.Duration(h) * time.Hour),
+			Resolution: "1h", ValueScaled: 10, ScaleFactor: 10, Quality: domain.QualityValid}
+		_ = standard.Save(ctx, sr, ports.UpsertStrategyLastWriteWins)
+	}
+	generated, err := services.NewReportGenerator(standard).Generate(ctx, ports.ReportQuery{Device: domain.DeviceInfo{ID: "M1"},
+		Metric: domain.MetricEnergy, Resolution: "1h", Period: domain.ReportPeriodDay, Start: day, End: day.AddDate(0, 0, 2)})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	reports := memory.NewEnergyReportRepository()
+	if err := reports.Save(ctx, generated...); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := reports.Save(ctx, generated[1]); err != nil { // regenerating a period overwrites it
+		t.Fatalf("Save failed: %v", err)
+	}
+	filter := ports.EnergyReportFilter{DeviceID: "M1", Metric: domain.MetricEnergy, Period: domain.ReportPeriodDay, Start: day, End: day.AddDate(0, 0, 7)}
+	got, err := reports.Find(ctx, filter)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(got) != 2 || !got[0].StartTime.Equal(day) || got[1].UsageScaled != 240 || got[1].Metric != domain.MetricEnergy {
+		t.Errorf("unexpected stored reports: %+v", got)
+	}
+	filter.Metric = ""
+	if got, _ := reports.Find(ctx, filter); len(got) != 0 {
+		t.Errorf("expected reports of another channel to be excluded, got %+v", got)
+	}
+}
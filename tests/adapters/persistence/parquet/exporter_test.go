@@ -0,0 +1,127 @@
+package parquet_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/adapters/persistence/parquet"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+func TestExportPartitionsByDeviceAndDay(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for _, id := range []string{"D1", "site/2"} {
+		for i := range 4 {
+			ts := day.Add(time.Duration(i) * 12 * time.Hour) // two readings per day over two days
+			batch = append(batch, domain.StandardReading{DeviceID: id, Timestamp: ts, Resolution: "15m",
+				ValueScaled: int64(i), ScaleFactor: 100, Quality: domain.QualityValid, SourceType: domain.ReadingTypeStandard})
+		}
+	}
+	batch[0].Calibration = &domain.Calibration{Multiplier: 40}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	root := t.TempDir()
+	exporter := parquet.NewExporter(parquet.NewDirSink(root), parquet.WithPrefix("lake/standard"))
+	result, err := exporter.Export(ctx, repo, []string{"D1", "site/2"}, day.Add(time.Hour), day.Add(25*time.Hour))
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	want := []string{
+		"lake/standard/device_id=D1/date=2023-06-01/data.parquet",
+		"lake/standard/device_id=D1/date=2023-06-02/data.parquet",
+		"lake/standard/device_id=site%2F2/date=2023-06-01/data.parquet",
+		"lake/standard/device_id=site%2F2/date=2023-06-02/data.parquet",
+	}
+	if len(result.Files) != len(want) || result.Rows != 8 {
+		t.Fatalf("expected whole days for both devices, got %+v", result)
+	}
+	for i, key := range want {
+		if result.Files[i] != key {
+			t.Errorf("file %d: expected %s, got %s", i, key, result.Files[i])
+		}
+		b, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(key)))
+		if err != nil {
+			t.Fatalf("read %s: %v", key, err)
+		}
+		checkParquet(t, b)
+	}
+
+	// Re-exporting a day overwrites its partition instead of adding files
+	if _, err := exporter.Export(ctx, repo, []string{"D1"}, day, day.Add(time.Hour)); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(root, "lake/standard/device_id=D1/date=2023-06-01"))
+	if len(entries) != 1 {
+		t.Errorf("expected a single file per partition, got %d", len(entries))
+	}
+}
+
+func TestEncodeStandardReadings(t *testing.T) {
+	var buf bytes.Buffer
+	readings := []domain.StandardReading{
+		{DeviceID: "D1", Timestamp: time.Unix(0, 0), Resolution: "1h", Quality: domain.QualityValid},
+		{DeviceID: "D1", Timestamp: time.Unix(3600, 0), Resolution: "1h", Quality: domain.QualityWithdrawn,
+			Withdrawal: &domain.Withdrawal{Reason: "bad CT ratio"}},
+	}
+	if err := parquet.EncodeStandardReadings(&buf, readings); err != nil {
+		t.Fatalf("EncodeStandardReadings failed: %v", err)
+	}
+	footer := checkParquet(t, buf.Bytes())
+	for _, name := range []string{"device_id", "value_scaled", "withdrawal", "prism-core"} {
+		if !bytes.Contains(footer, []byte(name)) {
+			t.Errorf("expected the footer to describe %q", name)
+		}
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"reason":"bad CT ratio"`)) {
+		t.Error("expected the withdrawal to be stored as JSON")
+	}
+}
+
+func TestDirSinkReplacesFile(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	sink := parquet.NewDirSink(root)
+	for _, body := range []string{"first", "second"} {
+		if err := sink.Put(ctx, "a/b.parquet", bytes.NewBufferString(body)); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	f, err := os.Open(filepath.Join(root, "a", "b.parquet"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	b, _ := io.ReadAll(f)
+	if string(b) != "second" {
+		t.Errorf("expected the file to be replaced, got %q", b)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(root, "a")); len(entries) != 1 {
+		t.Errorf("expected temporary files to be cleaned up, got %d entries", len(entries))
+	}
+}
+
+// checkParquet 校验 Parquet 文件的首尾魔数与页脚长度，返回页脚 (FileMetaData)
+func checkParquet(t *testing.T, b []byte) []byte {
+	t.Helper()
+	if len(b) < 12 || string(b[:4]) != "PAR1" || string(b[len(b)-4:]) != "PAR1" {
+		t.Fatalf("missing parquet magic in %d bytes", len(b))
+	}
+	n := int(binary.LittleEndian.Uint32(b[len(b)-8 : len(b)-4]))
+	if n <= 0 || n > len(b)-12 {
+		t.Fatalf("invalid footer length %d", n)
+	}
+	return b[len(b)-8-n : len(b)-8]
+}
@@ -0,0 +1,108 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/postgres"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+// 集成测试连接真实的 PostgreSQL / TimescaleDB，验证生成的 SQL 能被数据库执行:
+//
+//	PRISM_PG_DSN=postgres://... go test -tags integration ./tests/adapters/persistence/postgres/
+//
+// PRISM_PG_DRIVER 为 database/sql 驱动名 (默认 "postgres")。本模块不依赖具体驱动，
+// 运行前需在测试二进制中注册驱动 (如在本目录添加只含 import _ "github.com/lib/pq" 的 _test.go 文件)。
+// 每次运行使用独立的租户与幂等键，测试读数在结束时删除。
+
+// openIntegrationDB 打开 PRISM_PG_DSN 指向的数据库并执行迁移，未设置时跳过测试
+func openIntegrationDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("PRISM_PG_DSN")
+	if dsn == "" {
+		t.Skip("PRISM_PG_DSN not set")
+	}
+	driverName := os.Getenv("PRISM_PG_DRIVER")
+	if driverName == "" {
+		driverName = "postgres"
+	}
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		t.Fatalf("open %s (is the driver registered?): %v", driverName, err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := postgres.Migrate(context.Background(), db); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	return db
+}
+
+func TestIntegrationSaveBatchAndQuery(t *testing.T) {
+	db := openIntegrationDB(t)
+	run := fmt.Sprintf("it-%d", time.Now().UnixNano())
+	ctx := domain.WithTenant(context.Background(), run)
+	repo := postgres.NewStandardReadingRepository(db, postgres.WithCopyFrom(postgres.InsertValues))
+	t.Cleanup(func() {
+		if _, err := repo.DeleteOlderThan(ctx, "", "", time.Now().AddDate(100, 0, 0)); err != nil {
+			t.Errorf("cleanup failed: %v", err)
+		}
+	})
+
+	tBase := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 超过一条 INSERT 的行数上限，验证分块后的语句在真实数据库中的参数个数
+	const n = 5000
+	batch := make([]domain.StandardReading, n)
+	for i := range batch {
+		batch[i] = domain.StandardReading{DeviceID: "D1", Timestamp: tBase.Add(time.Duration(i) * 15 * time.Minute), Resolution: "15m",
+			ValueScaled: int64(i), ScaleFactor: 10000, ValueDisplay: float64(i) / 10000, Quality: domain.QualityValid, Priority: 50}
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyHighPriorityWins, run+"-k1"); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	last := batch[n-1].Timestamp
+	got, err := repo.FindRange(ctx, "D1", tBase, last)
+	if err != nil {
+		t.Fatalf("FindRange failed: %v", err)
+	}
+	if len(got) != n {
+		t.Fatalf("expected %d readings, got %d", n, len(got))
+	}
+
+	// Lower priority must not overwrite; a replayed key is skipped
+	lower := batch[0]
+	lower.ValueScaled, lower.Priority = -1, 10
+	if err := repo.SaveBatch(ctx, []domain.StandardReading{lower}, ports.UpsertStrategyHighPriorityWins, run+"-k2"); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	replay := batch[1]
+	replay.ValueScaled = -1
+	if err := repo.SaveBatch(ctx, []domain.StandardReading{replay}, ports.UpsertStrategyLastWriteWins, run+"-k1"); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	for _, ts := range []time.Time{batch[0].Timestamp, batch[1].Timestamp} {
+		sr, err := repo.FindExact(ctx, "D1", ts)
+		if err != nil {
+			t.Fatalf("FindExact failed: %v", err)
+		}
+		if sr.ValueScaled < 0 {
+			t.Errorf("%s: expected the stored reading to survive, got %+v", ts.Format(time.RFC3339), sr)
+		}
+	}
+
+	// Other tenants do not see the readings
+	other, err := repo.FindRange(domain.WithTenant(context.Background(), "it-other"), "D1", tBase, last)
+	if err != nil {
+		t.Fatalf("FindRange failed: %v", err)
+	}
+	if len(other) != 0 {
+		t.Errorf("expected tenant isolation, got %d readings", len(other))
+	}
+}
@@ -0,0 +1,59 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/postgres"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+)
+
+func TestEnergyReportRepository(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	db, rec := openDB(t, "idempotency_key")
+	repo := postgres.NewEnergyReportRepository(db, "energy.reports")
+
+	if err := repo.EnsureSchema(ctx); err != nil {
+		t.Fatalf("EnsureSchema failed: %v", err)
+	}
+	if len(rec.Containing(`CREATE INDEX IF NOT EXISTS "reports_device_period" ON "energy"."reports"`)) != 1 {
+		t.Error("expected the lookup index on the configured table")
+	}
+
+	reports := []domain.EnergyReport{
+		{ID: "M1/DAY/1", DeviceID: "M1", Period: domain.ReportPeriodDay, StartTime: day, EndTime: day.AddDate(0, 0, 1), UsageScaled: 125, ScaleFactor: 10, TotalUsage: 12.5},
+		{ID: "M1/DAY/2", DeviceID: "M1", Period: domain.ReportPeriodDay, StartTime: day.AddDate(0, 0, 1), EndTime: day.AddDate(0, 0, 2), UsageScaled: 80, ScaleFactor: 10, TotalUsage: 8,
+			Carbon: &domain.CarbonEmission{CO2eKg: 4, CO2eScaled: 40}},
+	}
+	if err := repo.Save(ctx, reports...); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if rec.CopyRows() != 2 || rec.Commits() != 1 || len(rec.Containing("ON CONFLICT (id) DO UPDATE")) != 1 {
+		t.Errorf("expected two upserts in one transaction, got %d rows and %d commits", rec.CopyRows(), rec.Commits())
+	}
+	if err := repo.Save(ctx, domain.EnergyReport{DeviceID: "M1"}); err == nil {
+		t.Error("expected an error for a report without id")
+	}
+
+	rec.Rows = [][]driver.Value{
+		{"M1/DAY/1", "M1", "", "DAY", day, day.AddDate(0, 0, 1), "", "ELECTRIC", 12.5, int64(125), int64(10), nil, nil, nil},
+		{"M1/DAY/2", "M1", "", "DAY", day.AddDate(0, 0, 1), day.AddDate(0, 0, 2), "", "ELECTRIC", 8.0, int64(80), int64(10), 4.0, int64(40),
+			[]byte(`{"basis":"HEATING","normalized_usage":6.4,"normalized_scaled":64}`)},
+	}
+	got, err := repo.Find(ctx, ports.EnergyReportFilter{DeviceID: "M1", Period: domain.ReportPeriodDay, Start: day, End: day.AddDate(0, 0, 7)})
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Carbon != nil || got[1].Carbon == nil || got[1].Carbon.CO2eScaled != 40 ||
+		got[0].Period != domain.ReportPeriodDay || got[0].DeviceType != "ELECTRIC" || got[1].UsageScaled != 80 ||
+		got[0].Weather != nil || got[1].Weather == nil || got[1].Weather.NormalizedScaled != 64 {
+		t.Errorf("unexpected decoded reports: %+v", got)
+	}
+	if _, err := repo.Find(ctx, ports.EnergyReportFilter{DeviceID: "M1", Period: "YEAR", Start: day, End: day.AddDate(0, 0, 7)}); err == nil {
+		t.Error("expected an error for an unsupported period")
+	}
+}
@@ -0,0 +1,425 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/postgres"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/tests/adapters/persistence/sqltest"
+)
+
+func sampleStandards(tBase time.Time) []domain.StandardReading {
+	return []domain.StandardReading{
+		{DeviceID: "D1", Timestamp: tBase, Resolution: "15m", ValueScaled: 1000000, ScaleFactor: 10000, ValueDisplay: 100, Quality: domain.QualityValid, Priority: 50},
+		{DeviceID: "D1", Timestamp: tBase.Add(15 * time.Minute), Resolution: "15m", ValueScaled: 1010000, ScaleFactor: 10000, ValueDisplay: 101, Quality: domain.QualityValid, Priority: 50},
+	}
+}
+
+// openDB 打开记录语句的测试库，参数超过 PostgreSQL 上限的语句执行失败
+func openDB(t *testing.T, keyColumn string) (*sql.DB, *sqltest.Recorder) {
+	t.Helper()
+	db, rec := sqltest.Open(t, keyColumn)
+	rec.MaxArgs = postgres.MaxBindParams
+	return db, rec
+}
+
+func TestSaveBatchStrategies(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("HighPriorityWins", func(t *testing.T) {
+		db, rec := openDB(t, "idempotency_key")
+		repo := postgres.NewStandardReadingRepository(db)
+		if err := repo.SaveBatch(context.Background(), sampleStandards(tBase), ports.UpsertStrategyHighPriorityWins, "k1"); err != nil {
+			t.Fatalf("SaveBatch failed: %v", err)
+		}
+		if len(rec.Containing("COPY")) != 1 || rec.CopyRows() != 2 {
+			t.Errorf("expected one COPY with 2 rows, got %d rows", rec.CopyRows())
+		}
+		merges := rec.Containing("ON CONFLICT (tenant_id, device_id, metric, resolution, ts)")
+		if len(merges) != 1 {
+			t.Fatalf("expected one merge statement, got %v", merges)
+		}
+		// New data may only replace stored data of lower or equal priority
+		if !strings.Contains(merges[0].Query, "WHERE EXCLUDED.priority >= t.priority") {
+			t.Errorf("merge must guard on priority, got %s", merges[0].Query)
+		}
+		if !strings.Contains(merges[0].Query, "priority DESC, seq DESC") {
+			t.Errorf("in-batch duplicates must keep the highest priority, got %s", merges[0].Query)
+		}
+		if rec.Commits() != 1 {
+			t.Errorf("expected commit, got %d", rec.Commits())
+		}
+	})
+
+	t.Run("LastWriteWins", func(t *testing.T) {
+		db, rec := openDB(t, "idempotency_key")
+		repo := postgres.NewStandardReadingRepository(db)
+		if err := repo.SaveBatch(context.Background(), sampleStandards(tBase), ports.UpsertStrategyLastWriteWins, ""); err != nil {
+			t.Fatalf("SaveBatch failed: %v", err)
+		}
+		merges := rec.Containing("ON CONFLICT")
+		if len(merges) != 1 || strings.Contains(merges[0].Query, "WHERE EXCLUDED.priority") {
+			t.Errorf("LAST_WRITE_WINS must overwrite unconditionally, got %v", merges)
+		}
+	})
+
+	t.Run("UnknownStrategy", func(t *testing.T) {
+		db, _ := openDB(t, "idempotency_key")
+		repo := postgres.NewStandardReadingRepository(db)
+		if err := repo.SaveBatch(context.Background(), sampleStandards(tBase), "", "k"); err == nil {
+			t.Error("expected an error for an empty strategy")
+		}
+	})
+}
+
+func TestSaveBatchIdempotencyKey(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := openDB(t, "idempotency_key")
+	repo := postgres.NewStandardReadingRepository(db)
+	ctx := context.Background()
+
+	for range 2 {
+		if err := repo.SaveBatch(ctx, sampleStandards(tBase), ports.UpsertStrategyLastWriteWins, "same-key"); err != nil {
+			t.Fatalf("SaveBatch failed: %v", err)
+		}
+	}
+	if n := len(rec.Containing("COPY")); n != 1 {
+		t.Errorf("replayed batch must not be copied again, got %d COPY statements", n)
+	}
+}
+
+func TestInsertValuesChunksWithinBindLimit(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := openDB(t, "")
+	repo := postgres.NewStandardReadingRepository(db, postgres.WithCopyFrom(postgres.InsertValues))
+
+	const n = 5000 // 超过旧的固定分块 (4000 行) 在 17 列时的上限
+	batch := make([]domain.StandardReading, n)
+	for i := range batch {
+		batch[i] = domain.StandardReading{DeviceID: "D1", Timestamp: tBase.Add(time.Duration(i) * 15 * time.Minute), Resolution: "15m", Quality: domain.QualityValid}
+	}
+	if err := repo.SaveBatch(context.Background(), batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	inserts := rec.Containing("VALUES ($1")
+	if len(inserts) < 2 {
+		t.Fatalf("expected the stage insert to be chunked, got %d statements", len(inserts))
+	}
+	rows := 0
+	for _, s := range inserts {
+		list := s.Query[strings.Index(s.Query, "(")+1 : strings.Index(s.Query, ")")]
+		columns := strings.Split(list, ", ")
+		if limit := postgres.InsertChunkRows(columns) * len(columns); limit > postgres.MaxBindParams {
+			t.Errorf("chunk of %d columns allows %d parameters, over the limit", len(columns), limit)
+		}
+		if len(s.Args) > postgres.MaxBindParams || len(s.Args)%len(columns) != 0 {
+			t.Errorf("statement binds %d parameters for %d columns", len(s.Args), len(columns))
+		}
+		rows += len(s.Args) / len(columns)
+	}
+	if rows != n {
+		t.Errorf("expected %d staged rows, got %d", n, rows)
+	}
+}
+
+func TestStatementsStayWithinBindLimit(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := openDB(t, "idempotency_key")
+	repo := postgres.NewStandardReadingRepository(db, postgres.WithCopyFrom(postgres.InsertValues),
+		postgres.WithVersioning(""), postgres.WithOutbox("", ""))
+
+	// The recorder rejects statements over the limit, as PostgreSQL does
+	probe, _ := openDB(t, "")
+	over := make([]any, postgres.MaxBindParams+1)
+	if _, err := probe.ExecContext(ctx, "SELECT 1", over...); err == nil {
+		t.Fatal("expected a statement over the bind limit to fail")
+	}
+
+	batch := make([]domain.StandardReading, 2*postgres.InsertChunkRows(make([]string, 17))+1)
+	ids := make([]string, 0, len(batch))
+	for i := range batch {
+		id := fmt.Sprintf("L%05d", i)
+		batch[i] = domain.StandardReading{DeviceID: id, Timestamp: tBase, Resolution: "15m", Quality: domain.QualityValid}
+		ids = append(ids, id)
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyHighPriorityWins, "k1"); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	if _, err := repo.FindRangeMulti(ctx, ids, tBase, tBase.Add(time.Hour)); err != nil {
+		t.Fatalf("FindRangeMulti failed: %v", err)
+	}
+
+	for _, s := range rec.Containing("") {
+		if len(s.Args) > postgres.MaxBindParams {
+			t.Errorf("statement binds %d parameters, over the limit: %.80s", len(s.Args), s.Query)
+		}
+	}
+}
+
+func TestFindQueries(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	db, rec := openDB(t, "idempotency_key")
+	repo := postgres.NewStandardReadingRepository(db, postgres.WithTable("energy.readings"))
+	if _, err := repo.FindLastBefore(ctx, "D1", domain.MetricEnergy, "15m", tBase); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	rec.Rows = [][]driver.Value{{
+		"", "D1", "ENERGY", "15m", tBase,
+		int64(1000000), int64(10000), 100.0,
+		"VALID", "", 1.0, "STANDARD", []byte(`{"ct_ratio":40}`),
+		tBase, int64(1000), nil, []byte(`{"trace_id":"t-1","operator":"alice","batch_id":"b-7"}`),
+	}}
+	got, err := repo.FindRange(ctx, "D1", tBase, tBase.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("FindRange failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 reading, got %d", len(got))
+	}
+	sr := got[0]
+	if sr.Metric != domain.MetricEnergy || sr.ValueScaled != 1000000 || sr.Priority != 1000 || sr.Calibration == nil || sr.Calibration.CTRatio != 40 {
+		t.Errorf("unexpected decoded reading: %+v", sr)
+	}
+	if p := sr.Provenance; p == nil || p.TraceID != "t-1" || p.Operator != "alice" || p.BatchID != "b-7" {
+		t.Errorf("expected provenance decoded, got %+v", p)
+	}
+	if q := rec.Containing(`FROM "energy"."readings"`); len(q) != 2 {
+		t.Errorf("expected queries against the configured table, got %v", q)
+	}
+}
+
+func TestTenantScopesWritesAndQueries(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := openDB(t, "")
+	repo := postgres.NewStandardReadingRepository(db, postgres.WithCopyFrom(postgres.InsertValues))
+	acme := domain.WithTenant(context.Background(), "acme")
+
+	if err := repo.SaveBatch(acme, sampleStandards(tBase), ports.UpsertStrategyHighPriorityWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	staged := rec.Containing(`INSERT INTO "prism_standard_stage" (tenant_id, device_id`)
+	if len(staged) != 1 || staged[0].Args[0] != "acme" {
+		t.Fatalf("expected staged rows stamped with the ctx tenant, got %v", staged)
+	}
+
+	for name, call := range map[string]func() error{
+		"FindExact":      func() error { _, err := repo.FindExact(acme, "D1", tBase); return err },
+		"FindRange":      func() error { _, err := repo.FindRange(acme, "D1", tBase, tBase); return err },
+		"FindRangeMulti": func() error { _, err := repo.FindRangeMulti(acme, []string{"D1"}, tBase, tBase); return err },
+		"FindLatest":     func() error { _, err := repo.FindLatest(acme, "D1"); return err },
+		"FindVersions":   func() error { _, err := repo.FindVersions(acme, "D1", tBase); return err },
+		"Withdraw": func() error {
+			_, err := repo.Withdraw(acme, ports.WithdrawRequest{DeviceID: "D1", Start: tBase, End: tBase, Reason: "x", Operator: "alice"})
+			return err
+		},
+		"SumByPeriod": func() error {
+			_, err := repo.SumByPeriod(acme, ports.AggregateQuery{DeviceID: "D1", Resolution: "15m", Start: tBase, End: tBase.Add(time.Hour), Period: domain.ReportPeriodDay})
+			return err
+		},
+	} {
+		before := len(rec.Containing("tenant_id = $"))
+		if err := call(); err != nil && !errors.Is(err, ports.ErrNotFound) {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		scoped := rec.Containing("tenant_id = $")
+		if len(scoped) != before+1 || !slices.Contains(scoped[len(scoped)-1].Args, any("acme")) {
+			t.Errorf("%s: expected a tenant-filtered query bound to acme, got %v", name, scoped[before:])
+		}
+	}
+
+	raw := postgres.NewRawReadingRepository(db, "")
+	if err := raw.Save(acme, []domain.Reading{{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: 1}}); err != nil {
+		t.Fatalf("raw Save failed: %v", err)
+	}
+	if q := rec.Containing("ON CONFLICT (tenant_id, device_id, metric, ts)"); len(q) != 1 {
+		t.Errorf("expected the raw upsert keyed by tenant, got %v", q)
+	}
+	if _, err := raw.FindRange(acme, "D1", tBase, tBase); err != nil {
+		t.Fatalf("raw FindRange failed: %v", err)
+	}
+	if q := rec.Containing(`FROM "raw_readings" WHERE tenant_id = $1`); len(q) != 1 || q[0].Args[0] != "acme" {
+		t.Errorf("expected raw readings filtered by tenant, got %v", q)
+	}
+}
+
+func TestFindRangeMultiBatchesDevices(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := openDB(t, "idempotency_key")
+	repo := postgres.NewStandardReadingRepository(db)
+
+	ids := make([]string, 0, 1500)
+	for i := range 1500 {
+		ids = append(ids, fmt.Sprintf("M%04d", i))
+	}
+	if _, err := repo.FindRangeMulti(context.Background(), append(ids, "M0000"), tBase, tBase.Add(24*time.Hour)); err != nil {
+		t.Fatalf("FindRangeMulti failed: %v", err)
+	}
+	queries := rec.Containing("device_id IN (")
+	if len(queries) != 2 {
+		t.Fatalf("expected 1500 devices to be fetched in 2 queries, got %d", len(queries))
+	}
+	if n := len(queries[0].Args) + len(queries[1].Args); n != 1500+6 {
+		t.Errorf("expected each device bound once plus the tenant and time range per query, got %d args", n)
+	}
+}
+
+func TestAggregationPushedToDatabase(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	db, rec := openDB(t, "idempotency_key")
+	repo := postgres.NewStandardReadingRepository(db)
+
+	rec.Rows = [][]driver.Value{{tBase, 42.0, int64(96)}}
+	got, err := repo.SumByPeriod(context.Background(), ports.AggregateQuery{
+		DeviceID: "D1", Resolution: "15m", Start: tBase, End: tBase.Add(24 * time.Hour), Period: domain.ReportPeriodDay,
+	})
+	if err != nil {
+		t.Fatalf("SumByPeriod failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Value != 42 || got[0].Count != 96 || !got[0].Start.Equal(tBase) {
+		t.Errorf("unexpected buckets: %+v", got)
+	}
+	queries := rec.Containing("date_trunc")
+	if len(queries) != 1 || !strings.Contains(queries[0].Query, "SUM(value_display)") || queries[0].Args[0] != "day" {
+		t.Errorf("expected a date_trunc/SUM query, got %v", queries)
+	}
+}
+
+func TestWithdrawUpdatesInPlace(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := openDB(t, "idempotency_key")
+	repo := postgres.NewStandardReadingRepository(db)
+
+	rec.Rows = [][]driver.Value{{
+		"", "D1", "", "1h", tBase,
+		int64(0), int64(10000), 0.0,
+		"WITHDRAWN", "", 1.0, "STANDARD", nil,
+		tBase, int64(100), []byte(`{"reason":"bad CT","operator":"alice","at":"2023-01-03T10:00:00Z","previous_quality":"VALID"}`), nil,
+	}}
+	got, err := repo.Withdraw(context.Background(), ports.WithdrawRequest{
+		DeviceID: "D1", Start: tBase, End: tBase.Add(time.Hour), Reason: "bad CT", Operator: "alice",
+	})
+	if err != nil {
+		t.Fatalf("Withdraw failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Withdrawal == nil || got[0].Withdrawal.PreviousQuality != domain.QualityValid {
+		t.Errorf("unexpected withdrawn readings: %+v", got)
+	}
+	if q := rec.Containing("RETURNING"); len(q) != 1 || !strings.Contains(q[0].Query, "withdrawal IS NULL") || len(rec.Containing("DELETE")) != 0 {
+		t.Errorf("expected a single UPDATE ... RETURNING without deletes, got %v", q)
+	}
+}
+
+func TestSaveBatchWritesOutboxInSameTransaction(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := openDB(t, "idempotency_key")
+	repo := postgres.NewStandardReadingRepository(db, postgres.WithCopyFrom(postgres.InsertValues), postgres.WithOutbox("", "energy.standards"))
+
+	// The merge RETURNING clause reports one applied row
+	rec.Rows = [][]driver.Value{{
+		"", "D1", "", "15m", tBase,
+		int64(1), int64(10000), 0.0001,
+		"VALID", "", 1.0, "STANDARD", nil,
+		tBase, int64(100), nil, nil,
+	}}
+	if err := repo.SaveBatch(context.Background(), sampleStandards(tBase), ports.UpsertStrategyHighPriorityWins, "k1"); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	if q := rec.Containing("RETURNING"); len(q) != 1 {
+		t.Errorf("expected the merge to return applied rows, got %v", q)
+	}
+	outbox := rec.Containing(`INSERT INTO "standard_reading_outbox"`)
+	if len(outbox) != 1 || outbox[0].Args[0] != "energy.standards" || outbox[0].Args[1] != "D1" {
+		t.Fatalf("expected one outbox message for D1, got %v", outbox)
+	}
+	if rec.Commits() != 1 {
+		t.Errorf("expected data and outbox to commit together, got %d commits", rec.Commits())
+	}
+}
+
+func TestVersioningArchivesBeforeMerge(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := openDB(t, "idempotency_key")
+	repo := postgres.NewStandardReadingRepository(db, postgres.WithCopyFrom(postgres.InsertValues), postgres.WithVersioning(""))
+
+	if err := repo.SaveBatch(ctx, sampleStandards(tBase), ports.UpsertStrategyHighPriorityWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	archive := rec.Containing(`INSERT INTO "standard_reading_versions"`)
+	if len(archive) != 1 || !strings.Contains(archive[0].Query, "SELECT DISTINCT ON") ||
+		!strings.Contains(archive[0].Query, "s.priority >= t.priority") {
+		t.Fatalf("expected one priority-guarded archive of the deduplicated batch, got %v", archive)
+	}
+	if rec.Commits() != 1 {
+		t.Errorf("expected archive and merge to commit together, got %d commits", rec.Commits())
+	}
+
+	// Save archives from its own parameters in the same transaction
+	if err := repo.Save(ctx, sampleStandards(tBase)[0], ports.UpsertStrategyLastWriteWins); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	archive = rec.Containing(`INSERT INTO "standard_reading_versions"`)
+	if len(archive) != 2 || len(archive[1].Args) != 9 || strings.Contains(archive[1].Query, "s.priority >= t.priority") {
+		t.Errorf("expected an unguarded single-row archive, got %v", archive)
+	}
+
+	rec.Rows = [][]driver.Value{{
+		"", "D1", "", "15m", tBase,
+		int64(1), int64(10000), 0.0001,
+		"VALID", "", 1.0, "STANDARD", nil,
+		tBase, int64(100), nil, nil, int64(2),
+	}}
+	versions, err := repo.FindVersions(ctx, "D1", tBase)
+	if err != nil {
+		t.Fatalf("FindVersions failed: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Version != 2 {
+		t.Errorf("expected version 2 to be scanned, got %+v", versions)
+	}
+	if q := rec.Containing("UNION ALL"); len(q) != 1 {
+		t.Errorf("expected history and current rows in one query, got %v", q)
+	}
+}
+
+func TestHealthCheckReadsOwnTables(t *testing.T) {
+	db, rec := openDB(t, "idempotency_key")
+	repo := postgres.NewStandardReadingRepository(db, postgres.WithOutbox("", ""))
+
+	if err := repo.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck failed: %v", err)
+	}
+	for _, table := range []string{`"standard_readings"`, `"standard_reading_batches"`, `"standard_reading_outbox"`} {
+		if q := rec.Containing("SELECT 1 FROM " + table); len(q) != 1 {
+			t.Errorf("expected health check to read %s, got %v", table, q)
+		}
+	}
+}
+
+func TestMigrateCreatesDefaultTables(t *testing.T) {
+	db, rec := openDB(t, "idempotency_key")
+	if err := postgres.Migrate(context.Background(), db); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	for _, table := range []string{postgres.DefaultTable, postgres.DefaultBatchTable, postgres.DefaultOutboxTable, postgres.DefaultReportTable} {
+		if len(rec.Containing("CREATE TABLE IF NOT EXISTS "+table+" (")) != 1 {
+			t.Errorf("expected migration to create %s", table)
+		}
+	}
+	if len(rec.Containing("INSERT INTO schema_migrations (version, dirty) VALUES (1, false)")) != 1 {
+		t.Error("expected version 1 to be recorded")
+	}
+}
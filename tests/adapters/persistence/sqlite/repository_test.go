@@ -0,0 +1,297 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/sqlite"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/tests/adapters/persistence/sqltest"
+)
+
+func TestStandardSaveBatch(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	readings := []domain.StandardReading{
+		{DeviceID: "D1", Timestamp: tBase, Resolution: "15m", ValueScaled: 1000000, ScaleFactor: 10000, Priority: 50},
+		{DeviceID: "D1", Timestamp: tBase.Add(15 * time.Minute), Resolution: "15m", ValueScaled: 1010000, ScaleFactor: 10000, Priority: 50},
+	}
+	ctx := context.Background()
+
+	db, rec := sqltest.Open(t, "idempotency_key")
+	repo := sqlite.NewStandardReadingRepository(db)
+	for range 2 {
+		if err := repo.SaveBatch(ctx, readings, ports.UpsertStrategyHighPriorityWins, "k1"); err != nil {
+			t.Fatalf("SaveBatch failed: %v", err)
+		}
+	}
+
+	// The replayed key is skipped, so only the first call writes rows
+	if n := rec.CopyRows(); n != 2 {
+		t.Errorf("expected 2 rows written once, got %d", n)
+	}
+	upserts := rec.Containing("ON CONFLICT (tenant_id, device_id, metric, resolution, ts)")
+	if len(upserts) != 1 || !strings.Contains(upserts[0].Query, "WHERE excluded.priority >= standard_readings.priority") {
+		t.Errorf("expected one priority-guarded upsert, got %v", upserts)
+	}
+
+	if err := repo.Save(ctx, readings[0], ports.UpsertStrategyLastWriteWins); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if lww := rec.Containing("ON CONFLICT"); strings.Contains(lww[len(lww)-1].Query, "excluded.priority >=") {
+		t.Errorf("LAST_WRITE_WINS must overwrite unconditionally, got %s", lww[len(lww)-1].Query)
+	}
+	if err := repo.Save(ctx, readings[0], ""); err == nil {
+		t.Error("expected an error for an empty strategy")
+	}
+
+	if _, err := repo.FindLastBefore(ctx, "D1", "", "15m", tBase); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestQuarantineSaveAssignsID(t *testing.T) {
+	db, rec := sqltest.Open(t, "")
+	repo := sqlite.NewQuarantineRepository(db)
+
+	q := domain.QuarantineReading{
+		Reading: domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "D1", Type: domain.DeviceTypeElec}, Value: -1},
+		Reason:  "negative",
+		Code:    domain.QuarantineCodeRuleRejected,
+	}
+	if err := repo.Save(context.Background(), q); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	saved := rec.Containing("INSERT INTO quarantine_readings")
+	if len(saved) != 1 {
+		t.Fatalf("expected one insert, got %v", saved)
+	}
+	if id, _ := saved[0].Args[0].(string); id == "" {
+		t.Error("expected a generated id")
+	}
+	if status := saved[0].Args[5]; status != string(domain.QuarantineStatusPending) {
+		t.Errorf("expected PENDING status by default, got %v", status)
+	}
+}
+
+func TestQuarantineUpdateStatusComparesAndSets(t *testing.T) {
+	db, rec := sqltest.Open(t, "")
+	repo := sqlite.NewQuarantineRepository(db)
+
+	q := domain.QuarantineReading{ID: "q1", Reading: domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "D1"}}, Status: domain.QuarantineStatusResolved}
+	if err := repo.UpdateStatus(domain.WithTenant(context.Background(), "acme"), q, domain.QuarantineStatusPending); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	updates := rec.Containing("UPDATE quarantine_readings")
+	if len(updates) != 1 || !strings.Contains(updates[0].Query, "AND status = ?") {
+		t.Fatalf("expected one conditional update, got %v", updates)
+	}
+	args := updates[0].Args
+	if args[0] != string(domain.QuarantineStatusResolved) || args[2] != "q1" || args[3] != "acme" || args[4] != string(domain.QuarantineStatusPending) {
+		t.Errorf("expected the new status, id, tenant and expected status to be bound, got %v", args)
+	}
+}
+
+func TestTenantScopesWritesAndQueries(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := sqltest.Open(t, "")
+	repo := sqlite.NewStandardReadingRepository(db)
+	acme := domain.WithTenant(context.Background(), "acme")
+
+	if err := repo.Save(acme, domain.StandardReading{DeviceID: "D1", Timestamp: tBase, Resolution: "15m"}, ports.UpsertStrategyLastWriteWins); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if saved := rec.Containing("INSERT INTO standard_readings"); len(saved) != 1 || saved[0].Args[0] != "acme" {
+		t.Fatalf("expected the reading stamped with the ctx tenant, got %v", saved)
+	}
+
+	for name, call := range map[string]func() error{
+		"FindExact":      func() error { _, err := repo.FindExact(acme, "D1", tBase); return err },
+		"FindRange":      func() error { _, err := repo.FindRange(acme, "D1", tBase, tBase); return err },
+		"FindRangeMulti": func() error { _, err := repo.FindRangeMulti(acme, []string{"D1"}, tBase, tBase); return err },
+		"FindLatest":     func() error { _, err := repo.FindLatest(acme, "D1"); return err },
+		"FindVersions":   func() error { _, err := repo.FindVersions(acme, "D1", tBase); return err },
+		"Withdraw": func() error {
+			_, err := repo.Withdraw(acme, ports.WithdrawRequest{DeviceID: "D1", Start: tBase, End: tBase, Reason: "x", Operator: "alice"})
+			return err
+		},
+		"SumByPeriod": func() error {
+			_, err := repo.SumByPeriod(acme, ports.AggregateQuery{DeviceID: "D1", Resolution: "15m", Start: tBase, End: tBase.Add(time.Hour), Period: domain.ReportPeriodDay})
+			return err
+		},
+	} {
+		before := len(rec.Containing("tenant_id = ?"))
+		if err := call(); err != nil && !errors.Is(err, ports.ErrNotFound) {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		scoped := rec.Containing("tenant_id = ?")
+		if len(scoped) != before+1 || !slices.Contains(scoped[len(scoped)-1].Args, any("acme")) {
+			t.Errorf("%s: expected a tenant-filtered query bound to acme, got %v", name, scoped[before:])
+		}
+	}
+
+	raw := sqlite.NewRawReadingRepository(db)
+	if err := raw.Save(acme, []domain.Reading{{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: 1}}); err != nil {
+		t.Fatalf("raw Save failed: %v", err)
+	}
+	if q := rec.Containing("ON CONFLICT (tenant_id, device_id, metric, ts)"); len(q) != 1 {
+		t.Errorf("expected the raw upsert keyed by tenant, got %v", q)
+	}
+	if _, err := raw.FindRange(acme, "D1", tBase, tBase); err != nil {
+		t.Fatalf("raw FindRange failed: %v", err)
+	}
+	if q := rec.Containing("FROM raw_readings WHERE tenant_id = ?"); len(q) != 1 || q[0].Args[0] != "acme" {
+		t.Errorf("expected raw readings filtered by tenant, got %v", q)
+	}
+
+	quarantine := sqlite.NewQuarantineRepository(db)
+	if err := quarantine.Save(acme, domain.QuarantineReading{Reading: domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "D1"}}}); err != nil {
+		t.Fatalf("quarantine Save failed: %v", err)
+	}
+	saved := rec.Containing("INSERT INTO quarantine_readings")
+	if len(saved) != 1 || saved[0].Args[1] != "acme" || !strings.Contains(saved[0].Query, "WHERE quarantine_readings.tenant_id = excluded.tenant_id") {
+		t.Errorf("expected the quarantine record stamped with the tenant and not overwritable across tenants, got %v", saved)
+	}
+	if _, err := quarantine.FindPending(acme, 0); err != nil {
+		t.Fatalf("FindPending failed: %v", err)
+	}
+	if _, err := quarantine.Count(acme, ports.QuarantineFilter{}, ports.QuarantineGroupStatus); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if q := rec.Containing("FROM quarantine_readings WHERE tenant_id = ?"); len(q) != 2 || q[0].Args[0] != "acme" || q[1].Args[0] != "acme" {
+		t.Errorf("expected quarantine queries filtered by tenant, got %v", q)
+	}
+}
+
+func TestCleaningRuleRoundTrip(t *testing.T) {
+	db, rec := sqltest.Open(t, "")
+	repo := sqlite.NewCleaningRuleRepository(db)
+	ctx := context.Background()
+
+	rule := domain.CleaningRule{
+		ID: "r1", DeviceType: domain.DeviceTypeElec, Type: domain.RuleTypeRange, Action: domain.ActionReject,
+		Enabled: true, Parameters: map[string]any{"min": 0.0, "max": 100.0}, Priority: 10,
+	}
+	if err := repo.Save(ctx, rule); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := repo.Save(ctx, domain.CleaningRule{}); err == nil {
+		t.Error("expected an error for a rule without id")
+	}
+
+	body, _ := json.Marshal(rule)
+	rec.Rows = [][]driver.Value{{string(body)}}
+	got, err := repo.ListEnabledByDeviceType(ctx, domain.DeviceTypeElec)
+	if err != nil {
+		t.Fatalf("ListEnabledByDeviceType failed: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "r1" || got[0].Parameters["max"] != 100.0 {
+		t.Errorf("unexpected rules: %+v", got)
+	}
+	if q := rec.Containing("enabled = 1"); len(q) != 1 {
+		t.Errorf("expected enabled filter, got %v", q)
+	}
+}
+
+func TestQuarantineFindAndCount(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := sqltest.Open(t, "")
+	repo := sqlite.NewQuarantineRepository(db)
+
+	_, err := repo.Find(ctx, ports.QuarantineFilter{DeviceID: "D1", RuleID: "range", ReasonContains: "max", Start: tBase, Limit: 20, Offset: 40})
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	q := rec.Containing("SELECT body FROM quarantine_readings")
+	if len(q) != 1 || !strings.Contains(q[0].Query, "json_extract(body, '$.rule_id') = ?") || !strings.Contains(q[0].Query, "LIMIT ? OFFSET ?") {
+		t.Fatalf("expected a filtered, paged query, got %v", q)
+	}
+	if want := []any{"", "D1", "range", "max", tBase.UnixNano(), int64(20), int64(40)}; fmt.Sprint(q[0].Args) != fmt.Sprint(want) {
+		t.Errorf("expected args %v, got %v", want, q[0].Args)
+	}
+
+	rec.Rows = [][]driver.Value{{"D1", int64(3)}, {"D2", int64(7)}}
+	counts, err := repo.Count(ctx, ports.QuarantineFilter{Status: domain.QuarantineStatusPending}, ports.QuarantineGroupDevice)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if len(counts) != 2 || counts[0] != (ports.QuarantineCount{Key: "D2", Count: 7}) {
+		t.Errorf("expected counts sorted by size, got %v", counts)
+	}
+	if c := rec.Containing("GROUP BY 1"); len(c) != 1 || !strings.Contains(c[0].Query, "COALESCE(device_id, '')") {
+		t.Errorf("expected counting in the database, got %v", c)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	db, rec := sqltest.Open(t, "idempotency_key")
+	if err := sqlite.Migrate(ctx, db); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if len(rec.Containing("CREATE TABLE IF NOT EXISTS quarantine_readings")) != 1 {
+		t.Error("expected the initial migration to create the schema")
+	}
+	versions := rec.Containing("INSERT INTO schema_migrations")
+	if len(versions) < 2 || !strings.Contains(versions[0].Query, "(1, true)") || !strings.Contains(versions[1].Query, "(1, false)") {
+		t.Errorf("expected version 1 to be marked dirty then clean, got %v", versions)
+	}
+
+	// Already at version 1: only later migrations are applied
+	db, rec = sqltest.Open(t, "idempotency_key")
+	rec.Rows = [][]driver.Value{{int64(1), false}}
+	if err := sqlite.Migrate(ctx, db); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if len(rec.Containing("CREATE TABLE IF NOT EXISTS standard_readings")) != 0 {
+		t.Error("expected applied migrations to be skipped")
+	}
+	if len(rec.Containing("CREATE TABLE IF NOT EXISTS standard_reading_audit")) != 1 {
+		t.Error("expected the audit table migration to be applied")
+	}
+
+	// A dirty version needs manual repair
+	db, rec = sqltest.Open(t, "idempotency_key")
+	rec.Rows = [][]driver.Value{{int64(1), true}}
+	if err := sqlite.Migrate(ctx, db); err == nil || !strings.Contains(err.Error(), "dirty") {
+		t.Errorf("expected dirty version to abort, got %v", err)
+	}
+}
+
+func TestRawReadingRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, rec := sqltest.Open(t, "")
+	repo := sqlite.NewRawReadingRepository(db)
+
+	readings := []domain.Reading{
+		{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: math.NaN()}, // NaN must not break the JSON body
+		{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase.Add(15 * time.Minute), Value: 101.5},
+	}
+	if err := repo.Save(ctx, readings); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if rec.CopyRows() != 2 || rec.Commits() != 1 {
+		t.Errorf("expected 2 rows upserted in one transaction, got %d rows / %d commits", rec.CopyRows(), rec.Commits())
+	}
+
+	body, _ := json.Marshal(domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase})
+	rec.Rows = [][]driver.Value{{nil, body}, {101.5, body}}
+	got, err := repo.FindRange(ctx, "D1", tBase, tBase.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("FindRange failed: %v", err)
+	}
+	if len(got) != 2 || !math.IsNaN(got[0].Value) || got[1].Value != 101.5 || got[0].DeviceInfo.ID != "D1" {
+		t.Errorf("expected NULL values to decode as NaN, got %+v", got)
+	}
+}
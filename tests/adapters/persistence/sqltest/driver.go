@@ -0,0 +1,212 @@
+// Package sqltest 提供记录 SQL 的 database/sql 驱动，供持久化适配器的测试使用 (不连接真实数据库)
+package sqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Statement 一条被执行的语句及其参数
+type Statement struct {
+	Query string
+	Args  []any
+}
+
+// Recorder 记录全部执行的语句
+// 包含 KeyColumn 的 INSERT 按首个参数去重: 重复的 key 返回影响行数 0 (模拟 ON CONFLICT DO NOTHING / INSERT OR IGNORE)；
+// 查询返回 Rows 中预置的行。预编译语句的每次带参数 Exec 记为一行 COPY 数据。
+// MaxArgs > 0 时绑定参数超过 MaxArgs 个的语句执行失败 (模拟数据库的参数上限，如 postgres.MaxBindParams)。
+type Recorder struct {
+	KeyColumn string
+	Rows      [][]driver.Value
+	MaxArgs   int
+
+	mu         sync.Mutex
+	statements []Statement
+	keys       map[any]bool
+	copyRows   int
+	commits    int
+}
+
+var (
+	registry sync.Map // dsn -> *Recorder
+	seq      atomic.Int64
+)
+
+func init() {
+	sql.Register("sqltest", recordingDriver{})
+}
+
+// Open 打开一个记录语句的 *sql.DB，测试结束时自动关闭
+func Open(t *testing.T, keyColumn string) (*sql.DB, *Recorder) {
+	t.Helper()
+	rec := &Recorder{KeyColumn: keyColumn, keys: make(map[any]bool)}
+	dsn := fmt.Sprintf("rec-%d", seq.Add(1))
+	registry.Store(dsn, rec)
+	db, err := sql.Open("sqltest", dsn)
+	if err != nil {
+		t.Fatalf("open sqltest: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		registry.Delete(dsn)
+	})
+	return db, rec
+}
+
+// Containing 返回查询文本包含 sub 的语句
+func (r *Recorder) Containing(sub string) []Statement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Statement
+	for _, s := range r.statements {
+		if strings.Contains(s.Query, sub) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// CopyRows 返回通过预编译语句写入的行数
+func (r *Recorder) CopyRows() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.copyRows
+}
+
+// Commits 返回提交的事务数
+func (r *Recorder) Commits() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.commits
+}
+
+func (r *Recorder) record(query string, args []driver.NamedValue) {
+	values := make([]any, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	r.statements = append(r.statements, Statement{Query: query, Args: values})
+}
+
+// checkArgs 按 MaxArgs 检查语句的参数个数
+func (r *Recorder) checkArgs(args []driver.NamedValue) error {
+	if r.MaxArgs > 0 && len(args) > r.MaxArgs {
+		return fmt.Errorf("sqltest: statement binds %d parameters, over the limit of %d", len(args), r.MaxArgs)
+	}
+	return nil
+}
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(dsn string) (driver.Conn, error) {
+	rec, ok := registry.Load(dsn)
+	if !ok {
+		return nil, fmt.Errorf("sqltest: unknown dsn %q", dsn)
+	}
+	return &conn{rec: rec.(*Recorder)}, nil
+}
+
+type conn struct{ rec *Recorder }
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	c.rec.mu.Lock()
+	defer c.rec.mu.Unlock()
+	c.rec.record(query, nil)
+	return &stmt{rec: c.rec}, nil
+}
+
+func (c *conn) Close() error              { return nil }
+func (c *conn) Begin() (driver.Tx, error) { return &tx{rec: c.rec}, nil }
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.rec.mu.Lock()
+	defer c.rec.mu.Unlock()
+	c.rec.record(query, args)
+	if err := c.rec.checkArgs(args); err != nil {
+		return nil, err
+	}
+	if c.rec.KeyColumn != "" && strings.HasPrefix(query, "INSERT") && strings.Contains(query, c.rec.KeyColumn) && len(args) > 0 {
+		if c.rec.keys[args[0].Value] {
+			return driver.RowsAffected(0), nil
+		}
+		c.rec.keys[args[0].Value] = true
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.rec.mu.Lock()
+	defer c.rec.mu.Unlock()
+	c.rec.record(query, args)
+	if err := c.rec.checkArgs(args); err != nil {
+		return nil, err
+	}
+	return &rows{rows: c.rec.Rows}, nil
+}
+
+type tx struct{ rec *Recorder }
+
+func (t *tx) Commit() error {
+	t.rec.mu.Lock()
+	defer t.rec.mu.Unlock()
+	t.rec.commits++
+	return nil
+}
+
+func (t *tx) Rollback() error { return nil }
+
+// stmt 预编译语句: 带参数的 Exec 记为一行数据 (lib/pq 的 COPY 约定)
+type stmt struct{ rec *Recorder }
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.rec.mu.Lock()
+	defer s.rec.mu.Unlock()
+	if len(args) > 0 {
+		s.rec.copyRows++
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *stmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("sqltest: query on prepared statement not supported")
+}
+
+type rows struct {
+	rows [][]driver.Value
+	i    int
+}
+
+func (r *rows) Columns() []string {
+	n := 0
+	if len(r.rows) > 0 {
+		n = len(r.rows[0])
+	}
+	cols := make([]string, n)
+	for i := range cols {
+		cols[i] = fmt.Sprintf("c%d", i)
+	}
+	return cols
+}
+
+func (r *rows) Close() error { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.i >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.i])
+	r.i++
+	return nil
+}
@@ -0,0 +1,179 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/config"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/tests/adapters/persistence/sqltest"
+)
+
+const pipelineConfig = `{
+  "standardizer": {
+    "interval": "15m",
+    "tolerance": "1m",
+    "resolutions": ["1h"],
+    "precision": 1000,
+    "rounding_mode": "HALF_EVEN",
+    "quarantine": {"mode": "SYNC"}
+  },
+  "storage": {"type": "memory", "raw": true},
+  "ingest": {"formats": ["csv"]},
+  "rules": [
+    {"id": "elec-range", "device_type": "ELEC", "type": "RANGE", "action": "REJECT", "enabled": true, "parameters": {"min": 0, "max": 1000}}
+  ]
+}`
+
+func TestBuildPipelineFromConfig(t *testing.T) {
+	ctx := context.Background()
+	cfg, err := config.Parse([]byte(pipelineConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipeline, err := config.Build(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pipeline.Close()
+
+	if _, ok := pipeline.Ingestors["json"]; ok || pipeline.Ingestors["csv"] == nil {
+		t.Fatalf("expected only the csv ingestor, got %v", pipeline.Ingestors)
+	}
+	csv := "device_id,type,timestamp,value\n" +
+		"M1,ELEC,2024-01-01T00:00:00Z,10\n" +
+		"M1,ELEC,2024-01-01T00:15:00Z,-5\n"
+	if _, err := pipeline.Ingestors["csv"].IngestStream(ctx, strings.NewReader(csv)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := pipeline.Repository.FindExact(ctx, "M1", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ScaleFactor != 1000 || got.ValueScaled != 10000 {
+		t.Errorf("expected precision 1000 applied, got %+v", got)
+	}
+	quarantined, err := pipeline.QuarantineRepository.Find(ctx, ports.QuarantineFilter{RuleID: "elec-range"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quarantined) != 1 || quarantined[0].Reading.Value != -5 {
+		t.Errorf("expected the negative reading quarantined by the inline rule, got %+v", quarantined)
+	}
+	if pipeline.RawRepository == nil || pipeline.AuditRepository != nil {
+		t.Error("expected raw archiving on and auditing off")
+	}
+}
+
+func TestBuildPostgresRequiresInjectedRepositories(t *testing.T) {
+	ctx := context.Background()
+	cfg, err := config.Parse([]byte(`{
+  "storage": {"type": "postgres", "dsn": "unused"},
+  "rules": [
+    {"id": "elec-range", "device_type": "ELEC", "type": "RANGE", "action": "REJECT", "enabled": true, "parameters": {"min": 0, "max": 1000}}
+  ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := sqltest.Open(t, "device_id")
+
+	// 未传入时拒绝构建，而不是静默使用进程内仓储
+	quarantine, rules := memory.NewQuarantineRepository(), memory.NewCleaningRuleRepository(nil)
+	for name, opts := range map[string][]config.BuildOption{
+		"none":            {config.WithDB(db)},
+		"quarantine only": {config.WithDB(db), config.WithQuarantineRepository(quarantine)},
+		"rules only":      {config.WithDB(db), config.WithRuleRepository(rules)},
+	} {
+		if _, err := config.Build(ctx, cfg, opts...); err == nil || !strings.Contains(err.Error(), "config.WithQuarantineRepository") {
+			t.Errorf("%s: expected missing repository error, got %v", name, err)
+		}
+	}
+
+	pipeline, err := config.Build(ctx, cfg, config.WithDB(db), config.WithQuarantineRepository(quarantine), config.WithRuleRepository(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pipeline.Close()
+	if pipeline.QuarantineRepository != quarantine || pipeline.RuleRepository != rules {
+		t.Error("expected the injected repositories")
+	}
+	saved, err := rules.ListByDeviceType(ctx, "ELEC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 1 || saved[0].ID != "elec-range" {
+		t.Errorf("expected the inline rule saved to the injected repository, got %+v", saved)
+	}
+}
+
+func TestValidateReportsEveryProblemWithPath(t *testing.T) {
+	_, err := config.Parse([]byte(`{
+  "standardizer": {"rounding_mode": "HALF", "timezone": "Mars/Olympus"},
+  "storage": {"type": "sqlite"},
+  "rules": [
+    {"id": "r1", "device_type": "ELEC", "type": "NOPE"},
+    {"id": "r1", "type": "RANGE", "parameters": {"min": 0, "max": 1}}
+  ]
+}`))
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{
+		`standardizer.rounding_mode: unknown value "HALF" (want HALF_UP, HALF_EVEN, TRUNCATE)`,
+		`standardizer.timezone: unknown time zone "Mars/Olympus"`,
+		`storage.dsn: is required for sqlite storage`,
+		`rules[0] (r1): `,
+		`rules[1] (r1).device_type: is required`,
+		`rules[1] (r1).id: duplicates rules[0]`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing %q in:\n%v", want, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]struct{ input, want string }{
+		"unknown field": {`{"standardizer": {"intreval": "15m"}}`, `unknown field "intreval"`},
+		"syntax":        {"{\n  \"storage\": {\"type\": \"memory\",}\n}", "line 2, column"},
+		"type":          {"{\n  \"standardizer\": {\"precision\": \"high\"}\n}", "line 2, column"},
+		"duration":      {`{"standardizer": {"interval": 900}}`, `duration must be a string such as "15m"`},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := config.Parse([]byte(tc.input))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadWithDecoder(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prism.yaml")
+	if err := os.WriteFile(path, []byte(pipelineConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Load(path); err == nil || !strings.Contains(err.Error(), "config.WithDecoder") {
+		t.Fatalf("expected unsupported extension error, got %v", err)
+	}
+
+	// JSON 是 YAML 的子集，这里用 json.Unmarshal 代替 YAML 解析库
+	cfg, err := config.Load(path, config.WithDecoder(".yaml", json.Unmarshal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Standardizer.Interval.Std() != 15*time.Minute || cfg.Standardizer.RoundingMode != domain.RoundHalfEven || len(cfg.Rules) != 1 {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+}
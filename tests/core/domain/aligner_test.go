@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+func TestFindSnapshotMode(t *testing.T) {
+	grid := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	readings := []domain.Reading{
+		{Timestamp: grid.Add(-4 * time.Minute), Value: 100},
+		{Timestamp: grid.Add(1 * time.Minute), Value: 101},
+	}
+	aligner := domain.NewAligner(5 * time.Minute)
+
+	if got := aligner.FindSnapshot(readings, grid); got == nil || got.Value != 101 {
+		t.Errorf("NEAREST: expected the 10:01 reading, got %+v", got)
+	}
+	if got := aligner.FindSnapshotMode(readings, grid, domain.SnapshotAtOrBefore); got == nil || got.Value != 100 {
+		t.Errorf("AT_OR_BEFORE: expected the 09:56 reading, got %+v", got)
+	}
+
+	// Only a later reading within tolerance: billing semantics leave the slot empty
+	if got := aligner.FindSnapshotMode(readings[1:], grid, domain.SnapshotAtOrBefore); got != nil {
+		t.Errorf("AT_OR_BEFORE must not use a reading after the grid time, got %+v", got)
+	}
+
+	// An exact match is always taken
+	exact := []domain.Reading{{Timestamp: grid, Value: 99}}
+	if got := aligner.FindSnapshotMode(exact, grid, domain.SnapshotAtOrBefore); got == nil || got.Value != 99 {
+		t.Errorf("AT_OR_BEFORE: expected the exact reading, got %+v", got)
+	}
+
+	// The mode field sets the default for FindSnapshot
+	aligner.Mode = domain.SnapshotAtOrBefore
+	if got := aligner.FindSnapshot(readings, grid); got == nil || got.Value != 100 {
+		t.Errorf("Mode=AT_OR_BEFORE: expected the 09:56 reading, got %+v", got)
+	}
+}
+
+func TestFindSnapshotInterpolate(t *testing.T) {
+	grid := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	readings := []domain.Reading{
+		{Timestamp: grid.Add(-1 * time.Minute), Value: 100, Confidence: 0.9},
+		{Timestamp: grid.Add(3 * time.Minute), Value: 140},
+	}
+	aligner := domain.NewAligner(5 * time.Minute)
+
+	got := aligner.FindSnapshotMode(readings, grid, domain.SnapshotInterpolate)
+	if got == nil {
+		t.Fatal("expected an interpolated reading")
+	}
+	if got.Value != 110 || !got.Timestamp.Equal(grid) {
+		t.Errorf("expected 110 at the grid time, got %v at %v", got.Value, got.Timestamp)
+	}
+	if got.Quality != domain.QualityInterpolated || got.Confidence != 0.9 {
+		t.Errorf("expected INTERPOLATED with confidence 0.9, got %s / %v", got.Quality, got.Confidence)
+	}
+	if readings[0].Value != 100 || readings[0].Quality != "" {
+		t.Errorf("source readings must not be modified, got %+v", readings[0])
+	}
+
+	// Only one side within tolerance: falls back to the nearest reading
+	far := []domain.Reading{readings[0], {Timestamp: grid.Add(10 * time.Minute), Value: 200}}
+	if got := aligner.FindSnapshotMode(far, grid, domain.SnapshotInterpolate); got == nil || got.Value != 100 || got.Quality != "" {
+		t.Errorf("expected fallback to the 09:59 reading, got %+v", got)
+	}
+}
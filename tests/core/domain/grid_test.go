@@ -0,0 +1,69 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+func TestTimeGridDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	// 2023-03-12 is the spring-forward day in New York (23 hours)
+	daily := domain.NewTimeGrid(24*time.Hour, ny)
+	start := daily.Floor(time.Date(2023, 3, 12, 15, 0, 0, 0, time.UTC))
+	if want := time.Date(2023, 3, 12, 0, 0, 0, 0, ny); !start.Equal(want) {
+		t.Fatalf("expected local midnight %v, got %v", want, start)
+	}
+	if d := daily.Next(start).Sub(start); d != 23*time.Hour {
+		t.Errorf("expected 23h day on DST start, got %v", d)
+	}
+
+	// 2023-11-05 is the fall-back day (25 hours)
+	fall := daily.Floor(time.Date(2023, 11, 5, 15, 0, 0, 0, time.UTC))
+	if d := daily.Next(fall).Sub(fall); d != 25*time.Hour {
+		t.Errorf("expected 25h day on DST end, got %v", d)
+	}
+
+	// Hourly slots stay on local whole hours across the transition
+	hourly := domain.NewTimeGrid(time.Hour, ny)
+	slot := start
+	for i := 0; i < 23; i++ {
+		if slot.In(ny).Minute() != 0 {
+			t.Fatalf("slot %v not on a whole local hour", slot)
+		}
+		slot = hourly.Next(slot)
+	}
+	if !slot.Equal(daily.Next(start)) {
+		t.Errorf("23 hourly slots should reach the next local midnight, got %v", slot.In(ny))
+	}
+}
+
+func TestTimeGridAnchor(t *testing.T) {
+	grid := domain.NewTimeGrid(15*time.Minute, time.UTC).WithAnchor(5 * time.Minute)
+	base := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	if got, want := grid.Floor(base.Add(3*time.Minute)), base.Add(-10*time.Minute); !got.Equal(want) {
+		t.Errorf("Floor(10:03): expected %v, got %v", want, got)
+	}
+	if got, want := grid.Ceil(base.Add(6*time.Minute)), base.Add(20*time.Minute); !got.Equal(want) {
+		t.Errorf("Ceil(10:06): expected %v, got %v", want, got)
+	}
+
+	slot := grid.Floor(base.Add(5 * time.Minute))
+	for _, minute := range []int{5, 20, 35, 50, 5} {
+		if slot.Minute() != minute {
+			t.Fatalf("expected slot at :%02d, got %v", minute, slot)
+		}
+		slot = grid.Next(slot)
+	}
+
+	// Offsets are taken modulo the interval
+	if got := domain.NewTimeGrid(15*time.Minute, time.UTC).WithAnchor(20 * time.Minute).Floor(base); got.Minute() != 50 {
+		t.Errorf("anchor 20m on a 15m grid should behave as 5m, got %v", got)
+	}
+}
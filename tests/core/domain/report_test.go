@@ -0,0 +1,60 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+func TestReportPeriodCalendar(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	// 2023-11-05: clocks fall back at 02:00 EDT, so the local day lasts 25 hours
+	fallBack := time.Date(2023, 11, 5, 12, 0, 0, 0, ny)
+	day := domain.ReportPeriodDay.Start(fallBack, ny)
+	if next := domain.ReportPeriodDay.Next(day, ny); next.Sub(day) != 25*time.Hour {
+		t.Errorf("expected a 25h day, got %s", next.Sub(day))
+	}
+	// The repeated 01:00 hour yields two distinct periods
+	firstOne := time.Date(2023, 11, 5, 5, 30, 0, 0, time.UTC) // 01:30 EDT
+	secondOne := firstOne.Add(time.Hour)                      // 01:30 EST
+	a, b := domain.ReportPeriodHour.Start(firstOne, ny), domain.ReportPeriodHour.Start(secondOne, ny)
+	if b.Sub(a) != time.Hour || !domain.ReportPeriodHour.Next(a, ny).Equal(b) {
+		t.Errorf("expected consecutive hourly periods across the fall-back, got %s and %s", a, b)
+	}
+
+	// 2023-03-12: spring forward, 23-hour day
+	spring := domain.ReportPeriodDay.Start(time.Date(2023, 3, 12, 12, 0, 0, 0, ny), ny)
+	if next := domain.ReportPeriodDay.Next(spring, ny); next.Sub(spring) != 23*time.Hour {
+		t.Errorf("expected a 23h day, got %s", next.Sub(spring))
+	}
+
+	// Month lengths follow the calendar, including leap years
+	for _, tc := range []struct {
+		month time.Time
+		days  int
+	}{
+		{time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 29},
+		{time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC), 28},
+		{time.Date(2023, 4, 30, 23, 0, 0, 0, time.UTC), 30},
+		{time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), 31},
+	} {
+		start := domain.ReportPeriodMonth.Start(tc.month, nil)
+		if next := domain.ReportPeriodMonth.Next(start, nil); next.Sub(start) != time.Duration(tc.days)*24*time.Hour {
+			t.Errorf("%s: expected %d days, got %s", tc.month.Format("2006-01"), tc.days, next.Sub(start))
+		}
+	}
+
+	// Weeks start on Monday, including for Sunday and across the spring-forward
+	week := domain.ReportPeriodWeek.Start(time.Date(2023, 3, 12, 12, 0, 0, 0, ny), ny) // Sunday
+	if want := time.Date(2023, 3, 6, 0, 0, 0, 0, ny); !week.Equal(want) {
+		t.Errorf("expected week to start %s, got %s", want, week)
+	}
+	if next := domain.ReportPeriodWeek.Next(week, ny); next.Sub(week) != 7*24*time.Hour-time.Hour {
+		t.Errorf("expected a 167h week, got %s", next.Sub(week))
+	}
+}
@@ -0,0 +1,56 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+func TestStandardizationResultErr(t *testing.T) {
+	ok := &domain.StandardizationResult{}
+	if ok.Failed() || ok.Err() != nil {
+		t.Fatal("result without device errors must not fail")
+	}
+
+	errBoom := errors.New("boom")
+	partial := &domain.StandardizationResult{
+		Readings: []domain.StandardReading{{DeviceID: "D1"}},
+		DeviceErrors: map[string]error{
+			"D3": errBoom,
+			"D2": errors.New("bad grid"),
+		},
+	}
+	if !partial.Failed() {
+		t.Fatal("expected Failed() with device errors")
+	}
+	err := partial.Err()
+	if !errors.Is(err, errBoom) {
+		t.Errorf("expected joined error to wrap device error, got %v", err)
+	}
+	if msg := err.Error(); strings.Index(msg, "D2") > strings.Index(msg, "D3") {
+		t.Errorf("expected device errors ordered by id, got %q", msg)
+	}
+}
+
+func TestBatchFingerprint(t *testing.T) {
+	ts := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	a := domain.StandardReading{DeviceID: "D1", Timestamp: ts, ValueScaled: 1, ScaleFactor: 10000, IngestedAt: time.Now()}
+	b := domain.StandardReading{DeviceID: "D2", Timestamp: ts, ValueScaled: 2, ScaleFactor: 10000}
+
+	first := domain.BatchFingerprint("B1", []domain.StandardReading{a, b})
+	a.IngestedAt = a.IngestedAt.Add(time.Minute)
+	if again := domain.BatchFingerprint("B1", []domain.StandardReading{b, a}); again != first {
+		t.Errorf("fingerprint must ignore order and IngestedAt: %s != %s", again, first)
+	}
+
+	a.ValueScaled = 3
+	if changed := domain.BatchFingerprint("B1", []domain.StandardReading{a, b}); changed == first {
+		t.Error("fingerprint must change with content")
+	}
+	if other := domain.BatchFingerprint("B2", []domain.StandardReading{b}); !strings.HasPrefix(other, "batch:B2:") {
+		t.Errorf("expected batch-scoped key, got %s", other)
+	}
+}
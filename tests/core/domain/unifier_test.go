@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+)
+
+func TestScaleExact(t *testing.T) {
+	cases := []struct {
+		val    float64
+		factor int
+		want   int64
+	}{
+		{100.00019, 10000, 1000002},
+		{123.4567, 10000, 1234567},
+		{0.1 + 0.2, 10, 3},
+		{1.005, 100, 101},
+		{-1.005, 100, -101},
+		{-100.00019, 10000, -1000002},
+		{12.3456, 1000, 12346},
+		{math.NaN(), 10000, 0},
+		{1e300, 10000, math.MaxInt64},
+	}
+	for _, tc := range cases {
+		if got := domain.ScaleExact(tc.val, tc.factor); got != tc.want {
+			t.Errorf("ScaleExact(%v, %d) = %d, want %d", tc.val, tc.factor, got, tc.want)
+		}
+	}
+}
+
+func TestScaleDecimalRoundingModes(t *testing.T) {
+	cases := []struct {
+		val  float64
+		mode domain.RoundingMode
+		want int64
+	}{
+		{2.5, domain.RoundHalfUp, 3},
+		{2.5, domain.RoundHalfEven, 2},
+		{3.5, domain.RoundHalfEven, 4},
+		{-2.5, domain.RoundHalfEven, -2},
+		{2.51, domain.RoundHalfEven, 3},
+		{2.9, domain.RoundTruncate, 2},
+		{-2.9, domain.RoundTruncate, -2},
+	}
+	for _, tc := range cases {
+		if got := domain.ScaleDecimal(tc.val, 1, tc.mode); got != tc.want {
+			t.Errorf("ScaleDecimal(%v, 1, %s) = %d, want %d", tc.val, tc.mode, got, tc.want)
+		}
+	}
+}
+
+func TestScaleDecimalChecked(t *testing.T) {
+	if _, err := domain.ScaleDecimalChecked(1e16, 10000, domain.RoundHalfUp); !errors.Is(err, domain.ErrScaleOverflow) {
+		t.Errorf("expected ErrScaleOverflow, got %v", err)
+	}
+	if v, err := domain.ScaleDecimalChecked(-12.5, 10, domain.RoundHalfUp); err != nil || v != -125 {
+		t.Errorf("expected -125, got %d (%v)", v, err)
+	}
+	for _, factor := range []int{0, -10, 1024, 10 * domain.MaxScaleFactor} {
+		if err := domain.ValidateScaleFactor(factor); !errors.Is(err, domain.ErrInvalidScaleFactor) {
+			t.Errorf("factor %d: expected ErrInvalidScaleFactor, got %v", factor, err)
+		}
+	}
+	if err := domain.ValidateScaleFactor(1); err != nil {
+		t.Errorf("factor 1 should be valid, got %v", err)
+	}
+}
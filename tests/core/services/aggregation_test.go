@@ -0,0 +1,61 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestAggregationModes(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	raw := func(deviceType domain.DeviceType) []domain.Reading {
+		dev := domain.DeviceInfo{ID: "D-" + string(deviceType), Type: deviceType}
+		return []domain.Reading{
+			{DeviceInfo: dev, Timestamp: tBase.Add(1 * time.Minute), Value: 10},
+			{DeviceInfo: dev, Timestamp: tBase.Add(5 * time.Minute), Value: 30},
+			{DeviceInfo: dev, Timestamp: tBase.Add(10 * time.Minute), Value: 20},
+			{DeviceInfo: dev, Timestamp: tBase.Add(20 * time.Minute), Value: 5},
+		}
+	}
+
+	cases := []struct {
+		mode domain.AggregationMode
+		want []float64
+	}{
+		{domain.AggregationFirst, []float64{10, 5}},
+		{domain.AggregationLast, []float64{20, 5}},
+		{domain.AggregationMean, []float64{20, 5}},
+		{domain.AggregationMax, []float64{30, 5}},
+		{domain.AggregationMin, []float64{10, 5}},
+		{domain.AggregationSum, []float64{60, 5}},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			deviceType := domain.DeviceType(tc.mode)
+			standardizer := services.NewCoreStandardizer(
+				services.WithAlignment(15*time.Minute, 5*time.Minute),
+				services.WithAggregation(deviceType, tc.mode),
+			)
+
+			result, err := standardizer.ProcessAndStandardize(context.Background(), raw(deviceType))
+			if err != nil {
+				t.Fatalf("Process failed: %v", err)
+			}
+			results := result.Readings
+			if len(results) != len(tc.want) {
+				t.Fatalf("expected %d slots, got %d", len(tc.want), len(results))
+			}
+			for i, want := range tc.want {
+				slot := tBase.Add(time.Duration(i) * 15 * time.Minute)
+				if !results[i].Timestamp.Equal(slot) || results[i].ValueDisplay != want {
+					t.Errorf("slot %d: expected %v at %v, got %v at %v",
+						i, want, slot, results[i].ValueDisplay, results[i].Timestamp)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestAnomalyScannerSeasonalThreshold(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 6, 7, 0, 0, 0, 0, time.UTC) // a Wednesday
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for d := -28; d <= 0; d++ {
+		for h := range 24 {
+			ts := day.AddDate(0, 0, d).Add(time.Duration(h) * time.Hour)
+			night := h < 5
+			v := 10 + float64((d+30)%3) // 10, 11, 12
+			if night {
+				v = 1 + float64((d+30)%3)*0.5 // 1, 1.5, 2
+			}
+			if d == 0 {
+				switch {
+				case night:
+					v = 3 // base load up, but within the per-hour spread
+				case h == 14:
+					v = 40
+				default:
+					v = 11
+				}
+			}
+			batch = append(batch, domain.StandardReading{DeviceID: "M1", Timestamp: ts, Resolution: "1h", ScaleFactor: 10, ValueScaled: int64(v * 10), Quality: domain.QualityValid})
+		}
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	publisher := &recordingPublisher{}
+	scanner := services.NewAnomalyScanner(repo, services.WithAnomalyEventPublisher(publisher))
+	anomalies, err := scanner.Scan(ctx, services.AnomalyQuery{DeviceIDs: []string{"M1"}, Resolution: "1h", Start: day, End: day.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(anomalies) != 2 {
+		t.Fatalf("expected a base-load increase and a spike, got %+v", anomalies)
+	}
+	base, spike := anomalies[0], anomalies[1]
+	if base.Kind != domain.AnomalyBaseLoadIncrease || !base.Start.Equal(day) || !base.End.Equal(day.Add(5*time.Hour)) ||
+		base.Observed != 3 || base.Expected != 1.5 {
+		t.Errorf("unexpected base-load anomaly: %+v", base)
+	}
+	if spike.Kind != domain.AnomalyConsumptionSpike || !spike.Start.Equal(day.Add(14*time.Hour)) || spike.Observed != 40 || spike.Expected != 11 {
+		t.Errorf("unexpected spike anomaly: %+v", spike)
+	}
+	if events := publisher.ofType(ports.EventAnomalyDetected); len(events) != 2 {
+		t.Errorf("expected one event per anomaly, got %d", len(events))
+	}
+
+	// Without enough history nothing is flagged
+	short := services.NewAnomalyScanner(repo, services.WithBaselineWindow(24*time.Hour))
+	if anomalies, err := short.Scan(ctx, services.AnomalyQuery{DeviceIDs: []string{"M1"}, Resolution: "1h", Start: day, End: day.AddDate(0, 0, 1)}); err != nil || len(anomalies) != 0 {
+		t.Errorf("expected no anomalies from a single day of history, got %+v (%v)", anomalies, err)
+	}
+}
@@ -0,0 +1,253 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestCalibrationRequiresApproval(t *testing.T) {
+	repo := memory.NewStandardReadingRepository()
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithCalibrationApproval(memory.NewApprovalRepository(), 1),
+	).(*services.CoreStandardizer)
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "C1", Type: "ELEC"}
+	batch := func(value float64, n int) []domain.Reading {
+		var out []domain.Reading
+		for i := range n {
+			out = append(out, domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(time.Duration(i) * 15 * time.Minute), Value: value})
+		}
+		return out
+	}
+	calibration := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyCalibration, Operator: "alice"})
+
+	// 低于阈值的校准与非校准批次直接写入
+	if result, err := standardizer.ProcessAndStandardize(calibration, batch(5, 1)); err != nil || result.PendingApproval != "" {
+		t.Fatalf("expected a small calibration to be applied, got %+v, %v", result, err)
+	}
+
+	result, err := standardizer.ProcessAndStandardize(calibration, batch(7, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PendingApproval == "" || len(result.Readings) != 0 {
+		t.Fatalf("expected the batch to be parked, got %+v", result)
+	}
+	if got, _ := repo.FindExact(context.Background(), dev.ID, tBase); got.ValueDisplay != 5 {
+		t.Fatalf("expected the golden store to be untouched before approval, got %v", got.ValueDisplay)
+	}
+
+	if _, err := standardizer.Approve(context.Background(), result.PendingApproval, "alice"); !errors.Is(err, services.ErrSelfApproval) {
+		t.Errorf("expected ErrSelfApproval, got %v", err)
+	}
+	applied, err := standardizer.Approve(context.Background(), result.PendingApproval, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied.Readings) != 3 {
+		t.Errorf("expected 3 readings applied, got %d", len(applied.Readings))
+	}
+	got, _ := repo.FindExact(context.Background(), dev.ID, tBase)
+	if got.ValueDisplay != 7 || got.Priority != 1000 || got.Provenance == nil || got.Provenance.Operator != "alice" {
+		t.Errorf("expected the approved calibration to overwrite, got %+v", got)
+	}
+	if _, err := standardizer.Approve(context.Background(), result.PendingApproval, "bob"); !errors.Is(err, services.ErrApprovalNotPending) {
+		t.Errorf("expected ErrApprovalNotPending on a second approval, got %v", err)
+	}
+	if pending, _ := standardizer.PendingApprovals(context.Background(), 0); len(pending) != 0 {
+		t.Errorf("expected no pending approvals, got %+v", pending)
+	}
+}
+
+func TestRejectedCalibrationIsNotApplied(t *testing.T) {
+	repo := memory.NewStandardReadingRepository()
+	approvals := memory.NewApprovalRepository()
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithCalibrationApproval(approvals, 0),
+	).(*services.CoreStandardizer)
+	ctx := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyCalibration, Operator: "alice"})
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err := standardizer.ProcessAndStandardize(ctx, []domain.Reading{{DeviceInfo: domain.DeviceInfo{ID: "C2"}, Timestamp: tBase, Value: 1}})
+	if err != nil || result.PendingApproval == "" {
+		t.Fatalf("expected the batch to be parked, got %+v, %v", result, err)
+	}
+
+	if err := standardizer.Reject(context.Background(), result.PendingApproval, "bob", "wrong meter"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindExact(context.Background(), "C2", tBase); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected nothing written, got %v", err)
+	}
+	req, _ := approvals.Get(context.Background(), result.PendingApproval)
+	if req.Status != domain.ApprovalStatusRejected || req.ReviewedBy != "bob" || req.Note != "wrong meter" {
+		t.Errorf("unexpected review record %+v", req)
+	}
+}
+
+func TestApprovalRequiresKnownSubmitter(t *testing.T) {
+	approvals := memory.NewApprovalRepository()
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(memory.NewStandardReadingRepository()),
+		services.WithCalibrationApproval(approvals, 0),
+	).(*services.CoreStandardizer)
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	readings := []domain.Reading{{DeviceInfo: domain.DeviceInfo{ID: "C3"}, Timestamp: tBase, Value: 1}}
+
+	anonymous := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyCalibration})
+	if _, err := standardizer.ProcessAndStandardize(anonymous, readings); !errors.Is(err, services.ErrSubmitterRequired) {
+		t.Errorf("expected ErrSubmitterRequired, got %v", err)
+	}
+
+	// 提交人未知的历史记录无法确认审批人不同，只能驳回
+	id, _ := approvals.Create(context.Background(), domain.ApprovalRequest{Readings: readings})
+	if _, err := standardizer.Approve(context.Background(), id, "bob"); !errors.Is(err, services.ErrSelfApproval) {
+		t.Errorf("expected ErrSelfApproval without a recorded submitter, got %v", err)
+	}
+	if err := standardizer.Reject(context.Background(), id, "bob", "unknown submitter"); err != nil {
+		t.Errorf("expected a record without submitter to be rejectable, got %v", err)
+	}
+}
+
+func TestConcurrentApprovalsApplyOnce(t *testing.T) {
+	approvals := memory.NewApprovalRepository()
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(memory.NewStandardReadingRepository()),
+		services.WithCalibrationApproval(approvals, 0),
+	).(*services.CoreStandardizer)
+	ctx := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyCalibration, Operator: "alice"})
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	parked, err := standardizer.ProcessAndStandardize(ctx, []domain.Reading{{DeviceInfo: domain.DeviceInfo{ID: "C4"}, Timestamp: tBase, Value: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const reviewers = 16
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make(chan error, reviewers)
+	)
+	for i := range reviewers {
+		wg.Go(func() {
+			<-start
+			var err error
+			if i%2 == 0 {
+				_, err = standardizer.Approve(context.Background(), parked.PendingApproval, fmt.Sprintf("reviewer-%d", i))
+			} else {
+				err = standardizer.Reject(context.Background(), parked.PendingApproval, fmt.Sprintf("reviewer-%d", i), "no")
+			}
+			errs <- err
+		})
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, services.ErrApprovalNotPending):
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("expected exactly one review to win, got %d", succeeded)
+	}
+}
+
+func TestQuarantineCorrectionRequiresApproval(t *testing.T) {
+	repo := memory.NewStandardReadingRepository()
+	quarantine := memory.NewQuarantineRepository()
+	policy := services.DefaultQuarantinePolicy()
+	policy.Mode = services.QuarantineSync
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithQuarantineRepository(quarantine),
+		services.WithQuarantinePolicy(policy),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionReject}),
+		services.WithCalibrationApproval(memory.NewApprovalRepository(), 0),
+	).(*services.CoreStandardizer)
+	ctx := context.Background()
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "Q1", Type: "ELEC"}
+	if _, err := standardizer.ProcessAndStandardize(ctx, []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: -5},
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: -6},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	pending, _ := quarantine.FindPending(ctx, 0)
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 quarantined readings, got %d", len(pending))
+	}
+
+	// threshold 0: 单条修正同样需要第二人批准，批准后不经清洗规则写入
+	result, err := standardizer.Resolve(ctx, pending[0].ID, 150, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PendingApproval == "" {
+		t.Fatalf("expected the correction to be parked, got %+v", result)
+	}
+	if _, err := repo.FindExact(ctx, dev.ID, pending[0].Reading.Timestamp); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected nothing written before approval, got %v", err)
+	}
+	if _, err := standardizer.Approve(ctx, result.PendingApproval, "alice"); !errors.Is(err, services.ErrSelfApproval) {
+		t.Errorf("expected ErrSelfApproval, got %v", err)
+	}
+	if _, err := standardizer.Approve(ctx, result.PendingApproval, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repo.FindExact(ctx, dev.ID, pending[0].Reading.Timestamp); err != nil || got.ValueDisplay != 150 || got.Priority != 1000 {
+		t.Errorf("expected the approved correction to be written, got %+v (%v)", got, err)
+	}
+
+	// 驳回后修正值不写入，隔离记录恢复为 PENDING
+	result, err = standardizer.Resolve(ctx, pending[1].ID, 7, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := standardizer.Reject(ctx, result.PendingApproval, "bob", "wrong value"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindExact(ctx, dev.ID, pending[1].Reading.Timestamp); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected the rejected correction not to be written, got %v", err)
+	}
+	if left, _ := quarantine.FindPending(ctx, 0); len(left) != 1 || left[0].ID != pending[1].ID {
+		t.Errorf("expected the rejected record to be pending again, got %+v", left)
+	}
+}
+
+func TestStreamingCalibrationRequiresApproval(t *testing.T) {
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	calibration := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyCalibration, Operator: "alice"})
+	reading := domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "S1"}, Timestamp: tBase, Value: 1}
+
+	stream := services.NewStreamingStandardizer(services.WithCalibrationApproval(memory.NewApprovalRepository(), 0))
+	if _, err := stream.Push(calibration, reading); !errors.Is(err, services.ErrApprovalRequired) {
+		t.Errorf("expected ErrApprovalRequired, got %v", err)
+	}
+	if _, err := stream.Push(context.Background(), reading); err != nil {
+		t.Errorf("expected realtime readings to stream, got %v", err)
+	}
+
+	// 单条读数未超过阈值时无需审批
+	stream = services.NewStreamingStandardizer(services.WithCalibrationApproval(memory.NewApprovalRepository(), 1))
+	if _, err := stream.Push(calibration, reading); err != nil {
+		t.Errorf("expected calibration below the threshold to stream, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestAuditRecordsOverwrites(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "D1", Type: domain.DeviceTypeElec}
+	audit := memory.NewAuditRepository()
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(memory.NewStandardReadingRepository()),
+		services.WithAuditRepository(audit),
+	)
+	ingest := func(strategy domain.IngestStrategy, operator string, value float64) {
+		t.Helper()
+		ctx := domain.NewContext(context.Background(), domain.IngestContext{Strategy: strategy, Operator: operator})
+		raw := []domain.Reading{{DeviceInfo: dev, Timestamp: tBase, Value: value}}
+		if _, err := standardizer.ProcessAndStandardize(ctx, raw); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+	}
+
+	ingest(domain.IngestStrategyRealtime, "", 10)         // first write: nothing overwritten
+	ingest(domain.IngestStrategyCalibration, "alice", 12) // overwrites the realtime value
+	ingest(domain.IngestStrategyCalibration, "alice", 12) // replay: unchanged, not audited
+	ingest(domain.IngestStrategyBatchLate, "", 9)         // lower priority: rejected, not audited
+
+	entries, err := audit.FindByDevice(context.Background(), dev.ID, tBase, tBase)
+	if err != nil {
+		t.Fatalf("FindByDevice failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected exactly one audited overwrite, got %+v", entries)
+	}
+	e := entries[0]
+	if e.Old.ValueDisplay != 10 || e.New.ValueDisplay != 12 || e.Old.Priority != 100 || e.New.Priority != 1000 {
+		t.Errorf("unexpected old/new values: %+v -> %+v", e.Old, e.New)
+	}
+	if e.Operator != "alice" || e.Strategy != ports.UpsertStrategyHighPriorityWins || e.ChangedAt.IsZero() || e.BatchKey == "" {
+		t.Errorf("unexpected audit metadata: %+v", e)
+	}
+}
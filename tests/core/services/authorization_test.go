@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestAuthorizerGuardsAdminOperations(t *testing.T) {
+	var seen []ports.AuthRequest
+	authz := ports.AuthorizerFunc(func(_ context.Context, req ports.AuthRequest) error {
+		seen = append(seen, req)
+		if req.Operator != "supervisor" {
+			return ports.ErrForbidden
+		}
+		return nil
+	})
+	repo := memory.NewStandardReadingRepository()
+	quarantine := memory.NewQuarantineRepository()
+	policy := services.DefaultQuarantinePolicy()
+	policy.Mode = services.QuarantineSync
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithQuarantineRepository(quarantine),
+		services.WithQuarantinePolicy(policy),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionReject}),
+		services.WithAuthorizer(authz),
+	).(*services.CoreStandardizer)
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "Z1", Type: "ELEC"}
+
+	// 实时写入不需要授权
+	if _, err := standardizer.ProcessAndStandardize(context.Background(), []domain.Reading{{DeviceInfo: dev, Timestamp: tBase, Value: -5}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 0 {
+		t.Fatalf("expected realtime ingestion not to consult the authorizer, got %+v", seen)
+	}
+
+	calibration := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyCalibration, Operator: "intern", BatchID: "fix-9"})
+	if _, err := standardizer.ProcessAndStandardize(calibration, []domain.Reading{{DeviceInfo: dev, Timestamp: tBase, Value: 50}}); !errors.Is(err, ports.ErrForbidden) {
+		t.Errorf("expected calibration by intern to be forbidden, got %v", err)
+	}
+	want := ports.AuthRequest{Operation: ports.OperationCalibrationIngest, Operator: "intern", Resource: "fix-9"}
+	if len(seen) != 1 || seen[0] != want {
+		t.Errorf("expected authorization request %+v, got %+v", want, seen)
+	}
+
+	pending, _ := quarantine.FindPending(context.Background(), 0)
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 quarantined reading, got %d", len(pending))
+	}
+	if _, err := standardizer.Resolve(context.Background(), pending[0].ID, 7, "intern"); !errors.Is(err, ports.ErrForbidden) {
+		t.Errorf("expected resolve by intern to be forbidden, got %v", err)
+	}
+	if still, _ := quarantine.FindPending(context.Background(), 0); len(still) != 1 {
+		t.Error("expected a forbidden resolve to leave the record pending")
+	}
+	if _, err := standardizer.Resolve(context.Background(), pending[0].ID, 7, "supervisor"); err != nil {
+		t.Fatalf("expected resolve by supervisor to succeed, got %v", err)
+	}
+}
+
+func TestStreamingCalibrationRequiresAuthorization(t *testing.T) {
+	authz := ports.AuthorizerFunc(func(_ context.Context, req ports.AuthRequest) error {
+		if req.Operation == ports.OperationCalibrationIngest && req.Operator != "supervisor" {
+			return ports.ErrForbidden
+		}
+		return nil
+	})
+	repo := memory.NewStandardReadingRepository()
+	stream := services.NewStreamingStandardizer(
+		services.WithAlignment(15*time.Minute, 5*time.Minute),
+		services.WithRepository(repo),
+		services.WithAuthorizer(authz),
+	)
+	tBase := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "Z1"}
+
+	intern := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyCalibration, Operator: "intern", BatchID: "fix-9"})
+	for _, offset := range []time.Duration{time.Minute, 20 * time.Minute} {
+		if _, err := stream.Push(intern, domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(offset), Value: 50}); !errors.Is(err, ports.ErrForbidden) {
+			t.Fatalf("expected streamed calibration by intern to be forbidden, got %v", err)
+		}
+	}
+	if _, err := stream.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if repo.Len() != 0 {
+		t.Errorf("expected nothing to be written, got %d readings", repo.Len())
+	}
+
+	supervisor := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyCalibration, Operator: "supervisor", BatchID: "fix-10"})
+	if _, err := stream.Push(supervisor, domain.Reading{DeviceInfo: dev, Timestamp: tBase, Value: 50}); err != nil {
+		t.Errorf("expected calibration by supervisor to be accepted, got %v", err)
+	}
+}
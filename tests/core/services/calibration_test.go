@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestCalibrationAppliedOnStandardization(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	// 200/5 CT on a 10kV/100V PT: register values must be multiplied by 4000
+	dev := domain.DeviceInfo{
+		ID:          "FEEDER-1",
+		Type:        domain.DeviceTypeElec,
+		Calibration: &domain.Calibration{CTRatio: 40, PTRatio: 100},
+	}
+	repo := &memoryStandardRepo{}
+	standardizer := services.NewCoreStandardizer(services.WithRepository(repo))
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 1.25},
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	if len(result.Readings) != 1 {
+		t.Fatalf("expected 1 standard reading, got %d", len(result.Readings))
+	}
+	sr := result.Readings[0]
+	if sr.ValueDisplay != 5000 || sr.ValueScaled != 50000000 {
+		t.Errorf("expected calibrated value 5000 (scaled 50000000), got %v (%d)", sr.ValueDisplay, sr.ValueScaled)
+	}
+	if sr.Calibration == nil || sr.Calibration.Factor() != 4000 {
+		t.Errorf("expected applied calibration to be recorded, got %+v", sr.Calibration)
+	}
+
+	// Cleaning rules see raw register values: persisted history is reverted before use
+	next, err := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithCleaningRules(noRegressionRule{}),
+	).ProcessAndStandardize(context.Background(), []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: 1.5},
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	if len(next.Readings) != 1 || next.Readings[0].ValueDisplay != 6000 {
+		t.Errorf("expected 1.5 (> raw history 1.25) to pass and calibrate to 6000, got %+v", next.Readings)
+	}
+}
+
+func TestUncalibratedDeviceUnchanged(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1", Type: domain.DeviceTypeElec, Calibration: &domain.Calibration{}}
+
+	result, err := services.NewCoreStandardizer().ProcessAndStandardize(context.Background(), []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 12.5},
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	if sr := result.Readings[0]; sr.ValueDisplay != 12.5 || sr.Calibration != nil {
+		t.Errorf("identity calibration should not be applied, got %+v", sr)
+	}
+}
@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestAnalyzeCompleteness(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for i := range 8 { // two hours of 15m slots
+		ts := base.Add(time.Duration(i) * 15 * time.Minute)
+		batch = append(batch, domain.StandardReading{DeviceID: "GOOD", Timestamp: ts, Resolution: "15m", ScaleFactor: 1, Quality: domain.QualityValid})
+		quality := domain.QualityValid
+		switch i {
+		case 2, 3, 6:
+			continue // never reported
+		case 4:
+			quality = domain.QualityMissing // gap-fill placeholder
+		case 7:
+			quality = domain.QualityInterpolated
+		}
+		batch = append(batch, domain.StandardReading{DeviceID: "BAD", Timestamp: ts, Resolution: "15m", ScaleFactor: 1, Quality: quality})
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	stats, err := services.AnalyzeCompleteness(ctx, repo, services.CompletenessQuery{DeviceIDs: []string{"GOOD", "BAD", "GONE"},
+		Resolution: "15m", Start: base, End: base.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("AnalyzeCompleteness failed: %v", err)
+	}
+	if len(stats) != 3 || stats[0].DeviceID != "GONE" || stats[1].DeviceID != "BAD" || stats[2].DeviceID != "GOOD" {
+		t.Fatalf("expected devices ordered from worst to best, got %+v", stats)
+	}
+	if gone := stats[0]; gone.Expected != 8 || gone.Actual != 0 || len(gone.Gaps) != 1 || gone.Gaps[0].ExpectedCount != 8 {
+		t.Errorf("expected a device without data to be one 8-slot gap, got %+v", gone)
+	}
+	bad := stats[1]
+	if bad.Expected != 8 || bad.Actual != 4 || bad.Estimated != 1 || bad.Completeness != 50 {
+		t.Errorf("expected 4 of 8 slots with one interpolated, got %+v", bad)
+	}
+	if len(bad.Gaps) != 2 || !bad.Gaps[0].From.Equal(base.Add(30*time.Minute)) || !bad.Gaps[0].To.Equal(base.Add(time.Hour)) ||
+		bad.Gaps[0].ExpectedCount != 3 || bad.Gaps[1].ExpectedCount != 1 || bad.Gaps[0].Interval != 15*time.Minute {
+		t.Errorf("expected gaps 00:30-01:00 (3 slots, MISSING included) and 01:30, got %+v", bad.Gaps)
+	}
+	if stats[2].Completeness != 100 || len(stats[2].Gaps) != 0 {
+		t.Errorf("expected full completeness, got %+v", stats[2])
+	}
+}
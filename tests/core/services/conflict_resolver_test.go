@@ -0,0 +1,64 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestConflictResolverOverridesStrategy(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := time.Now()
+	dev := domain.DeviceInfo{ID: "C1", Type: domain.DeviceTypeElec}
+
+	// Calibrations at both slots: a stale one (100 days) and a fresh one (10 days)
+	repo := memory.NewStandardReadingRepository()
+	_ = repo.SaveBatch(ctx, []domain.StandardReading{
+		{DeviceID: "C1", Timestamp: tBase, Resolution: "15m", ValueDisplay: 1, Priority: 1000, IngestedAt: now.Add(-100 * 24 * time.Hour)},
+		{DeviceID: "C1", Timestamp: tBase.Add(15 * time.Minute), Resolution: "15m", ValueDisplay: 2, Priority: 1000, IngestedAt: now.Add(-10 * 24 * time.Hour)},
+	}, ports.UpsertStrategyLastWriteWins, "")
+
+	// Prefer calibration unless it is older than 90 days
+	preferFreshCalibration := func(existing, incoming domain.StandardReading) ports.ConflictDecision {
+		if existing.Priority < domain.IngestStrategyCalibration.GetPriority() {
+			return ports.ConflictUseStrategy
+		}
+		if incoming.IngestedAt.Sub(existing.IngestedAt) > 90*24*time.Hour {
+			return ports.ConflictReplace
+		}
+		return ports.ConflictKeepExisting
+	}
+	standardizer := services.NewCoreStandardizer(
+		services.WithAlignment(15*time.Minute, time.Minute),
+		services.WithRepository(repo),
+		services.WithConflictResolver(preferFreshCalibration),
+	)
+	_, err := standardizer.ProcessAndStandardize(ctx, []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 10},
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: 20},
+		{DeviceInfo: dev, Timestamp: tBase.Add(30 * time.Minute), Value: 30},
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+
+	got, _ := repo.FindRange(ctx, "C1", tBase, tBase.Add(30*time.Minute))
+	if len(got) != 3 {
+		t.Fatalf("expected 3 standard readings, got %+v", got)
+	}
+	if got[0].ValueDisplay != 10 {
+		t.Errorf("10:00: expected the stale calibration to be replaced despite its priority, got %v", got[0].ValueDisplay)
+	}
+	if got[1].ValueDisplay != 2 {
+		t.Errorf("10:15: expected the fresh calibration to be kept, got %v", got[1].ValueDisplay)
+	}
+	if got[2].ValueDisplay != 30 {
+		t.Errorf("10:30: expected the uncontested slot to be written, got %v", got[2].ValueDisplay)
+	}
+}
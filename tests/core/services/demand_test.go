@@ -0,0 +1,73 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestAnalyzeDemand(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	usage := map[string][]int64{ // 15m interval usage in Wh-precision kWh (scale 1000)
+		"M1": {2000, 3000, 2500, 1000},
+		"M2": {1000, 500, 2500, 4000},
+	}
+	var batch []domain.StandardReading
+	for id, values := range usage {
+		for i, v := range values {
+			batch = append(batch, domain.StandardReading{DeviceID: id, Timestamp: base.Add(time.Duration(i) * 15 * time.Minute),
+				Resolution: "15m", ValueScaled: v, ScaleFactor: 1000, Quality: domain.QualityValid})
+		}
+	}
+	batch = append(batch, domain.StandardReading{DeviceID: "M2", Timestamp: base.Add(time.Hour), Resolution: "15m",
+		ValueScaled: 99000, ScaleFactor: 1000, Quality: domain.QualityWithdrawn})
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	single, err := services.AnalyzeDemand(ctx, repo, services.DemandQuery{DeviceIDs: []string{"M1"}, Resolution: "15m",
+		Start: base, End: base.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("AnalyzeDemand failed: %v", err)
+	}
+	peak, ok := single.Max()
+	if !ok || peak.DemandScaled != 12000 || peak.Demand != 12 || !peak.Start.Equal(base.Add(15*time.Minute)) || peak.Usage != 3 {
+		t.Errorf("expected a 12 kW peak (3 kWh x 4) at 12:15, got %+v", peak)
+	}
+
+	// The group peak is coincident: 12:30 (2.5 + 2.5), not the sum of individual peaks (3 + 4)
+	group, err := services.AnalyzeDemand(ctx, repo, services.DemandQuery{DeviceIDs: []string{"M1", "M2", "M1"}, Resolution: "15m",
+		Start: base, End: base.Add(2 * time.Hour), TopN: 3})
+	if err != nil {
+		t.Fatalf("AnalyzeDemand failed: %v", err)
+	}
+	if len(group.Peaks) != 3 || group.Interval != 15*time.Minute {
+		t.Fatalf("expected the top 3 windows, got %+v", group)
+	}
+	if p := group.Peaks[0]; p.DemandScaled != 20000 || !p.Start.Equal(base.Add(30*time.Minute)) || p.Devices != 2 {
+		t.Errorf("expected a 20 kW coincident peak at 12:30, got %+v", p)
+	}
+	if group.Peaks[1].DemandScaled != 20000 || !group.Peaks[1].Start.Equal(base.Add(45*time.Minute)) {
+		t.Errorf("expected ties to be ordered by time, got %+v", group.Peaks[1])
+	}
+	if group.Peaks[2].DemandScaled != 14000 {
+		t.Errorf("expected the third window at 14 kW, got %+v", group.Peaks[2])
+	}
+
+	if _, err := services.AnalyzeDemand(ctx, repo, services.DemandQuery{DeviceIDs: []string{"M1"}, Resolution: "7x",
+		Start: base, End: base.Add(time.Hour)}); err == nil {
+		t.Error("expected an error for an invalid resolution")
+	}
+	empty, err := services.AnalyzeDemand(ctx, repo, services.DemandQuery{DeviceIDs: []string{"M9"}, Resolution: "1h",
+		Start: base, End: base.Add(time.Hour)})
+	if _, ok := empty.Max(); err != nil || ok {
+		t.Errorf("expected no peak without data, got %+v (%v)", empty, err)
+	}
+}
@@ -0,0 +1,42 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestDuplicatePolicies(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1"}
+	raw := func() []domain.Reading {
+		return []domain.Reading{
+			{DeviceInfo: dev, Timestamp: tBase, Value: 10, Priority: 100},
+			{DeviceInfo: dev, Timestamp: tBase, Value: 20, Priority: 1000},
+			{DeviceInfo: dev, Timestamp: tBase, Value: 30, Priority: 50},
+		}
+	}
+
+	cases := []struct {
+		policy      domain.DuplicatePolicy
+		value       float64
+		quarantined int
+	}{
+		{domain.DuplicateKeepFirst, 10, 2},
+		{domain.DuplicateKeepLast, 30, 2},
+		{domain.DuplicateKeepHighestPrio, 20, 2},
+		{domain.DuplicateAverage, 20, 0},
+	}
+
+	for _, c := range cases {
+		clean, quarantined := services.NewSanitizerWithPolicy(c.policy).Clean(raw())
+		if len(clean) != 1 || clean[0].Value != c.value {
+			t.Errorf("%s: expected single reading with value %v, got %+v", c.policy, c.value, clean)
+		}
+		if len(quarantined) != c.quarantined {
+			t.Errorf("%s: expected %d quarantined, got %d", c.policy, c.quarantined, len(quarantined))
+		}
+	}
+}
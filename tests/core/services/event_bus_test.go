@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/ingest"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestEventBusSubscribeAndUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	bus := services.NewEventBus()
+
+	var all, typed int
+	unsubscribeAll := bus.Subscribe(func(context.Context, ports.Event) error { all++; return nil })
+	unsubscribeTyped := services.Subscribe(bus, func(_ context.Context, e ports.PersistFailed) error {
+		typed++
+		return e.Err
+	})
+
+	if err := bus.Publish(ctx, ports.BatchIngested{Format: "csv"}); err != nil {
+		t.Fatal(err)
+	}
+	err := bus.Publish(ctx, ports.PersistFailed{Err: errors.New("disk full")})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected handler error to be returned, got %v", err)
+	}
+	if all != 2 || typed != 1 {
+		t.Fatalf("expected 2 / 1 deliveries, got %d / %d", all, typed)
+	}
+
+	unsubscribeAll()
+	unsubscribeTyped()
+	unsubscribeTyped() // 重复调用无副作用
+	_ = bus.Publish(ctx, ports.PersistFailed{})
+	if all != 2 || typed != 1 {
+		t.Fatalf("unsubscribed handlers should not be called, got %d / %d", all, typed)
+	}
+}
+
+func TestPipelineEventsOnBus(t *testing.T) {
+	ctx := context.Background()
+	bus := services.NewEventBus()
+	recorder := &recordingPublisher{}
+	bus.Subscribe(recorder.Publish)
+
+	repo := &flakyStandardRepo{failures: 1}
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 1000, Action: domain.ActionReject}),
+		services.WithEventPublisher(bus),
+	)
+	ingestor := ingest.NewCsvUniversalIngestor(func(ctx context.Context, readings []domain.Reading) error {
+		_, err := standardizer.ProcessAndStandardize(ctx, readings)
+		return err
+	}, ingest.WithEventPublisher(bus))
+
+	csv := "device_id,timestamp,value\nE1,2023-01-01T10:00:00Z,10\nE1,2023-01-01T10:15:00Z,-5\nE1,2023-01-01T10:30:00Z,30\n"
+	if _, err := ingestor.IngestStream(ctx, strings.NewReader(csv)); err == nil {
+		t.Fatal("expected first persist attempt to fail")
+	}
+	if _, err := ingestor.IngestStream(ctx, strings.NewReader(csv)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := recorder.ofType(ports.EventPersistFailed); len(got) != 1 || got[0].(ports.PersistFailed).Err == nil {
+		t.Errorf("expected 1 PersistFailed event, got %+v", got)
+	}
+	if got := recorder.ofType(ports.EventReadingQuarantined); len(got) != 2 || got[0].(ports.ReadingQuarantined).Record.Reading.Value != -5 {
+		t.Errorf("expected the negative reading quarantined once per attempt, got %+v", got)
+	}
+	standardized := recorder.ofType(ports.EventBatchStandardized)
+	if len(standardized) != 1 {
+		t.Fatalf("expected 1 BatchStandardized event, got %d", len(standardized))
+	}
+	if ev := standardized[0].(ports.BatchStandardized); ev.Input != 3 || ev.Quarantined != 1 || len(ev.Readings) == 0 || ev.Elapsed < 0 {
+		t.Errorf("unexpected standardized event: %+v", ev)
+	}
+	ingested := recorder.ofType(ports.EventBatchIngested)
+	if len(ingested) != 2 {
+		t.Fatalf("expected 2 BatchIngested events, got %d", len(ingested))
+	}
+	if ev := ingested[1].(ports.BatchIngested); ev.Format != "csv" || ev.Result.Success != 3 || ev.OccurredAt.After(time.Now()) {
+		t.Errorf("unexpected ingested event: %+v", ev)
+	}
+}
@@ -0,0 +1,101 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+// recordingPublisher collects published events
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []ports.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event ports.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) ofType(t ports.EventType) []ports.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var out []ports.Event
+	for _, e := range p.events {
+		if e.EventType() == t {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestStandardizerEmitsChangeEvents(t *testing.T) {
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "E1", Type: domain.DeviceTypeElec}
+	publisher := &recordingPublisher{}
+
+	standardizer := services.NewCoreStandardizer(
+		services.WithAlignment(15*time.Minute, time.Minute),
+		services.WithRepository(memory.NewStandardReadingRepository()),
+		services.WithQuarantineRepository(memory.NewQuarantineRepository()),
+		services.WithQuarantinePolicy(services.QuarantinePolicy{Mode: services.QuarantineSync}),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 1000, Action: domain.ActionReject}),
+		services.WithEventPublisher(publisher),
+	)
+	_, err := standardizer.ProcessAndStandardize(context.Background(), []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 10},
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: 20},
+		{DeviceInfo: dev, Timestamp: tBase.Add(30 * time.Minute), Value: -5},
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+
+	saved := publisher.ofType(ports.EventStandardReadingSaved)
+	if len(saved) != 1 {
+		t.Fatalf("expected 1 StandardReadingSaved event, got %d", len(saved))
+	}
+	if ev := saved[0].(ports.StandardReadingSaved); len(ev.Readings) == 0 || ev.BatchKey == "" || ev.Strategy != ports.UpsertStrategyHighPriorityWins {
+		t.Errorf("unexpected saved event: %+v", ev)
+	}
+	quarantined := publisher.ofType(ports.EventQuarantineCreated)
+	if len(quarantined) != 1 || quarantined[0].(ports.QuarantineCreated).Record.Reading.Value != -5 {
+		t.Errorf("expected 1 QuarantineCreated event for the rejected reading, got %+v", quarantined)
+	}
+}
+
+func TestRangeLearnerEmitsRuleUpdated(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "E1", Type: domain.DeviceTypeElec}
+	readings := memory.NewStandardReadingRepository()
+	for i := range 10 {
+		_ = readings.Save(ctx, domain.StandardReading{DeviceID: "E1", Timestamp: now.Add(-time.Duration(i+1) * time.Hour), Resolution: "1h", ValueDisplay: float64(i)},
+			ports.UpsertStrategyLastWriteWins)
+	}
+	publisher := &recordingPublisher{}
+	learner := services.NewRangeLearner(readings, memory.NewCleaningRuleRepository(nil),
+		services.WithMinSamples(10), services.WithLearnerEventPublisher(publisher))
+
+	for run := range 2 {
+		if _, err := learner.Learn(ctx, []domain.DeviceInfo{dev}, now); err != nil {
+			t.Fatalf("Learn failed: %v", err)
+		}
+		updated := publisher.ofType(ports.EventRuleUpdated)
+		if len(updated) != run+1 {
+			t.Fatalf("expected %d RuleUpdated events, got %d", run+1, len(updated))
+		}
+		if ev := updated[run].(ports.RuleUpdated); ev.Created != (run == 0) || ev.Rule.ID != services.LearnedRangeRulePrefix+"E1" {
+			t.Errorf("run %d: unexpected event %+v", run, ev)
+		}
+	}
+}
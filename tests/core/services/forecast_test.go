@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestSeasonalNaiveForecaster(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for h := range 48 { // two days of hourly usage, the last hour missing
+		quality := domain.QualityValid
+		if h == 47 {
+			quality = domain.QualityMissing
+		}
+		batch = append(batch, domain.StandardReading{DeviceID: "M1", Timestamp: base.Add(time.Duration(h) * time.Hour),
+			Resolution: "1h", ValueScaled: int64(h), ValueDisplay: float64(h) / 10, ScaleFactor: 10, Quality: quality})
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	now := base.Add(48 * time.Hour)
+	forecaster := services.NewSeasonalNaiveForecaster(repo, "1h",
+		services.WithSeason(24*time.Hour), services.WithForecastClock(func() time.Time { return now }))
+
+	fc, err := forecaster.Forecast(ctx, "M1", 30*time.Hour)
+	if err != nil {
+		t.Fatalf("Forecast failed: %v", err)
+	}
+	// The last usable reading is 46:00, so the forecast starts at 47:00 and spans past one season
+	if !fc.Start.Equal(base.Add(47*time.Hour)) || !fc.End.Equal(base.Add(77*time.Hour)) || fc.Method != "seasonal-naive" {
+		t.Fatalf("unexpected forecast window: %+v", fc)
+	}
+	if len(fc.Points) != 30 { // later hours look back two seasons, never onto the missing 47:00
+		t.Fatalf("expected 30 points, got %d", len(fc.Points))
+	}
+	if p := fc.Points[0]; !p.Timestamp.Equal(base.Add(47*time.Hour)) || p.ValueScaled != 23 || p.ScaleFactor != 10 {
+		t.Errorf("expected 47:00 to repeat 23:00, got %+v", p)
+	}
+	if p := fc.Points[len(fc.Points)-1]; !p.Timestamp.Equal(base.Add(76*time.Hour)) || p.ValueScaled != 28 {
+		t.Errorf("expected 76:00 to repeat 28:00 two seasons back, got %+v", p)
+	}
+
+	if _, err := forecaster.Forecast(ctx, "UNKNOWN", time.Hour); !errors.Is(err, ports.ErrInsufficientHistory) {
+		t.Errorf("expected ErrInsufficientHistory, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+type recordingGapSink struct {
+	gaps []domain.DataGap
+}
+
+func (s *recordingGapSink) ReportGaps(ctx context.Context, gaps []domain.DataGap) error {
+	s.gaps = append(s.gaps, gaps...)
+	return nil
+}
+
+func TestGapFillAndGapEvents(t *testing.T) {
+	sink := &recordingGapSink{}
+	standardizer := services.NewCoreStandardizer(
+		services.WithAlignment(15*time.Minute, 5*time.Minute),
+		services.WithGapFill(domain.GapFillLinear),
+		services.WithGapSink(sink),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	raw := []domain.Reading{
+		{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: 100},
+		{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase.Add(time.Hour), Value: 140},
+	}
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	results := result.Readings
+	if len(results) != 5 {
+		t.Fatalf("expected 5 standard readings (2 real + 3 filled), got %d", len(results))
+	}
+
+	for _, r := range results {
+		if r.Timestamp.Equal(tBase.Add(30 * time.Minute)) {
+			if r.Quality != domain.QualityInterpolated || r.ValueDisplay != 120 {
+				t.Errorf("expected interpolated 120 at 10:30, got %v (%s)", r.ValueDisplay, r.Quality)
+			}
+		}
+	}
+
+	if len(sink.gaps) != 1 || sink.gaps[0].ExpectedCount != 3 {
+		t.Fatalf("expected one gap of 3 slots, got %+v", sink.gaps)
+	}
+}
@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestGenerateGroupReportRollsUpTree(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	elec := func(id string) *domain.DeviceInfo { return &domain.DeviceInfo{ID: id, Type: domain.DeviceTypeElec} }
+	topology := memory.NewTopologyRepository([]domain.TopologyNode{
+		{ID: "campus", Level: domain.NodeLevelSite},
+		{ID: "b1", ParentID: "campus", Level: domain.NodeLevelBuilding},
+		{ID: "b1-f1", ParentID: "b1", Level: domain.NodeLevelFloor},
+		{ID: "b1-f2", ParentID: "b1", Level: domain.NodeLevelFloor},
+		{ID: "m1", ParentID: "b1-f1", Level: domain.NodeLevelDevice, Device: elec("M1")},
+		{ID: "m2", ParentID: "b1-f2", Level: domain.NodeLevelDevice, Device: elec("M2")},
+		{ID: "m2-sub", ParentID: "m2", Level: domain.NodeLevelDevice, Device: elec("M2S")}, // sub-meter already counted by M2
+		{ID: "w1", ParentID: "b1-f1", Level: domain.NodeLevelDevice, Device: &domain.DeviceInfo{ID: "W1", Type: domain.DeviceTypeWater}},
+		{ID: "b2", ParentID: "campus", Level: domain.NodeLevelBuilding},
+		{ID: "m3", ParentID: "b2", Level: domain.NodeLevelDevice, Device: elec("M3")},
+	})
+
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for id, v := range map[string]int64{"M1": 15, "M2": 20, "M2S": 7, "W1": 1000, "M3": 5} {
+		scale := 10
+		if id == "M3" {
+			v, scale = 500, 1000 // finer precision is kept when summing
+		}
+		for h := range 2 {
+			batch = append(batch, domain.StandardReading{DeviceID: id, Timestamp: day.Add(time.Duration(h) * time.Hour),
+				Resolution: "1h", ValueScaled: v, ScaleFactor: scale, Quality: domain.QualityValid})
+		}
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	root, err := services.GenerateGroupReport(ctx, topology, services.NewReportGenerator(repo), services.GroupReportQuery{
+		RootID: "campus", DeviceType: domain.DeviceTypeElec, Resolution: "1h", Period: domain.ReportPeriodDay,
+		Start: day, End: day.Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("GenerateGroupReport failed: %v", err)
+	}
+	total := func(g *services.GroupReport) int64 {
+		if len(g.Reports) != 1 {
+			t.Fatalf("%s: expected one daily report, got %+v", g.Node.ID, g.Reports)
+		}
+		return g.Reports[0].UsageScaled
+	}
+	// campus = b1 (M1 1.5 + M2 2.0, x2 hours) + b2 (M3 0.5 x2) = 8.0
+	if total(root) != 8000 || root.Reports[0].ScaleFactor != 1000 || root.Reports[0].DeviceType != domain.DeviceTypeElec {
+		t.Errorf("expected a campus total of 8.0, got %+v", root.Reports[0])
+	}
+	if len(root.Children) != 2 {
+		t.Fatalf("expected two buildings, got %d", len(root.Children))
+	}
+	b1 := root.Children[0]
+	if b1.Node.ID != "b1" || total(b1) != 70 || len(b1.Children) != 2 {
+		t.Errorf("expected building b1 at 7.0 across two floors, got %+v", b1.Reports)
+	}
+	if f1 := b1.Children[0]; len(f1.Children) != 1 {
+		t.Errorf("expected the water meter to be excluded from an electricity report, got %d children", len(f1.Children))
+	}
+	if m2 := b1.Children[1].Children[0]; total(m2) != 40 {
+		t.Errorf("expected M2 to report only its own readings, got %+v", m2.Reports)
+	}
+
+	if _, err := services.GenerateGroupReport(ctx, topology, services.NewReportGenerator(repo), services.GroupReportQuery{
+		RootID: "nowhere", DeviceType: domain.DeviceTypeElec, Resolution: "1h", Period: domain.ReportPeriodDay,
+		Start: day, End: day.Add(time.Hour)}); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown root, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+type failingChecker struct{ err error }
+
+func (c failingChecker) HealthCheck(ctx context.Context) error { return c.err }
+
+func TestHealthChecksCheckAll(t *testing.T) {
+	ctx := context.Background()
+	healthy := services.HealthChecks{
+		"standard":   memory.NewStandardReadingRepository(),
+		"rules":      memory.NewCleaningRuleRepository(nil),
+		"quarantine": memory.NewQuarantineRepository(),
+	}
+	if err := healthy.CheckAll(ctx); err != nil {
+		t.Fatalf("expected all checks to pass, got %v", err)
+	}
+
+	errDown := errors.New("connection refused")
+	checks := services.HealthChecks{
+		"standard": memory.NewStandardReadingRepository(),
+		"rules":    failingChecker{errDown},
+		"outbox":   failingChecker{errors.New("table missing")},
+	}
+	err := checks.CheckAll(ctx)
+	if !errors.Is(err, errDown) {
+		t.Fatalf("expected the failing check to be reported, got %v", err)
+	}
+	if msg := err.Error(); msg != "outbox: table missing\nrules: connection refused" {
+		t.Errorf("expected failures sorted by name, got %q", msg)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := healthy.CheckAll(cancelled); err == nil || !strings.Contains(err.Error(), "standard:") {
+		t.Errorf("expected cancelled context to fail the checks, got %v", err)
+	}
+}
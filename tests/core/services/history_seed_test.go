@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+// memoryStandardRepo 内存版标准读数仓储 (按幂等键去重批次)
+type memoryStandardRepo struct {
+	readings []domain.StandardReading
+	applied  map[string]bool
+	keys     []string
+}
+
+func (r *memoryStandardRepo) Save(ctx context.Context, reading domain.StandardReading, strategy ports.UpsertStrategy) error {
+	r.readings = append(r.readings, reading)
+	return nil
+}
+
+func (r *memoryStandardRepo) SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, idempotencyKey string) error {
+	r.keys = append(r.keys, idempotencyKey)
+	if r.applied[idempotencyKey] {
+		return nil
+	}
+	if r.applied == nil {
+		r.applied = make(map[string]bool)
+	}
+	r.applied[idempotencyKey] = true
+	r.readings = append(r.readings, readings...)
+	return nil
+}
+
+func (r *memoryStandardRepo) FindExact(ctx context.Context, deviceID string, timestamp time.Time) (*domain.StandardReading, error) {
+	for i := range r.readings {
+		if r.readings[i].DeviceID == deviceID && r.readings[i].Timestamp.Equal(timestamp) {
+			return &r.readings[i], nil
+		}
+	}
+	return nil, ports.ErrNotFound
+}
+
+func (r *memoryStandardRepo) FindRange(ctx context.Context, deviceID string, start, end time.Time) ([]domain.StandardReading, error) {
+	var out []domain.StandardReading
+	for _, sr := range r.readings {
+		if sr.DeviceID == deviceID && !sr.Timestamp.Before(start) && sr.Timestamp.Before(end) {
+			out = append(out, sr)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryStandardRepo) FindLatest(ctx context.Context, deviceID string) (*domain.StandardReading, error) {
+	var latest *domain.StandardReading
+	for i, sr := range r.readings {
+		if sr.DeviceID == deviceID && (latest == nil || sr.Timestamp.After(latest.Timestamp)) {
+			latest = &r.readings[i]
+		}
+	}
+	if latest == nil {
+		return nil, ports.ErrNotFound
+	}
+	return latest, nil
+}
+
+func (r *memoryStandardRepo) FindLastBefore(ctx context.Context, deviceID string, metric domain.Metric, resolution string, before time.Time) (*domain.StandardReading, error) {
+	var latest *domain.StandardReading
+	for i, sr := range r.readings {
+		if sr.DeviceID != deviceID || sr.Metric != metric || sr.Resolution != resolution || !sr.Timestamp.Before(before) {
+			continue
+		}
+		if latest == nil || sr.Timestamp.After(latest.Timestamp) {
+			latest = &r.readings[i]
+		}
+	}
+	if latest == nil {
+		return nil, ports.ErrNotFound
+	}
+	return latest, nil
+}
+
+// noRegressionRule 拒绝小于上一条读数的值
+type noRegressionRule struct{}
+
+func (noRegressionRule) Check(ctx ports.CleaningContext, curr domain.Reading) ports.CheckResult {
+	if ctx.Previous != nil && curr.Value < ctx.Previous.Value {
+		return ports.CheckResult{Reading: curr, Passed: false, Reason: "regression"}
+	}
+	return ports.CheckResult{Reading: curr, Passed: true}
+}
+
+func TestFirstReadingValidatedAgainstHistory(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	repo := &memoryStandardRepo{readings: []domain.StandardReading{
+		{DeviceID: "D1", Timestamp: tBase.Add(-15 * time.Minute), ValueDisplay: 1000, Resolution: "15m"},
+	}}
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithCleaningRules(noRegressionRule{}),
+	)
+
+	raw := []domain.Reading{
+		{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: 500},
+	}
+	result, err := standardizer.ProcessAndStandardize(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	results := result.Readings
+	if len(results) != 0 {
+		t.Fatalf("expected regression against persisted history to be rejected, got %+v", results)
+	}
+}
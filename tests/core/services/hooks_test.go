@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestPipelineHooks(t *testing.T) {
+	var mu sync.Mutex
+	var stages []string
+	record := func(stage string) {
+		mu.Lock()
+		defer mu.Unlock()
+		stages = append(stages, stage)
+	}
+
+	standardizer := services.NewCoreStandardizer(
+		services.WithPreCleanHook(func(ctx context.Context, raw []domain.Reading) ([]domain.Reading, error) {
+			record("pre-clean")
+			for i := range raw {
+				raw[i].DeviceInfo.Type = domain.DeviceType("ELEC") // enrichment
+			}
+			return raw, nil
+		}),
+		services.WithPostCleanHook(func(ctx context.Context, clean []domain.Reading, q []domain.QuarantineReading) ([]domain.Reading, []domain.QuarantineReading, error) {
+			record("post-clean")
+			var kept []domain.Reading
+			for _, r := range clean {
+				if r.DeviceInfo.ID == "BLOCKED" {
+					q = append(q, domain.QuarantineReading{Reading: r, Reason: "blocked device"})
+					continue
+				}
+				kept = append(kept, r)
+			}
+			return kept, q, nil
+		}),
+		services.WithPreAlignHook(func(ctx context.Context, devReadings []domain.Reading) ([]domain.Reading, error) {
+			record("pre-align")
+			if devReadings[0].DeviceInfo.ID == "BROKEN" {
+				return nil, errors.New("no calibration data")
+			}
+			return devReadings, nil
+		}),
+		services.WithPrePersistHook(func(ctx context.Context, standards []domain.StandardReading) ([]domain.StandardReading, error) {
+			record("pre-persist")
+			for _, sr := range standards {
+				if sr.Quality != domain.QualityValid {
+					t.Errorf("unexpected quality %s", sr.Quality)
+				}
+			}
+			return standards, nil
+		}),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	raw := []domain.Reading{
+		{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: 1},
+		{DeviceInfo: domain.DeviceInfo{ID: "BLOCKED"}, Timestamp: tBase, Value: 2},
+		{DeviceInfo: domain.DeviceInfo{ID: "BROKEN"}, Timestamp: tBase, Value: 3},
+	}
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if len(result.Readings) != 1 || result.Readings[0].DeviceID != "D1" {
+		t.Fatalf("expected only D1 to be standardized, got %+v", result.Readings)
+	}
+	if result.DeviceErrors["BROKEN"] == nil {
+		t.Errorf("expected pre-align error recorded for BROKEN, got %v", result.DeviceErrors)
+	}
+	if stages[0] != "pre-clean" || stages[1] != "post-clean" || stages[len(stages)-1] != "pre-persist" {
+		t.Errorf("unexpected stage order: %v", stages)
+	}
+}
+
+func TestPreCleanHookErrorAbortsBatch(t *testing.T) {
+	standardizer := services.NewCoreStandardizer(
+		services.WithPreCleanHook(func(ctx context.Context, raw []domain.Reading) ([]domain.Reading, error) {
+			return nil, errors.New("enrichment unavailable")
+		}),
+	)
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	raw := []domain.Reading{{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: 1}}
+	if _, err := standardizer.ProcessAndStandardize(context.Background(), raw); err == nil {
+		t.Fatal("expected pre-clean hook error to fail the batch")
+	}
+}
@@ -0,0 +1,37 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestRetriedBatchIsIdempotent(t *testing.T) {
+	repo := &memoryStandardRepo{}
+	standardizer := services.NewCoreStandardizer(services.WithRepository(repo))
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	batch := func() []domain.Reading {
+		return []domain.Reading{
+			{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: 100},
+			{DeviceInfo: domain.DeviceInfo{ID: "D2"}, Timestamp: tBase, Value: 200},
+		}
+	}
+
+	ctx := domain.NewContext(context.Background(), domain.IngestContext{BatchID: "import-42"})
+	for range 2 {
+		if _, err := standardizer.ProcessAndStandardize(ctx, batch()); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+	}
+
+	if len(repo.keys) != 2 || repo.keys[0] != repo.keys[1] {
+		t.Fatalf("expected the same idempotency key for a retried batch, got %v", repo.keys)
+	}
+	if len(repo.readings) != 2 {
+		t.Fatalf("expected the retried batch to be applied once, got %d readings", len(repo.readings))
+	}
+}
@@ -0,0 +1,79 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestLineageTracesStandardReading(t *testing.T) {
+	repo := memory.NewStandardReadingRepository()
+	quarantine := memory.NewQuarantineRepository()
+	policy := services.DefaultQuarantinePolicy()
+	policy.Mode = services.QuarantineSync
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithRawRepository(memory.NewRawReadingRepository()),
+		services.WithAuditRepository(memory.NewAuditRepository()),
+		services.WithQuarantineRepository(quarantine),
+		services.WithQuarantinePolicy(policy),
+		services.WithAlignment(15*time.Minute, 5*time.Minute),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionReject}),
+	).(*services.CoreStandardizer)
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	slot := tBase.Add(15 * time.Minute)
+	dev := domain.DeviceInfo{ID: "L1", Type: "ELEC"}
+	ingest := func(ic domain.IngestContext, readings ...domain.Reading) {
+		t.Helper()
+		if _, err := standardizer.ProcessAndStandardize(domain.NewContext(context.Background(), ic), readings); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ingest(domain.IngestContext{Strategy: domain.IngestStrategyRealtime, BatchID: "rt-1"},
+		domain.Reading{DeviceInfo: dev, Timestamp: tBase, Value: 10},
+		domain.Reading{DeviceInfo: dev, Timestamp: slot.Add(-time.Minute), Value: -1},
+		domain.Reading{DeviceInfo: dev, Timestamp: slot.Add(time.Minute), Value: 12},
+	)
+	ingest(domain.IngestContext{Strategy: domain.IngestStrategyCalibration, BatchID: "fix-1"},
+		domain.Reading{DeviceInfo: dev, Timestamp: slot, Value: 13},
+	)
+
+	sr, err := repo.FindExact(context.Background(), dev.ID, slot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lineage, err := standardizer.Lineage(context.Background(), *sr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lineage.Raw) != 3 {
+		t.Errorf("expected 3 raw readings in the slot window, got %+v", lineage.Raw)
+	}
+	if len(lineage.Quarantined) != 1 || lineage.Quarantined[0].Reading.Value != -1 {
+		t.Errorf("expected the rejected reading in the lineage, got %+v", lineage.Quarantined)
+	}
+	if len(lineage.Rules) != 1 {
+		t.Errorf("expected the range rule to be listed, got %v", lineage.Rules)
+	}
+	if lineage.BatchID != "fix-1" {
+		t.Errorf("expected batch fix-1, got %q", lineage.BatchID)
+	}
+	if len(lineage.Audit) != 1 || lineage.Audit[0].Old.ValueDisplay != 12 || lineage.Audit[0].New.ValueDisplay != 13 {
+		t.Errorf("expected the calibration overwrite in the audit trail, got %+v", lineage.Audit)
+	}
+}
+
+func TestLineageRequiresRawRepository(t *testing.T) {
+	standardizer := services.NewCoreStandardizer().(*services.CoreStandardizer)
+	_, err := standardizer.Lineage(context.Background(), domain.StandardReading{DeviceID: "L1"})
+	if !errors.Is(err, services.ErrRawRepositoryNotConfigured) {
+		t.Fatalf("expected ErrRawRepositoryNotConfigured, got %v", err)
+	}
+}
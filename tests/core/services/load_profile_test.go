@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestGenerateLoadProfiles(t *testing.T) {
+	ctx := context.Background()
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	// Fri 2023-06-30 .. Mon 2023-07-03 (local), hourly: weekdays use 10 + day-of-month at 09:00 and 1 otherwise, weekends 2
+	for day := time.Date(2023, 6, 30, 0, 0, 0, 0, shanghai); day.Before(time.Date(2023, 7, 4, 0, 0, 0, 0, shanghai)); day = day.AddDate(0, 0, 1) {
+		for h := range 24 {
+			v := int64(1)
+			switch {
+			case day.Weekday() == time.Saturday || day.Weekday() == time.Sunday:
+				v = 2
+			case h == 9:
+				v = int64(10 + day.Day())
+			}
+			for _, id := range []string{"A", "B"} {
+				batch = append(batch, domain.StandardReading{DeviceID: id, Timestamp: day.Add(time.Duration(h) * time.Hour),
+					Resolution: "1h", ValueScaled: v * 10, ScaleFactor: 10, Quality: domain.QualityValid})
+			}
+		}
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	profiles, err := services.GenerateLoadProfiles(ctx, repo, services.LoadProfileQuery{DeviceIDs: []string{"A", "B"},
+		Resolution: "1h", Start: time.Date(2023, 6, 1, 0, 0, 0, 0, shanghai), End: time.Date(2023, 8, 1, 0, 0, 0, 0, shanghai), Location: shanghai})
+	if err != nil {
+		t.Fatalf("GenerateLoadProfiles failed: %v", err)
+	}
+	// all-month weekday/weekend, June weekday, July weekday/weekend
+	if len(profiles.Profiles) != 5 || profiles.Interval != time.Hour || profiles.Location != "Asia/Shanghai" {
+		t.Fatalf("unexpected profile set: %+v", profiles)
+	}
+
+	weekday, ok := profiles.Find(0, services.DayTypeWeekday)
+	if !ok || weekday.Days != 2 || len(weekday.Points) != 24 {
+		t.Fatalf("expected a 24-point weekday profile over 2 days, got %+v", weekday)
+	}
+	// Group sum per hour, averaged over Friday (2 x 40) and Monday (2 x 13)
+	if p := weekday.Points[9]; p.Average != 53 || p.Samples != 2 || p.Label != "09:00" {
+		t.Errorf("expected the 09:00 weekday average to be 53, got %+v", p)
+	}
+	if weekday.Points[0].Average != 2 {
+		t.Errorf("expected the night baseline of 2, got %+v", weekday.Points[0])
+	}
+
+	weekend, _ := profiles.Find(time.July, services.DayTypeWeekend)
+	if weekend.Days != 2 || weekend.Points[9].Average != 4 {
+		t.Errorf("expected the July weekend profile to be flat at 4, got %+v", weekend)
+	}
+	june, _ := profiles.Find(time.June, services.DayTypeWeekday)
+	if june.Days != 1 || june.Points[9].Average != 80 {
+		t.Errorf("expected June to contain only Friday the 30th, got %+v", june)
+	}
+
+	if _, err := services.GenerateLoadProfiles(ctx, repo, services.LoadProfileQuery{DeviceIDs: []string{"A"}, Resolution: "7h",
+		Start: time.Now().Add(-time.Hour), End: time.Now()}); err == nil {
+		t.Error("expected an error for a resolution that does not divide a day")
+	}
+}
@@ -0,0 +1,34 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestStandardizerUsesInjectedLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil)).With("component", "standardizer")
+	standardizer := services.NewCoreStandardizer(services.WithLogger(logger))
+
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	device := domain.DeviceInfo{ID: "D1", Timezone: "Mars/Olympus"}
+	raw := []domain.Reading{
+		{DeviceInfo: device, Timestamp: tBase, Value: 100},
+		{DeviceInfo: device, Timestamp: tBase.Add(15 * time.Minute), Value: 110},
+	}
+	if _, err := standardizer.ProcessAndStandardize(context.Background(), raw); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "unknown device timezone") || !strings.Contains(out, "component=standardizer") {
+		t.Fatalf("expected timezone warning on injected logger, got %q", out)
+	}
+}
@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestMultiMetricChannels(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "M1", Type: domain.DeviceTypeElec}
+	reading := func(metric domain.Metric, offset time.Duration, value float64) domain.Reading {
+		return domain.Reading{DeviceInfo: dev, Metric: metric, Timestamp: tBase.Add(offset), Value: value}
+	}
+
+	standardizer := services.NewCoreStandardizer(
+		services.WithCleaningRules(noRegressionRule{}),
+		services.WithMetricAggregation(domain.MetricPower, domain.AggregationMean),
+	)
+	result, err := standardizer.ProcessAndStandardize(context.Background(), []domain.Reading{
+		// Same timestamps on both channels are not duplicates
+		reading(domain.MetricEnergy, 0, 1000),
+		reading(domain.MetricPower, 0, 20), // far below the energy register, but power is its own channel
+		reading(domain.MetricPower, 5*time.Minute, 40),
+		reading(domain.MetricEnergy, 15*time.Minute, 1010),
+		reading(domain.MetricPower, 15*time.Minute, 50),
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+
+	values := make(map[domain.Metric][]float64)
+	for _, sr := range result.Readings {
+		if sr.DeviceID != "M1" {
+			t.Fatalf("unexpected device %s", sr.DeviceID)
+		}
+		values[sr.Metric] = append(values[sr.Metric], sr.ValueDisplay)
+	}
+
+	if got := values[domain.MetricEnergy]; len(got) != 2 || got[0] != 1000 || got[1] != 1010 {
+		t.Errorf("ENERGY channel: expected snapshots [1000 1010], got %v", got)
+	}
+	if got := values[domain.MetricPower]; len(got) != 2 || got[0] != 30 || got[1] != 50 {
+		t.Errorf("POWER channel: expected window means [30 50], got %v", got)
+	}
+
+	// Output is grouped by channel: all ENERGY readings precede POWER readings
+	if result.Readings[0].Metric != domain.MetricEnergy || result.Readings[len(result.Readings)-1].Metric != domain.MetricPower {
+		t.Errorf("expected output sorted by device, metric and timestamp, got %+v", result.Readings)
+	}
+}
+
+func TestChannelIDUnambiguous(t *testing.T) {
+	for _, pair := range [][2]string{
+		{domain.ChannelID("a#b", ""), domain.ChannelID("a", "b")},
+		{domain.ChannelID("a#b", "c"), domain.ChannelID("a", "b#c")},
+		{domain.ChannelID(`"a"#b`, ""), domain.ChannelID("a", "b")},
+	} {
+		if pair[0] == pair[1] {
+			t.Errorf("expected distinct channel IDs, both are %q", pair[0])
+		}
+	}
+
+	// 设备 "a#b" 的默认通道与设备 "a" 的 b 通道同一时刻的读数各自清洗、对齐
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	standardizer := services.NewCoreStandardizer(services.WithCleaningRules(noRegressionRule{}))
+	result, err := standardizer.ProcessAndStandardize(context.Background(), []domain.Reading{
+		{DeviceInfo: domain.DeviceInfo{ID: "a#b", Type: domain.DeviceTypeElec}, Timestamp: tBase, Value: 1000},
+		{DeviceInfo: domain.DeviceInfo{ID: "a", Type: domain.DeviceTypeElec}, Metric: "b", Timestamp: tBase, Value: 20},
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	values := make(map[string]float64)
+	for _, sr := range result.Readings {
+		values[sr.DeviceID+"/"+string(sr.Metric)] = sr.ValueDisplay
+	}
+	if len(values) != 2 || values["a#b/"] != 1000 || values["a/b"] != 20 {
+		t.Errorf("expected both channels standardized independently, got %v", values)
+	}
+}
@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+// flakyPublisher records published messages and fails the first failures calls
+type flakyPublisher struct {
+	failures  int
+	published []ports.OutboxMessage
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, msg ports.OutboxMessage) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, msg)
+	return nil
+}
+
+func TestOutboxRelay(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	outbox := memory.NewOutbox()
+	repo := memory.NewStandardReadingRepository(memory.WithOutbox(outbox, "energy.standards"))
+
+	_ = repo.Save(ctx, domain.StandardReading{DeviceID: "D1", Timestamp: tBase, Resolution: "15m", Priority: 1000}, ports.UpsertStrategyHighPriorityWins)
+	// D1@10:00 loses on priority and must not be published; D1@10:15 and D2 are
+	_ = repo.SaveBatch(ctx, []domain.StandardReading{
+		{DeviceID: "D1", Timestamp: tBase, Resolution: "15m", Priority: 100},
+		{DeviceID: "D1", Timestamp: tBase.Add(15 * time.Minute), Resolution: "15m", Priority: 100},
+		{DeviceID: "D2", Timestamp: tBase, Resolution: "15m", Priority: 100},
+	}, ports.UpsertStrategyHighPriorityWins, "")
+	if outbox.Len() != 3 {
+		t.Fatalf("expected 3 outbox messages (one per device per write), got %d", outbox.Len())
+	}
+
+	publisher := &flakyPublisher{failures: 1}
+	relay, err := services.NewOutboxRelay(outbox, publisher, 2)
+	if err != nil {
+		t.Fatalf("NewOutboxRelay failed: %v", err)
+	}
+	if n, err := relay.RunOnce(ctx); err == nil || n != 0 {
+		t.Fatalf("expected the first run to stop at the failing message, got %d / %v", n, err)
+	}
+	if n, err := relay.RunOnce(ctx); err != nil || n != 3 {
+		t.Fatalf("expected the retry to publish all 3 messages, got %d / %v", n, err)
+	}
+	if outbox.Len() != 0 {
+		t.Errorf("expected published messages to leave the outbox, %d left", outbox.Len())
+	}
+
+	if len(publisher.published) != 3 {
+		t.Fatalf("expected 3 published messages, got %d", len(publisher.published))
+	}
+	second := publisher.published[1]
+	var readings []domain.StandardReading
+	if err := json.Unmarshal(second.Payload, &readings); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if second.Topic != "energy.standards" || second.Key != "D1" || len(readings) != 1 || !readings[0].Timestamp.Equal(tBase.Add(15*time.Minute)) {
+		t.Errorf("expected only the applied D1 reading, got %s / %s / %+v", second.Topic, second.Key, readings)
+	}
+	if publisher.published[2].Key != "D2" {
+		t.Errorf("expected messages in outbox order, got key %s last", publisher.published[2].Key)
+	}
+}
@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+type recordingMetrics struct {
+	mu       sync.Mutex
+	counters map[ports.PipelineCounter]int
+	stages   map[ports.PipelineStage]int
+}
+
+func (m *recordingMetrics) AddReadings(counter ports.PipelineCounter, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[counter] += n
+}
+
+func (m *recordingMetrics) ObserveStage(stage ports.PipelineStage, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stages[stage]++
+}
+
+func TestStandardizerPipelineMetrics(t *testing.T) {
+	metrics := &recordingMetrics{
+		counters: make(map[ports.PipelineCounter]int),
+		stages:   make(map[ports.PipelineStage]int),
+	}
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(&memoryStandardRepo{}),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 1000, Action: domain.ActionReject}),
+		services.WithMetrics(metrics),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1", Type: domain.DeviceType("ELEC")}
+	readings := []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 100},
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: 5000}, // out of range
+		{DeviceInfo: dev, Timestamp: tBase.Add(30 * time.Minute), Value: 110},
+	}
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), readings)
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+
+	want := map[ports.PipelineCounter]int{
+		ports.CounterReadingsIn:  3,
+		ports.CounterCleaned:     2,
+		ports.CounterQuarantined: 1,
+		ports.CounterAligned:     len(result.Readings),
+		ports.CounterPersisted:   len(result.Readings),
+	}
+	for counter, n := range want {
+		if metrics.counters[counter] != n {
+			t.Errorf("counter %s: expected %d, got %d", counter, n, metrics.counters[counter])
+		}
+	}
+	for _, stage := range []ports.PipelineStage{ports.StageClean, ports.StageAlign, ports.StagePersist, ports.StageTotal} {
+		if metrics.stages[stage] != 1 {
+			t.Errorf("stage %s: expected 1 observation, got %d", stage, metrics.stages[stage])
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestPrecisionPerDeviceType(t *testing.T) {
+	standardizer := services.NewCoreStandardizer(
+		services.WithPrecision(100),
+		services.WithDeviceTypePrecision(domain.DeviceType("GAS"), 1000),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	raw := []domain.Reading{
+		{DeviceInfo: domain.DeviceInfo{ID: "E1", Type: domain.DeviceType("ELEC")}, Timestamp: tBase, Value: 12.5},
+		{DeviceInfo: domain.DeviceInfo{ID: "G1", Type: domain.DeviceType("GAS")}, Timestamp: tBase, Value: 12.5},
+	}
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	results := result.Readings
+
+	want := map[string]struct {
+		scaled int64
+		factor int
+	}{
+		"E1": {1250, 100},
+		"G1": {12500, 1000},
+	}
+	for _, r := range results {
+		w := want[r.DeviceID]
+		if r.ValueScaled != w.scaled || r.ScaleFactor != w.factor {
+			t.Errorf("%s: expected %d (x%d), got %d (x%d)", r.DeviceID, w.scaled, w.factor, r.ValueScaled, r.ScaleFactor)
+		}
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+}
@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestSlotConflictResolvedByPriority(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "P1", Type: domain.DeviceTypeElec}
+
+	// Late batch readings take the context priority (50); the manual fix carries its own (1000)
+	ctx := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyBatchLate})
+	readings := []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase.Add(30 * time.Second), Value: 100},                // nearer to 10:00
+		{DeviceInfo: dev, Timestamp: tBase.Add(2 * time.Minute), Value: 101, Priority: 1000}, // manual fix, same slot
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: 110},                // uncontested slot
+	}
+
+	repo := &memoryStandardRepo{}
+	standardizer := services.NewCoreStandardizer(
+		services.WithAlignment(15*time.Minute, 5*time.Minute),
+		services.WithRepository(repo),
+	)
+	result, err := standardizer.ProcessAndStandardize(ctx, readings)
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+
+	if len(result.Readings) != 2 {
+		t.Fatalf("expected 2 standard readings, got %d: %+v", len(result.Readings), result.Readings)
+	}
+	first, second := result.Readings[0], result.Readings[1]
+	if first.ValueDisplay != 101 || first.Priority != 1000 {
+		t.Errorf("10:00 slot: expected manual fix (101, priority 1000) to win, got %v (priority %d)", first.ValueDisplay, first.Priority)
+	}
+	if second.ValueDisplay != 110 || second.Priority != 50 {
+		t.Errorf("10:15 slot: expected late batch reading (110, priority 50), got %v (priority %d)", second.ValueDisplay, second.Priority)
+	}
+
+	if len(result.Conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %+v", result.Conflicts)
+	}
+	c := result.Conflicts[0]
+	if c.DeviceID != "P1" || !c.Timestamp.Equal(tBase) || c.Resolution != "15m" {
+		t.Errorf("unexpected conflict location: %+v", c)
+	}
+	if c.WinnerPriority != 1000 || c.LoserPriority != 50 || c.Overridden != 1 {
+		t.Errorf("unexpected conflict resolution: %+v", c)
+	}
+
+	// The winner is resolved before persistence, not by repository upsert
+	if saved := repo.readings; len(saved) != 2 || saved[0].ValueDisplay != 101 {
+		t.Errorf("expected persisted slot to hold the manual fix, got %+v", saved)
+	}
+}
+
+func TestSameTimestampResolvedByPriorityBeforeDedupe(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "P2", Type: domain.DeviceTypeElec}
+
+	// The realtime reading arrives first; KEEP_FIRST must not let it beat the manual fix
+	readings := []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 100},
+		{DeviceInfo: dev, Timestamp: tBase, Value: 101, Priority: 1000},
+	}
+	quarantine := memory.NewQuarantineRepository()
+	policy := services.DefaultQuarantinePolicy()
+	policy.Mode = services.QuarantineSync
+	standardizer := services.NewCoreStandardizer(
+		services.WithDuplicatePolicy(domain.DuplicateKeepFirst),
+		services.WithQuarantineRepository(quarantine),
+		services.WithQuarantinePolicy(policy),
+	)
+	result, err := standardizer.ProcessAndStandardize(context.Background(), readings)
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	if len(result.Readings) != 1 || result.Readings[0].ValueDisplay != 101 {
+		t.Errorf("expected the higher-priority duplicate to win, got %+v", result.Readings)
+	}
+	if pending, _ := quarantine.FindPending(context.Background(), 0); len(pending) != 1 || pending[0].Code != domain.QuarantineCodeDuplicate || pending[0].Reading.Value != 100 {
+		t.Errorf("expected the outranked reading to be quarantined as a duplicate, got %+v", pending)
+	}
+
+	// The caller's slice keeps its original priorities
+	if readings[0].Priority != 0 {
+		t.Errorf("expected the input readings to be left untouched, got priority %d", readings[0].Priority)
+	}
+}
@@ -0,0 +1,49 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestProvenanceCarriedFromIngestContext(t *testing.T) {
+	repo := memory.NewStandardReadingRepository()
+	quarantine := memory.NewQuarantineRepository()
+	policy := services.DefaultQuarantinePolicy()
+	policy.Mode = services.QuarantineSync
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithQuarantineRepository(quarantine),
+		services.WithQuarantinePolicy(policy),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionReject}),
+	)
+	ctx := domain.NewContext(context.Background(), domain.IngestContext{
+		TraceID: "trace-42", Operator: "alice", BatchID: "upload-7", Strategy: domain.IngestStrategyBatchLate,
+	})
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "P1", Type: "ELEC"}
+	if _, err := standardizer.ProcessAndStandardize(ctx, []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 10},
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: -1},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := domain.Provenance{TraceID: "trace-42", Operator: "alice", BatchID: "upload-7"}
+	got, err := repo.FindExact(context.Background(), "P1", tBase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Provenance == nil || *got.Provenance != want {
+		t.Errorf("expected standard reading provenance %+v, got %+v", want, got.Provenance)
+	}
+	pending, _ := quarantine.FindPending(context.Background(), 0)
+	if len(pending) != 1 || pending[0].Provenance == nil || *pending[0].Provenance != want || pending[0].BatchID != "upload-7" {
+		t.Errorf("expected quarantine record provenance %+v, got %+v", want, pending)
+	}
+}
@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestHMACPseudonymizer(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	authz := ports.AuthorizerFunc(func(_ context.Context, req ports.AuthRequest) error {
+		if req.Operation != ports.OperationDeviceReveal || req.Operator != "dpo" {
+			return ports.ErrForbidden
+		}
+		return nil
+	})
+	p, err := services.NewHMACPseudonymizer(key, memory.NewPseudonymRepository(), authz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	a1, _ := p.Pseudonymize(ctx, "METER-001")
+	a2, _ := p.Pseudonymize(ctx, "METER-001")
+	b, _ := p.Pseudonymize(ctx, "METER-002")
+	acme, _ := p.Pseudonymize(domain.WithTenant(ctx, "acme"), "METER-001")
+	if a1 != a2 || a1 == b || a1 == acme {
+		t.Errorf("expected stable per-device, per-tenant pseudonyms, got %q %q %q %q", a1, a2, b, acme)
+	}
+	if !strings.HasPrefix(a1, services.PseudonymPrefix) || strings.Contains(a1, "METER") {
+		t.Errorf("unexpected pseudonym %q", a1)
+	}
+
+	other, _ := services.NewHMACPseudonymizer([]byte("another-key-0123456789"), memory.NewPseudonymRepository(), authz)
+	if o, _ := other.Pseudonymize(ctx, "METER-001"); o == a1 {
+		t.Error("expected a different key to produce a different pseudonym")
+	}
+
+	if _, err := p.Reveal(ctx, a1, "analyst"); !errors.Is(err, ports.ErrForbidden) {
+		t.Errorf("expected reveal by analyst to be forbidden, got %v", err)
+	}
+	if id, err := p.Reveal(ctx, a1, "dpo"); err != nil || id != "METER-001" {
+		t.Errorf("expected dpo to reveal METER-001, got %q, %v", id, err)
+	}
+	if _, err := p.Reveal(ctx, acme, "dpo"); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected another tenant's pseudonym to be unknown, got %v", err)
+	}
+
+	if _, err := services.NewHMACPseudonymizer([]byte("short"), memory.NewPseudonymRepository(), nil); err == nil {
+		t.Error("expected a short key to be rejected")
+	}
+	unguarded, _ := services.NewHMACPseudonymizer(key, memory.NewPseudonymRepository(), nil)
+	pseudonym, _ := unguarded.Pseudonymize(ctx, "METER-001")
+	if _, err := unguarded.Reveal(ctx, pseudonym, "dpo"); !errors.Is(err, ports.ErrForbidden) {
+		t.Errorf("expected reveal without an authorizer to be forbidden, got %v", err)
+	}
+}
+
+func TestHMACPseudonymizerCachePerTenant(t *testing.T) {
+	p, err := services.NewHMACPseudonymizer([]byte("0123456789abcdef0123456789abcdef"), memory.NewPseudonymRepository(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// 默认租户的设备ID恰好形如其他租户的作用域ID时，缓存不能返回其他租户的假名
+	scoped, _ := p.Pseudonymize(domain.WithTenant(ctx, "acme"), "M1")
+	bare, _ := p.Pseudonymize(ctx, domain.TenantScopedID("acme", "M1"))
+	if scoped == bare {
+		t.Errorf("expected distinct pseudonyms, both are %q", scoped)
+	}
+	if a, b := mustPseudonymize(t, p, "a", "b/c"), mustPseudonymize(t, p, "a/b", "c"); a == b {
+		t.Errorf("expected distinct pseudonyms, both are %q", a)
+	}
+}
+
+func mustPseudonymize(t *testing.T, p *services.HMACPseudonymizer, tenantID, deviceID string) string {
+	t.Helper()
+	pseudonym, err := p.Pseudonymize(domain.WithTenant(context.Background(), tenantID), deviceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pseudonym
+}
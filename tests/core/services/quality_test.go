@@ -0,0 +1,40 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestCorrectedQualityPropagates(t *testing.T) {
+	standardizer := services.NewCoreStandardizer(
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionCorrect}),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	raw := []domain.Reading{
+		{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: 150},
+		{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase.Add(15 * time.Minute), Value: 50},
+	}
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	results := result.Readings
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	corrected, untouched := results[0], results[1]
+	if corrected.Quality != domain.QualityCorrected || corrected.QualityReason == "" {
+		t.Errorf("expected CORRECTED with reason, got %s (%q)", corrected.Quality, corrected.QualityReason)
+	}
+	if untouched.Quality != domain.QualityValid || untouched.QualityReason != "" {
+		t.Errorf("expected VALID without reason, got %s (%q)", untouched.Quality, untouched.QualityReason)
+	}
+}
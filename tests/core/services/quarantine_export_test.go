@@ -0,0 +1,62 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestExportQuarantine(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "D1", Type: domain.DeviceTypeElec}
+	repo := memory.NewQuarantineRepository()
+	_ = repo.Save(ctx, domain.QuarantineReading{ID: "q1", Reading: domain.Reading{DeviceInfo: dev, Timestamp: tBase, Value: -5}, RuleID: "range", Reason: `value "-5", below 0`, CreatedAt: tBase})
+	_ = repo.Save(ctx, domain.QuarantineReading{ID: "q2", Reading: domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(time.Hour), Value: math.NaN()}, Code: domain.QuarantineCodeMissingValue, CreatedAt: tBase.Add(time.Minute)})
+	_ = repo.Save(ctx, domain.QuarantineReading{ID: "q3", Reading: domain.Reading{DeviceInfo: dev, Timestamp: tBase, Value: 1}, Status: domain.QuarantineStatusIgnored, CreatedAt: tBase.Add(2 * time.Minute)})
+	pending := ports.QuarantineFilter{Status: domain.QuarantineStatusPending}
+
+	var buf bytes.Buffer
+	n, err := services.ExportQuarantine(ctx, repo, pending, services.ExportFormatCSV, &buf)
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 records exported, got %d (%v)", n, err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("exported CSV does not parse: %v", err)
+	}
+	if len(rows) != 3 || rows[0][0] != "id" || rows[0][len(rows[0])-1] != "corrected_value" {
+		t.Fatalf("expected a header and 2 rows, got %v", rows)
+	}
+	if got := rows[1]; got[0] != "q1" || got[4] != "2023-01-01T10:00:00Z" || got[5] != "-5" || got[8] != `value "-5", below 0` {
+		t.Errorf("unexpected first row %v", got)
+	}
+	if got := rows[2]; got[5] != "" || got[6] != string(domain.QuarantineCodeMissingValue) {
+		t.Errorf("expected a missing value to export as an empty cell, got %v", got)
+	}
+
+	buf.Reset()
+	if _, err := services.ExportQuarantine(ctx, repo, pending, services.ExportFormatJSON, &buf); err != nil {
+		t.Fatalf("JSON export failed: %v", err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("exported JSON does not parse: %v\n%s", err, buf.String())
+	}
+	if len(records) != 2 || records[0]["id"] != "q1" || records[1]["value"] != nil {
+		t.Errorf("unexpected JSON export %v", records)
+	}
+
+	if _, err := services.ExportQuarantine(ctx, repo, pending, "xlsx", &buf); err == nil {
+		t.Error("expected an unsupported format to be rejected")
+	}
+}
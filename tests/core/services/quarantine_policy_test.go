@@ -0,0 +1,167 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+// memoryQuarantineRepo 内存版隔离区仓储
+type memoryQuarantineRepo struct {
+	mu      sync.Mutex
+	records []domain.QuarantineReading
+	err     error
+}
+
+func (r *memoryQuarantineRepo) Save(ctx context.Context, record domain.QuarantineReading) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	for i := range r.records {
+		if record.ID != "" && r.records[i].ID == record.ID {
+			r.records[i] = record
+			return nil
+		}
+	}
+	r.records = append(r.records, record)
+	return nil
+}
+
+func (r *memoryQuarantineRepo) UpdateStatus(ctx context.Context, record domain.QuarantineReading, expected domain.QuarantineStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.records {
+		if r.records[i].ID == record.ID {
+			if r.records[i].Status != expected {
+				return ports.ErrQuarantineStatusChanged
+			}
+			r.records[i] = record
+			return nil
+		}
+	}
+	return ports.ErrNotFound
+}
+
+func (r *memoryQuarantineRepo) FindPending(ctx context.Context, limit int) ([]domain.QuarantineReading, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.QuarantineReading(nil), r.records...), nil
+}
+
+func (r *memoryQuarantineRepo) FindByDevice(ctx context.Context, deviceID string, start, end time.Time) ([]domain.QuarantineReading, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.QuarantineReading
+	for _, q := range r.records {
+		ts := q.Reading.Timestamp
+		if q.Reading.DeviceInfo.ID == deviceID && !ts.Before(start) && !ts.After(end) {
+			out = append(out, q)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryQuarantineRepo) FindPendingByDeviceType(ctx context.Context, deviceType domain.DeviceType, limit int) ([]domain.QuarantineReading, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.QuarantineReading
+	for _, q := range r.records {
+		if q.Status == domain.QuarantineStatusPending && q.Reading.DeviceInfo.Type == deviceType {
+			out = append(out, q)
+		}
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *memoryQuarantineRepo) Find(ctx context.Context, filter ports.QuarantineFilter) ([]domain.QuarantineReading, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.QuarantineReading
+	for _, q := range r.records {
+		if filter.Match(q) {
+			out = append(out, q)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryQuarantineRepo) Count(ctx context.Context, filter ports.QuarantineFilter, groupBy ports.QuarantineGroupBy) ([]ports.QuarantineCount, error) {
+	found, _ := r.Find(ctx, filter)
+	return ports.CountQuarantine(found, groupBy), nil
+}
+
+func quarantineBatch() []domain.Reading {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1"}
+	return []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: -1},
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: -2},
+		{DeviceInfo: dev, Timestamp: tBase.Add(30 * time.Minute), Value: 10},
+	}
+}
+
+func TestQuarantineAsyncFlush(t *testing.T) {
+	repo := &memoryQuarantineRepo{}
+	standardizer := services.NewCoreStandardizer(
+		services.WithQuarantineRepository(repo),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionReject}),
+	).(*services.CoreStandardizer)
+
+	if _, err := standardizer.ProcessAndStandardize(context.Background(), quarantineBatch()); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if err := standardizer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	pending, _ := repo.FindPending(context.Background(), 0)
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 quarantine records after Close, got %d", len(pending))
+	}
+}
+
+func TestQuarantineSyncReturnsError(t *testing.T) {
+	repo := &memoryQuarantineRepo{err: errors.New("db down")}
+	standardizer := services.NewCoreStandardizer(
+		services.WithQuarantineRepository(repo),
+		services.WithQuarantinePolicy(services.QuarantinePolicy{Mode: services.QuarantineSync}),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionReject}),
+	)
+
+	if _, err := standardizer.ProcessAndStandardize(context.Background(), quarantineBatch()); err == nil {
+		t.Fatal("expected sync quarantine failure to fail the batch")
+	}
+}
+
+func TestQuarantineCallback(t *testing.T) {
+	var got []domain.QuarantineReading
+	standardizer := services.NewCoreStandardizer(
+		services.WithQuarantinePolicy(services.QuarantinePolicy{
+			Mode: services.QuarantineCallback,
+			Callback: func(ctx context.Context, records []domain.QuarantineReading) error {
+				got = append(got, records...)
+				return nil
+			},
+		}),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionReject}),
+	)
+
+	if _, err := standardizer.ProcessAndStandardize(context.Background(), quarantineBatch()); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected callback to receive 2 records, got %d", len(got))
+	}
+}
@@ -0,0 +1,225 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestReevaluateQuarantineResolvesPassingRecords(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1", Type: domain.DeviceType("ELEC")}
+	pending := func(id string, offset time.Duration, value float64) domain.QuarantineReading {
+		return domain.QuarantineReading{
+			ID:      id,
+			Reading: domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(offset), Value: value},
+			Reason:  "value out of range",
+			Status:  domain.QuarantineStatusPending,
+		}
+	}
+	quarantine := &memoryQuarantineRepo{records: []domain.QuarantineReading{
+		pending("q1", 0, 150),
+		pending("q2", 15*time.Minute, 180),
+		pending("q3", 30*time.Minute, 900),
+	}}
+	repo := &memoryStandardRepo{}
+
+	// 阈值从 100 放宽到 200 之后
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithQuarantineRepository(quarantine),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 200, Action: domain.ActionReject}),
+	).(*services.CoreStandardizer)
+
+	review, err := standardizer.ReevaluateQuarantine(context.Background(), dev.Type, 0)
+	if err != nil {
+		t.Fatalf("ReevaluateQuarantine failed: %v", err)
+	}
+	if len(review.Resolved) != 2 || review.StillPending != 1 {
+		t.Fatalf("expected 2 resolved and 1 pending, got %d resolved, %d pending", len(review.Resolved), review.StillPending)
+	}
+	if len(repo.readings) != 2 {
+		t.Errorf("expected 2 re-ingested standard readings, got %d", len(repo.readings))
+	}
+
+	left, _ := quarantine.FindPendingByDeviceType(context.Background(), dev.Type, 0)
+	if len(left) != 1 || left[0].ID != "q3" {
+		t.Errorf("expected only q3 to remain pending, got %+v", left)
+	}
+	if len(quarantine.records) != 3 {
+		t.Errorf("re-evaluation must not create new quarantine records, got %d", len(quarantine.records))
+	}
+}
+
+func TestResolveAndIgnoreQuarantine(t *testing.T) {
+	ctx := context.Background()
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1", Type: domain.DeviceType("ELEC")}
+	quarantine := memory.NewQuarantineRepository()
+	_ = quarantine.Save(ctx, domain.QuarantineReading{ID: "q1", Reading: domain.Reading{DeviceInfo: dev, Timestamp: tBase, Value: -5}, Reason: "negative"})
+	_ = quarantine.Save(ctx, domain.QuarantineReading{ID: "q2", Reading: domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: -7}, Reason: "negative"})
+	repo := memory.NewStandardReadingRepository()
+
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithQuarantineRepository(quarantine),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 200, Action: domain.ActionReject}),
+	).(*services.CoreStandardizer)
+
+	result, err := standardizer.Resolve(ctx, "q1", 12.5, "alice")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if len(result.Readings) != 1 || result.Readings[0].Priority != domain.IngestStrategyCalibration.GetPriority() {
+		t.Fatalf("expected one calibration-priority standard reading, got %+v", result.Readings)
+	}
+	if saved, _ := repo.FindExact(ctx, dev.ID, tBase); saved == nil || saved.ValueDisplay != 12.5 {
+		t.Errorf("expected the corrected value to be persisted, got %+v", saved)
+	}
+	resolved, _ := quarantine.Find(ctx, ports.QuarantineFilter{ID: "q1"})
+	if len(resolved) != 1 || resolved[0].Status != domain.QuarantineStatusResolved || resolved[0].Operator != "alice" || *resolved[0].CorrectedValue != 12.5 {
+		t.Errorf("expected q1 to be resolved by alice, got %+v", resolved)
+	}
+	if _, err := standardizer.Resolve(ctx, "q1", 13, "alice"); !errors.Is(err, services.ErrQuarantineNotPending) {
+		t.Errorf("expected a second resolution to be rejected, got %v", err)
+	}
+
+	opCtx := domain.NewContext(ctx, domain.IngestContext{Operator: "bob"})
+	if err := standardizer.Ignore(opCtx, "q2", "sensor glitch"); err != nil {
+		t.Fatalf("Ignore failed: %v", err)
+	}
+	ignored, _ := quarantine.Find(ctx, ports.QuarantineFilter{Status: domain.QuarantineStatusIgnored})
+	if len(ignored) != 1 || ignored[0].ID != "q2" || ignored[0].Note != "sensor glitch" || ignored[0].Operator != "bob" {
+		t.Errorf("expected q2 to be ignored by bob, got %+v", ignored)
+	}
+	if err := standardizer.Ignore(ctx, "missing", ""); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// barrierQuarantineRepo 按 ID 查询时等待所有调用方都读到记录后才返回，使并发处理必然读到同一个 PENDING 状态
+type barrierQuarantineRepo struct {
+	*memory.QuarantineRepository
+	loaded *sync.WaitGroup
+}
+
+func (r *barrierQuarantineRepo) Find(ctx context.Context, filter ports.QuarantineFilter) ([]domain.QuarantineReading, error) {
+	found, err := r.QuarantineRepository.Find(ctx, filter)
+	if filter.ID != "" {
+		r.loaded.Done()
+		r.loaded.Wait()
+	}
+	return found, err
+}
+
+func TestConcurrentQuarantineHandlingAppliesOnce(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	const n = 8
+	var loaded sync.WaitGroup
+	loaded.Add(n + 1)
+	repo := memory.NewStandardReadingRepository()
+	quarantine := &barrierQuarantineRepo{QuarantineRepository: memory.NewQuarantineRepository(), loaded: &loaded}
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithQuarantineRepository(quarantine),
+	).(*services.CoreStandardizer)
+	if err := quarantine.Save(ctx, domain.QuarantineReading{Reading: domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: -5}}); err != nil {
+		t.Fatal(err)
+	}
+	pending, _ := quarantine.FindPending(ctx, 0)
+	id := pending[0].ID
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, n+1)
+	for i := range n {
+		wg.Go(func() {
+			<-start
+			_, errs[i] = standardizer.Resolve(ctx, id, float64(10+i), fmt.Sprintf("op-%d", i))
+		})
+	}
+	wg.Go(func() {
+		<-start
+		errs[n] = standardizer.Ignore(ctx, id, "meter swapped")
+	})
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, services.ErrQuarantineNotPending):
+			t.Errorf("expected ErrQuarantineNotPending for the losers, got %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one handler to win, got %d", succeeded)
+	}
+
+	// 记录状态与写入的数据一致: 忽略时未写入，修正时写入的正是记录的修正值
+	record, _ := quarantine.QuarantineRepository.Find(ctx, ports.QuarantineFilter{ID: id})
+	got, err := repo.FindExact(ctx, "D1", tBase)
+	switch record[0].Status {
+	case domain.QuarantineStatusIgnored:
+		if !errors.Is(err, ports.ErrNotFound) {
+			t.Errorf("expected nothing written for an ignored record, got %+v (%v)", got, err)
+		}
+	case domain.QuarantineStatusResolved:
+		if err != nil || got.ValueDisplay != *record[0].CorrectedValue {
+			t.Errorf("expected the recorded correction %v to be written, got %+v (%v)", *record[0].CorrectedValue, got, err)
+		}
+	default:
+		t.Errorf("unexpected final status %s", record[0].Status)
+	}
+}
+
+// failingStandardRepo 前 fail 次 SaveBatch 失败
+type failingStandardRepo struct {
+	ports.StandardReadingRepository
+	fail int
+}
+
+func (r *failingStandardRepo) SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, idempotencyKey string) error {
+	if r.fail > 0 {
+		r.fail--
+		return errors.New("database unavailable")
+	}
+	return r.StandardReadingRepository.SaveBatch(ctx, readings, strategy, idempotencyKey)
+}
+
+func TestResolveRestoresPendingOnFailure(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &failingStandardRepo{StandardReadingRepository: memory.NewStandardReadingRepository(), fail: 1}
+	quarantine := memory.NewQuarantineRepository()
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithQuarantineRepository(quarantine),
+	).(*services.CoreStandardizer)
+	if err := quarantine.Save(ctx, domain.QuarantineReading{Reading: domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: -5}}); err != nil {
+		t.Fatal(err)
+	}
+	pending, _ := quarantine.FindPending(ctx, 0)
+
+	if _, err := standardizer.Resolve(ctx, pending[0].ID, 7, "alice"); err == nil {
+		t.Fatal("expected the first resolve to fail")
+	}
+	if left, _ := quarantine.FindPending(ctx, 0); len(left) != 1 || left[0].CorrectedValue != nil {
+		t.Fatalf("expected the record to be pending again, got %+v", left)
+	}
+	if _, err := standardizer.Resolve(ctx, pending[0].ID, 7, "alice"); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+}
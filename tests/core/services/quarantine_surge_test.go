@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []ports.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification ports.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestQuarantineSurgeMonitorNotifiesDeadSensor(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	notifier := &recordingNotifier{}
+	monitor, err := services.NewQuarantineSurgeMonitor(notifier,
+		services.WithSurgeThreshold(0.6, 5),
+		services.WithSurgeClock(func() time.Time { return now }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := services.NewEventBus()
+	monitor.Subscribe(bus)
+
+	standardizer := services.NewCoreStandardizer(
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 1000, Action: domain.ActionReject}),
+		services.WithEventPublisher(bus),
+	)
+	batch := func(start time.Time) []domain.Reading {
+		var out []domain.Reading
+		for i := range 6 {
+			ts := start.Add(time.Duration(i) * 15 * time.Minute)
+			out = append(out,
+				domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "DEAD"}, Timestamp: ts, Value: -1},
+				domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "OK"}, Timestamp: ts, Value: float64(10 * i)},
+			)
+		}
+		return out
+	}
+
+	if _, err := standardizer.ProcessAndStandardize(ctx, batch(now)); err != nil {
+		t.Fatal(err)
+	}
+	// DEAD 的隔离率 100%；规则的隔离率为全部输入的 50%，低于阈值
+	if len(notifier.sent) != 1 {
+		t.Fatalf("expected 1 notification, got %+v", notifier.sent)
+	}
+	n := notifier.sent[0]
+	if n.Kind != services.NotificationQuarantineSurge || n.Labels["device_id"] != "DEAD" || n.Labels["quarantined"] != "6" || n.Labels["inputs"] != "6" {
+		t.Errorf("unexpected notification: %+v", n)
+	}
+
+	// 冷却期内不重复通知
+	if _, err := standardizer.ProcessAndStandardize(ctx, batch(now.Add(2*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 1 {
+		t.Fatalf("expected no notification during cooldown, got %+v", notifier.sent)
+	}
+
+	// 冷却期过后再次通知 (窗口内只统计新批次)
+	now = now.Add(2 * time.Hour)
+	if _, err := standardizer.ProcessAndStandardize(ctx, batch(now.Add(4*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 2 || notifier.sent[1].Labels["quarantined"] != "6" {
+		t.Fatalf("expected a fresh notification after cooldown, got %+v", notifier.sent)
+	}
+}
@@ -0,0 +1,44 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestFindRangeMulti(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	readings := []domain.StandardReading{
+		{DeviceID: "D1", Timestamp: tBase.Add(time.Hour), Resolution: "1h"},
+		{DeviceID: "D1", Timestamp: tBase, Resolution: "1h"},
+		{DeviceID: "D2", Timestamp: tBase, Resolution: "1h"},
+		{DeviceID: "D3", Timestamp: tBase, Resolution: "1h"},
+	}
+
+	bulk := memory.NewStandardReadingRepository()
+	_ = bulk.SaveBatch(ctx, readings, ports.UpsertStrategyLastWriteWins, "")
+	// The history-seed fake does not implement the bulk port and exercises the per-device fallback
+	fallback := &memoryStandardRepo{readings: readings}
+
+	for name, repo := range map[string]ports.StandardReadingRepository{"bulk": bulk, "fallback": fallback} {
+		got, err := services.FindRangeMulti(ctx, repo, []string{"D1", "D2", "D1", "D9"}, tBase, tBase.Add(2*time.Hour))
+		if err != nil {
+			t.Fatalf("%s: FindRangeMulti failed: %v", name, err)
+		}
+		if len(got) != 2 || len(got["D1"]) != 2 || len(got["D2"]) != 1 {
+			t.Errorf("%s: expected D1 x2 and D2 x1, got %v", name, got)
+		}
+		if _, ok := got["D9"]; ok {
+			t.Errorf("%s: devices without data must be omitted", name)
+		}
+	}
+	if got, _ := bulk.FindRangeMulti(ctx, []string{"D1"}, tBase, tBase.Add(2*time.Hour)); !got["D1"][0].Timestamp.Equal(tBase) {
+		t.Errorf("expected readings ordered by time, got %v", got["D1"])
+	}
+}
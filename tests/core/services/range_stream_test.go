@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestStreamRangePages(t *testing.T) {
+	ctx := context.Background()
+	tBase := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+
+	// 5 timestamps x 2 resolutions: pages must split ties on the same timestamp correctly
+	var batch []domain.StandardReading
+	for i := range 5 {
+		ts := tBase.Add(time.Duration(i) * time.Hour)
+		batch = append(batch,
+			domain.StandardReading{DeviceID: "D1", Timestamp: ts, Resolution: "15m"},
+			domain.StandardReading{DeviceID: "D1", Timestamp: ts, Resolution: "1h"},
+		)
+	}
+	_ = repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, "")
+
+	var pages [][]domain.StandardReading
+	err := services.StreamRange(ctx, repo, "D1", tBase, tBase.Add(24*time.Hour), 3, func(page []domain.StandardReading) error {
+		pages = append(pages, page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamRange failed: %v", err)
+	}
+	if len(pages) != 4 || len(pages[3]) != 1 {
+		t.Fatalf("expected pages of 3/3/3/1, got %d pages", len(pages))
+	}
+
+	seen := make(map[string]bool)
+	var prev *domain.StandardReading
+	for _, page := range pages {
+		for i := range page {
+			sr := page[i]
+			key := sr.Timestamp.String() + sr.Resolution
+			if seen[key] {
+				t.Errorf("duplicate reading across pages: %s", key)
+			}
+			seen[key] = true
+			if prev != nil && sr.Timestamp.Before(prev.Timestamp) {
+				t.Errorf("pages out of order: %s after %s", sr.Timestamp, prev.Timestamp)
+			}
+			prev = &sr
+		}
+	}
+	if len(seen) != 10 {
+		t.Errorf("expected 10 distinct readings, got %d", len(seen))
+	}
+
+	if _, err := repo.FindRangePaged(ctx, "D1", tBase, tBase.Add(time.Hour), ports.PageRequest{Cursor: "%%%"}); !errors.Is(err, ports.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
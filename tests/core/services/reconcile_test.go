@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestReconcileReportsLostStandardReadings(t *testing.T) {
+	ctx := context.Background()
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	dev := domain.DeviceInfo{ID: "R1", Type: "ELEC"}
+	at := func(t time.Time, minutes int) time.Time { return t.Add(time.Duration(minutes) * time.Minute) }
+
+	raw := memory.NewRawReadingRepository()
+	_ = raw.Save(ctx, []domain.Reading{
+		{DeviceInfo: dev, Timestamp: at(day1, 0), Value: 1},
+		{DeviceInfo: dev, Timestamp: at(day1, 5), Value: 1},
+		{DeviceInfo: dev, Timestamp: at(day1, 10), Value: 1},
+		{DeviceInfo: dev, Timestamp: at(day1, 15), Value: 2},
+		{DeviceInfo: dev, Timestamp: at(day2, 0), Value: 5},
+		{DeviceInfo: dev, Timestamp: at(day2, 15), Value: 5},
+	})
+	standard := memory.NewStandardReadingRepository()
+	std := func(ts time.Time, v float64) domain.StandardReading {
+		return domain.StandardReading{DeviceID: dev.ID, Timestamp: ts, Resolution: "15m", ValueScaled: int64(v * 100), ScaleFactor: 100, ValueDisplay: v, Quality: domain.QualityValid}
+	}
+	// day2 的 00:15 槽位在管道中丢失
+	_ = standard.SaveBatch(ctx, []domain.StandardReading{std(at(day1, 0), 3), std(at(day1, 15), 2), std(at(day2, 0), 5)}, ports.UpsertStrategyLastWriteWins, "")
+
+	got, err := services.Reconcile(ctx, raw, standard, services.ReconcileQuery{
+		DeviceIDs: []string{dev.ID}, Resolution: "15m", Start: day1, End: day2.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected only day2 to be reported, got %+v", got)
+	}
+	d := got[0]
+	if !d.Day.Equal(day2) || d.RawCount != 2 || d.RawSlots != 2 || d.StandardCount != 1 || d.RawUsage != 10 || d.StandardUsage != 5 || d.Deviation != 0.5 {
+		t.Errorf("unexpected discrepancy %+v", d)
+	}
+	if len(d.Issues) != 2 {
+		t.Errorf("expected missing-count and usage issues, got %v", d.Issues)
+	}
+
+	if _, err := services.Reconcile(ctx, raw, standard, services.ReconcileQuery{DeviceIDs: []string{dev.ID}, Resolution: "15m", Start: day2, End: day1}); err == nil {
+		t.Error("expected an invalid range to be rejected")
+	}
+}
+
+func TestReconcileAppliesCalibration(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// CT 变比 40: 标准读数保存的是一次值，原始归档保存的是寄存器值
+	dev := domain.DeviceInfo{ID: "CT1", Type: "ELEC", Calibration: &domain.Calibration{CTRatio: 40}}
+
+	raw := memory.NewRawReadingRepository()
+	standard := memory.NewStandardReadingRepository()
+	standardizer := services.NewCoreStandardizer(services.WithRepository(standard), services.WithRawRepository(raw))
+	if _, err := standardizer.ProcessAndStandardize(ctx, []domain.Reading{
+		{DeviceInfo: dev, Timestamp: day, Value: 2.5},
+		{DeviceInfo: dev, Timestamp: day.Add(15 * time.Minute), Value: 1.5},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := services.Reconcile(ctx, raw, standard, services.ReconcileQuery{
+		DeviceIDs: []string{dev.ID}, Resolution: "15m", Start: day, End: day.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected calibrated raw usage to match the standard readings, got %+v", got)
+	}
+}
@@ -0,0 +1,244 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestReportGeneratorSumsIntervalUsage(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for i := range 8 { // 15m interval usage of 0.1 kWh over 00:00 - 02:00
+		batch = append(batch, domain.StandardReading{DeviceID: "D1", Timestamp: day.Add(time.Duration(i) * 15 * time.Minute),
+			Resolution: "15m", ValueScaled: 1000, ScaleFactor: 10000, ValueDisplay: 0.1, Quality: domain.QualityValid})
+	}
+	batch[1].ValueScaled, batch[1].ScaleFactor = 1, 10 // coarser precision is rescaled, not truncated
+	batch[2].Quality = domain.QualityMissing
+	batch = append(batch, domain.StandardReading{DeviceID: "D1", Timestamp: day, Resolution: "1h", ValueScaled: 99, ScaleFactor: 1})
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	gen := services.NewReportGenerator(repo)
+	dev := domain.DeviceInfo{ID: "D1", Model: "DTSU666", Type: domain.DeviceTypeElec}
+	hourly, err := gen.Generate(ctx, ports.ReportQuery{Device: dev, Resolution: "15m", Period: domain.ReportPeriodHour,
+		Start: day.Add(10 * time.Minute), End: day.Add(3 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(hourly) != 2 {
+		t.Fatalf("expected 2 hourly reports (the empty third hour is skipped), got %d", len(hourly))
+	}
+	first := hourly[0]
+	if first.UsageScaled != 3000 || first.ScaleFactor != 10000 || first.TotalUsage != 0.3 {
+		t.Errorf("expected 0.1 + 0.1 + 0.1 with the missing slot skipped, got %+v", first)
+	}
+	if !first.StartTime.Equal(day) || !first.EndTime.Equal(day.Add(time.Hour)) || first.DeviceModel != "DTSU666" || first.DeviceType != domain.DeviceTypeElec {
+		t.Errorf("unexpected report metadata: %+v", first)
+	}
+	if hourly[1].UsageScaled != 4000 {
+		t.Errorf("expected 0.4 in the second hour, got %+v", hourly[1])
+	}
+
+	daily, err := gen.Generate(ctx, ports.ReportQuery{Device: dev, Resolution: "15m", Period: domain.ReportPeriodDay, Start: day, End: day.Add(time.Hour)})
+	if err != nil || len(daily) != 1 || daily[0].UsageScaled != 7000 || !daily[0].EndTime.Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("expected one daily report of 0.7, got %+v (%v)", daily, err)
+	}
+}
+
+func TestReportGeneratorCumulativeReadings(t *testing.T) {
+	ctx := context.Background()
+	jan := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for i, v := range []int64{1000, 1310, 1590} { // meter register at the start of Jan, Feb and Mar
+		batch = append(batch, domain.StandardReading{DeviceID: "D1", Metric: domain.MetricEnergy, Timestamp: jan.AddDate(0, i, 0),
+			Resolution: "1d", ValueScaled: v, ScaleFactor: 1, Quality: domain.QualityValid})
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	monthly, err := services.NewReportGenerator(repo).Generate(ctx, ports.ReportQuery{Device: domain.DeviceInfo{ID: "D1"},
+		Metric: domain.MetricEnergy, Resolution: "1d", Period: domain.ReportPeriodMonth, Cumulative: true,
+		Start: jan, End: jan.AddDate(0, 3, 0)})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(monthly) != 2 || monthly[0].UsageScaled != 310 || monthly[1].UsageScaled != 280 {
+		t.Fatalf("expected Jan 310 and Feb 280 from register differences (March lacks an end reading), got %+v", monthly)
+	}
+	if !monthly[1].EndTime.Equal(jan.AddDate(0, 2, 0)) {
+		t.Errorf("expected February to end on March 1st, got %s", monthly[1].EndTime)
+	}
+}
+
+func TestReportGeneratorRejectsOverflow(t *testing.T) {
+	ctx := context.Background()
+	ts := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	_ = repo.SaveBatch(ctx, []domain.StandardReading{
+		{DeviceID: "D1", Timestamp: ts, Resolution: "15m", ValueScaled: 1 << 62, ScaleFactor: 10000},
+		{DeviceID: "D1", Timestamp: ts.Add(15 * time.Minute), Resolution: "15m", ValueScaled: 1 << 62, ScaleFactor: 10000},
+	}, ports.UpsertStrategyLastWriteWins, "")
+
+	_, err := services.NewReportGenerator(repo).Generate(ctx, ports.ReportQuery{Device: domain.DeviceInfo{ID: "D1"},
+		Resolution: "15m", Period: domain.ReportPeriodHour, Start: ts, End: ts.Add(time.Hour)})
+	if !errors.Is(err, domain.ErrScaleOverflow) {
+		t.Errorf("expected ErrScaleOverflow, got %v", err)
+	}
+	if _, err := services.NewReportGenerator(repo).Generate(ctx, ports.ReportQuery{Device: domain.DeviceInfo{ID: "D1"}, Period: domain.ReportPeriodHour,
+		Start: ts, End: ts.Add(time.Hour)}); err == nil {
+		t.Error("expected an error without a resolution")
+	}
+}
+
+func TestReportGeneratorLocalCalendar(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+	ctx := context.Background()
+	start := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for i := range 24 { // 1 unit per UTC hour on 2023-06-01
+		batch = append(batch, domain.StandardReading{DeviceID: "D1", Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Resolution: "1h", ValueScaled: 1, ScaleFactor: 1, Quality: domain.QualityValid})
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	gen := services.NewReportGenerator(repo)
+	q := ports.ReportQuery{Device: domain.DeviceInfo{ID: "D1", Timezone: "Asia/Shanghai"}, Resolution: "1h",
+		Period: domain.ReportPeriodDay, Start: start, End: start.Add(24 * time.Hour)}
+	local, err := gen.Generate(ctx, q)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	// UTC 00:00-16:00 falls on June 1st in Shanghai, the remaining 8 hours on June 2nd
+	if len(local) != 2 || local[0].UsageScaled != 16 || local[1].UsageScaled != 8 {
+		t.Fatalf("expected the device timezone to split days at 16:00 UTC, got %+v", local)
+	}
+	if !local[0].StartTime.Equal(time.Date(2023, 6, 1, 0, 0, 0, 0, shanghai)) {
+		t.Errorf("expected the first day to start at local midnight, got %s", local[0].StartTime)
+	}
+
+	q.Location = time.UTC // an explicit location overrides the device timezone
+	utc, err := gen.Generate(ctx, q)
+	if err != nil || len(utc) != 1 || utc[0].UsageScaled != 24 {
+		t.Errorf("expected a single UTC day of 24, got %+v (%v)", utc, err)
+	}
+
+	q.Location, q.Device.Timezone = nil, "Mars/Olympus"
+	if _, err := gen.Generate(ctx, q); err == nil {
+		t.Error("expected an error for an unknown device timezone")
+	}
+}
+
+func TestReportGeneratorCarbonEmission(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for i := range 4 { // 1.5 kWh per hour, 00:00 - 04:00
+		batch = append(batch, domain.StandardReading{DeviceID: "E1", Timestamp: day.Add(time.Duration(i) * time.Hour),
+			Resolution: "1h", ValueScaled: 15000, ScaleFactor: 10000, Quality: domain.QualityValid})
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	factors := services.NewEmissionFactorTable(
+		domain.EmissionFactor{KgCO2ePerUnit: 1},                                                                         // fallback for any carrier
+		domain.EmissionFactor{DeviceType: domain.DeviceTypeElec, KgCO2ePerUnit: 0.5703},                                 // annual grid factor
+		domain.EmissionFactor{DeviceType: domain.DeviceTypeElec, KgCO2ePerUnit: 0.3, ValidFrom: day.Add(2 * time.Hour)}, // cleaner grid from 02:00
+	)
+	gen := services.NewReportGenerator(repo, services.WithEmissionFactors(factors))
+	q := ports.ReportQuery{Device: domain.DeviceInfo{ID: "E1", Type: domain.DeviceTypeElec}, Resolution: "1h",
+		Period: domain.ReportPeriodDay, Start: day, End: day.Add(time.Hour)}
+	reports, err := gen.Generate(ctx, q)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	// 2 x 1.5 x 0.5703 + 2 x 1.5 x 0.3 = 2.6109
+	if len(reports) != 1 || reports[0].Carbon == nil || reports[0].Carbon.CO2eScaled != 26109 || reports[0].Carbon.CO2eKg != 2.6109 {
+		t.Fatalf("expected time-varying factors to yield 2.6109 kg CO2e, got %+v", reports)
+	}
+
+	q.Device.Type = domain.DeviceTypeGas
+	gas, _ := gen.Generate(ctx, q)
+	if gas[0].Carbon == nil || gas[0].Carbon.CO2eScaled != 60000 {
+		t.Errorf("expected the catch-all factor for gas, got %+v", gas[0].Carbon)
+	}
+
+	partial := services.NewReportGenerator(repo, services.WithEmissionFactors(services.NewEmissionFactorTable(
+		domain.EmissionFactor{KgCO2ePerUnit: 0.5, ValidFrom: day.Add(time.Hour)},
+	)))
+	if r, _ := partial.Generate(ctx, q); r[0].Carbon != nil {
+		t.Errorf("expected no carbon total when a factor is missing for part of the period, got %+v", r[0].Carbon)
+	}
+	if r, _ := services.NewReportGenerator(repo).Generate(ctx, q); r[0].Carbon != nil || r[0].UsageScaled != 60000 {
+		t.Errorf("expected usage only without emission factors, got %+v", r[0])
+	}
+}
+
+// monthlyWeather serves degree days per calendar month
+type monthlyWeather struct {
+	actual, normal map[time.Month]domain.DegreeDays
+}
+
+func (w monthlyWeather) DegreeDays(ctx context.Context, device domain.DeviceInfo, start, end time.Time) (domain.DegreeDays, error) {
+	return w.actual[start.Month()], nil
+}
+
+func (w monthlyWeather) NormalDegreeDays(ctx context.Context, device domain.DeviceInfo, start, end time.Time) (domain.DegreeDays, error) {
+	return w.normal[start.Month()], nil
+}
+
+func TestReportGeneratorWeatherNormalization(t *testing.T) {
+	ctx := context.Background()
+	jan := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	batch := []domain.StandardReading{
+		{DeviceID: "G1", Timestamp: jan, Resolution: "1d", ValueScaled: 3000, ScaleFactor: 10, Quality: domain.QualityValid},
+		{DeviceID: "G1", Timestamp: jan.AddDate(0, 1, 0), Resolution: "1d", ValueScaled: 2000, ScaleFactor: 10, Quality: domain.QualityValid},
+		{DeviceID: "G1", Timestamp: jan.AddDate(0, 6, 0), Resolution: "1d", ValueScaled: 500, ScaleFactor: 10, Quality: domain.QualityValid},
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	weather := monthlyWeather{
+		actual: map[time.Month]domain.DegreeDays{time.January: {Heating: 600}, time.February: {Heating: 300, Cooling: 5}, time.July: {Cooling: 80}},
+		normal: map[time.Month]domain.DegreeDays{time.January: {Heating: 500}, time.February: {Heating: 400}, time.July: {Cooling: 60}},
+	}
+	gen := services.NewReportGenerator(repo, services.WithWeatherNormalization(weather, domain.DegreeDayHeating))
+	reports, err := gen.Generate(ctx, ports.ReportQuery{Device: domain.DeviceInfo{ID: "G1"}, Resolution: "1d",
+		Period: domain.ReportPeriodMonth, Start: jan, End: jan.AddDate(1, 0, 0)})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(reports) != 3 {
+		t.Fatalf("expected 3 monthly reports, got %d", len(reports))
+	}
+	// January was colder than normal, February milder: 300 x 500/600 = 250, 200 x 400/300 = 266.7
+	if w := reports[0].Weather; w == nil || w.NormalizedScaled != 2500 || w.UsagePerDegreeDay != 0.5 || w.Basis != domain.DegreeDayHeating {
+		t.Errorf("unexpected January normalization: %+v", w)
+	}
+	if w := reports[1].Weather; w == nil || w.NormalizedScaled != 2667 || w.NormalizedUsage != 266.7 {
+		t.Errorf("expected cooling degree days to be ignored for a heating basis, got %+v", w)
+	}
+	if w := reports[2].Weather; w == nil || w.NormalizedScaled != 500 || w.UsagePerDegreeDay != 0 {
+		t.Errorf("expected a month without heating degree days to keep its usage, got %+v", w)
+	}
+}
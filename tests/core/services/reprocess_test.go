@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+// memoryRawRepo 内存版原始读数仓储
+type memoryRawRepo struct {
+	readings []domain.Reading
+}
+
+func (r *memoryRawRepo) Save(ctx context.Context, readings []domain.Reading) error {
+	r.readings = append(r.readings, readings...)
+	return nil
+}
+
+func (r *memoryRawRepo) FindRange(ctx context.Context, deviceID string, start, end time.Time) ([]domain.Reading, error) {
+	var out []domain.Reading
+	for _, rd := range r.readings {
+		if rd.DeviceInfo.ID == deviceID && !rd.Timestamp.Before(start) && !rd.Timestamp.After(end) {
+			out = append(out, rd)
+		}
+	}
+	return out, nil
+}
+
+func TestReprocessRange(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1"}
+
+	raw := &memoryRawRepo{readings: []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 100},
+		{DeviceInfo: dev, Timestamp: tBase.Add(30 * time.Minute), Value: 130},
+		{DeviceInfo: dev, Timestamp: tBase.Add(2 * time.Hour), Value: 200}, // 区间外
+	}}
+	quarantine := &memoryQuarantineRepo{records: []domain.QuarantineReading{
+		{Reading: domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: 115}, Reason: "too strict"},
+	}}
+	repo := &memoryStandardRepo{}
+
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithRawRepository(raw),
+		services.WithQuarantineRepository(quarantine),
+	).(*services.CoreStandardizer)
+
+	result, err := standardizer.ReprocessRange(context.Background(), "D1", tBase, tBase.Add(time.Hour), services.ReprocessOptions{
+		Strategy:           ports.UpsertStrategyLastWriteWins,
+		IncludeQuarantined: true,
+	})
+	if err != nil {
+		t.Fatalf("ReprocessRange failed: %v", err)
+	}
+
+	if len(result.Readings) != 3 {
+		t.Fatalf("expected 3 standard readings (raw + recovered quarantine), got %d", len(result.Readings))
+	}
+	if result.Readings[1].ValueDisplay != 115 {
+		t.Errorf("expected quarantined reading to be re-evaluated at 10:15, got %v", result.Readings[1].ValueDisplay)
+	}
+	if len(repo.readings) != 3 {
+		t.Errorf("expected 3 readings re-persisted, got %d", len(repo.readings))
+	}
+}
+
+func TestReprocessRangeRequiresRawRepository(t *testing.T) {
+	standardizer := services.NewCoreStandardizer().(*services.CoreStandardizer)
+	_, err := standardizer.ReprocessRange(context.Background(), "D1", time.Now().Add(-time.Hour), time.Now(), services.ReprocessOptions{})
+	if !errors.Is(err, services.ErrRawRepositoryNotConfigured) {
+		t.Fatalf("expected ErrRawRepositoryNotConfigured, got %v", err)
+	}
+}
+
+func TestProcessArchivesRawReadingsBeforeCleaning(t *testing.T) {
+	ctx := context.Background()
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1"}
+	raw := memory.NewRawReadingRepository()
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(&memoryStandardRepo{}),
+		services.WithRawRepository(raw),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 1000, Action: domain.ActionReject}),
+	).(*services.CoreStandardizer)
+
+	batch := []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 100},
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: 5000}, // rejected by the range rule
+		{DeviceInfo: dev, Timestamp: tBase.Add(30 * time.Minute), Value: 130},
+	}
+	if _, err := standardizer.ProcessAndStandardize(ctx, batch); err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	archived, _ := raw.FindRange(ctx, "D1", tBase, tBase.Add(time.Hour))
+	if len(archived) != 3 || archived[1].Value != 5000 {
+		t.Fatalf("expected every raw reading to be archived as received, got %+v", archived)
+	}
+
+	// Reprocessing reads the archive without archiving it again
+	result, err := standardizer.ReprocessRange(ctx, "D1", tBase, tBase.Add(time.Hour), services.ReprocessOptions{Strategy: ports.UpsertStrategyLastWriteWins})
+	if err != nil {
+		t.Fatalf("ReprocessRange failed: %v", err)
+	}
+	if len(result.Readings) == 0 {
+		t.Error("expected reprocessing to rebuild standard readings from the archive")
+	}
+}
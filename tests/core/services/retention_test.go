@@ -0,0 +1,64 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestRetentionRunner(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+
+	// One 15m and one 1h reading per day over the last 10 days
+	var batch []domain.StandardReading
+	for day := range 10 {
+		ts := now.Add(-time.Duration(day+1) * 24 * time.Hour)
+		batch = append(batch,
+			domain.StandardReading{DeviceID: "D1", Timestamp: ts, Resolution: "15m"},
+			domain.StandardReading{DeviceID: "D1", Timestamp: ts, Resolution: "1h"},
+		)
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	runner, err := services.NewRetentionRunner(repo,
+		services.RetentionRule{Resolution: "15m", MaxAge: 3 * 24 * time.Hour},
+		services.RetentionRule{Resolution: "1h", MaxAge: 7 * 24 * time.Hour},
+	)
+	if err != nil {
+		t.Fatalf("NewRetentionRunner failed: %v", err)
+	}
+	result, err := runner.RunOnce(ctx, now)
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	// Days 4..10 are older than 3 days (7 rows); days 8..10 older than 7 days (3 rows)
+	if result.Deleted["15m"] != 7 || result.Deleted["1h"] != 3 || result.Total() != 10 {
+		t.Errorf("unexpected deletions: %+v", result.Deleted)
+	}
+
+	remaining, _ := repo.FindRange(ctx, "D1", now.Add(-30*24*time.Hour), now)
+	counts := map[string]int{}
+	for _, sr := range remaining {
+		counts[sr.Resolution]++
+	}
+	if counts["15m"] != 3 || counts["1h"] != 7 {
+		t.Errorf("expected 3 x 15m and 7 x 1h left, got %v", counts)
+	}
+
+	// Invalid configurations are rejected up front
+	if _, err := services.NewRetentionRunner(repo, services.RetentionRule{Resolution: "24h", MaxAge: time.Hour}); err == nil {
+		t.Error("expected an error for a resolution tag the standardizer never produces")
+	}
+	if _, err := services.NewRetentionRunner(repo, services.RetentionRule{Resolution: "1d"}); err == nil {
+		t.Error("expected an error for a non-positive max age")
+	}
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestRollupRunner(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	quarter := func(ts time.Time, value float64) domain.StandardReading {
+		return domain.StandardReading{DeviceID: "D1", Timestamp: ts, Resolution: "15m", ValueDisplay: value,
+			ValueScaled: int64(value * 100), ScaleFactor: 100, Quality: domain.QualityValid, Priority: 100}
+	}
+
+	// 1.5 kWh per quarter hour for the first two hours of the day
+	var batch []domain.StandardReading
+	for i := range 8 {
+		batch = append(batch, quarter(day.Add(time.Duration(i)*15*time.Minute), 1.5))
+	}
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	runner, err := services.NewRollupRunner(repo,
+		services.RollupRule{Source: "15m", Target: time.Hour},
+		services.RollupRule{Source: "1h", Target: 24 * time.Hour, Lookback: 48 * time.Hour},
+	)
+	if err != nil {
+		t.Fatalf("NewRollupRunner failed: %v", err)
+	}
+
+	// At 02:10 hours 00 and 01 are complete; the day is still in progress
+	result, err := runner.RunOnce(ctx, []string{"D1"}, day.Add(2*time.Hour+10*time.Minute))
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if result.Written["1h"] != 2 || result.Written["1d"] != 0 {
+		t.Errorf("expected two hourly summaries only, got %+v", result.Written)
+	}
+	hourly := findResolution(t, repo, day, "1h")
+	if len(hourly) != 2 || hourly[0].ValueScaled != 600 || hourly[0].ScaleFactor != 100 || hourly[0].Priority != 100 {
+		t.Fatalf("expected 6 kWh per hour, got %+v", hourly)
+	}
+
+	// A late quarter hour within the lookback is picked up by the next run
+	late := quarter(day.Add(15*time.Minute), 2.5)
+	late.Priority = 50
+	if err := repo.Save(ctx, late, ports.UpsertStrategyLastWriteWins); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := runner.RunOnce(ctx, []string{"D1"}, day.Add(2*time.Hour+50*time.Minute)); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	hourly = findResolution(t, repo, day, "1h")
+	if len(hourly) != 2 || hourly[0].ValueScaled != 700 {
+		t.Errorf("expected hour 00 to be recomputed to 7 kWh, got %+v", hourly)
+	}
+
+	// Once the day is over the daily rule sums the hourly summaries
+	if _, err := runner.RunOnce(ctx, []string{"D1"}, day.Add(24*time.Hour+5*time.Minute)); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	daily := findResolution(t, repo, day, "1d")
+	if len(daily) != 1 || daily[0].ValueScaled != 1300 || !daily[0].Timestamp.Equal(day) {
+		t.Errorf("expected a 13 kWh daily total, got %+v", daily)
+	}
+
+	// Recomputing unchanged data is idempotent
+	if _, err := runner.Recompute(ctx, "D1", day, day.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Recompute failed: %v", err)
+	}
+	if again := findResolution(t, repo, day, "1d"); len(again) != 1 || again[0].ValueScaled != 1300 {
+		t.Errorf("expected recomputation to keep the daily total, got %+v", again)
+	}
+
+	// Invalid configurations are rejected up front
+	for _, rule := range []services.RollupRule{
+		{Source: "15m", Target: 20 * time.Minute},
+		{Source: "1h", Target: 15 * time.Minute},
+		{Source: "24h", Target: 48 * time.Hour},
+		{Source: "15m", Target: time.Hour, Mode: domain.AggregationSnapshot},
+	} {
+		if _, err := services.NewRollupRunner(repo, rule); err == nil {
+			t.Errorf("expected an error for %+v", rule)
+		}
+	}
+}
+
+// findResolution 返回设备当天指定分辨率的标准读数
+func findResolution(t *testing.T, repo ports.StandardReadingRepository, day time.Time, resolution string) []domain.StandardReading {
+	t.Helper()
+	all, err := repo.FindRange(context.Background(), "D1", day, day.Add(24*time.Hour-time.Nanosecond))
+	if err != nil {
+		t.Fatalf("FindRange failed: %v", err)
+	}
+	var out []domain.StandardReading
+	for _, sr := range all {
+		if sr.Resolution == resolution {
+			out = append(out, sr)
+		}
+	}
+	return out
+}
@@ -0,0 +1,144 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+// countingRuleRepo is an in-memory CleaningRuleRepository that counts list queries
+type countingRuleRepo struct {
+	rules []domain.CleaningRule
+	calls int
+}
+
+func (r *countingRuleRepo) Save(ctx context.Context, rule domain.CleaningRule) error {
+	r.rules = append(r.rules, rule)
+	return nil
+}
+
+func (r *countingRuleRepo) GetByID(ctx context.Context, id string) (*domain.CleaningRule, error) {
+	for i := range r.rules {
+		if r.rules[i].ID == id {
+			return &r.rules[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *countingRuleRepo) ListByDeviceType(ctx context.Context, deviceType domain.DeviceType) ([]domain.CleaningRule, error) {
+	var out []domain.CleaningRule
+	for _, rule := range r.rules {
+		if rule.DeviceType == deviceType {
+			out = append(out, rule)
+		}
+	}
+	return out, nil
+}
+
+func (r *countingRuleRepo) ListEnabledByDeviceType(ctx context.Context, deviceType domain.DeviceType) ([]domain.CleaningRule, error) {
+	r.calls++
+	var out []domain.CleaningRule
+	for _, rule := range r.rules {
+		if rule.DeviceType == deviceType && rule.Enabled {
+			out = append(out, rule)
+		}
+	}
+	return out, nil
+}
+
+func (r *countingRuleRepo) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func (r *countingRuleRepo) Watch(ctx context.Context) (<-chan ports.RuleChangeEvent, error) {
+	return nil, ports.ErrWatchNotSupported
+}
+
+func TestDynamicRulesAreCached(t *testing.T) {
+	repo := &countingRuleRepo{rules: []domain.CleaningRule{{
+		ID:         "range-elec",
+		DeviceType: domain.DeviceTypeElec,
+		Type:       domain.RuleTypeRange,
+		Enabled:    true,
+		Parameters: map[string]any{"min": 0.0, "max": 1000.0},
+	}}}
+
+	standardizer := services.NewCoreStandardizer(
+		services.WithRuleRepository(repo),
+		services.WithRuleCacheTTL(time.Minute),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	raw := []domain.Reading{
+		{DeviceInfo: domain.DeviceInfo{ID: "E1", Type: domain.DeviceTypeElec}, Timestamp: tBase, Value: 10},
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := standardizer.ProcessAndStandardize(ctx, raw); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+	}
+	if repo.calls != 1 {
+		t.Fatalf("expected rules to be loaded once, got %d queries", repo.calls)
+	}
+
+	standardizer.(*services.CoreStandardizer).InvalidateRules(domain.DeviceTypeElec)
+	if _, err := standardizer.ProcessAndStandardize(ctx, raw); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if repo.calls != 2 {
+		t.Fatalf("expected rules to be reloaded after invalidation, got %d queries", repo.calls)
+	}
+}
+
+func TestRuleChangesInvalidateCache(t *testing.T) {
+	ctx := context.Background()
+	rule := domain.CleaningRule{
+		ID:         "range-elec",
+		DeviceType: domain.DeviceTypeElec,
+		Type:       domain.RuleTypeRange,
+		Enabled:    true,
+		Parameters: map[string]any{"min": 0.0, "max": 1000.0},
+	}
+	repo := memory.NewCleaningRuleRepository([]domain.CleaningRule{rule})
+	standardizer := services.NewCoreStandardizer(
+		services.WithRuleRepository(repo),
+		services.WithRuleCacheTTL(time.Hour),
+	)
+	defer standardizer.(*services.CoreStandardizer).Close()
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	raw := []domain.Reading{
+		{DeviceInfo: domain.DeviceInfo{ID: "E1", Type: domain.DeviceTypeElec}, Timestamp: tBase, Value: 1500},
+	}
+	if result, _ := standardizer.ProcessAndStandardize(ctx, raw); len(result.Readings) != 0 {
+		t.Fatalf("expected 1500 to be rejected by the cached rule, got %+v", result.Readings)
+	}
+
+	// Raising the limit must take effect without waiting for the hour-long TTL
+	rule.Parameters = map[string]any{"min": 0.0, "max": 2000.0}
+	if err := repo.Save(ctx, rule); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		result, err := standardizer.ProcessAndStandardize(ctx, raw)
+		if err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+		if len(result.Readings) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the rule change to invalidate the cache")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,46 @@
+package rules_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestBidirectionalMonotonicRule(t *testing.T) {
+	rule := &rules.BidirectionalMonotonicRule{MaxRate: 50, Rollover: 100000, Action: domain.ActionReject}
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+
+	prev := domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "PV1"}, Timestamp: tBase, Value: 500}
+	ctx := ports.CleaningContext{Previous: &prev}
+
+	cases := []struct {
+		name   string
+		value  float64
+		passed bool
+	}{
+		{"export decreases value", 480, true},
+		{"import increases value", 530, true},
+		{"decrease faster than max rate", 400, false},
+		{"increase faster than max rate", 700, false},
+	}
+
+	for _, c := range cases {
+		curr := domain.Reading{DeviceInfo: prev.DeviceInfo, Timestamp: tBase.Add(time.Hour), Value: c.value}
+		if got := rule.Check(ctx, curr); got.Passed != c.passed {
+			t.Errorf("%s: expected passed=%v, got %v (%s)", c.name, c.passed, got.Passed, got.Reason)
+		}
+	}
+
+	// Rollover in both directions should be treated as a small delta
+	high := domain.Reading{DeviceInfo: prev.DeviceInfo, Timestamp: tBase, Value: 99990}
+	wrapped := domain.Reading{DeviceInfo: prev.DeviceInfo, Timestamp: tBase.Add(time.Hour), Value: 10}
+	if got := rule.Check(ports.CleaningContext{Previous: &high}, wrapped); !got.Passed {
+		t.Errorf("forward rollover rejected: %s", got.Reason)
+	}
+	if got := rule.Check(ports.CleaningContext{Previous: &wrapped}, domain.Reading{Timestamp: tBase.Add(2 * time.Hour), Value: 99990}); !got.Passed {
+		t.Errorf("reverse rollover rejected: %s", got.Reason)
+	}
+}
@@ -0,0 +1,48 @@
+package rules_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+	"github.com/renjie/prism-core/pkg/core/services/rules/expr"
+)
+
+func TestScriptRule(t *testing.T) {
+	cond, err := expr.Compile(`device.tags["site"] != "lab" && has_prev && abs(value - prev.value) / (dt / 3600) > 100`)
+	if err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+	// Condition describes the failure, so negate it for the rule
+	notCond, _ := expr.Compile("!(" + cond.String() + ")")
+	rule := &rules.ScriptRule{Condition: notCond, Action: domain.ActionReject}
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "E1", Tags: map[string]string{"site": "plant"}}
+	prev := domain.Reading{DeviceInfo: dev, Timestamp: tBase, Value: 100}
+
+	jump := domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(time.Hour), Value: 500}
+	if res := rule.Check(ports.CleaningContext{Previous: &prev}, jump); res.Passed {
+		t.Errorf("expected jump to be rejected")
+	}
+
+	lab := jump
+	lab.DeviceInfo.Tags = map[string]string{"site": "lab"}
+	if res := rule.Check(ports.CleaningContext{Previous: &prev}, lab); !res.Passed {
+		t.Errorf("expected lab device to pass: %s", res.Reason)
+	}
+
+	if res := rule.Check(ports.CleaningContext{}, jump); !res.Passed {
+		t.Errorf("expected first reading to pass: %s", res.Reason)
+	}
+}
+
+func TestScriptCompileRejectsUnsafeSyntax(t *testing.T) {
+	for _, src := range []string{`os.Getenv("HOME")`, `func() bool { return true }()`, `x[0:1]`, `'c'`} {
+		if _, err := expr.Compile(src); err == nil {
+			t.Errorf("expected %q to be rejected", src)
+		}
+	}
+}
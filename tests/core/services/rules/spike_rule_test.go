@@ -0,0 +1,31 @@
+package rules_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestSpikeRuleRejectsOnlyTheSpike(t *testing.T) {
+	sanitizer := services.NewSanitizer(&rules.SpikeRule{Threshold: 10, Action: domain.ActionReject})
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1"}
+
+	raw := []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 100},
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: 900}, // spike
+		{DeviceInfo: dev, Timestamp: tBase.Add(30 * time.Minute), Value: 102},
+		{DeviceInfo: dev, Timestamp: tBase.Add(45 * time.Minute), Value: 104},
+	}
+
+	clean, quarantined := sanitizer.Clean(raw)
+	if len(quarantined) != 1 || quarantined[0].Reading.Value != 900 {
+		t.Fatalf("expected only the spike to be quarantined, got %+v", quarantined)
+	}
+	if len(clean) != 3 {
+		t.Fatalf("expected 3 clean readings, got %d", len(clean))
+	}
+}
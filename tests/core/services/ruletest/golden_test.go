@@ -0,0 +1,24 @@
+package ruletest_test
+
+import (
+	"testing"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+	"github.com/renjie/prism-core/pkg/core/services/ruletest"
+)
+
+func TestElecRulesGoldenDataset(t *testing.T) {
+	cases, err := ruletest.LoadCSVFile("../../../../testdata/golden/elec_rules.csv")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+
+	sanitizer := services.NewSanitizer(
+		&rules.ImputeRule{Strategy: domain.ImputeLinear},
+		&rules.SpikeRule{Threshold: 100, Action: domain.ActionReject},
+		&rules.RangeRule{Min: 0, Max: 1000, Action: domain.ActionCorrect},
+	)
+	ruletest.Run(t, sanitizer, cases)
+}
@@ -0,0 +1,60 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestScaleOverflowQuarantined(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1", Type: domain.DeviceTypeElec}
+	quarantine := &memoryQuarantineRepo{}
+	standardizer := services.NewCoreStandardizer(
+		services.WithQuarantineRepository(quarantine),
+		services.WithQuarantinePolicy(services.QuarantinePolicy{Mode: services.QuarantineSync}),
+	)
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase, Value: 100},
+		{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: 1e16}, // 1e16 x 10000 overflows int64
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	for _, sr := range result.Readings {
+		if sr.ValueDisplay == 1e16 {
+			t.Fatalf("overflowing reading must not be standardized, got %+v", sr)
+		}
+	}
+	if len(quarantine.records) != 1 || quarantine.records[0].Code != domain.QuarantineCodeScaleOverflow {
+		t.Fatalf("expected one SCALE_OVERFLOW quarantine record, got %+v", quarantine.records)
+	}
+}
+
+func TestInvalidScaleFactorQuarantined(t *testing.T) {
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	gas := domain.DeviceInfo{ID: "G1", Type: domain.DeviceTypeGas}
+	quarantine := &memoryQuarantineRepo{}
+	standardizer := services.NewCoreStandardizer(
+		services.WithQuarantineRepository(quarantine),
+		services.WithQuarantinePolicy(services.QuarantinePolicy{Mode: services.QuarantineSync}),
+		services.WithDeviceTypePrecision(domain.DeviceTypeGas, 1024),
+	)
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), []domain.Reading{
+		{DeviceInfo: gas, Timestamp: tBase, Value: 10},
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	if len(result.Readings) != 0 {
+		t.Errorf("expected no standard readings, got %+v", result.Readings)
+	}
+	if len(quarantine.records) != 1 || quarantine.records[0].Code != domain.QuarantineCodeInvalidFactor {
+		t.Fatalf("expected one INVALID_FACTOR quarantine record, got %+v", quarantine.records)
+	}
+}
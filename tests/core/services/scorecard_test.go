@@ -0,0 +1,79 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestBuildQualityScorecard(t *testing.T) {
+	ctx := context.Background()
+	monday := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	standard := memory.NewStandardReadingRepository()
+	quarantine := memory.NewQuarantineRepository()
+	meterA := domain.DeviceInfo{ID: "A", Type: "ELECTRIC"}
+	meterB := domain.DeviceInfo{ID: "B", Type: "ELECTRIC"}
+	water := domain.DeviceInfo{ID: "W", Type: "WATER"}
+
+	reading := func(id string, ts time.Time, quality domain.QualityState) domain.StandardReading {
+		return domain.StandardReading{DeviceID: id, Timestamp: ts, Resolution: "1h", ScaleFactor: 1, Quality: quality}
+	}
+	batch := []domain.StandardReading{
+		// week 1
+		reading("A", monday, domain.QualityValid),
+		reading("A", monday.Add(time.Hour), domain.QualityCorrected),
+		reading("B", monday, domain.QualityValid),
+		reading("W", monday, domain.QualityInterpolated),
+		// week 2
+		reading("A", monday.AddDate(0, 0, 7), domain.QualityValid),
+		reading("A", monday.AddDate(0, 0, 7).Add(time.Hour), domain.QualityWithdrawn),
+		reading("W", monday.AddDate(0, 0, 8), domain.QualityMissing),
+		reading("W", monday.AddDate(0, 0, 8).Add(time.Hour), domain.QualityValid),
+	}
+	if err := standard.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	for i, ts := range []time.Time{monday.Add(2 * time.Hour), monday.AddDate(0, 0, 9)} {
+		rec := domain.QuarantineReading{ID: string(rune('q' + i)), Reading: domain.Reading{DeviceInfo: water, Timestamp: ts}, Status: domain.QuarantineStatusPending}
+		if err := quarantine.Save(ctx, rec); err != nil {
+			t.Fatalf("Save quarantine failed: %v", err)
+		}
+	}
+
+	card, err := services.BuildQualityScorecard(ctx, standard, quarantine, services.ScorecardQuery{
+		Devices: []domain.DeviceInfo{meterA, meterB, water}, Resolution: "1h",
+		Start: monday.Add(12 * time.Hour), End: monday.AddDate(0, 0, 10), // widened to whole weeks
+	})
+	if err != nil {
+		t.Fatalf("BuildQualityScorecard failed: %v", err)
+	}
+	if card.Period != domain.ReportPeriodWeek || len(card.Overall.Trend) != 2 ||
+		!card.Overall.Trend[0].Start.Equal(monday) || !card.Overall.Trend[1].End.Equal(monday.AddDate(0, 0, 14)) {
+		t.Fatalf("expected two weekly periods, got %+v", card.Overall.Trend)
+	}
+	week1, week2 := card.Overall.Trend[0], card.Overall.Trend[1]
+	if week1.Valid != 2 || week1.Corrected != 1 || week1.Interpolated != 1 || week1.Quarantined != 1 || week1.Score != 40 {
+		t.Errorf("unexpected week 1 counts: %+v", week1.QualityCounts)
+	}
+	if week2.Valid != 2 || week2.Withdrawn != 1 || week2.Missing != 1 || week2.Quarantined != 1 || week2.Score != 50 {
+		t.Errorf("unexpected week 2 counts: %+v", week2.QualityCounts)
+	}
+
+	if len(card.Devices) != 3 || card.Devices[0].Key != "W" || card.Devices[2].Key != "B" {
+		t.Fatalf("expected devices ordered from worst to best, got %+v", card.Devices)
+	}
+	if w := card.Devices[0].Total; w.Valid != 1 || w.Quarantined != 2 || w.Score != 20 {
+		t.Errorf("unexpected water meter totals: %+v", w)
+	}
+	if len(card.Types) != 2 || card.Types[0].Key != "WATER" || card.Types[1].Key != "ELECTRIC" || card.Types[1].Devices != 2 {
+		t.Fatalf("expected device types ordered from worst to best, got %+v", card.Types)
+	}
+	if e := card.Types[1].Total; e.Valid != 3 || e.Corrected != 1 || e.Withdrawn != 1 || e.Score != 75 {
+		t.Errorf("unexpected electric totals: %+v", e)
+	}
+}
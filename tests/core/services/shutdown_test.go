@@ -0,0 +1,125 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestShutdownWaitsForInFlightBatches(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	quarantine := &memoryQuarantineRepo{}
+	standardizer := services.NewCoreStandardizer(
+		services.WithQuarantineRepository(quarantine),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionReject}),
+		services.WithPreCleanHook(func(ctx context.Context, raw []domain.Reading) ([]domain.Reading, error) {
+			close(entered)
+			<-release
+			return raw, nil
+		}),
+	).(*services.CoreStandardizer)
+
+	processed := make(chan error, 1)
+	go func() {
+		_, err := standardizer.ProcessAndStandardize(context.Background(), quarantineBatch())
+		processed <- err
+	}()
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := standardizer.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Shutdown to time out while a batch is in flight, got %v", err)
+	}
+	if _, err := standardizer.ProcessAndStandardize(context.Background(), quarantineBatch()); !errors.Is(err, services.ErrStandardizerClosed) {
+		t.Fatalf("expected new batches to be rejected, got %v", err)
+	}
+
+	close(release)
+	if err := standardizer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if err := <-processed; err != nil {
+		t.Fatalf("in-flight batch failed: %v", err)
+	}
+	if pending, _ := quarantine.FindPending(context.Background(), 0); len(pending) != 2 {
+		t.Errorf("expected the in-flight batch's 2 quarantine records to be saved, got %d", len(pending))
+	}
+}
+
+func TestDrainKeepsStandardizerOpen(t *testing.T) {
+	quarantine := &memoryQuarantineRepo{}
+	standardizer := services.NewCoreStandardizer(
+		services.WithQuarantineRepository(quarantine),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionReject}),
+	).(*services.CoreStandardizer)
+	defer standardizer.Close()
+
+	if _, err := standardizer.ProcessAndStandardize(context.Background(), quarantineBatch()); err != nil {
+		t.Fatal(err)
+	}
+	if err := standardizer.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if pending, _ := quarantine.FindPending(context.Background(), 0); len(pending) != 2 {
+		t.Fatalf("expected 2 quarantine records after Drain, got %d", len(pending))
+	}
+	if _, err := standardizer.ProcessAndStandardize(context.Background(), quarantineBatch()); err != nil {
+		t.Fatalf("expected standardizer to stay open after Drain, got %v", err)
+	}
+}
+
+func TestStreamingShutdownFlushesPendingSlots(t *testing.T) {
+	repo := memory.NewStandardReadingRepository()
+	streaming := services.NewStreamingStandardizer(services.WithRepository(repo))
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "S1", Type: "ELEC"}
+
+	// 最后一条读数对应的槽位在 Flush 之前不会输出
+	for i, v := range []float64{10, 11} {
+		if _, err := streaming.Push(context.Background(), domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(time.Duration(i) * 15 * time.Minute), Value: v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := streaming.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, err := repo.FindExact(context.Background(), "S1", tBase.Add(15*time.Minute)); err != nil {
+		t.Errorf("expected the last slot to be flushed and persisted: %v", err)
+	}
+	if _, err := streaming.Push(context.Background(), domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(time.Hour), Value: 12}); !errors.Is(err, services.ErrStandardizerClosed) {
+		t.Errorf("expected Push after Shutdown to be rejected, got %v", err)
+	}
+}
+
+func TestStandardizerServiceShutdown(t *testing.T) {
+	repo := memory.NewStandardReadingRepository()
+	svc := services.RunStandardizerService(context.Background(), services.ServiceOptions{
+		FlushInterval: time.Hour,
+		Options:       []services.StandardizerOption{services.WithRepository(repo)},
+	})
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dev := domain.DeviceInfo{ID: "Q1", Type: "ELEC"}
+	if err := svc.Enqueue(context.Background(),
+		domain.Reading{DeviceInfo: dev, Timestamp: tBase, Value: 1},
+		domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: 2},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, err := repo.FindExact(context.Background(), "Q1", tBase); err != nil {
+		t.Errorf("expected queued readings to be processed before Shutdown returned: %v", err)
+	}
+	if err := svc.Enqueue(context.Background(), domain.Reading{DeviceInfo: dev, Timestamp: tBase, Value: 1}); !errors.Is(err, services.ErrServiceStopped) {
+		t.Errorf("expected Enqueue after Shutdown to fail, got %v", err)
+	}
+}
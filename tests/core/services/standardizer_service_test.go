@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+// flakyStandardRepo 前 failures 次 SaveBatch 返回错误
+type flakyStandardRepo struct {
+	memoryStandardRepo
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (r *flakyStandardRepo) SaveBatch(ctx context.Context, readings []domain.StandardReading, strategy ports.UpsertStrategy, idempotencyKey string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if r.calls <= r.failures {
+		return errors.New("connection reset")
+	}
+	return r.memoryStandardRepo.SaveBatch(ctx, readings, strategy, idempotencyKey)
+}
+
+func TestStandardizerServiceMicroBatchesAndRetries(t *testing.T) {
+	repo := &flakyStandardRepo{failures: 2}
+	var mu sync.Mutex
+	batches := 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	svc := services.RunStandardizerService(ctx, services.ServiceOptions{
+		BatchSize:     4,
+		FlushInterval: time.Hour, // size-triggered only, the remainder is flushed on shutdown
+		RetryBackoff:  time.Millisecond,
+		Options:       []services.StandardizerOption{services.WithRepository(repo)},
+		OnResult: func(ctx context.Context, result *domain.StandardizationResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			batches++
+		},
+	})
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	for i := range 6 {
+		r := domain.Reading{
+			DeviceInfo: domain.DeviceInfo{ID: "D1", Type: domain.DeviceTypeElec},
+			Timestamp:  tBase.Add(time.Duration(i) * 15 * time.Minute),
+			Value:      float64(100 + i),
+		}
+		if err := svc.Enqueue(context.Background(), r); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	cancel()
+	if err := svc.Wait(); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	if batches != 2 {
+		t.Errorf("expected 2 micro-batches (4 + 2 on shutdown), got %d", batches)
+	}
+	if len(repo.readings) != 6 {
+		t.Errorf("expected all 6 readings persisted after retries, got %d", len(repo.readings))
+	}
+	if repo.calls != 4 {
+		t.Errorf("expected 2 failed + 2 successful SaveBatch calls, got %d", repo.calls)
+	}
+	if err := svc.Enqueue(context.Background(), domain.Reading{}); !errors.Is(err, services.ErrServiceStopped) {
+		t.Errorf("expected ErrServiceStopped after shutdown, got %v", err)
+	}
+}
+
+func TestStandardizerServiceKeepsIngestPriority(t *testing.T) {
+	repo := &memoryStandardRepo{}
+	ctx, cancel := context.WithCancel(context.Background())
+	svc := services.RunStandardizerService(ctx, services.ServiceOptions{
+		Options: []services.StandardizerOption{services.WithRepository(repo)},
+	})
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	lateCtx := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyBatchLate})
+	err := svc.Enqueue(lateCtx, domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: 1})
+	if err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	cancel()
+	if err := svc.Wait(); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	if len(repo.readings) != 1 || repo.readings[0].Priority != domain.IngestStrategyBatchLate.GetPriority() {
+		t.Fatalf("expected BATCH_LATE priority to survive micro-batching, got %+v", repo.readings)
+	}
+}
+
+func TestStandardizerServiceKeepsIngestContextPerReading(t *testing.T) {
+	repo := &memoryStandardRepo{}
+	ctx, cancel := context.WithCancel(context.Background())
+	svc := services.RunStandardizerService(ctx, services.ServiceOptions{
+		FlushInterval: time.Hour, // both submissions land in the same micro-batch
+		Options:       []services.StandardizerOption{services.WithRepository(repo)},
+	})
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	for i, op := range []string{"alice", "bob"} {
+		ictx := domain.NewContext(context.Background(), domain.IngestContext{Strategy: domain.IngestStrategyBatchLate, Operator: op, TraceID: "trace-" + op, BatchID: "batch-" + op})
+		r := domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "D" + op}, Timestamp: tBase.Add(time.Duration(i) * 15 * time.Minute), Value: 1}
+		if err := svc.Enqueue(ictx, r); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+	cancel()
+	if err := svc.Wait(); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	if len(repo.readings) != 2 {
+		t.Fatalf("expected 2 readings, got %+v", repo.readings)
+	}
+	for _, sr := range repo.readings {
+		op := sr.DeviceID[1:]
+		if p := sr.Provenance; p == nil || p.Operator != op || p.TraceID != "trace-"+op || p.BatchID != "batch-"+op {
+			t.Errorf("%s: expected the provenance of its own submission, got %+v", sr.DeviceID, sr.Provenance)
+		}
+	}
+}
@@ -73,10 +73,11 @@ func TestCoreStandardizer(t *testing.T) {
 	// Result: Item 1 and Item 4.
 
 	ctx := context.Background()
-	results, err := standardizer.ProcessAndStandardize(ctx, raw)
+	result, err := standardizer.ProcessAndStandardize(ctx, raw)
 	if err != nil {
 		t.Fatalf("Process failed: %v", err)
 	}
+	results := result.Readings
 
 	if len(results) != 2 {
 		t.Fatalf("Expected 2 standard readings, got %d", len(results))
@@ -93,8 +94,8 @@ func TestCoreStandardizer(t *testing.T) {
 
 	// Verify Item 2 (Precision Alignment)
 	r2 := results[1]
-	// 100.00019 * 10000 = 1000001.9 -> int64 truncation -> 1000001
-	expectedScaled := int64(1000001)
+	// 100.00019 * 10000 = 1000001.9 -> exact decimal rounding -> 1000002
+	expectedScaled := int64(1000002)
 	if r2.ValueScaled != expectedScaled {
 		t.Errorf("Item 2 Scaled Value wrong: expected %d, got %d", expectedScaled, r2.ValueScaled)
 	}
@@ -107,3 +108,85 @@ func TestCoreStandardizer(t *testing.T) {
 		t.Logf("[%s] %v -> Scaled: %d (x%d)", r.Timestamp.Format("15:04:05"), r.ValueDisplay, r.ValueScaled, r.ScaleFactor)
 	}
 }
+
+func TestStandardizerGridAnchor(t *testing.T) {
+	standardizer := services.NewCoreStandardizer(
+		services.WithAlignment(15*time.Minute, 2*time.Minute),
+		services.WithGridAnchor(5*time.Minute),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "LEGACY", Type: domain.DeviceType("ELEC")}
+	readings := []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase.Add(5*time.Minute + 30*time.Second), Value: 100},
+		{DeviceInfo: dev, Timestamp: tBase.Add(19 * time.Minute), Value: 101},
+		{DeviceInfo: dev, Timestamp: tBase.Add(35 * time.Minute), Value: 102},
+	}
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), readings)
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	if len(result.Readings) != 3 {
+		t.Fatalf("expected 3 anchored slots, got %d", len(result.Readings))
+	}
+	for i, minute := range []int{5, 20, 35} {
+		if got := result.Readings[i].Timestamp.Minute(); got != minute {
+			t.Errorf("slot %d: expected :%02d, got :%02d", i, minute, got)
+		}
+	}
+}
+
+func TestStandardizerSnapshotAtOrBefore(t *testing.T) {
+	standardizer := services.NewCoreStandardizer(
+		services.WithAlignment(15*time.Minute, 5*time.Minute),
+		services.WithSnapshotMode(domain.SnapshotAtOrBefore),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "BILL", Type: domain.DeviceType("ELEC")}
+	readings := []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase.Add(-3 * time.Minute), Value: 100},
+		{DeviceInfo: dev, Timestamp: tBase.Add(1 * time.Minute), Value: 105},
+	}
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), readings)
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	for _, sr := range result.Readings {
+		if sr.Timestamp.Equal(tBase) {
+			if sr.ValueDisplay != 100 {
+				t.Errorf("10:00 slot should use the 09:57 reading, got %v", sr.ValueDisplay)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected a 10:00 slot, got %+v", result.Readings)
+}
+
+func TestStandardizerSnapshotInterpolate(t *testing.T) {
+	standardizer := services.NewCoreStandardizer(
+		services.WithAlignment(15*time.Minute, 5*time.Minute),
+		services.WithSnapshotMode(domain.SnapshotInterpolate),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "FAST", Type: domain.DeviceType("ELEC")}
+	result, err := standardizer.ProcessAndStandardize(context.Background(), []domain.Reading{
+		{DeviceInfo: dev, Timestamp: tBase.Add(-2 * time.Minute), Value: 100},
+		{DeviceInfo: dev, Timestamp: tBase.Add(2 * time.Minute), Value: 120},
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndStandardize failed: %v", err)
+	}
+	for _, sr := range result.Readings {
+		if sr.Timestamp.Equal(tBase) {
+			if sr.ValueDisplay != 110 || sr.Quality != domain.QualityInterpolated {
+				t.Errorf("expected interpolated 110 at 10:00, got %v (%s)", sr.ValueDisplay, sr.Quality)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected a 10:00 slot, got %+v", result.Readings)
+}
@@ -0,0 +1,47 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestStreamingStandardizerEmitsIncrementally(t *testing.T) {
+	ctx := context.Background()
+	stream := services.NewStreamingStandardizer(
+		services.WithAlignment(15*time.Minute, 5*time.Minute),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	dev := domain.DeviceInfo{ID: "D1"}
+	push := func(offset time.Duration, value float64) []domain.StandardReading {
+		t.Helper()
+		out, err := stream.Push(ctx, domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(offset), Value: value})
+		if err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		return out
+	}
+
+	if out := push(time.Minute, 100); len(out) != 0 {
+		t.Fatalf("10:00 slot still open, expected nothing, got %d", len(out))
+	}
+	out := push(14*time.Minute, 110)
+	if len(out) != 1 || !out[0].Timestamp.Equal(tBase) || out[0].ValueDisplay != 100 {
+		t.Fatalf("expected 10:00 slot with 100, got %+v", out)
+	}
+	if out := push(9*time.Minute, 105); len(out) != 0 {
+		t.Fatalf("out-of-order reading must not emit, got %d", len(out))
+	}
+
+	out, err := stream.Flush(ctx)
+	if err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if len(out) != 1 || !out[0].Timestamp.Equal(tBase.Add(15*time.Minute)) || out[0].ValueDisplay != 110 {
+		t.Fatalf("expected 10:15 slot with 110 on flush, got %+v", out)
+	}
+}
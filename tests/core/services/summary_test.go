@@ -0,0 +1,52 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestSummarizeUsage(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewStandardReadingRepository()
+	var batch []domain.StandardReading
+	for i := range 20 { // 1.0 .. 2.0 kWh in steps of 0.05, with mixed precision
+		sr := domain.StandardReading{DeviceID: "M1", Timestamp: base.Add(time.Duration(i) * 15 * time.Minute),
+			Resolution: "15m", ValueScaled: int64(100 + 5*i), ScaleFactor: 100, Quality: domain.QualityValid}
+		if i%2 == 1 {
+			sr.ValueScaled, sr.ScaleFactor = sr.ValueScaled*10, 1000
+		}
+		batch = append(batch, sr)
+	}
+	batch = append(batch, domain.StandardReading{DeviceID: "M1", Timestamp: base.Add(5 * time.Hour), Resolution: "15m",
+		ValueScaled: 99900, ScaleFactor: 100, Quality: domain.QualityWithdrawn})
+	if err := repo.SaveBatch(ctx, batch, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	got, err := services.SummarizeUsage(ctx, repo, services.SummaryQuery{DeviceIDs: []string{"M1", "IDLE"}, Resolution: "15m",
+		Start: base, End: base.Add(6 * time.Hour)})
+	if err != nil {
+		t.Fatalf("SummarizeUsage failed: %v", err)
+	}
+	if len(got) != 2 || got[0].DeviceID != "IDLE" || got[0].Count != 0 {
+		t.Fatalf("expected an empty summary for IDLE first, got %+v", got)
+	}
+	s := got[1]
+	if s.Count != 20 || s.ScaleFactor != 1000 || s.MinScaled != 1000 || s.MaxScaled != 1950 || s.TotalScaled != 29500 {
+		t.Errorf("unexpected counts and extremes: %+v", s)
+	}
+	// mean 1.475, median (1.45 + 1.50) / 2 = 1.475, p95 is the 19th of 20 values
+	if s.MeanScaled != 1475 || s.MedianScaled != 1475 || s.P95Scaled != 1900 || s.P95 != 1.9 {
+		t.Errorf("unexpected statistics: %+v", s)
+	}
+	if want := 1.475 / 1.95; s.LoadFactor < want-1e-12 || s.LoadFactor > want+1e-12 {
+		t.Errorf("expected load factor %v, got %v", want, s.LoadFactor)
+	}
+}
@@ -0,0 +1,149 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/core/services"
+	"github.com/renjie/prism-core/pkg/core/services/rules"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	repo := memory.NewStandardReadingRepository()
+	quarantine := memory.NewQuarantineRepository()
+	policy := services.DefaultQuarantinePolicy()
+	policy.Mode = services.QuarantineSync
+	standardizer := services.NewCoreStandardizer(
+		services.WithRepository(repo),
+		services.WithQuarantineRepository(quarantine),
+		services.WithQuarantinePolicy(policy),
+		services.WithCleaningRules(&rules.RangeRule{Min: 0, Max: 100, Action: domain.ActionReject}),
+	)
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	batch := func(values ...float64) []domain.Reading {
+		var out []domain.Reading
+		for i, v := range values {
+			out = append(out, domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "M1", Type: "ELEC"}, Timestamp: tBase.Add(time.Duration(i) * 15 * time.Minute), Value: v})
+		}
+		return out
+	}
+
+	// 两个租户的同名设备互不覆盖
+	acme, globex := domain.WithTenant(context.Background(), "acme"), domain.WithTenant(context.Background(), "globex")
+	if _, err := standardizer.ProcessAndStandardize(acme, batch(10, 11)); err != nil {
+		t.Fatal(err)
+	}
+	result, err := standardizer.ProcessAndStandardize(globex, batch(20, 21, -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Readings[0].TenantID != "globex" {
+		t.Errorf("expected standard readings stamped with the tenant, got %+v", result.Readings[0])
+	}
+	for ctx, want := range map[context.Context]float64{acme: 10, globex: 20} {
+		got, err := repo.FindExact(ctx, "M1", tBase)
+		if err != nil || got.ValueDisplay != want {
+			t.Errorf("tenant %s: expected %v, got %+v (%v)", domain.TenantFromContext(ctx), want, got, err)
+		}
+	}
+	if _, err := repo.FindExact(context.Background(), "M1", tBase); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected the default tenant to see no readings, got %v", err)
+	}
+
+	if pending, _ := quarantine.FindPending(acme, 0); len(pending) != 0 {
+		t.Errorf("expected no quarantine records for acme, got %+v", pending)
+	}
+	if pending, _ := quarantine.FindPending(globex, 0); len(pending) != 1 || pending[0].TenantID != "globex" {
+		t.Errorf("expected 1 quarantine record for globex, got %+v", pending)
+	}
+}
+
+func TestStreamingStandardizerTenantIsolation(t *testing.T) {
+	repo := memory.NewStandardReadingRepository()
+	stream := services.NewStreamingStandardizer(
+		services.WithAlignment(15*time.Minute, 5*time.Minute),
+		services.WithRepository(repo),
+	)
+	tBase := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	acme, globex := domain.WithTenant(context.Background(), "acme"), domain.WithTenant(context.Background(), "globex")
+	push := func(ctx context.Context, offset time.Duration, value float64) []domain.StandardReading {
+		t.Helper()
+		out, err := stream.Push(ctx, domain.Reading{DeviceInfo: domain.DeviceInfo{ID: "M1"}, Timestamp: tBase.Add(offset), Value: value})
+		if err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		return out
+	}
+
+	// 同名设备在两个租户下各自维护游标与缓冲
+	push(acme, time.Minute, 100)
+	push(globex, time.Minute, 200)
+	if out := push(acme, 14*time.Minute, 110); len(out) != 1 || out[0].ValueDisplay != 100 || out[0].TenantID != "acme" {
+		t.Fatalf("acme: expected the 10:00 slot with 100, got %+v", out)
+	}
+	if out := push(globex, 14*time.Minute, 210); len(out) != 1 || out[0].ValueDisplay != 200 || out[0].TenantID != "globex" {
+		t.Fatalf("globex: expected the 10:00 slot with 200, got %+v", out)
+	}
+
+	// Flush 在各自租户内持久化，与调用方 ctx 的租户无关
+	if _, err := stream.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	for ctx, want := range map[context.Context][]float64{acme: {100, 110}, globex: {200, 210}} {
+		got, err := repo.FindRange(ctx, "M1", tBase, tBase.Add(time.Hour))
+		if err != nil || len(got) != 2 || got[0].ValueDisplay != want[0] || got[1].ValueDisplay != want[1] {
+			t.Errorf("tenant %s: expected %v, got %+v (%v)", domain.TenantFromContext(ctx), want, got, err)
+		}
+	}
+	if got, _ := repo.FindRange(context.Background(), "M1", tBase, tBase.Add(time.Hour)); len(got) != 0 {
+		t.Errorf("expected the default tenant to see no readings, got %+v", got)
+	}
+}
+
+func TestTenantMismatchRejected(t *testing.T) {
+	standardizer := services.NewCoreStandardizer()
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reading := func(tenant string) domain.Reading {
+		return domain.Reading{DeviceInfo: domain.DeviceInfo{TenantID: tenant, ID: "M1", Type: "ELEC"}, Timestamp: tBase, Value: 1}
+	}
+
+	if _, err := standardizer.ProcessAndStandardize(context.Background(), []domain.Reading{reading("acme"), reading("globex")}); !errors.Is(err, domain.ErrTenantMismatch) {
+		t.Errorf("expected a mixed-tenant batch to be rejected, got %v", err)
+	}
+	ctx := domain.WithTenant(context.Background(), "acme")
+	if _, err := standardizer.ProcessAndStandardize(ctx, []domain.Reading{reading("globex")}); !errors.Is(err, domain.ErrTenantMismatch) {
+		t.Errorf("expected a reading of another tenant to be rejected, got %v", err)
+	}
+	result, err := standardizer.ProcessAndStandardize(context.Background(), []domain.Reading{reading("acme"), reading("")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Readings) != 1 || result.Readings[0].TenantID != "acme" {
+		t.Errorf("expected the batch tenant to be taken from its readings, got %+v", result.Readings)
+	}
+}
+
+func TestTenantScopedIDUnambiguous(t *testing.T) {
+	if a, b := domain.TenantScopedID("a", "b/c"), domain.TenantScopedID("a/b", "c"); a == b {
+		t.Fatalf("expected distinct scoped IDs, both are %q", a)
+	}
+
+	repo := memory.NewStandardReadingRepository()
+	tBase := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctxA := domain.WithTenant(context.Background(), "a")
+	sr := domain.StandardReading{DeviceID: "b/c", Timestamp: tBase, Resolution: "15m", ValueDisplay: 1, Quality: domain.QualityValid}
+	if err := repo.SaveBatch(ctxA, []domain.StandardReading{sr}, ports.UpsertStrategyLastWriteWins, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindExact(domain.WithTenant(context.Background(), "a/b"), "c", tBase); !errors.Is(err, ports.ErrNotFound) {
+		t.Errorf("expected tenant a/b not to see device b/c of tenant a, got %v", err)
+	}
+	if _, err := repo.FindExact(ctxA, "b/c", tBase); err != nil {
+		t.Errorf("expected tenant a to read its own device, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/services"
+)
+
+func TestManyDevicesWithSmallWorkerPool(t *testing.T) {
+	standardizer := services.NewCoreStandardizer(services.WithConcurrencyLimit(4))
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	const devices = 500
+	raw := make([]domain.Reading, 0, devices*2)
+	for i := range devices {
+		dev := domain.DeviceInfo{ID: fmt.Sprintf("D%03d", i)}
+		raw = append(raw,
+			domain.Reading{DeviceInfo: dev, Timestamp: tBase, Value: float64(i)},
+			domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(15 * time.Minute), Value: float64(i + 1)},
+		)
+	}
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	results := result.Readings
+	if len(results) != devices*2 {
+		t.Fatalf("expected %d standard readings, got %d", devices*2, len(results))
+	}
+}
+
+func TestCancelledContextStopsWorkers(t *testing.T) {
+	standardizer := services.NewCoreStandardizer(services.WithConcurrencyLimit(2))
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	raw := []domain.Reading{
+		{DeviceInfo: domain.DeviceInfo{ID: "D1"}, Timestamp: tBase, Value: 1},
+		{DeviceInfo: domain.DeviceInfo{ID: "D2"}, Timestamp: tBase, Value: 2},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := standardizer.ProcessAndStandardize(ctx, raw); err == nil {
+		t.Fatal("expected error for cancelled context")
+	}
+}
+
+func TestOutputOrderIsDeterministic(t *testing.T) {
+	standardizer := services.NewCoreStandardizer(
+		services.WithConcurrencyLimit(8),
+		services.WithResolutions(time.Hour),
+	)
+
+	tBase, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+	var raw []domain.Reading
+	for i := 50; i > 0; i-- {
+		dev := domain.DeviceInfo{ID: fmt.Sprintf("D%02d", i)}
+		for slot := 4; slot >= 0; slot-- {
+			raw = append(raw, domain.Reading{DeviceInfo: dev, Timestamp: tBase.Add(time.Duration(slot) * 15 * time.Minute), Value: float64(slot)})
+		}
+	}
+
+	result, err := standardizer.ProcessAndStandardize(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	for i := 1; i < len(result.Readings); i++ {
+		prev, curr := result.Readings[i-1], result.Readings[i]
+		if prev.DeviceID > curr.DeviceID || (prev.DeviceID == curr.DeviceID && prev.Timestamp.After(curr.Timestamp)) {
+			t.Fatalf("results not ordered at %d: %s@%v before %s@%v", i, prev.DeviceID, prev.Timestamp, curr.DeviceID, curr.Timestamp)
+		}
+		if prev.DeviceID == curr.DeviceID && prev.Timestamp.Equal(curr.Timestamp) && prev.Resolution != "15m" {
+			t.Fatalf("standard interval should come first at %d, got %s then %s", i, prev.Resolution, curr.Resolution)
+		}
+	}
+}
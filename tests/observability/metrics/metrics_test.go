@@ -0,0 +1,102 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renjie/prism-core/pkg/adapters/ingest"
+	"github.com/renjie/prism-core/pkg/adapters/persistence/memory"
+	"github.com/renjie/prism-core/pkg/core/domain"
+	"github.com/renjie/prism-core/pkg/core/ports"
+	"github.com/renjie/prism-core/pkg/observability/metrics"
+)
+
+type staticRuleStats []ports.RuleStats
+
+func (s staticRuleStats) RuleStats() []ports.RuleStats { return s }
+
+func scrape(t *testing.T, reg *metrics.Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	return rec.Body.String()
+}
+
+func assertContains(t *testing.T, body string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, body)
+		}
+	}
+}
+
+func TestMetricsExposition(t *testing.T) {
+	reg := metrics.NewRegistry()
+	m, err := metrics.New(reg, metrics.WithLatencyBuckets(0.1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.WatchRuleStats(staticRuleStats{{RuleID: "range", DeviceType: "METER", Checked: 10, Passed: 7, Corrected: 1, Rejected: 2}})
+
+	m.AddReadings(ports.CounterReadingsIn, 10)
+	m.AddReadings(ports.CounterQuarantined, 2)
+	m.ObserveStage(ports.StageClean, 50*time.Millisecond)
+	m.ObserveStage(ports.StageClean, 2*time.Second)
+
+	ingestor := m.InstrumentIngestor("csv", ingest.NewCsvUniversalIngestor(func(context.Context, []domain.Reading) error { return nil }))
+	csv := "device_id,timestamp,value\nd1,2024-01-01T00:00:00Z,1\nd1,bad,2\n"
+	if _, err := ingestor.IngestStream(context.Background(), strings.NewReader(csv)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ingestor.IngestBatch(context.Background(), strings.NewReader("device_id\n"), "csv"); err == nil {
+		t.Fatal("expected header error")
+	}
+
+	repo := m.InstrumentRepository("memory", memory.NewStandardReadingRepository())
+	readings := []domain.StandardReading{
+		{DeviceID: "d1", Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Resolution: "15m", ValueScaled: 1, ScaleFactor: 1},
+		{DeviceID: "d1", Timestamp: time.Date(2024, 1, 1, 0, 15, 0, 0, time.UTC), Resolution: "15m", ValueScaled: 2, ScaleFactor: 1},
+	}
+	if err := repo.SaveBatch(context.Background(), readings, ports.UpsertStrategyLastWriteWins, "k1"); err != nil {
+		t.Fatal(err)
+	}
+	m.ObserveWrite("memory", 3, time.Millisecond, errors.New("boom"))
+
+	body := scrape(t, reg)
+	assertContains(t, body,
+		"# TYPE prism_ingest_records_total counter",
+		`prism_ingest_records_total{format="csv",outcome="success"} 1`,
+		`prism_ingest_records_total{format="csv",outcome="failed"} 1`,
+		`prism_ingest_errors_total{format="csv"} 1`,
+		`prism_pipeline_readings_total{counter="quarantined"} 2`,
+		"# TYPE prism_standardize_stage_duration_seconds histogram",
+		`prism_standardize_stage_duration_seconds_bucket{stage="clean",le="0.1"} 1`,
+		`prism_standardize_stage_duration_seconds_bucket{stage="clean",le="1"} 1`,
+		`prism_standardize_stage_duration_seconds_bucket{stage="clean",le="+Inf"} 2`,
+		`prism_standardize_stage_duration_seconds_count{stage="clean"} 2`,
+		`prism_repository_writes_total{repository="memory",outcome="success"} 2`,
+		`prism_repository_writes_total{repository="memory",outcome="failed"} 3`,
+		`prism_rule_checks_total{rule_id="range",device_type="METER",result="rejected"} 2`,
+	)
+}
+
+func TestRegistryRejectsDuplicateMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	if _, err := metrics.New(reg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := metrics.New(reg); err == nil {
+		t.Fatal("expected duplicate registration error")
+	}
+	if _, err := metrics.New(reg, metrics.WithNamespace("edge")); err != nil {
+		t.Fatalf("distinct namespace should register: %v", err)
+	}
+}